@@ -5,8 +5,13 @@ import (
 	"os"
 
 	"github.com/steveyegge/gastown/internal/cmd"
+	"github.com/steveyegge/gastown/internal/headless"
 )
 
 func main() {
+	// gt re-executes itself to host headless sessions
+	if headless.IsHost() {
+		os.Exit(headless.RunHost())
+	}
 	os.Exit(cmd.Execute())
 }
@@ -3,6 +3,7 @@ package beads
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/runtime"
 )
@@ -88,6 +90,8 @@ type ListOptions struct {
 	Parent     string // filter by parent ID
 	Assignee   string // filter by assignee (e.g., "gastown/Toast")
 	NoAssignee bool   // filter for issues with no assignee
+	Limit      int    // Max results; 0 for bd's default, -1 for no limit
+	Ephemeral  bool   // Only ephemeral issues (wisps)
 }
 
 // CreateOptions specifies options for creating an issue.
@@ -97,8 +101,10 @@ type CreateOptions struct {
 	Priority    int    // 0-4
 	Description string
 	Parent      string
-	Actor       string // Who is creating this issue (populates created_by)
-	Ephemeral   bool   // Create as ephemeral (wisp) - not exported to JSONL
+	Actor       string   // Who is creating this issue (populates created_by)
+	Ephemeral   bool     // Create as ephemeral (wisp) - not exported to JSONL
+	Assignee    string   // Initial assignee
+	Labels      []string // Labels in addition to the gt:<type> label
 }
 
 // UpdateOptions specifies options for updating an issue.
@@ -124,8 +130,9 @@ type SyncStatus struct {
 // Beads wraps bd CLI operations for a working directory.
 type Beads struct {
 	workDir  string
-	beadsDir string // Optional BEADS_DIR override for cross-database access
-	isolated bool   // If true, suppress inherited beads env vars (for test isolation)
+	beadsDir string        // Optional BEADS_DIR override for cross-database access
+	isolated bool          // If true, suppress inherited beads env vars (for test isolation)
+	timeout  time.Duration // Optional per-command timeout (WithTimeout)
 
 	// Lazy-cached town root for routing resolution.
 	// Populated on first call to getTownRoot() to avoid filesystem walk on every operation.
@@ -151,6 +158,13 @@ func NewWithBeadsDir(workDir, beadsDir string) *Beads {
 	return &Beads{workDir: workDir, beadsDir: beadsDir}
 }
 
+// WithTimeout bounds every bd command run through b to d, so a hung bd
+// fails the call instead of blocking the caller. It returns b.
+func (b *Beads) WithTimeout(d time.Duration) *Beads {
+	b.timeout = d
+	return b
+}
+
 // getActor returns the BD_ACTOR value for this context.
 // Returns empty string when in isolated mode (tests) to prevent
 // inherited actors from routing to production databases.
@@ -211,7 +225,13 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 		fullArgs = append([]string{"--db", beadsDB}, fullArgs...)
 	}
 
-	cmd := exec.Command("bd", fullArgs...) //nolint:gosec // G204: bd is a trusted internal tool
+	ctx := context.Background()
+	if b.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, "bd", fullArgs...) //nolint:gosec // G204: bd is a trusted internal tool
 	cmd.Dir = b.workDir
 
 	// Build environment: filter beads env vars when in isolated mode (tests)
@@ -322,6 +342,14 @@ func (b *Beads) List(opts ListOptions) ([]*Issue, error) {
 	if opts.NoAssignee {
 		args = append(args, "--no-assignee")
 	}
+	if opts.Ephemeral {
+		args = append(args, "--ephemeral")
+	}
+	if opts.Limit > 0 {
+		args = append(args, fmt.Sprintf("--limit=%d", opts.Limit))
+	} else if opts.Limit < 0 {
+		args = append(args, "--limit=0")
+	}
 
 	out, err := b.run(args...)
 	if err != nil {
@@ -500,9 +528,11 @@ func (b *Beads) Create(opts CreateOptions) (*Issue, error) {
 	if opts.Title != "" {
 		args = append(args, "--title="+opts.Title)
 	}
-	// Type is deprecated: convert to gt:<type> label
-	if opts.Type != "" {
-		args = append(args, "--labels=gt:"+opts.Type)
+	if labels := createLabels(opts); len(labels) > 0 {
+		args = append(args, "--labels="+strings.Join(labels, ","))
+	}
+	if opts.Assignee != "" {
+		args = append(args, "--assignee="+opts.Assignee)
 	}
 	if opts.Priority >= 0 {
 		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
@@ -539,6 +569,17 @@ func (b *Beads) Create(opts CreateOptions) (*Issue, error) {
 	return &issue, nil
 }
 
+// createLabels returns the labels bd create should apply: the gt:<type>
+// label for the deprecated Type, then opts.Labels.
+func createLabels(opts CreateOptions) []string {
+	var labels []string
+	// Type is deprecated: convert to gt:<type> label
+	if opts.Type != "" {
+		labels = append(labels, "gt:"+opts.Type)
+	}
+	return append(labels, opts.Labels...)
+}
+
 // CreateWithID creates an issue with a specific ID.
 // This is useful for agent beads, role beads, and other beads that need
 // deterministic IDs rather than auto-generated ones.
@@ -551,9 +592,11 @@ func (b *Beads) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
 	if opts.Title != "" {
 		args = append(args, "--title="+opts.Title)
 	}
-	// Type is deprecated: convert to gt:<type> label
-	if opts.Type != "" {
-		args = append(args, "--labels=gt:"+opts.Type)
+	if labels := createLabels(opts); len(labels) > 0 {
+		args = append(args, "--labels="+strings.Join(labels, ","))
+	}
+	if opts.Assignee != "" {
+		args = append(args, "--assignee="+opts.Assignee)
 	}
 	if opts.Priority >= 0 {
 		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
@@ -710,12 +753,42 @@ func (b *Beads) AddDependency(issue, dependsOn string) error {
 	return err
 }
 
+// AddTypedDependency adds a dependency of the given type (e.g., "tracks").
+func (b *Beads) AddTypedDependency(issue, dependsOn, depType string) error {
+	_, err := b.run("dep", "add", issue, dependsOn, "--type="+depType)
+	return err
+}
+
 // RemoveDependency removes a dependency.
 func (b *Beads) RemoveDependency(issue, dependsOn string) error {
 	_, err := b.run("dep", "remove", issue, dependsOn)
 	return err
 }
 
+// ListDependencies returns the issues linked to id by dependencies.
+// Cross-rig references come back in external:prefix:id form.
+func (b *Beads) ListDependencies(id string, opts DepListOptions) ([]*Issue, error) {
+	args := []string{"dep", "list", id, "--json"}
+	if opts.Direction != "" {
+		args = append(args, "--direction="+opts.Direction)
+	}
+	if opts.Type != "" {
+		args = append(args, "--type="+opts.Type)
+	}
+
+	out, err := b.run(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []*Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, fmt.Errorf("parsing bd dep list output: %w", err)
+	}
+
+	return issues, nil
+}
+
 // Sync syncs beads with remote.
 func (b *Beads) Sync() error {
 	_, err := b.run("sync")
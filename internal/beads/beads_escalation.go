@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -27,6 +28,7 @@ type EscalationFields struct {
 	ReescalationCount  int    // Number of times this has been re-escalated
 	LastReescalatedAt  string // When last re-escalated (empty if never)
 	LastReescalatedBy  string // Who last re-escalated (empty if never)
+	Notifications      string // Delivery status per external channel (e.g., "email:human=delivered slack=failed")
 }
 
 // EscalationState constants for bead status tracking.
@@ -103,6 +105,11 @@ func FormatEscalationDescription(title string, fields *EscalationFields) string
 		lines = append(lines, "last_reescalated_by: null")
 	}
 
+	// Delivery status for external actions (omitted until something is sent)
+	if fields.Notifications != "" {
+		lines = append(lines, fmt.Sprintf("notifications: %s", fields.Notifications))
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -158,6 +165,8 @@ func ParseEscalationFields(description string) *EscalationFields {
 			fields.LastReescalatedAt = value
 		case "last_reescalated_by":
 			fields.LastReescalatedBy = value
+		case "notifications":
+			fields.Notifications = value
 		}
 	}
 
@@ -263,6 +272,77 @@ func (b *Beads) CloseEscalation(id, closedBy, reason string) error {
 	return err
 }
 
+// RecordEscalationDeliveries records external notification outcomes on an
+// escalation bead. statuses maps channel (e.g., "email:human") to status
+// (e.g., "delivered", "failed"); channels already recorded are overwritten.
+// The "notify-failed" label is kept in step with the merged statuses: added
+// while any channel's latest delivery failed, removed once retries succeed.
+func (b *Beads) RecordEscalationDeliveries(id string, statuses map[string]string) error {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+
+	// Verify it's an escalation
+	if !HasLabel(issue, "gt:escalation") {
+		return fmt.Errorf("issue %s is not an escalation bead (missing gt:escalation label)", id)
+	}
+
+	fields := ParseEscalationFields(issue.Description)
+	fields.Notifications = MergeNotificationStatuses(fields.Notifications, statuses)
+	description := FormatEscalationDescription(issue.Title, fields)
+
+	opts := UpdateOptions{Description: &description}
+	switch failed := NotificationsFailed(fields.Notifications); {
+	case failed && !HasLabel(issue, "notify-failed"):
+		opts.AddLabels = []string{"notify-failed"}
+	case !failed && HasLabel(issue, "notify-failed"):
+		opts.RemoveLabels = []string{"notify-failed"}
+	}
+	return b.Update(id, opts)
+}
+
+// NotificationsFailed reports whether any channel in a notifications field
+// value is recorded as failed.
+func NotificationsFailed(notifications string) bool {
+	for _, pair := range strings.Fields(notifications) {
+		if _, status, ok := strings.Cut(pair, "="); ok && status == "failed" {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeNotificationStatuses merges channel statuses into a notifications
+// field value ("channel=status" pairs separated by spaces, sorted by channel).
+func MergeNotificationStatuses(existing string, statuses map[string]string) string {
+	merged := make(map[string]string)
+	for _, pair := range strings.Fields(existing) {
+		if channel, status, ok := strings.Cut(pair, "="); ok {
+			merged[channel] = status
+		}
+	}
+	for channel, status := range statuses {
+		merged[channel] = status
+	}
+
+	channels := make([]string, 0, len(merged))
+	for channel := range merged {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+
+	pairs := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		pairs = append(pairs, channel+"="+merged[channel])
+	}
+	return strings.Join(pairs, " ")
+}
+
 // GetEscalationBead retrieves an escalation bead by ID.
 // Returns nil if not found.
 func (b *Beads) GetEscalationBead(id string) (*Issue, *EscalationFields, error) {
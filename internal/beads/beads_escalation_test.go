@@ -257,6 +257,7 @@ func TestEscalationFieldsRoundTrip(t *testing.T) {
 		ReescalationCount: 1,
 		LastReescalatedAt: "2024-06-15T11:30:00Z",
 		LastReescalatedBy: "deacon",
+		Notifications:     "email:human=delivered slack=failed",
 	}
 
 	formatted := FormatEscalationDescription("Escalation: Agent stuck", original)
@@ -298,6 +299,60 @@ func TestEscalationFieldsRoundTrip(t *testing.T) {
 	if parsed.LastReescalatedBy != original.LastReescalatedBy {
 		t.Errorf("LastReescalatedBy: got %q, want %q", parsed.LastReescalatedBy, original.LastReescalatedBy)
 	}
+	if parsed.Notifications != original.Notifications {
+		t.Errorf("Notifications: got %q, want %q", parsed.Notifications, original.Notifications)
+	}
+}
+
+func TestMergeNotificationStatuses(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		statuses map[string]string
+		want     string
+	}{
+		{
+			name:     "empty existing",
+			statuses: map[string]string{"slack": "delivered", "email:human": "failed"},
+			want:     "email:human=failed slack=delivered",
+		},
+		{
+			name:     "overwrites retried channel",
+			existing: "email:human=failed log=delivered",
+			statuses: map[string]string{"email:human": "delivered"},
+			want:     "email:human=delivered log=delivered",
+		},
+		{
+			name:     "adds new channel",
+			existing: "log=delivered",
+			statuses: map[string]string{"sms:human": "delivered"},
+			want:     "log=delivered sms:human=delivered",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeNotificationStatuses(tt.existing, tt.statuses)
+			if got != tt.want {
+				t.Errorf("MergeNotificationStatuses() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotificationsFailed(t *testing.T) {
+	tests := map[string]bool{
+		"":                                    false,
+		"email:human=failed log=delivered":    true,
+		"email:human=delivered log=delivered": false,
+		"sms:human=skipped slack=delivered":   false,
+		"slack=failed":                        true,
+	}
+	for notifications, want := range tests {
+		if got := NotificationsFailed(notifications); got != want {
+			t.Errorf("NotificationsFailed(%q) = %v, want %v", notifications, got, want)
+		}
+	}
 }
 
 func TestBumpSeverity(t *testing.T) {
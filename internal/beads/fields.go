@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 )
 
 // Note: AgentFields, ParseAgentFields, FormatAgentDescription, and CreateAgentBead are in beads.go
@@ -713,3 +714,179 @@ func ExpandRolePattern(pattern, townRoot, rig, name, role string) string {
 	result = strings.ReplaceAll(result, "{role}", role)
 	return result
 }
+
+// StepFields holds the execution policy and state of a molecule step bead.
+// The policy comes from the formula step (timeout, retries, on_failure) and
+// is stamped on the bead when the molecule is created; the state fields are
+// updated as the step runs.
+type StepFields struct {
+	StepID      string // Formula step ID
+	Timeout     string // Go duration, e.g. "30m"
+	Retries     int    // Retries allowed after a failure
+	OnFailure   string // "continue", "abort" or "escalate"
+	Attempts    int    // Failed attempts so far
+	StartedAt   string // RFC3339 start of the current attempt
+	EscalatedAt string // RFC3339 time the witness escalated a timeout
+	LastError   string // Reason given for the most recent failure
+}
+
+// stepFieldKeys are the description keys owned by StepFields.
+var stepFieldKeys = map[string]bool{
+	"step_id":           true,
+	"step_timeout":      true,
+	"step_retries":      true,
+	"step_on_failure":   true,
+	"step_attempts":     true,
+	"step_started_at":   true,
+	"step_escalated_at": true,
+	"step_last_error":   true,
+}
+
+// ParseStepFields extracts step fields from an issue's description.
+// Returns nil if no step fields are present.
+func ParseStepFields(issue *Issue) *StepFields {
+	if issue == nil || issue.Description == "" {
+		return nil
+	}
+
+	fields := &StepFields{}
+	hasFields := false
+
+	for _, line := range strings.Split(issue.Description, "\n") {
+		line = strings.TrimSpace(line)
+		colonIdx := strings.Index(line, ":")
+		if colonIdx == -1 {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(line[:colonIdx]))
+		value := strings.TrimSpace(line[colonIdx+1:])
+		if value == "" || !stepFieldKeys[key] {
+			continue
+		}
+		hasFields = true
+
+		switch key {
+		case "step_id":
+			fields.StepID = value
+		case "step_timeout":
+			fields.Timeout = value
+		case "step_retries":
+			if n, err := parseIntField(value); err == nil {
+				fields.Retries = n
+			}
+		case "step_on_failure":
+			fields.OnFailure = value
+		case "step_attempts":
+			if n, err := parseIntField(value); err == nil {
+				fields.Attempts = n
+			}
+		case "step_started_at":
+			fields.StartedAt = value
+		case "step_escalated_at":
+			fields.EscalatedAt = value
+		case "step_last_error":
+			fields.LastError = value
+		}
+	}
+
+	if !hasFields {
+		return nil
+	}
+	return fields
+}
+
+// FormatStepFields formats StepFields as description lines.
+// Only non-empty fields are included.
+func FormatStepFields(fields *StepFields) string {
+	if fields == nil {
+		return ""
+	}
+
+	var lines []string
+	if fields.StepID != "" {
+		lines = append(lines, "step_id: "+fields.StepID)
+	}
+	if fields.Timeout != "" {
+		lines = append(lines, "step_timeout: "+fields.Timeout)
+	}
+	if fields.Retries > 0 {
+		lines = append(lines, fmt.Sprintf("step_retries: %d", fields.Retries))
+	}
+	if fields.OnFailure != "" {
+		lines = append(lines, "step_on_failure: "+fields.OnFailure)
+	}
+	if fields.Attempts > 0 {
+		lines = append(lines, fmt.Sprintf("step_attempts: %d", fields.Attempts))
+	}
+	if fields.StartedAt != "" {
+		lines = append(lines, "step_started_at: "+fields.StartedAt)
+	}
+	if fields.EscalatedAt != "" {
+		lines = append(lines, "step_escalated_at: "+fields.EscalatedAt)
+	}
+	if fields.LastError != "" {
+		// Keep the reason on one line so it parses back
+		lines = append(lines, "step_last_error: "+strings.Join(strings.Fields(fields.LastError), " "))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// SetStepFields returns the issue description with its step field lines
+// replaced by fields. Step fields are appended after the step's own
+// instructions, which are left untouched.
+func SetStepFields(issue *Issue, fields *StepFields) string {
+	var otherLines []string
+	if issue != nil && issue.Description != "" {
+		for _, line := range strings.Split(issue.Description, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if colonIdx := strings.Index(trimmed, ":"); colonIdx != -1 {
+				if stepFieldKeys[strings.ToLower(strings.TrimSpace(trimmed[:colonIdx]))] {
+					continue
+				}
+			}
+			otherLines = append(otherLines, line)
+		}
+	}
+
+	for len(otherLines) > 0 && strings.TrimSpace(otherLines[len(otherLines)-1]) == "" {
+		otherLines = otherLines[:len(otherLines)-1]
+	}
+
+	formatted := FormatStepFields(fields)
+	if formatted == "" {
+		return strings.Join(otherLines, "\n")
+	}
+	if len(otherLines) == 0 {
+		return formatted
+	}
+	return strings.Join(otherLines, "\n") + "\n\n" + formatted
+}
+
+// TimeoutDuration returns the step timeout, or 0 if unset or invalid.
+func (f *StepFields) TimeoutDuration() time.Duration {
+	if f == nil || f.Timeout == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(f.Timeout)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// Overdue reports whether the current attempt has run longer than the
+// step timeout, and by how long it has been running.
+func (f *StepFields) Overdue(now time.Time) (time.Duration, bool) {
+	timeout := f.TimeoutDuration()
+	if timeout <= 0 || f.StartedAt == "" {
+		return 0, false
+	}
+	started, err := time.Parse(time.RFC3339, f.StartedAt)
+	if err != nil {
+		return 0, false
+	}
+	elapsed := now.Sub(started)
+	return elapsed, elapsed > timeout
+}
@@ -1,7 +1,9 @@
 package beads
 
 import (
+	"strings"
 	"testing"
+	"time"
 )
 
 // --- SynthesisFields (not covered in beads_test.go) ---
@@ -266,3 +268,59 @@ func indexSubstring(s, substr string) int {
 	}
 	return -1
 }
+
+// --- StepFields ---
+
+func TestStepFieldsRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "Run the test suite.\n\nNote: keep output short.\n"}
+	fields := &StepFields{
+		StepID:    "test",
+		Timeout:   "30m",
+		Retries:   2,
+		OnFailure: "escalate",
+		Attempts:  1,
+		StartedAt: "2026-01-01T12:00:00Z",
+		LastError: "flaky\nnetwork",
+	}
+
+	issue.Description = SetStepFields(issue, fields)
+	if !strings.HasPrefix(issue.Description, "Run the test suite.\n\nNote: keep output short.\n\nstep_id: test") {
+		t.Errorf("description = %q, want instructions first", issue.Description)
+	}
+
+	parsed := ParseStepFields(issue)
+	if parsed == nil {
+		t.Fatal("ParseStepFields returned nil")
+	}
+	fields.LastError = "flaky network"
+	if *parsed != *fields {
+		t.Errorf("parsed = %+v, want %+v", *parsed, *fields)
+	}
+
+	// Updating replaces the old lines rather than appending
+	parsed.Attempts = 2
+	parsed.StartedAt = ""
+	issue.Description = SetStepFields(issue, parsed)
+	if strings.Count(issue.Description, "step_attempts") != 1 || strings.Contains(issue.Description, "step_started_at") {
+		t.Errorf("description = %q", issue.Description)
+	}
+
+	if ParseStepFields(&Issue{Description: "timeout: 5m"}) != nil {
+		t.Error("unprefixed keys parsed as step fields")
+	}
+}
+
+func TestStepFieldsOverdue(t *testing.T) {
+	now := time.Date(2026, 1, 1, 13, 0, 0, 0, time.UTC)
+	fields := &StepFields{Timeout: "30m", StartedAt: "2026-01-01T12:00:00Z"}
+	if elapsed, overdue := fields.Overdue(now); !overdue || elapsed != time.Hour {
+		t.Errorf("Overdue = %v, %v; want 1h, true", elapsed, overdue)
+	}
+	fields.Timeout = "2h"
+	if _, overdue := fields.Overdue(now); overdue {
+		t.Error("step within timeout reported overdue")
+	}
+	if _, overdue := (&StepFields{Timeout: "1m"}).Overdue(now); overdue {
+		t.Error("unstarted step reported overdue")
+	}
+}
@@ -0,0 +1,578 @@
+package beads
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default values bd applies when creating issues.
+const (
+	memDefaultPriority = 2
+	memBlockingDepType = "blocks"
+	memDefaultLimit    = 50 // bd list's page size when no --limit is given
+)
+
+// memDep is a directed dependency edge: from depends on to.
+type memDep struct {
+	from, to, depType string
+}
+
+// MemStore is an in-memory Store for tests and dry runs.
+// It mirrors the bd semantics Gas Town relies on: generated IDs with a
+// prefix, type labels (gt:<type>), status filtering that hides closed issues
+// by default, bd's default List page size, and readiness derived from
+// "blocks" dependencies.
+// Issues returned by MemStore are copies; mutate them through Update.
+// A MemStore is safe for concurrent use.
+type MemStore struct {
+	mu     sync.Mutex
+	prefix string
+	next   int
+	order  []string // creation order, for stable listings
+	issues map[string]*Issue
+	deps   []memDep
+
+	// Merge slot (bd merge-slot): the slot bead, its holder and waiters
+	slotID      string
+	slotHolder  string
+	slotWaiters []string
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+var _ Store = (*MemStore)(nil)
+
+// NewMemStore returns an empty MemStore that generates IDs as <prefix>-<n>.
+func NewMemStore(prefix string) *MemStore {
+	if prefix == "" {
+		prefix = "mem"
+	}
+	return &MemStore{
+		prefix: strings.TrimSuffix(prefix, "-"),
+		issues: make(map[string]*Issue),
+		Now:    time.Now,
+	}
+}
+
+func (m *MemStore) timestamp() string {
+	return m.Now().UTC().Format(time.RFC3339)
+}
+
+// List returns issues matching the given options in creation order.
+// An empty Status excludes closed issues; "all" includes everything.
+func (m *MemStore) List(opts ListOptions) ([]*Issue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	label := opts.Label
+	if label == "" && opts.Type != "" {
+		label = "gt:" + opts.Type
+	}
+
+	limit := opts.Limit
+	if limit == 0 {
+		limit = memDefaultLimit
+	}
+	var result []*Issue
+	for _, id := range m.order {
+		issue := m.issues[id]
+		switch opts.Status {
+		case "":
+			if issue.Status == "closed" {
+				continue
+			}
+		case "all":
+		default:
+			if issue.Status != opts.Status {
+				continue
+			}
+		}
+		if label != "" && !HasLabel(issue, label) {
+			continue
+		}
+		if opts.Priority >= 0 && issue.Priority != opts.Priority {
+			continue
+		}
+		if opts.Parent != "" && issue.Parent != opts.Parent {
+			continue
+		}
+		if opts.Assignee != "" && issue.Assignee != opts.Assignee {
+			continue
+		}
+		if opts.NoAssignee && issue.Assignee != "" {
+			continue
+		}
+		if opts.Ephemeral && !issue.Ephemeral {
+			continue
+		}
+		result = append(result, m.view(issue))
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// Show returns an issue with its dependency details filled in.
+func (m *MemStore) Show(id string) (*Issue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	issue, ok := m.issues[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return m.view(issue), nil
+}
+
+// ShowMultiple returns the requested issues keyed by ID.
+// Missing IDs are not included in the map.
+func (m *MemStore) ShowMultiple(ids []string) (map[string]*Issue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make(map[string]*Issue, len(ids))
+	for _, id := range ids {
+		if issue, ok := m.issues[id]; ok {
+			result[id] = m.view(issue)
+		}
+	}
+	return result, nil
+}
+
+// Ready returns open issues with no open blockers.
+func (m *MemStore) Ready() ([]*Issue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []*Issue
+	for _, id := range m.order {
+		issue := m.issues[id]
+		if issue.Status == "open" && len(m.openBlockers(id)) == 0 {
+			result = append(result, m.view(issue))
+		}
+	}
+	return result, nil
+}
+
+// ReadyWithType returns ready issues carrying the gt:<issueType> label.
+func (m *MemStore) ReadyWithType(issueType string) ([]*Issue, error) {
+	ready, err := m.Ready()
+	if err != nil {
+		return nil, err
+	}
+	var result []*Issue
+	for _, issue := range ready {
+		if HasLabel(issue, "gt:"+issueType) {
+			result = append(result, issue)
+		}
+	}
+	return result, nil
+}
+
+// Blocked returns unclosed issues that have at least one open blocker.
+func (m *MemStore) Blocked() ([]*Issue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []*Issue
+	for _, id := range m.order {
+		issue := m.issues[id]
+		if issue.Status != "closed" && len(m.openBlockers(id)) > 0 {
+			result = append(result, m.view(issue))
+		}
+	}
+	return result, nil
+}
+
+// Create creates an issue with a generated ID.
+func (m *MemStore) Create(opts CreateOptions) (*Issue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var id string
+	for {
+		m.next++
+		id = fmt.Sprintf("%s-%d", m.prefix, m.next)
+		if _, exists := m.issues[id]; !exists {
+			break
+		}
+	}
+	return m.create(id, opts)
+}
+
+// CreateWithID creates an issue with a specific ID.
+func (m *MemStore) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if id == "" {
+		return nil, fmt.Errorf("issue ID required")
+	}
+	if _, exists := m.issues[id]; exists {
+		return nil, fmt.Errorf("issue %s already exists", id)
+	}
+	return m.create(id, opts)
+}
+
+func (m *MemStore) create(id string, opts CreateOptions) (*Issue, error) {
+	if opts.Title == "" {
+		return nil, fmt.Errorf("title required")
+	}
+	var parent *Issue
+	if opts.Parent != "" {
+		var ok bool
+		if parent, ok = m.issues[opts.Parent]; !ok {
+			return nil, fmt.Errorf("parent %s: %w", opts.Parent, ErrNotFound)
+		}
+	}
+
+	priority := opts.Priority
+	if priority < 0 {
+		priority = memDefaultPriority
+	}
+	now := m.timestamp()
+	issue := &Issue{
+		ID:          id,
+		Title:       opts.Title,
+		Description: opts.Description,
+		Status:      "open",
+		Priority:    priority,
+		CreatedAt:   now,
+		CreatedBy:   opts.Actor,
+		UpdatedAt:   now,
+		Parent:      opts.Parent,
+		Assignee:    opts.Assignee,
+		Ephemeral:   opts.Ephemeral,
+	}
+	for _, label := range createLabels(opts) {
+		if !HasLabel(issue, label) {
+			issue.Labels = append(issue.Labels, label)
+		}
+	}
+	if parent != nil {
+		parent.Children = append(parent.Children, id)
+	}
+
+	m.issues[id] = issue
+	m.order = append(m.order, id)
+	return m.view(issue), nil
+}
+
+// Update applies the non-nil fields of opts to an issue.
+func (m *MemStore) Update(id string, opts UpdateOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	issue, ok := m.issues[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if opts.Title != nil {
+		issue.Title = *opts.Title
+	}
+	if opts.Status != nil {
+		m.setStatus(issue, *opts.Status)
+	}
+	if opts.Priority != nil {
+		issue.Priority = *opts.Priority
+	}
+	if opts.Description != nil {
+		issue.Description = *opts.Description
+	}
+	if opts.Assignee != nil {
+		issue.Assignee = *opts.Assignee
+	}
+	// Same precedence as bd update: set-labels replaces, otherwise add/remove
+	if len(opts.SetLabels) > 0 {
+		issue.Labels = append([]string(nil), opts.SetLabels...)
+	} else {
+		for _, label := range opts.AddLabels {
+			if !HasLabel(issue, label) {
+				issue.Labels = append(issue.Labels, label)
+			}
+		}
+		for _, label := range opts.RemoveLabels {
+			issue.Labels = removeString(issue.Labels, label)
+		}
+	}
+	issue.UpdatedAt = m.timestamp()
+	return nil
+}
+
+// Close closes one or more issues. No issue is closed if any ID is unknown.
+func (m *MemStore) Close(ids ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range ids {
+		if _, ok := m.issues[id]; !ok {
+			return ErrNotFound
+		}
+	}
+	for _, id := range ids {
+		issue := m.issues[id]
+		m.setStatus(issue, "closed")
+		issue.UpdatedAt = issue.ClosedAt
+	}
+	return nil
+}
+
+// CloseWithReason closes one or more issues. The reason is not retained.
+func (m *MemStore) CloseWithReason(reason string, ids ...string) error {
+	return m.Close(ids...)
+}
+
+func (m *MemStore) setStatus(issue *Issue, status string) {
+	issue.Status = status
+	if status == "closed" {
+		issue.ClosedAt = m.timestamp()
+	} else {
+		issue.ClosedAt = ""
+	}
+}
+
+// AddDependency records that issue is blocked by dependsOn.
+func (m *MemStore) AddDependency(issue, dependsOn string) error {
+	return m.AddTypedDependency(issue, dependsOn, memBlockingDepType)
+}
+
+// AddTypedDependency adds a dependency of the given type. Like bd, the
+// target may be an external:prefix:id reference to another database.
+// Adding an existing dependency is a no-op.
+func (m *MemStore) AddTypedDependency(issue, dependsOn, depType string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if issue == dependsOn {
+		return fmt.Errorf("issue %s cannot depend on itself", issue)
+	}
+	if _, ok := m.issues[issue]; !ok {
+		return ErrNotFound
+	}
+	if _, ok := m.issues[dependsOn]; !ok && !strings.HasPrefix(dependsOn, "external:") {
+		return ErrNotFound
+	}
+	if depType == "" {
+		depType = memBlockingDepType
+	}
+
+	for _, d := range m.deps {
+		if d.from == issue && d.to == dependsOn && d.depType == depType {
+			return nil
+		}
+	}
+	m.deps = append(m.deps, memDep{from: issue, to: dependsOn, depType: depType})
+	return nil
+}
+
+// RemoveDependency removes every dependency of issue on dependsOn.
+func (m *MemStore) RemoveDependency(issue, dependsOn string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.deps[:0]
+	removed := false
+	for _, d := range m.deps {
+		if d.from == issue && d.to == dependsOn {
+			removed = true
+			continue
+		}
+		kept = append(kept, d)
+	}
+	m.deps = kept
+	if !removed {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListDependencies returns the issues linked to id in the given direction.
+// External references to issues not in the store are returned with only
+// their ID set, as bd does for unresolvable cross-database links.
+func (m *MemStore) ListDependencies(id string, opts DepListOptions) ([]*Issue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.issues[id]; !ok {
+		return nil, ErrNotFound
+	}
+
+	up := opts.Direction == DepDirectionUp
+	var result []*Issue
+	for _, d := range m.deps {
+		if opts.Type != "" && d.depType != opts.Type {
+			continue
+		}
+		var other string
+		switch {
+		case up && d.to == id:
+			other = d.from
+		case !up && d.from == id:
+			other = d.to
+		default:
+			continue
+		}
+		if issue, ok := m.issues[other]; ok {
+			result = append(result, m.view(issue))
+		} else {
+			result = append(result, &Issue{ID: other})
+		}
+	}
+	return result, nil
+}
+
+// UpdateAgentActiveMR sets the active_mr field in an agent bead's
+// description. An empty activeMR clears it.
+func (m *MemStore) UpdateAgentActiveMR(id string, activeMR string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	issue, ok := m.issues[id]
+	if !ok {
+		return ErrNotFound
+	}
+	fields := ParseAgentFields(issue.Description)
+	fields.ActiveMR = activeMR
+	issue.Description = FormatAgentDescription(issue.Title, fields)
+	issue.UpdatedAt = m.timestamp()
+	return nil
+}
+
+// MergeSlotEnsureExists creates the merge slot bead if it does not exist
+// and returns its ID.
+func (m *MemStore) MergeSlotEnsureExists() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.slotID != "" {
+		return m.slotID, nil
+	}
+	slot, err := m.create(m.prefix+"-merge-slot", CreateOptions{Title: "Merge slot", Type: "merge-slot"})
+	if err != nil {
+		return "", err
+	}
+	m.slotID = slot.ID
+	return m.slotID, nil
+}
+
+// MergeSlotAcquire takes the merge slot for holder if it is free or
+// already held by holder. Otherwise the status reports the current holder,
+// and with addWaiter holder joins the waiters.
+func (m *MemStore) MergeSlotAcquire(holder string, addWaiter bool) (*MergeSlotStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.slotID == "" {
+		return nil, fmt.Errorf("acquiring merge slot: %w", ErrNotFound)
+	}
+	if m.slotHolder == "" || m.slotHolder == holder {
+		m.slotHolder = holder
+		m.slotWaiters = removeString(m.slotWaiters, holder)
+		return &MergeSlotStatus{ID: m.slotID, Available: true, Holder: holder}, nil
+	}
+	if addWaiter && !containsString(m.slotWaiters, holder) {
+		m.slotWaiters = append(m.slotWaiters, holder)
+	}
+	return &MergeSlotStatus{
+		ID:      m.slotID,
+		Holder:  m.slotHolder,
+		Waiters: append([]string(nil), m.slotWaiters...),
+	}, nil
+}
+
+// MergeSlotRelease frees the merge slot. A non-empty holder must be the
+// one holding it.
+func (m *MemStore) MergeSlotRelease(holder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch {
+	case m.slotID == "":
+		return fmt.Errorf("releasing merge slot: %w", ErrNotFound)
+	case m.slotHolder == "":
+		return fmt.Errorf("slot release failed: slot not held")
+	case holder != "" && holder != m.slotHolder:
+		return fmt.Errorf("slot release failed: held by %s, not %s", m.slotHolder, holder)
+	}
+	m.slotHolder = ""
+	return nil
+}
+
+// openBlockers returns the IDs of unclosed issues blocking id.
+// Callers must hold m.mu.
+func (m *MemStore) openBlockers(id string) []string {
+	var blockers []string
+	for _, d := range m.deps {
+		if d.from != id || d.depType != memBlockingDepType {
+			continue
+		}
+		if target, ok := m.issues[d.to]; ok && target.Status != "closed" {
+			blockers = append(blockers, d.to)
+		}
+	}
+	return blockers
+}
+
+// view returns a copy of issue with dependency fields derived from the
+// edge list, matching what bd show --json reports. Callers must hold m.mu.
+func (m *MemStore) view(issue *Issue) *Issue {
+	c := *issue
+	c.Children = append([]string(nil), issue.Children...)
+	c.Labels = append([]string(nil), issue.Labels...)
+	c.DependsOn, c.Blocks, c.Dependencies, c.Dependents = nil, nil, nil, nil
+
+	for _, d := range m.deps {
+		switch issue.ID {
+		case d.from:
+			c.Dependencies = append(c.Dependencies, m.depView(d.to, d.depType))
+			if d.depType == memBlockingDepType {
+				c.DependsOn = append(c.DependsOn, d.to)
+			}
+		case d.to:
+			c.Dependents = append(c.Dependents, m.depView(d.from, d.depType))
+			if d.depType == memBlockingDepType {
+				c.Blocks = append(c.Blocks, d.from)
+			}
+		}
+	}
+	c.BlockedBy = m.openBlockers(issue.ID)
+	c.DependencyCount = len(c.Dependencies)
+	c.DependentCount = len(c.Dependents)
+	c.BlockedByCount = len(c.BlockedBy)
+	return &c
+}
+
+func (m *MemStore) depView(id, depType string) IssueDep {
+	dep := IssueDep{ID: id, DependencyType: depType}
+	if issue, ok := m.issues[id]; ok {
+		dep.Title = issue.Title
+		dep.Status = issue.Status
+		dep.Priority = issue.Priority
+		dep.Type = issue.Type
+	}
+	return dep
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(list []string, s string) []string {
+	out := list[:0]
+	for _, v := range list {
+		if v != s {
+			out = append(out, v)
+		}
+	}
+	return out
+}
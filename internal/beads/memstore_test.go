@@ -0,0 +1,328 @@
+package beads
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestMemStore() *MemStore {
+	m := NewMemStore("gt")
+	m.Now = func() time.Time { return time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC) }
+	return m
+}
+
+func mustCreate(t *testing.T, s Store, opts CreateOptions) *Issue {
+	t.Helper()
+	issue, err := s.Create(opts)
+	if err != nil {
+		t.Fatalf("Create(%q): %v", opts.Title, err)
+	}
+	return issue
+}
+
+func TestMemStore_CreateAndShow(t *testing.T) {
+	s := newTestMemStore()
+
+	epic := mustCreate(t, s, CreateOptions{Title: "Epic", Type: "epic", Priority: 1, Actor: "mayor"})
+	if epic.ID != "gt-1" || epic.Status != "open" || epic.CreatedBy != "mayor" {
+		t.Errorf("epic = %+v", epic)
+	}
+	if !HasLabel(epic, "gt:epic") {
+		t.Errorf("epic labels = %v, want gt:epic", epic.Labels)
+	}
+	if epic.CreatedAt != "2025-01-15T10:00:00Z" {
+		t.Errorf("CreatedAt = %q", epic.CreatedAt)
+	}
+
+	child := mustCreate(t, s, CreateOptions{Title: "Child", Parent: epic.ID, Priority: -1})
+	if child.Priority != memDefaultPriority {
+		t.Errorf("child priority = %d, want default %d", child.Priority, memDefaultPriority)
+	}
+
+	got, err := s.Show(epic.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Children) != 1 || got.Children[0] != child.ID {
+		t.Errorf("children = %v, want [%s]", got.Children, child.ID)
+	}
+
+	// Returned issues are copies
+	got.Title = "changed"
+	if again, _ := s.Show(epic.ID); again.Title != "Epic" {
+		t.Errorf("mutating a returned issue changed the store: %q", again.Title)
+	}
+
+	if _, err := s.Show("gt-404"); err != ErrNotFound {
+		t.Errorf("Show(missing) error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Create(CreateOptions{Title: "orphan", Parent: "gt-404"}); err == nil {
+		t.Error("Create with unknown parent succeeded")
+	}
+	if _, err := s.CreateWithID("hq-mayor", CreateOptions{Title: "Mayor", Type: "agent"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateWithID("hq-mayor", CreateOptions{Title: "Mayor"}); err == nil {
+		t.Error("CreateWithID with duplicate ID succeeded")
+	}
+
+	multi, err := s.ShowMultiple([]string{epic.ID, "hq-mayor", "gt-404"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(multi) != 2 || multi["hq-mayor"] == nil {
+		t.Errorf("ShowMultiple = %v, want 2 issues", multi)
+	}
+}
+
+func TestMemStore_ListFilters(t *testing.T) {
+	s := newTestMemStore()
+	a := mustCreate(t, s, CreateOptions{Title: "a", Type: "task", Priority: 1})
+	b := mustCreate(t, s, CreateOptions{Title: "b", Type: "bug", Priority: 2, Ephemeral: true})
+	c := mustCreate(t, s, CreateOptions{Title: "c", Type: "task", Priority: 2})
+
+	assignee := "gastown/polecats/nux"
+	if err := s.Update(b.ID, UpdateOptions{Assignee: &assignee}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(c.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(opts ListOptions) []string {
+		t.Helper()
+		issues, err := s.List(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, i := range issues {
+			out = append(out, i.ID)
+		}
+		return out
+	}
+	tests := []struct {
+		name string
+		opts ListOptions
+		want []string
+	}{
+		{"default hides closed", ListOptions{Priority: -1}, []string{a.ID, b.ID}},
+		{"all", ListOptions{Status: "all", Priority: -1}, []string{a.ID, b.ID, c.ID}},
+		{"closed", ListOptions{Status: "closed", Priority: -1}, []string{c.ID}},
+		{"label", ListOptions{Status: "all", Label: "gt:task", Priority: -1}, []string{a.ID, c.ID}},
+		{"type", ListOptions{Type: "bug", Priority: -1}, []string{b.ID}},
+		{"priority", ListOptions{Status: "all", Priority: 2}, []string{b.ID, c.ID}},
+		{"assignee", ListOptions{Assignee: assignee, Priority: -1}, []string{b.ID}},
+		{"no assignee", ListOptions{NoAssignee: true, Priority: -1}, []string{a.ID}},
+		{"limit", ListOptions{Status: "all", Priority: -1, Limit: 2}, []string{a.ID, b.ID}},
+		{"ephemeral", ListOptions{Status: "all", Priority: -1, Ephemeral: true}, []string{b.ID}},
+	}
+	for _, tt := range tests {
+		got := ids(tt.opts)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestMemStore_UpdateLabels(t *testing.T) {
+	s := newTestMemStore()
+	issue := mustCreate(t, s, CreateOptions{Title: "msg", Type: "message"})
+
+	if err := s.Update(issue.ID, UpdateOptions{AddLabels: []string{"read", "read", "from:mayor"}}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := s.Show(issue.ID)
+	if len(got.Labels) != 3 || !HasLabel(got, "read") {
+		t.Errorf("labels after add = %v", got.Labels)
+	}
+
+	if err := s.Update(issue.ID, UpdateOptions{RemoveLabels: []string{"read"}}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = s.Show(issue.ID)
+	if HasLabel(got, "read") {
+		t.Errorf("labels after remove = %v", got.Labels)
+	}
+
+	if err := s.Update(issue.ID, UpdateOptions{SetLabels: []string{"gt:task"}}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = s.Show(issue.ID)
+	if len(got.Labels) != 1 || got.Labels[0] != "gt:task" {
+		t.Errorf("labels after set = %v", got.Labels)
+	}
+
+	status := "in_progress"
+	if err := s.Update(issue.ID, UpdateOptions{Status: &status}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = s.Show(issue.ID); got.Status != "in_progress" {
+		t.Errorf("status = %q", got.Status)
+	}
+	if err := s.Update("gt-404", UpdateOptions{Status: &status}); err != ErrNotFound {
+		t.Errorf("Update(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemStore_ReadyAndBlocked(t *testing.T) {
+	s := newTestMemStore()
+	design := mustCreate(t, s, CreateOptions{Title: "design"})
+	build := mustCreate(t, s, CreateOptions{Title: "build"})
+
+	if err := s.AddDependency(build.ID, design.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	ready, _ := s.Ready()
+	if len(ready) != 1 || ready[0].ID != design.ID {
+		t.Errorf("ready = %v, want only %s", ready, design.ID)
+	}
+	blocked, _ := s.Blocked()
+	if len(blocked) != 1 || blocked[0].ID != build.ID {
+		t.Errorf("blocked = %v, want only %s", blocked, build.ID)
+	}
+
+	got, _ := s.Show(build.ID)
+	if len(got.DependsOn) != 1 || len(got.BlockedBy) != 1 || got.Dependencies[0].DependencyType != "blocks" {
+		t.Errorf("build deps = %+v", got)
+	}
+	if got, _ := s.Show(design.ID); len(got.Blocks) != 1 || got.Blocks[0] != build.ID {
+		t.Errorf("design blocks = %v", got.Blocks)
+	}
+
+	if err := s.CloseWithReason("done", design.ID); err != nil {
+		t.Fatal(err)
+	}
+	ready, _ = s.Ready()
+	if len(ready) != 1 || ready[0].ID != build.ID {
+		t.Errorf("ready after close = %v, want only %s", ready, build.ID)
+	}
+	if blocked, _ := s.Blocked(); len(blocked) != 0 {
+		t.Errorf("blocked after close = %v, want none", blocked)
+	}
+
+	if err := s.RemoveDependency(build.ID, design.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RemoveDependency(build.ID, design.ID); err != ErrNotFound {
+		t.Errorf("second RemoveDependency error = %v, want ErrNotFound", err)
+	}
+	if err := s.AddDependency(build.ID, build.ID); err == nil {
+		t.Error("self dependency succeeded")
+	}
+}
+
+func TestMemStore_ListDependencies(t *testing.T) {
+	s := NewMemStore("hq")
+	convoy := mustCreate(t, s, CreateOptions{Title: "convoy", Type: "convoy"})
+	local := mustCreate(t, s, CreateOptions{Title: "local work"})
+
+	if err := s.AddTypedDependency(convoy.ID, local.ID, "tracks"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddTypedDependency(convoy.ID, "external:gt:gt-abc", "tracks"); err != nil {
+		t.Fatal(err)
+	}
+	// Duplicate adds are no-ops
+	if err := s.AddTypedDependency(convoy.ID, local.ID, "tracks"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddTypedDependency(convoy.ID, "hq-404", "tracks"); err != ErrNotFound {
+		t.Errorf("dependency on missing issue error = %v, want ErrNotFound", err)
+	}
+
+	down, err := s.ListDependencies(convoy.ID, DepListOptions{Type: "tracks"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(down) != 2 || down[0].ID != local.ID || down[1].ID != "external:gt:gt-abc" {
+		t.Errorf("tracked = %v", down)
+	}
+
+	up, err := s.ListDependencies(local.ID, DepListOptions{Direction: DepDirectionUp, Type: "tracks"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(up) != 1 || up[0].ID != convoy.ID {
+		t.Errorf("tracking = %v, want [%s]", up, convoy.ID)
+	}
+
+	if blocks, _ := s.ListDependencies(local.ID, DepListOptions{Direction: DepDirectionUp, Type: "blocks"}); len(blocks) != 0 {
+		t.Errorf("blocks = %v, want none", blocks)
+	}
+
+	// Tracking is not blocking
+	if ready, _ := s.Ready(); len(ready) != 2 {
+		t.Errorf("ready = %v, want both issues", ready)
+	}
+}
+
+func TestMemStore_CreateAssigneeAndLabels(t *testing.T) {
+	s := newTestMemStore()
+	msg := mustCreate(t, s, CreateOptions{Title: "hi", Type: "message", Assignee: "mayor/", Labels: []string{"from:deacon/", "gt:message"}})
+	if msg.Assignee != "mayor/" {
+		t.Errorf("Assignee = %q", msg.Assignee)
+	}
+	if len(msg.Labels) != 2 || !HasLabel(msg, "gt:message") || !HasLabel(msg, "from:deacon/") {
+		t.Errorf("Labels = %v", msg.Labels)
+	}
+}
+
+func TestMemStore_RefineryOperations(t *testing.T) {
+	s := newTestMemStore()
+	mr := mustCreate(t, s, CreateOptions{Title: "MR", Type: "merge-request"})
+	mustCreate(t, s, CreateOptions{Title: "task", Type: "task"})
+	if ready, _ := s.ReadyWithType("merge-request"); len(ready) != 1 || ready[0].ID != mr.ID {
+		t.Errorf("ReadyWithType = %v, want [%s]", ready, mr.ID)
+	}
+
+	agent := mustCreate(t, s, CreateOptions{Title: "Polecat nux", Type: "agent", Description: FormatAgentDescription("Polecat nux", &AgentFields{RoleType: "polecat"})})
+	if err := s.UpdateAgentActiveMR(agent.ID, mr.ID); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := s.Show(agent.ID)
+	if fields := ParseAgentFields(got.Description); fields.ActiveMR != mr.ID || fields.RoleType != "polecat" {
+		t.Errorf("agent fields = %+v", fields)
+	}
+	if err := s.UpdateAgentActiveMR("gt-404", ""); err != ErrNotFound {
+		t.Errorf("UpdateAgentActiveMR on missing bead = %v, want ErrNotFound", err)
+	}
+
+	if _, err := s.MergeSlotAcquire("gastown/refinery", false); err == nil {
+		t.Error("acquired a merge slot that does not exist")
+	}
+	slot, err := s.MergeSlotEnsureExists()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := s.MergeSlotEnsureExists(); again != slot {
+		t.Errorf("MergeSlotEnsureExists = %q, then %q", slot, again)
+	}
+	if st, _ := s.MergeSlotAcquire("gastown/refinery", false); !st.Available || st.Holder != "gastown/refinery" {
+		t.Errorf("first acquire = %+v", st)
+	}
+	if st, _ := s.MergeSlotAcquire("gastown/polecats/nux", true); st.Available || st.Holder != "gastown/refinery" || len(st.Waiters) != 1 {
+		t.Errorf("contended acquire = %+v", st)
+	}
+	if err := s.MergeSlotRelease("gastown/polecats/nux"); err == nil {
+		t.Error("released a slot held by someone else")
+	}
+	if err := s.MergeSlotRelease("gastown/refinery"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MergeSlotRelease("gastown/refinery"); err == nil || !strings.Contains(err.Error(), "not held") {
+		t.Errorf("second release = %v, want not held", err)
+	}
+	if st, _ := s.MergeSlotAcquire("gastown/polecats/nux", false); !st.Available || len(st.Waiters) != 0 {
+		t.Errorf("acquire after release = %+v", st)
+	}
+}
@@ -0,0 +1,50 @@
+package beads
+
+// Store is the set of issue operations Gas Town components need from beads.
+//
+// *Beads is the production implementation and shells out to bd. MemStore
+// keeps everything in memory for tests and dry runs. Code that only needs
+// list/show/create/update/close/dependency/label operations should accept a
+// Store rather than *Beads so it can run without a bd binary. Labels are
+// managed through UpdateOptions (AddLabels, RemoveLabels, SetLabels).
+// The agent-bead and merge-slot operations are the ones the refinery needs
+// beyond plain issue CRUD.
+type Store interface {
+	List(opts ListOptions) ([]*Issue, error)
+	Show(id string) (*Issue, error)
+	ShowMultiple(ids []string) (map[string]*Issue, error)
+	Ready() ([]*Issue, error)
+	ReadyWithType(issueType string) ([]*Issue, error)
+	Blocked() ([]*Issue, error)
+
+	Create(opts CreateOptions) (*Issue, error)
+	CreateWithID(id string, opts CreateOptions) (*Issue, error)
+	Update(id string, opts UpdateOptions) error
+	Close(ids ...string) error
+	CloseWithReason(reason string, ids ...string) error
+
+	AddDependency(issue, dependsOn string) error
+	AddTypedDependency(issue, dependsOn, depType string) error
+	RemoveDependency(issue, dependsOn string) error
+	ListDependencies(id string, opts DepListOptions) ([]*Issue, error)
+
+	UpdateAgentActiveMR(id string, activeMR string) error
+
+	MergeSlotEnsureExists() (string, error)
+	MergeSlotAcquire(holder string, addWaiter bool) (*MergeSlotStatus, error)
+	MergeSlotRelease(holder string) error
+}
+
+var _ Store = (*Beads)(nil)
+
+// Dependency directions for DepListOptions.
+const (
+	DepDirectionDown = "down" // issues that id depends on
+	DepDirectionUp   = "up"   // issues that depend on id
+)
+
+// DepListOptions filters the issues returned by ListDependencies.
+type DepListOptions struct {
+	Direction string // DepDirectionDown (default) or DepDirectionUp
+	Type      string // Dependency type filter (e.g., "tracks"); empty for all
+}
@@ -0,0 +1,221 @@
+// Package budget checks agent spend against the caps configured on rigs and
+// convoys.
+//
+// Spend comes from the same sources gt costs reports on: session entries in
+// ~/.gt/costs.jsonl (appended by the Stop hook via gt costs record) and the
+// daily "Cost Report" digest beads those entries are rolled into. A Ledger
+// combines both; Evaluate compares a Spend against a config.BudgetConfig.
+package budget
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// dayFormat is the date format used by cost digests.
+const dayFormat = "2006-01-02"
+
+// Entry is one session's recorded cost, as written to costs.jsonl by
+// gt costs record and kept in digest payloads.
+type Entry struct {
+	SessionID string    `json:"session_id"`
+	Role      string    `json:"role"`
+	Rig       string    `json:"rig,omitempty"`
+	Worker    string    `json:"worker,omitempty"`
+	CostUSD   float64   `json:"cost_usd"`
+	EndedAt   time.Time `json:"ended_at"`
+	WorkItem  string    `json:"work_item,omitempty"`
+}
+
+// Digest is the payload of a daily "Cost Report" bead.
+type Digest struct {
+	Date     string             `json:"date"`
+	TotalUSD float64            `json:"total_usd"`
+	ByRig    map[string]float64 `json:"by_rig,omitempty"`
+	Sessions []Entry            `json:"sessions"`
+}
+
+// LogPath returns the path of the session cost log (~/.gt/costs.jsonl).
+func LogPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "/tmp/gt-costs.jsonl" // Fallback
+	}
+	return filepath.Join(home, ".gt", "costs.jsonl")
+}
+
+// ReadLog reads session cost entries. A missing log yields no entries;
+// malformed lines are skipped.
+func ReadLog(path string) ([]Entry, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is the well-known costs log
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening costs log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading costs log: %w", err)
+	}
+	return entries, nil
+}
+
+// Spend is the amount spent in USD today and overall.
+type Spend struct {
+	Daily float64 `json:"daily_usd"`
+	Total float64 `json:"total_usd"`
+}
+
+// Ledger is the spend history: digested days plus log entries that have not
+// been digested yet. Log entries for a day that already has a digest are
+// ignored, so a digest whose source entries were not cleaned up is not
+// counted twice.
+type Ledger struct {
+	Digests []Digest
+	Entries []Entry
+}
+
+// each calls fn for every session entry in the ledger.
+func (l *Ledger) each(fn func(day string, e Entry)) {
+	digested := make(map[string]bool, len(l.Digests))
+	for _, d := range l.Digests {
+		digested[d.Date] = true
+		for _, e := range d.Sessions {
+			fn(d.Date, e)
+		}
+	}
+	for _, e := range l.Entries {
+		day := e.EndedAt.Local().Format(dayFormat)
+		if digested[day] {
+			continue
+		}
+		fn(day, e)
+	}
+}
+
+// Rig returns a rig's spend, with Daily counting the day containing now.
+func (l *Ledger) Rig(rig string, now time.Time) Spend {
+	today := now.Local().Format(dayFormat)
+	var s Spend
+	l.each(func(day string, e Entry) {
+		if e.Rig != rig {
+			return
+		}
+		s.Total += e.CostUSD
+		if day == today {
+			s.Daily += e.CostUSD
+		}
+	})
+	return s
+}
+
+// WorkItems returns the spend attributed to a set of work items (the
+// issues a convoy tracks), with Daily counting the day containing now.
+func (l *Ledger) WorkItems(items map[string]bool, now time.Time) Spend {
+	today := now.Local().Format(dayFormat)
+	var s Spend
+	l.each(func(day string, e Entry) {
+		if e.WorkItem == "" || !items[e.WorkItem] {
+			return
+		}
+		s.Total += e.CostUSD
+		if day == today {
+			s.Daily += e.CostUSD
+		}
+	})
+	return s
+}
+
+// Level is how close spend is to a budget.
+type Level int
+
+const (
+	// LevelOK is spend under the warning threshold.
+	LevelOK Level = iota
+	// LevelWarn is spend at or over the warning threshold (soft limit).
+	LevelWarn
+	// LevelExceeded is spend at or over a cap (hard limit).
+	LevelExceeded
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelWarn:
+		return "warn"
+	case LevelExceeded:
+		return "exceeded"
+	default:
+		return "ok"
+	}
+}
+
+// MarshalJSON encodes the level by name.
+func (l Level) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.String())
+}
+
+// UnmarshalJSON decodes a level name.
+func (l *Level) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	switch s {
+	case "warn":
+		*l = LevelWarn
+	case "exceeded":
+		*l = LevelExceeded
+	default:
+		*l = LevelOK
+	}
+	return nil
+}
+
+// Evaluate compares spend against a budget and returns the level reached
+// and a one-line explanation naming the cap that decided it. Daily and total
+// caps are checked independently; the worse result wins.
+func Evaluate(b *config.BudgetConfig, s Spend) (Level, string) {
+	if b.IsZero() {
+		return LevelOK, ""
+	}
+	warn := b.WarnFraction()
+
+	level, reason, worst := LevelOK, "", -1.0
+	check := func(name string, spent, limit float64) {
+		if limit <= 0 {
+			return
+		}
+		ratio := spent / limit
+		l := LevelOK
+		switch {
+		case ratio >= 1:
+			l = LevelExceeded
+		case ratio >= warn:
+			l = LevelWarn
+		}
+		if l > level || (l == level && ratio > worst) {
+			level, worst = l, ratio
+			reason = fmt.Sprintf("%s spend $%.2f of $%.2f cap (%.0f%%)", name, spent, limit, 100*ratio)
+		}
+	}
+	check("daily", s.Daily, b.DailyUSD)
+	check("total", s.Total, b.TotalUSD)
+	return level, reason
+}
@@ -0,0 +1,146 @@
+package budget
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestLedger(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.Local)
+	yesterday := now.AddDate(0, 0, -1)
+
+	l := &Ledger{
+		Digests: []Digest{{
+			Date: "2026-03-08",
+			Sessions: []Entry{
+				{Rig: "gastown", CostUSD: 10, WorkItem: "gt-a"},
+				{Rig: "beads", CostUSD: 4},
+			},
+		}},
+		Entries: []Entry{
+			// Already covered by the 2026-03-08 digest
+			{Rig: "gastown", CostUSD: 10, EndedAt: time.Date(2026, 3, 8, 9, 0, 0, 0, time.Local)},
+			{Rig: "gastown", CostUSD: 3, EndedAt: yesterday, WorkItem: "gt-b"},
+			{Rig: "gastown", CostUSD: 2.5, EndedAt: now, WorkItem: "gt-a"},
+			{Rig: "beads", CostUSD: 1, EndedAt: now},
+		},
+	}
+
+	if got := l.Rig("gastown", now); got != (Spend{Daily: 2.5, Total: 15.5}) {
+		t.Errorf("Rig(gastown) = %+v", got)
+	}
+	if got := l.Rig("beads", now); got != (Spend{Daily: 1, Total: 5}) {
+		t.Errorf("Rig(beads) = %+v", got)
+	}
+	if got := l.WorkItems(map[string]bool{"gt-a": true}, now); got != (Spend{Daily: 2.5, Total: 12.5}) {
+		t.Errorf("WorkItems(gt-a) = %+v", got)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	b := &config.BudgetConfig{DailyUSD: 50, TotalUSD: 500}
+	tests := []struct {
+		spend  Spend
+		want   Level
+		reason string
+	}{
+		{Spend{Daily: 10, Total: 150}, LevelOK, "total spend $150.00 of $500.00 cap (30%)"},
+		{Spend{Daily: 40, Total: 100}, LevelWarn, "daily spend $40.00 of $50.00 cap (80%)"},
+		{Spend{Daily: 10, Total: 450}, LevelWarn, "total spend $450.00 of $500.00 cap (90%)"},
+		{Spend{Daily: 50, Total: 450}, LevelExceeded, "daily spend $50.00 of $50.00 cap (100%)"},
+		{Spend{Daily: 45, Total: 600}, LevelExceeded, "total spend $600.00 of $500.00 cap (120%)"},
+	}
+	for _, tt := range tests {
+		level, reason := Evaluate(b, tt.spend)
+		if level != tt.want || reason != tt.reason {
+			t.Errorf("Evaluate(%+v) = %s %q, want %s %q", tt.spend, level, reason, tt.want, tt.reason)
+		}
+	}
+
+	if level, _ := Evaluate(&config.BudgetConfig{DailyUSD: 10, WarnPercent: 50}, Spend{Daily: 5}); level != LevelWarn {
+		t.Errorf("warn_percent=50 at 50%% = %s, want warn", level)
+	}
+	if level, reason := Evaluate(nil, Spend{Daily: 1e6}); level != LevelOK || reason != "" {
+		t.Errorf("nil budget = %s %q", level, reason)
+	}
+}
+
+func TestStateTransitions(t *testing.T) {
+	dir := t.TempDir()
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	scope := RigScope("gastown")
+
+	if _, changed := state.Update(scope, LevelOK, "", Spend{}, now); changed {
+		t.Error("first OK reported a transition")
+	}
+	tr, changed := state.Update(scope, LevelWarn, "daily", Spend{Daily: 40}, now)
+	if !changed || !tr.Raised() || tr.Released() {
+		t.Errorf("OK→warn = %+v, %v", tr, changed)
+	}
+	if _, changed := state.Update(scope, LevelWarn, "daily", Spend{Daily: 42}, now); changed {
+		t.Error("warn→warn reported a transition")
+	}
+	if tr, _ = state.Update(scope, LevelExceeded, "daily", Spend{Daily: 50}, now); !tr.Raised() {
+		t.Errorf("warn→exceeded = %+v", tr)
+	}
+
+	if err := SaveState(dir, state); err != nil {
+		t.Fatal(err)
+	}
+	state, err = LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := state.Scopes[scope]; st == nil || st.Level != LevelExceeded || st.Spend.Daily != 50 {
+		t.Fatalf("reloaded scope = %+v", st)
+	}
+
+	if tr, _ = state.Update(scope, LevelOK, "", Spend{}, now); !tr.Released() || tr.Raised() {
+		t.Errorf("exceeded→OK = %+v", tr)
+	}
+
+	state.Update(ConvoyScope("hq-cv-1"), LevelExceeded, "total", Spend{Total: 9}, now)
+	state.Update(ConvoyScope("hq-cv-2"), LevelWarn, "total", Spend{Total: 8}, now)
+	released := state.Prune(map[string]bool{scope: true})
+	if len(released) != 1 || released[0] != ConvoyScope("hq-cv-1") {
+		t.Errorf("Prune released %v, want [convoy:hq-cv-1]", released)
+	}
+	if len(state.Scopes) != 1 {
+		t.Errorf("Prune kept %d scopes, want 1", len(state.Scopes))
+	}
+}
+
+func TestReadLogAndDigests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "costs.jsonl")
+	data := `{"session_id":"gt-gastown-toast","role":"polecat","rig":"gastown","cost_usd":1.25,"ended_at":"2026-03-10T12:00:00Z","work_item":"gt-a"}
+not json
+{"session_id":"hq-mayor","role":"mayor","cost_usd":0.5,"ended_at":"2026-03-10T13:00:00Z"}
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ReadLog(path)
+	if err != nil || len(entries) != 2 || entries[0].WorkItem != "gt-a" {
+		t.Fatalf("ReadLog = %+v, %v", entries, err)
+	}
+	if entries, err := ReadLog(filepath.Join(t.TempDir(), "missing")); err != nil || entries != nil {
+		t.Errorf("ReadLog(missing) = %v, %v", entries, err)
+	}
+
+	show := `[
+  {"id":"hq-1","event_kind":"costs.digest","payload":"{\"date\":\"2026-03-09\",\"total_usd\":3,\"sessions\":[{\"rig\":\"gastown\",\"cost_usd\":3}]}"},
+  {"id":"hq-2","event_kind":"session.ended","payload":"{}"}
+]`
+	digests, err := parseDigestEvents([]byte(show))
+	if err != nil || len(digests) != 1 || digests[0].Date != "2026-03-09" || digests[0].Sessions[0].CostUSD != 3 {
+		t.Errorf("parseDigestEvents = %+v, %v", digests, err)
+	}
+}
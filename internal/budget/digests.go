@@ -0,0 +1,78 @@
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// digestEventKind is the event category of daily cost digest beads.
+const digestEventKind = "costs.digest"
+
+// digestTitlePrefix prefixes the title of every digest bead ("Cost Report 2026-01-07").
+const digestTitlePrefix = "Cost Report "
+
+// LoadDigests reads the daily cost digest beads from the beads database in
+// dir, running bdPath (usually "bd").
+func LoadDigests(dir, bdPath string) ([]Digest, error) {
+	listCmd := exec.Command(bdPath, "list", "--type=event", "--all", "--limit=0", "--json") //nolint:gosec // G204: fixed arguments
+	listCmd.Dir = dir
+	listCmd.Env = os.Environ()
+	out, err := listCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing events: %w", err)
+	}
+
+	var items []struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(out, &items); err != nil {
+		return nil, fmt.Errorf("parsing event list: %w", err)
+	}
+
+	showArgs := []string{"show", "--json"}
+	for _, item := range items {
+		if strings.HasPrefix(item.Title, digestTitlePrefix) {
+			showArgs = append(showArgs, item.ID)
+		}
+	}
+	if len(showArgs) == 2 {
+		return nil, nil
+	}
+
+	showCmd := exec.Command(bdPath, showArgs...) //nolint:gosec // G204: args are bead IDs from bd itself
+	showCmd.Dir = dir
+	showCmd.Env = os.Environ()
+	out, err = showCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("showing digests: %w", err)
+	}
+	return parseDigestEvents(out)
+}
+
+// parseDigestEvents extracts digest payloads from bd show --json output.
+func parseDigestEvents(data []byte) ([]Digest, error) {
+	var events []struct {
+		EventKind string `json:"event_kind"`
+		Payload   string `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &events); err != nil {
+		return nil, fmt.Errorf("parsing digests: %w", err)
+	}
+
+	var digests []Digest
+	for _, ev := range events {
+		if ev.EventKind != digestEventKind || ev.Payload == "" {
+			continue
+		}
+		var d Digest
+		if err := json.Unmarshal([]byte(ev.Payload), &d); err != nil || d.Date == "" {
+			continue
+		}
+		digests = append(digests, d)
+	}
+	return digests, nil
+}
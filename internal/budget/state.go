@@ -0,0 +1,130 @@
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// RigScope returns the State scope for a rig. Scopes are "rig:<name>" or
+// "convoy:<id>".
+func RigScope(rig string) string { return "rig:" + rig }
+
+// ConvoyScope returns the State scope for a convoy.
+func ConvoyScope(convoyID string) string { return "convoy:" + convoyID }
+
+// WispParkedKey is the rig wisp config key the daemon sets when it parks a
+// rig for exceeding its budget. Only rigs carrying it are unparked again when
+// the budget is released, so a human park is never undone by the daemon.
+const WispParkedKey = "budget_parked"
+
+// ScopeState is the last level a budget reached.
+type ScopeState struct {
+	Level  Level     `json:"level"`
+	Reason string    `json:"reason,omitempty"`
+	Since  time.Time `json:"since"`
+	Spend  Spend     `json:"spend"`
+}
+
+// State remembers the level each budget reached at the last check, so the
+// daemon mails and enforces once per threshold crossing instead of on every
+// heartbeat.
+type State struct {
+	Scopes map[string]*ScopeState `json:"scopes"`
+}
+
+// StatePath returns the path of the budget state file.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "budgets.json")
+}
+
+// LoadState loads budget state; a missing file yields an empty state.
+func LoadState(townRoot string) (*State, error) {
+	state := &State{Scopes: make(map[string]*ScopeState)}
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("reading budget state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing budget state: %w", err)
+	}
+	if state.Scopes == nil {
+		state.Scopes = make(map[string]*ScopeState)
+	}
+	return state, nil
+}
+
+// SaveState writes budget state.
+func SaveState(townRoot string, state *State) error {
+	path := StatePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating daemon directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Transition is a budget moving between levels.
+type Transition struct {
+	Scope  string
+	From   Level
+	To     Level
+	Reason string
+	Spend  Spend
+}
+
+// Raised reports whether spend crossed a higher threshold.
+func (t Transition) Raised() bool { return t.To > t.From }
+
+// Released reports whether a budget came back under its cap, because the
+// cap was raised or a new day reset daily spend.
+func (t Transition) Released() bool {
+	return t.From == LevelExceeded && t.To < LevelExceeded
+}
+
+// Update records the level a budget is at now and returns the transition if
+// it changed since the last check.
+func (s *State) Update(scope string, level Level, reason string, spend Spend, now time.Time) (Transition, bool) {
+	prev, ok := s.Scopes[scope]
+	from := LevelOK
+	if ok {
+		from = prev.Level
+	}
+	if ok && from == level {
+		prev.Reason = reason
+		prev.Spend = spend
+		return Transition{}, false
+	}
+	s.Scopes[scope] = &ScopeState{Level: level, Reason: reason, Since: now, Spend: spend}
+	if from == level {
+		return Transition{}, false // first sighting at OK
+	}
+	return Transition{Scope: scope, From: from, To: level, Reason: reason, Spend: spend}, true
+}
+
+// Prune drops scopes that are no longer budgeted (budget removed, convoy
+// closed), so a budget set again later starts fresh. It returns the dropped
+// scopes that were over budget, whose enforcement should be lifted.
+func (s *State) Prune(active map[string]bool) []string {
+	var released []string
+	for scope, st := range s.Scopes {
+		if active[scope] {
+			continue
+		}
+		if st.Level == LevelExceeded {
+			released = append(released, scope)
+		}
+		delete(s.Scopes, scope)
+	}
+	sort.Strings(released)
+	return released
+}
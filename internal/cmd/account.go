@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/ratelimit"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -34,7 +36,7 @@ Commands:
   gt account list              List registered accounts
   gt account add <handle>      Add a new account
   gt account default <handle>  Set the default account
-  gt account status            Show current account info`,
+  gt account status            Show current account info and cooldowns`,
 }
 
 var accountListCmd = &cobra.Command{
@@ -255,15 +257,21 @@ func runAccountDefault(cmd *cobra.Command, args []string) error {
 
 var accountStatusCmd = &cobra.Command{
 	Use:   "status",
-	Short: "Show current account info",
+	Short: "Show current account info and cooldowns",
 	Long: `Show which Claude Code account would be used for new sessions.
 
 Displays the currently resolved account based on:
 1. GT_ACCOUNT environment variable (highest priority)
 2. Default account from config
 
+Also lists accounts cooling down after hitting a usage or rate limit, and
+until when. The daemon puts an account on cooldown when a polecat hits its
+limit and restarts the polecat on the next available account; new polecats
+skip cooling accounts unless one is chosen with --account or GT_ACCOUNT.
+
 Examples:
-  gt account status           # Show current account
+  gt account status           # Show current account and cooldowns
+  gt account status --json    # JSON output
   GT_ACCOUNT=work gt account status  # Show with env override`,
 	RunE: runAccountStatus,
 }
@@ -287,6 +295,24 @@ Examples:
 	RunE: runAccountSwitch,
 }
 
+// AccountCooldown is an account resting after it hit a usage or rate limit.
+type AccountCooldown struct {
+	Handle  string    `json:"handle"`
+	Until   time.Time `json:"until"`
+	Since   time.Time `json:"since"`
+	Reason  string    `json:"reason,omitempty"`
+	Session string    `json:"session,omitempty"`
+}
+
+// AccountStatus is the JSON output of gt account status.
+type AccountStatus struct {
+	Handle    string            `json:"handle,omitempty"`
+	Email     string            `json:"email,omitempty"`
+	ConfigDir string            `json:"config_dir,omitempty"`
+	FromEnv   bool              `json:"from_env"`
+	Cooldowns []AccountCooldown `json:"cooldowns"`
+}
+
 func runAccountStatus(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwd()
 	if err != nil {
@@ -301,7 +327,12 @@ func runAccountStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("resolving account: %w", err)
 	}
 
-	if handle == "" {
+	cooldowns, err := loadAccountCooldowns(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if handle == "" && !accountJSON {
 		fmt.Println("No account configured.")
 		fmt.Println("\nTo add an account:")
 		fmt.Println("  gt account add <handle>")
@@ -311,15 +342,33 @@ func runAccountStatus(cmd *cobra.Command, args []string) error {
 	// Check if GT_ACCOUNT is overriding
 	envAccount := os.Getenv("GT_ACCOUNT")
 
-	// Load config to get full account info
-	cfg, err := config.LoadAccountsConfig(accountsPath)
-	if err != nil {
-		return fmt.Errorf("loading accounts config: %w", err)
+	status := AccountStatus{
+		Handle:    handle,
+		ConfigDir: configDir,
+		FromEnv:   envAccount != "",
+		Cooldowns: cooldowns,
 	}
 
-	acct := cfg.GetAccount(handle)
-	if acct == nil {
-		return fmt.Errorf("account '%s' not found", handle)
+	var acct *config.Account
+	var cfg *config.AccountsConfig
+	if handle != "" {
+		// Load config to get full account info
+		cfg, err = config.LoadAccountsConfig(accountsPath)
+		if err != nil {
+			return fmt.Errorf("loading accounts config: %w", err)
+		}
+
+		acct = cfg.GetAccount(handle)
+		if acct == nil {
+			return fmt.Errorf("account '%s' not found", handle)
+		}
+		status.Email = acct.Email
+	}
+
+	if accountJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
 	}
 
 	fmt.Printf("%s\n\n", style.Bold.Render("Current Account"))
@@ -338,9 +387,54 @@ func runAccountStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("\n%s\n", style.Dim.Render("(default account)"))
 	}
 
+	fmt.Printf("\n%s\n", style.Bold.Render("Cooldowns"))
+	if len(cooldowns) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No accounts cooling down"))
+		return nil
+	}
+	for _, cd := range cooldowns {
+		fmt.Printf("  %s  until %s (%s left)\n", style.Warning.Render(cd.Handle),
+			cd.Until.Local().Format("Jan 2 15:04"), time.Until(cd.Until).Round(time.Minute))
+		if cd.Reason != "" {
+			detail := cd.Reason
+			if cd.Session != "" {
+				detail = cd.Session + ": " + detail
+			}
+			fmt.Printf("    %s\n", style.Dim.Render(detail))
+		}
+	}
+
 	return nil
 }
 
+// loadAccountCooldowns returns the accounts currently cooling down, soonest
+// to recover first.
+func loadAccountCooldowns(townRoot string) ([]AccountCooldown, error) {
+	state, err := ratelimit.LoadState(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	cooldowns := []AccountCooldown{}
+	for handle := range state.Accounts {
+		cd := state.Active(handle, now)
+		if cd == nil {
+			continue
+		}
+		cooldowns = append(cooldowns, AccountCooldown{
+			Handle:  handle,
+			Until:   cd.Until,
+			Since:   cd.Since,
+			Reason:  cd.Reason,
+			Session: cd.Session,
+		})
+	}
+	sort.Slice(cooldowns, func(i, j int) bool {
+		return cooldowns[i].Until.Before(cooldowns[j].Until)
+	})
+	return cooldowns, nil
+}
+
 func runAccountSwitch(cmd *cobra.Command, args []string) error {
 	targetHandle := args[0]
 
@@ -460,6 +554,7 @@ func runAccountSwitch(cmd *cobra.Command, args []string) error {
 func init() {
 	// Add flags
 	accountListCmd.Flags().BoolVar(&accountJSON, "json", false, "Output as JSON")
+	accountStatusCmd.Flags().BoolVar(&accountJSON, "json", false, "Output as JSON")
 
 	accountAddCmd.Flags().StringVar(&accountEmail, "email", "", "Account email address")
 	accountAddCmd.Flags().StringVar(&accountDescription, "desc", "", "Account description")
@@ -404,35 +404,13 @@ func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, e
 	var entries []AuditEntry
 
 	eventsPath := filepath.Join(townRoot, events.EventsFile)
-	file, err := os.Open(eventsPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil // No events file yet
-		}
-		return nil, err
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		var e events.Event
-		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
-			continue // Skip malformed lines
-		}
-
+	err := events.Query(eventsPath, events.Filter{Since: since}, func(e events.Event) error {
 		// Apply actor filter
 		if actor != "" && !matchesActor(e.Actor, actor) {
-			continue
-		}
-
-		// Parse timestamp
-		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
-
-		// Apply since filter
-		if !since.IsZero() && ts.Before(since) {
-			continue
+			return nil
 		}
 
+		ts, _ := e.Time()
 		entries = append(entries, AuditEntry{
 			Timestamp: ts,
 			Source:    "events",
@@ -440,39 +418,39 @@ func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, e
 			Actor:     e.Actor,
 			Summary:   formatFeedSummary(e),
 		})
-	}
-
-	return entries, nil
+		return nil
+	})
+	return entries, err
 }
 
 // formatFeedSummary creates a readable summary from a feed event.
 func formatFeedSummary(e events.Event) string {
 	switch e.Type {
 	case events.TypeSling:
-		if bead, ok := e.Payload["bead"].(string); ok {
-			return fmt.Sprintf("Slung %s", bead)
+		if p, _ := events.DecodePayload[events.Sling](e); p.Bead != "" {
+			return fmt.Sprintf("Slung %s", p.Bead)
 		}
 		return "Slung work"
 	case events.TypeMerged:
-		if branch, ok := e.Payload["branch"].(string); ok {
-			return fmt.Sprintf("Merged %s", branch)
+		if p, _ := events.DecodePayload[events.Merge](e); p.Branch != "" {
+			return fmt.Sprintf("Merged %s", p.Branch)
 		}
 		return "Merged work"
 	case events.TypeMergeFailed:
-		if reason, ok := e.Payload["reason"].(string); ok {
-			return fmt.Sprintf("Merge failed: %s", reason)
+		if p, _ := events.DecodePayload[events.Merge](e); p.Reason != "" {
+			return fmt.Sprintf("Merge failed: %s", p.Reason)
 		}
 		return "Merge failed"
 	case events.TypeHandoff:
 		return "Handed off"
 	case events.TypeDone:
-		if bead, ok := e.Payload["bead"].(string); ok {
-			return fmt.Sprintf("Done %s", bead)
+		if p, _ := events.DecodePayload[events.Done](e); p.Bead != "" {
+			return fmt.Sprintf("Done %s", p.Bead)
 		}
 		return "Done"
 	case events.TypeMail:
-		if to, ok := e.Payload["to"].(string); ok {
-			return fmt.Sprintf("Sent mail to %s", to)
+		if p, _ := events.DecodePayload[events.Mail](e); p.To != "" {
+			return fmt.Sprintf("Sent mail to %s", p.To)
 		}
 		return "Sent mail"
 	default:
@@ -17,6 +17,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/federation"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tui/convoy"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -65,6 +66,7 @@ var (
 	convoyNotify       string
 	convoyOwner        string
 	convoyStatusJSON   bool
+	convoyStatusSync   bool
 	convoyListJSON     bool
 	convoyListStatus   string
 	convoyListAll      bool
@@ -107,6 +109,7 @@ WHAT IS A SWARM:
 TRACKING SEMANTICS:
   - 'tracks' relation is non-blocking (tracked issues don't block convoy)
   - Cross-prefix capable (convoy in hq-* tracks issues in gt-*, bd-*)
+  - Cross-town capable via hop:// URIs (see gt remote)
   - Landed: all tracked issues closed → notification sent to subscribers
 
 COMMANDS:
@@ -123,7 +126,7 @@ var convoyCreateCmd = &cobra.Command{
 	Long: `Create a new convoy that tracks the specified issues.
 
 The convoy is created in town-level beads (hq-* prefix) and can track
-issues across any rig.
+issues across any rig. Issues in other towns are given as hop:// URIs.
 
 The --owner flag specifies who requested the convoy (receives completion
 notification by default). If not specified, defaults to created_by.
@@ -134,7 +137,8 @@ Examples:
   gt convoy create "Release prep" gt-abc --notify           # defaults to mayor/
   gt convoy create "Release prep" gt-abc --notify ops/      # notify ops/
   gt convoy create "Feature rollout" gt-a gt-b --owner mayor/ --notify ops/
-  gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release`,
+  gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release
+  gt convoy create "Joint launch" gt-a hop://acme/main-town/greenplace/gp-xyz`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runConvoyCreate,
 }
@@ -145,7 +149,10 @@ var convoyStatusCmd = &cobra.Command{
 	Long: `Show detailed status for a convoy.
 
 Displays convoy metadata, tracked issues, and completion progress.
-Without an ID, shows status of all active convoys.`,
+Without an ID, shows status of all active convoys.
+
+Issues in other towns are shown as last synced (gt remote sync); --sync
+pulls their status first.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runConvoyStatus,
 }
@@ -171,9 +178,12 @@ var convoyAddCmd = &cobra.Command{
 
 If the convoy is closed, it will be automatically reopened.
 
+Issues in other towns are added by hop:// URI (see gt remote).
+
 Examples:
   gt convoy add hq-cv-abc gt-new-issue
-  gt convoy add hq-cv-abc gt-issue1 gt-issue2 gt-issue3`,
+  gt convoy add hq-cv-abc gt-issue1 gt-issue2 gt-issue3
+  gt convoy add hq-cv-abc hop://acme/main-town/greenplace/gp-xyz`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: runConvoyAdd,
 }
@@ -188,6 +198,9 @@ Without arguments, checks all open convoys. With a convoy ID, checks only that c
 This handles cross-rig convoy completion: convoys in town beads tracking issues
 in rig beads won't auto-close via bd close alone. This command bridges that gap.
 
+Issues in other towns (hop:// legs) are synced from their remotes first; a
+leg that cannot be read counts as open.
+
 Can be run manually or by deacon patrol to ensure convoys close promptly.
 
 Examples:
@@ -247,6 +260,7 @@ func init() {
 
 	// Status flags
 	convoyStatusCmd.Flags().BoolVar(&convoyStatusJSON, "json", false, "Output as JSON")
+	convoyStatusCmd.Flags().BoolVar(&convoyStatusSync, "sync", false, "Pull the status of remote issues before showing them")
 
 	// List flags
 	convoyListCmd.Flags().BoolVar(&convoyListJSON, "json", false, "Output as JSON")
@@ -295,10 +309,12 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 
 	// If first arg looks like an issue ID (has beads prefix), treat all args as issues
 	// and auto-generate a name from the first issue's title
-	if looksLikeIssueID(name) {
+	if looksLikeIssueID(name) || federation.IsURI(name) {
 		trackedIssues = args // All args are issue IDs
 		// Get the first issue's title to use as convoy name
-		if details := getIssueDetails(args[0]); details != nil && details.Title != "" {
+		if federation.IsURI(name) {
+			name = fmt.Sprintf("Tracking %s", args[0])
+		} else if details := getIssueDetails(args[0]); details != nil && details.Title != "" {
 			name = details.Title
 		} else {
 			name = fmt.Sprintf("Tracking %s", args[0])
@@ -310,6 +326,12 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Issues in other towns (hop:// URIs) are tracked in the description
+	trackedIssues, remoteLegs, err := splitConvoyTargets(filepath.Dir(townBeads), trackedIssues)
+	if err != nil {
+		return err
+	}
+
 	// Ensure custom types (including 'convoy') are registered in town beads.
 	// This handles cases where install didn't complete or beads was initialized manually.
 	if err := beads.EnsureCustomTypes(townBeads); err != nil {
@@ -317,7 +339,7 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create convoy issue in town beads
-	description := fmt.Sprintf("Convoy tracking %d issues", len(trackedIssues))
+	description := fmt.Sprintf("Convoy tracking %d issues", len(trackedIssues)+len(remoteLegs))
 
 	// Default owner to creator identity if not specified
 	owner := convoyOwner
@@ -333,6 +355,7 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMolecule != "" {
 		description += fmt.Sprintf("\nMolecule: %s", convoyMolecule)
 	}
+	description = withRemoteLegs(description, remoteLegs)
 
 	// Generate convoy ID with cv- prefix
 	convoyID := fmt.Sprintf("hq-cv-%s", generateShortID())
@@ -386,10 +409,16 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	// Output
 	fmt.Printf("%s Created convoy 🚚 %s\n\n", style.Bold.Render("✓"), convoyID)
 	fmt.Printf("  Name:     %s\n", name)
-	fmt.Printf("  Tracking: %d issues\n", trackedCount)
+	fmt.Printf("  Tracking: %d issues\n", trackedCount+len(remoteLegs))
 	if len(trackedIssues) > 0 {
 		fmt.Printf("  Issues:   %s\n", strings.Join(trackedIssues, ", "))
 	}
+	if len(remoteLegs) > 0 {
+		fmt.Printf("  Remote:   %d issue(s) in other towns\n", len(remoteLegs))
+		for _, u := range remoteLegs {
+			fmt.Printf("            %s\n", u)
+		}
+	}
 	if owner != "" {
 		fmt.Printf("  Owner:    %s\n", owner)
 	}
@@ -426,10 +455,11 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	var convoys []struct {
-		ID     string `json:"id"`
-		Title  string `json:"title"`
-		Status string `json:"status"`
-		Type   string `json:"issue_type"`
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		Status      string `json:"status"`
+		Type        string `json:"issue_type"`
+		Description string `json:"description"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
 		return fmt.Errorf("parsing convoy data: %w", err)
@@ -446,6 +476,11 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("'%s' is not a convoy (type: %s)", convoyID, convoy.Type)
 	}
 
+	issuesToAdd, remoteLegs, err := splitConvoyTargets(filepath.Dir(townBeads), issuesToAdd)
+	if err != nil {
+		return err
+	}
+
 	// If convoy is closed, reopen it
 	reopened := false
 	if convoy.Status == "closed" {
@@ -479,14 +514,29 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Remote issues are recorded in the convoy description
+	if len(remoteLegs) > 0 {
+		description := withRemoteLegs(convoy.Description, remoteLegs)
+		updateCmd := exec.Command("bd", "update", convoyID, "--description="+description)
+		updateCmd.Dir = townBeads
+		var updateStderr bytes.Buffer
+		updateCmd.Stderr = &updateStderr
+		if err := updateCmd.Run(); err != nil {
+			return fmt.Errorf("adding remote issues: %w (%s)", err, strings.TrimSpace(updateStderr.String()))
+		}
+	}
+
 	// Output
 	if reopened {
 		fmt.Println()
 	}
-	fmt.Printf("%s Added %d issue(s) to convoy 🚚 %s\n", style.Bold.Render("✓"), addedCount, convoyID)
+	fmt.Printf("%s Added %d issue(s) to convoy 🚚 %s\n", style.Bold.Render("✓"), addedCount+len(remoteLegs), convoyID)
 	if addedCount > 0 {
 		fmt.Printf("  Issues: %s\n", strings.Join(issuesToAdd[:addedCount], ", "))
 	}
+	for _, u := range remoteLegs {
+		fmt.Printf("  Remote: %s\n", u)
+	}
 
 	return nil
 }
@@ -571,7 +621,8 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 	if err != nil {
 		return fmt.Errorf("checking convoy %s: %w", convoyID, err)
 	}
-	if len(tracked) == 0 {
+	remote := convoyRemoteLegs(townBeads, convoy.Description, true)
+	if len(tracked) == 0 && len(remote) == 0 {
 		fmt.Printf("%s Convoy %s has no tracked issues\n", style.Dim.Render("○"), convoyID)
 		return nil
 	}
@@ -585,6 +636,12 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 			openCount++
 		}
 	}
+	for _, s := range remote {
+		if !s.Closed() {
+			allClosed = false
+			openCount++
+		}
+	}
 
 	if !allClosed {
 		fmt.Printf("%s Convoy %s has %d open issue(s) remaining\n", style.Dim.Render("○"), convoyID, openCount)
@@ -976,8 +1033,9 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 	}
 
 	var convoys []struct {
-		ID    string `json:"id"`
-		Title string `json:"title"`
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
 		return nil, fmt.Errorf("parsing convoy list: %w", err)
@@ -990,7 +1048,8 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 			style.PrintWarning("skipping convoy %s: %v", convoy.ID, err)
 			continue
 		}
-		if len(tracked) == 0 {
+		remote := convoyRemoteLegs(townBeads, convoy.Description, true)
+		if len(tracked) == 0 && len(remote) == 0 {
 			continue // No tracked issues, nothing to check
 		}
 
@@ -1002,6 +1061,12 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 				break
 			}
 		}
+		for _, s := range remote {
+			if !s.Closed() {
+				allClosed = false
+				break
+			}
+		}
 
 		if allClosed {
 			if dryRun {
@@ -1144,6 +1209,8 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting tracked issues for %s: %w", convoyID, err)
 	}
 
+	remote := convoyRemoteLegs(townBeads, convoy.Description, convoyStatusSync)
+
 	// Count completed
 	completed := 0
 	for _, t := range tracked {
@@ -1151,23 +1218,31 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			completed++
 		}
 	}
+	for _, s := range remote {
+		if s.Closed() {
+			completed++
+		}
+	}
+	total := len(tracked) + len(remote)
 
 	if convoyStatusJSON {
 		type jsonStatus struct {
-			ID        string             `json:"id"`
-			Title     string             `json:"title"`
-			Status    string             `json:"status"`
-			Tracked   []trackedIssueInfo `json:"tracked"`
-			Completed int                `json:"completed"`
-			Total     int                `json:"total"`
+			ID        string               `json:"id"`
+			Title     string               `json:"title"`
+			Status    string               `json:"status"`
+			Tracked   []trackedIssueInfo   `json:"tracked"`
+			Remote    []*federation.Status `json:"remote,omitempty"`
+			Completed int                  `json:"completed"`
+			Total     int                  `json:"total"`
 		}
 		out := jsonStatus{
 			ID:        convoy.ID,
 			Title:     convoy.Title,
 			Status:    convoy.Status,
 			Tracked:   tracked,
+			Remote:    remote,
 			Completed: completed,
-			Total:     len(tracked),
+			Total:     total,
 		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -1177,7 +1252,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 	// Human-readable output
 	fmt.Printf("🚚 %s %s\n\n", style.Bold.Render(convoy.ID+":"), convoy.Title)
 	fmt.Printf("  Status:    %s\n", formatConvoyStatus(convoy.Status))
-	fmt.Printf("  Progress:  %d/%d completed\n", completed, len(tracked))
+	fmt.Printf("  Progress:  %d/%d completed\n", completed, total)
 	fmt.Printf("  Created:   %s\n", convoy.CreatedAt)
 	if convoy.ClosedAt != "" {
 		fmt.Printf("  Closed:    %s\n", convoy.ClosedAt)
@@ -1216,6 +1291,13 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if len(remote) > 0 {
+		fmt.Printf("\n  %s\n", style.Bold.Render("Remote Issues:"))
+		for _, s := range remote {
+			printRemoteLeg("    ", s)
+		}
+	}
+
 	return nil
 }
 
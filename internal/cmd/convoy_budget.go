@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	convoyBudgetDaily float64
+	convoyBudgetTotal float64
+	convoyBudgetWarn  int
+	convoyBudgetClear bool
+	convoyBudgetJSON  bool
+)
+
+var convoyBudgetCmd = &cobra.Command{
+	Use:   "budget <convoy-id>",
+	Short: "Show or set a convoy's cost budget",
+	Long: `Show or set the cost budget of a convoy.
+
+A convoy budget caps the spend of the sessions working its tracked issues
+(attributed via the work item recorded by 'gt costs record'). The daemon
+checks budgets on every heartbeat:
+  - At warn_percent of a cap (default 80%) the mayor is mailed
+  - At the cap the convoy is labeled budget:exceeded and no more polecats
+    are spawned for its issues
+
+Raising the cap (or a new day, for daily caps) lifts enforcement on the
+daemon's next heartbeat. With no flags, shows the current budget and spend.
+
+Examples:
+  gt convoy budget hq-cv-abc                       # Show budget and spend
+  gt convoy budget hq-cv-abc --total 200           # Cap total spend at $200
+  gt convoy budget hq-cv-abc --daily 25 --warn 90  # $25/day, warn at 90%
+  gt convoy budget hq-cv-abc --clear               # Remove the budget`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvoyBudget,
+}
+
+func init() {
+	convoyBudgetCmd.Flags().Float64Var(&convoyBudgetDaily, "daily", 0, "Daily spend cap in USD (0 removes it)")
+	convoyBudgetCmd.Flags().Float64Var(&convoyBudgetTotal, "total", 0, "Total spend cap in USD (0 removes it)")
+	convoyBudgetCmd.Flags().IntVar(&convoyBudgetWarn, "warn", 0, "Percent of a cap at which to warn the mayor (default 80)")
+	convoyBudgetCmd.Flags().BoolVar(&convoyBudgetClear, "clear", false, "Remove the convoy's budget")
+	convoyBudgetCmd.Flags().BoolVar(&convoyBudgetJSON, "json", false, "Output as JSON")
+
+	convoyCmd.AddCommand(convoyBudgetCmd)
+}
+
+// ConvoyBudgetStatus is the JSON output of gt convoy budget.
+type ConvoyBudgetStatus struct {
+	ConvoyID string               `json:"convoy_id"`
+	Budget   *config.BudgetConfig `json:"budget,omitempty"`
+	Level    string               `json:"level,omitempty"`
+	Reason   string               `json:"reason,omitempty"`
+	Spend    *budget.Spend        `json:"spend,omitempty"`
+	Exceeded bool                 `json:"exceeded"`
+}
+
+func runConvoyBudget(cmd *cobra.Command, args []string) error {
+	convoyID := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	bd := beads.New(townRoot)
+	issue, err := bd.Show(convoyID)
+	if err != nil {
+		return fmt.Errorf("convoy '%s' not found", convoyID)
+	}
+	if issue.Type != "convoy" {
+		return fmt.Errorf("'%s' is not a convoy (type: %s)", convoyID, issue.Type)
+	}
+
+	flags := cmd.Flags()
+	if !convoyBudgetClear && !flags.Changed("daily") && !flags.Changed("total") && !flags.Changed("warn") {
+		return showConvoyBudget(townRoot, issue)
+	}
+
+	var b *config.BudgetConfig
+	if !convoyBudgetClear {
+		b = &config.BudgetConfig{}
+		if current := convoy.ParseBudget(issue.Description); current != nil {
+			*b = *current
+		}
+		if flags.Changed("daily") {
+			b.DailyUSD = convoyBudgetDaily
+		}
+		if flags.Changed("total") {
+			b.TotalUSD = convoyBudgetTotal
+		}
+		if flags.Changed("warn") {
+			b.WarnPercent = convoyBudgetWarn
+		}
+		if err := config.ValidateBudgetConfig(b); err != nil {
+			return err
+		}
+	}
+
+	desc := convoy.SetBudget(issue.Description, b)
+	if err := bd.Update(convoyID, beads.UpdateOptions{Description: &desc}); err != nil {
+		return fmt.Errorf("updating convoy: %w", err)
+	}
+
+	if b.IsZero() {
+		fmt.Printf("%s Removed budget from convoy %s\n", style.Success.Render("✓"), convoyID)
+	} else {
+		fmt.Printf("%s Set budget for convoy %s: %s\n", style.Success.Render("✓"), convoyID, formatBudget(b))
+	}
+	if convoy.IsBudgetExceeded(issue) {
+		fmt.Printf("  %s\n", style.Dim.Render("Convoy is over budget; the daemon lifts the block on its next heartbeat if spend is under the new cap"))
+	}
+	return nil
+}
+
+// showConvoyBudget prints a convoy's budget with the spend the daemon last saw.
+func showConvoyBudget(townRoot string, issue *beads.Issue) error {
+	status := ConvoyBudgetStatus{
+		ConvoyID: issue.ID,
+		Budget:   convoy.ParseBudget(issue.Description),
+		Exceeded: convoy.IsBudgetExceeded(issue),
+	}
+	if state, err := budget.LoadState(townRoot); err == nil {
+		if st := state.Scopes[budget.ConvoyScope(issue.ID)]; st != nil {
+			status.Level = st.Level.String()
+			status.Reason = st.Reason
+			spend := st.Spend
+			status.Spend = &spend
+		}
+	}
+
+	if convoyBudgetJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	}
+
+	if status.Budget == nil {
+		fmt.Printf("Convoy %s has no budget\n", issue.ID)
+		return nil
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render("Budget:"), formatBudget(status.Budget))
+	if status.Spend != nil {
+		fmt.Printf("%s $%.2f today, $%.2f total\n", style.Bold.Render("Spend:"), status.Spend.Daily, status.Spend.Total)
+	} else {
+		fmt.Printf("%s %s\n", style.Bold.Render("Spend:"), style.Dim.Render("not yet checked by the daemon"))
+	}
+	if status.Exceeded {
+		fmt.Printf("%s %s\n", style.Warning.Render("⚠ Over budget:"), status.Reason)
+	} else if status.Level == budget.LevelWarn.String() {
+		fmt.Printf("%s %s\n", style.Warning.Render("Near cap:"), status.Reason)
+	}
+	return nil
+}
+
+// formatBudget renders a budget as "$25.00/day, $200.00 total, warn at 80%".
+func formatBudget(b *config.BudgetConfig) string {
+	var s string
+	if b.DailyUSD > 0 {
+		s = fmt.Sprintf("$%.2f/day", b.DailyUSD)
+	}
+	if b.TotalUSD > 0 {
+		if s != "" {
+			s += ", "
+		}
+		s += fmt.Sprintf("$%.2f total", b.TotalUSD)
+	}
+	return fmt.Sprintf("%s, warn at %.0f%%", s, b.WarnFraction()*100)
+}
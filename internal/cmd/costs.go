@@ -2,10 +2,8 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
-	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -15,6 +13,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/budget"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/style"
@@ -46,11 +45,22 @@ var (
 var costsCmd = &cobra.Command{
 	Use:     "costs",
 	GroupID: GroupDiag,
-	Short:   "Show costs for running Claude sessions",
-	Long: `Display costs for Claude Code sessions in Gas Town.
+	Short:   "Show costs for running agent sessions",
+	Long: `Display costs for agent sessions in Gas Town.
 
-Costs are calculated from Claude Code transcript files at ~/.claude/projects/
-by summing token usage from assistant messages and applying model-specific pricing.
+Costs are calculated from each runtime's own transcripts by summing token
+usage and applying model-specific pricing. The agent preset's "usage"
+source says where its transcripts live and how to parse them:
+
+  claude     ~/.claude/projects/<workdir>/*.jsonl
+  codex      ~/.codex/sessions/YYYY/MM/DD/rollout-*.jsonl
+  gemini     ~/.gemini/tmp/<project-hash>/chats/session-*.json
+  opencode   ~/.local/share/opencode/storage
+
+Runtimes without a usage source (cursor, auggie, amp) are recorded at $0.
+Prices per million tokens can be overridden in settings/config.json:
+
+  "pricing": {"gpt-5": {"input": 1.25, "output": 10, "cache_read": 0.125}}
 
 Examples:
   gt costs              # Live costs from running sessions
@@ -72,8 +82,8 @@ var costsRecordCmd = &cobra.Command{
 	Short: "Record session cost to local log file (called by Stop hook)",
 	Long: `Record the final cost of a session to a local log file.
 
-This command is intended to be called from a Claude Code Stop hook.
-It reads token usage from the Claude Code transcript file (~/.claude/projects/...)
+This command is intended to be called from an agent's Stop hook.
+It reads token usage from the session's runtime transcript (see 'gt costs')
 and calculates the cost based on model pricing, then appends it to
 ~/.gt/costs.jsonl. This is a simple append operation that never fails
 due to database availability.
@@ -81,6 +91,9 @@ due to database availability.
 Session costs are aggregated daily by 'gt costs digest' into a single
 permanent "Cost Report YYYY-MM-DD" bead for audit purposes.
 
+The entry is attributed to --work-item, or to the session's GT_ISSUE when
+the flag is omitted. Work items are how convoy budgets count spend.
+
 Examples:
   gt costs record --session gt-gastown-toast
   gt costs record --session gt-gastown-toast --work-item gt-abc123`,
@@ -158,6 +171,7 @@ type SessionCost struct {
 	Role    string  `json:"role"`
 	Rig     string  `json:"rig,omitempty"`
 	Worker  string  `json:"worker,omitempty"`
+	Agent   string  `json:"agent,omitempty"`
 	Cost    float64 `json:"cost_usd"`
 	Running bool    `json:"running"`
 }
@@ -186,56 +200,6 @@ type CostsOutput struct {
 // costRegex matches cost patterns like "$1.23" or "$12.34"
 var costRegex = regexp.MustCompile(`\$(\d+\.\d{2})`)
 
-// TranscriptMessage represents a message from a Claude Code transcript file.
-type TranscriptMessage struct {
-	Type      string                 `json:"type"`
-	SessionID string                 `json:"sessionId"`
-	CWD       string                 `json:"cwd"`
-	Message   *TranscriptMessageBody `json:"message,omitempty"`
-}
-
-// TranscriptMessageBody contains the message content and usage info.
-type TranscriptMessageBody struct {
-	Model string          `json:"model"`
-	Role  string          `json:"role"`
-	Usage *TranscriptUsage `json:"usage,omitempty"`
-}
-
-// TranscriptUsage contains token usage information.
-type TranscriptUsage struct {
-	InputTokens              int `json:"input_tokens"`
-	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
-	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
-	OutputTokens             int `json:"output_tokens"`
-}
-
-// TokenUsage aggregates token usage across a session.
-type TokenUsage struct {
-	Model                    string
-	InputTokens              int
-	CacheCreationInputTokens int
-	CacheReadInputTokens     int
-	OutputTokens             int
-}
-
-// Model pricing per million tokens (as of Jan 2025).
-// See: https://www.anthropic.com/pricing
-var modelPricing = map[string]struct {
-	InputPerMillion       float64
-	OutputPerMillion      float64
-	CacheReadPerMillion   float64 // 90% discount on input price
-	CacheCreatePerMillion float64 // 25% premium on input price
-}{
-	// Claude Opus 4.5
-	"claude-opus-4-5-20251101": {15.0, 75.0, 1.5, 18.75},
-	// Claude Sonnet 4
-	"claude-sonnet-4-20250514": {3.0, 15.0, 0.3, 3.75},
-	// Claude Haiku 3.5
-	"claude-3-5-haiku-20241022": {1.0, 5.0, 0.1, 1.25},
-	// Fallback for unknown models (use Sonnet pricing)
-	"default": {3.0, 15.0, 0.3, 3.75},
-}
-
 func runCosts(cmd *cobra.Command, args []string) error {
 	// If querying ledger, use ledger functions
 	if costsToday || costsWeek || costsByRole || costsByRig {
@@ -258,6 +222,7 @@ func runLiveCosts() error {
 	var costs []SessionCost
 	var total float64
 
+	coster := newSessionCoster()
 	for _, session := range sessions {
 		// Only process Gas Town sessions (start with "gt-")
 		if !strings.HasPrefix(session, constants.SessionPrefix) {
@@ -276,8 +241,8 @@ func runLiveCosts() error {
 			continue
 		}
 
-		// Extract cost from Claude transcript
-		cost, err := extractCostFromWorkDir(workDir)
+		// Extract cost from the agent runtime's transcript
+		cost, agent, err := coster.cost(session, role, rig, workDir)
 		if err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] could not extract cost for %s: %v\n", session, err)
@@ -294,6 +259,7 @@ func runLiveCosts() error {
 			Role:    role,
 			Rig:     rig,
 			Worker:  worker,
+			Agent:   agent,
 			Cost:    cost,
 			Running: running,
 		})
@@ -707,146 +673,6 @@ func extractCost(content string) float64 {
 	return cost
 }
 
-// getClaudeProjectDir returns the Claude Code project directory for a working directory.
-// Claude Code stores transcripts in ~/.claude/projects/<path-with-dashes-instead-of-slashes>/
-func getClaudeProjectDir(workDir string) (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-
-	// Convert path to Claude's directory naming: replace / with -
-	// Keep leading slash - it becomes a leading dash in Claude's encoding
-	projectName := strings.ReplaceAll(workDir, "/", "-")
-	return filepath.Join(home, ".claude", "projects", projectName), nil
-}
-
-// findLatestTranscript finds the most recently modified .jsonl file in a directory.
-func findLatestTranscript(projectDir string) (string, error) {
-	var latestPath string
-	var latestTime time.Time
-
-	err := filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if d.IsDir() && path != projectDir {
-			return fs.SkipDir // Don't recurse into subdirectories
-		}
-		if !d.IsDir() && strings.HasSuffix(path, ".jsonl") {
-			info, err := d.Info()
-			if err != nil {
-				return nil // Skip files we can't stat
-			}
-			if info.ModTime().After(latestTime) {
-				latestTime = info.ModTime()
-				latestPath = path
-			}
-		}
-		return nil
-	})
-
-	if err != nil {
-		return "", err
-	}
-	if latestPath == "" {
-		return "", fmt.Errorf("no transcript files found in %s", projectDir)
-	}
-	return latestPath, nil
-}
-
-// parseTranscriptUsage reads a transcript file and sums token usage from assistant messages.
-func parseTranscriptUsage(transcriptPath string) (*TokenUsage, error) {
-	file, err := os.Open(transcriptPath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	usage := &TokenUsage{}
-	scanner := bufio.NewScanner(file)
-	// Increase buffer for potentially large JSON lines
-	buf := make([]byte, 0, 256*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-
-		var msg TranscriptMessage
-		if err := json.Unmarshal(line, &msg); err != nil {
-			continue // Skip malformed lines
-		}
-
-		// Only process assistant messages with usage info
-		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
-			continue
-		}
-
-		// Capture the model (use first one found, they should all be the same)
-		if usage.Model == "" && msg.Message.Model != "" {
-			usage.Model = msg.Message.Model
-		}
-
-		// Sum token usage
-		u := msg.Message.Usage
-		usage.InputTokens += u.InputTokens
-		usage.CacheCreationInputTokens += u.CacheCreationInputTokens
-		usage.CacheReadInputTokens += u.CacheReadInputTokens
-		usage.OutputTokens += u.OutputTokens
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-
-	return usage, nil
-}
-
-// calculateCost converts token usage to USD cost based on model pricing.
-func calculateCost(usage *TokenUsage) float64 {
-	if usage == nil {
-		return 0.0
-	}
-
-	// Look up pricing for the model
-	pricing, ok := modelPricing[usage.Model]
-	if !ok {
-		pricing = modelPricing["default"]
-	}
-
-	// Calculate cost (prices are per million tokens)
-	inputCost := float64(usage.InputTokens) / 1_000_000 * pricing.InputPerMillion
-	cacheReadCost := float64(usage.CacheReadInputTokens) / 1_000_000 * pricing.CacheReadPerMillion
-	cacheCreateCost := float64(usage.CacheCreationInputTokens) / 1_000_000 * pricing.CacheCreatePerMillion
-	outputCost := float64(usage.OutputTokens) / 1_000_000 * pricing.OutputPerMillion
-
-	return inputCost + cacheReadCost + cacheCreateCost + outputCost
-}
-
-// extractCostFromWorkDir extracts cost from Claude Code transcript for a working directory.
-// This reads the most recent transcript file and sums all token usage.
-func extractCostFromWorkDir(workDir string) (float64, error) {
-	projectDir, err := getClaudeProjectDir(workDir)
-	if err != nil {
-		return 0, fmt.Errorf("getting project dir: %w", err)
-	}
-
-	transcriptPath, err := findLatestTranscript(projectDir)
-	if err != nil {
-		return 0, fmt.Errorf("finding transcript: %w", err)
-	}
-
-	usage, err := parseTranscriptUsage(transcriptPath)
-	if err != nil {
-		return 0, fmt.Errorf("parsing transcript: %w", err)
-	}
-
-	return calculateCost(usage), nil
-}
-
 // getTmuxSessionWorkDir gets the current working directory of a tmux session.
 func getTmuxSessionWorkDir(session string) (string, error) {
 	cmd := exec.Command("tmux", "display-message", "-t", session, "-p", "#{pane_current_path}")
@@ -947,6 +773,7 @@ type CostLogEntry struct {
 	Role      string    `json:"role"`
 	Rig       string    `json:"rig,omitempty"`
 	Worker    string    `json:"worker,omitempty"`
+	Agent     string    `json:"agent,omitempty"`
 	CostUSD   float64   `json:"cost_usd"`
 	EndedAt   time.Time `json:"ended_at"`
 	WorkItem  string    `json:"work_item,omitempty"`
@@ -954,11 +781,16 @@ type CostLogEntry struct {
 
 // getCostsLogPath returns the path to the costs log file (~/.gt/costs.jsonl).
 func getCostsLogPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "/tmp/gt-costs.jsonl" // Fallback
+	return budget.LogPath()
+}
+
+// detectSessionWorkItem returns the issue a session is working (GT_ISSUE),
+// read from the tmux session environment and then the process environment.
+func detectSessionWorkItem(session string) string {
+	if issue, err := tmux.NewTmux().GetEnvironment(session, "GT_ISSUE"); err == nil && issue != "" {
+		return issue
 	}
-	return filepath.Join(home, ".gt", "costs.jsonl")
+	return os.Getenv("GT_ISSUE")
 }
 
 // runCostsRecord captures the final cost from a session and appends it to a local log file.
@@ -995,11 +827,15 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Extract cost from Claude transcript
+	// Parse session name
+	role, rig, worker := parseSessionName(session)
+
+	// Extract cost from the agent runtime's transcript
 	var cost float64
+	var agent string
 	if workDir != "" {
 		var err error
-		cost, err = extractCostFromWorkDir(workDir)
+		cost, agent, err = newSessionCoster().cost(session, role, rig, workDir)
 		if err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] could not extract cost from transcript: %v\n", err)
@@ -1008,8 +844,12 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Parse session name
-	role, rig, worker := parseSessionName(session)
+	// Attribute the session to the issue it was working, so convoy budgets
+	// can count it. --work-item wins over the session's GT_ISSUE.
+	workItem := recordWorkItem
+	if workItem == "" {
+		workItem = detectSessionWorkItem(session)
+	}
 
 	// Build log entry
 	entry := CostLogEntry{
@@ -1017,9 +857,10 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		Role:      role,
 		Rig:       rig,
 		Worker:    worker,
+		Agent:     agent,
 		CostUSD:   cost,
 		EndedAt:   time.Now(),
-		WorkItem:  recordWorkItem,
+		WorkItem:  workItem,
 	}
 
 	// Marshal to JSON
@@ -1052,10 +893,10 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	}
 
 	// Output confirmation (silent if cost is zero and no work item)
-	if cost > 0 || recordWorkItem != "" {
+	if cost > 0 || workItem != "" {
 		fmt.Printf("%s Recorded $%.2f for %s", style.Success.Render("✓"), cost, session)
-		if recordWorkItem != "" {
-			fmt.Printf(" (work: %s)", recordWorkItem)
+		if workItem != "" {
+			fmt.Printf(" (work: %s)", workItem)
 		}
 		fmt.Println()
 	}
@@ -3,6 +3,8 @@ package cmd
 import (
 	"os"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 func TestDeriveSessionName(t *testing.T) {
@@ -112,3 +114,31 @@ func TestDeriveSessionName(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionCosterAgent(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.DefaultAgent = "codex"
+	settings.RoleAgents = map[string]string{"mayor": "claude-opus", "crew": "gemini"}
+	settings.Agents = map[string]*config.RuntimeConfig{
+		"claude-opus": {Provider: "claude", Command: "claude", Args: []string{"--model", "opus"}},
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &sessionCoster{townRoot: townRoot}
+	tests := []struct {
+		session, role, rig string
+		want               string
+	}{
+		{"gt-nosuch-mayor", "mayor", "", "claude"}, // custom agent resolves to its provider
+		{"gt-nosuch-crew-joe", "crew", "gastown", "gemini"},
+		{"gt-nosuch-toast", "polecat", "gastown", "codex"},
+	}
+	for _, tt := range tests {
+		if got := c.sessionAgent(tt.session, tt.role, tt.rig); got != tt.want {
+			t.Errorf("sessionAgent(%s) = %q, want %q", tt.role, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/usage"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// sessionCoster prices agent sessions from their runtime's transcripts,
+// using the town's pricing table.
+type sessionCoster struct {
+	townRoot string
+	pricing  *usage.Pricing
+}
+
+func newSessionCoster() *sessionCoster {
+	townRoot, _ := workspace.FindFromCwd()
+	return &sessionCoster{townRoot: townRoot, pricing: usage.LoadPricing(townRoot)}
+}
+
+// cost returns the cost of the session that ran in workDir and the agent
+// preset it ran.
+func (c *sessionCoster) cost(session, role, rig, workDir string) (float64, string, error) {
+	agent := c.sessionAgent(session, role, rig)
+	extractor, err := usage.ForAgent(agent)
+	if err != nil {
+		return 0, agent, err
+	}
+	u, err := extractor.Extract(workDir)
+	if err != nil {
+		return 0, agent, err
+	}
+	return c.pricing.Cost(u), agent, nil
+}
+
+// sessionAgent returns the agent preset a session runs: the session's
+// GT_AGENT override, else the agent configured for its role. Custom agents
+// resolve to the preset they are built on.
+func (c *sessionCoster) sessionAgent(session, role, rig string) string {
+	if c.townRoot == "" {
+		return string(config.AgentClaude)
+	}
+	rigPath := ""
+	if rig != "" {
+		rigPath = filepath.Join(c.townRoot, rig)
+	}
+
+	name, _ := tmux.NewTmux().GetEnvironment(session, "GT_AGENT")
+	if name == "" {
+		name, _ = config.ResolveRoleAgentName(role, c.townRoot, rigPath)
+	}
+	// Loads the town and rig agent registries, so custom presets are found
+	rc, _, err := config.ResolveAgentConfigWithOverride(c.townRoot, rigPath, name)
+	if config.GetAgentPresetByName(name) != nil {
+		return name
+	}
+	if err == nil && rc.Provider != "" {
+		return rc.Provider
+	}
+	return name
+}
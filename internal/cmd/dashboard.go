@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/metrics"
 	"github.com/steveyegge/gastown/internal/web"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -16,6 +17,9 @@ import (
 var (
 	dashboardPort int
 	dashboardOpen bool
+
+	dashboardTokenReadOnly bool
+	dashboardTokenRotate   bool
 )
 
 var dashboardCmd = &cobra.Command{
@@ -30,6 +34,13 @@ The dashboard shows real-time convoy status with:
 - Last activity indicator (green/yellow/red)
 - Auto-refresh every 30 seconds via htmx
 
+Access requires the dashboard token (stored in settings/config.json under
+web_auth, generated on first run). Open the login URL printed at startup, or
+send "Authorization: Bearer <token>" from scripts. See 'gt dashboard token'.
+
+Town health metrics are served in the Prometheus format at /metrics (see
+'gt metrics'); point Prometheus at it with the read-only token.
+
 Example:
   gt dashboard              # Start on default port 8080
   gt dashboard --port 3000  # Start on port 3000
@@ -37,9 +48,33 @@ Example:
 	RunE: runDashboard,
 }
 
+var dashboardTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Show or rotate the dashboard access token",
+	Long: `Show the dashboard access token, generating one if needed.
+
+The admin token can view the dashboard and run commands. A read-only token
+can view the dashboard but not run commands, send mail or create issues.
+
+Rotating a token logs out every browser session that used it.
+
+Examples:
+  gt dashboard token                       # Show the admin token
+  gt dashboard token --read-only           # Show (or create) the read-only token
+  gt dashboard token --rotate              # Replace the admin token
+  gt dashboard token --read-only --rotate  # Replace the read-only token`,
+	Args: cobra.NoArgs,
+	RunE: runDashboardToken,
+}
+
 func init() {
 	dashboardCmd.Flags().IntVar(&dashboardPort, "port", 8080, "HTTP port to listen on")
 	dashboardCmd.Flags().BoolVar(&dashboardOpen, "open", false, "Open browser automatically")
+
+	dashboardTokenCmd.Flags().BoolVar(&dashboardTokenReadOnly, "read-only", false, "Show the read-only token instead of the admin token")
+	dashboardTokenCmd.Flags().BoolVar(&dashboardTokenRotate, "rotate", false, "Generate a new token, replacing the current one")
+	dashboardCmd.AddCommand(dashboardTokenCmd)
+
 	rootCmd.AddCommand(dashboardCmd)
 }
 
@@ -47,11 +82,21 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	// Check if we're in a workspace - if not, run in setup mode
 	var handler http.Handler
 	var err error
+	var authCfg *config.WebAuthConfig
 
 	townRoot, wsErr := workspace.FindFromCwdOrError()
 	if wsErr != nil {
-		// No workspace - run in setup mode
-		handler, err = web.NewSetupMux()
+		// No workspace - run in setup mode with a one-off token
+		token, tokenErr := web.GenerateToken()
+		if tokenErr != nil {
+			return tokenErr
+		}
+		authCfg = &config.WebAuthConfig{Token: token}
+		auth, authErr := web.NewAuth(authCfg)
+		if authErr != nil {
+			return authErr
+		}
+		handler, err = web.NewSetupMux(auth)
 		if err != nil {
 			return fmt.Errorf("creating setup handler: %w", err)
 		}
@@ -70,18 +115,34 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 			fmt.Fprintf(cmd.ErrOrStderr(), "warning: loading town settings: %v (using defaults)\n", loadErr)
 		}
 
-		handler, err = web.NewDashboardMux(fetcher, webCfg)
+		authCfg, err = web.LoadOrCreateAuthConfig(townRoot)
+		if err != nil {
+			return fmt.Errorf("loading dashboard token: %w", err)
+		}
+		auth, authErr := web.NewAuth(authCfg)
+		if authErr != nil {
+			return authErr
+		}
+
+		dashboard, err := web.NewDashboardMux(fetcher, webCfg, auth)
 		if err != nil {
 			return fmt.Errorf("creating dashboard handler: %w", err)
 		}
+
+		// Prometheus metrics, behind the same token
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", auth.Wrap(metrics.Handler(townRoot)))
+		mux.Handle("/", dashboard)
+		handler = mux
 	}
 
 	// Build the URL
 	url := fmt.Sprintf("http://localhost:%d", dashboardPort)
+	loginURL := fmt.Sprintf("%s/login?token=%s", url, authCfg.Token)
 
 	// Open browser if requested
 	if dashboardOpen {
-		go openBrowser(url)
+		go openBrowser(loginURL)
 	}
 
 	// Start the server with timeouts
@@ -108,6 +169,7 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 
 `)
 	fmt.Printf("  launching dashboard at %s  •  api: %s/api/  •  ctrl+c to stop\n", url, url)
+	fmt.Printf("  login: %s\n", loginURL)
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf(":%d", dashboardPort),
@@ -120,6 +182,45 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	return server.ListenAndServe()
 }
 
+func runDashboardToken(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	// Ensures an admin token exists before we touch the read-only one
+	if _, err := web.LoadOrCreateAuthConfig(townRoot); err != nil {
+		return err
+	}
+
+	path := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+
+	target := &settings.WebAuth.Token
+	if dashboardTokenReadOnly {
+		target = &settings.WebAuth.ReadOnlyToken
+	}
+	if *target == "" || dashboardTokenRotate {
+		token, err := web.GenerateToken()
+		if err != nil {
+			return err
+		}
+		*target = token
+		if err := config.SaveTownSettings(path, settings); err != nil {
+			return fmt.Errorf("saving town settings: %w", err)
+		}
+		if dashboardTokenRotate {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Token rotated; restart gt dashboard to apply.")
+		}
+	}
+
+	fmt.Println(*target)
+	return nil
+}
+
 // openBrowser opens the specified URL in the default browser.
 func openBrowser(url string) {
 	var cmd *exec.Cmd
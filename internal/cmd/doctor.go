@@ -3,10 +3,14 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -16,6 +20,9 @@ var (
 	doctorRig             string
 	doctorRestartSessions bool
 	doctorSlow            string
+	doctorQuick           bool
+	doctorNotify          bool
+	doctorScheduled       bool
 )
 
 var doctorCmd = &cobra.Command{
@@ -86,7 +93,13 @@ Patrol checks:
 
 Use --fix to attempt automatic fixes for issues that support it.
 Use --rig to check a specific rig instead of the entire workspace.
-Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).`,
+Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).
+Use --quick to run only the cheap checks (the daemon runs these hourly).
+Use --notify to escalate checks that regressed since the last run
+(errors escalate, warnings mail the mayor).
+
+Every run, including the fixes applied by --fix, is recorded in
+.runtime/doctor-history.jsonl. See 'gt doctor history'.`,
 	RunE: runDoctor,
 }
 
@@ -98,6 +111,10 @@ func init() {
 	doctorCmd.Flags().StringVar(&doctorSlow, "slow", "", "Highlight slow checks (optional threshold, default 1s)")
 	// Allow --slow without a value (uses default 1s)
 	doctorCmd.Flags().Lookup("slow").NoOptDefVal = "1s"
+	doctorCmd.Flags().BoolVar(&doctorQuick, "quick", false, "Run only the cheap checks")
+	doctorCmd.Flags().BoolVar(&doctorNotify, "notify", false, "Escalate checks that regressed since the last run")
+	doctorCmd.Flags().BoolVar(&doctorScheduled, "scheduled", false, "Scheduled daemon run (implies --quick --notify)")
+	_ = doctorCmd.Flags().MarkHidden("scheduled")
 	rootCmd.AddCommand(doctorCmd)
 }
 
@@ -116,9 +133,59 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		RestartSessions: doctorRestartSessions,
 	}
 
+	source := doctor.SourceCLI
+	if doctorScheduled {
+		doctorQuick, doctorNotify = true, true
+		source = doctor.SourceDaemon
+	}
+
 	// Create doctor and register checks
 	d := doctor.NewDoctor()
+	if doctorQuick {
+		d.RegisterAll(doctor.QuickChecks()...)
+	} else {
+		registerDoctorChecks(d)
+	}
+
+	// Rig-specific checks (only when --rig is specified)
+	if doctorRig != "" {
+		d.RegisterAll(doctor.RigChecks()...)
+	}
+
+	// Parse slow threshold (0 = disabled)
+	var slowThreshold time.Duration
+	if doctorSlow != "" {
+		var err error
+		slowThreshold, err = time.ParseDuration(doctorSlow)
+		if err != nil {
+			return fmt.Errorf("invalid --slow duration %q: %w", doctorSlow, err)
+		}
+	}
+
+	// Run checks with streaming output
+	fmt.Println() // Initial blank line
+	var report *doctor.Report
+	if doctorFix {
+		report = d.FixStreaming(ctx, os.Stdout, slowThreshold)
+	} else {
+		report = d.RunStreaming(ctx, os.Stdout, slowThreshold)
+	}
+
+	// Print summary (checks were already printed during streaming)
+	report.PrintSummaryOnly(os.Stdout, doctorVerbose, slowThreshold)
+
+	recordDoctorRun(townRoot, doctor.NewRunRecord(report, source, doctorRig, doctorQuick, doctorFix))
+
+	// Exit with error code if there are errors
+	if report.HasErrors() {
+		return fmt.Errorf("doctor found %d error(s)", report.Summary.Errors)
+	}
+
+	return nil
+}
 
+// registerDoctorChecks registers the full set of checks.
+func registerDoctorChecks(d *doctor.Doctor) {
 	// Register workspace-level checks first (fundamental)
 	d.RegisterAll(doctor.WorkspaceChecks()...)
 
@@ -202,39 +269,78 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	// Dolt health checks
 	d.Register(doctor.NewDoltMetadataCheck())
 	d.Register(doctor.NewDoltServerReachableCheck())
+}
 
-	// Rig-specific checks (only when --rig is specified)
-	if doctorRig != "" {
-		d.RegisterAll(doctor.RigChecks()...)
-	}
-
-	// Parse slow threshold (0 = disabled)
-	var slowThreshold time.Duration
-	if doctorSlow != "" {
+// recordDoctorRun appends a run to the doctor history and, with --notify,
+// escalates the checks that got worse since the previous run.
+func recordDoctorRun(townRoot string, run *doctor.RunRecord) {
+	var history []*doctor.RunRecord
+	if doctorNotify {
 		var err error
-		slowThreshold, err = time.ParseDuration(doctorSlow)
-		if err != nil {
-			return fmt.Errorf("invalid --slow duration %q: %w", doctorSlow, err)
+		if history, err = doctor.LoadHistory(townRoot, time.Time{}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 		}
 	}
-
-	// Run checks with streaming output
-	fmt.Println() // Initial blank line
-	var report *doctor.Report
-	if doctorFix {
-		report = d.FixStreaming(ctx, os.Stdout, slowThreshold)
-	} else {
-		report = d.RunStreaming(ctx, os.Stdout, slowThreshold)
+	if err := doctor.AppendHistory(townRoot, run); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: recording doctor history: %v\n", err)
 	}
+	if !doctorNotify {
+		return
+	}
+	regressions := doctor.Regressions(history, run)
+	if len(regressions) == 0 {
+		return
+	}
+	if err := notifyDoctorRegressions(townRoot, run, regressions); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: notifying regressions: %v\n", err)
+		return
+	}
+	fmt.Printf("%s Reported %d regressed check(s)\n", style.Bold.Render("!"), len(regressions))
+}
 
-	// Print summary (checks were already printed during streaming)
-	report.PrintSummaryOnly(os.Stdout, doctorVerbose, slowThreshold)
-
-	// Exit with error code if there are errors
-	if report.HasErrors() {
-		return fmt.Errorf("doctor found %d error(s)", report.Summary.Errors)
+// notifyDoctorRegressions escalates regressed errors and mails the mayor
+// about regressed warnings.
+func notifyDoctorRegressions(townRoot string, run *doctor.RunRecord, regressions []doctor.CheckRecord) error {
+	scope := "town"
+	if run.Rig != "" {
+		scope = "rig " + run.Rig
+	}
+	// The daemon's PATH may not include gt; use this binary
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = "gt"
+	}
+	var warnings []string
+	for _, c := range regressions {
+		detail := c.Message
+		if len(c.Details) > 0 {
+			detail += "\n" + strings.Join(c.Details, "\n")
+		}
+		if c.Status != doctor.StatusError.String() {
+			warnings = append(warnings, fmt.Sprintf("- %s: %s", c.Name, detail))
+			continue
+		}
+		escCmd := exec.Command(gtPath, "escalate", fmt.Sprintf("Doctor check failing: %s (%s)", c.Name, scope),
+			"--severity", "high",
+			"--reason", detail+"\nSee 'gt doctor history "+c.Name+"'.",
+			"--source", "doctor:"+run.Source) //nolint:gosec // G204: args are constructed internally
+		escCmd.Dir = townRoot
+		escCmd.Stdout = os.Stdout
+		escCmd.Stderr = os.Stderr
+		if err := escCmd.Run(); err != nil {
+			return fmt.Errorf("escalating %s: %w", c.Name, err)
+		}
+	}
+	if len(warnings) == 0 {
+		return nil
 	}
 
-	return nil
+	router := mail.NewRouter(townRoot)
+	return router.Send(&mail.Message{
+		From:      "deacon/",
+		To:        "mayor/",
+		Subject:   fmt.Sprintf("Doctor: %d check(s) now warning (%s)", len(warnings), scope),
+		Body:      strings.Join(warnings, "\n") + "\n\nSee 'gt doctor history'.",
+		Timestamp: time.Now(),
+	})
 }
-
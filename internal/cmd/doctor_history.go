@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doctorHistorySince string
+	doctorHistoryRig   string
+	doctorHistoryAll   bool
+	doctorHistoryFixes bool
+	doctorHistoryJSON  bool
+)
+
+var doctorHistoryCmd = &cobra.Command{
+	Use:   "history [check]",
+	Short: "Show health check history",
+	Long: `Show how doctor checks have fared over time.
+
+Every gt doctor run is recorded, including the scheduled quick runs made
+by the daemon. For each check this shows its latest status, when its
+current failure started, how many runs failed, and how often it flapped
+between passing and failing.
+
+By default only checks that are failing now or failed during the window
+are listed; --all lists every check. Naming a check lists its runs.
+
+Examples:
+  gt doctor history                    # Problem checks, last 7 days
+  gt doctor history --since 30d --all  # Every check, last 30 days
+  gt doctor history routes-config      # Each run of one check
+  gt doctor history --fixes            # Fixes applied by --fix`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDoctorHistory,
+}
+
+func init() {
+	doctorHistoryCmd.Flags().StringVar(&doctorHistorySince, "since", "7d", "Time window (e.g. 24h, 7d)")
+	doctorHistoryCmd.Flags().StringVar(&doctorHistoryRig, "rig", "", "Only runs for this rig (--rig runs)")
+	doctorHistoryCmd.Flags().BoolVar(&doctorHistoryAll, "all", false, "Include checks that always passed")
+	doctorHistoryCmd.Flags().BoolVar(&doctorHistoryFixes, "fixes", false, "List fixes applied by --fix")
+	doctorHistoryCmd.Flags().BoolVar(&doctorHistoryJSON, "json", false, "Output as JSON")
+	doctorCmd.AddCommand(doctorHistoryCmd)
+}
+
+func runDoctorHistory(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	window, err := parseDuration(doctorHistorySince)
+	if err != nil {
+		return fmt.Errorf("invalid --since %q: %w", doctorHistorySince, err)
+	}
+	runs, err := doctor.LoadHistory(townRoot, time.Now().Add(-window))
+	if err != nil {
+		return err
+	}
+	runs = filterDoctorRuns(runs, doctorHistoryRig)
+
+	switch {
+	case doctorHistoryFixes:
+		return printDoctorFixes(doctor.Fixes(runs))
+	case len(args) == 1:
+		return printDoctorCheckRuns(runs, args[0])
+	}
+
+	var trends []*doctor.CheckTrend
+	for _, t := range doctor.Trends(runs) {
+		if doctorHistoryAll || t.Failures > 0 {
+			trends = append(trends, t)
+		}
+	}
+	if doctorHistoryJSON {
+		return printJSONIndent(trends)
+	}
+	if len(runs) == 0 {
+		fmt.Printf("No doctor runs recorded in the last %s.\n", doctorHistorySince)
+		return nil
+	}
+	fmt.Printf("%s %s\n\n", style.Bold.Render("Doctor History"),
+		style.Dim.Render(fmt.Sprintf("(%d runs since %s)", len(runs), runs[0].Timestamp.Local().Format("2006-01-02 15:04"))))
+	if len(trends) == 0 {
+		fmt.Println("All checks passed in every run.")
+		return nil
+	}
+	for _, t := range trends {
+		name := t.Name
+		if t.Rig != "" {
+			name = t.Rig + ":" + t.Name
+		}
+		state := style.Success.Render("✓ passing")
+		if t.Failing() {
+			state = style.Error.Render("✗ " + t.Status)
+			if t.FailingSince != nil {
+				state += " since " + t.FailingSince.Local().Format("2006-01-02 15:04")
+			}
+		}
+		fmt.Printf("  %-32s %s\n", name, state)
+		fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("failed %d/%d runs, %d flap(s)", t.Failures, t.Runs, t.Flaps)))
+		if t.Failing() && t.Message != "" {
+			fmt.Printf("    %s\n", t.Message)
+		}
+	}
+	return nil
+}
+
+// filterDoctorRuns keeps the runs of rig, or the town-wide runs if rig is
+// empty.
+func filterDoctorRuns(runs []*doctor.RunRecord, rig string) []*doctor.RunRecord {
+	var kept []*doctor.RunRecord
+	for _, run := range runs {
+		if run.Rig == rig {
+			kept = append(kept, run)
+		}
+	}
+	return kept
+}
+
+func printDoctorCheckRuns(runs []*doctor.RunRecord, name string) error {
+	type checkRun struct {
+		Timestamp time.Time `json:"ts"`
+		Source    string    `json:"source"`
+		doctor.CheckRecord
+	}
+	var found []checkRun
+	for _, run := range runs {
+		for _, c := range run.Checks {
+			if c.Name == name {
+				found = append(found, checkRun{Timestamp: run.Timestamp, Source: run.Source, CheckRecord: c})
+			}
+		}
+	}
+	if doctorHistoryJSON {
+		return printJSONIndent(found)
+	}
+	if len(found) == 0 {
+		fmt.Printf("No runs of %s in the last %s.\n", name, doctorHistorySince)
+		return nil
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render("History of "+name))
+	for _, r := range found {
+		mark := style.Success.Render("✓")
+		if !r.OK() {
+			mark = style.Error.Render("✗")
+		}
+		fmt.Printf("  %s %s %s %s\n", r.Timestamp.Local().Format("2006-01-02 15:04"), mark,
+			r.Message, style.Dim.Render("("+r.Source+")"))
+		if r.Fix != nil {
+			fmt.Printf("      %s\n", style.Dim.Render(formatDoctorFix(r.Fix)))
+		}
+	}
+	return nil
+}
+
+func printDoctorFixes(fixes []doctor.FixEntry) error {
+	if doctorHistoryJSON {
+		return printJSONIndent(fixes)
+	}
+	if len(fixes) == 0 {
+		fmt.Printf("No fixes applied in the last %s.\n", doctorHistorySince)
+		return nil
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render("Doctor Fixes"))
+	for _, f := range fixes {
+		fmt.Printf("  %s %-28s %s\n", f.Timestamp.Local().Format("2006-01-02 15:04"), f.Check, formatDoctorFix(&f.FixRecord))
+	}
+	return nil
+}
+
+func formatDoctorFix(f *doctor.FixRecord) string {
+	switch {
+	case f.Error != "":
+		return fmt.Sprintf("fix failed (%s): %s", f.Problem, f.Error)
+	case f.Fixed:
+		return "fixed: " + f.Problem
+	default:
+		return "fix applied, still failing: " + f.Problem
+	}
+}
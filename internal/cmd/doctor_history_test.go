@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/doctor"
+)
+
+func TestFilterDoctorRuns(t *testing.T) {
+	runs := []*doctor.RunRecord{{Rig: ""}, {Rig: "gastown"}, {Rig: ""}, {Rig: "beads"}}
+	if got := filterDoctorRuns(runs, ""); len(got) != 2 {
+		t.Errorf("town runs = %d, want 2", len(got))
+	}
+	if got := filterDoctorRuns(runs, "gastown"); len(got) != 1 || got[0].Rig != "gastown" {
+		t.Errorf("gastown runs = %+v", got)
+	}
+}
+
+func TestFormatDoctorFix(t *testing.T) {
+	tests := []struct {
+		fix  doctor.FixRecord
+		want string
+	}{
+		{doctor.FixRecord{Problem: "missing", Fixed: true}, "fixed: missing"},
+		{doctor.FixRecord{Problem: "missing", Error: "denied"}, "fix failed (missing): denied"},
+		{doctor.FixRecord{Problem: "missing"}, "fix applied, still failing: missing"},
+	}
+	for _, tt := range tests {
+		if got := formatDoctorFix(&tt.fix); got != tt.want {
+			t.Errorf("formatDoctorFix(%+v) = %q, want %q", tt.fix, got, tt.want)
+		}
+	}
+}
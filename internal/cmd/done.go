@@ -15,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -585,7 +586,7 @@ notifyWitness:
 	townRouter := mail.NewRouter(townRoot)
 	witnessAddr := fmt.Sprintf("%s/witness", rigName)
 
-	// Build notification body
+	// Build the WORK_DONE notification body
 	var bodyLines []string
 	bodyLines = append(bodyLines, fmt.Sprintf("Exit: %s", exitType))
 	if issueID != "" {
@@ -602,15 +603,18 @@ notifyWitness:
 		bodyLines = append(bodyLines, fmt.Sprintf("Errors: %s", strings.Join(doneErrors, "; ")))
 	}
 
-	doneNotification := &mail.Message{
-		To:      witnessAddr,
-		From:    sender,
-		Subject: fmt.Sprintf("POLECAT_DONE %s", polecatName),
-		Body:    strings.Join(bodyLines, "\n"),
-	}
+	doneNotification := protocol.NewMessage(sender, witnessAddr, &protocol.PolecatDonePayload{
+		Polecat: polecatName,
+		Exit:    exitType,
+		Issue:   issueID,
+		MR:      mrID,
+		Branch:  branch,
+		Gate:    doneGate,
+		Errors:  doneErrors,
+	})
 
 	fmt.Printf("\nNotifying Witness...\n")
-	if err := townRouter.Send(doneNotification); err != nil {
+	if err := protocol.Send(townRouter, doneNotification); err != nil {
 		style.PrintWarning("could not notify witness: %v", err)
 	} else {
 		fmt.Printf("%s Witness notified of %s\n", style.Bold.Render("✓"), exitType)
@@ -339,7 +339,7 @@ func stopAllPolecats(t *tmux.Tmux, townRoot string, rigNames []string, force boo
 			continue
 		}
 
-		polecatMgr := polecat.NewSessionManager(t, r)
+		polecatMgr := polecat.NewSessionManager(session.DefaultBackend(), r)
 		infos, err := polecatMgr.ListPolecats()
 		if err != nil {
 			continue
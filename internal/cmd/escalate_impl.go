@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -13,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/notify"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -127,8 +130,16 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
+	// Process external notification actions (email:, sms:, slack, log)
+	executeExternalActions(actions, escalationConfig, townRoot, bd, &notify.Notification{
+		EscalationID: issue.ID,
+		Severity:     severity,
+		Subject:      fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description),
+		Body:         formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
+		From:         agentID,
+		Source:       escalateSource,
+		Time:         time.Now(),
+	}, escalateJSON)
 
 	// Log to activity feed
 	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
@@ -392,6 +403,16 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 				}
 			}
 
+			// Notify humans on the new severity's external channels
+			executeExternalActions(actions, escalationConfig, townRoot, bd, &notify.Notification{
+				EscalationID: result.ID,
+				Severity:     result.NewSeverity,
+				Subject:      fmt.Sprintf("[%s→%s] Re-escalated: %s", strings.ToUpper(result.OldSeverity), strings.ToUpper(result.NewSeverity), result.Title),
+				Body:         formatReescalationMailBody(result, reescalatedBy),
+				From:         reescalatedBy,
+				Time:         time.Now(),
+			}, escalateStaleJSON)
+
 			// Log to activity feed
 			_ = events.LogFeed(events.TypeEscalationSent, reescalatedBy, map[string]interface{}{
 				"escalation_id":    result.ID,
@@ -503,6 +524,7 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 			"closedBy":    fields.ClosedBy,
 			"closedReason": fields.ClosedReason,
 			"relatedBead": fields.RelatedBead,
+			"notifications": fields.Notifications,
 		}
 		out, _ := json.MarshalIndent(data, "", "  ")
 		fmt.Println(string(out))
@@ -529,6 +551,9 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 	if fields.RelatedBead != "" {
 		fmt.Printf("  Related: %s\n", fields.RelatedBead)
 	}
+	if fields.Notifications != "" {
+		fmt.Printf("  Notifications: %s\n", fields.Notifications)
+	}
 
 	return nil
 }
@@ -551,40 +576,76 @@ func extractMailTargetsFromActions(actions []string) []string {
 	return targets
 }
 
-// executeExternalActions processes external notification actions (email:, sms:, slack).
-// For now, this logs warnings if contacts aren't configured - actual sending is future work.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) {
+// executeExternalActions delivers external notification actions (email:, sms:,
+// slack, log) in parallel, retrying per the configured policy, and records each
+// channel's delivery status on the escalation bead (unless bd is nil).
+// Progress is printed unless quiet.
+func executeExternalActions(actions []string, cfg *config.EscalationConfig, townRoot string, bd *beads.Beads, n *notify.Notification, quiet bool) []notify.Result {
+	var notifiers []notify.Notifier
+	statuses := make(map[string]string)
 	for _, action := range actions {
-		switch {
-		case strings.HasPrefix(action, "email:"):
-			if cfg.Contacts.HumanEmail == "" {
-				style.PrintWarning("email action '%s' skipped: contacts.human_email not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual email sending
-				fmt.Printf("  📧 Would send email to %s (not yet implemented)\n", cfg.Contacts.HumanEmail)
+		notifier, err := notify.ForAction(action, cfg, townRoot)
+		if err != nil {
+			statuses[action] = notify.StatusSkipped
+			if !quiet {
+				style.PrintWarning("%s action skipped: %v in settings/escalation.json", action, err)
 			}
+			continue
+		}
+		if notifier != nil {
+			notifiers = append(notifiers, notifier)
+		}
+	}
 
-		case strings.HasPrefix(action, "sms:"):
-			if cfg.Contacts.HumanSMS == "" {
-				style.PrintWarning("sms action '%s' skipped: contacts.human_sms not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual SMS sending
-				fmt.Printf("  📱 Would send SMS to %s (not yet implemented)\n", cfg.Contacts.HumanSMS)
-			}
+	results := make([]notify.Result, len(notifiers))
+	if len(notifiers) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
 
-		case action == "slack":
-			if cfg.Contacts.SlackWebhook == "" {
-				style.PrintWarning("slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
-			} else {
-				// TODO: Implement actual Slack webhook posting
-				fmt.Printf("  💬 Would post to Slack (not yet implemented)\n")
-			}
+		policy := notify.PolicyFromConfig(cfg)
+		var wg sync.WaitGroup
+		for i, notifier := range notifiers {
+			wg.Add(1)
+			go func(i int, notifier notify.Notifier) {
+				defer wg.Done()
+				results[i] = notify.Deliver(ctx, notifier, n, policy)
+			}(i, notifier)
+		}
+		wg.Wait()
+	}
 
-		case action == "log":
-			// Log action always succeeds - writes to escalation log file
-			// TODO: Implement actual log file writing
-			fmt.Printf("  📝 Logged to escalation log\n")
+	for _, r := range results {
+		statuses[r.Channel] = r.Status
+		if quiet {
+			continue
 		}
+		if r.Status == notify.StatusDelivered {
+			fmt.Printf("  %s %s\n", channelEmoji(r.Channel), r.Channel)
+		} else {
+			style.PrintWarning("%s delivery failed after %d attempt(s): %v", r.Channel, r.Attempts, r.Err)
+		}
+	}
+
+	if bd == nil {
+		return results
+	}
+	if err := bd.RecordEscalationDeliveries(n.EscalationID, statuses); err != nil && !quiet {
+		style.PrintWarning("failed to record delivery status on %s: %v", n.EscalationID, err)
+	}
+	return results
+}
+
+// channelEmoji returns the emoji shown for a delivered notification channel.
+func channelEmoji(channel string) string {
+	switch {
+	case strings.HasPrefix(channel, "email:"):
+		return "📧"
+	case strings.HasPrefix(channel, "sms:"):
+		return "📱"
+	case channel == "slack":
+		return "💬"
+	default:
+		return "📝"
 	}
 }
 
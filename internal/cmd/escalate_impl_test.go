@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,6 +11,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/notify"
 )
 
 // TestExtractMailTargetsFromActions tests extraction of mail targets from action strings.
@@ -383,88 +386,94 @@ func TestDetectSenderFallback(t *testing.T) {
 
 // TestExecuteExternalActions tests external notification dispatch logic.
 func TestExecuteExternalActions(t *testing.T) {
-	t.Run("email action with no contact configured", func(t *testing.T) {
-		cfg := &config.EscalationConfig{
-			Contacts: config.EscalationContacts{
-				HumanEmail: "",
-			},
-		}
-		// Should not panic - just prints a warning
-		executeExternalActions([]string{"email:human"}, cfg, "gt-test", "high", "test desc")
-	})
+	n := &notify.Notification{EscalationID: "gt-test", Severity: "high", Subject: "[HIGH] test desc", Body: "test body"}
 
-	t.Run("email action with contact configured", func(t *testing.T) {
-		cfg := &config.EscalationConfig{
-			Contacts: config.EscalationContacts{
-				HumanEmail: "user@example.com",
-			},
+	statusOf := func(results []notify.Result, channel string) string {
+		for _, r := range results {
+			if r.Channel == channel {
+				return r.Status
+			}
 		}
-		// Should not panic - prints "would send" message
-		executeExternalActions([]string{"email:human"}, cfg, "gt-test", "high", "test desc")
-	})
+		return ""
+	}
 
-	t.Run("sms action with no contact configured", func(t *testing.T) {
-		cfg := &config.EscalationConfig{
-			Contacts: config.EscalationContacts{
-				HumanSMS: "",
-			},
+	t.Run("unconfigured contacts are skipped", func(t *testing.T) {
+		cfg := &config.EscalationConfig{}
+		results := executeExternalActions([]string{"email:human", "sms:human", "slack"}, cfg, t.TempDir(), nil, n, true)
+		if len(results) != 0 {
+			t.Errorf("expected no deliveries without contacts, got %+v", results)
 		}
-		executeExternalActions([]string{"sms:human"}, cfg, "gt-test", "high", "test desc")
 	})
 
-	t.Run("sms action with contact configured", func(t *testing.T) {
+	t.Run("email contact without smtp is skipped", func(t *testing.T) {
 		cfg := &config.EscalationConfig{
-			Contacts: config.EscalationContacts{
-				HumanSMS: "+1234567890",
-			},
+			Contacts: config.EscalationContacts{HumanEmail: "user@example.com"},
 		}
-		executeExternalActions([]string{"sms:human"}, cfg, "gt-test", "high", "test desc")
-	})
-
-	t.Run("slack action with no webhook configured", func(t *testing.T) {
-		cfg := &config.EscalationConfig{
-			Contacts: config.EscalationContacts{
-				SlackWebhook: "",
-			},
+		results := executeExternalActions([]string{"email:human"}, cfg, t.TempDir(), nil, n, true)
+		if len(results) != 0 {
+			t.Errorf("expected email to be skipped without delivery.smtp, got %+v", results)
 		}
-		executeExternalActions([]string{"slack"}, cfg, "gt-test", "high", "test desc")
 	})
 
-	t.Run("slack action with webhook configured", func(t *testing.T) {
+	t.Run("slack and log delivered", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		townRoot := t.TempDir()
 		cfg := &config.EscalationConfig{
-			Contacts: config.EscalationContacts{
-				SlackWebhook: "https://hooks.slack.com/test",
-			},
+			Contacts: config.EscalationContacts{SlackWebhook: srv.URL},
+		}
+		actions := []string{"bead", "mail:mayor", "slack", "log"}
+		results := executeExternalActions(actions, cfg, townRoot, nil, n, true)
+		if len(results) != 2 {
+			t.Fatalf("expected 2 deliveries (mail: and bead are not external), got %d", len(results))
+		}
+		if got := statusOf(results, "slack"); got != notify.StatusDelivered {
+			t.Errorf("slack status = %q, want delivered", got)
+		}
+		if got := statusOf(results, "log"); got != notify.StatusDelivered {
+			t.Errorf("log status = %q, want delivered", got)
+		}
+		if _, err := os.Stat(filepath.Join(townRoot, notify.DefaultLogPath)); err != nil {
+			t.Errorf("escalation log not written: %v", err)
 		}
-		executeExternalActions([]string{"slack"}, cfg, "gt-test", "high", "test desc")
 	})
 
-	t.Run("log action", func(t *testing.T) {
-		cfg := &config.EscalationConfig{}
-		executeExternalActions([]string{"log"}, cfg, "gt-test", "high", "test desc")
-	})
+	t.Run("failing webhook is retried then reported", func(t *testing.T) {
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer srv.Close()
 
-	t.Run("mixed actions", func(t *testing.T) {
 		cfg := &config.EscalationConfig{
-			Contacts: config.EscalationContacts{
-				HumanEmail:   "user@example.com",
-				HumanSMS:     "+1234567890",
-				SlackWebhook: "https://hooks.slack.com/test",
-			},
+			Contacts: config.EscalationContacts{SlackWebhook: srv.URL},
+			Delivery: config.EscalationDelivery{MaxAttempts: 2, InitialBackoff: "1ms"},
+		}
+		results := executeExternalActions([]string{"slack"}, cfg, t.TempDir(), nil, n, true)
+		if got := statusOf(results, "slack"); got != notify.StatusFailed {
+			t.Errorf("slack status = %q, want failed", got)
+		}
+		if calls != 2 {
+			t.Errorf("webhook called %d times, want 2", calls)
 		}
-		actions := []string{"email:human", "sms:human", "slack", "log", "mail:mayor"}
-		// mail: actions are not external - should be skipped by executeExternalActions
-		executeExternalActions(actions, cfg, "gt-test", "critical", "test desc")
 	})
 
 	t.Run("empty actions", func(t *testing.T) {
 		cfg := &config.EscalationConfig{}
-		executeExternalActions([]string{}, cfg, "gt-test", "high", "test desc")
+		if results := executeExternalActions([]string{}, cfg, t.TempDir(), nil, n, true); len(results) != 0 {
+			t.Errorf("expected no results, got %+v", results)
+		}
 	})
 
 	t.Run("unknown action types are ignored", func(t *testing.T) {
 		cfg := &config.EscalationConfig{}
-		executeExternalActions([]string{"bead", "unknown:thing"}, cfg, "gt-test", "high", "test desc")
+		if results := executeExternalActions([]string{"bead", "unknown:thing"}, cfg, t.TempDir(), nil, n, true); len(results) != 0 {
+			t.Errorf("expected no results, got %+v", results)
+		}
 	})
 }
 
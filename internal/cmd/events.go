@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	eventsTypes  []string
+	eventsActor  string
+	eventsRig    string
+	eventsSince  string
+	eventsUntil  string
+	eventsWhere  []string
+	eventsSelect string
+	eventsLimit  int
+	eventsFollow bool
+	eventsJSON   bool
+)
+
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	GroupID: GroupDiag,
+	Short:   "Query the event log",
+	Long: `Query the town's event log (~/gt/.events.jsonl).
+
+Every built-in event type has a versioned payload schema; payloads are
+validated when events are written. Use 'gt events schema' to list them.`,
+	RunE: requireSubcommand,
+}
+
+var eventsQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Filter and project events",
+	Long: `Filter events by type, actor, rig, time and payload fields, and print
+them or selected fields of them.
+
+Time flags take RFC3339, "2006-01-02 15:04", "15:04", or a duration ago
+(2h, 7d). --since seeks the log by timestamp, so recent windows are fast
+even on a large log.
+
+--actor matches exactly, by prefix when it ends in "/", or as a glob.
+--where and --select take field paths, jq style: .type, .actor,
+.payload.bead, .payload.agents[0].
+
+Examples:
+  gt events query --type sling --since 2h
+  gt events query --rig gastown --type done,merged --since 1d
+  gt events query --actor 'gastown/polecats/*' --select .ts,.type,.payload.bead
+  gt events query --where .payload.bead=gt-abc --json
+  gt events query --type session_death --follow`,
+	RunE: runEventsQuery,
+}
+
+var eventsSchemaCmd = &cobra.Command{
+	Use:   "schema [type]",
+	Short: "List event payload schemas",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runEventsSchema,
+}
+
+func init() {
+	eventsQueryCmd.Flags().StringSliceVarP(&eventsTypes, "type", "t", nil, "Event type(s), repeatable or comma-separated")
+	eventsQueryCmd.Flags().StringVar(&eventsActor, "actor", "", "Actor: exact, prefix ending in /, or glob")
+	eventsQueryCmd.Flags().StringVar(&eventsRig, "rig", "", "Rig the event is about")
+	eventsQueryCmd.Flags().StringVar(&eventsSince, "since", "", "Events at or after this time")
+	eventsQueryCmd.Flags().StringVar(&eventsUntil, "until", "", "Events before this time")
+	eventsQueryCmd.Flags().StringArrayVarP(&eventsWhere, "where", "w", nil, "Field condition path=value (repeatable)")
+	eventsQueryCmd.Flags().StringVarP(&eventsSelect, "select", "s", "", "Comma-separated field paths to print")
+	eventsQueryCmd.Flags().IntVarP(&eventsLimit, "limit", "n", 0, "Only the last N matching events")
+	eventsQueryCmd.Flags().BoolVarP(&eventsFollow, "follow", "f", false, "Keep printing new events as they are written")
+	eventsQueryCmd.Flags().BoolVar(&eventsJSON, "json", false, "Output JSON lines")
+
+	eventsSchemaCmd.Flags().BoolVar(&eventsJSON, "json", false, "Output as JSON")
+
+	eventsCmd.AddCommand(eventsQueryCmd)
+	eventsCmd.AddCommand(eventsSchemaCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func runEventsQuery(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	filter, err := eventsFilter(time.Now())
+	if err != nil {
+		return err
+	}
+	var paths []string
+	if eventsSelect != "" {
+		paths = strings.Split(eventsSelect, ",")
+	}
+	logPath := filepath.Join(townRoot, events.EventsFile)
+	emit := func(e events.Event) error {
+		return printQueryEvent(e, paths)
+	}
+
+	if eventsFollow {
+		// Skip all but the last --limit existing matches, then stream
+		skip := 0
+		if eventsLimit > 0 {
+			n := 0
+			if err := events.Query(logPath, filter, func(events.Event) error { n++; return nil }); err != nil {
+				return err
+			}
+			skip = n - eventsLimit
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return events.Follow(ctx, logPath, filter, func(e events.Event) error {
+			if skip > 0 {
+				skip--
+				return nil
+			}
+			return emit(e)
+		})
+	}
+
+	if eventsLimit <= 0 {
+		return events.Query(logPath, filter, emit)
+	}
+	var last []events.Event
+	if err := events.Query(logPath, filter, func(e events.Event) error {
+		last = append(last, e)
+		if len(last) > eventsLimit {
+			last = last[1:]
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, e := range last {
+		if err := emit(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eventsFilter builds the query filter from the flags.
+func eventsFilter(now time.Time) (events.Filter, error) {
+	f := events.Filter{Types: eventsTypes, Actor: eventsActor, Rig: eventsRig}
+	var err error
+	if eventsSince != "" {
+		if f.Since, err = parseReplayTime(eventsSince, now); err != nil {
+			return f, fmt.Errorf("--since: %w", err)
+		}
+	}
+	if eventsUntil != "" {
+		if f.Until, err = parseReplayTime(eventsUntil, now); err != nil {
+			return f, fmt.Errorf("--until: %w", err)
+		}
+	}
+	for _, w := range eventsWhere {
+		path, value, ok := strings.Cut(w, "=")
+		if !ok || path == "" {
+			return f, fmt.Errorf("invalid --where %q: use path=value", w)
+		}
+		if f.Where == nil {
+			f.Where = make(map[string]string)
+		}
+		f.Where[path] = value
+	}
+	return f, nil
+}
+
+// printQueryEvent prints one event: the selected fields, tab-separated (or as a
+// JSON object keyed by path), or else the whole event.
+func printQueryEvent(e events.Event, paths []string) error {
+	if len(paths) > 0 {
+		values := e.Project(paths)
+		if eventsJSON {
+			obj := make(map[string]interface{}, len(paths))
+			for i, p := range paths {
+				obj[p] = values[i]
+			}
+			return printJSONLine(obj)
+		}
+		cols := make([]string, len(values))
+		for i, v := range values {
+			cols[i] = events.FormatField(v)
+		}
+		fmt.Println(strings.Join(cols, "\t"))
+		return nil
+	}
+	if eventsJSON {
+		return printJSONLine(e)
+	}
+
+	ts := e.Timestamp
+	if t, ok := e.Time(); ok {
+		ts = t.Local().Format("2006-01-02 15:04:05")
+	}
+	keys := make([]string, 0, len(e.Payload))
+	for k := range e.Payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]string, len(keys))
+	for i, k := range keys {
+		fields[i] = k + "=" + events.FormatField(e.Payload[k])
+	}
+	fmt.Printf("%s  %-18s %-28s %s\n", style.Dim.Render(ts), e.Type, e.Actor, strings.Join(fields, " "))
+	return nil
+}
+
+func printJSONLine(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func runEventsSchema(cmd *cobra.Command, args []string) error {
+	schemas := events.Schemas()
+	if len(args) == 1 {
+		s, ok := events.LookupSchema(args[0])
+		if !ok {
+			return fmt.Errorf("no schema for event type %q (custom types are not validated)", args[0])
+		}
+		schemas = []*events.Schema{s}
+	}
+
+	if eventsJSON {
+		type schemaJSON struct {
+			Type     string   `json:"type"`
+			Version  int      `json:"version"`
+			Fields   []string `json:"fields"`
+			Required []string `json:"required,omitempty"`
+		}
+		out := make([]schemaJSON, len(schemas))
+		for i, s := range schemas {
+			out[i] = schemaJSON{Type: s.Type, Version: s.Version, Fields: s.Fields(), Required: s.Required()}
+		}
+		return printJSONIndent(out)
+	}
+	for _, s := range schemas {
+		required := make(map[string]bool)
+		for _, name := range s.Required() {
+			required[name] = true
+		}
+		fields := s.Fields()
+		for i, name := range fields {
+			if required[name] {
+				fields[i] = name + "*"
+			}
+		}
+		fmt.Printf("%-22s v%d  %s\n", style.Bold.Render(s.Type), s.Version, strings.Join(fields, ", "))
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("* required"))
+	return nil
+}
@@ -5,10 +5,12 @@ import (
 	"bytes"
 	"crypto/rand"
 	"encoding/base32"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -46,32 +48,42 @@ and composition rules. They can be "poured" to create molecules or "wisped"
 for ephemeral patrol cycles.
 
 Commands:
-  list    List available formulas from all search paths
+  list    List available formulas from all tiers
   show    Display formula details (steps, variables, composition)
   run     Execute a formula (pour and dispatch)
   create  Create a new formula template
 
-Search paths (in order):
-  1. .beads/formulas/ (project)
-  2. ~/.beads/formulas/ (user)
-  3. $GT_ROOT/.beads/formulas/ (orchestrator)
+Registry (Mol Mall):
+  search     Search the formula registry
+  install    Install formulas into the town tier (pinning, checksums)
+  update     Update registry-installed formulas
+  uninstall  Remove registry-installed formulas
+  publish    Package a formula into a static registry directory
+
+Resolution order (most specific wins):
+  1. project  nearest .beads/formulas/ above the current directory
+  2. town     <town>/.beads/formulas/
+  3. user     ~/.beads/formulas/
+  4. system   embedded in the gt binary
+
+Each tier may hold .formula.toml or bd's .formula.json files (TOML wins).
 
 Examples:
   gt formula list                    # List all formulas
   gt formula show shiny              # Show formula details
   gt formula run shiny --pr=123      # Run formula on PR #123
-  gt formula create my-workflow      # Create new formula template`,
+  gt formula create my-workflow      # Create new formula template
+  gt formula install mol-review@1    # Install from the registry, pinned to 1.x`,
 }
 
 var formulaListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available formulas",
-	Long: `List available formulas from all search paths.
+	Long: `List available formulas from all tiers.
 
-Searches for formula files (.formula.toml, .formula.json) in:
-  1. .beads/formulas/ (project)
-  2. ~/.beads/formulas/ (user)
-  3. $GT_ROOT/.beads/formulas/ (orchestrator)
+Each formula is shown with the tier that wins (project, town, user or system)
+and any lower tiers it shadows. Local copies that are older than the
+embedded version are flagged.
 
 Examples:
   gt formula list            # List all formulas
@@ -85,6 +97,7 @@ var formulaShowCmd = &cobra.Command{
 	Long: `Display detailed information about a formula.
 
 Shows:
+  - Which file won resolution and which copies it shadows
   - Formula metadata (name, type, description)
   - Variables with defaults and constraints
   - Steps with dependencies
@@ -172,31 +185,185 @@ func init() {
 	rootCmd.AddCommand(formulaCmd)
 }
 
-// runFormulaList delegates to bd formula list
+// runFormulaList lists formulas from all tiers with their resolution.
 func runFormulaList(cmd *cobra.Command, args []string) error {
-	bdArgs := []string{"formula", "list"}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	resolutions, err := formula.NewResolver(cwd).List()
+	if err != nil {
+		return fmt.Errorf("listing formulas: %w", err)
+	}
+
+	type listEntry struct {
+		*formula.Resolution
+		Type        formula.FormulaType `json:"type,omitempty"`
+		Version     int                 `json:"version,omitempty"`
+		Description string              `json:"description,omitempty"`
+	}
+	entries := make([]listEntry, 0, len(resolutions))
+	for _, res := range resolutions {
+		entry := listEntry{Resolution: res}
+		if f, err := res.Load(); err == nil {
+			entry.Type = f.Type
+			entry.Version = f.Version
+			entry.Description = f.Description
+		}
+		entries = append(entries, entry)
+	}
+
 	if formulaListJSON {
-		bdArgs = append(bdArgs, "--json")
+		out, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
 	}
 
-	bdCmd := exec.Command("bd", bdArgs...)
-	bdCmd.Stdout = os.Stdout
-	bdCmd.Stderr = os.Stderr
-	return bdCmd.Run()
+	fmt.Printf("%s (%d)\n\n", style.Bold.Render("Formulas"), len(entries))
+	for _, e := range entries {
+		version := ""
+		if e.Version > 0 {
+			version = fmt.Sprintf("v%d", e.Version)
+		}
+		line := strings.TrimRight(fmt.Sprintf("  %-32s %-4s %-10s", e.Name, version, "["+string(e.Winner.Tier)+"]"), " ")
+		if len(e.Shadowed) > 0 {
+			var tiers []string
+			for _, c := range e.Shadowed {
+				tiers = append(tiers, string(c.Tier))
+			}
+			line += style.Dim.Render(" shadows " + strings.Join(tiers, ", "))
+		}
+		fmt.Println(line)
+		for _, w := range e.Warnings {
+			fmt.Printf("    %s %s\n", style.Warning.Render("⚠"), w)
+		}
+	}
+	return nil
 }
 
-// runFormulaShow delegates to bd formula show
+// runFormulaShow displays a formula along with how it was resolved.
 func runFormulaShow(cmd *cobra.Command, args []string) error {
-	formulaName := args[0]
-	bdArgs := []string{"formula", "show", formulaName}
+	res, err := resolveFormulaFromCwd(args[0])
+	if err != nil {
+		return err
+	}
+	f, loadErr := res.Load()
+
 	if formulaShowJSON {
-		bdArgs = append(bdArgs, "--json")
+		out, err := json.MarshalIndent(struct {
+			*formula.Resolution
+			Formula *formula.Formula `json:"formula,omitempty"`
+		}{res, f}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return loadErr
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("Formula:"), res.Name)
+	printFormulaResolution(res)
+	if loadErr != nil {
+		return fmt.Errorf("parsing %s: %w", res.Name, loadErr)
+	}
+
+	fmt.Println()
+	fmt.Printf("  Type:    %s\n", f.Type)
+	if f.Version > 0 {
+		fmt.Printf("  Version: %d\n", f.Version)
+	}
+	if f.Description != "" {
+		fmt.Printf("\n%s\n", strings.TrimSpace(f.Description))
+	}
+
+	if len(f.Vars) > 0 || len(f.Inputs) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Variables:"))
+		for _, name := range sortedKeys(f.Vars) {
+			v := f.Vars[name]
+			fmt.Printf("  %s%s  %s\n", name, varSuffix(v.Required, v.Default), style.Dim.Render(v.Description))
+		}
+		for _, name := range sortedKeys(f.Inputs) {
+			in := f.Inputs[name]
+			fmt.Printf("  %s%s  %s\n", name, varSuffix(in.Required, in.Default), style.Dim.Render(in.Description))
+		}
+	}
+
+	switch {
+	case len(f.Steps) > 0:
+		fmt.Printf("\n%s\n", style.Bold.Render("Steps:"))
+		for _, step := range f.Steps {
+			fmt.Printf("  • %s: %s", step.ID, step.Title)
+			if len(step.Needs) > 0 {
+				fmt.Printf(" %s", style.Dim.Render("(needs "+strings.Join(step.Needs, ", ")+")"))
+			}
+			fmt.Println()
+		}
+	case len(f.Legs) > 0:
+		fmt.Printf("\n%s\n", style.Bold.Render("Legs:"))
+		for _, leg := range f.Legs {
+			fmt.Printf("  • %s: %s\n", leg.ID, leg.Title)
+		}
+		if f.Synthesis != nil {
+			fmt.Printf("  → synthesis: %s\n", f.Synthesis.Title)
+		}
+	case len(f.Aspects) > 0:
+		fmt.Printf("\n%s\n", style.Bold.Render("Aspects:"))
+		for _, a := range f.Aspects {
+			fmt.Printf("  • %s: %s\n", a.ID, a.Title)
+		}
+	case len(f.Template) > 0:
+		fmt.Printf("\n%s\n", style.Bold.Render("Template:"))
+		for _, tmpl := range f.Template {
+			fmt.Printf("  • %s: %s\n", tmpl.ID, tmpl.Title)
+		}
+	}
+	return nil
+}
+
+// varSuffix renders the required/default marker for a formula variable.
+func varSuffix(required bool, def string) string {
+	switch {
+	case required:
+		return " (required)"
+	case def != "":
+		return fmt.Sprintf(" (default: %s)", def)
+	default:
+		return ""
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resolveFormulaFromCwd resolves a formula name using the project → town →
+// user → system tiers as seen from the current directory.
+func resolveFormulaFromCwd(name string) (*formula.Resolution, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting current directory: %w", err)
 	}
+	return formula.ResolveFormula(name, cwd)
+}
 
-	bdCmd := exec.Command("bd", bdArgs...)
-	bdCmd.Stdout = os.Stdout
-	bdCmd.Stderr = os.Stderr
-	return bdCmd.Run()
+// printFormulaResolution shows which copy of a formula won and what it shadows.
+func printFormulaResolution(res *formula.Resolution) {
+	fmt.Printf("  Resolved: %s %s\n", style.Bold.Render("["+string(res.Winner.Tier)+"]"), res.Winner.Path)
+	for _, c := range res.Shadowed {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("Shadows:  [%s] %s", c.Tier, c.Path)))
+	}
+	for _, w := range res.Warnings {
+		style.PrintWarning("%s", w)
+	}
 }
 
 // runFormulaRun executes a formula by spawning a convoy of polecats.
@@ -249,14 +416,15 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s Using default formula: %s\n", style.Dim.Render("Note:"), formulaName)
 	}
 
-	// Find the formula file
-	formulaPath, err := findFormulaFile(formulaName)
+	// Resolve the formula (project → town → user → system)
+	res, err := resolveFormulaFromCwd(formulaName)
 	if err != nil {
 		return fmt.Errorf("finding formula: %w", err)
 	}
+	printFormulaResolution(res)
 
 	// Parse the formula
-	f, err := parseFormulaFile(formulaPath)
+	f, err := res.Load()
 	if err != nil {
 		return fmt.Errorf("parsing formula: %w", err)
 	}
@@ -629,45 +797,6 @@ func executeConvoyFormula(f *formula.Formula, formulaName, targetRig string) err
 	return nil
 }
 
-// findFormulaFile searches for a formula file by name
-func findFormulaFile(name string) (string, error) {
-	// Search paths in order
-	searchPaths := []string{}
-
-	// 1. Project .beads/formulas/
-	if cwd, err := os.Getwd(); err == nil {
-		searchPaths = append(searchPaths, filepath.Join(cwd, ".beads", "formulas"))
-	}
-
-	// 2. Town .beads/formulas/
-	if townRoot, err := workspace.FindFromCwd(); err == nil {
-		searchPaths = append(searchPaths, filepath.Join(townRoot, ".beads", "formulas"))
-	}
-
-	// 3. User ~/.beads/formulas/
-	if home, err := os.UserHomeDir(); err == nil {
-		searchPaths = append(searchPaths, filepath.Join(home, ".beads", "formulas"))
-	}
-
-	// Try each path with common extensions
-	extensions := []string{".formula.toml", ".formula.json"}
-	for _, basePath := range searchPaths {
-		for _, ext := range extensions {
-			path := filepath.Join(basePath, name+ext)
-			if _, err := os.Stat(path); err == nil {
-				return path, nil
-			}
-		}
-	}
-
-	return "", fmt.Errorf("formula '%s' not found in search paths", name)
-}
-
-// parseFormulaFile parses a formula file using the formula package's TOML parser.
-func parseFormulaFile(path string) (*formula.Formula, error) {
-	return formula.ParseFile(path)
-}
-
 // renderTemplate renders a Go text/template with the given context map
 func renderTemplate(tmplText string, ctx map[string]interface{}) (string, error) {
 	tmpl, err := template.New("prompt").Parse(tmplText)
@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Mol Mall registry flags
+var (
+	formulaRegistry      string
+	formulaSearchCaps    []string
+	formulaSearchJSON    bool
+	formulaForce         bool
+	formulaUnpin         bool
+	formulaPublishTo     string
+	formulaPublishVer    string
+	formulaPublishLog    string
+	formulaPublishAuthor string
+	formulaPublishCaps   []string
+)
+
+var formulaSearchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search the formula registry",
+	Long: `Search the Mol Mall registry for formulas whose name or description
+matches the query.
+
+Examples:
+  gt formula search review
+  gt formula search --capability security --capability go
+  gt formula search --registry ./my-registry`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFormulaSearch,
+}
+
+var formulaInstallCmd = &cobra.Command{
+	Use:   "install <name>[@version]...",
+	Short: "Install formulas from the registry",
+	Long: `Install formulas from the Mol Mall registry into the town tier
+(<town>/.beads/formulas/).
+
+Each download is verified against the registry's checksum before it is
+written. Installed versions and checksums are recorded in
+.beads/formulas/.installed.json. A version pins the formula: @4.1.0 exactly,
+@4 to the newest 4.x. Without a version the formula tracks the latest.
+
+An existing formula is only replaced if it was installed from a registry
+and has not been edited since; use --force to replace it anyway.
+
+Examples:
+  gt formula install mol-code-review
+  gt formula install mol-polecat-work@4.0.0
+  gt formula install mol-deploy@2 --registry https://molmall.acme.corp`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runFormulaInstall,
+}
+
+var formulaUpdateCmd = &cobra.Command{
+	Use:   "update [name[@version]]...",
+	Short: "Update registry-installed formulas",
+	Long: `Update formulas installed from the registry to the newest version their
+pin allows. With no names, every installed formula is updated.
+
+A version repins the formula; --unpin makes it track the latest again.
+
+Examples:
+  gt formula update                        # Update everything within its pin
+  gt formula update mol-polecat-work@4.1   # Repin to 4.1.x
+  gt formula update mol-polecat-work --unpin`,
+	RunE: runFormulaUpdate,
+}
+
+var formulaUninstallCmd = &cobra.Command{
+	Use:   "uninstall <name>...",
+	Short: "Remove registry-installed formulas",
+	Long: `Remove formulas installed from the registry and their lockfile entries.
+Formulas edited since they were installed are kept unless --force is given.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runFormulaUninstall,
+}
+
+var formulaPublishCmd = &cobra.Command{
+	Use:   "publish <name>",
+	Short: "Package a formula into a registry directory",
+	Long: `Package a formula version into a static registry directory.
+
+The formula is resolved like any other (project, town, system), validated,
+and stored with its checksum under --to. The directory is a complete
+registry: serve it with any static file host, or point --registry at it.
+Published versions are immutable.
+
+Examples:
+  gt formula publish mol-code-review --version 1.0.0 --to ./registry
+  gt formula publish mol-deploy --version 2.1.0 --to ./registry \
+      --changelog "Add canary step" --capability k8s --capability deploy`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFormulaPublish,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{formulaSearchCmd, formulaInstallCmd, formulaUpdateCmd} {
+		c.Flags().StringVar(&formulaRegistry, "registry", "", "Registry URL or directory (default: town formula_registry, $GT_FORMULA_REGISTRY, or "+formula.DefaultRegistry+")")
+	}
+	for _, c := range []*cobra.Command{formulaInstallCmd, formulaUpdateCmd, formulaUninstallCmd} {
+		c.Flags().BoolVarP(&formulaForce, "force", "f", false, "Replace formulas that were edited or not installed from a registry")
+	}
+	formulaSearchCmd.Flags().StringSliceVarP(&formulaSearchCaps, "capability", "c", nil, "Only formulas declaring this capability (repeatable)")
+	formulaSearchCmd.Flags().BoolVar(&formulaSearchJSON, "json", false, "Output as JSON")
+	formulaUpdateCmd.Flags().BoolVar(&formulaUnpin, "unpin", false, "Track the latest version again")
+
+	formulaPublishCmd.Flags().StringVar(&formulaPublishTo, "to", "", "Registry directory to publish into (required)")
+	formulaPublishCmd.Flags().StringVar(&formulaPublishVer, "version", "", "Version to publish, MAJOR.MINOR.PATCH (required)")
+	formulaPublishCmd.Flags().StringVar(&formulaPublishLog, "changelog", "", "What changed in this version")
+	formulaPublishCmd.Flags().StringVar(&formulaPublishAuthor, "author", "", "Author shown in the registry")
+	formulaPublishCmd.Flags().StringSliceVarP(&formulaPublishCaps, "capability", "c", nil, "Capability tag (repeatable)")
+	_ = formulaPublishCmd.MarkFlagRequired("to")
+	_ = formulaPublishCmd.MarkFlagRequired("version")
+
+	formulaCmd.AddCommand(formulaSearchCmd)
+	formulaCmd.AddCommand(formulaInstallCmd)
+	formulaCmd.AddCommand(formulaUpdateCmd)
+	formulaCmd.AddCommand(formulaUninstallCmd)
+	formulaCmd.AddCommand(formulaPublishCmd)
+}
+
+// openFormulaRegistry returns the registry to use: --registry, then
+// $GT_FORMULA_REGISTRY, then the town's formula_registry setting.
+func openFormulaRegistry(townRoot string) *formula.Registry {
+	url := formulaRegistry
+	if url == "" {
+		url = os.Getenv("GT_FORMULA_REGISTRY")
+	}
+	if url == "" && townRoot != "" {
+		if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+			url = settings.FormulaRegistry
+		}
+	}
+	if url == "" {
+		url = formula.DefaultRegistry
+	}
+	return formula.NewRegistry(url)
+}
+
+// townFormulasDir returns the town tier directory registry installs go to.
+func townFormulasDir() (string, string, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return townRoot, filepath.Join(townRoot, ".beads", "formulas"), nil
+}
+
+func runFormulaSearch(cmd *cobra.Command, args []string) error {
+	townRoot, _ := workspace.FindFromCwd()
+	reg := openFormulaRegistry(townRoot)
+	idx, err := reg.Index()
+	if err != nil {
+		return err
+	}
+	query := ""
+	if len(args) == 1 {
+		query = args[0]
+	}
+	results := idx.Search(query, formulaSearchCaps)
+
+	if formulaSearchJSON {
+		return printJSONIndent(results)
+	}
+	if len(results) == 0 {
+		fmt.Printf("%s No formulas in %s match\n", style.Dim.Render("○"), reg.URL)
+		return nil
+	}
+	fmt.Printf("%s (%d in %s)\n\n", style.Bold.Render("Formulas"), len(results), reg.URL)
+	for _, r := range results {
+		fmt.Printf("  %-32s %s\n", r.Name, style.Dim.Render("v"+r.Latest))
+		if r.Description != "" {
+			fmt.Printf("    %s\n", strings.SplitN(r.Description, "\n", 2)[0])
+		}
+		if len(r.Capabilities) > 0 {
+			fmt.Printf("    %s\n", style.Dim.Render("Capabilities: "+strings.Join(r.Capabilities, ", ")))
+		}
+	}
+	return nil
+}
+
+func runFormulaInstall(cmd *cobra.Command, args []string) error {
+	townRoot, dir, err := townFormulasDir()
+	if err != nil {
+		return err
+	}
+	reg := openFormulaRegistry(townRoot)
+	for _, arg := range args {
+		ref, err := formula.ParseRef(arg)
+		if err != nil {
+			return err
+		}
+		res, err := formula.Install(dir, reg, ref, formulaForce)
+		if err != nil {
+			return fmt.Errorf("installing %s: %w", ref, err)
+		}
+		pin := "latest"
+		if ref.Version != "" {
+			pin = "pinned @" + ref.Version
+		}
+		fmt.Printf("%s Installed %s@%s %s\n", style.Success.Render("✓"), res.Name, res.Version, style.Dim.Render("("+pin+", checksum verified)"))
+		fmt.Printf("    %s\n", style.Dim.Render(res.Path))
+	}
+	return nil
+}
+
+func runFormulaUpdate(cmd *cobra.Command, args []string) error {
+	townRoot, dir, err := townFormulasDir()
+	if err != nil {
+		return err
+	}
+	if formulaUnpin && len(args) == 0 {
+		return fmt.Errorf("--unpin needs formula names")
+	}
+	reg := openFormulaRegistry(townRoot)
+
+	// Each argument may repin its formula, so update them one at a time
+	type target struct {
+		names      []string
+		constraint *string
+	}
+	targets := []target{{}}
+	if len(args) > 0 {
+		targets = targets[:0]
+		for _, arg := range args {
+			ref, err := formula.ParseRef(arg)
+			if err != nil {
+				return err
+			}
+			t := target{names: []string{ref.Name}}
+			if ref.Version != "" || formulaUnpin {
+				v := ref.Version
+				t.constraint = &v
+			}
+			targets = append(targets, t)
+		}
+	}
+
+	updated := 0
+	for _, t := range targets {
+		results, err := formula.Update(dir, reg, t.names, t.constraint, formulaForce)
+		for _, res := range results {
+			fmt.Printf("%s Updated %s %s → %s\n", style.Success.Render("✓"), res.Name, res.Previous, res.Version)
+		}
+		updated += len(results)
+		if err != nil {
+			return err
+		}
+	}
+	if updated == 0 {
+		fmt.Println(style.Dim.Render("All registry formulas are up to date"))
+	}
+	return nil
+}
+
+func runFormulaUninstall(cmd *cobra.Command, args []string) error {
+	_, dir, err := townFormulasDir()
+	if err != nil {
+		return err
+	}
+	for _, name := range args {
+		if err := formula.Uninstall(dir, strings.TrimSuffix(name, formula.FormulaExt), formulaForce); err != nil {
+			return err
+		}
+		fmt.Printf("%s Uninstalled %s\n", style.Success.Render("✓"), name)
+	}
+	return nil
+}
+
+func runFormulaPublish(cmd *cobra.Command, args []string) error {
+	if strings.HasPrefix(formulaPublishTo, "http://") || strings.HasPrefix(formulaPublishTo, "https://") {
+		return fmt.Errorf("--to must be a local registry directory; upload it to your registry host afterwards")
+	}
+	res, err := resolveFormulaFromCwd(args[0])
+	if err != nil {
+		return err
+	}
+	content, err := res.Read()
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", res.Name, err)
+	}
+	dir := strings.TrimPrefix(formulaPublishTo, "file://")
+	name, err := formula.Publish(dir, content, formula.PublishOptions{
+		Version:      strings.TrimPrefix(formulaPublishVer, "v"),
+		Author:       formulaPublishAuthor,
+		Changelog:    formulaPublishLog,
+		Capabilities: formulaPublishCaps,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Published %s@%s to %s\n", style.Success.Render("✓"), name, strings.TrimPrefix(formulaPublishVer, "v"), dir)
+	return nil
+}
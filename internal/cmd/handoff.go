@@ -12,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/hooklog"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -747,6 +748,13 @@ func sendHandoffMail(subject, message string) (string, error) {
 		style.PrintWarning("created mail %s but failed to auto-hook: %v", beadID, err)
 		return beadID, nil
 	}
+	recordHookActivity(hooklog.Entry{
+		Action:  hooklog.ActionAttach,
+		Command: "gt handoff",
+		Agent:   agentID,
+		Bead:    beadID,
+		Reason:  "handoff mail",
+	})
 
 	return beadID, nil
 }
@@ -814,6 +822,13 @@ func hookBeadForHandoff(beadID string) error {
 	}
 
 	fmt.Printf("%s Work attached to hook (pinned bead)\n", style.Bold.Render("✓"))
+	recordHookActivity(hooklog.Entry{
+		Action:  hooklog.ActionAttach,
+		Command: "gt handoff",
+		Agent:   agentID,
+		Bead:    beadID,
+		Reason:  "handoff",
+	})
 	return nil
 }
 
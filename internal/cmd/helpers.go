@@ -11,7 +11,9 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/headless"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -76,7 +78,12 @@ func isInTmuxSession(targetSession string) bool {
 
 // attachToTmuxSession attaches to a tmux session.
 // If already inside tmux, uses switch-client instead of attach-session.
+// With the headless backend, attaches over the session host's socket.
 func attachToTmuxSession(sessionID string) error {
+	if session.BackendName() == session.BackendHeadless {
+		return headless.New().Attach(sessionID, os.Stdin, os.Stdout)
+	}
+
 	tmuxPath, err := exec.LookPath("tmux")
 	if err != nil {
 		return fmt.Errorf("tmux not found: %w", err)
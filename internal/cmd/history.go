@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	historyRig  string
+	historyAsOf string
+	historyFrom string
+	historyTo   string
+	historyJSON bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:     "history [bead-id]",
+	GroupID: GroupDiag,
+	Short:   "Show how beads changed over time (Dolt time travel)",
+	Long: `Show beads history from the Dolt server for post-incident review.
+
+Every bd write is a Dolt commit, so nothing is lost: closed, compacted and
+deleted beads are all still in the history.
+
+Modes:
+  gt history <bead-id>              Every field change to a bead, oldest first
+  gt history --as-of <time>         The ready queue, hooked work and open
+                                    convoys as they were at that moment
+  gt history --from <t1> [--to <t2>]
+                                    Every bead change between two moments
+
+The rig database is taken from the bead's prefix, or --rig (default: the
+town database, hq). Times are RFC3339, "2006-01-02 15:04", "15:04" or a
+duration ago such as 2h.
+
+Examples:
+  gt history gt-abc12
+  gt history --as-of "2026-06-01 14:30" --rig gastown
+  gt history --from 3h --to 1h --rig gastown --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHistory,
+}
+
+func init() {
+	historyCmd.Flags().StringVar(&historyRig, "rig", "", "Rig database to read (default: from bead prefix, else hq)")
+	historyCmd.Flags().StringVar(&historyAsOf, "as-of", "", "Show the rig's work as it was at this time")
+	historyCmd.Flags().StringVar(&historyFrom, "from", "", "Show changes after this time")
+	historyCmd.Flags().StringVar(&historyTo, "to", "", "Show changes up to this time (default: now)")
+	historyCmd.Flags().BoolVar(&historyJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	modes := 0
+	for _, set := range []bool{len(args) == 1, historyAsOf != "", historyFrom != ""} {
+		if set {
+			modes++
+		}
+	}
+	if modes != 1 {
+		return fmt.Errorf("give exactly one of: a bead ID, --as-of, or --from")
+	}
+	if historyTo != "" && historyFrom == "" {
+		return fmt.Errorf("--to needs --from")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	now := time.Now()
+
+	switch {
+	case len(args) == 1:
+		bead := args[0]
+		rigDB := historyRig
+		if rigDB == "" {
+			rigDB = historyDatabase(townRoot, bead)
+		}
+		revs, err := doltserver.BeadHistory(townRoot, rigDB, bead)
+		if err != nil {
+			return err
+		}
+		if historyJSON {
+			return printJSONIndent(revs)
+		}
+		if len(revs) == 0 {
+			fmt.Printf("%s No history for %s in %s\n", style.Dim.Render("○"), bead, rigDB)
+			return nil
+		}
+		fmt.Printf("%s %s (%d changes)\n\n", style.Bold.Render("History of"), bead, len(revs))
+		printRevisions(revs, false)
+
+	case historyAsOf != "":
+		at, err := parseReplayTime(historyAsOf, now)
+		if err != nil {
+			return fmt.Errorf("--as-of: %w", err)
+		}
+		snap, err := doltserver.SnapshotAt(townRoot, historyRigOrTown(), at)
+		if err != nil {
+			return err
+		}
+		if historyJSON {
+			return printJSONIndent(snap)
+		}
+		printSnapshot(snap)
+
+	default:
+		from, err := parseReplayTime(historyFrom, now)
+		if err != nil {
+			return fmt.Errorf("--from: %w", err)
+		}
+		to := now
+		if historyTo != "" {
+			if to, err = parseReplayTime(historyTo, now); err != nil {
+				return fmt.Errorf("--to: %w", err)
+			}
+		}
+		rigDB := historyRigOrTown()
+		revs, err := doltserver.HistoryBetween(townRoot, rigDB, from, to)
+		if err != nil {
+			return err
+		}
+		if historyJSON {
+			return printJSONIndent(revs)
+		}
+		fmt.Printf("%s %s, %s → %s (%d changes)\n\n", style.Bold.Render("Changes in"), rigDB,
+			from.Local().Format("2006-01-02 15:04"), to.Local().Format("2006-01-02 15:04"), len(revs))
+		printRevisions(revs, true)
+	}
+	return nil
+}
+
+// historyDatabase returns the rig database holding bead, by its prefix.
+func historyDatabase(townRoot, bead string) string {
+	if rig := beads.GetRigNameForPrefix(townRoot, beads.ExtractPrefix(bead)); rig != "" {
+		return rig
+	}
+	return doltserver.TownDatabase
+}
+
+func historyRigOrTown() string {
+	if historyRig != "" {
+		return historyRig
+	}
+	return doltserver.TownDatabase
+}
+
+func printRevisions(revs []doltserver.Revision, showBead bool) {
+	for _, r := range revs {
+		commit := r.Commit
+		if len(commit) > 8 {
+			commit = commit[:8]
+		}
+		line := fmt.Sprintf("%s  %-8s %s", style.Dim.Render(r.At.Local().Format("2006-01-02 15:04:05")), r.Kind, commit)
+		if showBead {
+			line += "  " + r.Bead
+		}
+		if r.Committer != "" {
+			line += "  " + style.Dim.Render(r.Committer)
+		}
+		fmt.Println(line)
+		for _, f := range r.Fields {
+			switch {
+			case r.Kind == "added":
+				fmt.Printf("    %s: %s\n", f.Field, historyValue(f.New))
+			case r.Kind == "removed":
+				fmt.Printf("    %s: %s\n", f.Field, style.Dim.Render(historyValue(f.Old)))
+			default:
+				fmt.Printf("    %s: %s → %s\n", f.Field, style.Dim.Render(historyValue(f.Old)), historyValue(f.New))
+			}
+		}
+	}
+}
+
+// historyValue shortens a field value to one line.
+func historyValue(v string) string {
+	if v == "" {
+		return "(empty)"
+	}
+	v = strings.Join(strings.Fields(v), " ")
+	if len(v) > 72 {
+		v = v[:69] + "..."
+	}
+	return v
+}
+
+func printSnapshot(snap *doltserver.Snapshot) {
+	fmt.Printf("%s %s as of %s\n", style.Bold.Render("Snapshot of"), snap.Rig, snap.At.Local().Format("2006-01-02 15:04:05"))
+	for _, section := range []struct {
+		name   string
+		issues []doltserver.SnapshotIssue
+	}{
+		{"Ready", snap.Ready},
+		{"Hooked", snap.Hooked},
+		{"Convoys", snap.Convoys},
+	} {
+		fmt.Printf("\n%s (%d)\n", style.Bold.Render(section.name), len(section.issues))
+		if len(section.issues) == 0 {
+			fmt.Printf("  %s\n", style.Dim.Render("none"))
+		}
+		for _, i := range section.issues {
+			extra := i.Assignee
+			if i.Tracked > 0 {
+				extra = fmt.Sprintf("%d tracked", i.Tracked)
+			}
+			fmt.Printf("  P%d %-14s %-44s %s\n", i.Priority, i.ID, historyValue(i.Title), style.Dim.Render(extra))
+		}
+	}
+}
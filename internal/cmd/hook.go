@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/hooklog"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -199,8 +200,10 @@ func runHook(_ *cobra.Command, args []string) error {
 	}
 
 	// If there's an existing hooked bead, check if we can auto-replace
+	var previousBead, hookReason string
 	if len(existingPinned) > 0 {
 		existing := existingPinned[0]
+		previousBead = existing.ID
 
 		// Skip if it's the same bead we're trying to pin
 		if existing.ID == beadID {
@@ -213,6 +216,7 @@ func runHook(_ *cobra.Command, args []string) error {
 
 		if isComplete {
 			// Auto-replace completed bead
+			hookReason = "replaced completed bead"
 			fmt.Printf("%s Replacing completed bead %s...\n", style.Dim.Render("ℹ"), existing.ID)
 			if !hookDryRun {
 				if hasAttachment {
@@ -237,6 +241,7 @@ func runHook(_ *cobra.Command, args []string) error {
 			}
 		} else if hookForce {
 			// Force replace incomplete bead
+			hookReason = "force-replaced incomplete bead"
 			fmt.Printf("%s Force-replacing incomplete bead %s...\n", style.Dim.Render("⚠"), existing.ID)
 			if !hookDryRun {
 				// Unpin by setting status back to open
@@ -311,6 +316,14 @@ func runHook(_ *cobra.Command, args []string) error {
 	if err := events.LogFeed(events.TypeHook, agentID, events.HookPayload(beadID)); err != nil {
 		fmt.Fprintf(os.Stderr, "%s Warning: failed to log hook event: %v\n", style.Dim.Render("⚠"), err)
 	}
+	recordHookActivity(hooklog.Entry{
+		Action:   hooklog.ActionAttach,
+		Command:  "gt hook",
+		Agent:    agentID,
+		Bead:     beadID,
+		Previous: previousBead,
+		Reason:   hookReason,
+	})
 
 	return nil
 }
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/hooklog"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/transcript"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// recordHookActivity appends an entry to the town's hook activity log.
+// Best-effort: hook changes never fail because the log could not be written.
+// Actor defaults to the current agent; outside a town this is a no-op.
+func recordHookActivity(entry hooklog.Entry) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	if entry.Actor == "" {
+		entry.Actor = detectActor()
+	}
+	entry.Actor = strings.TrimSuffix(entry.Actor, "/")
+	entry.Agent = strings.TrimSuffix(entry.Agent, "/")
+
+	if err := hooklog.New(townRoot).Append(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Warning: failed to record hook activity: %v\n", style.Dim.Render("⚠"), err)
+	}
+
+	// Index the agent's transcript by bead so replays can find the work
+	if entry.Action == hooklog.ActionAttach && entry.Bead != "" && entry.Agent != "" {
+		_ = transcript.IndexBead(townRoot, entry.Bead, entry.Agent)
+	}
+}
@@ -294,7 +294,7 @@ func runKrcPrune(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("pruning: %w", err)
 	}
 
-	if result.EventsPruned == 0 {
+	if result.EventsPruned == 0 && result.TranscriptsPruned == 0 {
 		fmt.Println("No expired events to prune.")
 		return nil
 	}
@@ -304,6 +304,9 @@ func runKrcPrune(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Events pruned:    %d\n", result.EventsPruned)
 	fmt.Printf("  Events retained:  %d\n", result.EventsRetained)
 	fmt.Printf("  Space saved:      %s\n", formatBytes(result.BytesBefore-result.BytesAfter))
+	if result.TranscriptsPruned > 0 {
+		fmt.Printf("  Transcripts:      %d removed (%s)\n", result.TranscriptsPruned, formatBytes(result.TranscriptBytesFreed))
+	}
 	fmt.Printf("  Duration:         %s\n", result.Duration.Round(time.Millisecond))
 
 	if len(result.PrunedByType) > 0 {
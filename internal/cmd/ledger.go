@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/ledger"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	ledgerActor    string
+	ledgerTriggers []string
+	ledgerSince    string
+	ledgerLimit    int
+	ledgerOutput   string
+	ledgerJSON     bool
+	ledgerCommit   string
+)
+
+var ledgerCmd = &cobra.Command{
+	Use:     "ledger",
+	GroupID: GroupDiag,
+	Short:   "Inspect the permanent record of completed work",
+	Long: `Inspect the town's completion ledger (~/gt/ledger/completions.jsonl).
+
+A compressed completion record is appended whenever work crosses a
+completion boundary:
+
+  mr_merged          the refinery merged a polecat's work
+  convoy_landed      every bead a convoy tracks closed
+  molecule_squashed  a molecule was squashed into a digest
+
+Each record holds who did the work, the bead, formula, duration, cost,
+diff stats and outcome. Records are never changed; the ledger is
+hash-chained so any edit is detected by 'gt ledger verify'. Polecat CVs
+(gt polecat identity show) are built from it.`,
+	RunE: requireSubcommand,
+}
+
+var ledgerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List completion records",
+	Long: `List completion records, oldest first.
+
+Examples:
+  gt ledger list --since 7d
+  gt ledger list --actor gastown/polecats/Toast
+  gt ledger list --trigger convoy_landed --json`,
+	Args: cobra.NoArgs,
+	RunE: runLedgerList,
+}
+
+var ledgerVerifyCmd = &cobra.Command{
+	Use:   "verify [file]",
+	Short: "Verify the ledger's hash chain",
+	Long: `Verify that every ledger entry matches its hash and links to the entry
+before it. With a file, verify an exported ledger instead; only a complete
+(unfiltered) export forms a chain.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLedgerVerify,
+}
+
+var ledgerExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export ledger entries as JSONL",
+	Long: `Write ledger entries as JSONL, exactly as stored, with their hashes.
+
+The ledger is verified first; a broken chain is not exported. An export
+without filters can itself be checked with 'gt ledger verify <file>'.
+
+Examples:
+  gt ledger export -o ledger.jsonl
+  gt ledger export --actor gastown/polecats/Toast --since 30d`,
+	Args: cobra.NoArgs,
+	RunE: runLedgerExport,
+}
+
+var ledgerRecordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Record a completion by hand",
+	RunE:  requireSubcommand,
+}
+
+var ledgerRecordMRCmd = &cobra.Command{
+	Use:   "mr <mr-bead-id>",
+	Short: "Record a merged MR",
+	Long: `Record the merge of an MR's source issue (mr_merged), as the refinery
+patrol does after pushing a merge. Run it from the refinery's clone: the
+diff stats come from the merge commit (default: the MR's merge_commit,
+else HEAD). Recording the same merge twice is a no-op.
+
+Examples:
+  gt ledger record mr gt-mr-abc
+  gt ledger record mr gt-mr-abc --commit 1a2b3c4`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLedgerRecordMR,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{ledgerListCmd, ledgerExportCmd} {
+		c.Flags().StringVar(&ledgerActor, "actor", "", "Records involving this agent")
+		c.Flags().StringSliceVarP(&ledgerTriggers, "trigger", "t", nil, "Trigger(s): mr_merged, convoy_landed, molecule_squashed")
+		c.Flags().StringVar(&ledgerSince, "since", "", "Records completed at or after this time (RFC3339, date, or duration ago)")
+	}
+	ledgerListCmd.Flags().IntVarP(&ledgerLimit, "limit", "n", 0, "Only the last N records")
+	ledgerListCmd.Flags().BoolVar(&ledgerJSON, "json", false, "Output JSON lines")
+	ledgerExportCmd.Flags().StringVarP(&ledgerOutput, "output", "o", "", "Write to file instead of stdout")
+	ledgerRecordMRCmd.Flags().StringVar(&ledgerCommit, "commit", "", "Merge commit (default: the MR's merge_commit, else HEAD)")
+
+	ledgerRecordCmd.AddCommand(ledgerRecordMRCmd)
+
+	ledgerCmd.AddCommand(ledgerListCmd)
+	ledgerCmd.AddCommand(ledgerVerifyCmd)
+	ledgerCmd.AddCommand(ledgerExportCmd)
+	ledgerCmd.AddCommand(ledgerRecordCmd)
+	rootCmd.AddCommand(ledgerCmd)
+}
+
+// ledgerMatch builds a record filter from the flags.
+func ledgerMatch(now time.Time) (func(ledger.Record) bool, error) {
+	var since time.Time
+	if ledgerSince != "" {
+		t, err := parseReplayTime(ledgerSince, now)
+		if err != nil {
+			return nil, fmt.Errorf("--since: %w", err)
+		}
+		since = t
+	}
+	return func(r ledger.Record) bool {
+		if ledgerActor != "" && !r.Involves(ledgerActor) {
+			return false
+		}
+		if len(ledgerTriggers) > 0 && !slices.Contains(ledgerTriggers, string(r.Trigger)) {
+			return false
+		}
+		return since.IsZero() || !r.Completed.Before(since)
+	}, nil
+}
+
+func runLedgerList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	match, err := ledgerMatch(time.Now())
+	if err != nil {
+		return err
+	}
+	records, err := ledger.Records(ledger.Path(townRoot))
+	if err != nil {
+		return err
+	}
+	var shown []ledger.Record
+	for _, r := range records {
+		if match(r) {
+			shown = append(shown, r)
+		}
+	}
+	if ledgerLimit > 0 && len(shown) > ledgerLimit {
+		shown = shown[len(shown)-ledgerLimit:]
+	}
+
+	if ledgerJSON {
+		for _, r := range shown {
+			if err := printJSONLine(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(shown) == 0 {
+		fmt.Println(style.Dim.Render("No completion records"))
+		return nil
+	}
+	for _, r := range shown {
+		who := r.Actor
+		if who == "" {
+			who = strings.Join(r.Agents, ",")
+		}
+		details := []string{r.Outcome}
+		if r.Formula != "" {
+			details = append(details, r.Formula)
+		}
+		if d := r.Duration(); d > 0 {
+			details = append(details, d.Round(time.Minute).String())
+		}
+		if r.CostUSD > 0 {
+			details = append(details, fmt.Sprintf("$%.2f", r.CostUSD))
+		}
+		if r.Diff != nil {
+			details = append(details, fmt.Sprintf("+%d -%d in %d files", r.Diff.Added, r.Diff.Removed, r.Diff.Changed))
+		}
+		fmt.Printf("%s  %-17s %-10s %-28s %s\n", style.Dim.Render(r.Completed.Local().Format("2006-01-02 15:04")),
+			r.Trigger, r.Bead, who, strings.Join(details, ", "))
+		if r.Title != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(r.Title))
+		}
+	}
+	return nil
+}
+
+func runLedgerVerify(cmd *cobra.Command, args []string) error {
+	path := ""
+	if len(args) == 1 {
+		path = args[0]
+	} else {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		path = ledger.Path(townRoot)
+	}
+	n, err := ledger.VerifyFile(path)
+	if err != nil {
+		return fmt.Errorf("ledger verification failed after %d good entries: %w", n, err)
+	}
+	fmt.Printf("%s Ledger intact: %d entries\n", style.Success.Render("✓"), n)
+	return nil
+}
+
+func runLedgerExport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	match, err := ledgerMatch(time.Now())
+	if err != nil {
+		return err
+	}
+	path := ledger.Path(townRoot)
+	if _, err := ledger.VerifyFile(path); err != nil {
+		return fmt.Errorf("not exporting a broken ledger: %w", err)
+	}
+	entries, err := ledger.Read(path)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if ledgerOutput != "" {
+		f, err := os.Create(ledgerOutput)
+		if err != nil {
+			return fmt.Errorf("creating export: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	n := 0
+	for i := range entries {
+		r, err := entries[i].Decode()
+		if err != nil {
+			return err
+		}
+		if !match(r) {
+			continue
+		}
+		data, err := json.Marshal(entries[i])
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(out, string(data)); err != nil {
+			return err
+		}
+		n++
+	}
+	if ledgerOutput != "" {
+		fmt.Printf("%s Exported %d entries to %s\n", style.Success.Render("✓"), n, ledgerOutput)
+	}
+	return nil
+}
+
+func runLedgerRecordMR(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+
+	b := beads.New(cwd)
+	mr, err := b.Show(args[0])
+	if err != nil {
+		return fmt.Errorf("showing MR %s: %w", args[0], err)
+	}
+	fields := beads.ParseMRFields(mr)
+	if fields == nil || fields.SourceIssue == "" {
+		return fmt.Errorf("%s has no source_issue; is it a merge request?", args[0])
+	}
+	commit := ledgerCommit
+	if commit == "" {
+		commit = fields.MergeCommit
+	}
+	if commit == "" {
+		commit = "HEAD"
+	}
+	if commit, err = git.NewGit(cwd).Rev(commit); err != nil {
+		return fmt.Errorf("resolving merge commit: %w", err)
+	}
+	rigName := fields.Rig
+	if rigName == "" {
+		if rel, err := filepath.Rel(townRoot, cwd); err == nil && !strings.HasPrefix(rel, "..") {
+			rigName = strings.Split(filepath.ToSlash(rel), "/")[0]
+		}
+	}
+
+	rec := refinery.MergeRecord(b, townRoot, cwd, rigName, fields.SourceIssue, fields.Worker, fields.ConvoyID, commit)
+	e, added, err := ledger.Append(townRoot, rec)
+	if err != nil {
+		return err
+	}
+	if !added {
+		fmt.Printf("%s %s already recorded (entry %d)\n", style.Dim.Render("○"), fields.SourceIssue, e.Seq)
+		return nil
+	}
+	fmt.Printf("%s Recorded %s merged at %s (entry %d)\n", style.Success.Render("✓"), fields.SourceIssue, commit[:8], e.Seq)
+	return nil
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Machine command flags
+var (
+	machineJSON       bool
+	machineKeyPath    string
+	machineKnownHosts string
+	machineTownPath   string
+)
+
+var machineCmd = &cobra.Command{
+	Use:     "machine",
+	GroupID: GroupConfig,
+	Short:   "Manage remote machines for running agents",
+	RunE:    requireSubcommand,
+	Long: `Manage the machines Gas Town can run agents on.
+
+Machines are stored in mayor/machines.json. The "local" machine always
+exists. Remote machines are reached over SSH and need sh, coreutils and
+tmux installed.
+
+Commands:
+  gt machine list                         List registered machines
+  gt machine add <name> <user@host[:port]> Register an SSH machine
+  gt machine remove <name>                Remove a machine
+  gt machine test <name>                  Check connectivity and tooling`,
+}
+
+var machineListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered machines",
+	Long: `List all registered machines.
+
+Examples:
+  gt machine list
+  gt machine list --json`,
+	Args: cobra.NoArgs,
+	RunE: runMachineList,
+}
+
+var machineAddCmd = &cobra.Command{
+	Use:   "add <name> <user@host[:port]>",
+	Short: "Register an SSH machine",
+	Long: `Register a remote machine reachable over SSH.
+
+Authentication uses --key if given, otherwise the running SSH agent.
+Host keys are verified against ~/.ssh/known_hosts (or --known-hosts), so
+connect once with plain ssh first to record the key.
+
+Examples:
+  gt machine add bigbox gt@bigbox.internal
+  gt machine add bigbox gt@10.0.0.5:2222 --key ~/.ssh/gt_ed25519 --town-path /home/gt/gt`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMachineAdd,
+}
+
+var machineRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a machine",
+	Long: `Remove a machine from the registry.
+
+The "local" machine cannot be removed.
+
+Examples:
+  gt machine remove bigbox`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMachineRemove,
+}
+
+var machineTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Check connectivity and tooling on a machine",
+	Long: `Connect to a machine and verify it can host Gas Town agents.
+
+Checks that the connection works, that tmux and git are installed, and
+that the configured town path exists.
+
+Examples:
+  gt machine test bigbox`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMachineTest,
+}
+
+func init() {
+	machineListCmd.Flags().BoolVar(&machineJSON, "json", false, "Output as JSON")
+
+	machineAddCmd.Flags().StringVar(&machineKeyPath, "key", "", "SSH private key path (default: use ssh-agent)")
+	machineAddCmd.Flags().StringVar(&machineKnownHosts, "known-hosts", "", "known_hosts file (default: ~/.ssh/known_hosts)")
+	machineAddCmd.Flags().StringVar(&machineTownPath, "town-path", "", "Path to the town root on the remote machine")
+
+	machineCmd.AddCommand(machineListCmd)
+	machineCmd.AddCommand(machineAddCmd)
+	machineCmd.AddCommand(machineRemoveCmd)
+	machineCmd.AddCommand(machineTestCmd)
+
+	rootCmd.AddCommand(machineCmd)
+}
+
+// loadMachineRegistry opens the town's machine registry.
+func loadMachineRegistry() (*connection.MachineRegistry, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return connection.NewMachineRegistry(constants.MayorMachinesPath(townRoot))
+}
+
+func runMachineList(cmd *cobra.Command, args []string) error {
+	reg, err := loadMachineRegistry()
+	if err != nil {
+		return err
+	}
+
+	machines := reg.List()
+	sort.Slice(machines, func(i, j int) bool {
+		return machines[i].Name < machines[j].Name
+	})
+
+	if machineJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(machines)
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Machines"))
+	for _, m := range machines {
+		fmt.Printf("  %s  %s", style.Bold.Render(m.Name), style.Dim.Render(m.Type))
+		if m.Host != "" {
+			fmt.Printf("  %s", m.Host)
+		}
+		fmt.Println()
+		if m.TownPath != "" {
+			fmt.Printf("    town: %s\n", m.TownPath)
+		}
+		if m.KeyPath != "" {
+			fmt.Printf("    key:  %s\n", style.Dim.Render(m.KeyPath))
+		}
+	}
+	return nil
+}
+
+func runMachineAdd(cmd *cobra.Command, args []string) error {
+	name, host := args[0], args[1]
+	if name == "local" {
+		return fmt.Errorf("'local' is reserved for this machine")
+	}
+	if strings.ContainsAny(name, "/: ") {
+		return fmt.Errorf("invalid machine name %q: must not contain '/', ':' or spaces", name)
+	}
+
+	reg, err := loadMachineRegistry()
+	if err != nil {
+		return err
+	}
+	if _, err := reg.Get(name); err == nil {
+		return fmt.Errorf("machine '%s' already exists (remove it first to change it)", name)
+	}
+
+	m := &connection.Machine{
+		Name:           name,
+		Type:           "ssh",
+		Host:           host,
+		KeyPath:        machineKeyPath,
+		KnownHostsPath: machineKnownHosts,
+		TownPath:       machineTownPath,
+	}
+	if err := reg.Add(m); err != nil {
+		return fmt.Errorf("adding machine: %w", err)
+	}
+
+	fmt.Printf("%s Added machine '%s' (%s)\n", style.Bold.Render("✓"), name, host)
+	fmt.Printf("  %s\n", style.Dim.Render("Run 'gt machine test "+name+"' to verify connectivity"))
+	return nil
+}
+
+func runMachineRemove(cmd *cobra.Command, args []string) error {
+	reg, err := loadMachineRegistry()
+	if err != nil {
+		return err
+	}
+	if err := reg.Remove(args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed machine '%s'\n", style.Bold.Render("✓"), args[0])
+	return nil
+}
+
+func runMachineTest(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	reg, err := loadMachineRegistry()
+	if err != nil {
+		return err
+	}
+	m, err := reg.Get(name)
+	if err != nil {
+		return err
+	}
+	conn, err := reg.Connection(name)
+	if err != nil {
+		return err
+	}
+	if sc, ok := conn.(*connection.SSHConnection); ok {
+		defer sc.Close()
+	}
+
+	fmt.Printf("Testing machine %s...\n\n", style.Bold.Render(name))
+	failed := false
+	check := func(label string, fn func() (string, error)) {
+		detail, err := fn()
+		if err != nil {
+			failed = true
+			fmt.Printf("  %s %s: %v\n", style.Error.Render("✗"), label, err)
+			return
+		}
+		fmt.Printf("  %s %s %s\n", style.Success.Render("✓"), label, style.Dim.Render(detail))
+	}
+
+	start := time.Now()
+	check("connect", func() (string, error) {
+		out, err := conn.Exec("uname", "-sm")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s (%s)", strings.TrimSpace(string(out)), time.Since(start).Round(time.Millisecond)), nil
+	})
+	if failed {
+		return fmt.Errorf("cannot connect to %s", name)
+	}
+
+	for _, tool := range []string{"tmux", "git"} {
+		tool := tool
+		check(tool, func() (string, error) {
+			out, err := conn.Exec("sh", "-c", "command -v "+tool)
+			if err != nil {
+				return "", fmt.Errorf("not found in PATH")
+			}
+			return strings.TrimSpace(string(out)), nil
+		})
+	}
+
+	if m.TownPath != "" {
+		check("town path", func() (string, error) {
+			fi, err := conn.Stat(m.TownPath)
+			if err != nil {
+				return "", err
+			}
+			if !fi.IsDir() {
+				return "", fmt.Errorf("%s is not a directory", m.TownPath)
+			}
+			return m.TownPath, nil
+		})
+	}
+
+	check("tmux sessions", func() (string, error) {
+		sessions, err := conn.TmuxListSessions()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d running", len(sessions)), nil
+	})
+
+	fmt.Println()
+	if failed {
+		return fmt.Errorf("machine %s failed checks", name)
+	}
+	fmt.Printf("%s Machine %s is ready\n", style.Bold.Render("✓"), name)
+	return nil
+}
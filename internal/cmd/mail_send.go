@@ -116,10 +116,17 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 				style.PrintWarning("could not find original message %s for threading (new thread will be created)", mailReplyTo)
 			} else {
 				msg.ThreadID = original.ThreadID
+				msg.CorrelationID = original.CorrelationID
 			}
 		}
 	}
 
+	// An explicit correlation settles the pending request it answers, e.g.
+	// the witness's MERGE_READY awaiting the refinery's MERGED
+	if mailCorrelation != "" {
+		msg.CorrelationID = mailCorrelation
+	}
+
 	// Generate thread ID for new threads
 	if msg.ThreadID == "" {
 		msg.ThreadID = generateThreadID()
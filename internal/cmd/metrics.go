@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/metrics"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var metricsTextfile string
+
+var metricsCmd = &cobra.Command{
+	Use:     "metrics",
+	GroupID: GroupDiag,
+	Short:   "Export town health metrics for Prometheus",
+	Long: `Collect town health metrics in the Prometheus text format.
+
+Metrics cover polecats and merge queues per rig, running and zombie
+sessions, Deacon health-check failures, Dolt server health, open
+escalations, krc event stats and recent session deaths, labelled by rig
+and role where they apply.
+
+By default the metrics are printed. --textfile writes them atomically to a
+file for node_exporter's textfile collector (the file should end in .prom).
+To keep the file current, run this from cron or enable the daemon's
+metrics patrol in mayor/daemon.json:
+
+  {"patrols": {"metrics": {"enabled": true, "textfile": "/var/lib/node_exporter/gastown.prom"}}}
+
+gt dashboard also serves the metrics at /metrics; scrape it with the
+read-only dashboard token as a bearer token (see 'gt dashboard token').
+
+Examples:
+  gt metrics                                   # Print metrics
+  gt metrics --textfile /var/lib/node_exporter/gastown.prom`,
+	Args: cobra.NoArgs,
+	RunE: runMetrics,
+}
+
+func init() {
+	metricsCmd.Flags().StringVar(&metricsTextfile, "textfile", "", "Write metrics to this file for node_exporter")
+	rootCmd.AddCommand(metricsCmd)
+}
+
+func runMetrics(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	set := metrics.Collect(townRoot)
+	if metricsTextfile != "" {
+		return set.WriteTextfile(metricsTextfile)
+	}
+	return set.WriteText(os.Stdout)
+}
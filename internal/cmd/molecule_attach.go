@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/hooklog"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -38,6 +39,12 @@ func runMoleculeAttach(cmd *cobra.Command, args []string) error {
 
 	b := beads.New(workDir)
 
+	// Note what was attached before, for the hook activity log
+	var previousMolecule string
+	if prev, err := b.GetAttachment(pinnedBeadID); err == nil && prev != nil {
+		previousMolecule = prev.AttachedMolecule
+	}
+
 	// Attach the molecule
 	issue, err := b.AttachMolecule(pinnedBeadID, moleculeID)
 	if err != nil {
@@ -49,6 +56,14 @@ func runMoleculeAttach(cmd *cobra.Command, args []string) error {
 	if attachment != nil && attachment.AttachedAt != "" {
 		fmt.Printf("  attached_at: %s\n", attachment.AttachedAt)
 	}
+	recordHookActivity(hooklog.Entry{
+		Action:   hooklog.ActionAttach,
+		Command:  "gt mol attach",
+		Agent:    issue.Assignee,
+		Bead:     pinnedBeadID,
+		Molecule: moleculeID,
+		Previous: previousMolecule,
+	})
 
 	return nil
 }
@@ -119,15 +134,27 @@ func runMoleculeDetach(cmd *cobra.Command, args []string) error {
 	previousMolecule := attachment.AttachedMolecule
 
 	// Detach the molecule with audit logging
-	_, err = b.DetachMoleculeWithAudit(pinnedBeadID, beads.DetachOptions{
+	actor := detectCurrentAgent()
+	issue, err := b.DetachMoleculeWithAudit(pinnedBeadID, beads.DetachOptions{
 		Operation: "detach",
-		Agent:     detectCurrentAgent(),
+		Agent:     actor,
 	})
 	if err != nil {
 		return fmt.Errorf("detaching molecule: %w", err)
 	}
 
 	fmt.Printf("%s Detached %s from %s\n", style.Bold.Render("✓"), previousMolecule, pinnedBeadID)
+	entry := hooklog.Entry{
+		Action:   hooklog.ActionDetach,
+		Command:  "gt mol detach",
+		Actor:    actor,
+		Bead:     pinnedBeadID,
+		Molecule: previousMolecule,
+	}
+	if issue != nil {
+		entry.Agent = issue.Assignee
+	}
+	recordHookActivity(entry)
 
 	return nil
 }
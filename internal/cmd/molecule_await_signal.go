@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,7 +12,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
@@ -21,6 +24,7 @@ var (
 	awaitSignalBackoffMax  string
 	awaitSignalQuiet       bool
 	awaitSignalAgentBead   string
+	awaitSignalReply       string
 )
 
 var moleculeAwaitSignalCmd = &cobra.Command{
@@ -38,6 +42,13 @@ but sets the AWAIT_SIGNAL_REASON environment variable to "timeout".
 The timeout can be specified directly or via backoff configuration for
 exponential wait patterns.
 
+REPLY MODE:
+With --reply, the command waits for the reply to a mail request (sent with
+a deadline, e.g. MERGE_READY) instead of any feed activity. It returns
+"reply" when a message with the given correlation ID answers the request,
+"expired" when the request's deadline passes first (a mail_reply_timeout
+event is logged), or "timeout" when --timeout elapses before either.
+
 BACKOFF MODE:
 When backoff parameters are provided, the effective timeout is calculated as:
   min(base * multiplier^idle_cycles, max)
@@ -61,6 +72,9 @@ EXAMPLES:
   # On timeout, the agent bead's idle:N label is auto-incremented
   # On signal, caller should reset: gt agent state gt-gastown-witness --set idle=0
 
+  # Wait up to 10m for the refinery's reply to a MERGE_READY
+  gt mol await-signal --reply gt-mr-abc123 --timeout 10m
+
   # Quiet mode (no output, for scripting)
   gt mol await-signal --timeout 30s --quiet`,
 	RunE: runMoleculeAwaitSignal,
@@ -68,10 +82,12 @@ EXAMPLES:
 
 // AwaitSignalResult is the result of an await-signal operation.
 type AwaitSignalResult struct {
-	Reason     string        `json:"reason"`               // "signal" or "timeout"
-	Elapsed    time.Duration `json:"elapsed"`              // how long we waited
-	Signal     string        `json:"signal,omitempty"`     // the line that woke us (if signal)
+	Reason     string        `json:"reason"`                // "signal", "reply", "expired" or "timeout"
+	Elapsed    time.Duration `json:"elapsed"`               // how long we waited
+	Signal     string        `json:"signal,omitempty"`      // the line that woke us (if signal)
 	IdleCycles int           `json:"idle_cycles,omitempty"` // current idle cycle count (after update)
+	ReplyID    string        `json:"reply_id,omitempty"`    // the reply message (if reply)
+	ReplyFrom  string        `json:"reply_from,omitempty"`  // who replied (if reply)
 }
 
 func init() {
@@ -85,6 +101,8 @@ func init() {
 		"Maximum interval cap for backoff (e.g., 10m)")
 	moleculeAwaitSignalCmd.Flags().StringVar(&awaitSignalAgentBead, "agent-bead", "",
 		"Agent bead ID for tracking idle cycles (reads/writes idle:N label)")
+	moleculeAwaitSignalCmd.Flags().StringVar(&awaitSignalReply, "reply", "",
+		"Wait for the reply to the mail request with this correlation ID")
 	moleculeAwaitSignalCmd.Flags().BoolVar(&awaitSignalQuiet, "quiet", false,
 		"Suppress output (for scripting)")
 	moleculeAwaitSignalCmd.Flags().BoolVar(&moleculeJSON, "json", false,
@@ -165,6 +183,9 @@ func runMoleculeAwaitSignal(cmd *cobra.Command, args []string) error {
 		if resumed {
 			fmt.Printf("%s Resuming backoff (remaining: %v, idle: %d)...\n",
 				style.Dim.Render("⏳"), timeout.Round(time.Second), idleCycles)
+		} else if awaitSignalReply != "" {
+			fmt.Printf("%s Awaiting reply to %s (timeout: %v)...\n",
+				style.Dim.Render("⏳"), awaitSignalReply, timeout)
 		} else if awaitSignalAgentBead != "" {
 			fmt.Printf("%s Awaiting signal (timeout: %v, idle: %d)...\n",
 				style.Dim.Render("⏳"), timeout, idleCycles)
@@ -180,9 +201,21 @@ func runMoleculeAwaitSignal(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	result, err := waitForActivitySignal(ctx, workDir)
-	if err != nil {
-		return fmt.Errorf("feed subscription failed: %w", err)
+	var result *AwaitSignalResult
+	if awaitSignalReply != "" {
+		townRoot, err := workspace.FindFromCwd()
+		if err != nil || townRoot == "" {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		result, err = waitForReply(ctx, townRoot, awaitSignalReply)
+		if err != nil {
+			return err
+		}
+	} else {
+		result, err = waitForActivitySignal(ctx, workDir)
+		if err != nil {
+			return fmt.Errorf("feed subscription failed: %w", err)
+		}
 	}
 
 	result.Elapsed = time.Since(startTime)
@@ -200,7 +233,7 @@ func runMoleculeAwaitSignal(cmd *cobra.Command, args []string) error {
 		}
 		// Clear the backoff window — timeout completed normally
 		_ = clearAgentBackoffUntil(awaitSignalAgentBead, beadsDir)
-	} else if result.Reason == "signal" && awaitSignalAgentBead != "" {
+	} else if (result.Reason == "signal" || result.Reason == "reply") && awaitSignalAgentBead != "" {
 		// On signal, update last_activity to prove agent is alive
 		if err := updateAgentHeartbeat(awaitSignalAgentBead, beadsDir); err != nil {
 			if !awaitSignalQuiet {
@@ -234,6 +267,13 @@ func runMoleculeAwaitSignal(cmd *cobra.Command, args []string) error {
 				}
 				fmt.Printf("  %s\n", style.Dim.Render(sig))
 			}
+		case "reply":
+			fmt.Printf("%s Reply received after %v\n",
+				style.Bold.Render("✓"), result.Elapsed.Round(time.Millisecond))
+			fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%s from %s", result.ReplyID, result.ReplyFrom)))
+		case "expired":
+			fmt.Printf("%s Request %s passed its reply deadline\n",
+				style.Dim.Render("⏱"), awaitSignalReply)
 		case "timeout":
 			if awaitSignalAgentBead != "" {
 				fmt.Printf("%s Timeout after %v (idle cycle: %d)\n",
@@ -341,6 +381,27 @@ func waitForActivitySignal(ctx context.Context, workDir string) (*AwaitSignalRes
 	}
 }
 
+// waitForReply waits for the reply to the mail request with correlationID.
+// Returns "reply" once answered, "expired" when the request's deadline
+// passes, or "timeout" when ctx is done first.
+func waitForReply(ctx context.Context, townRoot, correlationID string) (*AwaitSignalResult, error) {
+	req, err := mail.NewPendingStore(townRoot).AwaitReply(ctx, correlationID)
+	switch {
+	case err == nil:
+		return &AwaitSignalResult{
+			Reason:    "reply",
+			ReplyID:   req.ReplyID,
+			ReplyFrom: req.ReplyFrom,
+		}, nil
+	case errors.Is(err, mail.ErrReplyTimeout):
+		return &AwaitSignalResult{Reason: "expired"}, nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return &AwaitSignalResult{Reason: "timeout"}, nil
+	default:
+		return nil, fmt.Errorf("awaiting reply: %w", err)
+	}
+}
+
 // parseIntSimple parses a string to int without using strconv.
 func parseIntSimple(s string) (int, error) {
 	if s == "" {
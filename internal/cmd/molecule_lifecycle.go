@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/ledger"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -253,6 +254,9 @@ squashed_at: %s
 		return fmt.Errorf("detaching molecule: %w", err)
 	}
 
+	// Record the completed molecule in the town ledger
+	recordSquash(b, townRoot, moleculeID, target, digestIssue.ID, progress)
+
 	if moleculeJSON {
 		result := map[string]interface{}{
 			"squashed":        moleculeID,
@@ -275,6 +279,47 @@ squashed_at: %s
 	return nil
 }
 
+// recordSquash appends a completion record for a squashed molecule to the
+// town ledger. Export is best-effort: the digest already exists, so a
+// failure only warns.
+func recordSquash(b *beads.Beads, townRoot, moleculeID, agent, digestID string, progress *MoleculeProgressInfo) {
+	rec := ledger.Record{
+		Trigger:   ledger.TriggerSquashed,
+		Bead:      moleculeID,
+		Actor:     agent,
+		Completed: time.Now().UTC(),
+		CostUSD:   ledger.WorkCost(moleculeID),
+		Outcome:   "squashed",
+		Ref:       digestID,
+	}
+	if rig, _, ok := strings.Cut(agent, "/"); ok {
+		rec.Rig = rig
+	}
+	rec.Started, rec.Formula = ledger.Slung(townRoot, moleculeID)
+	if progress != nil {
+		rec.Title = progress.RootTitle
+		if progress.MoleculeID != "" {
+			rec.Formula = progress.MoleculeID
+		}
+		rec.Outcome = "partial"
+		if progress.Complete {
+			rec.Outcome = "complete"
+		}
+	}
+	if mol, err := b.Show(moleculeID); err == nil {
+		if rec.Title == "" {
+			rec.Title = mol.Title
+		}
+		if rec.Started == nil {
+			rec.Started = ledger.ParseTime(mol.CreatedAt)
+		}
+	}
+
+	if _, _, err := ledger.Append(townRoot, rec); err != nil {
+		style.PrintWarning("could not record %s in the ledger: %v", moleculeID, err)
+	}
+}
+
 // closeDescendants recursively closes all descendant issues of a parent.
 // Returns the count of issues closed. Logs warnings on errors but doesn't fail.
 func closeDescendants(b *beads.Beads, parentID string) int {
@@ -7,9 +7,11 @@ import (
 	"os/exec"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -37,18 +39,29 @@ This command handles the step-to-step transition for polecats:
 IMPORTANT: This is the canonical way to complete molecule steps. Do NOT manually
 close steps with 'bd close' - it skips the auto-continuation logic.
 
-Example:
-  gt mol step done gt-abc.1    # Complete step 1 of molecule gt-abc`,
+FAILED STEPS:
+  With --failed, the step is not closed. Its formula policy decides what
+  happens next: the step is retried while it has retries left, then its
+  on_failure setting applies - continue (close it and move on), abort (the
+  default: mark it blocked and stop) or escalate (stop and run gt escalate).
+
+Examples:
+  gt mol step done gt-abc.1                          # Complete step 1 of molecule gt-abc
+  gt mol step done gt-abc.2 --failed -r "tests fail" # Report a failed attempt`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMoleculeStepDone,
 }
 
 var (
 	moleculeStepDryRun bool
+	moleculeStepFailed bool
+	moleculeStepReason string
 )
 
 func init() {
 	moleculeStepDoneCmd.Flags().BoolVarP(&moleculeStepDryRun, "dry-run", "n", false, "Show what would be done without executing")
+	moleculeStepDoneCmd.Flags().BoolVar(&moleculeStepFailed, "failed", false, "Report the step as failed and apply its retry/on_failure policy")
+	moleculeStepDoneCmd.Flags().StringVarP(&moleculeStepReason, "reason", "r", "", "Why the step failed (with --failed)")
 	moleculeStepDoneCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
 }
 
@@ -61,7 +74,8 @@ type StepDoneResult struct {
 	NextStepTitle string   `json:"next_step_title,omitempty"`
 	ParallelSteps []string `json:"parallel_steps,omitempty"` // Multiple ready steps for fan-out
 	Complete      bool     `json:"complete"`
-	Action        string   `json:"action"` // "continue", "parallel", "done", "no_more_ready"
+	Failure       string   `json:"failure,omitempty"` // With --failed: "retry", "continue", "abort", "escalate"
+	Action        string   `json:"action"`            // "continue", "parallel", "done", "no_more_ready", "retry", "aborted"
 }
 
 func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
@@ -106,8 +120,32 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		MoleculeID: moleculeID,
 	}
 
-	// Step 3: Close the step
-	if moleculeStepDryRun {
+	// Step 3: Close the step, or apply its failure policy
+	if moleculeStepFailed {
+		action, err := handleStepFailure(b, step, moleculeID, moleculeStepReason, moleculeStepDryRun)
+		if err != nil {
+			return err
+		}
+		result.Failure = string(action)
+		switch action {
+		case formula.FailureRetry:
+			result.Action = "retry"
+			result.NextStepID = step.ID
+			result.NextStepTitle = step.Title
+			if moleculeJSON {
+				return encodeStepDoneResult(result)
+			}
+			return handleStepContinue(cwd, townRoot, step, moleculeStepDryRun)
+		case formula.FailureAbort, formula.FailureEscalate:
+			result.Action = "aborted"
+			if moleculeJSON {
+				return encodeStepDoneResult(result)
+			}
+			fmt.Printf("Run 'gt mol progress %s' to see the molecule's state\n", moleculeID)
+			return nil
+		}
+		result.StepClosed = true
+	} else if moleculeStepDryRun {
 		fmt.Printf("[dry-run] Would close step: %s\n", stepID)
 		result.StepClosed = true
 	} else {
@@ -116,6 +154,12 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		}
 		result.StepClosed = true
 		fmt.Printf("%s Closed step %s: %s\n", style.Bold.Render("✓"), stepID, step.Title)
+		if fields := beads.ParseStepFields(step); fields != nil {
+			if elapsed, overdue := fields.Overdue(time.Now()); overdue {
+				fmt.Printf("  %s Step ran %s, over its %s timeout\n",
+					style.Dim.Render("⚠"), elapsed.Round(time.Second), fields.Timeout)
+			}
+		}
 	}
 
 	// Step 4: Find all ready steps (supports fan-out pattern)
@@ -145,9 +189,7 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 
 	// JSON output
 	if moleculeJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(result)
+		return encodeStepDoneResult(result)
 	}
 
 	// Step 5: Handle next action
@@ -171,6 +213,12 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func encodeStepDoneResult(result StepDoneResult) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
 // extractMoleculeIDFromStep extracts the molecule ID from a step ID.
 // Step IDs have format: mol-id.N where N is the step number.
 // Examples:
@@ -411,6 +459,7 @@ func handleStepContinue(cwd, townRoot string, nextStep *beads.Issue, dryRun bool
 	}
 
 	fmt.Printf("%s Next step pinned: %s\n", style.Bold.Render("📌"), nextStep.ID)
+	startStepClock(gitRoot, nextStep)
 
 	// Respawn the pane
 	if !tmux.IsInsideTmux() {
@@ -487,7 +536,9 @@ func handleParallelSteps(cwd, townRoot, _ string, steps []*beads.Issue, dryRun b
 		markCmd.Stderr = os.Stderr
 		if err := markCmd.Run(); err != nil {
 			style.PrintWarning("could not mark step %s as in_progress: %v", step.ID, err)
+			continue
 		}
+		startStepClock(gitRoot, step)
 	}
 
 	// Execute steps concurrently using goroutines
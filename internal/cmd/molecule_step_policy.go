@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// applyStepPolicies stamps formula step policies (timeout, retries,
+// on_failure) onto a new molecule's step beads and closes the steps whose
+// when condition is false for the molecule's vars. bd instantiates
+// molecules without knowing about these fields, so gt applies them after
+// the wisp exists. Step beads are matched to formula steps by title.
+func applyStepPolicies(formulaName, moleculeID, workDir string, vars []string) error {
+	var f *formula.Formula
+	for _, name := range []string{formulaName, "mol-" + formulaName} {
+		res, err := formula.ResolveFormula(name, workDir)
+		if err != nil {
+			continue
+		}
+		if f, err = res.Load(); errors.Is(err, formula.ErrJSONFormula) {
+			return nil // bd-only formula
+		} else if err != nil {
+			return fmt.Errorf("loading formula %s: %w", name, err)
+		}
+		break
+	}
+	if f == nil || f.Type != formula.TypeWorkflow {
+		return nil // bd-only formula or no steps to annotate
+	}
+
+	hasPolicy := false
+	for i := range f.Steps {
+		if f.Steps[i].HasPolicy() {
+			hasPolicy = true
+			break
+		}
+	}
+	if !hasPolicy {
+		return nil
+	}
+
+	values := make(map[string]string)
+	for _, v := range vars {
+		if key, value, ok := strings.Cut(v, "="); ok {
+			values[key] = value
+		}
+	}
+	resolved := f.ResolveVars(values)
+
+	b := beads.New(workDir)
+	children, err := b.List(beads.ListOptions{Parent: moleculeID, Status: "all", Priority: -1})
+	if err != nil {
+		return fmt.Errorf("listing molecule steps: %w", err)
+	}
+	byTitle := make(map[string]*beads.Issue, len(children))
+	for _, child := range children {
+		byTitle[child.Title] = child
+	}
+
+	for i := range f.Steps {
+		step := &f.Steps[i]
+		if !step.HasPolicy() {
+			continue
+		}
+		bead := byTitle[renderStepVars(step.Title, resolved)]
+		if bead == nil {
+			style.PrintWarning("no bead found for step %q; its policy is not applied", step.ID)
+			continue
+		}
+
+		if !f.StepEnabled(step.ID, values) {
+			reason := fmt.Sprintf("skipped: when %q is false", step.When)
+			if err := b.CloseWithReason(reason, bead.ID); err != nil {
+				return fmt.Errorf("skipping step %s: %w", bead.ID, err)
+			}
+			fmt.Printf("  %s Skipped step %s (%s)\n", style.Dim.Render("○"), bead.ID, reason)
+			continue
+		}
+
+		if step.Timeout == "" && step.Retries == 0 && step.OnFailure == "" {
+			continue
+		}
+		fields := beads.ParseStepFields(bead)
+		if fields == nil {
+			fields = &beads.StepFields{}
+		}
+		fields.StepID = step.ID
+		fields.Timeout = step.Timeout
+		fields.Retries = step.Retries
+		fields.OnFailure = step.OnFailure
+		if step.Timeout != "" && len(step.Needs) == 0 {
+			// Root steps start as soon as the molecule is slung; later steps
+			// start their clock when gt mol step done pins them.
+			fields.StartedAt = time.Now().UTC().Format(time.RFC3339)
+		}
+		desc := beads.SetStepFields(bead, fields)
+		if err := b.Update(bead.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+			return fmt.Errorf("storing policy on step %s: %w", bead.ID, err)
+		}
+	}
+	return nil
+}
+
+// renderStepVars substitutes {{var}} placeholders the way bd does when it
+// creates step beads, so titles can be matched.
+func renderStepVars(text string, vars map[string]string) string {
+	for name, value := range vars {
+		text = strings.ReplaceAll(text, "{{"+name+"}}", value)
+	}
+	return text
+}
+
+// startStepClock records when a step's current attempt began, so the
+// witness can tell when it runs past its timeout. Steps without a timeout
+// are left alone.
+func startStepClock(workDir string, step *beads.Issue) {
+	fields := beads.ParseStepFields(step)
+	if fields == nil || fields.Timeout == "" {
+		return
+	}
+	fields.StartedAt = time.Now().UTC().Format(time.RFC3339)
+	fields.EscalatedAt = ""
+	desc := beads.SetStepFields(step, fields)
+	if err := beads.New(workDir).Update(step.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+		style.PrintWarning("could not record start time for %s: %v", step.ID, err)
+		return
+	}
+	step.Description = desc
+}
+
+// handleStepFailure records a failed attempt on a step and applies its
+// retry and on_failure policy. Steps without a policy abort on the first
+// failure. Returns the action taken.
+func handleStepFailure(b *beads.Beads, step *beads.Issue, moleculeID, reason string, dryRun bool) (formula.FailureAction, error) {
+	fields := beads.ParseStepFields(step)
+	if fields == nil {
+		fields = &beads.StepFields{}
+	}
+	fields.Attempts++
+	fields.LastError = reason
+	fields.StartedAt = ""
+	fields.EscalatedAt = ""
+	action := formula.ResolveFailure(fields.Retries, fields.OnFailure, fields.Attempts)
+
+	if dryRun {
+		fmt.Printf("[dry-run] Would record failed attempt %d on %s and %s\n", fields.Attempts, step.ID, action)
+		return action, nil
+	}
+
+	desc := beads.SetStepFields(step, fields)
+	opts := beads.UpdateOptions{Description: &desc}
+	switch action {
+	case formula.FailureRetry:
+		status := "open"
+		opts.Status = &status
+	case formula.FailureAbort, formula.FailureEscalate:
+		status := "blocked"
+		opts.Status = &status
+	}
+	if err := b.Update(step.ID, opts); err != nil {
+		return action, fmt.Errorf("recording failure on %s: %w", step.ID, err)
+	}
+	step.Description = desc
+
+	switch action {
+	case formula.FailureRetry:
+		fmt.Printf("%s Step %s failed (attempt %d of %d), retrying\n",
+			style.Bold.Render("↻"), step.ID, fields.Attempts, fields.Retries+1)
+
+	case formula.FailureContinue:
+		closeReason := "failed, continuing (on_failure=continue)"
+		if reason != "" {
+			closeReason += ": " + reason
+		}
+		if err := b.CloseWithReason(closeReason, step.ID); err != nil {
+			return action, fmt.Errorf("closing step: %w", err)
+		}
+		fmt.Printf("%s Step %s failed; continuing (on_failure=continue)\n", style.Bold.Render("⚠"), step.ID)
+
+	case formula.FailureAbort:
+		fmt.Printf("%s Step %s failed; molecule %s stopped (status=blocked)\n", style.Bold.Render("✗"), step.ID, moleculeID)
+
+	case formula.FailureEscalate:
+		description := fmt.Sprintf("Molecule step failed: %s (%s)", step.Title, step.ID)
+		detail := fmt.Sprintf("Step %s of molecule %s failed after %d attempt(s).", step.ID, moleculeID, fields.Attempts)
+		if reason != "" {
+			detail += "\nReason: " + reason
+		}
+		escCmd := exec.Command("gt", "escalate", description,
+			"--severity", "high",
+			"--reason", detail,
+			"--source", "molecule:"+moleculeID,
+			"--related", step.ID)
+		escCmd.Stdout = os.Stdout
+		escCmd.Stderr = os.Stderr
+		if err := escCmd.Run(); err != nil {
+			return action, fmt.Errorf("escalating failed step: %w", err)
+		}
+		fmt.Printf("%s Step %s failed; molecule %s stopped and escalated\n", style.Bold.Render("✗"), step.ID, moleculeID)
+	}
+
+	return action, nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -17,12 +18,14 @@ import (
 
 // Plugin command flags
 var (
-	pluginListJSON    bool
-	pluginShowJSON    bool
-	pluginRunForce    bool
-	pluginRunDryRun   bool
-	pluginHistoryJSON bool
+	pluginListJSON     bool
+	pluginShowJSON     bool
+	pluginRunForce     bool
+	pluginRunDryRun    bool
+	pluginHistoryJSON  bool
 	pluginHistoryLimit int
+	pluginDueJSON      bool
+	pluginDueAll       bool
 )
 
 var pluginCmd = &cobra.Command{
@@ -47,6 +50,7 @@ GATE TYPES:
 Examples:
   gt plugin list                    # List all discovered plugins
   gt plugin show <name>             # Show plugin details
+  gt plugin due                     # Show which gates are open
   gt plugin list --json             # JSON output`,
 	RunE: requireSubcommand,
 }
@@ -113,6 +117,25 @@ Examples:
 	RunE: runPluginHistory,
 }
 
+var pluginDueCmd = &cobra.Command{
+	Use:   "due",
+	Short: "Show which plugins would fire next and why",
+	Long: `Evaluate every plugin's gate and show which are due.
+
+Cooldown and cron gates are checked against the last recorded run,
+condition gates run their check command, and event gates look for
+matching events in .events.jsonl since the last run. Closed gates show
+when they are expected to open, where that can be predicted.
+
+Manual plugins are hidden unless --all is given.
+
+Examples:
+  gt plugin due
+  gt plugin due --all
+  gt plugin due --json`,
+	RunE: runPluginDue,
+}
+
 func init() {
 	// List subcommand flags
 	pluginListCmd.Flags().BoolVar(&pluginListJSON, "json", false, "Output as JSON")
@@ -128,11 +151,16 @@ func init() {
 	pluginHistoryCmd.Flags().BoolVar(&pluginHistoryJSON, "json", false, "Output as JSON")
 	pluginHistoryCmd.Flags().IntVar(&pluginHistoryLimit, "limit", 10, "Maximum number of runs to show")
 
+	// Due subcommand flags
+	pluginDueCmd.Flags().BoolVar(&pluginDueJSON, "json", false, "Output as JSON")
+	pluginDueCmd.Flags().BoolVar(&pluginDueAll, "all", false, "Include manual plugins")
+
 	// Add subcommands
 	pluginCmd.AddCommand(pluginListCmd)
 	pluginCmd.AddCommand(pluginShowCmd)
 	pluginCmd.AddCommand(pluginRunCmd)
 	pluginCmd.AddCommand(pluginHistoryCmd)
+	pluginCmd.AddCommand(pluginDueCmd)
 
 	rootCmd.AddCommand(pluginCmd)
 }
@@ -383,22 +411,17 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Check gate status for cooldown gates
+	// Check gate status (manual gates are always open for explicit runs)
 	gateOpen := true
 	gateReason := ""
-	if p.Gate != nil && p.Gate.Type == plugin.GateCooldown && !pluginRunForce {
-		recorder := plugin.NewRecorder(townRoot)
-		duration := p.Gate.Duration
-		if duration == "" {
-			duration = "1h" // default
-		}
-		count, err := recorder.CountRunsSince(p.Name, duration)
-		if err != nil {
+	if p.Gate != nil && p.Gate.Type != plugin.GateManual && !pluginRunForce {
+		decision := plugin.NewGateEvaluator(townRoot).Evaluate(context.Background(), p)
+		if decision.Error != "" {
 			// Log warning but continue
-			fmt.Fprintf(os.Stderr, "Warning: checking gate status: %v\n", err)
-		} else if count > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: checking gate status: %s\n", decision.Error)
+		} else if !decision.Due {
 			gateOpen = false
-			gateReason = fmt.Sprintf("ran %d time(s) within %s cooldown", count, duration)
+			gateReason = decision.Reason
 		}
 	}
 
@@ -505,3 +528,85 @@ func runPluginHistory(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runPluginDue(cmd *cobra.Command, args []string) error {
+	scanner, townRoot, err := getPluginScanner()
+	if err != nil {
+		return err
+	}
+
+	plugins, err := scanner.DiscoverAll()
+	if err != nil {
+		return fmt.Errorf("discovering plugins: %w", err)
+	}
+
+	decisions := plugin.NewGateEvaluator(townRoot).EvaluateAll(context.Background(), plugins)
+	if !pluginDueAll {
+		filtered := decisions[:0]
+		for _, d := range decisions {
+			if d.Gate != plugin.GateManual {
+				filtered = append(filtered, d)
+			}
+		}
+		decisions = filtered
+	}
+
+	// Due first, then soonest to open, then by name
+	sort.SliceStable(decisions, func(i, j int) bool {
+		a, b := decisions[i], decisions[j]
+		if a.Due != b.Due {
+			return a.Due
+		}
+		if (a.NextAt == nil) != (b.NextAt == nil) {
+			return a.NextAt != nil
+		}
+		if a.NextAt != nil && !a.NextAt.Equal(*b.NextAt) {
+			return a.NextAt.Before(*b.NextAt)
+		}
+		return a.Plugin < b.Plugin
+	})
+
+	if pluginDueJSON {
+		if decisions == nil {
+			decisions = []plugin.GateDecision{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(decisions)
+	}
+
+	if len(decisions) == 0 {
+		fmt.Printf("%s No gated plugins\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	due := 0
+	for _, d := range decisions {
+		if d.Due {
+			due++
+		}
+	}
+	fmt.Printf("%s %d of %d plugin(s) due\n\n", style.Success.Render("●"), due, len(decisions))
+
+	for _, d := range decisions {
+		icon := style.Dim.Render("○")
+		switch {
+		case d.Error != "":
+			icon = style.Error.Render("✗")
+		case d.Due:
+			icon = style.Success.Render("●")
+		}
+
+		name := d.Plugin
+		if d.RigName != "" {
+			name = d.RigName + "/" + d.Plugin
+		}
+		fmt.Printf("  %s %s %s\n", icon, name, style.Dim.Render("["+string(d.Gate)+"]"))
+		fmt.Printf("      %s\n", d.Reason)
+		if d.LastRun != nil {
+			fmt.Printf("      %s\n", style.Dim.Render("last run: "+d.LastRun.Local().Format("2006-01-02 15:04")))
+		}
+	}
+
+	return nil
+}
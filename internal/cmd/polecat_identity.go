@@ -15,7 +15,9 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/ledger"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -86,6 +88,7 @@ Displays:
   - Identity bead ID and creation date
   - Session count
   - Completion statistics (issues completed, failed, abandoned)
+  - Merges, lines changed, cost and convoys from the completion ledger
   - Language breakdown from file extensions
   - Work type breakdown (feat, fix, refactor, etc.)
   - Recent work list with relative timestamps
@@ -192,6 +195,11 @@ type CVSummary struct {
 	WorkTypes        map[string]int   `json:"work_types,omitempty"`
 	AvgCompletionMin int              `json:"avg_completion_minutes,omitempty"`
 	FirstPassRate    float64          `json:"first_pass_rate,omitempty"`
+	Merged           int              `json:"merged,omitempty"`         // From the ledger
+	LinesAdded       int              `json:"lines_added,omitempty"`    // From the ledger
+	LinesRemoved     int              `json:"lines_removed,omitempty"`  // From the ledger
+	CostUSD          float64          `json:"cost_usd,omitempty"`       // From the ledger
+	ConvoysLanded    int              `json:"convoys_landed,omitempty"` // From the ledger
 	RecentWork       []RecentWorkItem `json:"recent_work,omitempty"`
 }
 
@@ -277,7 +285,7 @@ func runPolecatIdentityList(cmd *cobra.Command, args []string) error {
 	// Filter for polecat beads in this rig
 	identities := []IdentityInfo{} // Initialize to empty slice (not nil) for JSON
 	t := tmux.NewTmux()
-	polecatMgr := polecat.NewSessionManager(t, r)
+	polecatMgr := polecat.NewSessionManager(session.DefaultBackend(), r)
 
 	for id, issue := range agentBeads {
 		// Parse the bead ID to check if it's a polecat for this rig
@@ -396,7 +404,7 @@ func runPolecatIdentityShow(cmd *cobra.Command, args []string) error {
 
 	// Check worktree and session
 	t := tmux.NewTmux()
-	polecatMgr := polecat.NewSessionManager(t, r)
+	polecatMgr := polecat.NewSessionManager(session.DefaultBackend(), r)
 	mgr := polecat.NewManager(r, nil, t)
 
 	worktreeExists := false
@@ -507,6 +515,15 @@ func runPolecatIdentityShow(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Issues completed: %s\n", style.Success.Render(fmt.Sprintf("%d", cv.IssuesCompleted)))
 	fmt.Printf("  Issues failed:    %s\n", formatCountStyled(cv.IssuesFailed, style.Error))
 	fmt.Printf("  Issues abandoned: %s\n", formatCountStyled(cv.IssuesAbandoned, style.Warning))
+	if cv.Merged > 0 {
+		fmt.Printf("  Merged:           %d (+%d -%d lines)\n", cv.Merged, cv.LinesAdded, cv.LinesRemoved)
+	}
+	if cv.ConvoysLanded > 0 {
+		fmt.Printf("  Convoys landed:   %d\n", cv.ConvoysLanded)
+	}
+	if cv.CostUSD > 0 {
+		fmt.Printf("  Cost:             $%.2f\n", cv.CostUSD)
+	}
 
 	// Language stats
 	if len(cv.Languages) > 0 {
@@ -582,8 +599,7 @@ func runPolecatIdentityRename(cmd *cobra.Command, args []string) error {
 	}
 
 	// Safety check: no active session
-	t := tmux.NewTmux()
-	polecatMgr := polecat.NewSessionManager(t, r)
+	polecatMgr := polecat.NewSessionManager(session.DefaultBackend(), r)
 	running, _ := polecatMgr.IsRunning(oldName)
 	if running {
 		return fmt.Errorf("cannot rename: polecat session %s is running", oldName)
@@ -653,8 +669,7 @@ func runPolecatIdentityRemove(cmd *cobra.Command, args []string) error {
 		var reasons []string
 
 		// Check for active session
-		t := tmux.NewTmux()
-		polecatMgr := polecat.NewSessionManager(t, r)
+		polecatMgr := polecat.NewSessionManager(session.DefaultBackend(), r)
 		running, _ := polecatMgr.IsRunning(polecatName)
 		if running {
 			reasons = append(reasons, "session is running")
@@ -782,6 +797,11 @@ func buildCVSummary(rigPath, rigName, polecatName, identityBeadID, clonePath str
 		}
 	}
 
+	// Merges, timings, cost and touched languages from the town ledger
+	if records, err := ledger.Records(ledger.Path(filepath.Dir(rigPath))); err == nil {
+		applyLedgerCV(cv, records, assignee)
+	}
+
 	// Calculate first-pass success rate
 	total := cv.IssuesCompleted + cv.IssuesFailed + cv.IssuesAbandoned
 	if total > 0 {
@@ -791,6 +811,40 @@ func buildCVSummary(rigPath, rigName, polecatName, identityBeadID, clonePath str
 	return cv
 }
 
+// applyLedgerCV adds actor's completion records to the CV. Merge records
+// carry the agent's own work: durations, cost and diffs. Languages from
+// the files those merges touched replace the clone's recent history, which
+// includes other agents' commits.
+func applyLedgerCV(cv *CVSummary, records []ledger.Record, actor string) {
+	var files []string
+	var total time.Duration
+	timed := 0
+	for _, r := range records {
+		switch {
+		case r.Trigger == ledger.TriggerMerged && r.Actor == actor:
+			cv.Merged++
+			cv.CostUSD += r.CostUSD
+			if d := r.Duration(); d > 0 {
+				total += d
+				timed++
+			}
+			if r.Diff != nil {
+				cv.LinesAdded += r.Diff.Added
+				cv.LinesRemoved += r.Diff.Removed
+				files = append(files, r.Diff.Files...)
+			}
+		case r.Trigger == ledger.TriggerLanded && r.Involves(actor):
+			cv.ConvoysLanded++
+		}
+	}
+	if timed > 0 {
+		cv.AvgCompletionMin = int((total / time.Duration(timed)).Minutes())
+	}
+	if langs := languageStats(files); len(langs) > 0 {
+		cv.Languages = langs
+	}
+}
+
 // IssueInfo holds basic issue information for CV queries.
 type IssueInfo struct {
 	ID      string `json:"id"`
@@ -880,59 +934,51 @@ func getLanguageStats(clonePath string) map[string]int {
 		return stats
 	}
 
-	// Count file extensions
-	extCount := make(map[string]int)
-	for _, line := range strings.Split(string(out), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		ext := filepath.Ext(line)
-		if ext != "" {
-			extCount[ext]++
-		}
-	}
+	return languageStats(strings.Split(string(out), "\n"))
+}
+
+// cvLanguages maps file extensions to the languages shown on CVs.
+var cvLanguages = map[string]string{
+	".go":    "Go",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".py":    "Python",
+	".rs":    "Rust",
+	".java":  "Java",
+	".rb":    "Ruby",
+	".c":     "C",
+	".cpp":   "C++",
+	".h":     "C",
+	".hpp":   "C++",
+	".cs":    "C#",
+	".swift": "Swift",
+	".kt":    "Kotlin",
+	".scala": "Scala",
+	".php":   "PHP",
+	".sh":    "Shell",
+	".bash":  "Shell",
+	".zsh":   "Shell",
+	".md":    "Markdown",
+	".yaml":  "YAML",
+	".yml":   "YAML",
+	".json":  "JSON",
+	".toml":  "TOML",
+	".sql":   "SQL",
+	".html":  "HTML",
+	".css":   "CSS",
+	".scss":  "SCSS",
+}
 
-	// Map extensions to languages
-	extToLang := map[string]string{
-		".go":    "Go",
-		".ts":    "TypeScript",
-		".tsx":   "TypeScript",
-		".js":    "JavaScript",
-		".jsx":   "JavaScript",
-		".py":    "Python",
-		".rs":    "Rust",
-		".java":  "Java",
-		".rb":    "Ruby",
-		".c":     "C",
-		".cpp":   "C++",
-		".h":     "C",
-		".hpp":   "C++",
-		".cs":    "C#",
-		".swift": "Swift",
-		".kt":    "Kotlin",
-		".scala": "Scala",
-		".php":   "PHP",
-		".sh":    "Shell",
-		".bash":  "Shell",
-		".zsh":   "Shell",
-		".md":    "Markdown",
-		".yaml":  "YAML",
-		".yml":   "YAML",
-		".json":  "JSON",
-		".toml":  "TOML",
-		".sql":   "SQL",
-		".html":  "HTML",
-		".css":   "CSS",
-		".scss":  "SCSS",
-	}
-
-	for ext, count := range extCount {
-		if lang, ok := extToLang[ext]; ok {
-			stats[lang] += count
+// languageStats counts file paths by language.
+func languageStats(paths []string) map[string]int {
+	stats := make(map[string]int)
+	for _, p := range paths {
+		if lang, ok := cvLanguages[filepath.Ext(strings.TrimSpace(p))]; ok {
+			stats[lang]++
 		}
 	}
-
 	return stats
 }
 
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/ledger"
+)
+
+func TestApplyLedgerCV(t *testing.T) {
+	toast := "gastown/polecats/Toast"
+	records := []ledger.Record{
+		{Trigger: ledger.TriggerMerged, Bead: "gt-1", Actor: toast, DurationSec: 1800, CostUSD: 1.5,
+			Diff: &ledger.Diff{Files: []string{"cmd/main.go", "README.md"}, Changed: 2, Added: 40, Removed: 5}},
+		{Trigger: ledger.TriggerMerged, Bead: "gt-2", Actor: toast, DurationSec: 5400, CostUSD: 2,
+			Diff: &ledger.Diff{Files: []string{"internal/x.go"}, Changed: 1, Added: 10, Removed: 1}},
+		{Trigger: ledger.TriggerMerged, Bead: "gt-3", Actor: "gastown/polecats/Nux", DurationSec: 60},
+		{Trigger: ledger.TriggerSquashed, Bead: "gt-wisp-1", Actor: toast, DurationSec: 7200},
+		{Trigger: ledger.TriggerLanded, Bead: "hq-cv-1", Agents: []string{"gastown/polecats/Nux", toast}},
+	}
+	cv := &CVSummary{Languages: map[string]int{"Python": 9}}
+	applyLedgerCV(cv, records, toast)
+
+	if cv.Merged != 2 || cv.ConvoysLanded != 1 || cv.CostUSD != 3.5 {
+		t.Errorf("merged %d convoys %d cost %v", cv.Merged, cv.ConvoysLanded, cv.CostUSD)
+	}
+	if cv.LinesAdded != 50 || cv.LinesRemoved != 6 {
+		t.Errorf("lines +%d -%d, want +50 -6", cv.LinesAdded, cv.LinesRemoved)
+	}
+	if want := int((time.Hour).Minutes()); cv.AvgCompletionMin != want {
+		t.Errorf("avg completion = %d, want %d", cv.AvgCompletionMin, want)
+	}
+	if cv.Languages["Go"] != 2 || cv.Languages["Markdown"] != 1 || cv.Languages["Python"] != 0 {
+		t.Errorf("languages = %v", cv.Languages)
+	}
+}
@@ -9,15 +9,20 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/budget"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/convoy"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/ratelimit"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/wisp"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -78,6 +83,12 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		return nil, fmt.Errorf("rig '%s' not found", rigName)
 	}
 
+	// Pre-spawn budget check: the daemon parks rigs and marks convoys that
+	// reached their cost cap; nothing new spawns until a human raises it.
+	if err := checkSpawnBudget(townRoot, rigName, opts.HookBead); err != nil {
+		return nil, err
+	}
+
 	// Get polecat manager (with tmux for session-aware allocation)
 	polecatGit := git.NewGit(r.Path)
 	t := tmux.NewTmux()
@@ -180,7 +191,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	fmt.Printf("%s Dolt branch: %s\n", style.Bold.Render("✓"), doltBranch)
 
 	// Get session manager for session name (session start is deferred)
-	polecatSessMgr := polecat.NewSessionManager(t, r)
+	polecatSessMgr := polecat.NewSessionManager(session.DefaultBackend(), r)
 	sessionName := polecatSessMgr.SessionName(polecatName)
 
 	fmt.Printf("%s Polecat %s spawned (session start deferred)\n", style.Bold.Render("✓"), polecatName)
@@ -230,14 +241,17 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 
 	// Resolve account
 	accountsPath := constants.MayorAccountsPath(townRoot)
-	claudeConfigDir, _, err := config.ResolveAccountConfigDir(accountsPath, s.account)
+	claudeConfigDir, accountHandle, err := config.ResolveAccountConfigDir(accountsPath, s.account)
 	if err != nil {
 		return "", fmt.Errorf("resolving account: %w", err)
 	}
+	if s.account == "" && os.Getenv("GT_ACCOUNT") == "" {
+		claudeConfigDir = avoidCoolingAccount(townRoot, accountsPath, accountHandle, claudeConfigDir)
+	}
 
 	// Start session
 	t := tmux.NewTmux()
-	polecatSessMgr := polecat.NewSessionManager(t, r)
+	polecatSessMgr := polecat.NewSessionManager(session.DefaultBackend(), r)
 
 	fmt.Printf("Starting session for %s/%s...\n", s.RigName, s.PolecatName)
 	startOpts := polecat.SessionStartOptions{
@@ -360,3 +374,44 @@ func verifyWorktreeExists(clonePath string) error {
 
 	return nil
 }
+
+// checkSpawnBudget refuses to spawn a polecat in a rig the daemon parked for
+// exceeding its budget, or for an issue tracked by an over-budget convoy.
+func checkSpawnBudget(townRoot, rigName, hookBead string) error {
+	if reason := wisp.NewConfig(townRoot, rigName).GetString(budget.WispParkedKey); reason != "" {
+		return fmt.Errorf("rig '%s' is over budget (%s)\nraise the cap with: gt rig settings set %s budget.daily_usd N (or budget.total_usd)",
+			rigName, reason, rigName)
+	}
+	if hookBead == "" {
+		return nil
+	}
+	if blocked := convoy.BudgetBlocks(beads.New(townRoot), hookBead); len(blocked) > 0 {
+		return fmt.Errorf("%s is tracked by over-budget convoy %s\nraise the cap with: gt convoy budget %s --daily N --total N",
+			hookBead, strings.Join(blocked, ", "), blocked[0])
+	}
+	return nil
+}
+
+// avoidCoolingAccount swaps the default account for the next available one
+// when it is cooling down after hitting a rate limit. Explicit --account or
+// GT_ACCOUNT choices are not second-guessed.
+func avoidCoolingAccount(townRoot, accountsPath, handle, configDir string) string {
+	if handle == "" {
+		return configDir
+	}
+	state, err := ratelimit.LoadState(townRoot)
+	if err != nil || state.Active(handle, time.Now()) == nil {
+		return configDir
+	}
+	cfg, err := config.LoadAccountsConfig(accountsPath)
+	if err != nil {
+		return configDir
+	}
+	next := state.NextAvailable(cfg, handle, time.Now())
+	if next == "" {
+		fmt.Printf("%s Account %s is cooling down and no other account is available\n", style.Warning.Render("⚠"), handle)
+		return configDir
+	}
+	fmt.Printf("Account %s is cooling down; using %s\n", handle, next)
+	return ratelimit.ConfigDir(cfg, next)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -217,6 +218,29 @@ Examples:
 
 var refineryBlockedJSON bool
 
+var refineryTrainCmd = &cobra.Command{
+	Use:   "train [rig]",
+	Short: "Merge the next batch of MRs as a speculative merge train",
+	Long: `Merge the top ready MRs together as one speculative merge train.
+
+Up to merge_queue.max_concurrent MRs (taken in merge queue order) are
+squash-merged onto a temporary branch and the test command runs once for
+the whole batch. If the tests pass, the target branch is fast-forwarded
+and pushed. If they fail, the batch is bisected to find the culprit MR(s),
+which are failed individually while the rest land.
+
+Each MR still gets its own MERGED or MERGE_FAILED message to the Witness.
+With max_concurrent = 1 this is equivalent to serial processing.
+
+Examples:
+  gt refinery train
+  gt refinery train greenplace --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryTrain,
+}
+
+var refineryTrainDryRun bool
+
 func init() {
 	// Start flags
 	refineryStartCmd.Flags().BoolVar(&refineryForeground, "foreground", false, "Run in foreground (default: background)")
@@ -243,6 +267,9 @@ func init() {
 	// Blocked flags
 	refineryBlockedCmd.Flags().BoolVar(&refineryBlockedJSON, "json", false, "Output as JSON")
 
+	// Train flags
+	refineryTrainCmd.Flags().BoolVarP(&refineryTrainDryRun, "dry-run", "n", false, "Show the next train without merging")
+
 	// Add subcommands
 	refineryCmd.AddCommand(refineryStartCmd)
 	refineryCmd.AddCommand(refineryStopCmd)
@@ -255,6 +282,7 @@ func init() {
 	refineryCmd.AddCommand(refineryUnclaimedCmd)
 	refineryCmd.AddCommand(refineryReadyCmd)
 	refineryCmd.AddCommand(refineryBlockedCmd)
+	refineryCmd.AddCommand(refineryTrainCmd)
 
 	rootCmd.AddCommand(refineryCmd)
 }
@@ -766,3 +794,74 @@ func runRefineryBlocked(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runRefineryTrain(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+
+	mgr, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+
+	ready, err := eng.ListReadyMRs()
+	if err != nil {
+		return fmt.Errorf("listing ready MRs: %w", err)
+	}
+	queue, err := mgr.Queue()
+	if err != nil {
+		return fmt.Errorf("reading merge queue: %w", err)
+	}
+	batch := eng.NextBatch(refinery.OrderByQueue(ready, queue))
+
+	fmt.Printf("%s Next merge train for '%s' (max %d):\n\n", style.Bold.Render("🚂"), rigName, eng.BatchSize())
+	if len(batch) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no ready MRs)"))
+		return nil
+	}
+	for i, mr := range batch {
+		fmt.Printf("  %d. %s → %s\n", i+1, mr.Branch, mr.Target)
+		fmt.Printf("     ID: %s  Worker: %s\n", mr.ID, mr.Worker)
+	}
+	fmt.Println()
+
+	if refineryTrainDryRun {
+		return nil
+	}
+
+	// Claim every MR in the train so parallel workers skip them
+	workerID := getWorkerID()
+	var claimed []*refinery.MRInfo
+	for _, mr := range batch {
+		if err := eng.ClaimMR(mr.ID, workerID); err != nil {
+			fmt.Printf("  %s could not claim %s: %v\n", style.Warning.Render("⚠"), mr.ID, err)
+			continue
+		}
+		claimed = append(claimed, mr)
+	}
+
+	results := eng.ProcessBatch(context.Background(), claimed)
+	eng.HandleBatchResults(results)
+
+	var merged, failed, deferred int
+	for _, res := range results {
+		switch {
+		case res.Deferred:
+			deferred++
+		case res.Result.Success:
+			merged++
+		default:
+			failed++
+		}
+	}
+	fmt.Printf("\n%s Train complete: %d merged, %d failed, %d deferred\n",
+		style.Bold.Render("✓"), merged, failed, deferred)
+	return nil
+}
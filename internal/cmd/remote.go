@@ -0,0 +1,440 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/federation"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	remoteJSON     bool
+	remoteExportTo string
+)
+
+var remoteCmd = &cobra.Command{
+	Use:     "remote",
+	GroupID: GroupWork,
+	Short:   "Track work in other towns (hop:// federation)",
+	RunE:    requireSubcommand,
+	Long: `Manage the other towns this town can reference.
+
+Work in another town is named with a HOP URI:
+
+  hop://entity/chain/rig/issue-id
+  hop://steve@example.com/main-town/greenplace/gp-xyz
+
+The entity is the town's owner and the chain its name (mayor/town.json).
+A remote says where such a town can be read: the root of a town on this
+machine, or the URL or path of a status export it publishes with
+'gt remote export'.
+
+Convoys track remote issues by URI (gt convoy create/add). 'gt remote sync'
+pulls their status into federation/status.json; 'gt convoy status --sync'
+and 'gt convoy check' sync the legs they show.
+
+Examples:
+  gt remote add acme hop://acme/main-town ~/gt-acme
+  gt remote add partner hop://ops@partner.io/prod https://partner.io/gt/export.json
+  gt remote sync
+  gt remote export -o /srv/www/gt/export.json`,
+}
+
+var remoteAddCmd = &cobra.Command{
+	Use:   "add <name> <hop://entity/chain> <source>",
+	Short: "Register a remote town",
+	Long: `Register a remote town. Source is a town root on this machine, or the
+http(s) URL or file path of the town's status export.`,
+	Args: cobra.ExactArgs(3),
+	RunE: runRemoteAdd,
+}
+
+var remoteRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Unregister a remote town",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRemoteRemove,
+}
+
+var remoteListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List remote towns",
+	Args:  cobra.NoArgs,
+	RunE:  runRemoteList,
+}
+
+var remoteSyncCmd = &cobra.Command{
+	Use:   "sync [hop://...]...",
+	Short: "Pull the status of remote issues",
+	Long: `Pull the status of remote issues into the local cache.
+
+With no arguments, every remote leg of every open convoy is synced.`,
+	RunE: runRemoteSync,
+}
+
+var remoteShowCmd = &cobra.Command{
+	Use:   "show <hop://...>",
+	Short: "Show the last synced status of a remote issue",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRemoteShow,
+}
+
+var remoteExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write this town's status export for other towns",
+	Long: `Write a status snapshot of this town's issues (ID, title, status,
+assignee) for other towns to read as a remote. Mail, agent, role and rig
+beads and wisps are left out. Publish the file anywhere they can fetch it;
+rerun to refresh it.`,
+	Args: cobra.NoArgs,
+	RunE: runRemoteExport,
+}
+
+func init() {
+	remoteListCmd.Flags().BoolVar(&remoteJSON, "json", false, "Output as JSON")
+	remoteSyncCmd.Flags().BoolVar(&remoteJSON, "json", false, "Output as JSON")
+	remoteShowCmd.Flags().BoolVar(&remoteJSON, "json", false, "Output as JSON")
+	remoteExportCmd.Flags().StringVarP(&remoteExportTo, "output", "o", "", "File to write (default: stdout)")
+
+	remoteCmd.AddCommand(remoteAddCmd)
+	remoteCmd.AddCommand(remoteRemoveCmd)
+	remoteCmd.AddCommand(remoteListCmd)
+	remoteCmd.AddCommand(remoteSyncCmd)
+	remoteCmd.AddCommand(remoteShowCmd)
+	remoteCmd.AddCommand(remoteExportCmd)
+	rootCmd.AddCommand(remoteCmd)
+}
+
+func runRemoteAdd(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	entity, chain, err := federation.ParseTown(args[1])
+	if err != nil {
+		return err
+	}
+	if selfEntity, selfChain, err := federation.Identity(townRoot); err == nil && selfEntity == entity && selfChain == chain {
+		return fmt.Errorf("%s is this town; its issues are tracked directly", args[1])
+	}
+	source := args[2]
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		path, err := filepath.Abs(strings.TrimPrefix(source, "file://"))
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("source %s: %w", source, err)
+		}
+		source = path
+	}
+
+	remotes, err := federation.LoadRemotes(townRoot)
+	if err != nil {
+		return err
+	}
+	r := &federation.Remote{Name: args[0], Entity: entity, Chain: chain, Source: source}
+	if err := remotes.Add(r); err != nil {
+		return err
+	}
+	if err := remotes.Save(townRoot); err != nil {
+		return err
+	}
+	fmt.Printf("%s Added remote %s → %s\n", style.Success.Render("✓"), r.Name, r.Town())
+	fmt.Printf("    %s\n", style.Dim.Render(source))
+	return nil
+}
+
+func runRemoteRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	remotes, err := federation.LoadRemotes(townRoot)
+	if err != nil {
+		return err
+	}
+	if !remotes.Remove(args[0]) {
+		return fmt.Errorf("no remote named %s", args[0])
+	}
+	if err := remotes.Save(townRoot); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed remote %s\n", style.Success.Render("✓"), args[0])
+	return nil
+}
+
+func runRemoteList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	remotes, err := federation.LoadRemotes(townRoot)
+	if err != nil {
+		return err
+	}
+	if remoteJSON {
+		return printJSONIndent(remotes.Remotes)
+	}
+	if entity, chain, err := federation.Identity(townRoot); err == nil {
+		fmt.Printf("%s %s\n\n", style.Bold.Render("This town:"), federation.Scheme+entity+"/"+chain)
+	}
+	if len(remotes.Remotes) == 0 {
+		fmt.Println(style.Dim.Render("No remotes (gt remote add <name> <hop://entity/chain> <source>)"))
+		return nil
+	}
+	for _, r := range remotes.Remotes {
+		fmt.Printf("  %-16s %s\n", r.Name, r.Town())
+		fmt.Printf("  %-16s %s\n", "", style.Dim.Render(r.Source))
+	}
+	return nil
+}
+
+func runRemoteSync(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	var uris []federation.URI
+	if len(args) > 0 {
+		for _, arg := range args {
+			u, err := federation.ParseURI(arg)
+			if err != nil {
+				return err
+			}
+			uris = append(uris, u)
+		}
+	} else if uris, err = openConvoyRemoteLegs(filepath.Join(townRoot, ".beads")); err != nil {
+		return err
+	}
+
+	if len(uris) == 0 {
+		if !remoteJSON {
+			fmt.Println(style.Dim.Render("No remote issues to sync"))
+			return nil
+		}
+		return printJSONIndent([]*federation.Status{})
+	}
+	statuses, err := federation.Sync(townRoot, uris)
+	if err != nil {
+		return err
+	}
+	if remoteJSON {
+		return printJSONIndent(statuses)
+	}
+	failed := 0
+	for _, s := range statuses {
+		printRemoteLeg("", s)
+		if s.Error != "" {
+			failed++
+		}
+	}
+	fmt.Printf("\n%s Synced %d remote issue(s)", style.Success.Render("✓"), len(statuses)-failed)
+	if failed > 0 {
+		fmt.Printf(", %s", style.Warning.Render(fmt.Sprintf("%d failed", failed)))
+	}
+	fmt.Println()
+	return nil
+}
+
+func runRemoteShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	u, err := federation.ParseURI(args[0])
+	if err != nil {
+		return err
+	}
+	cache, err := federation.LoadCache(townRoot)
+	if err != nil {
+		return err
+	}
+	s := cache.Get(u)
+	if s == nil {
+		return fmt.Errorf("%s has not been synced (gt remote sync %s)", u, u)
+	}
+	if remoteJSON {
+		return printJSONIndent(s)
+	}
+	fmt.Printf("%s\n", style.Bold.Render(u.String()))
+	if s.Issue != nil {
+		fmt.Printf("  Title:    %s\n", s.Issue.Title)
+		fmt.Printf("  Status:   %s\n", s.Issue.Status)
+		if s.Issue.Assignee != "" {
+			fmt.Printf("  Assignee: %s\n", s.Issue.Assignee)
+		}
+		fmt.Printf("  Synced:   %s from %s\n", s.SyncedAt.Local().Format(time.DateTime), s.Remote)
+	}
+	if s.Error != "" {
+		fmt.Printf("  %s\n", style.Warning.Render("Last sync failed: "+s.Error))
+	}
+	return nil
+}
+
+func runRemoteExport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	entity, chain, err := federation.Identity(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading town identity: %w", err)
+	}
+
+	stores := map[string]beads.Store{"": beads.New(townRoot)}
+	routes, err := beads.LoadRoutes(filepath.Join(townRoot, ".beads"))
+	if err != nil {
+		return fmt.Errorf("loading routes: %w", err)
+	}
+	for _, r := range routes {
+		if r.Path == "." {
+			continue
+		}
+		rig := strings.SplitN(r.Path, "/", 2)[0]
+		if _, ok := stores[rig]; !ok {
+			stores[rig] = beads.New(filepath.Join(townRoot, r.Path))
+		}
+	}
+	exp, err := federation.BuildExport(entity, chain, stores)
+	if err != nil {
+		return err
+	}
+
+	if remoteExportTo == "" {
+		return printJSONIndent(exp)
+	}
+	if err := writeJSON(remoteExportTo, exp); err != nil {
+		return fmt.Errorf("writing export: %w", err)
+	}
+	fmt.Printf("%s Exported %d issues of %s%s/%s to %s\n", style.Success.Render("✓"), len(exp.Issues), federation.Scheme, entity, chain, remoteExportTo)
+	return nil
+}
+
+// splitConvoyTargets separates the issue IDs a convoy tracks directly from
+// the hop:// URIs of issues in other towns. URIs naming this town are
+// tracked directly by their issue ID.
+func splitConvoyTargets(townRoot string, args []string) (local []string, remote []federation.URI, err error) {
+	selfEntity, selfChain, _ := federation.Identity(townRoot)
+	for _, arg := range args {
+		if !federation.IsURI(arg) {
+			local = append(local, arg)
+			continue
+		}
+		u, err := federation.ParseURI(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		if u.Entity == selfEntity && u.Chain == selfChain {
+			local = append(local, u.Issue)
+			continue
+		}
+		remote = append(remote, u)
+	}
+	return local, remote, nil
+}
+
+// convoyRemoteLegs returns the status of each remote leg in a convoy
+// description, syncing them first if sync is set. Legs never synced have a
+// status with no issue.
+func convoyRemoteLegs(townBeads, description string, sync bool) []*federation.Status {
+	legs := convoy.ParseRemoteLegs(description)
+	if len(legs) == 0 {
+		return nil
+	}
+	townRoot := filepath.Dir(townBeads)
+	if sync {
+		statuses, err := federation.Sync(townRoot, legs)
+		if err == nil {
+			return statuses
+		}
+		style.PrintWarning("couldn't sync remote issues: %v", err)
+	}
+	cache, err := federation.LoadCache(townRoot)
+	if err != nil {
+		style.PrintWarning("%v", err)
+	}
+	statuses := make([]*federation.Status, len(legs))
+	for i, u := range legs {
+		if cache != nil {
+			statuses[i] = cache.Get(u)
+		}
+		if statuses[i] == nil {
+			statuses[i] = &federation.Status{URI: u.String()}
+		}
+	}
+	return statuses
+}
+
+// openConvoyRemoteLegs returns the remote legs of every open convoy.
+func openConvoyRemoteLegs(townBeads string) ([]federation.URI, error) {
+	listCmd := exec.Command("bd", "list", "--type=convoy", "--status=open", "--json")
+	listCmd.Dir = townBeads
+	var stdout bytes.Buffer
+	listCmd.Stdout = &stdout
+	if err := listCmd.Run(); err != nil {
+		return nil, fmt.Errorf("listing convoys: %w", err)
+	}
+	var convoys []struct {
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
+		return nil, fmt.Errorf("parsing convoy list: %w", err)
+	}
+	var refs []string
+	for _, c := range convoys {
+		for _, u := range convoy.ParseRemoteLegs(c.Description) {
+			refs = append(refs, u.String())
+		}
+	}
+	return federation.URIs(refs), nil
+}
+
+// printRemoteLeg prints one remote issue line in the convoy status style.
+func printRemoteLeg(indent string, s *federation.Status) {
+	if s.Issue == nil {
+		reason := "not synced"
+		if s.Error != "" {
+			reason = s.Error
+		}
+		fmt.Printf("%s? %s  %s\n", indent, s.URI, style.Warning.Render(reason))
+		return
+	}
+	symbol := "○"
+	switch s.Issue.Status {
+	case "closed", "tombstone":
+		symbol = "✓"
+	case "in_progress", "hooked":
+		symbol = "▶"
+	}
+	bracket := "unassigned"
+	if s.Issue.Assignee != "" {
+		parts := strings.Split(s.Issue.Assignee, "/")
+		bracket = parts[len(parts)-1]
+	}
+	line := fmt.Sprintf("%s%s %s: %s [%s]", indent, symbol, s.URI, s.Issue.Title, bracket)
+	note := "synced " + formatWorkerAge(time.Since(s.SyncedAt)) + " ago"
+	if s.Error != "" {
+		note += "; last sync failed"
+	}
+	fmt.Printf("%s  %s\n", line, style.Dim.Render(note))
+}
+
+// withRemoteLegs returns a convoy description that also tracks uris.
+func withRemoteLegs(description string, uris []federation.URI) string {
+	if len(uris) == 0 {
+		return description
+	}
+	return convoy.AddRemoteLegs(description, uris)
+}
@@ -29,6 +29,7 @@ Rig settings control behavioral configuration for a rig:
 - Namepool settings
 - Crew startup settings
 - Workflow settings
+- Cost budget (budget.daily_usd, budget.total_usd, budget.warn_percent)
 
 Settings are stored in settings/config.json within each rig directory.
 Use dot notation to access nested keys (e.g., role_agents.witness).`,
@@ -65,7 +66,8 @@ Examples:
   gt rig settings set gastown agent claude
   gt rig settings set gastown role_agents.witness gemini
   gt rig settings set gastown merge_queue.max_concurrent 5
-  gt rig settings set gastown theme.background_color "#000000"`,
+  gt rig settings set gastown theme.background_color "#000000"
+  gt rig settings set gastown budget.daily_usd 50`,
 	Args: cobra.ExactArgs(3),
 	RunE: runRigSettingsSet,
 }
@@ -329,7 +331,7 @@ func setNestedValue(obj interface{}, keyPath string, value interface{}) error {
 				validKeys := []string{
 					"type", "version",
 					"merge_queue", "theme", "namepool", "crew", "workflow",
-					"runtime", "agent", "agents", "role_agents",
+					"runtime", "agent", "agents", "role_agents", "budget",
 				}
 				return fmt.Errorf("unknown key %q (valid top-level keys: %s)", keyPath, strings.Join(validKeys, ", "))
 			}
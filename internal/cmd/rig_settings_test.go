@@ -191,6 +191,24 @@ func TestRigSettingsSet(t *testing.T) {
 		}
 	})
 
+	t.Run("sets budget caps", func(t *testing.T) {
+		townRoot, rigName := setupTestRigForSettings(t)
+		rigPath := filepath.Join(townRoot, rigName)
+
+		cmd := rigSettingsSetCmd
+		if err := runRigSettingsSet(cmd, []string{rigName, "budget.daily_usd", "50"}); err != nil {
+			t.Fatalf("runRigSettingsSet error: %v", err)
+		}
+
+		settings, err := config.LoadRigSettings(filepath.Join(rigPath, "settings", "config.json"))
+		if err != nil {
+			t.Fatalf("load settings: %v", err)
+		}
+		if settings.Budget == nil || settings.Budget.DailyUSD != 50 {
+			t.Errorf("Budget = %+v, want daily_usd 50", settings.Budget)
+		}
+	})
+
 	t.Run("sets nested keys with dot notation", func(t *testing.T) {
 		townRoot, rigName := setupTestRigForSettings(t)
 		rigPath := filepath.Join(townRoot, rigName)
@@ -58,6 +58,7 @@ var beadsExemptCommands = map[string]bool{
 	"feed":       true,
 	"rig":        true,
 	"config":     true,
+	"machine":    true,
 	"install":    true,
 	"tap":        true,
 	"dnd":        true,
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/transcript"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+// Transcript command flags
+var (
+	recordTown      string
+	recordCols      int
+	recordRows      int
+	replayAt        string
+	replayBead      string
+	replaySpeed     float64
+	replayInstant   bool
+	transcriptsBead string
+	transcriptsJSON bool
+)
+
+var sessionRecordCmd = &cobra.Command{
+	Use:    "record <session>",
+	Short:  "Record pane output from stdin (used by tmux pipe-pane)",
+	Hidden: true, // Internal command run by tmux pipe-pane
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return transcript.Record(recordTown, args[0], recordCols, recordRows, os.Stdin)
+	},
+}
+
+var sessionReplayCmd = &cobra.Command{
+	Use:   "replay [<agent>]",
+	Short: "Replay a recorded agent session",
+	Long: `Replay an agent session's recorded terminal output.
+
+Every polecat, witness, refinery, deacon and mayor session's pane output
+is recorded while it runs, so the session can be replayed after it is
+gone. Agents are named <rig>/<polecat>, <rig>/witness, <rig>/refinery,
+deacon or mayor. Recordings are kept
+for the krc "transcript" TTL (see 'gt krc config').
+
+By default the latest recording plays from the start. --at picks the
+recording running at a time and fast-forwards to it; --bead plays the
+session that worked on a bead, from when the bead was hooked.
+
+--at accepts RFC3339, "2006-01-02 15:04", "15:04" (today), or a duration
+ago such as 30m or 2d.
+
+Examples:
+  gt session replay gastown/Toast                 # Latest recording
+  gt session replay gastown/Toast --at 14:30      # As of 14:30 today
+  gt session replay gastown/Toast --at 2h --speed 4
+  gt session replay --bead gt-abc12               # Work on a bead
+  gt session replay gastown/Toast --instant | less -R
+  gt session replay gastown/refinery --at 1h      # A rig's refinery
+  gt session replay mayor`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSessionReplay,
+}
+
+var sessionTranscriptsCmd = &cobra.Command{
+	Use:   "transcripts [<agent>]",
+	Short: "List recorded session transcripts",
+	Long: `List recorded session transcripts.
+
+With --bead, searches the transcript index for the sessions that worked
+on a bead.
+
+Examples:
+  gt session transcripts                  # All recordings
+  gt session transcripts gastown/Toast    # One polecat
+  gt session transcripts deacon           # One town agent
+  gt session transcripts --bead gt-abc12  # Sessions that worked on a bead`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSessionTranscripts,
+}
+
+func init() {
+	sessionRecordCmd.Flags().StringVar(&recordTown, "town", "", "Town root")
+	sessionRecordCmd.Flags().IntVar(&recordCols, "cols", 80, "Pane width")
+	sessionRecordCmd.Flags().IntVar(&recordRows, "rows", 24, "Pane height")
+	_ = sessionRecordCmd.MarkFlagRequired("town")
+
+	sessionReplayCmd.Flags().StringVar(&replayAt, "at", "", "Replay the session as of this time")
+	sessionReplayCmd.Flags().StringVar(&replayBead, "bead", "", "Replay the session that worked on this bead")
+	sessionReplayCmd.Flags().Float64Var(&replaySpeed, "speed", 1, "Playback speed multiplier")
+	sessionReplayCmd.Flags().BoolVar(&replayInstant, "instant", false, "Print the output without pauses")
+
+	sessionTranscriptsCmd.Flags().StringVar(&transcriptsBead, "bead", "", "Find transcripts for a bead")
+	sessionTranscriptsCmd.Flags().BoolVar(&transcriptsJSON, "json", false, "Output as JSON")
+
+	sessionCmd.AddCommand(sessionRecordCmd)
+	sessionCmd.AddCommand(sessionReplayCmd)
+	sessionCmd.AddCommand(sessionTranscriptsCmd)
+}
+
+func runSessionReplay(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var rec transcript.Recording
+	var from time.Time
+	switch {
+	case replayBead != "":
+		matches, err := transcript.ForBead(townRoot, replayBead)
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("%w for bead %s", transcript.ErrNoRecording, replayBead)
+		}
+		// The latest hook of the bead is the one most likely wanted
+		m := matches[len(matches)-1]
+		rec, from = m.Recording, m.Entry.At
+	case len(args) == 1:
+		sessionName, err := transcriptSessionName(args[0])
+		if err != nil {
+			return err
+		}
+		if replayAt != "" {
+			if from, err = parseReplayTime(replayAt, time.Now()); err != nil {
+				return err
+			}
+		}
+		rec, err = transcript.Find(townRoot, sessionName, from)
+		if err != nil {
+			return err
+		}
+		if rec.Started.After(from) {
+			from = time.Time{} // Nothing recorded at --at; play the nearest from its start
+		}
+	default:
+		return errors.New("specify an agent (<rig>/<polecat>, <rig>/witness, deacon, ...) or --bead")
+	}
+
+	opts := transcript.PlayOptions{
+		From:    from,
+		Speed:   replaySpeed,
+		Instant: replayInstant || !term.IsTerminal(int(os.Stdout.Fd())),
+	}
+	if err := transcript.Play(rec.Path, os.Stdout, opts); err != nil {
+		return fmt.Errorf("replaying %s: %w", rec.Path, err)
+	}
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		// Leave the terminal usable whatever state the agent left it in
+		fmt.Print("\x1b[0m\x1b[?25h\r\n")
+		fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("[end of %s recording from %s]",
+			rec.Session, rec.Started.Local().Format("2006-01-02 15:04:05"))))
+	}
+	return nil
+}
+
+// transcriptSessionName returns the session recorded for an agent address:
+// mayor, deacon, <rig>/witness, <rig>/refinery or <rig>/<polecat>.
+func transcriptSessionName(addr string) (string, error) {
+	switch strings.TrimSuffix(addr, "/") {
+	case "mayor":
+		return session.MayorSessionName(), nil
+	case "deacon":
+		return session.DeaconSessionName(), nil
+	}
+	rigName, name, err := parseAddress(addr)
+	if err != nil {
+		return "", err
+	}
+	switch name {
+	case "witness":
+		return session.WitnessSessionName(rigName), nil
+	case "refinery":
+		return session.RefinerySessionName(rigName), nil
+	}
+	return session.PolecatSessionName(rigName, name), nil
+}
+
+// parseReplayTime parses a time flag (--at, --since): an absolute time, a
+// time of day, or a duration before now.
+func parseReplayTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("15:04", s, time.Local); err == nil {
+		y, m, d := now.Date()
+		return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, time.Local), nil
+	}
+	if d, err := parseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use RFC3339, \"2006-01-02 15:04\", \"15:04\", or a duration like 2h", s)
+}
+
+func runSessionTranscripts(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if transcriptsBead != "" {
+		matches, err := transcript.ForBead(townRoot, transcriptsBead)
+		if err != nil {
+			return err
+		}
+		if transcriptsJSON {
+			return printJSONIndent(matches)
+		}
+		if len(matches) == 0 {
+			fmt.Printf("No transcripts for %s.\n", transcriptsBead)
+			return nil
+		}
+		fmt.Printf("%s\n\n", style.Bold.Render("Transcripts for "+transcriptsBead))
+		for _, m := range matches {
+			fmt.Printf("  %s  %s\n", m.Entry.At.Local().Format("2006-01-02 15:04"), m.Entry.Agent)
+			fmt.Printf("    %s\n", style.Dim.Render(m.Recording.Path))
+		}
+		return nil
+	}
+
+	sessionName := ""
+	if len(args) == 1 {
+		if sessionName, err = transcriptSessionName(args[0]); err != nil {
+			return err
+		}
+	}
+	recs, err := transcript.List(townRoot, sessionName)
+	if err != nil {
+		return err
+	}
+	if transcriptsJSON {
+		return printJSONIndent(recs)
+	}
+	if len(recs) == 0 {
+		fmt.Println("No transcripts recorded.")
+		return nil
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render("Session Transcripts"))
+	for _, r := range recs {
+		fmt.Printf("  %-28s %s – %s  %s\n", r.Session,
+			r.Started.Local().Format("2006-01-02 15:04"),
+			r.Ended.Local().Format("15:04"),
+			style.Dim.Render(formatBytes(r.Size)))
+	}
+	return nil
+}
+
+func printJSONIndent(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
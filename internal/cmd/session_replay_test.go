@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseReplayTime(t *testing.T) {
+	now := time.Date(2026, 3, 4, 18, 0, 0, 0, time.Local)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-03-01T10:00:00Z", time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{"2026-03-02 09:30", time.Date(2026, 3, 2, 9, 30, 0, 0, time.Local)},
+		{"14:30", time.Date(2026, 3, 4, 14, 30, 0, 0, time.Local)},
+		{"2h", now.Add(-2 * time.Hour)},
+		{"2d", now.Add(-48 * time.Hour)},
+	}
+	for _, tt := range tests {
+		got, err := parseReplayTime(tt.in, now)
+		if err != nil {
+			t.Errorf("parseReplayTime(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseReplayTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	if _, err := parseReplayTime("yesterday", now); err == nil {
+		t.Error("parseReplayTime(yesterday) should fail")
+	}
+}
+
+func TestTranscriptSessionName(t *testing.T) {
+	tests := map[string]string{
+		"mayor":            "hq-mayor",
+		"deacon/":          "hq-deacon",
+		"gastown/witness":  "gt-gastown-witness",
+		"gastown/refinery": "gt-gastown-refinery",
+		"gastown/Toast":    "gt-gastown-Toast",
+	}
+	for addr, want := range tests {
+		got, err := transcriptSessionName(addr)
+		if err != nil || got != want {
+			t.Errorf("transcriptSessionName(%q) = %q, %v; want %q", addr, got, err, want)
+		}
+	}
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/hooklog"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -325,15 +327,16 @@ func runSling(cmd *cobra.Command, args []string) error {
 			// otherwise create cleanup wisp for manual intervention
 			if townRoot != "" {
 				router := mail.NewRouter(townRoot)
-				shutdownMsg := &mail.Message{
-					From:     "gt-sling",
-					To:       fmt.Sprintf("%s/witness", oldRigName),
-					Subject:  fmt.Sprintf("LIFECYCLE:Shutdown %s", oldPolecatName),
-					Body:     fmt.Sprintf("Reason: work_reassigned\nRequestedBy: %s\nBead: %s\nNewAssignee: %s", requester, beadID, targetAgent),
-					Type:     mail.TypeTask,
-					Priority: mail.PriorityHigh,
-				}
-				if err := router.Send(shutdownMsg); err != nil {
+				shutdownMsg := protocol.NewMessage("gt-sling", fmt.Sprintf("%s/witness", oldRigName), &protocol.LifecycleShutdownPayload{
+					Polecat:     oldPolecatName,
+					Reason:      "work_reassigned",
+					RequestedBy: requester,
+					Bead:        beadID,
+					NewAssignee: targetAgent,
+				})
+				shutdownMsg.Type = mail.TypeTask
+				shutdownMsg.Priority = mail.PriorityHigh
+				if err := protocol.Send(router, shutdownMsg); err != nil {
 					fmt.Printf("%s Could not send shutdown to witness: %v\n", style.Dim.Render("Warning:"), err)
 				} else {
 					fmt.Printf("%s Sent LIFECYCLE:Shutdown to %s/witness for %s\n", style.Bold.Render("→"), oldRigName, oldPolecatName)
@@ -444,7 +447,27 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 	// Log sling event to activity feed
 	actor := detectActor()
-	_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadID, targetAgent))
+	payload := events.SlingPayload(beadID, targetAgent)
+	if formulaName != "" {
+		payload["formula"] = formulaName
+	}
+	_ = events.LogFeed(events.TypeSling, actor, payload)
+
+	var previousAgent, slingReason string
+	if info.Status == "hooked" || info.Status == "pinned" {
+		previousAgent = info.Assignee
+		slingReason = "re-slung (--force)"
+	}
+	recordHookActivity(hooklog.Entry{
+		Action:   hooklog.ActionAttach,
+		Command:  "gt sling",
+		Actor:    actor,
+		Agent:    targetAgent,
+		Bead:     beadID,
+		Molecule: attachedMoleculeID,
+		Previous: previousAgent,
+		Reason:   slingReason,
+	})
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Skip if hook was already set atomically during polecat spawn - avoids "agent bead not found"
@@ -133,7 +133,8 @@ exit /b 0
 	}
 	logContent := string(logBytes)
 
-	if !strings.Contains(logContent, "cook mol-polecat-work") {
+	// bd cooks the copy gt resolved (here the embedded one), not its own lookup
+	if !strings.Contains(logContent, "/mol-polecat-work.formula.toml") {
 		t.Errorf("cook command not found in log:\n%s", logContent)
 	}
 	if !strings.Contains(logContent, "mol wisp mol-polecat-work") {
@@ -255,14 +256,23 @@ exit /b 0
 	t.Setenv("BD_LOG", logPath)
 	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
 
+	// A town copy shadows the embedded formula and is the file bd cooks
+	townFormula := filepath.Join(townRoot, ".beads", "formulas", "mol-polecat-work.formula.toml")
+	if err := os.MkdirAll(filepath.Dir(townFormula), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(townFormula, []byte("formula = \"mol-polecat-work\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
 	err := CookFormula("mol-polecat-work", townRoot, townRoot)
 	if err != nil {
 		t.Fatalf("CookFormula failed: %v", err)
 	}
 
 	logBytes, _ := os.ReadFile(logPath)
-	if !strings.Contains(string(logBytes), "cook mol-polecat-work") {
-		t.Errorf("cook command not found in log")
+	if !strings.Contains(string(logBytes), "cook "+townFormula) {
+		t.Errorf("cook of %s not found in log:\n%s", townFormula, logBytes)
 	}
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/hooklog"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
@@ -173,6 +174,15 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		// Log sling event
 		actor := detectActor()
 		_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadToHook, targetAgent))
+		recordHookActivity(hooklog.Entry{
+			Action:   hooklog.ActionAttach,
+			Command:  "gt sling",
+			Actor:    actor,
+			Agent:    targetAgent,
+			Bead:     beadToHook,
+			Molecule: attachedMoleculeID,
+			Reason:   "batch sling",
+		})
 
 		// Update agent bead state
 		updateAgentHookBead(targetAgent, beadToHook, hookWorkDir, townBeadsDir)
@@ -11,6 +11,8 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/hooklog"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -49,10 +51,19 @@ func trimJSONForError(jsonOutput []byte) string {
 	return s
 }
 
-// verifyFormulaExists checks that the formula exists using bd formula show.
+// verifyFormulaExists checks that the formula exists, first in the
+// project/town/system tiers and then via bd formula show.
 // Formulas are TOML files (.formula.toml).
 // Uses --allow-stale for consistency with verifyBeadExists.
 func verifyFormulaExists(formulaName string) error {
+	if cwd, err := os.Getwd(); err == nil {
+		for _, name := range []string{formulaName, "mol-" + formulaName} {
+			if _, err := formula.ResolveFormula(name, cwd); err == nil {
+				return nil
+			}
+		}
+	}
+
 	// Try bd formula show (handles all formula file formats)
 	// Use Output() instead of Run() to detect bd exit 0 bug:
 	// when formula not found, bd may exit 0 but produce empty stdout.
@@ -70,6 +81,56 @@ func verifyFormulaExists(formulaName string) error {
 	return fmt.Errorf("formula '%s' not found (check 'bd formula list')", formulaName)
 }
 
+// formulaCookTarget resolves formulaName from dir and returns what to pass
+// to bd cook: the path of the winning copy, so bd cooks the formula gt
+// resolved instead of running its own lookup. An embedded formula is written
+// to a temporary file that cleanup removes; a name gt cannot resolve is
+// returned as is for bd to find. The resolution is printed, so drift between
+// project, town, user and embedded copies is visible when work is dispatched.
+func formulaCookTarget(formulaName, dir string) (target string, cleanup func(), err error) {
+	cleanup = func() {}
+	res, err := formula.ResolveFormula(formulaName, dir)
+	if err != nil {
+		return formulaName, cleanup, nil
+	}
+	printFormulaCookResolution(res)
+	if res.Winner.Tier != formula.TierSystem {
+		return res.Winner.Path, cleanup, nil
+	}
+
+	data, err := res.Read()
+	if err != nil {
+		return "", cleanup, fmt.Errorf("reading embedded formula %s: %w", res.Name, err)
+	}
+	tmpDir, err := os.MkdirTemp("", "gt-formula-*")
+	if err != nil {
+		return "", cleanup, err
+	}
+	cleanup = func() { _ = os.RemoveAll(tmpDir) }
+	path := filepath.Join(tmpDir, res.Name+formula.FormulaExt)
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: formula is not secret
+		cleanup()
+		return "", func() {}, err
+	}
+	return path, cleanup, nil
+}
+
+// printFormulaCookResolution reports which copy of a formula is being slung.
+func printFormulaCookResolution(res *formula.Resolution) {
+	line := fmt.Sprintf("  Formula %s: [%s] %s", res.Name, res.Winner.Tier, res.Winner.Path)
+	if len(res.Shadowed) > 0 {
+		var tiers []string
+		for _, c := range res.Shadowed {
+			tiers = append(tiers, string(c.Tier))
+		}
+		line += " (shadows " + strings.Join(tiers, ", ") + ")"
+	}
+	fmt.Println(style.Dim.Render(line))
+	for _, w := range res.Warnings {
+		style.PrintWarning("%s", w)
+	}
+}
+
 // runSlingFormula handles standalone formula slinging.
 // Flow: cook → wisp → attach to hook → nudge
 func runSlingFormula(args []string) error {
@@ -126,11 +187,7 @@ func runSlingFormula(args []string) error {
 
 	// Step 1: Cook the formula (ensures proto exists)
 	fmt.Printf("  Cooking formula...\n")
-	cookArgs := []string{"cook", formulaName}
-	cookCmd := exec.Command("bd", cookArgs...)
-	cookCmd.Dir = formulaWorkDir
-	cookCmd.Stderr = os.Stderr
-	if err := cookCmd.Run(); err != nil {
+	if err := CookFormula(formulaName, formulaWorkDir, townRoot); err != nil {
 		return fmt.Errorf("cooking formula: %w", err)
 	}
 
@@ -158,6 +215,11 @@ func runSlingFormula(args []string) error {
 
 	fmt.Printf("%s Wisp created: %s\n", style.Bold.Render("✓"), wispRootID)
 
+	// Apply step timeouts/retries/on_failure and skip steps whose when is false
+	if err := applyStepPolicies(formulaName, wispRootID, formulaWorkDir, slingVars); err != nil {
+		fmt.Printf("%s Could not apply step policies: %v\n", style.Dim.Render("Warning:"), err)
+	}
+
 	// Step 3: Hook the wisp bead with retry and verification.
 	// See: https://github.com/steveyegge/gastown/issues/148
 	hookDir := beads.ResolveHookDir(townRoot, wispRootID, "")
@@ -171,6 +233,15 @@ func runSlingFormula(args []string) error {
 	payload := events.SlingPayload(wispRootID, targetAgent)
 	payload["formula"] = formulaName
 	_ = events.LogFeed(events.TypeSling, actor, payload)
+	recordHookActivity(hooklog.Entry{
+		Action:   hooklog.ActionAttach,
+		Command:  "gt sling",
+		Actor:    actor,
+		Agent:    targetAgent,
+		Bead:     wispRootID,
+		Molecule: wispRootID,
+		Reason:   "formula " + formulaName,
+	})
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Note: formula slinging uses town root as workDir (no polecat-specific path)
@@ -469,11 +469,7 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 
 	// Step 1: Cook the formula (ensures proto exists)
 	if !skipCook {
-		cookCmd := exec.Command("bd", "cook", formulaName)
-		cookCmd.Dir = formulaWorkDir
-		cookCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
-		cookCmd.Stderr = os.Stderr
-		if err := cookCmd.Run(); err != nil {
+		if err := CookFormula(formulaName, formulaWorkDir, townRoot); err != nil {
 			return nil, fmt.Errorf("cooking formula %s: %w", formulaName, err)
 		}
 	}
@@ -501,6 +497,12 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 		return nil, fmt.Errorf("parsing wisp output: %w", err)
 	}
 
+	// Apply step timeouts/retries/on_failure and skip steps whose when is false
+	policyVars := append([]string{featureVar, issueVar}, extraVars...)
+	if err := applyStepPolicies(formulaName, wispRootID, formulaWorkDir, policyVars); err != nil {
+		fmt.Printf("%s Could not apply step policies: %v\n", style.Dim.Render("Warning:"), err)
+	}
+
 	// Step 3: Bond wisp to original bead (creates compound)
 	bondArgs := []string{"mol", "bond", wispRootID, beadID, "--json"}
 	bondCmd := exec.Command("bd", bondArgs...)
@@ -527,9 +529,16 @@ func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot
 
 // CookFormula cooks a formula to ensure its proto exists.
 // This is useful for batch mode where we cook once before processing multiple beads.
-// townRoot is required for GT_ROOT so bd can find town-level formulas.
+// bd cooks the copy gt resolves from workDir (see formulaCookTarget); townRoot
+// is passed as GT_ROOT for formulas only bd can find.
 func CookFormula(formulaName, workDir, townRoot string) error {
-	cookCmd := exec.Command("bd", "cook", formulaName)
+	target, cleanup, err := formulaCookTarget(formulaName, workDir)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	cookCmd := exec.Command("bd", "cook", target)
 	cookCmd.Dir = workDir
 	cookCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
 	cookCmd.Stderr = os.Stderr
@@ -147,9 +147,8 @@ func runSynthesisStart(cmd *cobra.Command, args []string) error {
 		}
 	} else if meta.Formula != "" {
 		// Try to find formula by name
-		formulaPath, findErr := findFormula(meta.Formula)
-		if findErr == nil {
-			f, err = formula.ParseFile(formulaPath)
+		if res, findErr := resolveFormulaFromCwd(meta.Formula); findErr == nil {
+			f, err = res.Load()
 			if err != nil {
 				return fmt.Errorf("loading formula: %w", err)
 			}
@@ -253,8 +252,8 @@ func runSynthesisStatus(cmd *cobra.Command, args []string) error {
 	if meta.FormulaPath != "" {
 		f, _ = formula.ParseFile(meta.FormulaPath)
 	} else if meta.Formula != "" {
-		if path, err := findFormula(meta.Formula); err == nil {
-			f, _ = formula.ParseFile(path)
+		if res, err := resolveFormulaFromCwd(meta.Formula); err == nil {
+			f, _ = res.Load()
 		}
 	}
 
@@ -584,41 +583,6 @@ func slingSynthesis(beadID, targetRig string) error {
 	return slingCmd.Run()
 }
 
-// findFormula searches for a formula file by name.
-func findFormula(name string) (string, error) {
-	// Search paths
-	searchPaths := []string{
-		".beads/formulas",
-	}
-
-	// Add home directory formulas
-	if home, err := os.UserHomeDir(); err == nil {
-		searchPaths = append(searchPaths, filepath.Join(home, ".beads", "formulas"))
-	}
-
-	// Add GT_ROOT formulas if set
-	if gtRoot := os.Getenv("GT_ROOT"); gtRoot != "" {
-		searchPaths = append(searchPaths, filepath.Join(gtRoot, ".beads", "formulas"))
-	}
-
-	// Try each search path
-	for _, searchPath := range searchPaths {
-		// Try with .formula.toml extension
-		path := filepath.Join(searchPath, name+".formula.toml")
-		if _, err := os.Stat(path); err == nil {
-			return path, nil
-		}
-
-		// Try with .formula.json extension
-		path = filepath.Join(searchPath, name+".formula.json")
-		if _, err := os.Stat(path); err == nil {
-			return path, nil
-		}
-	}
-
-	return "", fmt.Errorf("formula '%s' not found", name)
-}
-
 // CheckSynthesisReady checks if a convoy is ready for synthesis.
 // Returns true if all tracked legs are complete.
 func CheckSynthesisReady(convoyID string) (bool, error) {
@@ -656,8 +620,8 @@ func TriggerSynthesisIfReady(convoyID, targetRig string) error {
 	if meta.FormulaPath != "" {
 		f, _ = formula.ParseFile(meta.FormulaPath)
 	} else if meta.Formula != "" {
-		if path, err := findFormula(meta.Formula); err == nil {
-			f, _ = formula.ParseFile(path)
+		if res, err := resolveFormulaFromCwd(meta.Formula); err == nil {
+			f, _ = res.Load()
 		}
 	}
 
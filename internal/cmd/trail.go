@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/hooklog"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -19,6 +20,8 @@ var (
 	trailLimit int
 	trailJSON  bool
 	trailAll   bool
+	trailActor string
+	trailRig   string
 )
 
 var trailCmd = &cobra.Command{
@@ -85,10 +88,17 @@ var trailHooksCmd = &cobra.Command{
 	Short: "Show recent hook activity",
 	Long: `Show recent hook activity (agents taking or dropping hooks).
 
+Every attach, detach and clear made by gt hook, gt sling, gt unsling,
+gt handoff and gt mol attach/detach is recorded in logs/hooks.jsonl under
+the town root. Each entry names the actor, the hook's agent, the bead and
+molecule, what the hook held before, and why.
+
 Examples:
-  gt trail hooks              # Recent hook activity
-  gt trail hooks --since 1h   # Last hour of hook activity
-  gt trail hooks --json       # JSON output`,
+  gt trail hooks                        # Recent hook activity
+  gt trail hooks --since 1h             # Last hour of hook activity
+  gt trail hooks --actor mayor          # Changes made by (or to) the mayor
+  gt trail hooks --rig gastown          # Hooks of gastown agents
+  gt trail hooks --json                 # JSON output`,
 	RunE: runTrailHooks,
 }
 
@@ -98,6 +108,8 @@ func init() {
 	trailCmd.PersistentFlags().IntVar(&trailLimit, "limit", 20, "Maximum number of items to show")
 	trailCmd.PersistentFlags().BoolVar(&trailJSON, "json", false, "Output as JSON")
 	trailCmd.PersistentFlags().BoolVar(&trailAll, "all", false, "Include all activity (not just agents)")
+	trailHooksCmd.Flags().StringVar(&trailActor, "actor", "", "Only show changes by or to this agent (prefix match)")
+	trailHooksCmd.Flags().StringVar(&trailRig, "rig", "", "Only show hooks of agents in this rig")
 
 	// Add subcommands
 	trailCmd.AddCommand(trailCommitsCmd)
@@ -329,22 +341,88 @@ func runTrailBeadsSimple(beadsDir string) error {
 	return beadsCmd.Run()
 }
 
+// HookEntry represents a hook activity log entry for output.
+type HookEntry struct {
+	hooklog.Entry
+	TimeRel string `json:"time_relative"`
+}
+
 func runTrailHooks(cmd *cobra.Command, args []string) error {
-	// For now, show current hooks status since we don't have hook history
-	// TODO: Implement hook activity log with HookEntry tracking
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return fmt.Errorf("not in a Gas Town workspace")
+	}
+
+	filter := hooklog.Filter{
+		Actor: strings.TrimSuffix(trailActor, "/"),
+		Rig:   trailRig,
+		Limit: trailLimit,
+	}
+	if trailSince != "" {
+		duration, err := parseDuration(trailSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since value: %w", err)
+		}
+		filter.Since = time.Now().Add(-duration)
+	}
+
+	logEntries, err := hooklog.New(townRoot).Read(filter)
+	if err != nil {
+		return err
+	}
+
+	// Newest first, matching the other trail views
+	hooks := make([]HookEntry, 0, len(logEntries))
+	for i := len(logEntries) - 1; i >= 0; i-- {
+		hooks = append(hooks, HookEntry{Entry: logEntries[i], TimeRel: relativeTime(logEntries[i].Timestamp)})
+	}
 
 	if trailJSON {
-		// Return empty array for now
-		fmt.Println("[]")
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(hooks)
+	}
+
+	// Text output
+	if len(hooks) == 0 {
+		fmt.Println("No hook activity found")
 		return nil
 	}
 
 	fmt.Printf("%s\n\n", style.Bold.Render("Hook Activity"))
-	fmt.Printf("%s Hook activity tracking not yet implemented.\n", style.Dim.Render("Note:"))
-	fmt.Printf("%s Showing current hook status instead.\n\n", style.Dim.Render("     "))
+	for _, h := range hooks {
+		actionColor := style.Dim
+		switch h.Action {
+		case hooklog.ActionAttach:
+			actionColor = style.Success
+		case hooklog.ActionDetach:
+			actionColor = style.Warning
+		}
 
-	// Call the internal hook show function directly instead of spawning subprocess
-	return runHookShow(cmd, nil)
+		target := h.Bead
+		if h.Molecule != "" && h.Molecule != h.Bead {
+			target += " (molecule " + h.Molecule + ")"
+		}
+		fmt.Printf("%s %s", actionColor.Render(fmt.Sprintf("%-6s", h.Action)), style.Bold.Render(target))
+		if h.Agent != "" {
+			fmt.Printf(" → %s", h.Agent)
+		}
+		fmt.Println()
+
+		fmt.Printf("    %s by %s", style.Dim.Render(h.TimeRel), h.Actor)
+		if h.Command != "" {
+			fmt.Printf(" via %s", h.Command)
+		}
+		if h.Previous != "" {
+			fmt.Printf(", was %s", h.Previous)
+		}
+		if h.Reason != "" {
+			fmt.Printf(" %s", style.Dim.Render("("+h.Reason+")"))
+		}
+		fmt.Println()
+	}
+
+	return nil
 }
 
 func findBeadsDir() (string, error) {
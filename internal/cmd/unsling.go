@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/hooklog"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -234,6 +235,17 @@ func runUnslingWith(cmd *cobra.Command, args []string, dryRun, force bool) error
 
 	// Log unhook event
 	_ = events.LogFeed(events.TypeUnhook, agentID, events.UnhookPayload(hookedBeadID))
+	reason := "work complete"
+	if !isComplete {
+		reason = "forced: work incomplete"
+	}
+	recordHookActivity(hooklog.Entry{
+		Action:  unslingAction(cmd),
+		Command: cmd.CommandPath(),
+		Agent:   agentID,
+		Bead:    hookedBeadID,
+		Reason:  reason,
+	})
 
 	fmt.Printf("%s Work removed from hook\n", style.Bold.Render("✓"))
 	fmt.Printf("  Agent %s hook cleared (was: %s)\n", agentID, hookedBeadID)
@@ -300,10 +312,26 @@ func cleanStaleHookedBeads(cmd *cobra.Command, b *beads.Beads, agentID, targetBe
 			continue
 		}
 		fmt.Printf("%s Cleaned up stale bead %s (was hooked, now open)\n", style.Bold.Render("✓"), sb.ID)
+		recordHookActivity(hooklog.Entry{
+			Action:  hooklog.ActionClear,
+			Command: cmd.CommandPath(),
+			Agent:   agentID,
+			Bead:    sb.ID,
+			Reason:  "stale hook (agent hook_bead already empty)",
+		})
 	}
 	return true
 }
 
+// unslingAction returns the hook log action for an unsling: "clear" when run
+// as gt hook clear, "detach" for gt unsling / gt unhook.
+func unslingAction(cmd *cobra.Command) string {
+	if cmd.Name() == "clear" {
+		return hooklog.ActionClear
+	}
+	return hooklog.ActionDetach
+}
+
 // isAgentTarget checks if a string looks like an agent target rather than a bead ID.
 // Agent targets contain "/" or are known role names.
 func isAgentTarget(s string) bool {
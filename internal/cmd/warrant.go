@@ -1,17 +1,16 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/warrant"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -24,15 +23,7 @@ var (
 )
 
 // Warrant represents a death warrant for an agent
-type Warrant struct {
-	ID        string    `json:"id"`
-	Target    string    `json:"target"`    // e.g., "gastown/polecats/alpha", "deacon/dogs/bravo"
-	Reason    string    `json:"reason"`
-	FiledBy   string    `json:"filed_by"`
-	FiledAt   time.Time `json:"filed_at"`
-	Executed  bool      `json:"executed,omitempty"`
-	ExecutedAt *time.Time `json:"executed_at,omitempty"`
-}
+type Warrant = warrant.Warrant
 
 var warrantCmd = &cobra.Command{
 	Use:   "warrant",
@@ -40,13 +31,18 @@ var warrantCmd = &cobra.Command{
 	Long: `Manage death warrants for agents that need termination.
 
 Death warrants are filed when an agent is stuck, unresponsive, or needs
-forced termination. Boot handles warrant execution during triage cycles.
+forced termination. The daemon executes them with the shutdown dance.
 
 The warrant system provides a controlled way to terminate agents:
 1. Deacon/Witness files a warrant with a reason
-2. Boot picks up the warrant during triage
-3. Boot executes the warrant (terminates session, updates state)
-4. Warrant is marked as executed
+2. The daemon interrogates the agent's session: a health-check nudge
+   asking for an ALIVE reply, repeated with timeouts of 60s, 120s and 240s
+3. An ALIVE reply in the pane pardons the agent; otherwise the daemon
+   kills the session
+4. Warrant is marked as pardoned or executed
+
+Dances run concurrently, each on its own timers, and their progress is
+saved in the warrant so they survive daemon restarts.
 
 Warrants are stored in ~/gt/warrants/ as JSON files.`,
 }
@@ -71,9 +67,10 @@ Examples:
 var warrantListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List pending warrants",
-	Long: `List all pending (unexecuted) warrants.
+	Long: `List all open warrants and the phase of each one's shutdown dance:
+filed, interrogating, evaluating or executing.
 
-Use --all to include executed warrants.
+Use --all to include executed and pardoned warrants.
 
 Examples:
   gt warrant list
@@ -86,7 +83,7 @@ var warrantExecuteCmd = &cobra.Command{
 	Short: "Execute a warrant (terminate agent)",
 	Long: `Execute a pending warrant for the specified target.
 
-This will:
+This skips the shutdown dance and will:
 1. Find the warrant for the target
 2. Terminate the agent's tmux session (if exists)
 3. Mark the warrant as executed
@@ -106,7 +103,7 @@ func init() {
 	warrantFileCmd.Flags().BoolVar(&warrantStdin, "stdin", false, "Read reason from stdin (avoids shell quoting issues)")
 
 	// List flags
-	warrantListCmd.Flags().BoolVarP(&warrantListAll, "all", "a", false, "Include executed warrants")
+	warrantListCmd.Flags().BoolVarP(&warrantListAll, "all", "a", false, "Include executed and pardoned warrants")
 
 	// Execute flags
 	warrantExecuteCmd.Flags().BoolVarP(&warrantForce, "force", "f", false, "Execute even without a warrant")
@@ -124,14 +121,12 @@ func getWarrantDir() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("finding town root: %w", err)
 	}
-	return filepath.Join(townRoot, "warrants"), nil
+	return warrant.Dir(townRoot), nil
 }
 
 // warrantFilePath returns the path for a warrant file
 func warrantFilePath(dir, target string) string {
-	// Replace / with _ for filename safety
-	safe := strings.ReplaceAll(target, "/", "_")
-	return filepath.Join(dir, safe+".warrant.json")
+	return warrant.Path(dir, target)
 }
 
 func runWarrantFile(cmd *cobra.Command, args []string) error {
@@ -166,16 +161,12 @@ func runWarrantFile(cmd *cobra.Command, args []string) error {
 
 	// Check if warrant already exists
 	warrantPath := warrantFilePath(warrantDir, target)
-	if _, err := os.Stat(warrantPath); err == nil {
-		// Load existing warrant
-		data, _ := os.ReadFile(warrantPath)
-		var existing Warrant
-		if json.Unmarshal(data, &existing) == nil && !existing.Executed {
-			fmt.Printf("Warrant already exists for %s\n", target)
-			fmt.Printf("  Reason: %s\n", existing.Reason)
-			fmt.Printf("  Filed: %s\n", existing.FiledAt.Format(time.RFC3339))
-			return nil
-		}
+	if existing, err := warrant.Load(warrantPath); err == nil && !existing.Closed() {
+		fmt.Printf("Warrant already exists for %s\n", target)
+		fmt.Printf("  Reason: %s\n", existing.Reason)
+		fmt.Printf("  Filed: %s\n", existing.FiledAt.Format(time.RFC3339))
+		fmt.Printf("  Phase: %s\n", existing.Phase())
+		return nil
 	}
 
 	// Get filer identity
@@ -184,7 +175,7 @@ func runWarrantFile(cmd *cobra.Command, args []string) error {
 		filedBy = "unknown"
 	}
 
-	warrant := Warrant{
+	w := &Warrant{
 		ID:       fmt.Sprintf("warrant-%d", time.Now().UnixMilli()),
 		Target:   target,
 		Reason:   warrantReason,
@@ -193,18 +184,13 @@ func runWarrantFile(cmd *cobra.Command, args []string) error {
 		Executed: false,
 	}
 
-	data, err := json.MarshalIndent(warrant, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshaling warrant: %w", err)
-	}
-
-	if err := os.WriteFile(warrantPath, data, 0644); err != nil {
-		return fmt.Errorf("writing warrant: %w", err)
+	if err := warrant.Save(warrantPath, w); err != nil {
+		return err
 	}
 
 	fmt.Printf("✓ Filed death warrant for %s\n", style.Bold.Render(target))
 	fmt.Printf("  Reason: %s\n", warrantReason)
-	fmt.Printf("  ID: %s\n", warrant.ID)
+	fmt.Printf("  ID: %s\n", w.ID)
 
 	return nil
 }
@@ -215,32 +201,18 @@ func runWarrantList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	entries, err := os.ReadDir(warrantDir)
+	all, err := warrant.List(warrantDir)
 	if err != nil {
-		if os.IsNotExist(err) {
-			fmt.Println("No warrants filed")
-			return nil
-		}
-		return fmt.Errorf("reading warrants directory: %w", err)
+		return err
+	}
+	if len(all) == 0 {
+		fmt.Println("No warrants filed")
+		return nil
 	}
 
-	var warrants []Warrant
-	for _, entry := range entries {
-		if !strings.HasSuffix(entry.Name(), ".warrant.json") {
-			continue
-		}
-
-		data, err := os.ReadFile(filepath.Join(warrantDir, entry.Name()))
-		if err != nil {
-			continue
-		}
-
-		var w Warrant
-		if err := json.Unmarshal(data, &w); err != nil {
-			continue
-		}
-
-		if warrantListAll || !w.Executed {
+	var warrants []*Warrant
+	for _, w := range all {
+		if warrantListAll || !w.Closed() {
 			warrants = append(warrants, w)
 		}
 	}
@@ -258,13 +230,23 @@ func runWarrantList(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	for _, w := range warrants {
-		status := "⚠️  PENDING"
-		if w.Executed {
-			status = "✓ EXECUTED"
-		}
-		fmt.Printf("  %s %s\n", status, style.Bold.Render(w.Target))
+		fmt.Printf("  %s %s\n", formatWarrantPhase(w), style.Bold.Render(w.Target))
 		fmt.Printf("     Reason: %s\n", w.Reason)
 		fmt.Printf("     Filed: %s by %s\n", w.FiledAt.Format("2006-01-02 15:04"), w.FiledBy)
+		if d := w.Dance; d != nil {
+			switch d.Phase {
+			case warrant.PhaseInterrogating:
+				fmt.Printf("     Health check %d/%d sent %s, reply due %s\n", d.Attempt, len(warrant.Timeouts),
+					d.AskedAt.Local().Format("15:04:05"), d.Deadline.Local().Format("15:04:05"))
+			case warrant.PhasePardoned, warrant.PhaseExecuted:
+				if d.Outcome != "" {
+					fmt.Printf("     Outcome: %s\n", d.Outcome)
+				}
+			}
+			if d.LastError != "" {
+				fmt.Printf("     %s\n", style.Dim.Render("Last error: "+d.LastError))
+			}
+		}
 		if w.Executed && w.ExecutedAt != nil {
 			fmt.Printf("     Executed: %s\n", w.ExecutedAt.Format("2006-01-02 15:04"))
 		}
@@ -274,6 +256,20 @@ func runWarrantList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// formatWarrantPhase renders a warrant's dance phase for gt warrant list.
+func formatWarrantPhase(w *Warrant) string {
+	switch phase := w.Phase(); phase {
+	case warrant.PhaseExecuted:
+		return "✓ EXECUTED"
+	case warrant.PhasePardoned:
+		return "✓ PARDONED"
+	case warrant.PhaseFiled:
+		return "⚠️  PENDING"
+	default:
+		return "⚠️  " + strings.ToUpper(string(phase))
+	}
+}
+
 func runWarrantExecute(cmd *cobra.Command, args []string) error {
 	target := args[0]
 
@@ -283,22 +279,16 @@ func runWarrantExecute(cmd *cobra.Command, args []string) error {
 	}
 
 	warrantPath := warrantFilePath(warrantDir, target)
-	var warrant *Warrant
 
 	// Load warrant if exists
-	if data, err := os.ReadFile(warrantPath); err == nil {
-		var w Warrant
-		if json.Unmarshal(data, &w) == nil {
-			warrant = &w
-		}
-	}
+	w, _ := warrant.Load(warrantPath)
 
-	if warrant == nil && !warrantForce {
+	if w == nil && !warrantForce {
 		return fmt.Errorf("no warrant found for %s (use --force to execute anyway)", target)
 	}
 
-	if warrant != nil && warrant.Executed {
-		fmt.Printf("Warrant for %s already executed at %s\n", target, warrant.ExecutedAt.Format(time.RFC3339))
+	if w != nil && w.Executed {
+		fmt.Printf("Warrant for %s already executed at %s\n", target, w.ExecutedAt.Format(time.RFC3339))
 		return nil
 	}
 
@@ -319,14 +309,18 @@ func runWarrantExecute(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Session %s not found (already dead)\n", sessionName)
 	}
 
-	// Mark warrant as executed
-	if warrant != nil {
+	// Mark warrant as executed, ending any dance in progress
+	if w != nil {
 		now := time.Now()
-		warrant.Executed = true
-		warrant.ExecutedAt = &now
-
-		data, _ := json.MarshalIndent(warrant, "", "  ")
-		_ = os.WriteFile(warrantPath, data, 0644)
+		w.Executed = true
+		w.ExecutedAt = &now
+		if w.Dance == nil {
+			w.Dance = &warrant.Dance{Session: sessionName}
+		}
+		w.Dance.Phase = warrant.PhaseExecuted
+		w.Dance.Outcome = "executed by hand (gt warrant execute)"
+		w.Dance.UpdatedAt = now
+		_ = warrant.Save(warrantPath, w)
 	}
 
 	fmt.Printf("✓ Warrant executed for %s\n", style.Bold.Render(target))
@@ -335,29 +329,6 @@ func runWarrantExecute(cmd *cobra.Command, args []string) error {
 
 // targetToSessionName converts a target path to a tmux session name
 func targetToSessionName(target string) (string, error) {
-	parts := strings.Split(target, "/")
-
-	// Handle different target formats
-	switch {
-	case len(parts) == 3 && parts[1] == "polecats":
-		// gastown/polecats/alpha -> gt-gastown-alpha
-		return fmt.Sprintf("gt-%s-%s", parts[0], parts[2]), nil
-	case len(parts) == 2 && parts[0] == "deacon" && parts[1] == "dogs":
-		// This shouldn't happen - need dog name
-		return "", fmt.Errorf("invalid target: need dog name (e.g., deacon/dogs/alpha)")
-	case len(parts) == 3 && parts[0] == "deacon" && parts[1] == "dogs":
-		// deacon/dogs/alpha -> gt-dog-alpha (or gt-<town>-deacon-alpha)
-		townRoot, err := workspace.FindFromCwd()
-		if err != nil {
-			return fmt.Sprintf("gt-dog-%s", parts[2]), nil
-		}
-		townName, err := workspace.GetTownName(townRoot)
-		if err != nil {
-			return fmt.Sprintf("gt-dog-%s", parts[2]), nil
-		}
-		return fmt.Sprintf("gt-%s-deacon-%s", townName, parts[2]), nil
-	default:
-		// Fallback: just use the target with dashes
-		return "gt-" + strings.ReplaceAll(target, "/", "-"), nil
-	}
+	townRoot, _ := workspace.FindFromCwd()
+	return warrant.SessionName(townRoot, target)
 }
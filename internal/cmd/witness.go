@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/witness"
@@ -19,6 +21,7 @@ var (
 	witnessStatusJSON    bool
 	witnessAgentOverride string
 	witnessEnvOverrides  []string
+	witnessOverdueJSON   bool
 )
 
 var witnessCmd = &cobra.Command{
@@ -119,6 +122,22 @@ Examples:
 	RunE: runWitnessRestart,
 }
 
+var witnessOverdueCmd = &cobra.Command{
+	Use:   "overdue <rig>",
+	Short: "Escalate molecule steps running past their timeout",
+	Long: `Find molecule steps in a rig that have run past their formula timeout
+and escalate each one to the Deacon (STEP_TIMEOUT mail).
+
+Steps get a timeout from the formula's step "timeout" field. Each step is
+escalated once per attempt; the witness runs this during patrol.
+
+Examples:
+  gt witness overdue greenplace
+  gt witness overdue greenplace --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessOverdue,
+}
+
 func init() {
 	// Start flags
 	witnessStartCmd.Flags().BoolVar(&witnessForeground, "foreground", false, "Run in foreground (default: background)")
@@ -128,6 +147,9 @@ func init() {
 	// Status flags
 	witnessStatusCmd.Flags().BoolVar(&witnessStatusJSON, "json", false, "Output as JSON")
 
+	// Overdue flags
+	witnessOverdueCmd.Flags().BoolVar(&witnessOverdueJSON, "json", false, "Output as JSON")
+
 	// Restart flags
 	witnessRestartCmd.Flags().StringVar(&witnessAgentOverride, "agent", "", "Agent alias to run the Witness with (overrides town default)")
 	witnessRestartCmd.Flags().StringArrayVar(&witnessEnvOverrides, "env", nil, "Environment variable override (KEY=VALUE, can be repeated)")
@@ -138,6 +160,7 @@ func init() {
 	witnessCmd.AddCommand(witnessRestartCmd)
 	witnessCmd.AddCommand(witnessStatusCmd)
 	witnessCmd.AddCommand(witnessAttachCmd)
+	witnessCmd.AddCommand(witnessOverdueCmd)
 
 	rootCmd.AddCommand(witnessCmd)
 }
@@ -289,6 +312,78 @@ func runWitnessStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// WitnessOverdueOutput is the JSON output format for witness overdue.
+type WitnessOverdueOutput struct {
+	RigName string               `json:"rig_name"`
+	Checked int                  `json:"checked"`
+	Overdue []WitnessOverdueStep `json:"overdue"`
+	Errors  []string             `json:"errors,omitempty"`
+}
+
+// WitnessOverdueStep is one escalated step in WitnessOverdueOutput.
+type WitnessOverdueStep struct {
+	StepID   string `json:"step_id"`
+	Title    string `json:"title"`
+	Assignee string `json:"assignee,omitempty"`
+	Timeout  string `json:"timeout"`
+	Elapsed  string `json:"elapsed"`
+	MailID   string `json:"mail_id,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func runWitnessOverdue(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	result := witness.DetectOverdueSteps(r.BeadsPath(), rigName, mail.NewRouter(townRoot))
+
+	if witnessOverdueJSON {
+		output := WitnessOverdueOutput{RigName: rigName, Checked: result.Checked, Overdue: []WitnessOverdueStep{}}
+		for _, s := range result.Overdue {
+			step := WitnessOverdueStep{
+				StepID:   s.StepID,
+				Title:    s.Title,
+				Assignee: s.Assignee,
+				Timeout:  s.Timeout,
+				Elapsed:  s.Elapsed.Round(time.Second).String(),
+				MailID:   s.MailID,
+			}
+			if s.Error != nil {
+				step.Error = s.Error.Error()
+			}
+			output.Overdue = append(output.Overdue, step)
+		}
+		for _, e := range result.Errors {
+			output.Errors = append(output.Errors, e.Error())
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(output)
+	}
+
+	for _, e := range result.Errors {
+		style.PrintWarning("%v", e)
+	}
+	if len(result.Overdue) == 0 {
+		fmt.Printf("%s No overdue steps (%d with timeouts checked)\n", style.Bold.Render("✓"), result.Checked)
+		return nil
+	}
+	for _, s := range result.Overdue {
+		if s.Error != nil {
+			fmt.Printf("  %s %s: %s (running %s, timeout %s): %v\n", style.Bold.Render("✗"),
+				s.StepID, s.Title, s.Elapsed.Round(time.Second), s.Timeout, s.Error)
+			continue
+		}
+		fmt.Printf("  %s %s: %s (running %s, timeout %s) escalated to deacon\n", style.Bold.Render("⚠"),
+			s.StepID, s.Title, s.Elapsed.Round(time.Second), s.Timeout)
+	}
+	return nil
+}
+
 // witnessSessionName returns the tmux session name for a rig's witness.
 func witnessSessionName(rigName string) string {
 	return fmt.Sprintf("gt-%s-witness", rigName)
@@ -76,6 +76,20 @@ type AgentPresetInfo struct {
 
 	// NonInteractive contains settings for non-interactive mode.
 	NonInteractive *NonInteractiveConfig `json:"non_interactive,omitempty"`
+
+	// Usage says where the agent records token usage and how to parse it,
+	// for cost accounting. Nil means the runtime keeps no usable record.
+	Usage *UsageSource `json:"usage,omitempty"`
+}
+
+// UsageSource locates an agent's session transcripts for cost accounting.
+type UsageSource struct {
+	// Format selects the parser: "claude", "codex", "gemini" or "opencode".
+	Format string `json:"format"`
+
+	// Dir overrides where the transcripts live ("~" is the home directory).
+	// Empty uses the format's default, e.g. ~/.codex/sessions for codex.
+	Dir string `json:"dir,omitempty"`
 }
 
 // NonInteractiveConfig contains settings for running agents non-interactively.
@@ -116,6 +130,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		SupportsHooks:       true,
 		SupportsForkSession: true,
 		NonInteractive:      nil, // Claude is native non-interactive
+		Usage:               &UsageSource{Format: "claude"},
 	},
 	AgentGemini: {
 		Name:                AgentGemini,
@@ -131,6 +146,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 			PromptFlag: "-p",
 			OutputFlag: "--output-format json",
 		},
+		Usage: &UsageSource{Format: "gemini"},
 	},
 	AgentCodex: {
 		Name:                AgentCodex,
@@ -146,6 +162,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 			Subcommand: "exec",
 			OutputFlag: "--json",
 		},
+		Usage: &UsageSource{Format: "codex"},
 	},
 	AgentCursor: {
 		Name:                AgentCursor,
@@ -202,6 +219,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 			Subcommand: "run",
 			OutputFlag: "--format json",
 		},
+		Usage: &UsageSource{Format: "opencode"},
 	},
 }
 
@@ -212,6 +212,25 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.Budget != nil {
+		if err := ValidateBudgetConfig(c.Budget); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ErrInvalidBudget indicates a negative cap or an out-of-range warn_percent.
+var ErrInvalidBudget = errors.New("invalid budget")
+
+// ValidateBudgetConfig validates a BudgetConfig.
+func ValidateBudgetConfig(c *BudgetConfig) error {
+	if c.DailyUSD < 0 || c.TotalUSD < 0 {
+		return fmt.Errorf("%w: caps must not be negative", ErrInvalidBudget)
+	}
+	if c.WarnPercent < 0 || c.WarnPercent > 100 {
+		return fmt.Errorf("%w: warn_percent must be between 0 and 100, got %d", ErrInvalidBudget, c.WarnPercent)
+	}
 	return nil
 }
 
@@ -856,9 +875,20 @@ func SaveTownSettings(path string, settings *TownSettings) error {
 		return fmt.Errorf("encoding settings: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
+	// Dashboard tokens are secrets; keep the file private once they're set
+	perm := os.FileMode(0644)
+	if settings.WebAuth != nil {
+		perm = 0600
+	}
+	if err := os.WriteFile(path, data, perm); err != nil { //nolint:gosec // G306: mode is 0600 when the file holds dashboard tokens
 		return fmt.Errorf("writing settings: %w", err)
 	}
+	// WriteFile only applies perm to new files; tighten existing ones too
+	if perm == 0600 {
+		if err := os.Chmod(path, perm); err != nil {
+			return fmt.Errorf("setting settings permissions: %w", err)
+		}
+	}
 
 	return nil
 }
@@ -1856,6 +1886,19 @@ func validateEscalationConfig(c *EscalationConfig) error {
 		}
 	}
 
+	// Validate delivery settings if specified
+	if c.Delivery.InitialBackoff != "" {
+		if _, err := time.ParseDuration(c.Delivery.InitialBackoff); err != nil {
+			return fmt.Errorf("invalid delivery.initial_backoff: %w", err)
+		}
+	}
+	if c.Delivery.MaxAttempts < 0 {
+		return fmt.Errorf("%w: delivery.max_attempts must be non-negative", ErrMissingField)
+	}
+	if s := c.Delivery.SMSGateway; s != nil && s.Format != "" && s.Format != "form" && s.Format != "json" {
+		return fmt.Errorf("%w: delivery.sms_gateway.format must be 'form' or 'json'", ErrMissingField)
+	}
+
 	// Initialize nil maps
 	if c.Routes == nil {
 		c.Routes = make(map[string][]string)
@@ -1907,3 +1950,139 @@ func (c *EscalationConfig) GetMaxReescalations() int {
 	}
 	return *c.MaxReescalations
 }
+
+// GetDeliveryMaxAttempts returns how many times an external notification is tried.
+// Returns 3 if not configured.
+func (c *EscalationConfig) GetDeliveryMaxAttempts() int {
+	if c.Delivery.MaxAttempts <= 0 {
+		return 3
+	}
+	return c.Delivery.MaxAttempts
+}
+
+// GetDeliveryInitialBackoff returns the delay before the first delivery retry.
+// Returns 2 seconds if not configured or invalid.
+func (c *EscalationConfig) GetDeliveryInitialBackoff() time.Duration {
+	if c.Delivery.InitialBackoff == "" {
+		return 2 * time.Second
+	}
+	d, err := time.ParseDuration(c.Delivery.InitialBackoff)
+	if err != nil {
+		return 2 * time.Second
+	}
+	return d
+}
+
+// WebhooksConfigPath returns the standard path for webhook ingress config in a town.
+func WebhooksConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "webhooks.json")
+}
+
+// LoadWebhooksConfig loads and validates a webhook ingress configuration file.
+func LoadWebhooksConfig(path string) (*WebhooksConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading webhooks config: %w", err)
+	}
+
+	var config WebhooksConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing webhooks config: %w", err)
+	}
+
+	if err := validateWebhooksConfig(&config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// SaveWebhooksConfig saves a webhook ingress configuration to a file.
+func SaveWebhooksConfig(path string, config *WebhooksConfig) error {
+	if err := validateWebhooksConfig(config); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding webhooks config: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing webhooks config: %w", err)
+	}
+
+	return nil
+}
+
+// validateWebhooksConfig validates a WebhooksConfig.
+func validateWebhooksConfig(c *WebhooksConfig) error {
+	if c.Type != "webhooks" && c.Type != "" {
+		return fmt.Errorf("%w: expected type 'webhooks', got '%s'", ErrInvalidType, c.Type)
+	}
+	if c.Version > CurrentWebhooksVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentWebhooksVersion)
+	}
+	if c.MaxBodyBytes < 0 {
+		return fmt.Errorf("%w: max_body_bytes must be non-negative", ErrMissingField)
+	}
+
+	for i, route := range c.Routes {
+		if route.Path == "" || strings.Contains(route.Path, "/") {
+			return fmt.Errorf("%w: routes[%d].path must be a single non-empty path segment", ErrMissingField, i)
+		}
+		if c.Enabled && c.Secret == "" && route.Secret == "" {
+			return fmt.Errorf("%w: route %q has no secret (set secret or routes[%d].secret)", ErrMissingField, route.Path, i)
+		}
+		if len(route.Actions) == 0 {
+			return fmt.Errorf("%w: routes[%d].actions", ErrMissingField, i)
+		}
+		for j, action := range route.Actions {
+			if err := validateWebhookAction(action); err != nil {
+				return fmt.Errorf("routes[%d].actions[%d]: %w", i, j, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateWebhookAction checks that an action has the fields its type needs.
+func validateWebhookAction(a WebhookAction) error {
+	switch a.Type {
+	case WebhookActionMail:
+		if a.To == "" || a.Subject == "" {
+			return fmt.Errorf("%w: mail action needs to and subject", ErrMissingField)
+		}
+	case WebhookActionCloseGate:
+		if a.Gate == "" {
+			return fmt.Errorf("%w: close_gate action needs gate", ErrMissingField)
+		}
+	case WebhookActionCreateIssue:
+		if a.Rig == "" || a.Title == "" {
+			return fmt.Errorf("%w: create_issue action needs rig and title", ErrMissingField)
+		}
+		if a.Priority != nil && (*a.Priority < 0 || *a.Priority > 4) {
+			return fmt.Errorf("%w: create_issue priority must be 0-4", ErrMissingField)
+		}
+	case WebhookActionEvent:
+		if a.Event == "" {
+			return fmt.Errorf("%w: event action needs event", ErrMissingField)
+		}
+		switch a.Visibility {
+		case "", "audit", "feed", "both":
+		default:
+			return fmt.Errorf("%w: event visibility must be audit, feed or both", ErrMissingField)
+		}
+	default:
+		return fmt.Errorf("%w: unknown action type %q", ErrMissingField, a.Type)
+	}
+	return nil
+}
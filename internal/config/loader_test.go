@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -345,6 +346,33 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid budget",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Budget:  &BudgetConfig{DailyUSD: 50, TotalUSD: 500, WarnPercent: 75},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative budget",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Budget:  &BudgetConfig{DailyUSD: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "budget warn_percent out of range",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Budget:  &BudgetConfig{DailyUSD: 10, WarnPercent: 150},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -3783,3 +3811,72 @@ func TestBuildStartupCommandWithAgentOverride_NoGTAgentWhenNoOverride(t *testing
 		t.Errorf("expected no GT_AGENT in command when no override, got: %q", cmd)
 	}
 }
+
+func TestWebhooksConfigRoundTrip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := WebhooksConfigPath(dir)
+
+	original := NewWebhooksConfig()
+	original.Enabled = true
+	original.Secret = "s3cret"
+	original.Routes = []WebhookRoute{{
+		Name:    "ci",
+		Path:    "ci",
+		Match:   map[string]string{"status": "failure"},
+		Actions: []WebhookAction{{Type: WebhookActionMail, To: "mayor/", Subject: "CI failed"}},
+	}}
+
+	if err := SaveWebhooksConfig(path, original); err != nil {
+		t.Fatalf("SaveWebhooksConfig: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("webhooks.json mode = %v, want 0600 (holds the signing secret)", info.Mode().Perm())
+	}
+
+	loaded, err := LoadWebhooksConfig(path)
+	if err != nil {
+		t.Fatalf("LoadWebhooksConfig: %v", err)
+	}
+	if !loaded.Enabled || loaded.Secret != "s3cret" || loaded.Listen != DefaultWebhookListen {
+		t.Errorf("loaded = %+v", loaded)
+	}
+	if len(loaded.Routes) != 1 || loaded.Routes[0].Match["status"] != "failure" || loaded.Routes[0].Actions[0].To != "mayor/" {
+		t.Errorf("Routes = %+v", loaded.Routes)
+	}
+
+	if _, err := LoadWebhooksConfig(filepath.Join(dir, "missing.json")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing file error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestWebhooksConfigValidation(t *testing.T) {
+	t.Parallel()
+
+	mail := WebhookAction{Type: WebhookActionMail, To: "mayor/", Subject: "hi"}
+	badPriority := 7
+	tests := []struct {
+		name    string
+		config  *WebhooksConfig
+		wantErr bool
+	}{
+		{"disabled without secret", &WebhooksConfig{Routes: []WebhookRoute{{Path: "ci", Actions: []WebhookAction{mail}}}}, false},
+		{"enabled without secret", &WebhooksConfig{Enabled: true, Routes: []WebhookRoute{{Path: "ci", Actions: []WebhookAction{mail}}}}, true},
+		{"route secret", &WebhooksConfig{Enabled: true, Routes: []WebhookRoute{{Path: "ci", Secret: "x", Actions: []WebhookAction{mail}}}}, false},
+		{"wrong type", &WebhooksConfig{Type: "escalation"}, true},
+		{"nested path", &WebhooksConfig{Secret: "x", Routes: []WebhookRoute{{Path: "a/b", Actions: []WebhookAction{mail}}}}, true},
+		{"no actions", &WebhooksConfig{Secret: "x", Routes: []WebhookRoute{{Path: "ci"}}}, true},
+		{"unknown action", &WebhooksConfig{Secret: "x", Routes: []WebhookRoute{{Path: "ci", Actions: []WebhookAction{{Type: "shell"}}}}}, true},
+		{"gate without id", &WebhooksConfig{Secret: "x", Routes: []WebhookRoute{{Path: "ci", Actions: []WebhookAction{{Type: WebhookActionCloseGate}}}}}, true},
+		{"issue priority", &WebhooksConfig{Secret: "x", Routes: []WebhookRoute{{Path: "ci", Actions: []WebhookAction{{Type: WebhookActionCreateIssue, Rig: "gastown", Title: "t", Priority: &badPriority}}}}}, true},
+		{"event visibility", &WebhooksConfig{Secret: "x", Routes: []WebhookRoute{{Path: "ci", Actions: []WebhookAction{{Type: WebhookActionEvent, Event: "ci", Visibility: "public"}}}}}, true},
+	}
+	for _, tt := range tests {
+		err := validateWebhooksConfig(tt.config)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -73,11 +73,35 @@ type TownSettings struct {
 	// WebTimeouts configures command execution timeouts for the web dashboard.
 	WebTimeouts *WebTimeoutsConfig `json:"web_timeouts,omitempty"`
 
+	// WebAuth holds the web dashboard's access tokens.
+	// Generated on the first `gt dashboard` run.
+	WebAuth *WebAuthConfig `json:"web_auth,omitempty"`
+
 	// WorkerStatus configures activity-age thresholds for worker status classification.
 	WorkerStatus *WorkerStatusConfig `json:"worker_status,omitempty"`
 
 	// FeedCurator configures event deduplication and aggregation windows.
 	FeedCurator *FeedCuratorConfig `json:"feed_curator,omitempty"`
+
+	// Pricing sets per-model token prices for cost accounting, merged over
+	// the built-in table. Keys are model names or name prefixes ("gpt-5"
+	// matches "gpt-5-codex"); "default" prices models with no match.
+	// Example: {"gpt-5": {"input": 1.25, "output": 10, "cache_read": 0.125}}
+	Pricing map[string]*ModelPricing `json:"pricing,omitempty"`
+
+	// FormulaRegistry is the Mol Mall registry gt formula search/install/update
+	// read from: an http(s):// URL, file:// URL or local directory.
+	// Can be overridden by GT_FORMULA_REGISTRY or --registry.
+	// Default: "https://molmall.gastown.io"
+	FormulaRegistry string `json:"formula_registry,omitempty"`
+}
+
+// ModelPricing is a model's price in USD per million tokens.
+type ModelPricing struct {
+	Input      float64 `json:"input"`
+	Output     float64 `json:"output"`
+	CacheRead  float64 `json:"cache_read,omitempty"`
+	CacheWrite float64 `json:"cache_write,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -119,6 +143,23 @@ func DefaultWebTimeoutsConfig() *WebTimeoutsConfig {
 	}
 }
 
+// WebAuthConfig configures authentication for the web dashboard.
+type WebAuthConfig struct {
+	// Token grants full access: viewing, running commands, sending mail.
+	// Sent as "Authorization: Bearer <token>" by scripts, or exchanged for a
+	// login cookie at /login by browsers.
+	Token string `json:"token,omitempty"`
+	// ReadOnlyToken, if set, grants view-only access. Read-only clients
+	// cannot make POST requests (run commands, send mail, create issues).
+	ReadOnlyToken string `json:"read_only_token,omitempty"`
+	// AllowedOrigins lists extra origins (e.g. "https://ops.example.com")
+	// allowed to call the API cross-origin. The dashboard's own origin is
+	// always allowed.
+	AllowedOrigins []string `json:"allowed_origins,omitempty"`
+	// SessionTTL is how long a login cookie stays valid. Default: "24h".
+	SessionTTL string `json:"session_ttl,omitempty"`
+}
+
 // WorkerStatusConfig configures activity-age thresholds for worker status classification.
 type WorkerStatusConfig struct {
 	// StaleThreshold is the activity age after which a worker is considered "stale".
@@ -335,6 +376,39 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific rig.
 	// Example: {"witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// Budget caps this rig's agent spend. The daemon mails the mayor when
+	// spend crosses the warning threshold and parks the rig at the cap.
+	Budget *BudgetConfig `json:"budget,omitempty"`
+}
+
+// DefaultBudgetWarnPercent is the soft threshold used when a budget sets none.
+const DefaultBudgetWarnPercent = 80
+
+// BudgetConfig is a spend cap in USD. Zero caps are unlimited.
+type BudgetConfig struct {
+	// DailyUSD caps spend per calendar day (local time).
+	DailyUSD float64 `json:"daily_usd,omitempty"`
+
+	// TotalUSD caps all-time spend.
+	TotalUSD float64 `json:"total_usd,omitempty"`
+
+	// WarnPercent is the soft threshold, as a percentage of each cap, at
+	// which the mayor is warned. Defaults to 80.
+	WarnPercent int `json:"warn_percent,omitempty"`
+}
+
+// IsZero reports whether the budget sets no caps.
+func (b *BudgetConfig) IsZero() bool {
+	return b == nil || (b.DailyUSD <= 0 && b.TotalUSD <= 0)
+}
+
+// WarnFraction returns the soft threshold as a fraction of the cap.
+func (b *BudgetConfig) WarnFraction() float64 {
+	if b == nil || b.WarnPercent <= 0 {
+		return DefaultBudgetWarnPercent / 100.0
+	}
+	return float64(b.WarnPercent) / 100.0
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -1006,6 +1080,10 @@ type EscalationConfig struct {
 	// Contacts contains contact information for external notification actions.
 	Contacts EscalationContacts `json:"contacts"`
 
+	// Delivery configures how external notifications are sent (SMTP server,
+	// SMS gateway, escalation log, retry policy).
+	Delivery EscalationDelivery `json:"delivery,omitempty"`
+
 	// StaleThreshold is how long before an unacknowledged escalation
 	// is considered stale and gets re-escalated.
 	// Format: Go duration string (e.g., "4h", "30m", "24h")
@@ -1025,6 +1103,51 @@ type EscalationContacts struct {
 	SlackWebhook string `json:"slack_webhook,omitempty"` // webhook URL for slack action
 }
 
+// EscalationDelivery configures the transports behind external escalation actions.
+type EscalationDelivery struct {
+	// SMTP is the mail server used by email: actions.
+	SMTP *EscalationSMTP `json:"smtp,omitempty"`
+
+	// SMSGateway is the HTTP gateway used by sms: actions.
+	SMSGateway *EscalationSMSGateway `json:"sms_gateway,omitempty"`
+
+	// LogPath is the append-only escalation log written by the log action.
+	// Relative paths are resolved against the town root.
+	// Default: "logs/escalations.jsonl"
+	LogPath string `json:"log_path,omitempty"`
+
+	// MaxAttempts is how many times a notification is tried before it is
+	// recorded as failed. Default: 3
+	MaxAttempts int `json:"max_attempts,omitempty"`
+
+	// InitialBackoff is the delay before the first retry; it doubles on
+	// each subsequent attempt. Format: Go duration string. Default: "2s"
+	InitialBackoff string `json:"initial_backoff,omitempty"`
+}
+
+// EscalationSMTP holds SMTP server settings for email notifications.
+// The password is read from the environment so it never lands in settings.
+type EscalationSMTP struct {
+	Host        string `json:"host"`                   // SMTP server hostname
+	Port        int    `json:"port,omitempty"`         // default: 587
+	Username    string `json:"username,omitempty"`     // auth username (empty = no auth)
+	PasswordEnv string `json:"password_env,omitempty"` // env var holding the auth password
+	From        string `json:"from"`                   // envelope and header sender
+}
+
+// EscalationSMSGateway holds settings for an HTTP SMS gateway.
+//
+// With format "form" the gateway receives To, From and Body form fields
+// (Twilio-compatible); with format "json" it receives {"to","from","message"}.
+type EscalationSMSGateway struct {
+	URL         string `json:"url"`                    // endpoint that accepts the POST
+	Format      string `json:"format,omitempty"`       // "form" (default) or "json"
+	From        string `json:"from,omitempty"`         // sender number or ID
+	Username    string `json:"username,omitempty"`     // basic auth user (e.g., account SID)
+	PasswordEnv string `json:"password_env,omitempty"` // env var holding the basic auth password
+	TokenEnv    string `json:"token_env,omitempty"`    // env var holding a bearer token
+}
+
 // CurrentEscalationVersion is the current schema version for EscalationConfig.
 const CurrentEscalationVersion = 1
 
@@ -1085,3 +1208,102 @@ func NewEscalationConfig() *EscalationConfig {
 		MaxReescalations: intPtr(2),
 	}
 }
+
+// WebhooksConfig configures the daemon's webhook ingress.
+// Stored in settings/webhooks.json. The file holds the signing secret, so it
+// is written with 0600 permissions.
+type WebhooksConfig struct {
+	Type    string `json:"type"`    // "webhooks"
+	Version int    `json:"version"` // schema version
+
+	// Enabled starts the webhook listener when the daemon runs.
+	Enabled bool `json:"enabled"`
+
+	// Listen is the address to bind (default "127.0.0.1:8788").
+	// Put a TLS-terminating proxy in front before exposing it publicly.
+	Listen string `json:"listen,omitempty"`
+
+	// Secret is the HMAC-SHA256 key used to verify request signatures.
+	// Routes may override it with their own secret.
+	Secret string `json:"secret"`
+
+	// MaxBodyBytes caps the request body size (default 1 MiB).
+	MaxBodyBytes int64 `json:"max_body_bytes,omitempty"`
+
+	// Routes map incoming requests to actions.
+	Routes []WebhookRoute `json:"routes"`
+}
+
+// WebhookRoute maps requests on one path to a list of actions.
+// All rules on a path are evaluated in order and every matching rule runs.
+type WebhookRoute struct {
+	// Name identifies the route in logs and responses.
+	Name string `json:"name"`
+
+	// Path is the URL path segment; the route is served at /hooks/<path>.
+	Path string `json:"path"`
+
+	// Secret overrides the top-level secret for this route.
+	Secret string `json:"secret,omitempty"`
+
+	// Headers must all match (case-insensitive names, exact values),
+	// e.g. {"X-GitHub-Event": "workflow_run"}.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Match must all match. Keys are dotted paths into the JSON payload
+	// (e.g. "workflow_run.conclusion"), values are compared as strings.
+	// "*" matches any present value.
+	Match map[string]string `json:"match,omitempty"`
+
+	// Actions run in order when the route matches.
+	Actions []WebhookAction `json:"actions"`
+}
+
+// Webhook action types.
+const (
+	WebhookActionMail        = "mail"         // Send gt mail
+	WebhookActionCloseGate   = "close_gate"   // Close a bd gate and wake its waiters
+	WebhookActionCreateIssue = "create_issue" // Create an issue in a rig
+	WebhookActionEvent       = "event"        // Emit an events.Event
+)
+
+// WebhookAction is one action run for a matching webhook.
+// String fields are Go text/template strings evaluated against the JSON
+// payload, e.g. "CI {{.workflow_run.conclusion}} on {{.repository.name}}".
+type WebhookAction struct {
+	Type string `json:"type"`
+
+	// mail
+	To      string `json:"to,omitempty"`
+	Subject string `json:"subject,omitempty"`
+	Body    string `json:"body,omitempty"`
+
+	// close_gate
+	Gate   string `json:"gate,omitempty"`
+	Reason string `json:"reason,omitempty"`
+
+	// create_issue (Title and Body are shared with mail)
+	Rig       string `json:"rig,omitempty"`
+	Title     string `json:"title,omitempty"`
+	IssueType string `json:"issue_type,omitempty"`
+	Priority  *int   `json:"priority,omitempty"`
+
+	// event
+	Event      string `json:"event,omitempty"`
+	Visibility string `json:"visibility,omitempty"` // audit, feed or both (default feed)
+}
+
+// CurrentWebhooksVersion is the current schema version for WebhooksConfig.
+const CurrentWebhooksVersion = 1
+
+// DefaultWebhookListen is the default webhook listen address.
+const DefaultWebhookListen = "127.0.0.1:8788"
+
+// NewWebhooksConfig creates a disabled WebhooksConfig with no routes.
+func NewWebhooksConfig() *WebhooksConfig {
+	return &WebhooksConfig{
+		Type:    "webhooks",
+		Version: CurrentWebhooksVersion,
+		Listen:  DefaultWebhookListen,
+	}
+}
@@ -6,18 +6,20 @@ import (
 	"os/exec"
 	"path/filepath"
 
-	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/session"
 )
 
 // LocalConnection implements Connection for local file and command operations.
 type LocalConnection struct {
-	tmux *tmux.Tmux
+	tmux session.Backend
 }
 
-// NewLocalConnection creates a new local connection.
+// NewLocalConnection creates a new local connection using the session
+// backend selected by GT_SESSION_BACKEND. An unknown backend falls back to
+// tmux.
 func NewLocalConnection() *LocalConnection {
 	return &LocalConnection{
-		tmux: tmux.NewTmux(),
+		tmux: session.DefaultBackend(),
 	}
 }
 
@@ -155,9 +157,9 @@ func (c *LocalConnection) ExecEnv(env map[string]string, cmd string, args ...str
 	return command.CombinedOutput()
 }
 
-// TmuxNewSession creates a new tmux session.
+// TmuxNewSession creates a new session running the default shell.
 func (c *LocalConnection) TmuxNewSession(name, dir string) error {
-	return c.tmux.NewSession(name, dir)
+	return c.tmux.NewSessionWithCommand(name, dir, "")
 }
 
 // TmuxKillSession terminates a tmux session.
@@ -11,11 +11,12 @@ import (
 
 // Machine represents a managed machine in the federation.
 type Machine struct {
-	Name     string `json:"name"`
-	Type     string `json:"type"`      // "local", "ssh"
-	Host     string `json:"host"`      // for ssh: user@host
-	KeyPath  string `json:"key_path"`  // SSH private key path
-	TownPath string `json:"town_path"` // Path to town root on remote
+	Name           string `json:"name"`
+	Type           string `json:"type"`                       // "local", "ssh"
+	Host           string `json:"host"`                       // for ssh: user@host[:port]
+	KeyPath        string `json:"key_path"`                   // SSH private key path
+	KnownHostsPath string `json:"known_hosts_path,omitempty"` // known_hosts file (default ~/.ssh/known_hosts)
+	TownPath       string `json:"town_path"`                  // Path to town root on remote
 }
 
 // registryData is the JSON file structure.
@@ -176,8 +177,7 @@ func (r *MachineRegistry) Connection(name string) (Connection, error) {
 	case "local":
 		return NewLocalConnection(), nil
 	case "ssh":
-		// SSH connection not yet implemented
-		return nil, fmt.Errorf("ssh connections not yet implemented")
+		return NewSSHConnectionFromMachine(m), nil
 	default:
 		return nil, fmt.Errorf("unknown machine type: %s", m.Type)
 	}
@@ -0,0 +1,636 @@
+package connection
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// DefaultSSHTimeout is the dial timeout for SSH connections.
+const DefaultSSHTimeout = 15 * time.Second
+
+// Remote exit codes used by the shell snippets below to report error classes.
+// Chosen above the range used by coreutils so they can't be confused with a
+// failing command.
+const (
+	exitNotFound   = 66
+	exitPermission = 77
+)
+
+// SSHConfig configures an SSH connection.
+type SSHConfig struct {
+	// Host is the target in user@host[:port] form. User defaults to the
+	// current user and port to 22.
+	Host string
+
+	// KeyPath is the private key used for authentication. When empty, the
+	// SSH agent (SSH_AUTH_SOCK) is used.
+	KeyPath string
+
+	// KnownHostsPath is the known_hosts file used to verify the host key.
+	// Defaults to ~/.ssh/known_hosts.
+	KnownHostsPath string
+
+	// Timeout is the dial timeout. Defaults to DefaultSSHTimeout.
+	Timeout time.Duration
+
+	// HostKeyCallback overrides known_hosts verification (tests and callers
+	// that manage host keys themselves).
+	HostKeyCallback ssh.HostKeyCallback
+}
+
+// SSHConnection implements Connection for a remote machine over SSH.
+// File operations and tmux commands are executed as POSIX shell commands on
+// the remote host, so the remote only needs sh, coreutils and tmux.
+// The underlying client is dialed lazily and reused across operations.
+type SSHConnection struct {
+	name   string
+	config SSHConfig
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+// NewSSHConnection creates a new SSH connection. No network activity happens
+// until the first operation.
+func NewSSHConnection(name string, cfg SSHConfig) *SSHConnection {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = DefaultSSHTimeout
+	}
+	return &SSHConnection{name: name, config: cfg}
+}
+
+// NewSSHConnectionFromMachine creates an SSH connection for a registry machine.
+func NewSSHConnectionFromMachine(m *Machine) *SSHConnection {
+	return NewSSHConnection(m.Name, SSHConfig{
+		Host:           m.Host,
+		KeyPath:        m.KeyPath,
+		KnownHostsPath: m.KnownHostsPath,
+	})
+}
+
+// Name returns the machine name.
+func (c *SSHConnection) Name() string {
+	return c.name
+}
+
+// IsLocal returns false for SSH connections.
+func (c *SSHConnection) IsLocal() bool {
+	return false
+}
+
+// Close closes the underlying SSH client, if connected.
+func (c *SSHConnection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client == nil {
+		return nil
+	}
+	err := c.client.Close()
+	c.client = nil
+	return err
+}
+
+// parseSSHHost splits user@host[:port] into its parts, applying defaults.
+func parseSSHHost(target string) (userName, addr string, err error) {
+	if target == "" {
+		return "", "", fmt.Errorf("ssh host is required")
+	}
+
+	hostPart := target
+	if i := strings.LastIndex(target, "@"); i >= 0 {
+		userName = target[:i]
+		hostPart = target[i+1:]
+	}
+	if userName == "" {
+		if u, uerr := user.Current(); uerr == nil {
+			userName = u.Username
+		} else {
+			userName = os.Getenv("USER")
+		}
+	}
+	if hostPart == "" {
+		return "", "", fmt.Errorf("invalid ssh host %q", target)
+	}
+
+	if _, _, splitErr := net.SplitHostPort(hostPart); splitErr != nil {
+		hostPart = net.JoinHostPort(strings.Trim(hostPart, "[]"), "22")
+	}
+	return userName, hostPart, nil
+}
+
+// clientConfig builds the ssh.ClientConfig (auth methods and host key check).
+// When the keys come from the ssh agent, the agent connection is returned
+// too; it is only used during the handshake and the caller closes it.
+func (c *SSHConnection) clientConfig(userName string) (*ssh.ClientConfig, io.Closer, error) {
+	var auth []ssh.AuthMethod
+	var agentConn net.Conn
+
+	if c.config.KeyPath != "" {
+		keyPath := expandHome(c.config.KeyPath)
+		key, err := os.ReadFile(keyPath) //nolint:gosec // G304: key path comes from operator-managed machine registry
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading ssh key %s: %w", keyPath, err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing ssh key %s: %w", keyPath, err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	} else if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		conn, err := net.Dial("unix", sock)
+		if err != nil {
+			return nil, nil, fmt.Errorf("connecting to ssh agent: %w", err)
+		}
+		agentConn = conn
+		auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+	} else {
+		return nil, nil, fmt.Errorf("no ssh key configured and SSH_AUTH_SOCK not set")
+	}
+
+	hostKeyCallback := c.config.HostKeyCallback
+	if hostKeyCallback == nil {
+		khPath := c.config.KnownHostsPath
+		if khPath == "" {
+			khPath = "~/.ssh/known_hosts"
+		}
+		cb, err := knownhosts.New(expandHome(khPath))
+		if err != nil {
+			if agentConn != nil {
+				_ = agentConn.Close()
+			}
+			return nil, nil, fmt.Errorf("loading known hosts %s: %w", khPath, err)
+		}
+		hostKeyCallback = cb
+	}
+
+	cfg := &ssh.ClientConfig{
+		User:            userName,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         c.config.Timeout,
+	}
+	if agentConn == nil {
+		return cfg, nil, nil
+	}
+	return cfg, agentConn, nil
+}
+
+// connect returns the shared SSH client, dialing it on first use.
+func (c *SSHConnection) connect() (*ssh.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	userName, addr, err := parseSSHHost(c.config.Host)
+	if err != nil {
+		return nil, &ConnectionError{Op: "connect", Machine: c.name, Err: err}
+	}
+	cfg, agentConn, err := c.clientConfig(userName)
+	if err != nil {
+		return nil, &ConnectionError{Op: "connect", Machine: c.name, Err: err}
+	}
+	if agentConn != nil {
+		defer agentConn.Close() // Signing is done once the handshake completes
+	}
+	client, err := ssh.Dial("tcp", addr, cfg)
+	if err != nil {
+		return nil, &ConnectionError{Op: "connect", Machine: c.name, Err: err}
+	}
+
+	c.client = client
+	return client, nil
+}
+
+// run executes a shell command on the remote host, optionally feeding stdin.
+// Returns stdout and stderr separately, plus the remote exit status (or -1
+// if the command did not run to completion).
+func (c *SSHConnection) run(command string, stdin []byte) (stdout, stderr []byte, status int, err error) {
+	client, err := c.connect()
+	if err != nil {
+		return nil, nil, -1, err
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		// The connection may have dropped; reconnect once.
+		_ = c.Close()
+		if client, err = c.connect(); err != nil {
+			return nil, nil, -1, err
+		}
+		if session, err = client.NewSession(); err != nil {
+			return nil, nil, -1, &ConnectionError{Op: "session", Machine: c.name, Err: err}
+		}
+	}
+	defer session.Close()
+
+	var outBuf, errBuf bytes.Buffer
+	session.Stdout = &outBuf
+	session.Stderr = &errBuf
+	if stdin != nil {
+		session.Stdin = bytes.NewReader(stdin)
+	}
+
+	// LC_ALL=C keeps tool output stable for parsing.
+	err = session.Run("LC_ALL=C; export LC_ALL; " + command)
+	if err != nil {
+		var exitErr *ssh.ExitError
+		if errors.As(err, &exitErr) {
+			return outBuf.Bytes(), errBuf.Bytes(), exitErr.ExitStatus(), nil
+		}
+		return outBuf.Bytes(), errBuf.Bytes(), -1, &ConnectionError{Op: "exec", Machine: c.name, Err: err}
+	}
+	return outBuf.Bytes(), errBuf.Bytes(), 0, nil
+}
+
+// fileOp runs a shell snippet for a file operation on path and maps the
+// conventional exit codes to NotFoundError/PermissionError.
+func (c *SSHConnection) fileOp(op, path, command string, stdin []byte) ([]byte, error) {
+	stdout, stderr, status, err := c.run(command, stdin)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case 0:
+		return stdout, nil
+	case exitNotFound:
+		return nil, &NotFoundError{Path: path}
+	case exitPermission:
+		return nil, &PermissionError{Path: path, Op: op}
+	default:
+		return nil, fmt.Errorf("%s %s on %s: exit status %d: %s", op, path, c.name, status, strings.TrimSpace(string(stderr)))
+	}
+}
+
+// ReadFile reads the named file.
+func (c *SSHConnection) ReadFile(path string) ([]byte, error) {
+	p := shellQuote(path)
+	cmd := fmt.Sprintf(`[ -e %[1]s ] || exit %[2]d; [ -r %[1]s ] || exit %[3]d; cat -- %[1]s`, p, exitNotFound, exitPermission)
+	return c.fileOp("read", path, cmd, nil)
+}
+
+// WriteFile writes data to the named file. As with os.WriteFile, perm is only
+// applied when the file is created.
+func (c *SSHConnection) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	p := shellQuote(path)
+	cmd := fmt.Sprintf(`new=0; if [ -e %[1]s ]; then [ -w %[1]s ] || exit %[2]d; else new=1; d=$(dirname -- %[1]s); [ ! -d "$d" ] || [ -w "$d" ] || exit %[2]d; fi; `+
+		`cat > %[1]s || exit 1; if [ $new = 1 ]; then chmod %[3]o %[1]s; fi`, p, exitPermission, perm.Perm())
+	_, err := c.fileOp("write", path, cmd, data)
+	return err
+}
+
+// MkdirAll creates a directory and all parent directories.
+func (c *SSHConnection) MkdirAll(path string, perm fs.FileMode) error {
+	p := shellQuote(path)
+	cmd := fmt.Sprintf(`[ -d %[1]s ] && exit 0; mkdir -p -m %[2]o -- %[1]s 2>/dev/null && exit 0; `+
+		`d=%[1]s; while [ ! -e "$d" ]; do d=$(dirname -- "$d"); done; [ -w "$d" ] || exit %[3]d; exit 1`, p, perm.Perm(), exitPermission)
+	_, err := c.fileOp("mkdir", path, cmd, nil)
+	return err
+}
+
+// Remove removes the named file or empty directory. Missing paths are not an error.
+func (c *SSHConnection) Remove(path string) error {
+	p := shellQuote(path)
+	cmd := fmt.Sprintf(`[ -e %[1]s ] || [ -L %[1]s ] || exit 0; [ -w "$(dirname -- %[1]s)" ] || exit %[2]d; `+
+		`if [ -d %[1]s ] && [ ! -L %[1]s ]; then rmdir -- %[1]s; else rm -f -- %[1]s; fi`, p, exitPermission)
+	_, err := c.fileOp("remove", path, cmd, nil)
+	return err
+}
+
+// RemoveAll removes the named file or directory and any children.
+func (c *SSHConnection) RemoveAll(path string) error {
+	p := shellQuote(path)
+	cmd := fmt.Sprintf(`[ -e %[1]s ] || [ -L %[1]s ] || exit 0; rm -rf -- %[1]s 2>/dev/null && exit 0; exit %[2]d`, p, exitPermission)
+	_, err := c.fileOp("remove", path, cmd, nil)
+	return err
+}
+
+// Stat returns file info for the named file, following symlinks.
+// Supports both GNU (stat -c) and BSD (stat -f) stat.
+func (c *SSHConnection) Stat(path string) (FileInfo, error) {
+	p := shellQuote(path)
+	cmd := fmt.Sprintf(`[ -e %[1]s ] || exit %[2]d; stat -L -c '%%s %%f %%Y' -- %[1]s 2>/dev/null || stat -L -f '%%z %%Xp %%m' -- %[1]s`, p, exitNotFound)
+	out, err := c.fileOp("stat", path, cmd, nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseStatOutput(filepath.Base(path), string(out))
+}
+
+// parseStatOutput parses "<size> <raw mode hex> <mtime epoch>" into a FileInfo.
+func parseStatOutput(name, out string) (FileInfo, error) {
+	fields := strings.Fields(out)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected stat output %q", strings.TrimSpace(out))
+	}
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing size %q: %w", fields[0], err)
+	}
+	rawMode, err := strconv.ParseUint(fields[1], 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("parsing mode %q: %w", fields[1], err)
+	}
+	mtime, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing mtime %q: %w", fields[2], err)
+	}
+
+	mode := unixModeToFileMode(uint32(rawMode))
+	return BasicFileInfo{
+		FileName:    name,
+		FileSize:    size,
+		FileMode:    mode,
+		FileModTime: time.Unix(mtime, 0),
+		FileIsDir:   mode.IsDir(),
+	}, nil
+}
+
+// unixModeToFileMode converts a raw st_mode value to an fs.FileMode.
+func unixModeToFileMode(m uint32) fs.FileMode {
+	mode := fs.FileMode(m & 0777)
+	switch m & 0170000 {
+	case 0040000:
+		mode |= fs.ModeDir
+	case 0120000:
+		mode |= fs.ModeSymlink
+	case 0010000:
+		mode |= fs.ModeNamedPipe
+	case 0140000:
+		mode |= fs.ModeSocket
+	case 0020000:
+		mode |= fs.ModeDevice | fs.ModeCharDevice
+	case 0060000:
+		mode |= fs.ModeDevice
+	}
+	if m&04000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if m&02000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if m&01000 != 0 {
+		mode |= fs.ModeSticky
+	}
+	return mode
+}
+
+// Glob returns the names of all files matching the pattern, with the
+// syntax of filepath.Glob. The pattern never reaches the remote shell:
+// directories are listed remotely and matched here with path.Match.
+func (c *SSHConnection) Glob(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	if !hasGlobMeta(pattern) {
+		exists, err := c.Exists(pattern)
+		if err != nil || !exists {
+			return nil, err
+		}
+		return []string{pattern}, nil
+	}
+
+	dir, file := path.Split(pattern)
+	switch dir {
+	case "":
+		dir = "."
+	case "/":
+	default:
+		dir = dir[:len(dir)-1] // Strip the trailing slash
+	}
+	dirs := []string{dir}
+	if hasGlobMeta(dir) {
+		var err error
+		if dirs, err = c.Glob(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	var matches []string
+	for _, d := range dirs {
+		names, err := c.listDir(d)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if ok, _ := path.Match(file, name); ok {
+				matches = append(matches, path.Join(d, name))
+			}
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// listDir returns the entry names in a remote directory, or nothing if it
+// is not a readable directory.
+func (c *SSHConnection) listDir(dir string) ([]string, error) {
+	cmd := `cd ` + shellQuote(dir) + ` 2>/dev/null || exit 0; for f in * .*; do if [ -e "$f" ] || [ -L "$f" ]; then printf '%s\0' "$f"; fi; done`
+	stdout, stderr, status, err := c.run(cmd, nil)
+	if err != nil {
+		return nil, err
+	}
+	if status != 0 {
+		return nil, fmt.Errorf("listing %s on %s: %s", dir, c.name, strings.TrimSpace(string(stderr)))
+	}
+	var names []string
+	for _, name := range strings.Split(string(stdout), "\x00") {
+		if name != "" && name != "." && name != ".." {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// hasGlobMeta reports whether p contains any of the magic characters
+// recognized by path.Match.
+func hasGlobMeta(p string) bool {
+	return strings.ContainsAny(p, `*?[\`)
+}
+
+// Exists returns true if the path exists.
+func (c *SSHConnection) Exists(path string) (bool, error) {
+	_, stderr, status, err := c.run("test -e "+shellQuote(path), nil)
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case 0:
+		return true, nil
+	case 1:
+		return false, nil
+	default:
+		return false, fmt.Errorf("checking %s on %s: %s", path, c.name, strings.TrimSpace(string(stderr)))
+	}
+}
+
+// execCombined runs a command and returns its combined output. A non-zero
+// exit status is returned as an error, mirroring exec.Cmd.CombinedOutput.
+func (c *SSHConnection) execCombined(command string) ([]byte, error) {
+	stdout, stderr, status, err := c.run(command, nil)
+	out := append(stdout, stderr...)
+	if err != nil {
+		return out, err
+	}
+	if status != 0 {
+		return out, fmt.Errorf("exit status %d", status)
+	}
+	return out, nil
+}
+
+// Exec runs a command and returns its combined output.
+func (c *SSHConnection) Exec(cmd string, args ...string) ([]byte, error) {
+	return c.execCombined(shellJoin(cmd, args))
+}
+
+// ExecDir runs a command in the specified directory.
+func (c *SSHConnection) ExecDir(dir, cmd string, args ...string) ([]byte, error) {
+	return c.execCombined("cd " + shellQuote(dir) + " && " + shellJoin(cmd, args))
+}
+
+// ExecEnv runs a command with additional environment variables.
+func (c *SSHConnection) ExecEnv(env map[string]string, cmd string, args ...string) ([]byte, error) {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString("env")
+	for _, k := range keys {
+		sb.WriteString(" ")
+		sb.WriteString(shellQuote(k + "=" + env[k]))
+	}
+	sb.WriteString(" ")
+	sb.WriteString(shellJoin(cmd, args))
+	return c.execCombined(sb.String())
+}
+
+// tmux runs a tmux subcommand on the remote host.
+func (c *SSHConnection) tmux(args ...string) (string, error) {
+	out, err := c.execCombined(shellJoin("tmux", args))
+	if err != nil {
+		return "", fmt.Errorf("tmux %s on %s: %w: %s", args[0], c.name, err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// TmuxNewSession creates a new detached tmux session on the remote host.
+func (c *SSHConnection) TmuxNewSession(name, dir string) error {
+	args := []string{"new-session", "-d", "-s", name}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	_, err := c.tmux(args...)
+	return err
+}
+
+// TmuxKillSession terminates a remote tmux session.
+// Like the local implementation, the pane's process tree is terminated first
+// (SIGTERM, then SIGKILL after a grace period) so agents don't outlive the session.
+func (c *SSHConnection) TmuxKillSession(name string) error {
+	target := shellQuote("=" + name)
+	cmd := fmt.Sprintf(`pid=$(tmux display-message -p -t %[1]s '#{pane_pid}' 2>/dev/null); `+
+		`if [ -n "$pid" ]; then `+
+		`kids() { for k in $(pgrep -P "$1" 2>/dev/null); do kids "$k"; echo "$k"; done; }; `+
+		`all="$(kids "$pid") $pid"; kill -TERM $all 2>/dev/null; sleep 2; kill -KILL $all 2>/dev/null; fi; `+
+		`tmux kill-session -t %[1]s 2>/dev/null; exit 0`, target)
+	_, err := c.execCombined(cmd)
+	return err
+}
+
+// TmuxSendKeys sends keys to a remote tmux session, followed by Enter.
+// The text is sent literally (-l), matching tmux.SendKeys.
+func (c *SSHConnection) TmuxSendKeys(session, keys string) error {
+	if _, err := c.tmux("send-keys", "-t", session, "-l", keys); err != nil {
+		return err
+	}
+	time.Sleep(100 * time.Millisecond)
+	_, err := c.tmux("send-keys", "-t", session, "Enter")
+	return err
+}
+
+// TmuxCapturePane captures the last N lines from a remote tmux pane.
+func (c *SSHConnection) TmuxCapturePane(session string, lines int) (string, error) {
+	return c.tmux("capture-pane", "-p", "-t", session, "-S", fmt.Sprintf("-%d", lines))
+}
+
+// TmuxHasSession returns true if the remote session exists.
+func (c *SSHConnection) TmuxHasSession(name string) (bool, error) {
+	_, _, status, err := c.run(shellJoin("tmux", []string{"has-session", "-t", "=" + name}), nil)
+	if err != nil {
+		return false, err
+	}
+	return status == 0, nil
+}
+
+// TmuxListSessions returns all remote tmux session names.
+// Returns an empty list when no tmux server is running.
+func (c *SSHConnection) TmuxListSessions() ([]string, error) {
+	stdout, stderr, status, err := c.run(shellJoin("tmux", []string{"list-sessions", "-F", "#{session_name}"}), nil)
+	if err != nil {
+		return nil, err
+	}
+	if status != 0 {
+		msg := string(stderr)
+		if strings.Contains(msg, "no server running") || strings.Contains(msg, "error connecting") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("tmux list-sessions on %s: %s", c.name, strings.TrimSpace(msg))
+	}
+	var sessions []string
+	for _, line := range strings.Split(string(stdout), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			sessions = append(sessions, line)
+		}
+	}
+	return sessions, nil
+}
+
+// shellQuote quotes s for safe use as a single POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellJoin quotes a command and its arguments into a shell command line.
+func shellJoin(cmd string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, shellQuote(cmd))
+	for _, a := range args {
+		parts = append(parts, shellQuote(a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// expandHome expands a leading ~/ to the user's home directory.
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
+
+// Verify SSHConnection implements Connection.
+var _ Connection = (*SSHConnection)(nil)
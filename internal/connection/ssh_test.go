@@ -0,0 +1,496 @@
+package connection
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// startTestSSHServer starts an in-process SSH server on localhost that runs
+// "exec" requests with the local sh. Returns a connection configured with a
+// generated client key and a known_hosts file containing the server key.
+func startTestSSHServer(t *testing.T) *SSHConnection {
+	t.Helper()
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientPub, clientPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorized, err := ssh.NewPublicKey(clientPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) == string(authorized.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unauthorized key")
+		},
+	}
+	cfg.AddHostKey(hostSigner)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSSHConn(nc, cfg)
+		}
+	}()
+
+	dir := t.TempDir()
+	keyBlock, err := ssh.MarshalPrivateKey(clientPriv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(keyBlock), 0600); err != nil {
+		t.Fatal(err)
+	}
+	khPath := filepath.Join(dir, "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(ln.Addr().String())}, hostSigner.PublicKey())
+	if err := os.WriteFile(khPath, []byte(line+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	conn := NewSSHConnection("testbox", SSHConfig{
+		Host:           "tester@" + ln.Addr().String(),
+		KeyPath:        keyPath,
+		KnownHostsPath: khPath,
+	})
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// serveTestSSHConn handles session channels with exec requests.
+func serveTestSSHConn(nc net.Conn, cfg *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(nc, cfg)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newCh := range chans {
+		if newCh.ChannelType() != "session" {
+			_ = newCh.Reject(ssh.UnknownChannelType, "unsupported")
+			continue
+		}
+		ch, chReqs, err := newCh.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			defer ch.Close()
+			for req := range chReqs {
+				if req.Type != "exec" {
+					_ = req.Reply(false, nil)
+					continue
+				}
+				var payload struct{ Command string }
+				_ = ssh.Unmarshal(req.Payload, &payload)
+				_ = req.Reply(true, nil)
+
+				cmd := exec.Command("sh", "-c", payload.Command)
+				cmd.Stdin = ch
+				cmd.Stdout = ch
+				cmd.Stderr = ch.Stderr()
+				status := uint32(0)
+				if err := cmd.Run(); err != nil {
+					var exitErr *exec.ExitError
+					if errors.As(err, &exitErr) {
+						status = uint32(exitErr.ExitCode())
+					} else {
+						status = 255
+					}
+				}
+				buf := make([]byte, 4)
+				binary.BigEndian.PutUint32(buf, status)
+				_, _ = ch.SendRequest("exit-status", false, buf)
+				return
+			}
+		}()
+	}
+}
+
+func TestParseSSHHost(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantUser string
+		wantAddr string
+		wantErr  bool
+	}{
+		{"alice@box", "alice", "box:22", false},
+		{"alice@box:2222", "alice", "box:2222", false},
+		{"alice@[::1]:2200", "alice", "[::1]:2200", false},
+		{"", "", "", true},
+		{"alice@", "", "", true},
+	}
+	for _, tt := range tests {
+		u, addr, err := parseSSHHost(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSSHHost(%q) err = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if u != tt.wantUser || addr != tt.wantAddr {
+			t.Errorf("parseSSHHost(%q) = %q, %q; want %q, %q", tt.input, u, addr, tt.wantUser, tt.wantAddr)
+		}
+	}
+}
+
+func TestSSHConnection_GlobNeverRunsPattern(t *testing.T) {
+	conn := startTestSSHServer(t)
+	dir := t.TempDir()
+	t.Chdir(dir) // Where the remote shell would create the marker
+	for _, name := range []string{"x1", "xa", ".hidden", "y[1]"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, pattern := range []string{"x[$(touch${IFS}pwned)]*", "x`touch pwned`*", "x[;touch pwned;]"} {
+		if _, err := conn.Glob(filepath.Join(dir, pattern)); err != nil {
+			t.Fatalf("Glob(%q): %v", pattern, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pwned")); err == nil {
+		t.Fatal("glob pattern ran a command on the remote host")
+	}
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"x[0-9]", []string{"x1"}},
+		{"x[^0-9]", []string{"xa"}},
+		{"*", []string{".hidden", "x1", "xa", "y[1]"}},
+		{`y\[1]`, []string{"y[1]"}},
+		{"z*", nil},
+	}
+	for _, tt := range tests {
+		got, err := conn.Glob(filepath.Join(dir, tt.pattern))
+		if err != nil {
+			t.Fatalf("Glob(%q): %v", tt.pattern, err)
+		}
+		var want []string
+		for _, name := range tt.want {
+			want = append(want, filepath.Join(dir, name))
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("Glob(%q) = %v, want %v", tt.pattern, got, want)
+		}
+	}
+}
+
+func TestSSHConnection_FileOps(t *testing.T) {
+	conn := startTestSSHServer(t)
+	dir := t.TempDir()
+
+	if conn.IsLocal() {
+		t.Error("ssh connection should not be local")
+	}
+	if conn.Name() != "testbox" {
+		t.Errorf("Name() = %q, want testbox", conn.Name())
+	}
+
+	nested := filepath.Join(dir, "a b", "c")
+	if err := conn.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	path := filepath.Join(nested, "it's.txt")
+	content := []byte("hello\nremote world\n")
+	if err := conn.WriteFile(path, content, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	got, err := conn.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != string(content) {
+		t.Errorf("ReadFile = %q, want %q", got, content)
+	}
+
+	fi, err := conn.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if fi.Size() != int64(len(content)) || fi.IsDir() || fi.Mode().Perm() != 0600 {
+		t.Errorf("Stat = size %d dir %v mode %v", fi.Size(), fi.IsDir(), fi.Mode())
+	}
+	if di, err := conn.Stat(nested); err != nil || !di.IsDir() {
+		t.Errorf("Stat(dir) = %v, %v; want directory", di, err)
+	}
+
+	exists, err := conn.Exists(path)
+	if err != nil || !exists {
+		t.Errorf("Exists = %v, %v; want true", exists, err)
+	}
+
+	matches, err := conn.Glob(filepath.Join(nested, "*.txt"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != 1 || matches[0] != path {
+		t.Errorf("Glob = %v, want [%s]", matches, path)
+	}
+	if none, err := conn.Glob(filepath.Join(nested, "*.json")); err != nil || len(none) != 0 {
+		t.Errorf("Glob(no match) = %v, %v; want empty", none, err)
+	}
+
+	if err := conn.Remove(path); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if exists, _ := conn.Exists(path); exists {
+		t.Error("file still exists after Remove")
+	}
+	if err := conn.Remove(path); err != nil {
+		t.Errorf("Remove of missing file should be nil, got %v", err)
+	}
+
+	if err := conn.RemoveAll(filepath.Join(dir, "a b")); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+	if exists, _ := conn.Exists(nested); exists {
+		t.Error("directory still exists after RemoveAll")
+	}
+}
+
+func TestSSHConnection_Errors(t *testing.T) {
+	conn := startTestSSHServer(t)
+	dir := t.TempDir()
+
+	_, err := conn.ReadFile(filepath.Join(dir, "missing"))
+	var nf *NotFoundError
+	if !errors.As(err, &nf) {
+		t.Errorf("ReadFile(missing) = %v, want NotFoundError", err)
+	}
+
+	_, err = conn.Stat(filepath.Join(dir, "missing"))
+	if !errors.As(err, &nf) {
+		t.Errorf("Stat(missing) = %v, want NotFoundError", err)
+	}
+
+	if os.Geteuid() != 0 {
+		locked := filepath.Join(dir, "locked")
+		if err := os.WriteFile(locked, []byte("x"), 0000); err != nil {
+			t.Fatal(err)
+		}
+		_, err = conn.ReadFile(locked)
+		var pe *PermissionError
+		if !errors.As(err, &pe) {
+			t.Errorf("ReadFile(unreadable) = %v, want PermissionError", err)
+		}
+	}
+}
+
+func TestSSHConnection_Exec(t *testing.T) {
+	conn := startTestSSHServer(t)
+	dir := t.TempDir()
+
+	out, err := conn.Exec("echo", "hello", "it's me")
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "hello it's me" {
+		t.Errorf("Exec output = %q", out)
+	}
+
+	out, err = conn.ExecDir(dir, "pwd")
+	if err != nil {
+		t.Fatalf("ExecDir: %v", err)
+	}
+	gotDir, _ := filepath.EvalSymlinks(strings.TrimSpace(string(out)))
+	wantDir, _ := filepath.EvalSymlinks(dir)
+	if gotDir != wantDir {
+		t.Errorf("ExecDir pwd = %q, want %q", gotDir, wantDir)
+	}
+
+	out, err = conn.ExecEnv(map[string]string{"GT_TEST_VAR": "a b'c"}, "sh", "-c", "printf %s \"$GT_TEST_VAR\"")
+	if err != nil {
+		t.Fatalf("ExecEnv: %v", err)
+	}
+	if string(out) != "a b'c" {
+		t.Errorf("ExecEnv output = %q", out)
+	}
+
+	out, err = conn.Exec("sh", "-c", "echo oops >&2; exit 3")
+	if err == nil {
+		t.Error("expected error for non-zero exit")
+	}
+	if !strings.Contains(string(out), "oops") {
+		t.Errorf("combined output should include stderr, got %q", out)
+	}
+}
+
+func TestSSHConnection_AgentClosedAfterHandshake(t *testing.T) {
+	conn := startTestSSHServer(t)
+
+	// Serve the generated client key from an in-process agent instead
+	pemBytes, err := os.ReadFile(conn.config.KeyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.ParseRawPrivateKey(pemBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+		t.Fatal(err)
+	}
+	sock := filepath.Join(t.TempDir(), "agent.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	served := make(chan struct{})
+	go func() {
+		nc, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		_ = agent.ServeAgent(keyring, nc) // Returns when the client hangs up
+		close(served)
+	}()
+	t.Setenv("SSH_AUTH_SOCK", sock)
+	conn.config.KeyPath = ""
+
+	if _, err := conn.Exec("true"); err != nil {
+		t.Fatalf("Exec with agent auth: %v", err)
+	}
+	select {
+	case <-served:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ssh agent connection still open after the handshake")
+	}
+}
+
+func TestSSHConnection_Tmux(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	conn := startTestSSHServer(t)
+
+	// Use a private tmux server so the test doesn't touch the user's sessions.
+	socketDir := t.TempDir()
+	t.Setenv("TMUX_TMPDIR", socketDir)
+	t.Setenv("TMUX", "")
+
+	name := "gt-ssh-test"
+	if err := conn.TmuxNewSession(name, socketDir); err != nil {
+		t.Fatalf("TmuxNewSession: %v", err)
+	}
+	defer func() { _ = conn.TmuxKillSession(name) }()
+
+	has, err := conn.TmuxHasSession(name)
+	if err != nil || !has {
+		t.Fatalf("TmuxHasSession = %v, %v; want true", has, err)
+	}
+
+	sessions, err := conn.TmuxListSessions()
+	if err != nil {
+		t.Fatalf("TmuxListSessions: %v", err)
+	}
+	found := false
+	for _, s := range sessions {
+		if s == name {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("TmuxListSessions = %v, missing %s", sessions, name)
+	}
+
+	if err := conn.TmuxSendKeys(name, "echo gt-marker-$((40+2))"); err != nil {
+		t.Fatalf("TmuxSendKeys: %v", err)
+	}
+	var captured string
+	for i := 0; i < 30; i++ {
+		captured, err = conn.TmuxCapturePane(name, 50)
+		if err == nil && strings.Contains(captured, "gt-marker-42") {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if !strings.Contains(captured, "gt-marker-42") {
+		t.Errorf("TmuxCapturePane did not show command output:\n%s", captured)
+	}
+
+	if err := conn.TmuxKillSession(name); err != nil {
+		t.Fatalf("TmuxKillSession: %v", err)
+	}
+	if has, _ := conn.TmuxHasSession(name); has {
+		t.Error("session still exists after TmuxKillSession")
+	}
+}
+
+func TestSSHConnection_ConnectError(t *testing.T) {
+	conn := NewSSHConnection("nowhere", SSHConfig{
+		Host:            "nobody@127.0.0.1:1",
+		KeyPath:         filepath.Join(t.TempDir(), "missing-key"),
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), //nolint:gosec // test never reaches a host
+	})
+	_, err := conn.Exec("true")
+	var ce *ConnectionError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected ConnectionError, got %v", err)
+	}
+	if ce.Machine != "nowhere" {
+		t.Errorf("ConnectionError.Machine = %q", ce.Machine)
+	}
+}
+
+func TestMachineRegistry_SSHConnection(t *testing.T) {
+	reg, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Add(&Machine{Name: "big", Type: "ssh", Host: "gt@big.example"}); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := reg.Connection("big")
+	if err != nil {
+		t.Fatalf("Connection: %v", err)
+	}
+	if _, ok := conn.(*SSHConnection); !ok {
+		t.Errorf("expected *SSHConnection, got %T", conn)
+	}
+	var _ io.Closer = conn.(*SSHConnection)
+}
@@ -0,0 +1,135 @@
+package convoy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Convoy budgets live in the convoy description next to Owner and Notify:
+//
+//	Budget-Daily: 25.00
+//	Budget-Total: 200.00
+//	Budget-Warn: 80
+const (
+	budgetDailyKey = "Budget-Daily"
+	budgetTotalKey = "Budget-Total"
+	budgetWarnKey  = "Budget-Warn"
+)
+
+// LabelBudgetExceeded marks a convoy whose spend reached its cap. The daemon
+// sets it; gt sling and convoy feeding refuse to spawn polecats for the
+// convoy's issues while it is present.
+const LabelBudgetExceeded = "budget:exceeded"
+
+// ParseBudget returns the budget stored in a convoy description, or nil if
+// it has none.
+func ParseBudget(description string) *config.BudgetConfig {
+	var b config.BudgetConfig
+	found := false
+	for _, line := range strings.Split(description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimPrefix(strings.TrimSpace(value), "$")
+		switch strings.TrimSpace(key) {
+		case budgetDailyKey:
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				b.DailyUSD, found = v, true
+			}
+		case budgetTotalKey:
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				b.TotalUSD, found = v, true
+			}
+		case budgetWarnKey:
+			if v, err := strconv.Atoi(strings.TrimSuffix(value, "%")); err == nil {
+				b.WarnPercent = v
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+	return &b
+}
+
+// SetBudget returns the description with its budget lines replaced by b.
+// Zero caps are omitted; a nil or zero budget removes the lines.
+func SetBudget(description string, b *config.BudgetConfig) string {
+	var lines []string
+	for _, line := range strings.Split(description, "\n") {
+		key, _, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok {
+			switch strings.TrimSpace(key) {
+			case budgetDailyKey, budgetTotalKey, budgetWarnKey:
+				continue
+			}
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if !b.IsZero() {
+		if b.DailyUSD > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %.2f", budgetDailyKey, b.DailyUSD))
+		}
+		if b.TotalUSD > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %.2f", budgetTotalKey, b.TotalUSD))
+		}
+		if b.WarnPercent > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %d", budgetWarnKey, b.WarnPercent))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// IsBudgetExceeded reports whether a convoy carries the budget-exceeded label.
+func IsBudgetExceeded(issue *beads.Issue) bool {
+	if issue == nil {
+		return false
+	}
+	for _, label := range issue.Labels {
+		if label == LabelBudgetExceeded {
+			return true
+		}
+	}
+	return false
+}
+
+// TrackedIssueIDs returns the IDs of the issues a convoy tracks.
+func TrackedIssueIDs(store beads.Store, convoyID string) ([]string, error) {
+	deps, err := store.ListDependencies(convoyID, beads.DepListOptions{
+		Direction: beads.DepDirectionDown,
+		Type:      "tracks",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing tracked issues: %w", err)
+	}
+	ids := make([]string, len(deps))
+	for i, d := range deps {
+		ids[i] = extractIssueID(d.ID)
+	}
+	return ids, nil
+}
+
+// BudgetBlocks returns the open convoys tracking issueID that are over
+// budget. Spawning work for the issue should wait until they are released.
+func BudgetBlocks(store beads.Store, issueID string) []string {
+	var blocked []string
+	for _, convoyID := range getTrackingConvoys(store, issueID) {
+		issue, err := store.Show(convoyID)
+		if err != nil || issue.Status == "closed" {
+			continue
+		}
+		if IsBudgetExceeded(issue) {
+			blocked = append(blocked, convoyID)
+		}
+	}
+	return blocked
+}
@@ -0,0 +1,62 @@
+package convoy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestBudgetRoundTrip(t *testing.T) {
+	desc := "Convoy tracking 2 issues\nOwner: mayor/\nNotify: ops/"
+
+	if b := ParseBudget(desc); b != nil {
+		t.Fatalf("ParseBudget(no budget) = %+v, want nil", b)
+	}
+
+	desc = SetBudget(desc, &config.BudgetConfig{DailyUSD: 25, TotalUSD: 200, WarnPercent: 90})
+	want := "Convoy tracking 2 issues\nOwner: mayor/\nNotify: ops/\nBudget-Daily: 25.00\nBudget-Total: 200.00\nBudget-Warn: 90"
+	if desc != want {
+		t.Errorf("SetBudget =\n%s\nwant\n%s", desc, want)
+	}
+	got := ParseBudget(desc)
+	if !reflect.DeepEqual(got, &config.BudgetConfig{DailyUSD: 25, TotalUSD: 200, WarnPercent: 90}) {
+		t.Errorf("ParseBudget = %+v", got)
+	}
+
+	// Raising the cap replaces the old lines
+	desc = SetBudget(desc, &config.BudgetConfig{TotalUSD: 500})
+	if got := ParseBudget(desc); got.TotalUSD != 500 || got.DailyUSD != 0 || got.WarnPercent != 0 {
+		t.Errorf("after raise ParseBudget = %+v", got)
+	}
+	if desc = SetBudget(desc, nil); ParseBudget(desc) != nil {
+		t.Errorf("SetBudget(nil) left a budget: %q", desc)
+	}
+
+	if b := ParseBudget("Budget-Total: $75"); b == nil || b.TotalUSD != 75 {
+		t.Errorf("ParseBudget($75) = %+v", b)
+	}
+}
+
+func TestBudgetBlocks(t *testing.T) {
+	store, convoyID, issueID := newTrackingStore(t)
+
+	if got := BudgetBlocks(store, issueID); len(got) != 0 {
+		t.Fatalf("BudgetBlocks = %v, want none", got)
+	}
+	if err := store.Update(convoyID, beads.UpdateOptions{AddLabels: []string{LabelBudgetExceeded}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := BudgetBlocks(store, issueID); !reflect.DeepEqual(got, []string{convoyID}) {
+		t.Errorf("BudgetBlocks = %v, want [%s]", got, convoyID)
+	}
+
+	ids, err := TrackedIssueIDs(store, convoyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []string{issueID, "gt-abc"}) {
+		t.Errorf("TrackedIssueIDs = %v", ids)
+	}
+}
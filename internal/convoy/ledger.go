@@ -0,0 +1,100 @@
+package convoy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/ledger"
+)
+
+// ExportLanded appends the ledger record for a landed convoy. It does
+// nothing if the convoy is still open; landing reported by several
+// observers is recorded once. It reports whether a record was added.
+func ExportLanded(store beads.Store, townRoot, convoyID string) (bool, error) {
+	rec, err := LandedRecord(store, ledger.Path(townRoot), convoyID)
+	if err != nil || rec == nil {
+		return false, err
+	}
+	_, added, err := ledger.Append(townRoot, *rec)
+	return added, err
+}
+
+// LandedRecord builds the completion record of a closed convoy, or returns
+// nil if the convoy is open. Diff stats are summed from the merge records
+// of its beads already in the ledger at ledgerPath.
+func LandedRecord(store beads.Store, ledgerPath, convoyID string) (*ledger.Record, error) {
+	issue, err := store.Show(convoyID)
+	if err != nil {
+		return nil, fmt.Errorf("showing convoy %s: %w", convoyID, err)
+	}
+	if issue.Status != "closed" {
+		return nil, nil
+	}
+
+	rec := &ledger.Record{
+		Trigger:   ledger.TriggerLanded,
+		Bead:      convoyID,
+		Title:     issue.Title,
+		Started:   ledger.ParseTime(issue.CreatedAt),
+		Completed: time.Now().UTC(),
+		Outcome:   "landed",
+		Ref:       issue.ClosedAt,
+	}
+	if closed := ledger.ParseTime(issue.ClosedAt); closed != nil {
+		rec.Completed = *closed
+	}
+
+	tracked := getConvoyTrackedIssues(store, convoyID)
+	agents := make(map[string]bool)
+	for _, t := range tracked {
+		rec.Beads = append(rec.Beads, t.ID)
+		if t.Assignee != "" {
+			agents[t.Assignee] = true
+		}
+	}
+	for a := range agents {
+		rec.Agents = append(rec.Agents, a)
+	}
+	sort.Strings(rec.Agents)
+	if rigs := rigsOf(rec.Agents); len(rigs) == 1 {
+		rec.Rig = rigs[0]
+	}
+	rec.CostUSD = ledger.WorkCost(rec.Beads...)
+
+	// The convoy's diff is the sum of its merged beads' diffs
+	records, err := ledger.Records(ledgerPath)
+	if err != nil {
+		return nil, err
+	}
+	inConvoy := make(map[string]bool, len(rec.Beads))
+	for _, id := range rec.Beads {
+		inConvoy[id] = true
+	}
+	for _, r := range records {
+		if r.Trigger == ledger.TriggerMerged && inConvoy[r.Bead] && r.Diff != nil {
+			if rec.Diff == nil {
+				rec.Diff = &ledger.Diff{}
+			}
+			rec.Diff.Add(r.Diff)
+		}
+	}
+	return rec, nil
+}
+
+// rigsOf returns the distinct rigs of agent addresses ("gastown/polecats/Toast").
+// Town-level agents such as "mayor" have no rig.
+func rigsOf(agents []string) []string {
+	seen := make(map[string]bool)
+	var rigs []string
+	for _, a := range agents {
+		rig, _, ok := strings.Cut(a, "/")
+		if ok && !seen[rig] {
+			seen[rig] = true
+			rigs = append(rigs, rig)
+		}
+	}
+	return rigs
+}
@@ -0,0 +1,54 @@
+package convoy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/ledger"
+)
+
+func TestExportLanded(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // No session cost log
+	town := t.TempDir()
+	store, convoyID, issueID := newTrackingStore(t)
+
+	// An open convoy has not landed
+	if added, err := ExportLanded(store, town, convoyID); err != nil || added {
+		t.Fatalf("open convoy: added %v, err %v", added, err)
+	}
+
+	assignee := "gastown/polecats/nux"
+	if err := store.Update(issueID, beads.UpdateOptions{Assignee: &assignee}); err != nil {
+		t.Fatal(err)
+	}
+	merged := ledger.Record{Trigger: ledger.TriggerMerged, Bead: issueID, Completed: time.Now(), Outcome: "merged",
+		Diff: &ledger.Diff{Files: []string{"main.go"}, Changed: 1, Added: 12, Removed: 3}}
+	if _, _, err := ledger.Append(town, merged); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(issueID, convoyID); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []bool{true, false} { // A second observer adds nothing
+		if added, err := ExportLanded(store, town, convoyID); err != nil || added != want {
+			t.Fatalf("export %d: added %v, err %v; want %v", i, added, err, want)
+		}
+	}
+
+	records, err := ledger.Records(ledger.Path(town))
+	if err != nil || len(records) != 2 {
+		t.Fatalf("records = %d, err %v", len(records), err)
+	}
+	rec := records[1]
+	if rec.Trigger != ledger.TriggerLanded || rec.Bead != convoyID || rec.Outcome != "landed" {
+		t.Errorf("record = %+v", rec)
+	}
+	if len(rec.Beads) != 2 || len(rec.Agents) != 1 || rec.Agents[0] != assignee || rec.Rig != "gastown" {
+		t.Errorf("beads %v agents %v rig %q", rec.Beads, rec.Agents, rec.Rig)
+	}
+	if rec.Diff == nil || rec.Diff.Added != 12 || rec.Diff.Removed != 3 {
+		t.Errorf("diff = %+v, want the merged bead's", rec.Diff)
+	}
+}
@@ -3,7 +3,6 @@ package convoy
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -34,8 +33,14 @@ func CheckConvoysForIssue(townRoot, issueID, observer string, logger func(format
 		logger = func(format string, args ...interface{}) {} // no-op
 	}
 
+	return checkConvoysForIssue(beads.New(townRoot), townRoot, issueID, observer, logger)
+}
+
+// checkConvoysForIssue is CheckConvoysForIssue with the beads store supplied,
+// so tests can run it against a MemStore.
+func checkConvoysForIssue(store beads.Store, townRoot, issueID, observer string, logger func(format string, args ...interface{})) []string {
 	// Find convoys tracking this issue
-	convoyIDs := getTrackingConvoys(townRoot, issueID)
+	convoyIDs := getTrackingConvoys(store, issueID)
 	if len(convoyIDs) == 0 {
 		return nil
 	}
@@ -45,7 +50,7 @@ func CheckConvoysForIssue(townRoot, issueID, observer string, logger func(format
 	// Run convoy check for each tracking convoy
 	// Note: gt convoy check is idempotent and handles already-closed convoys
 	for _, convoyID := range convoyIDs {
-		if isConvoyClosed(townRoot, convoyID) {
+		if isConvoyClosed(store, convoyID) {
 			logger("%s: convoy %s already closed, skipping", observer, convoyID)
 			continue
 		}
@@ -55,11 +60,16 @@ func CheckConvoysForIssue(townRoot, issueID, observer string, logger func(format
 			logger("%s: convoy check failed: %v", observer, err)
 		}
 
-		// Continuation feed: if convoy is still open after the completion check,
-		// reactively dispatch the next ready issue. This makes convoy feeding
-		// event-driven instead of relying on polling-based patrol cycles.
-		if !isConvoyClosed(townRoot, convoyID) {
-			feedNextReadyIssue(townRoot, convoyID, observer, logger)
+		// A convoy the check closed has landed: record it in the ledger.
+		// Otherwise, continuation feed: reactively dispatch the next ready
+		// issue. This makes convoy feeding event-driven instead of relying on
+		// polling-based patrol cycles.
+		if isConvoyClosed(store, convoyID) {
+			if _, err := ExportLanded(store, townRoot, convoyID); err != nil {
+				logger("%s: ledger export of convoy %s failed: %v", observer, convoyID, err)
+			}
+		} else {
+			feedNextReadyIssue(store, townRoot, convoyID, observer, logger)
 		}
 	}
 
@@ -67,22 +77,13 @@ func CheckConvoysForIssue(townRoot, issueID, observer string, logger func(format
 }
 
 // getTrackingConvoys returns convoy IDs that track the given issue.
-// Uses bd dep list to query the dependency graph.
-func getTrackingConvoys(townRoot, issueID string) []string {
-	// Query for convoys that track this issue (direction=up finds dependents)
-	cmd := exec.Command("bd", "dep", "list", issueID, "--direction=up", "-t", "tracks", "--json")
-	cmd.Dir = townRoot
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-
-	if err := cmd.Run(); err != nil {
-		return nil
-	}
-
-	var results []struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+// Uses the dependency graph (direction=up finds dependents).
+func getTrackingConvoys(store beads.Store, issueID string) []string {
+	results, err := store.ListDependencies(issueID, beads.DepListOptions{
+		Direction: beads.DepDirectionUp,
+		Type:      "tracks",
+	})
+	if err != nil {
 		return nil
 	}
 
@@ -94,24 +95,12 @@ func getTrackingConvoys(townRoot, issueID string) []string {
 }
 
 // isConvoyClosed checks if a convoy is already closed.
-func isConvoyClosed(townRoot, convoyID string) bool {
-	cmd := exec.Command("bd", "show", convoyID, "--json")
-	cmd.Dir = townRoot
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-
-	if err := cmd.Run(); err != nil {
+func isConvoyClosed(store beads.Store, convoyID string) bool {
+	issue, err := store.Show(convoyID)
+	if err != nil {
 		return false
 	}
-
-	var results []struct {
-		Status string `json:"status"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil || len(results) == 0 {
-		return false
-	}
-
-	return results[0].Status == "closed"
+	return issue.Status == "closed"
 }
 
 // runConvoyCheck runs `gt convoy check <convoy-id>` to check a specific convoy.
@@ -144,14 +133,20 @@ type trackedIssue struct {
 //
 // Only one issue is dispatched per call. When that issue completes, the
 // observer fires again and feeds the next one.
-func feedNextReadyIssue(townRoot, convoyID, observer string, logger func(format string, args ...interface{})) {
-	tracked := getConvoyTrackedIssues(townRoot, convoyID)
+func feedNextReadyIssue(store beads.Store, townRoot, convoyID, observer string, logger func(format string, args ...interface{})) {
+	// Over-budget convoys stay parked until a human raises the cap
+	if issue, err := store.Show(convoyID); err == nil && IsBudgetExceeded(issue) {
+		logger("%s: convoy %s is over budget, not feeding", observer, convoyID)
+		return
+	}
+
+	tracked := getConvoyTrackedIssues(store, convoyID)
 	if len(tracked) == 0 {
 		return
 	}
 
 	// Find the first ready issue (open, no assignee).
-	// Issues are returned by bd dep list in dependency order, so we pick
+	// Issues are returned by dep list in dependency order, so we pick
 	// the first match which is typically the highest priority.
 	for _, issue := range tracked {
 		if issue.Status != "open" || issue.Assignee != "" {
@@ -176,57 +171,43 @@ func feedNextReadyIssue(townRoot, convoyID, observer string, logger func(format
 }
 
 // getConvoyTrackedIssues returns issues tracked by a convoy with fresh status.
-// Uses bd dep list for the tracking relations, then bd show for current status.
-func getConvoyTrackedIssues(townRoot, convoyID string) []trackedIssue {
+// Uses the tracking dependencies for the relations, then a batch show for
+// current status.
+func getConvoyTrackedIssues(store beads.Store, convoyID string) []trackedIssue {
 	// Get tracked issue IDs from dependency graph
-	depCmd := exec.Command("bd", "dep", "list", convoyID, "--direction=down", "--type=tracks", "--json")
-	depCmd.Dir = townRoot
-	var stdout bytes.Buffer
-	depCmd.Stdout = &stdout
-
-	if err := depCmd.Run(); err != nil {
-		return nil
-	}
-
-	var deps []struct {
-		ID       string `json:"id"`
-		Status   string `json:"status"`
-		Assignee string `json:"assignee"`
-		Priority int    `json:"priority"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &deps); err != nil {
-		return nil
-	}
-
-	if len(deps) == 0 {
+	deps, err := store.ListDependencies(convoyID, beads.DepListOptions{
+		Direction: beads.DepDirectionDown,
+		Type:      "tracks",
+	})
+	if err != nil || len(deps) == 0 {
 		return nil
 	}
 
 	// Unwrap external:prefix:id format
-	for i := range deps {
-		deps[i].ID = extractIssueID(deps[i].ID)
-	}
-
-	// Refresh status via bd show for cross-rig accuracy.
-	// bd dep list returns stale status from the HQ dependency record.
 	ids := make([]string, len(deps))
 	for i, d := range deps {
-		ids[i] = d.ID
+		ids[i] = extractIssueID(d.ID)
 	}
 
-	freshStatus := batchShowIssues(townRoot, ids)
+	// Refresh status via show for cross-rig accuracy.
+	// dep list returns stale status from the HQ dependency record.
+	// A failed refresh falls back to the dependency record.
+	fresh, err := store.ShowMultiple(ids)
+	if err != nil {
+		fresh = nil
+	}
 
 	result := make([]trackedIssue, len(deps))
 	for i, d := range deps {
 		t := trackedIssue{
-			ID:       d.ID,
+			ID:       ids[i],
 			Status:   d.Status,
 			Assignee: d.Assignee,
 			Priority: d.Priority,
 		}
-		if fresh, ok := freshStatus[d.ID]; ok {
-			t.Status = fresh.Status
-			t.Assignee = fresh.Assignee
+		if issue, ok := fresh[ids[i]]; ok {
+			t.Status = issue.Status
+			t.Assignee = issue.Assignee
 		}
 		result[i] = t
 	}
@@ -234,45 +215,6 @@ func getConvoyTrackedIssues(townRoot, convoyID string) []trackedIssue {
 	return result
 }
 
-// batchShowIssues fetches fresh status for multiple issues via bd show.
-func batchShowIssues(townRoot string, issueIDs []string) map[string]trackedIssue {
-	result := make(map[string]trackedIssue)
-	if len(issueIDs) == 0 {
-		return result
-	}
-
-	args := append([]string{"show"}, issueIDs...)
-	args = append(args, "--json")
-
-	cmd := exec.Command("bd", args...)
-	cmd.Dir = townRoot
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-
-	if err := cmd.Run(); err != nil {
-		return result
-	}
-
-	var issues []struct {
-		ID       string `json:"id"`
-		Status   string `json:"status"`
-		Assignee string `json:"assignee"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
-		return result
-	}
-
-	for _, issue := range issues {
-		result[issue.ID] = trackedIssue{
-			ID:       issue.ID,
-			Status:   issue.Status,
-			Assignee: issue.Assignee,
-		}
-	}
-
-	return result
-}
-
 // extractIssueID strips the external:prefix:id wrapper from bead IDs.
 func extractIssueID(id string) string {
 	if strings.HasPrefix(id, "external:") {
@@ -2,6 +2,8 @@ package convoy
 
 import (
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 func TestExtractIssueID(t *testing.T) {
@@ -70,3 +72,75 @@ func TestFeedNextReadyIssue_FindsReadyIssue(t *testing.T) {
 		t.Errorf("expected first ready issue to be gt-ready, got %s", foundReady)
 	}
 }
+
+// newTrackingStore returns a store with an open convoy tracking a local
+// issue and a cross-rig issue, plus a closed convoy tracking the local one.
+func newTrackingStore(t *testing.T) (*beads.MemStore, string, string) {
+	t.Helper()
+	store := beads.NewMemStore("hq")
+	create := func(title string) string {
+		issue, err := store.Create(beads.CreateOptions{Title: title, Priority: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return issue.ID
+	}
+	convoyID := create("convoy")
+	doneConvoyID := create("finished convoy")
+	issueID := create("work")
+
+	for _, dep := range [][2]string{
+		{convoyID, issueID},
+		{convoyID, "external:gt:gt-abc"},
+		{doneConvoyID, issueID},
+	} {
+		if err := store.AddTypedDependency(dep[0], dep[1], "tracks"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Close(doneConvoyID); err != nil {
+		t.Fatal(err)
+	}
+	return store, convoyID, issueID
+}
+
+func TestGetTrackingConvoys(t *testing.T) {
+	store, convoyID, issueID := newTrackingStore(t)
+
+	got := getTrackingConvoys(store, issueID)
+	if len(got) != 2 || got[0] != convoyID {
+		t.Errorf("getTrackingConvoys(%s) = %v, want %s first of 2", issueID, got, convoyID)
+	}
+	if got := getTrackingConvoys(store, "hq-404"); len(got) != 0 {
+		t.Errorf("getTrackingConvoys(missing) = %v, want none", got)
+	}
+
+	if isConvoyClosed(store, convoyID) {
+		t.Errorf("convoy %s reported closed", convoyID)
+	}
+	if !isConvoyClosed(store, got[1]) {
+		t.Errorf("convoy %s reported open", got[1])
+	}
+}
+
+func TestGetConvoyTrackedIssues_RefreshesStatus(t *testing.T) {
+	store, convoyID, issueID := newTrackingStore(t)
+
+	assignee := "gastown/polecats/nux"
+	hooked := "hooked"
+	if err := store.Update(issueID, beads.UpdateOptions{Assignee: &assignee, Status: &hooked}); err != nil {
+		t.Fatal(err)
+	}
+
+	tracked := getConvoyTrackedIssues(store, convoyID)
+	if len(tracked) != 2 {
+		t.Fatalf("tracked = %+v, want 2 issues", tracked)
+	}
+	if tracked[0].ID != issueID || tracked[0].Status != "hooked" || tracked[0].Assignee != assignee {
+		t.Errorf("tracked[0] = %+v, want hooked by %s", tracked[0], assignee)
+	}
+	// External references are unwrapped even when the issue lives elsewhere
+	if tracked[1].ID != "gt-abc" {
+		t.Errorf("tracked[1].ID = %q, want gt-abc", tracked[1].ID)
+	}
+}
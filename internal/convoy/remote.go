@@ -0,0 +1,46 @@
+package convoy
+
+import (
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/federation"
+)
+
+// Issues in other towns cannot be tracked with a bd dependency, so a convoy
+// records them in its description, one HOP URI per line:
+//
+//	Tracks-Remote: hop://acme/main-town/greenplace/gp-xyz
+const remoteLegKey = "Tracks-Remote"
+
+// ParseRemoteLegs returns the remote issues a convoy description tracks.
+func ParseRemoteLegs(description string) []federation.URI {
+	var refs []string
+	for _, line := range strings.Split(description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && strings.TrimSpace(key) == remoteLegKey {
+			refs = append(refs, strings.TrimSpace(value))
+		}
+	}
+	return federation.URIs(refs)
+}
+
+// AddRemoteLegs returns the description with uris appended as remote legs.
+// URIs the convoy already tracks are skipped.
+func AddRemoteLegs(description string, uris []federation.URI) string {
+	have := make(map[string]bool)
+	for _, u := range ParseRemoteLegs(description) {
+		have[u.String()] = true
+	}
+	out := strings.TrimRight(description, "\n ")
+	for _, u := range uris {
+		if have[u.String()] {
+			continue
+		}
+		have[u.String()] = true
+		if out != "" {
+			out += "\n"
+		}
+		out += remoteLegKey + ": " + u.String()
+	}
+	return out
+}
@@ -0,0 +1,22 @@
+package convoy
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/federation"
+)
+
+func TestRemoteLegs(t *testing.T) {
+	a, _ := federation.ParseURI("hop://acme/main/greenplace/gp-1")
+	b, _ := federation.ParseURI("hop://acme/main/hq-2")
+
+	desc := AddRemoteLegs("Convoy tracking 1 issues\nOwner: mayor/\n", []federation.URI{a})
+	desc = AddRemoteLegs(desc, []federation.URI{a, b})
+	want := "Convoy tracking 1 issues\nOwner: mayor/\nTracks-Remote: hop://acme/main/greenplace/gp-1\nTracks-Remote: hop://acme/main/hq-2"
+	if desc != want {
+		t.Errorf("AddRemoteLegs =\n%s\nwant\n%s", desc, want)
+	}
+	if got := ParseRemoteLegs(desc + "\nTracks-Remote: not-a-uri"); len(got) != 2 {
+		t.Errorf("ParseRemoteLegs = %v", got)
+	}
+}
@@ -0,0 +1,283 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+// costDigestRefresh bounds how often the daemon re-reads the daily cost
+// digest beads. Digests are created once a day, so hourly is plenty.
+const costDigestRefresh = time.Hour
+
+// budgetTarget is a rig or convoy with a budget.
+type budgetTarget struct {
+	scope  string
+	name   string // rig name or convoy ID
+	convoy bool
+	budget *config.BudgetConfig
+	items  map[string]bool // convoy: tracked issue IDs
+}
+
+// checkBudgets compares rig and convoy spend against their budgets. Crossing
+// the warn threshold mails the mayor; reaching a cap parks the rig or marks
+// the convoy budget:exceeded so no more polecats are spawned for it. Both are
+// lifted once spend is back under the cap (cap raised, or a new day for
+// daily caps).
+func (d *Daemon) checkBudgets() {
+	townRoot := d.config.TownRoot
+	targets := d.budgetTargets()
+
+	state, err := budget.LoadState(townRoot)
+	if err != nil {
+		d.logger.Printf("Budgets: %v", err)
+		return
+	}
+	if len(targets) == 0 && len(state.Scopes) == 0 {
+		return
+	}
+
+	now := time.Now()
+	active := make(map[string]bool, len(targets))
+	if len(targets) > 0 {
+		ledger, err := d.costLedger(targets)
+		if err != nil {
+			d.logger.Printf("Budgets: %v", err)
+			return
+		}
+		for _, t := range targets {
+			active[t.scope] = true
+			var spend budget.Spend
+			if t.convoy {
+				spend = ledger.WorkItems(t.items, now)
+			} else {
+				spend = ledger.Rig(t.name, now)
+			}
+			level, reason := budget.Evaluate(t.budget, spend)
+			if tr, changed := state.Update(t.scope, level, reason, spend, now); changed {
+				d.applyBudgetTransition(t, tr)
+			}
+		}
+	}
+
+	// Budgets that were removed, or convoys that closed, while exceeded
+	for _, scope := range state.Prune(active) {
+		kind, name, _ := strings.Cut(scope, ":")
+		t := budgetTarget{scope: scope, name: name, convoy: kind == "convoy"}
+		d.applyBudgetTransition(t, budget.Transition{
+			Scope:  scope,
+			From:   budget.LevelExceeded,
+			To:     budget.LevelOK,
+			Reason: "budget removed",
+		})
+	}
+
+	if err := budget.SaveState(townRoot, state); err != nil {
+		d.logger.Printf("Budgets: saving state: %v", err)
+	}
+}
+
+// budgetTargets returns the rigs and open convoys that have a budget.
+func (d *Daemon) budgetTargets() []budgetTarget {
+	townRoot := d.config.TownRoot
+	var targets []budgetTarget
+
+	for _, rigName := range d.getKnownRigs() {
+		settings, err := config.LoadRigSettings(filepath.Join(townRoot, rigName, "settings", "config.json"))
+		if err != nil || settings.Budget.IsZero() {
+			continue
+		}
+		targets = append(targets, budgetTarget{
+			scope:  budget.RigScope(rigName),
+			name:   rigName,
+			budget: settings.Budget,
+		})
+	}
+
+	cmd := exec.Command(d.bdPath, "list", "--type=convoy", "--status=open", "--json")
+	cmd.Dir = townRoot
+	cmd.Env = os.Environ()
+	out, err := cmd.Output()
+	if err != nil {
+		d.logger.Printf("Budgets: listing convoys: %v", err)
+		return targets
+	}
+	var convoys []*beads.Issue
+	if err := json.Unmarshal(out, &convoys); err != nil {
+		d.logger.Printf("Budgets: parsing convoy list: %v", err)
+		return targets
+	}
+
+	store := beads.New(townRoot)
+	for _, c := range convoys {
+		b := convoy.ParseBudget(c.Description)
+		if b.IsZero() {
+			continue
+		}
+		ids, err := convoy.TrackedIssueIDs(store, c.ID)
+		if err != nil {
+			d.logger.Printf("Budgets: convoy %s: %v", c.ID, err)
+			continue
+		}
+		items := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			items[id] = true
+		}
+		targets = append(targets, budgetTarget{
+			scope:  budget.ConvoyScope(c.ID),
+			name:   c.ID,
+			convoy: true,
+			budget: b,
+			items:  items,
+		})
+	}
+	return targets
+}
+
+// costLedger builds the spend ledger from the cost log. Digest beads are only
+// needed for total caps (the log is pruned after each digest) and are cached.
+func (d *Daemon) costLedger(targets []budgetTarget) (*budget.Ledger, error) {
+	entries, err := budget.ReadLog(budget.LogPath())
+	if err != nil {
+		return nil, err
+	}
+	ledger := &budget.Ledger{Entries: entries}
+
+	needTotals := false
+	for _, t := range targets {
+		if t.budget.TotalUSD > 0 {
+			needTotals = true
+			break
+		}
+	}
+	if !needTotals {
+		return ledger, nil
+	}
+
+	if time.Since(d.costDigestsAt) > costDigestRefresh {
+		digests, err := budget.LoadDigests(d.config.TownRoot, d.bdPath)
+		if err != nil {
+			// Keep the last good digests; totals are briefly stale at worst.
+			d.logger.Printf("Budgets: loading cost digests: %v", err)
+		} else {
+			d.costDigests = digests
+			d.costDigestsAt = time.Now()
+		}
+	}
+	ledger.Digests = d.costDigests
+	return ledger, nil
+}
+
+// applyBudgetTransition enforces or lifts a budget and tells the mayor.
+func (d *Daemon) applyBudgetTransition(t budgetTarget, tr budget.Transition) {
+	what := "Rig " + t.name
+	if t.convoy {
+		what = "Convoy " + t.name
+	}
+
+	switch {
+	case tr.Raised() && tr.To == budget.LevelExceeded:
+		d.logger.Printf("Budgets: %s over budget: %s", what, tr.Reason)
+		var action, raise string
+		if t.convoy {
+			action = "No more polecats will be spawned for its issues."
+			raise = fmt.Sprintf("gt convoy budget %s --daily N --total N", t.name)
+			d.setConvoyBudgetLabel(t.name, true)
+		} else {
+			action = "The rig has been parked."
+			raise = fmt.Sprintf("gt rig settings set %s budget.daily_usd N (or budget.total_usd)", t.name)
+			d.parkRigForBudget(t.name, tr.Reason)
+		}
+		d.mailMayor(fmt.Sprintf("BUDGET_EXCEEDED: %s", t.name), fmt.Sprintf(`%s reached its budget cap: %s.
+
+%s
+To continue, raise the cap:
+  %s
+
+Enforcement lifts automatically once spend is back under the cap.`, what, tr.Reason, action, raise))
+
+	case tr.Raised():
+		d.logger.Printf("Budgets: %s near budget: %s", what, tr.Reason)
+		d.mailMayor(fmt.Sprintf("BUDGET_WARNING: %s", t.name),
+			fmt.Sprintf("%s is approaching its budget cap: %s.", what, tr.Reason))
+
+	case tr.Released():
+		d.logger.Printf("Budgets: %s back under budget (%s)", what, tr.Reason)
+		if t.convoy {
+			d.setConvoyBudgetLabel(t.name, false)
+		} else {
+			d.unparkRigForBudget(t.name)
+		}
+		d.mailMayor(fmt.Sprintf("BUDGET_RELEASED: %s", t.name),
+			fmt.Sprintf("%s is back under its budget cap and may spawn work again.", what))
+	}
+}
+
+// parkRigForBudget parks a rig and records that the budget did it.
+func (d *Daemon) parkRigForBudget(rigName, reason string) {
+	cfg := wisp.NewConfig(d.config.TownRoot, rigName)
+	if cfg.GetString("status") != "parked" {
+		cmd := exec.Command(d.gtPath, "rig", "park", rigName) //nolint:gosec // G204: rig name from town config
+		cmd.Dir = d.config.TownRoot
+		cmd.Env = os.Environ()
+		if out, err := cmd.CombinedOutput(); err != nil {
+			d.logger.Printf("Budgets: parking %s: %v: %s", rigName, err, strings.TrimSpace(string(out)))
+			return
+		}
+	}
+	if err := cfg.Set(budget.WispParkedKey, reason); err != nil {
+		d.logger.Printf("Budgets: marking %s budget-parked: %v", rigName, err)
+	}
+}
+
+// unparkRigForBudget unparks a rig the budget parked. A rig parked by hand,
+// or already unparked by hand, is left alone.
+func (d *Daemon) unparkRigForBudget(rigName string) {
+	cfg := wisp.NewConfig(d.config.TownRoot, rigName)
+	if cfg.GetString(budget.WispParkedKey) == "" {
+		return
+	}
+	if err := cfg.Unset(budget.WispParkedKey); err != nil {
+		d.logger.Printf("Budgets: clearing %s budget park: %v", rigName, err)
+	}
+	if cfg.GetString("status") != "parked" {
+		return
+	}
+	cmd := exec.Command(d.gtPath, "rig", "unpark", rigName) //nolint:gosec // G204: rig name from town config
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		d.logger.Printf("Budgets: unparking %s: %v: %s", rigName, err, strings.TrimSpace(string(out)))
+	}
+}
+
+// setConvoyBudgetLabel adds or removes the budget:exceeded convoy label.
+func (d *Daemon) setConvoyBudgetLabel(convoyID string, exceeded bool) {
+	opts := beads.UpdateOptions{RemoveLabels: []string{convoy.LabelBudgetExceeded}}
+	if exceeded {
+		opts = beads.UpdateOptions{AddLabels: []string{convoy.LabelBudgetExceeded}}
+	}
+	if err := beads.New(d.config.TownRoot).Update(convoyID, opts); err != nil {
+		d.logger.Printf("Budgets: updating convoy %s label: %v", convoyID, err)
+	}
+}
+
+// mailMayor sends a budget notification to the mayor.
+func (d *Daemon) mailMayor(subject, body string) {
+	cmd := exec.Command(d.gtPath, "mail", "send", "mayor/", "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ()
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Budgets: failed to mail mayor: %v", err)
+	}
+}
@@ -11,6 +11,9 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/convoy"
 )
 
 // ConvoyWatcher monitors bd activity for issue closes and triggers convoy completion checks.
@@ -235,4 +238,12 @@ func (w *ConvoyWatcher) checkConvoyCompletion(convoyID string) {
 	if output := checkStdout.String(); output != "" && !strings.Contains(output, "No convoys ready") {
 		w.logger("convoy watcher: %s", strings.TrimSpace(output))
 	}
+
+	// If the check closed the convoy, it has landed: record it in the ledger
+	added, err := convoy.ExportLanded(beads.New(w.townRoot), w.townRoot, convoyID)
+	if err != nil {
+		w.logger("convoy watcher: ledger export of %s failed: %v", convoyID, err)
+	} else if added {
+		w.logger("convoy watcher: recorded landing of %s in the ledger", convoyID)
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -12,12 +13,14 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/boot"
+	"github.com/steveyegge/gastown/internal/budget"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
@@ -49,6 +52,8 @@ type Daemon struct {
 	convoyWatcher *ConvoyWatcher
 	doltServer    *DoltServerManager
 	krcPruner     *KRCPruner
+	warrantRunner *WarrantRunner
+	webhookServer *http.Server
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
@@ -65,6 +70,29 @@ type Daemon struct {
 	// Only accessed from heartbeat loop goroutine - no sync needed.
 	syncFailures map[string]int
 
+	// costDigests caches the daily cost digest beads used for total budget
+	// caps, refreshed hourly. Only accessed from heartbeat loop goroutine.
+	costDigests   []budget.Digest
+	costDigestsAt time.Time
+
+	// rateLimitRotations records when a session was last moved to another
+	// account, so it isn't rotated again while the old limit message is
+	// still on screen. Only accessed from heartbeat loop goroutine.
+	rateLimitRotations map[string]time.Time
+
+	// lastDoctorRun is when the scheduled quick doctor checks last ran.
+	// Only accessed from heartbeat loop goroutine.
+	lastDoctorRun time.Time
+
+	// doctorRunning is set while a scheduled doctor run is in flight;
+	// doctorWG lets shutdown wait for it.
+	doctorRunning atomic.Bool
+	doctorWG      sync.WaitGroup
+
+	// lastMetricsWrite is when the metrics textfile was last written.
+	// Only accessed from heartbeat loop goroutine.
+	lastMetricsWrite time.Time
+
 	// PATCH-006: Resolved binary paths to avoid PATH issues in subprocesses.
 	// The daemon may be started with a limited PATH, causing exec.Command("gt", ...)
 	// to fail with "executable file not found in $PATH".
@@ -233,6 +261,19 @@ func (d *Daemon) Run() error {
 		}
 	}
 
+	// Start the shutdown dance for death warrants (see gt warrant)
+	if IsPatrolEnabled(d.patrolConfig, "warrants") {
+		d.warrantRunner = NewWarrantRunner(d.config.TownRoot, d.logger.Printf, d.isShutdownInProgress)
+		if err := d.warrantRunner.Start(); err != nil {
+			d.logger.Printf("Warning: failed to start warrant runner: %v", err)
+		} else {
+			d.logger.Println("Warrant runner started")
+		}
+	}
+
+	// Start webhook ingress if settings/webhooks.json enables it
+	d.startWebhookServer()
+
 	// Start dedicated Dolt health check ticker if Dolt server is configured.
 	// This runs at a much higher frequency (default 30s) than the general
 	// heartbeat (3 min) so Dolt crashes are detected quickly.
@@ -389,6 +430,44 @@ func (d *Daemon) heartbeat(state *State) {
 	// If they have local .beads with databases, bd uses the wrong database.
 	d.cleanupTownServiceBeads()
 
+	// 14. Dispatch plugins whose gates are open to idle dogs.
+	// Opt-in via patrols.plugins in mayor/daemon.json (the Deacon patrol
+	// runs plugins itself by default).
+	if IsPatrolEnabled(d.patrolConfig, "plugins") {
+		d.dispatchDuePlugins()
+	}
+
+	// 15. Enforce per-rig and per-convoy cost budgets.
+	// Warns the mayor near a cap; parks the rig or blocks the convoy at it.
+	if IsPatrolEnabled(d.patrolConfig, "budgets") {
+		d.checkBudgets()
+	}
+
+	// 16. Move polecats stuck on an account usage/rate limit to the next
+	// available account (see gt account status for cooldowns).
+	if IsPatrolEnabled(d.patrolConfig, "account_rotation") {
+		d.checkRateLimits()
+	}
+
+	// 17. Expire mail requests whose reply deadline passed, logging a
+	// mail_reply_timeout event for each so patrols can retry or escalate.
+	if IsPatrolEnabled(d.patrolConfig, "mail_replies") {
+		d.expireOverdueReplies()
+	}
+
+	// 18. Run the quick doctor checks on a schedule (patrols.doctor.interval,
+	// default hourly), recording them in the doctor history and escalating
+	// checks that regressed.
+	if IsPatrolEnabled(d.patrolConfig, "doctor") {
+		d.runScheduledDoctor()
+	}
+
+	// 19. Write town health metrics for node_exporter's textfile collector.
+	// Opt-in via patrols.metrics in mayor/daemon.json.
+	if IsPatrolEnabled(d.patrolConfig, "metrics") {
+		d.writeMetricsTextfile()
+	}
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -1125,6 +1204,18 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("KRC pruner stopped")
 	}
 
+	// Stop warrant runner
+	if d.warrantRunner != nil {
+		d.warrantRunner.Stop()
+		d.logger.Println("Warrant runner stopped")
+	}
+
+	// Wait for a scheduled doctor run (cancelled with the daemon context)
+	d.doctorWG.Wait()
+
+	// Stop webhook ingress
+	d.stopWebhookServer()
+
 	// Stop Dolt server if we're managing it
 	if d.doltServer != nil && d.doltServer.IsEnabled() && !d.doltServer.IsExternal() {
 		if err := d.doltServer.Stop(); err != nil {
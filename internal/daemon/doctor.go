@@ -0,0 +1,80 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+)
+
+const (
+	// defaultDoctorInterval is how often the scheduled doctor checks run
+	// unless patrols.doctor.interval says otherwise.
+	defaultDoctorInterval = time.Hour
+
+	// doctorTimeout bounds a scheduled doctor run so a wedged check can't
+	// keep later runs from starting.
+	doctorTimeout = 2 * time.Minute
+)
+
+// runScheduledDoctor starts `gt doctor --scheduled` in the background when
+// the doctor interval has elapsed and no earlier run is still going, so a
+// slow check never holds up the rest of the heartbeat. The run records its
+// results in the doctor history and escalates checks that regressed since
+// the previous run; the daemon only schedules it (the doctor package imports
+// daemon, so it runs as gt).
+func (d *Daemon) runScheduledDoctor() {
+	interval := doctorInterval(d.patrolConfig)
+	if !d.lastDoctorRun.IsZero() && time.Since(d.lastDoctorRun) < interval {
+		return
+	}
+	if !d.doctorRunning.CompareAndSwap(false, true) {
+		return // Previous run still in flight
+	}
+	d.lastDoctorRun = time.Now()
+
+	parent := d.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	d.doctorWG.Add(1)
+	go func() {
+		defer d.doctorWG.Done()
+		defer d.doctorRunning.Store(false)
+		d.doctorRun(parent)
+	}()
+}
+
+// doctorRun runs the scheduled doctor checks once, bounded by doctorTimeout
+// and cancelled when the daemon stops.
+func (d *Daemon) doctorRun(parent context.Context) {
+	ctx, cancel := context.WithTimeout(parent, doctorTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, "doctor", "--scheduled") //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ()
+	// A non-zero exit means checks failed, which the history records
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			if parent.Err() == nil {
+				d.logger.Printf("Doctor: scheduled checks timed out after %v", doctorTimeout)
+			}
+			return
+		}
+		if _, ok := err.(*exec.ExitError); !ok {
+			d.logger.Printf("Doctor: failed to run scheduled checks: %v", err)
+			return
+		}
+		d.logger.Printf("Doctor: scheduled checks found problems (see gt doctor history)")
+	}
+}
+
+// doctorInterval returns the configured scheduled doctor interval.
+func doctorInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.Doctor != nil && config.Patrols.Doctor.Interval != "" {
+		if d, err := time.ParseDuration(config.Patrols.Doctor.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultDoctorInterval
+}
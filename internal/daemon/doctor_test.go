@@ -0,0 +1,50 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunScheduledDoctorDoesNotBlockHeartbeat(t *testing.T) {
+	// A gt stand-in whose doctor run outlasts the test unless cancelled
+	gt := filepath.Join(t.TempDir(), "gt")
+	if err := os.WriteFile(gt, []byte("#!/bin/sh\nexec sleep 30\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Daemon{
+		config: &Config{TownRoot: t.TempDir()},
+		logger: log.New(io.Discard, "", 0),
+		ctx:    ctx,
+		cancel: cancel,
+		gtPath: gt,
+	}
+
+	start := time.Now()
+	d.runScheduledDoctor()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("runScheduledDoctor blocked for %v", elapsed)
+	}
+	if !d.doctorRunning.Load() {
+		t.Fatal("expected a doctor run in flight")
+	}
+
+	// Due again, but the first run is still going: no second run starts
+	first := d.lastDoctorRun
+	d.lastDoctorRun = time.Time{}
+	d.runScheduledDoctor()
+	if !d.lastDoctorRun.IsZero() {
+		t.Errorf("started a second run while one was in flight (first at %v)", first)
+	}
+
+	cancel()
+	d.doctorWG.Wait()
+	if d.doctorRunning.Load() {
+		t.Error("in-flight flag not cleared after the run ended")
+	}
+}
@@ -87,4 +87,8 @@ func (p *KRCPruner) prune() {
 			result.BytesBefore-result.BytesAfter,
 			result.Duration.Round(time.Millisecond))
 	}
+	if result.TranscriptsPruned > 0 {
+		p.logger("KRC pruned %d session transcripts (saved %d bytes)",
+			result.TranscriptsPruned, result.TranscriptBytesFreed)
+	}
 }
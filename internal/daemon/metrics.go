@@ -0,0 +1,27 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/metrics"
+)
+
+// writeMetricsTextfile collects town health metrics and writes them to the
+// configured textfile, at most once per patrols.metrics.interval.
+func (d *Daemon) writeMetricsTextfile() {
+	cfg := d.patrolConfig.Patrols.Metrics // Non-nil: the patrol is opt-in
+	if cfg.Interval != "" && !d.lastMetricsWrite.IsZero() {
+		if interval, err := time.ParseDuration(cfg.Interval); err == nil && time.Since(d.lastMetricsWrite) < interval {
+			return
+		}
+	}
+	d.lastMetricsWrite = time.Now()
+
+	path := cfg.Textfile
+	if path == "" {
+		path = metrics.DefaultTextfile(d.config.TownRoot)
+	}
+	if err := metrics.Collect(d.config.TownRoot).WriteTextfile(path); err != nil {
+		d.logger.Printf("Metrics: failed to write %s: %v", path, err)
+	}
+}
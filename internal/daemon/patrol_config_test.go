@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadPatrolConfig(t *testing.T) {
@@ -51,3 +52,96 @@ func TestIsPatrolEnabled_NilConfig(t *testing.T) {
 		t.Error("expected default to be enabled")
 	}
 }
+
+func TestIsPatrolEnabled_PluginsOptIn(t *testing.T) {
+	if IsPatrolEnabled(nil, "plugins") {
+		t.Error("expected plugins patrol to be disabled with no config")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{}}
+	if IsPatrolEnabled(config, "plugins") {
+		t.Error("expected plugins patrol to be disabled when not configured")
+	}
+	config.Patrols.Plugins = &PatrolConfig{Enabled: true, Rigs: []string{"gastown"}}
+	if !IsPatrolEnabled(config, "plugins") {
+		t.Error("expected plugins patrol to be enabled")
+	}
+	if rigs := GetPatrolRigs(config, "plugins"); len(rigs) != 1 || rigs[0] != "gastown" {
+		t.Errorf("GetPatrolRigs(plugins) = %v, want [gastown]", rigs)
+	}
+}
+
+func TestDoctorPatrolConfig(t *testing.T) {
+	if !IsPatrolEnabled(nil, "doctor") {
+		t.Error("expected doctor patrol to be enabled by default")
+	}
+	if got := doctorInterval(nil); got != defaultDoctorInterval {
+		t.Errorf("doctorInterval(nil) = %v, want %v", got, defaultDoctorInterval)
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{
+		Doctor: &PatrolConfig{Enabled: true, Interval: "15m"},
+	}}
+	if got := doctorInterval(config); got != 15*time.Minute {
+		t.Errorf("doctorInterval = %v, want 15m", got)
+	}
+	config.Patrols.Doctor = &PatrolConfig{Enabled: false, Interval: "bogus"}
+	if IsPatrolEnabled(config, "doctor") {
+		t.Error("expected doctor patrol to be disabled")
+	}
+	if got := doctorInterval(config); got != defaultDoctorInterval {
+		t.Errorf("doctorInterval(invalid) = %v, want default", got)
+	}
+}
+
+func TestWarrantsPatrolConfig(t *testing.T) {
+	if !IsPatrolEnabled(nil, "warrants") {
+		t.Error("expected warrants patrol to be enabled by default")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{Warrants: &PatrolConfig{Enabled: false}}}
+	if IsPatrolEnabled(config, "warrants") {
+		t.Error("expected warrants patrol to be disabled")
+	}
+}
+
+func TestBudgetsPatrolConfig(t *testing.T) {
+	if !IsPatrolEnabled(nil, "budgets") {
+		t.Error("expected budgets patrol to be enabled by default")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{Budgets: &PatrolConfig{Enabled: false}}}
+	if IsPatrolEnabled(config, "budgets") {
+		t.Error("expected budgets patrol to be disabled")
+	}
+}
+
+func TestAccountRotationPatrolConfig(t *testing.T) {
+	if !IsPatrolEnabled(nil, "account_rotation") {
+		t.Error("expected account_rotation patrol to be enabled by default")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{AccountRotation: &PatrolConfig{Enabled: false}}}
+	if IsPatrolEnabled(config, "account_rotation") {
+		t.Error("expected account_rotation patrol to be disabled")
+	}
+}
+
+func TestMailRepliesPatrolConfig(t *testing.T) {
+	if !IsPatrolEnabled(nil, "mail_replies") {
+		t.Error("expected mail_replies patrol to be enabled by default")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{MailReplies: &PatrolConfig{Enabled: false}}}
+	if IsPatrolEnabled(config, "mail_replies") {
+		t.Error("expected mail_replies patrol to be disabled")
+	}
+}
+
+func TestIsPatrolEnabled_MetricsOptIn(t *testing.T) {
+	if IsPatrolEnabled(nil, "metrics") {
+		t.Error("expected metrics patrol to be disabled with no config")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{}}
+	if IsPatrolEnabled(config, "metrics") {
+		t.Error("expected metrics patrol to be disabled when not configured")
+	}
+	config.Patrols.Metrics = &MetricsConfig{Enabled: true}
+	if !IsPatrolEnabled(config, "metrics") {
+		t.Error("expected metrics patrol to be enabled")
+	}
+}
@@ -0,0 +1,84 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/dog"
+	"github.com/steveyegge/gastown/internal/plugin"
+)
+
+// pluginGateTimeout bounds a whole round of gate evaluation, including
+// condition checks, so a slow check can't stall the heartbeat.
+const pluginGateTimeout = 2 * time.Minute
+
+// dispatchDuePlugins evaluates plugin gates and dispatches due plugins to
+// idle dogs via 'gt dog dispatch'. Plugins a dog is already working on are
+// skipped; the gate stays open until the dog records its run.
+func (d *Daemon) dispatchDuePlugins() {
+	townRoot := d.config.TownRoot
+	plugins, err := plugin.NewScanner(townRoot, d.getPatrolRigs("plugins")).DiscoverAll()
+	if err != nil {
+		d.logger.Printf("Plugin gates: discovery failed: %v", err)
+		return
+	}
+	if len(plugins) == 0 {
+		return
+	}
+
+	inFlight := pluginsInFlight(townRoot)
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginGateTimeout)
+	defer cancel()
+
+	for _, decision := range plugin.NewGateEvaluator(townRoot).EvaluateAll(ctx, plugins) {
+		if decision.Error != "" {
+			d.logger.Printf("Plugin gates: %s: %s", decision.Plugin, decision.Error)
+			continue
+		}
+		if !decision.Due {
+			continue
+		}
+		if inFlight[decision.Plugin] {
+			d.logger.Printf("Plugin gates: %s is due but already running on a dog", decision.Plugin)
+			continue
+		}
+
+		args := []string{"dog", "dispatch", "--plugin", decision.Plugin}
+		if decision.RigName != "" {
+			args = append(args, "--rig", decision.RigName)
+		}
+		cmd := exec.Command(d.gtPath, args...) //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = townRoot
+		cmd.Env = os.Environ()
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			msg := strings.TrimSpace(string(out))
+			if strings.Contains(msg, "no idle dogs") {
+				d.logger.Printf("Plugin gates: %s is due but no dogs are idle, deferring", decision.Plugin)
+				return
+			}
+			d.logger.Printf("Plugin gates: dispatching %s failed: %v: %s", decision.Plugin, err, msg)
+			continue
+		}
+		d.logger.Printf("Plugin gates: dispatched %s (%s)", decision.Plugin, decision.Reason)
+	}
+}
+
+// pluginsInFlight returns the plugins currently assigned to working dogs.
+func pluginsInFlight(townRoot string) map[string]bool {
+	inFlight := make(map[string]bool)
+	dogs, err := dog.NewManager(townRoot, nil).List()
+	if err != nil {
+		return inFlight
+	}
+	for _, dg := range dogs {
+		if dg.State == dog.StateWorking && strings.HasPrefix(dg.Work, "plugin:") {
+			inFlight[strings.TrimPrefix(dg.Work, "plugin:")] = true
+		}
+	}
+	return inFlight
+}
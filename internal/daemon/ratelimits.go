@@ -0,0 +1,187 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/ratelimit"
+)
+
+// rotationGrace is how long after a rotation a session is left alone, so the
+// old limit message still on screen doesn't trigger another rotation.
+const rotationGrace = 5 * time.Minute
+
+// rateLimitPaneLines is how much recent pane output is scanned for limits.
+const rateLimitPaneLines = 30
+
+// checkRateLimits finds polecat sessions stuck on an account usage or rate
+// limit, puts the account on cooldown, and restarts the session under the
+// next available account (resuming the conversation where the agent
+// supports it).
+func (d *Daemon) checkRateLimits() {
+	townRoot := d.config.TownRoot
+	cfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+	if err != nil || len(cfg.Accounts) == 0 {
+		return // No accounts configured - nothing to rotate between
+	}
+
+	state, err := ratelimit.LoadState(townRoot)
+	if err != nil {
+		d.logger.Printf("Rate limits: %v", err)
+		return
+	}
+	now := time.Now()
+	changed := state.Prune(now)
+	if d.rateLimitRotations == nil {
+		d.rateLimitRotations = make(map[string]time.Time)
+	}
+
+	for _, rigName := range d.getKnownRigs() {
+		polecats, err := listPolecatWorktrees(filepath.Join(townRoot, rigName, "polecats"))
+		if err != nil {
+			continue
+		}
+		for _, polecatName := range polecats {
+			if d.checkPolecatRateLimit(cfg, state, rigName, polecatName, now) {
+				changed = true
+			}
+		}
+	}
+
+	if changed {
+		if err := ratelimit.SaveState(townRoot, state); err != nil {
+			d.logger.Printf("Rate limits: saving cooldowns: %v", err)
+		}
+	}
+}
+
+// checkPolecatRateLimit handles one polecat session. It reports whether a
+// cooldown was started.
+func (d *Daemon) checkPolecatRateLimit(cfg *config.AccountsConfig, state *ratelimit.State, rigName, polecatName string, now time.Time) bool {
+	sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
+	if alive, err := d.tmux.HasSession(sessionName); err != nil || !alive {
+		return false
+	}
+	if rotated, ok := d.rateLimitRotations[sessionName]; ok && now.Sub(rotated) < rotationGrace {
+		return false
+	}
+
+	// Accounts only apply to runtimes that take a config dir (Claude)
+	rigPath := filepath.Join(d.config.TownRoot, rigName)
+	agentName, _ := d.tmux.GetEnvironment(sessionName, "GT_AGENT")
+	var rc *config.RuntimeConfig
+	if agentName != "" {
+		var err error
+		if rc, _, err = config.ResolveAgentConfigWithOverride(d.config.TownRoot, rigPath, agentName); err != nil {
+			return false
+		}
+	} else {
+		rc = config.ResolveRoleAgentConfig("polecat", d.config.TownRoot, rigPath)
+		agentName, _ = config.ResolveRoleAgentName("polecat", d.config.TownRoot, rigPath)
+	}
+	if rc.Session == nil || rc.Session.ConfigDirEnv == "" {
+		return false
+	}
+
+	configDir, _ := d.tmux.GetEnvironment(sessionName, rc.Session.ConfigDirEnv)
+	handle := ratelimit.AccountForConfigDir(cfg, configDir)
+	if handle == "" {
+		return false // Session isn't on a registered account
+	}
+	if configDir == "" {
+		configDir = ratelimit.ConfigDir(cfg, handle)
+	}
+	workDir := polecatWorkDir(rigPath, rigName, polecatName)
+
+	// Pane output first; the transcript catches limits scrolled off screen
+	var hit ratelimit.Hit
+	found := false
+	if pane, err := d.tmux.CapturePane(sessionName, rateLimitPaneLines); err == nil {
+		hit, found = ratelimit.DetectText(pane, now)
+	}
+	if !found {
+		if _, transcript, err := ratelimit.LatestSession(configDir, workDir); err == nil {
+			hit, found = ratelimit.DetectTranscript(transcript, now)
+		}
+	}
+	if !found {
+		return false
+	}
+
+	started := false
+	if state.Active(handle, now) == nil {
+		cd := state.Start(handle, sessionName, hit, now)
+		started = true
+		d.logger.Printf("Rate limits: account %s hit its limit in %s (%s); cooling down until %s",
+			handle, sessionName, hit.Message, cd.Until.Format(time.RFC3339))
+	}
+
+	next := state.NextAvailable(cfg, handle, now)
+	if next == "" || next == handle {
+		d.logger.Printf("Rate limits: %s is limited and no other account is available", sessionName)
+		d.rateLimitRotations[sessionName] = now
+		return started
+	}
+
+	if err := d.rotateSessionAccount(sessionName, rigName, polecatName, agentName, workDir, configDir, ratelimit.ConfigDir(cfg, next)); err != nil {
+		d.logger.Printf("Rate limits: rotating %s to account %s: %v", sessionName, next, err)
+	} else {
+		d.logger.Printf("Rate limits: restarted %s on account %s", sessionName, next)
+	}
+	d.rateLimitRotations[sessionName] = now
+	return started
+}
+
+// rotateSessionAccount restarts a polecat session in place under another
+// account's config dir. The conversation is resumed when the agent supports
+// it and its transcript can be carried over; otherwise the polecat starts
+// fresh and picks its work back up from the hook.
+func (d *Daemon) rotateSessionAccount(sessionName, rigName, polecatName, agentName, workDir, fromDir, toDir string) error {
+	rigPath := filepath.Join(d.config.TownRoot, rigName)
+	envVars := config.AgentEnv(config.AgentEnvConfig{
+		Role:             "polecat",
+		Rig:              rigName,
+		AgentName:        polecatName,
+		TownRoot:         d.config.TownRoot,
+		RuntimeConfigDir: toDir,
+	})
+
+	var startCmd string
+	if config.SupportsSessionResume(agentName) {
+		if id, _, err := ratelimit.LatestSession(fromDir, workDir); err == nil {
+			if err := ratelimit.CopySession(fromDir, toDir, workDir, id); err == nil {
+				if resume := config.BuildResumeCommand(agentName, id); resume != "" {
+					startCmd = config.PrependEnv(resume, envVars)
+				}
+			}
+		}
+	}
+	if startCmd == "" {
+		startCmd = config.BuildStartupCommand(envVars, rigPath, "")
+	}
+
+	// Keep the session environment in step so respawns use the new account
+	for k, v := range envVars {
+		_ = d.tmux.SetEnvironment(sessionName, k, v)
+	}
+
+	paneID, err := d.tmux.GetPaneID(sessionName)
+	if err != nil {
+		return fmt.Errorf("finding pane: %w", err)
+	}
+	return d.tmux.RespawnPane(paneID, startCmd)
+}
+
+// polecatWorkDir returns a polecat's working directory, handling both the
+// polecats/<name>/<rig>/ and the older polecats/<name>/ layouts.
+func polecatWorkDir(rigPath, rigName, polecatName string) string {
+	workDir := filepath.Join(rigPath, "polecats", polecatName, rigName)
+	if _, err := os.Stat(workDir); os.IsNotExist(err) {
+		return filepath.Join(rigPath, "polecats", polecatName)
+	}
+	return workDir
+}
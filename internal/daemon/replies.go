@@ -0,0 +1,22 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// expireOverdueReplies expires mail requests whose reply deadline has
+// passed (see mail.Router.SendRequest). Each expiry logs a
+// mail_reply_timeout event that patrols act on by retrying or escalating.
+func (d *Daemon) expireOverdueReplies() {
+	expired, err := mail.NewPendingStore(d.config.TownRoot).ExpireOverdue(time.Now())
+	if err != nil {
+		d.logger.Printf("Mail replies: %v", err)
+		return
+	}
+	for _, req := range expired {
+		d.logger.Printf("Mail replies: %s → %s %q got no reply by %s",
+			req.From, req.To, req.Subject, req.Deadline.Format(time.RFC3339))
+	}
+}
@@ -111,12 +111,32 @@ type PatrolConfig struct {
 	Rigs []string `json:"rigs,omitempty"`
 }
 
+// MetricsConfig configures the metrics patrol, which writes town health
+// metrics for node_exporter's textfile collector.
+type MetricsConfig struct {
+	// Enabled controls whether metrics are written (opt-in).
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to write them (default: every heartbeat).
+	Interval string `json:"interval,omitempty"`
+
+	// Textfile is the file to write (default: .runtime/metrics/gastown.prom).
+	Textfile string `json:"textfile,omitempty"`
+}
+
 // PatrolsConfig holds configuration for all patrols.
 type PatrolsConfig struct {
-	Refinery   *PatrolConfig     `json:"refinery,omitempty"`
-	Witness    *PatrolConfig     `json:"witness,omitempty"`
-	Deacon     *PatrolConfig     `json:"deacon,omitempty"`
-	DoltServer *DoltServerConfig `json:"dolt_server,omitempty"`
+	Refinery        *PatrolConfig     `json:"refinery,omitempty"`
+	Witness         *PatrolConfig     `json:"witness,omitempty"`
+	Deacon          *PatrolConfig     `json:"deacon,omitempty"`
+	Plugins         *PatrolConfig     `json:"plugins,omitempty"`
+	Doctor          *PatrolConfig     `json:"doctor,omitempty"`
+	Warrants        *PatrolConfig     `json:"warrants,omitempty"`
+	Budgets         *PatrolConfig     `json:"budgets,omitempty"`
+	AccountRotation *PatrolConfig     `json:"account_rotation,omitempty"`
+	MailReplies     *PatrolConfig     `json:"mail_replies,omitempty"`
+	Metrics         *MetricsConfig    `json:"metrics,omitempty"`
+	DoltServer      *DoltServerConfig `json:"dolt_server,omitempty"`
 }
 
 // DaemonPatrolConfig is the structure of mayor/daemon.json.
@@ -150,7 +170,17 @@ func LoadPatrolConfig(townRoot string) *DaemonPatrolConfig {
 
 // IsPatrolEnabled checks if a patrol is enabled in the config.
 // Returns true if the config doesn't exist (default enabled for backwards compatibility).
+// The plugins patrol is the exception: it is opt-in, since the Deacon's own
+// patrol also runs plugins and enabling both would double-dispatch.
 func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
+	if patrol == "plugins" {
+		return config != nil && config.Patrols != nil &&
+			config.Patrols.Plugins != nil && config.Patrols.Plugins.Enabled
+	}
+	if patrol == "metrics" {
+		return config != nil && config.Patrols != nil &&
+			config.Patrols.Metrics != nil && config.Patrols.Metrics.Enabled
+	}
 	if config == nil || config.Patrols == nil {
 		return true // Default: enabled
 	}
@@ -168,6 +198,26 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		if config.Patrols.Deacon != nil {
 			return config.Patrols.Deacon.Enabled
 		}
+	case "doctor":
+		if config.Patrols.Doctor != nil {
+			return config.Patrols.Doctor.Enabled
+		}
+	case "warrants":
+		if config.Patrols.Warrants != nil {
+			return config.Patrols.Warrants.Enabled
+		}
+	case "budgets":
+		if config.Patrols.Budgets != nil {
+			return config.Patrols.Budgets.Enabled
+		}
+	case "account_rotation":
+		if config.Patrols.AccountRotation != nil {
+			return config.Patrols.AccountRotation.Enabled
+		}
+	case "mail_replies":
+		if config.Patrols.MailReplies != nil {
+			return config.Patrols.MailReplies.Enabled
+		}
 	}
 	return true // Default: enabled
 }
@@ -187,6 +237,10 @@ func GetPatrolRigs(config *DaemonPatrolConfig, patrol string) []string {
 		if config.Patrols.Witness != nil {
 			return config.Patrols.Witness.Rigs
 		}
+	case "plugins":
+		if config.Patrols.Plugins != nil {
+			return config.Patrols.Plugins.Rigs
+		}
 	}
 	return nil // All rigs
 }
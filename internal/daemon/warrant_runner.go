@@ -0,0 +1,95 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/warrant"
+)
+
+// warrantTickInterval is how often open warrants are advanced. It only
+// needs to be fine-grained relative to the shortest interrogation timeout
+// (60s); the 3-minute heartbeat is far too coarse.
+const warrantTickInterval = 10 * time.Second
+
+// WarrantRunner runs the shutdown dance for death warrants. Every tick it
+// advances each open warrant by its own timers: interrogating the target
+// session, pardoning it on an ALIVE reply, or killing it. Dance state is
+// kept in the warrant files, so a restarted daemon picks up where it left
+// off.
+type WarrantRunner struct {
+	dancer *warrant.Dancer
+	logger func(format string, args ...interface{})
+	paused func() bool // Skip ticks while true (gt down in progress)
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWarrantRunner creates a warrant runner for the town.
+func NewWarrantRunner(townRoot string, logger func(format string, args ...interface{}), paused func() bool) *WarrantRunner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WarrantRunner{
+		dancer: &warrant.Dancer{TownRoot: townRoot, Sessions: warrantSessions{session.DefaultBackend()}},
+		logger: logger,
+		paused: paused,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// warrantSessions adapts a session backend to the dance: interrogations are
+// tmux nudges, or typed input on other backends.
+type warrantSessions struct {
+	session.Backend
+}
+
+func (s warrantSessions) NudgeSession(name, message string) error {
+	if t, ok := s.Backend.(*tmux.Tmux); ok {
+		return t.NudgeSession(name, message)
+	}
+	return s.SendKeys(name, message)
+}
+
+// Start begins the runner goroutine.
+func (r *WarrantRunner) Start() error {
+	r.wg.Add(1)
+	go r.run()
+	return nil
+}
+
+// Stop gracefully stops the runner. A dance interrupted mid-phase resumes
+// from its saved phase on the next start.
+func (r *WarrantRunner) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *WarrantRunner) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(warrantTickInterval)
+	defer ticker.Stop()
+
+	r.tick()
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.tick()
+		}
+	}
+}
+
+func (r *WarrantRunner) tick() {
+	if r.paused != nil && r.paused() {
+		return
+	}
+	if err := r.dancer.Tick(); err != nil {
+		r.logger("Warrant shutdown dance: %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/webhook"
+)
+
+// startWebhookServer starts the webhook listener if settings/webhooks.json
+// enables it. A missing file leaves ingress off; a broken one is logged and
+// skipped so the rest of the daemon still runs.
+func (d *Daemon) startWebhookServer() {
+	path := config.WebhooksConfigPath(d.config.TownRoot)
+	cfg, err := config.LoadWebhooksConfig(path)
+	if err != nil {
+		if !errors.Is(err, config.ErrNotFound) {
+			d.logger.Printf("Warning: webhook ingress disabled: %v", err)
+		}
+		return
+	}
+	if !cfg.Enabled {
+		return
+	}
+
+	handler, err := webhook.NewHandler(cfg, &webhook.ExecActions{
+		TownRoot: d.config.TownRoot,
+		GtPath:   d.gtPath,
+		BdPath:   d.bdPath,
+	}, d.logger.Printf)
+	if err != nil {
+		d.logger.Printf("Warning: webhook ingress disabled: %v", err)
+		return
+	}
+
+	addr := cfg.Listen
+	if addr == "" {
+		addr = config.DefaultWebhookListen
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		d.logger.Printf("Warning: webhook ingress disabled: %v", err)
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(webhook.PathPrefix, handler)
+	d.webhookServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      2 * time.Minute, // actions shell out to gt/bd
+		IdleTimeout:       120 * time.Second,
+	}
+	go func() {
+		if err := d.webhookServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			d.logger.Printf("Webhook server stopped: %v", err)
+		}
+	}()
+	d.logger.Printf("Webhook ingress listening on %s (%d routes)", ln.Addr(), len(cfg.Routes))
+}
+
+// stopWebhookServer shuts the webhook listener down, letting in-flight
+// requests finish for a few seconds.
+func (d *Daemon) stopWebhookServer() {
+	if d.webhookServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := d.webhookServer.Shutdown(ctx); err != nil {
+		d.logger.Printf("Warning: webhook server shutdown: %v", err)
+	}
+	d.webhookServer = nil
+	d.logger.Println("Webhook server stopped")
+}
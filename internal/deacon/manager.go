@@ -12,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 )
 
 // Common errors
@@ -20,17 +21,14 @@ var (
 	ErrAlreadyRunning = errors.New("deacon already running")
 )
 
-// tmuxOps abstracts tmux operations for testing.
+// tmuxOps abstracts tmux operations for testing: the session backend plus
+// the tmux-specific styling and agent checks the deacon uses.
 type tmuxOps interface {
-	HasSession(name string) (bool, error)
+	session.Backend
 	IsAgentAlive(session string) bool
-	KillSessionWithProcesses(name string) error
-	NewSessionWithCommand(name, workDir, command string) error
 	SetRemainOnExit(pane string, on bool) error
-	SetEnvironment(session, key, value string) error
 	ConfigureGasTownSession(session string, theme tmux.Theme, rig, worker, role string) error
 	WaitForCommand(session string, excludeCommands []string, timeout time.Duration) error
-	SetAutoRespawnHook(session, townRoot, startupCmd string) error
 	AcceptBypassPermissionsWarning(session string) error
 	SendKeysRaw(session, keys string) error
 	GetSessionInfo(name string) (*tmux.SessionInfo, error)
@@ -120,6 +118,11 @@ func (m *Manager) Start(agentOverride string) error {
 	// The pane will show "[Exited]" status but remain available for respawn.
 	_ = t.SetRemainOnExit(sessionID, true)
 
+	// Record pane output so the session can be replayed after it is gone (non-fatal)
+	if realTmux, ok := t.(*tmux.Tmux); ok {
+		_ = transcript.Start(realTmux, m.townRoot, sessionID)
+	}
+
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	envVars := config.AgentEnv(config.AgentEnvConfig{
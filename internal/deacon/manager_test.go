@@ -6,46 +6,34 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/session/sessiontest"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
-// mockTmux implements tmuxOps for testing.
+// mockTmux implements tmuxOps for testing: a fake session backend plus
+// the tmux-specific calls the deacon makes.
 type mockTmux struct {
-	hasSessionResult bool
-	hasSessionErr    error
-	agentAlive       bool
-	killErr          error
-	newSessionErr    error
-	waitErr          error
-	sessionInfo      *tmux.SessionInfo
-	sessionInfoErr   error
-	sendKeysErr      error
-
-	// Call tracking
-	killCalls       []string
-	newSessionCalls int
+	*sessiontest.Backend
+	agentAlive     bool
+	waitErr        error
+	sessionInfo    *tmux.SessionInfo
+	sessionInfoErr error
+	sendKeysErr    error
 }
 
-func (m *mockTmux) HasSession(name string) (bool, error) {
-	return m.hasSessionResult, m.hasSessionErr
+// newMockTmux returns a mock with the deacon session running or not.
+func newMockTmux(running bool) *mockTmux {
+	if running {
+		return &mockTmux{Backend: sessiontest.New(SessionName())}
+	}
+	return &mockTmux{Backend: sessiontest.New()}
 }
 
 func (m *mockTmux) IsAgentAlive(_ string) bool {
 	return m.agentAlive
 }
 
-func (m *mockTmux) KillSessionWithProcesses(name string) error {
-	m.killCalls = append(m.killCalls, name)
-	return m.killErr
-}
-
-func (m *mockTmux) NewSessionWithCommand(_, _, _ string) error {
-	m.newSessionCalls++
-	return m.newSessionErr
-}
-
 func (m *mockTmux) SetRemainOnExit(_ string, _ bool) error { return nil }
-func (m *mockTmux) SetEnvironment(_, _, _ string) error     { return nil }
 func (m *mockTmux) ConfigureGasTownSession(_ string, _ tmux.Theme, _, _, _ string) error {
 	return nil
 }
@@ -54,9 +42,8 @@ func (m *mockTmux) WaitForCommand(_ string, _ []string, _ time.Duration) error {
 	return m.waitErr
 }
 
-func (m *mockTmux) SetAutoRespawnHook(_, _, _ string) error        { return nil }
-func (m *mockTmux) AcceptBypassPermissionsWarning(_ string) error  { return nil }
-func (m *mockTmux) SendKeysRaw(_, _ string) error                  { return m.sendKeysErr }
+func (m *mockTmux) AcceptBypassPermissionsWarning(_ string) error { return nil }
+func (m *mockTmux) SendKeysRaw(_, _ string) error                 { return m.sendKeysErr }
 func (m *mockTmux) GetSessionInfo(_ string) (*tmux.SessionInfo, error) {
 	return m.sessionInfo, m.sessionInfoErr
 }
@@ -99,10 +86,8 @@ func TestManager_deaconDir(t *testing.T) {
 }
 
 func TestStart_AlreadyRunning(t *testing.T) {
-	mock := &mockTmux{
-		hasSessionResult: true,
-		agentAlive:       true,
-	}
+	mock := newMockTmux(true)
+	mock.agentAlive = true
 	m := newTestManager(t.TempDir(), mock)
 
 	err := m.Start("")
@@ -113,11 +98,8 @@ func TestStart_AlreadyRunning(t *testing.T) {
 
 func TestStart_ZombieDetected_KillFails(t *testing.T) {
 	killErr := errors.New("kill failed: session locked")
-	mock := &mockTmux{
-		hasSessionResult: true,
-		agentAlive:       false, // zombie: tmux alive, agent dead
-		killErr:          killErr,
-	}
+	mock := newMockTmux(true) // zombie: session alive, agent dead
+	mock.KillErr = killErr
 	m := newTestManager(t.TempDir(), mock)
 
 	err := m.Start("")
@@ -127,22 +109,18 @@ func TestStart_ZombieDetected_KillFails(t *testing.T) {
 	if !errors.Is(err, killErr) {
 		t.Errorf("Start() error = %v, should wrap %v", err, killErr)
 	}
-	if len(mock.killCalls) != 1 {
-		t.Errorf("expected 1 kill call, got %d", len(mock.killCalls))
+	if len(mock.Killed) != 1 {
+		t.Errorf("expected 1 kill call, got %d", len(mock.Killed))
 	}
-	if len(mock.killCalls) > 0 && mock.killCalls[0] != m.SessionName() {
-		t.Errorf("killed session %q, want %q", mock.killCalls[0], m.SessionName())
+	if len(mock.Killed) > 0 && mock.Killed[0] != m.SessionName() {
+		t.Errorf("killed session %q, want %q", mock.Killed[0], m.SessionName())
 	}
 }
 
 func TestStart_ZombieDetected_KillSucceeds(t *testing.T) {
 	// Zombie kill succeeds, Start continues into config/runtime.
 	// We verify the zombie was detected and killed.
-	mock := &mockTmux{
-		hasSessionResult: true,
-		agentAlive:       false, // zombie
-		killErr:          nil,   // kill succeeds
-	}
+	mock := newMockTmux(true) // zombie
 	m := newTestManager(t.TempDir(), mock)
 
 	// Start will proceed past zombie kill into config resolution.
@@ -150,57 +128,51 @@ func TestStart_ZombieDetected_KillSucceeds(t *testing.T) {
 	// in the test environment - that's fine, we're verifying zombie handling.
 	_ = m.Start("")
 
-	if len(mock.killCalls) != 1 {
-		t.Errorf("expected 1 zombie kill call, got %d", len(mock.killCalls))
+	if len(mock.Killed) != 1 {
+		t.Errorf("expected 1 zombie kill call, got %d", len(mock.Killed))
 	}
 }
 
 func TestStart_NoExistingSession(t *testing.T) {
 	// No existing session - Start proceeds to create one.
 	// Will hit config/runtime calls which may error in test env.
-	mock := &mockTmux{
-		hasSessionResult: false,
-	}
+	mock := newMockTmux(false)
 	m := newTestManager(t.TempDir(), mock)
 
 	_ = m.Start("")
 
 	// Should NOT have tried to kill anything
-	if len(mock.killCalls) != 0 {
-		t.Errorf("expected 0 kill calls, got %d", len(mock.killCalls))
+	if len(mock.Killed) != 0 {
+		t.Errorf("expected 0 kill calls, got %d", len(mock.Killed))
 	}
 }
 
 func TestStart_HasSessionError(t *testing.T) {
 	// HasSession error is ignored (line 59: running, _ := ...).
 	// When HasSession errors, running=false, so Start proceeds normally.
-	mock := &mockTmux{
-		hasSessionResult: false,
-		hasSessionErr:    errors.New("tmux not available"),
-	}
+	mock := newMockTmux(false)
+	mock.HasSessionErr = errors.New("tmux not available")
 	m := newTestManager(t.TempDir(), mock)
 
 	_ = m.Start("")
 
 	// Should NOT have tried to kill anything
-	if len(mock.killCalls) != 0 {
-		t.Errorf("expected 0 kill calls when HasSession errors, got %d", len(mock.killCalls))
+	if len(mock.Killed) != 0 {
+		t.Errorf("expected 0 kill calls when HasSession errors, got %d", len(mock.Killed))
 	}
 }
 
 func TestStart_SessionCreateFails(t *testing.T) {
 	// Test that NewSessionWithCommand failure is propagated.
-	mock := &mockTmux{
-		hasSessionResult: false,
-		newSessionErr:    errors.New("tmux server not running"),
-	}
+	mock := newMockTmux(false)
+	mock.NewSessionErr = errors.New("tmux server not running")
 	m := newTestManager(t.TempDir(), mock)
 
 	err := m.Start("claude")
 	if err == nil {
 		// If we got past config without error, session creation should have failed.
 		// But config may have failed first - check if NewSessionWithCommand was called.
-		if mock.newSessionCalls > 0 {
+		if len(mock.Created) > 0 {
 			t.Fatal("Start() should return error when session creation fails")
 		}
 		// Config failed before reaching session creation - acceptable in test env.
@@ -208,7 +180,7 @@ func TestStart_SessionCreateFails(t *testing.T) {
 	}
 
 	// If NewSessionWithCommand was called and failed, error should wrap it.
-	if mock.newSessionCalls > 0 {
+	if len(mock.Created) > 0 {
 		if got := err.Error(); got == "" {
 			t.Error("error should have content")
 		}
@@ -218,17 +190,14 @@ func TestStart_SessionCreateFails(t *testing.T) {
 func TestStart_WaitForCommandFails(t *testing.T) {
 	// WaitForCommand failure should kill the session and return error.
 	waitErr := errors.New("timeout waiting for agent")
-	mock := &mockTmux{
-		hasSessionResult: false,
-		newSessionErr:    nil,
-		waitErr:          waitErr,
-	}
+	mock := newMockTmux(false)
+	mock.waitErr = waitErr
 	m := newTestManager(t.TempDir(), mock)
 
 	err := m.Start("claude")
 
 	// If we got past config to WaitForCommand, verify cleanup behavior.
-	if mock.newSessionCalls > 0 {
+	if len(mock.Created) > 0 {
 		// Session was created, WaitForCommand was called.
 		if err == nil {
 			t.Fatal("Start() should return error when WaitForCommand fails")
@@ -237,7 +206,7 @@ func TestStart_WaitForCommandFails(t *testing.T) {
 			t.Errorf("Start() error = %v, should wrap %v", err, waitErr)
 		}
 		// Should have killed the zombie session as cleanup (line 122).
-		if len(mock.killCalls) == 0 {
+		if len(mock.Killed) == 0 {
 			t.Error("expected cleanup kill call after WaitForCommand failure")
 		}
 	}
@@ -246,9 +215,7 @@ func TestStart_WaitForCommandFails(t *testing.T) {
 }
 
 func TestStop_NotRunning(t *testing.T) {
-	mock := &mockTmux{
-		hasSessionResult: false,
-	}
+	mock := newMockTmux(false)
 	m := newTestManager(t.TempDir(), mock)
 
 	err := m.Stop()
@@ -259,9 +226,8 @@ func TestStop_NotRunning(t *testing.T) {
 
 func TestStop_HasSessionError(t *testing.T) {
 	sessionErr := errors.New("tmux server crashed")
-	mock := &mockTmux{
-		hasSessionErr: sessionErr,
-	}
+	mock := newMockTmux(false)
+	mock.HasSessionErr = sessionErr
 	m := newTestManager(t.TempDir(), mock)
 
 	err := m.Stop()
@@ -274,26 +240,22 @@ func TestStop_HasSessionError(t *testing.T) {
 }
 
 func TestStop_Success(t *testing.T) {
-	mock := &mockTmux{
-		hasSessionResult: true,
-	}
+	mock := newMockTmux(true)
 	m := newTestManager(t.TempDir(), mock)
 
 	err := m.Stop()
 	if err != nil {
 		t.Errorf("Stop() error = %v, want nil", err)
 	}
-	if len(mock.killCalls) != 1 {
-		t.Errorf("expected 1 kill call, got %d", len(mock.killCalls))
+	if len(mock.Killed) != 1 {
+		t.Errorf("expected 1 kill call, got %d", len(mock.Killed))
 	}
 }
 
 func TestStop_KillFails(t *testing.T) {
 	killErr := errors.New("permission denied")
-	mock := &mockTmux{
-		hasSessionResult: true,
-		killErr:          killErr,
-	}
+	mock := newMockTmux(true)
+	mock.KillErr = killErr
 	m := newTestManager(t.TempDir(), mock)
 
 	err := m.Stop()
@@ -332,10 +294,8 @@ func TestIsRunning(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			mock := &mockTmux{
-				hasSessionResult: tc.running,
-				hasSessionErr:    tc.err,
-			}
+			mock := newMockTmux(tc.running)
+			mock.HasSessionErr = tc.err
 			m := newTestManager(t.TempDir(), mock)
 
 			running, err := m.IsRunning()
@@ -350,9 +310,7 @@ func TestIsRunning(t *testing.T) {
 }
 
 func TestStatus_NotRunning(t *testing.T) {
-	mock := &mockTmux{
-		hasSessionResult: false,
-	}
+	mock := newMockTmux(false)
 	m := newTestManager(t.TempDir(), mock)
 
 	info, err := m.Status()
@@ -366,9 +324,8 @@ func TestStatus_NotRunning(t *testing.T) {
 
 func TestStatus_HasSessionError(t *testing.T) {
 	sessionErr := errors.New("tmux gone")
-	mock := &mockTmux{
-		hasSessionErr: sessionErr,
-	}
+	mock := newMockTmux(false)
+	mock.HasSessionErr = sessionErr
 	m := newTestManager(t.TempDir(), mock)
 
 	info, err := m.Status()
@@ -388,10 +345,8 @@ func TestStatus_Running(t *testing.T) {
 		Name:    "hq-deacon",
 		Windows: 1,
 	}
-	mock := &mockTmux{
-		hasSessionResult: true,
-		sessionInfo:      expected,
-	}
+	mock := newMockTmux(true)
+	mock.sessionInfo = expected
 	m := newTestManager(t.TempDir(), mock)
 
 	info, err := m.Status()
@@ -405,10 +360,8 @@ func TestStatus_Running(t *testing.T) {
 
 func TestStatus_GetSessionInfoError(t *testing.T) {
 	infoErr := errors.New("session info unavailable")
-	mock := &mockTmux{
-		hasSessionResult: true,
-		sessionInfoErr:   infoErr,
-	}
+	mock := newMockTmux(true)
+	mock.sessionInfoErr = infoErr
 	m := newTestManager(t.TempDir(), mock)
 
 	info, err := m.Status()
@@ -150,6 +150,7 @@ func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 				fmt.Fprintf(w, "%s", ui.RenderMuted(" (fixing)..."))
 			}
 
+			problem := result.Message
 			err := check.Fix(ctx)
 			if err == nil {
 				// Re-run check to verify fix worked
@@ -169,7 +170,10 @@ func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 			} else {
 				// Fix failed, add error to details
 				result.Details = append(result.Details, "Fix failed: "+err.Error())
+				result.FixError = err.Error()
 			}
+			result.FixAttempted = true
+			result.Problem = problem
 		}
 
 		// Record total elapsed time including any fix attempts
@@ -260,3 +264,22 @@ type FixableCheck struct {
 func (f *FixableCheck) CanFix() bool {
 	return true
 }
+
+// QuickChecks returns a cheap subset of checks, fast enough to run on a
+// schedule (gt doctor --quick). They read local files and config and avoid
+// scanning sessions, processes, or the beads database.
+func QuickChecks() []Check {
+	checks := WorkspaceChecks()
+	return append(checks,
+		NewTownGitCheck(),
+		NewTownRootBranchCheck(),
+		NewRoutesCheck(),
+		NewRigRoutesJSONLCheck(),
+		NewRuntimeGitignoreCheck(),
+		NewHooksSyncCheck(),
+		NewCrewStateCheck(),
+		NewPatrolHooksWiredCheck(),
+		NewPatrolPluginsAccessibleCheck(),
+		NewDoltServerReachableCheck(),
+	)
+}
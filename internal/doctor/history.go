@@ -0,0 +1,311 @@
+package doctor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Sources of recorded doctor runs.
+const (
+	SourceCLI    = "cli"    // gt doctor
+	SourceDaemon = "daemon" // Scheduled quick checks
+)
+
+// maxHistoryBytes is the size at which the history file is trimmed to its
+// newer half.
+const maxHistoryBytes = 16 << 20
+
+// RunRecord is one persisted doctor run.
+type RunRecord struct {
+	Timestamp time.Time     `json:"ts"`
+	Source    string        `json:"source"`
+	Rig       string        `json:"rig,omitempty"`
+	Quick     bool          `json:"quick,omitempty"`
+	Fix       bool          `json:"fix,omitempty"`
+	Checks    []CheckRecord `json:"checks"`
+}
+
+// CheckRecord is a persisted check result. Details are kept only for
+// checks that did not pass.
+type CheckRecord struct {
+	Name      string     `json:"name"`
+	Category  string     `json:"category,omitempty"`
+	Status    string     `json:"status"`
+	Message   string     `json:"message,omitempty"`
+	Details   []string   `json:"details,omitempty"`
+	ElapsedMs int64      `json:"elapsed_ms"`
+	Fix       *FixRecord `json:"fix,omitempty"`
+}
+
+// FixRecord audits a fix attempted by --fix.
+type FixRecord struct {
+	Problem string `json:"problem"`         // Message before the fix
+	Fixed   bool   `json:"fixed"`           // Check passed after the fix
+	Error   string `json:"error,omitempty"` // Fix failure
+}
+
+// OK reports whether the check passed.
+func (c CheckRecord) OK() bool {
+	return c.Status == StatusOK.String()
+}
+
+// NewRunRecord converts a report into a history record.
+func NewRunRecord(report *Report, source, rig string, quick, fix bool) *RunRecord {
+	rec := &RunRecord{
+		Timestamp: report.Timestamp,
+		Source:    source,
+		Rig:       rig,
+		Quick:     quick,
+		Fix:       fix,
+		Checks:    make([]CheckRecord, 0, len(report.Checks)),
+	}
+	for _, r := range report.Checks {
+		c := CheckRecord{
+			Name:      r.Name,
+			Category:  r.Category,
+			Status:    r.Status.String(),
+			Message:   r.Message,
+			ElapsedMs: r.Elapsed.Milliseconds(),
+		}
+		if r.Status != StatusOK {
+			c.Details = r.Details
+		}
+		if r.FixAttempted {
+			c.Fix = &FixRecord{Problem: r.Problem, Fixed: r.Fixed, Error: r.FixError}
+		}
+		rec.Checks = append(rec.Checks, c)
+	}
+	return rec
+}
+
+// HistoryPath returns the town's doctor history file.
+func HistoryPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "doctor-history.jsonl")
+}
+
+// AppendHistory persists a run.
+func AppendHistory(townRoot string, rec *RunRecord) error {
+	path := HistoryPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring doctor history lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // G302: history is non-sensitive
+	if err != nil {
+		return fmt.Errorf("opening doctor history: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing doctor history: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Size() > maxHistoryBytes {
+		return trimHistory(path)
+	}
+	return nil
+}
+
+// trimHistory keeps the newer half of the history file's runs.
+func trimHistory(path string) error {
+	runs, err := readHistory(path, time.Time{})
+	if err != nil {
+		return err
+	}
+	runs = runs[len(runs)/2:]
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("trimming doctor history: %w", err)
+	}
+	enc := json.NewEncoder(f)
+	for _, run := range runs {
+		if err := enc.Encode(run); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadHistory returns recorded runs since the given time (zero for all),
+// oldest first.
+func LoadHistory(townRoot string, since time.Time) ([]*RunRecord, error) {
+	return readHistory(HistoryPath(townRoot), since)
+}
+
+func readHistory(path string, since time.Time) ([]*RunRecord, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading doctor history: %w", err)
+	}
+	defer f.Close()
+
+	var runs []*RunRecord
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 4<<20)
+	for sc.Scan() {
+		var run RunRecord
+		if json.Unmarshal(sc.Bytes(), &run) != nil {
+			continue // Skip a line cut short by a crash
+		}
+		if !since.IsZero() && run.Timestamp.Before(since) {
+			continue
+		}
+		runs = append(runs, &run)
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Timestamp.Before(runs[j].Timestamp) })
+	return runs, sc.Err()
+}
+
+// CheckTrend summarizes one check across recorded runs.
+type CheckTrend struct {
+	Name         string     `json:"name"`
+	Rig          string     `json:"rig,omitempty"`
+	Status       string     `json:"status"` // Latest status
+	Message      string     `json:"message,omitempty"`
+	Runs         int        `json:"runs"`
+	Failures     int        `json:"failures"`                // Runs not OK
+	Flaps        int        `json:"flaps"`                   // Changes between OK and not OK
+	FailingSince *time.Time `json:"failing_since,omitempty"` // Start of the current failure streak
+	LastOK       *time.Time `json:"last_ok,omitempty"`
+	LastSeen     time.Time  `json:"last_seen"`
+}
+
+// Failing reports whether the check's latest run did not pass.
+func (t *CheckTrend) Failing() bool {
+	return t.Status != StatusOK.String()
+}
+
+// Trends summarizes each check (per rig, for rig-scoped runs) across runs,
+// which must be oldest first. Checks failing now come first, then by name.
+func Trends(runs []*RunRecord) []*CheckTrend {
+	byKey := make(map[string]*CheckTrend)
+	var trends []*CheckTrend
+	for _, run := range runs {
+		for _, c := range run.Checks {
+			key := run.Rig + "\x00" + c.Name
+			t, ok := byKey[key]
+			if !ok {
+				t = &CheckTrend{Name: c.Name, Rig: run.Rig}
+				byKey[key] = t
+				trends = append(trends, t)
+			}
+			ts := run.Timestamp
+			wasFailing := t.Runs > 0 && t.Failing()
+			t.Runs++
+			t.Status = c.Status
+			t.Message = c.Message
+			t.LastSeen = ts
+			if c.OK() {
+				t.LastOK = &ts
+				t.FailingSince = nil
+				if wasFailing {
+					t.Flaps++
+				}
+				continue
+			}
+			t.Failures++
+			if !wasFailing {
+				t.FailingSince = &ts
+				if t.Runs > 1 {
+					t.Flaps++
+				}
+			}
+		}
+	}
+	sort.SliceStable(trends, func(i, j int) bool {
+		if trends[i].Failing() != trends[j].Failing() {
+			return trends[i].Failing()
+		}
+		if trends[i].Rig != trends[j].Rig {
+			return trends[i].Rig < trends[j].Rig
+		}
+		return trends[i].Name < trends[j].Name
+	})
+	return trends
+}
+
+// Regressions returns the checks in run whose status is worse than the last
+// recorded status of the same check in history (a check never seen before
+// counts as previously OK).
+func Regressions(history []*RunRecord, run *RunRecord) []CheckRecord {
+	prev := make(map[string]string)
+	for _, h := range history {
+		if h.Rig != run.Rig {
+			continue
+		}
+		for _, c := range h.Checks {
+			prev[c.Name] = c.Status
+		}
+	}
+	var regressed []CheckRecord
+	for _, c := range run.Checks {
+		before, ok := prev[c.Name]
+		if !ok {
+			before = StatusOK.String()
+		}
+		if statusRank(c.Status) > statusRank(before) {
+			regressed = append(regressed, c)
+		}
+	}
+	return regressed
+}
+
+func statusRank(status string) int {
+	switch status {
+	case StatusOK.String():
+		return 0
+	case StatusWarning.String():
+		return 1
+	default:
+		return 2
+	}
+}
+
+// FixEntry is a fix attempt found in history.
+type FixEntry struct {
+	Timestamp time.Time `json:"ts"`
+	Check     string    `json:"check"`
+	Rig       string    `json:"rig,omitempty"`
+	FixRecord
+}
+
+// Fixes returns the fix attempts recorded in runs, oldest first.
+func Fixes(runs []*RunRecord) []FixEntry {
+	var fixes []FixEntry
+	for _, run := range runs {
+		for _, c := range run.Checks {
+			if c.Fix != nil {
+				fixes = append(fixes, FixEntry{Timestamp: run.Timestamp, Check: c.Name, Rig: run.Rig, FixRecord: *c.Fix})
+			}
+		}
+	}
+	return fixes
+}
@@ -0,0 +1,160 @@
+package doctor
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func historyRun(ts time.Time, statuses map[string]CheckStatus) *RunRecord {
+	report := &Report{Timestamp: ts}
+	for name, status := range statuses {
+		report.Checks = append(report.Checks, &CheckResult{Name: name, Status: status, Message: name + " " + status.String()})
+	}
+	return NewRunRecord(report, SourceDaemon, "", true, false)
+}
+
+func TestAppendAndLoadHistory(t *testing.T) {
+	town := t.TempDir()
+	base := time.Now().Add(-3 * time.Hour).Truncate(time.Second)
+	for i := 0; i < 3; i++ {
+		run := historyRun(base.Add(time.Duration(i)*time.Hour), map[string]CheckStatus{"routes-config": StatusOK})
+		if err := AppendHistory(town, run); err != nil {
+			t.Fatalf("AppendHistory: %v", err)
+		}
+	}
+
+	runs, err := LoadHistory(town, time.Time{})
+	if err != nil || len(runs) != 3 {
+		t.Fatalf("LoadHistory = %d runs, %v; want 3", len(runs), err)
+	}
+	runs, _ = LoadHistory(town, base.Add(90*time.Minute))
+	if len(runs) != 1 || !runs[0].Timestamp.Equal(base.Add(2*time.Hour)) {
+		t.Errorf("LoadHistory(since) = %+v, want the last run", runs)
+	}
+}
+
+func TestLoadHistoryMissing(t *testing.T) {
+	runs, err := LoadHistory(t.TempDir(), time.Time{})
+	if err != nil || runs != nil {
+		t.Errorf("LoadHistory(no file) = %v, %v", runs, err)
+	}
+}
+
+func TestTrends(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sequence := []CheckStatus{StatusOK, StatusError, StatusOK, StatusWarning, StatusError}
+	var runs []*RunRecord
+	for i, status := range sequence {
+		runs = append(runs, historyRun(base.Add(time.Duration(i)*time.Hour), map[string]CheckStatus{
+			"flaky":  status,
+			"steady": StatusOK,
+		}))
+	}
+
+	trends := Trends(runs)
+	if len(trends) != 2 || trends[0].Name != "flaky" {
+		t.Fatalf("Trends = %+v, want flaky first", trends)
+	}
+	flaky := trends[0]
+	if flaky.Runs != 5 || flaky.Failures != 3 || flaky.Flaps != 3 {
+		t.Errorf("flaky runs/failures/flaps = %d/%d/%d, want 5/3/3", flaky.Runs, flaky.Failures, flaky.Flaps)
+	}
+	if flaky.FailingSince == nil || !flaky.FailingSince.Equal(base.Add(3*time.Hour)) {
+		t.Errorf("flaky FailingSince = %v, want start of the warning streak", flaky.FailingSince)
+	}
+	if flaky.LastOK == nil || !flaky.LastOK.Equal(base.Add(2*time.Hour)) {
+		t.Errorf("flaky LastOK = %v", flaky.LastOK)
+	}
+	if steady := trends[1]; steady.Failing() || steady.Failures != 0 || steady.Flaps != 0 || steady.FailingSince != nil {
+		t.Errorf("steady = %+v", steady)
+	}
+}
+
+func TestRegressions(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := []*RunRecord{historyRun(base, map[string]CheckStatus{
+		"ok-to-warn":   StatusOK,
+		"warn-to-err":  StatusWarning,
+		"err-to-err":   StatusError,
+		"err-to-ok":    StatusError,
+		"warn-to-warn": StatusWarning,
+	})}
+	run := historyRun(base.Add(time.Hour), map[string]CheckStatus{
+		"ok-to-warn":   StatusWarning,
+		"warn-to-err":  StatusError,
+		"err-to-err":   StatusError,
+		"err-to-ok":    StatusOK,
+		"warn-to-warn": StatusWarning,
+		"new-error":    StatusError,
+	})
+
+	got := make(map[string]bool)
+	for _, c := range Regressions(history, run) {
+		got[c.Name] = true
+	}
+	want := []string{"ok-to-warn", "warn-to-err", "new-error"}
+	if len(got) != len(want) {
+		t.Errorf("Regressions = %v, want %v", got, want)
+	}
+	for _, name := range want {
+		if !got[name] {
+			t.Errorf("Regressions missing %s", name)
+		}
+	}
+
+	// Runs for another rig don't count as prior history
+	rigRun := historyRun(base.Add(time.Hour), map[string]CheckStatus{"err-to-err": StatusError})
+	rigRun.Rig = "gastown"
+	if got := Regressions(history, rigRun); len(got) != 1 {
+		t.Errorf("Regressions(rig run) = %+v, want the first failure on the rig", got)
+	}
+}
+
+func TestNewRunRecordFixes(t *testing.T) {
+	report := &Report{Timestamp: time.Now()}
+	report.Checks = []*CheckResult{
+		{Name: "fixed", Status: StatusOK, Message: "ok", Fixed: true, FixAttempted: true, Problem: "broken", Details: []string{"verbose"}},
+		{Name: "fix-failed", Status: StatusError, Message: "broken", FixAttempted: true, Problem: "broken", FixError: "permission denied"},
+		{Name: "untouched", Status: StatusWarning, Message: "meh", Details: []string{"why"}},
+	}
+	run := NewRunRecord(report, SourceCLI, "", false, true)
+
+	if run.Checks[0].Details != nil {
+		t.Error("details of a passing check should not be recorded")
+	}
+	if run.Checks[2].Fix != nil || len(run.Checks[2].Details) != 1 {
+		t.Errorf("untouched = %+v", run.Checks[2])
+	}
+
+	fixes := Fixes([]*RunRecord{run})
+	if len(fixes) != 2 {
+		t.Fatalf("Fixes = %+v, want 2", fixes)
+	}
+	if !fixes[0].Fixed || fixes[0].Problem != "broken" || fixes[0].Check != "fixed" {
+		t.Errorf("fixes[0] = %+v", fixes[0])
+	}
+	if fixes[1].Fixed || fixes[1].Error != "permission denied" {
+		t.Errorf("fixes[1] = %+v", fixes[1])
+	}
+}
+
+func TestTrimHistory(t *testing.T) {
+	town := t.TempDir()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		if err := AppendHistory(town, historyRun(base.Add(time.Duration(i)*time.Hour), map[string]CheckStatus{"c": StatusOK})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := trimHistory(HistoryPath(town)); err != nil {
+		t.Fatalf("trimHistory: %v", err)
+	}
+	runs, _ := LoadHistory(town, time.Time{})
+	if len(runs) != 2 || !runs[0].Timestamp.Equal(base.Add(2*time.Hour)) {
+		t.Errorf("after trim = %d runs starting %v, want the newest 2", len(runs), runs[0].Timestamp)
+	}
+	if _, err := os.Stat(HistoryPath(town) + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temp file left behind")
+	}
+}
@@ -87,6 +87,11 @@ type CheckResult struct {
 	Category string        // Category for grouping (e.g., CategoryCore)
 	Elapsed  time.Duration // How long the check took to run
 	Fixed    bool          // True if this check was auto-fixed
+
+	// Fix attempts (--fix), kept for the history audit trail
+	FixAttempted bool   // True if a fix was tried
+	FixError     string // Why the fix failed, if it did
+	Problem      string // The check's message before the fix
 }
 
 // Check defines the interface for a health check.
@@ -0,0 +1,312 @@
+package doltserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time travel over beads databases. Every bd write is a Dolt commit, so the
+// dolt_diff_issues system table holds each change to each bead, and
+// "AS OF" reads a table as it was at any past moment. These are read-only
+// queries for post-incident review (gt history).
+
+// Revision is one change to a bead: a Dolt commit that added, modified or
+// removed its row.
+type Revision struct {
+	Bead      string        `json:"bead"`
+	At        time.Time     `json:"at"`
+	Commit    string        `json:"commit"`
+	Committer string        `json:"committer,omitempty"`
+	Message   string        `json:"message,omitempty"`
+	Kind      string        `json:"kind"` // added, modified or removed
+	Fields    []FieldChange `json:"fields,omitempty"`
+}
+
+// FieldChange is one column of a bead changing in a revision.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// Snapshot is what a rig's work looked like at a past moment.
+type Snapshot struct {
+	At      time.Time       `json:"at"`
+	Rig     string          `json:"rig"`
+	Ready   []SnapshotIssue `json:"ready"`   // Open and unblocked
+	Hooked  []SnapshotIssue `json:"hooked"`  // On an agent's hook
+	Convoys []SnapshotIssue `json:"convoys"` // Open convoys (town database)
+}
+
+// SnapshotIssue is a bead as it was in a snapshot.
+type SnapshotIssue struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Priority int    `json:"priority"`
+	Assignee string `json:"assignee,omitempty"`
+	Tracked  int    `json:"tracked,omitempty"` // Beads a convoy tracks
+}
+
+// TownDatabase is the database holding town-level (hq-*) beads and convoys.
+const TownDatabase = "hq"
+
+// historyIgnored are columns left out of revisions: they change on every
+// write and would bury the changes that matter.
+var historyIgnored = map[string]bool{
+	"updated_at":   true,
+	"content_hash": true,
+}
+
+// validDatabaseRe matches rig database names, which are interpolated as
+// quoted identifiers.
+var validDatabaseRe = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// historyQuery runs a query and returns its rows (swapped in tests).
+var historyQuery = queryRows
+
+// BeadHistory returns every change to bead in the rig database rigDB,
+// oldest first.
+func BeadHistory(townRoot, rigDB, bead string) ([]Revision, error) {
+	query, err := beadHistoryQuery(rigDB, bead)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := historyQuery(townRoot, query)
+	if err != nil {
+		return nil, fmt.Errorf("reading history of %s: %w", bead, err)
+	}
+	return revisionsFromDiff(rows), nil
+}
+
+// HistoryBetween returns every bead change in rigDB committed after from
+// and at or before to, oldest first.
+func HistoryBetween(townRoot, rigDB string, from, to time.Time) ([]Revision, error) {
+	query, err := historyBetweenQuery(rigDB, from, to)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := historyQuery(townRoot, query)
+	if err != nil {
+		return nil, fmt.Errorf("reading history of %s: %w", rigDB, err)
+	}
+	return revisionsFromDiff(rows), nil
+}
+
+// SnapshotAt returns the ready queue and hooked work of rigDB, and the open
+// convoys of the town, as they were at the given moment.
+func SnapshotAt(townRoot, rigDB string, at time.Time) (*Snapshot, error) {
+	queries, err := snapshotQueries(rigDB, at)
+	if err != nil {
+		return nil, err
+	}
+	snap := &Snapshot{At: at, Rig: rigDB}
+	for _, q := range []struct {
+		name  string
+		query string
+		dst   *[]SnapshotIssue
+	}{
+		{"ready queue", queries.ready, &snap.Ready},
+		{"hooked work", queries.hooked, &snap.Hooked},
+		{"convoys", queries.convoys, &snap.Convoys},
+	} {
+		rows, err := historyQuery(townRoot, q.query)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s as of %s: %w", q.name, at.Format(time.RFC3339), err)
+		}
+		*q.dst = snapshotIssues(rows)
+	}
+	return snap, nil
+}
+
+// diffSelect joins each bead change with the commit that made it.
+const diffSelect = "SELECT d.*, l.committer AS log_committer, l.message AS log_message " +
+	"FROM %[1]s.`dolt_diff_issues` d LEFT JOIN %[1]s.`dolt_log` l ON l.commit_hash = d.to_commit "
+
+func beadHistoryQuery(rigDB, bead string) (string, error) {
+	db, err := quoteDatabase(rigDB)
+	if err != nil {
+		return "", err
+	}
+	id := sqlString(bead)
+	return fmt.Sprintf(diffSelect+"WHERE d.to_id = %[2]s OR d.from_id = %[2]s ORDER BY d.to_commit_date", db, id), nil
+}
+
+func historyBetweenQuery(rigDB string, from, to time.Time) (string, error) {
+	db, err := quoteDatabase(rigDB)
+	if err != nil {
+		return "", err
+	}
+	if !to.After(from) {
+		return "", fmt.Errorf("history range ends before it starts")
+	}
+	return fmt.Sprintf(diffSelect+"WHERE d.to_commit_date > %[2]s AND d.to_commit_date <= %[3]s ORDER BY d.to_commit_date",
+		db, sqlTime(from), sqlTime(to)), nil
+}
+
+type snapshotSQL struct {
+	ready, hooked, convoys string
+}
+
+// snapshotQueries builds the as-of queries. Ready mirrors bd ready: open
+// beads with no open blocker.
+func snapshotQueries(rigDB string, at time.Time) (snapshotSQL, error) {
+	db, err := quoteDatabase(rigDB)
+	if err != nil {
+		return snapshotSQL{}, err
+	}
+	hq, _ := quoteDatabase(TownDatabase)
+	asOf := "AS OF TIMESTAMP(" + sqlTime(at) + ")"
+	cols := "i.id, i.title, i.status, i.priority, i.assignee"
+	return snapshotSQL{
+		ready: fmt.Sprintf("SELECT %[3]s FROM %[1]s.`issues` %[2]s i WHERE i.status = 'open' AND NOT EXISTS ("+
+			"SELECT 1 FROM %[1]s.`dependencies` %[2]s d JOIN %[1]s.`issues` %[2]s b ON b.id = d.depends_on_id "+
+			"WHERE d.issue_id = i.id AND d.type = 'blocks' AND b.status <> 'closed') "+
+			"ORDER BY i.priority, i.id", db, asOf, cols),
+		hooked: fmt.Sprintf("SELECT %[3]s FROM %[1]s.`issues` %[2]s i WHERE i.status = 'hooked' ORDER BY i.id",
+			db, asOf, cols),
+		convoys: fmt.Sprintf("SELECT %[3]s, COUNT(d.depends_on_id) AS tracked FROM %[1]s.`issues` %[2]s i "+
+			"LEFT JOIN %[1]s.`dependencies` %[2]s d ON d.issue_id = i.id AND d.type = 'tracks' "+
+			"WHERE i.issue_type = 'convoy' AND i.status <> 'closed' "+
+			"GROUP BY i.id, i.title, i.status, i.priority, i.assignee ORDER BY i.id", hq, asOf, cols),
+	}, nil
+}
+
+// revisionsFromDiff turns dolt_diff_issues rows into revisions. A diff row
+// holds the bead before (from_*) and after (to_*) the commit; the fields
+// that differ are the change.
+func revisionsFromDiff(rows []map[string]any) []Revision {
+	var revs []Revision
+	for _, row := range rows {
+		r := Revision{
+			Bead:      sqlValue(row["to_id"]),
+			Commit:    sqlValue(row["to_commit"]),
+			Committer: sqlValue(row["log_committer"]),
+			Message:   sqlValue(row["log_message"]),
+			Kind:      sqlValue(row["diff_type"]),
+		}
+		if r.Bead == "" {
+			r.Bead = sqlValue(row["from_id"])
+		}
+		if t, ok := parseSQLTime(sqlValue(row["to_commit_date"])); ok {
+			r.At = t
+		}
+		for col, v := range row {
+			field, ok := strings.CutPrefix(col, "to_")
+			if !ok || field == "commit" || field == "commit_date" || historyIgnored[field] {
+				continue
+			}
+			old, now := sqlValue(row["from_"+field]), sqlValue(v)
+			if old != now {
+				r.Fields = append(r.Fields, FieldChange{Field: field, Old: old, New: now})
+			}
+		}
+		sort.Slice(r.Fields, func(i, j int) bool { return r.Fields[i].Field < r.Fields[j].Field })
+		if r.Kind == "modified" && len(r.Fields) == 0 {
+			continue // Only ignored columns changed
+		}
+		revs = append(revs, r)
+	}
+	sort.SliceStable(revs, func(i, j int) bool { return revs[i].At.Before(revs[j].At) })
+	return revs
+}
+
+func snapshotIssues(rows []map[string]any) []SnapshotIssue {
+	issues := make([]SnapshotIssue, 0, len(rows))
+	for _, row := range rows {
+		priority, _ := strconv.Atoi(sqlValue(row["priority"]))
+		tracked, _ := strconv.Atoi(sqlValue(row["tracked"]))
+		issues = append(issues, SnapshotIssue{
+			ID:       sqlValue(row["id"]),
+			Title:    sqlValue(row["title"]),
+			Status:   sqlValue(row["status"]),
+			Priority: priority,
+			Assignee: sqlValue(row["assignee"]),
+			Tracked:  tracked,
+		})
+	}
+	return issues
+}
+
+// queryRows runs a read-only query with the dolt CLI from the data
+// directory and decodes its JSON result rows.
+func queryRows(townRoot, query string) ([]map[string]any, error) {
+	config := DefaultConfig(townRoot)
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "dolt", "sql", "-r", "json", "-q", query)
+	cmd.Dir = config.DataDir
+	output, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(ee.Stderr)))
+		}
+		return nil, err
+	}
+	return decodeRows(output)
+}
+
+// decodeRows parses `dolt sql -r json` output. A query with no rows prints
+// nothing.
+func decodeRows(output []byte) ([]map[string]any, error) {
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return nil, nil
+	}
+	var result struct {
+		Rows []map[string]any `json:"rows"`
+	}
+	dec := json.NewDecoder(strings.NewReader(string(output)))
+	dec.UseNumber()
+	if err := dec.Decode(&result); err != nil {
+		return nil, fmt.Errorf("parsing query result: %w", err)
+	}
+	return result.Rows, nil
+}
+
+// quoteDatabase returns rigDB as a quoted identifier.
+func quoteDatabase(rigDB string) (string, error) {
+	if !validDatabaseRe.MatchString(rigDB) {
+		return "", fmt.Errorf("invalid database name %q", rigDB)
+	}
+	return "`" + rigDB + "`", nil
+}
+
+// sqlString returns s as a SQL string literal.
+func sqlString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `''`).Replace(s) + "'"
+}
+
+// sqlTime returns t as a SQL datetime literal. Dolt commit dates are UTC.
+func sqlTime(t time.Time) string {
+	return "'" + t.UTC().Format("2006-01-02 15:04:05.000000") + "'"
+}
+
+// parseSQLTime parses a datetime from a query result.
+func parseSQLTime(s string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02 15:04:05.999999999", time.RFC3339Nano} {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// sqlValue renders a result value as text; NULL is empty.
+func sqlValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
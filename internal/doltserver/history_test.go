@@ -0,0 +1,114 @@
+package doltserver
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRevisionsFromDiff(t *testing.T) {
+	out := []byte(`{"rows":[
+{"to_id":"gt-1","to_status":"hooked","to_assignee":"gastown/polecats/Toast","to_updated_at":"2026-06-01 10:00:00","to_commit":"c2","to_commit_date":"2026-06-01 10:00:00.5",
+ "from_id":"gt-1","from_status":"open","from_assignee":null,"from_updated_at":"2026-06-01 09:00:00","from_commit":"c1","from_commit_date":"2026-06-01 09:00:00",
+ "diff_type":"modified","log_committer":"mayor","log_message":"bd: update gt-1"},
+{"to_id":"gt-1","to_status":"open","to_assignee":null,"to_priority":2,"to_updated_at":"2026-06-01 09:00:00","to_commit":"c1","to_commit_date":"2026-06-01 09:00:00",
+ "from_id":null,"from_status":null,"from_assignee":null,"from_priority":null,"from_updated_at":null,
+ "diff_type":"added"},
+{"to_id":"gt-1","to_status":"hooked","to_updated_at":"2026-06-01 11:00:00","to_commit":"c3","to_commit_date":"2026-06-01 11:00:00",
+ "from_id":"gt-1","from_status":"hooked","from_updated_at":"2026-06-01 10:00:00",
+ "diff_type":"modified"}
+]}`)
+	rows, err := decodeRows(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revs := revisionsFromDiff(rows)
+	if len(revs) != 2 {
+		t.Fatalf("got %d revisions, want 2 (touch-only change dropped): %+v", len(revs), revs)
+	}
+
+	added := revs[0]
+	if added.Kind != "added" || added.Commit != "c1" || added.Bead != "gt-1" {
+		t.Errorf("first revision = %+v", added)
+	}
+	if len(added.Fields) != 3 || added.Fields[0] != (FieldChange{Field: "id", New: "gt-1"}) ||
+		added.Fields[1] != (FieldChange{Field: "priority", New: "2"}) {
+		t.Errorf("added fields = %+v", added.Fields)
+	}
+
+	mod := revs[1]
+	want := []FieldChange{
+		{Field: "assignee", New: "gastown/polecats/Toast"},
+		{Field: "status", Old: "open", New: "hooked"},
+	}
+	if len(mod.Fields) != 2 || mod.Fields[0] != want[0] || mod.Fields[1] != want[1] {
+		t.Errorf("modified fields = %+v, want %+v", mod.Fields, want)
+	}
+	if mod.Committer != "mayor" || !mod.At.Equal(time.Date(2026, 6, 1, 10, 0, 0, 5e8, time.UTC)) {
+		t.Errorf("modified revision = %+v", mod)
+	}
+}
+
+func TestHistoryQueries(t *testing.T) {
+	q, err := beadHistoryQuery("gastown", "gt-1'; DROP TABLE issues; --")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(q, "`gastown`.`dolt_diff_issues`") || !strings.Contains(q, "'gt-1''; DROP TABLE issues; --'") {
+		t.Errorf("bead history query = %s", q)
+	}
+
+	if _, err := beadHistoryQuery("gastown`; DROP", "gt-1"); err == nil {
+		t.Error("unsafe database name accepted")
+	}
+
+	from := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	to := from.Add(2 * time.Hour)
+	q, err = historyBetweenQuery("hq", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(q, "d.to_commit_date > '2026-06-01 09:00:00.000000' AND d.to_commit_date <= '2026-06-01 11:00:00.000000'") {
+		t.Errorf("range query = %s", q)
+	}
+	if _, err := historyBetweenQuery("hq", to, from); err == nil {
+		t.Error("reversed range accepted")
+	}
+
+	sql, err := snapshotQueries("gastown", from.In(time.FixedZone("PDT", -7*3600)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sql.ready, "`gastown`.`issues` AS OF TIMESTAMP('2026-06-01 09:00:00.000000') i") ||
+		!strings.Contains(sql.ready, "`gastown`.`dependencies` AS OF") {
+		t.Errorf("ready query = %s", sql.ready)
+	}
+	if !strings.Contains(sql.convoys, "`hq`.`issues` AS OF") || !strings.Contains(sql.convoys, "d.type = 'tracks'") {
+		t.Errorf("convoys query = %s", sql.convoys)
+	}
+}
+
+func TestSnapshotAt(t *testing.T) {
+	old := historyQuery
+	t.Cleanup(func() { historyQuery = old })
+	historyQuery = func(_, query string) ([]map[string]any, error) {
+		switch {
+		case strings.Contains(query, "'convoy'"):
+			return decodeRows([]byte(`{"rows":[{"id":"hq-cv-1","title":"Auth","status":"open","priority":1,"assignee":null,"tracked":3}]}`))
+		case strings.Contains(query, "'hooked'"):
+			return decodeRows([]byte(`{"rows":[{"id":"gt-2","title":"Fix","status":"hooked","priority":0,"assignee":"gastown/polecats/Nux"}]}`))
+		default:
+			return decodeRows(nil)
+		}
+	}
+	snap, err := SnapshotAt("/town", "gastown", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Ready) != 0 || len(snap.Hooked) != 1 || snap.Hooked[0].Assignee != "gastown/polecats/Nux" {
+		t.Errorf("snapshot work = %+v", snap)
+	}
+	if len(snap.Convoys) != 1 || snap.Convoys[0].Tracked != 3 || snap.Convoys[0].Priority != 1 {
+		t.Errorf("snapshot convoys = %+v", snap.Convoys)
+	}
+}
@@ -23,6 +23,7 @@ type Event struct {
 	Actor      string                 `json:"actor"`
 	Payload    map[string]interface{} `json:"payload,omitempty"`
 	Visibility string                 `json:"visibility"`
+	Version    int                    `json:"v,omitempty"` // Payload schema version (registered types)
 }
 
 // Visibility levels for events.
@@ -55,9 +56,9 @@ const (
 	TypeMassDeath    = "mass_death"    // Multiple sessions died in short window
 
 	// Witness patrol events
-	TypePatrolStarted   = "patrol_started"
-	TypePolecatChecked  = "polecat_checked"
-	TypePolecatNudged   = "polecat_nudged"
+	TypePatrolStarted    = "patrol_started"
+	TypePolecatChecked   = "polecat_checked"
+	TypePolecatNudged    = "polecat_nudged"
 	TypeEscalationSent   = "escalation_sent"
 	TypeEscalationAcked  = "escalation_acked"
 	TypeEscalationClosed = "escalation_closed"
@@ -68,6 +69,9 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Mail request/reply events
+	TypeMailReplyTimeout = "mail_reply_timeout" // A request got no reply before its deadline
 )
 
 // EventsFile is the name of the raw events log.
@@ -75,8 +79,12 @@ const EventsFile = ".events.jsonl"
 
 // Log writes an event to the events log.
 // The event is appended to ~/gt/.events.jsonl.
-// Returns nil if logging fails (events are best-effort).
+// Returns nil if logging fails (events are best-effort), but a payload that
+// doesn't match its type's schema is rejected with an error.
 func Log(eventType, actor string, payload map[string]interface{}, visibility string) error {
+	if err := Validate(eventType, payload); err != nil {
+		return err
+	}
 	event := Event{
 		Timestamp:  time.Now().UTC().Format(time.RFC3339),
 		Source:     "gt",
@@ -85,6 +93,9 @@ func Log(eventType, actor string, payload map[string]interface{}, visibility str
 		Payload:    payload,
 		Visibility: visibility,
 	}
+	if s, ok := schemas[eventType]; ok {
+		event.Version = s.Version
+	}
 	return write(event)
 }
 
@@ -182,6 +193,20 @@ func MailPayload(to, subject string) map[string]interface{} {
 	}
 }
 
+// ReplyTimeoutPayload creates a payload for mail reply timeout events.
+// correlationID: correlation ID of the unanswered request
+// to: address the request was sent to
+// subject: subject of the request
+// deadline: when the reply was due
+func ReplyTimeoutPayload(correlationID, to, subject string, deadline time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"correlation_id": correlationID,
+		"to":             to,
+		"subject":        subject,
+		"deadline":       deadline.UTC().Format(time.RFC3339),
+	}
+}
+
 // SpawnPayload creates a payload for spawn events.
 func SpawnPayload(rig, polecat string) map[string]interface{} {
 	return map[string]interface{}{
@@ -0,0 +1,359 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// seekSlack allows for events written slightly out of order: the timestamp
+// is taken before the writer gets the file lock, so concurrent writers can
+// append a little out of sequence.
+const seekSlack = 5 * time.Minute
+
+// followInterval is how often Follow polls the log for new events.
+const followInterval = 500 * time.Millisecond
+
+// Filter selects events. Zero fields match everything.
+type Filter struct {
+	Types []string  // Event types (any of)
+	Actor string    // Actor: exact, a prefix ending in "/", or a glob ("gastown/*")
+	Rig   string    // Rig the event is about (see Event.Rig)
+	Since time.Time // At or after
+	Until time.Time // Before
+
+	// Where maps field paths (as in Project) to required values,
+	// compared as strings: {"payload.bead": "gt-abc"}.
+	Where map[string]string
+}
+
+// Match reports whether e passes the filter.
+func (f *Filter) Match(e Event) bool {
+	if len(f.Types) > 0 && !contains(f.Types, e.Type) {
+		return false
+	}
+	if f.Actor != "" && !matchActor(f.Actor, e.Actor) {
+		return false
+	}
+	if f.Rig != "" && e.Rig() != f.Rig {
+		return false
+	}
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		ts, ok := e.Time()
+		if !ok || (!f.Since.IsZero() && ts.Before(f.Since)) || (!f.Until.IsZero() && !ts.Before(f.Until)) {
+			return false
+		}
+	}
+	for p, want := range f.Where {
+		v, ok := e.Field(p)
+		if !ok || FormatField(v) != want {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// matchActor matches an actor pattern. A trailing "/*" matches everything
+// under the prefix ("gastown/*" matches "gastown/polecats/Toast"); other
+// globs match path segments as in path.Match.
+func matchActor(pattern, actor string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && !strings.ContainsAny(prefix, "*?[") {
+		return strings.HasPrefix(actor, prefix+"/")
+	}
+	if strings.ContainsAny(pattern, "*?[") {
+		ok, _ := path.Match(pattern, actor)
+		return ok
+	}
+	if strings.HasSuffix(pattern, "/") {
+		return strings.HasPrefix(actor, pattern)
+	}
+	return actor == pattern
+}
+
+// Time returns the event's timestamp.
+func (e Event) Time() (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, e.Timestamp)
+	return t, err == nil
+}
+
+// Rig returns the rig an event is about: the payload's rig, or else the
+// rig of a rig-level actor ("gastown/witness" → "gastown").
+func (e Event) Rig() string {
+	// escalation_sent historically carries the escalation ID in "rig"
+	if e.Type != TypeEscalationSent {
+		if rig, _ := e.Payload["rig"].(string); rig != "" && rig != "town" {
+			return rig
+		}
+	}
+	first, _, found := strings.Cut(e.Actor, "/")
+	if !found || first == "mayor" || first == "deacon" {
+		return ""
+	}
+	return first
+}
+
+// Field returns the value at a field path: "type", "payload.bead",
+// ".payload.agents[0]". A leading dot is optional, as in jq.
+func (e Event) Field(fieldPath string) (interface{}, bool) {
+	var root interface{} = e.asMap()
+	p := strings.TrimPrefix(fieldPath, ".")
+	if p == "" {
+		return root, true
+	}
+	cur := root
+	for _, part := range strings.Split(p, ".") {
+		name, indexes, err := splitIndexes(part)
+		if err != nil {
+			return nil, false
+		}
+		if name != "" {
+			m, ok := cur.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if cur, ok = m[name]; !ok {
+				return nil, false
+			}
+		}
+		for _, i := range indexes {
+			list, ok := cur.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			if i < 0 {
+				i += len(list)
+			}
+			if i < 0 || i >= len(list) {
+				return nil, false
+			}
+			cur = list[i]
+		}
+	}
+	return cur, true
+}
+
+// splitIndexes splits "agents[0][1]" into "agents" and [0, 1].
+func splitIndexes(part string) (string, []int, error) {
+	name, rest, _ := strings.Cut(part, "[")
+	if rest == "" {
+		return name, nil, nil
+	}
+	var indexes []int
+	for _, seg := range strings.Split("["+rest, "[")[1:] {
+		n, err := strconv.Atoi(strings.TrimSuffix(seg, "]"))
+		if err != nil || !strings.HasSuffix(seg, "]") {
+			return "", nil, fmt.Errorf("invalid index in %q", part)
+		}
+		indexes = append(indexes, n)
+	}
+	return name, indexes, nil
+}
+
+// asMap returns the event as generic JSON values, the form fields are
+// projected from.
+func (e Event) asMap() map[string]interface{} {
+	var m map[string]interface{}
+	data, _ := json.Marshal(e)
+	_ = json.Unmarshal(data, &m)
+	return m
+}
+
+// Project returns the values at paths; missing fields are nil.
+func (e Event) Project(paths []string) []interface{} {
+	values := make([]interface{}, len(paths))
+	for i, p := range paths {
+		values[i], _ = e.Field(p)
+	}
+	return values
+}
+
+// FormatField renders a field value as text: strings bare, anything else
+// as JSON.
+func FormatField(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// Query calls fn for each event in the log at path that matches f, oldest
+// first. With f.Since set it binary-searches the log by timestamp rather
+// than scanning it from the start, and with f.Until set it stops once past
+// it. fn may return io.EOF to stop early. A missing log has no events.
+func Query(logPath string, f Filter, fn func(Event) error) error {
+	_, err := query(logPath, 0, f, fn)
+	return err
+}
+
+// query is Query starting no earlier than offset; it returns the offset
+// after the last complete line read.
+func query(logPath string, offset int64, f Filter, fn func(Event) error) (int64, error) {
+	file, err := os.Open(logPath) //nolint:gosec // G304: path is the town's event log
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("opening event log: %w", err)
+	}
+	defer file.Close()
+
+	if !f.Since.IsZero() {
+		start, err := SeekTime(file, f.Since.Add(-seekSlack))
+		if err != nil {
+			return 0, err
+		}
+		if start > offset {
+			offset = start
+		}
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	r := bufio.NewReaderSize(file, 64*1024)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return offset, nil // A partial last line is left for the next read
+		}
+		if err != nil {
+			return offset, err
+		}
+		offset += int64(len(line))
+
+		var e Event
+		if json.Unmarshal(line, &e) != nil {
+			continue
+		}
+		if !f.Until.IsZero() {
+			if ts, ok := e.Time(); ok && ts.After(f.Until.Add(seekSlack)) {
+				return offset, nil
+			}
+		}
+		if !f.Match(e) {
+			continue
+		}
+		if err := fn(e); err == io.EOF {
+			return offset, nil
+		} else if err != nil {
+			return offset, err
+		}
+	}
+}
+
+// SeekTime returns the start of a line at or before the first event at or
+// after t, by binary search on line timestamps. The event log
+// is appended in time order, so this avoids reading it from the start.
+func SeekTime(file *os.File, t time.Time) (int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	lo, hi := int64(0), info.Size()
+	const minSpan = 16 * 1024 // Below this, scanning beats seeking
+	for hi-lo > minSpan {
+		mid := lo + (hi-lo)/2
+		lineStart, ts, err := lineTimeAfter(file, mid)
+		if err != nil {
+			return 0, err
+		}
+		if lineStart < 0 || !ts.Before(t) {
+			hi = mid
+		} else {
+			lo = lineStart
+		}
+	}
+	return lo, nil
+}
+
+// lineTimeAfter finds the first complete line starting after offset and
+// returns its start and timestamp, or -1 if there is none. Lines without a
+// parseable timestamp are skipped.
+func lineTimeAfter(file *os.File, offset int64) (int64, time.Time, error) {
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return -1, time.Time{}, err
+	}
+	r := bufio.NewReader(file)
+	skipped, err := r.ReadBytes('\n') // Rest of the line containing offset
+	if err != nil {
+		return -1, time.Time{}, nil
+	}
+	pos := offset + int64(len(skipped))
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return -1, time.Time{}, nil
+		}
+		var e struct {
+			Timestamp string `json:"ts"`
+		}
+		if json.Unmarshal(line, &e) == nil {
+			if ts, perr := time.Parse(time.RFC3339, e.Timestamp); perr == nil {
+				return pos, ts, nil
+			}
+		}
+		pos += int64(len(line))
+	}
+}
+
+// Follow calls fn for matching events as they are appended to the log,
+// after first replaying the existing ones that match (from f.Since, if
+// set). It returns when ctx is done or fn returns an error. If the log is
+// rewritten (krc prunes it in place), reading resumes after the last event
+// seen.
+func Follow(ctx context.Context, logPath string, f Filter, fn func(Event) error) error {
+	var offset int64
+	var size int64
+	var last time.Time
+	track := func(e Event) error {
+		if ts, ok := e.Time(); ok && ts.After(last) {
+			last = ts
+		}
+		return fn(e)
+	}
+	for {
+		if info, err := os.Stat(logPath); err == nil {
+			if info.Size() < size {
+				// Rewritten: resume by time instead of offset
+				offset = 0
+				if !last.IsZero() {
+					f.Since = last.Add(time.Second)
+				}
+			}
+			size = info.Size()
+		}
+		var err error
+		if offset, err = query(logPath, offset, f, track); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(followInterval):
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var queryBase = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// writeLog writes n events a minute apart, cycling through sling, done and
+// nudge, and returns the log path.
+func writeLog(t *testing.T, n int) string {
+	t.Helper()
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		ts := queryBase.Add(time.Duration(i) * time.Minute).Format(time.RFC3339)
+		switch i % 3 {
+		case 0:
+			fmt.Fprintf(&b, `{"ts":%q,"type":"sling","actor":"gastown/crew/joe","payload":{"bead":"gt-%d","target":"gastown/polecats/Toast"}}`+"\n", ts, i)
+		case 1:
+			fmt.Fprintf(&b, `{"ts":%q,"type":"done","actor":"gastown/polecats/Toast","payload":{"bead":"gt-%d"}}`+"\n", ts, i)
+		case 2:
+			fmt.Fprintf(&b, `{"ts":%q,"type":"nudge","actor":"mayor","payload":{"rig":"beads","target":"Nux"}}`+"\n", ts)
+		}
+	}
+	b.WriteString("not json\n")
+	path := filepath.Join(t.TempDir(), EventsFile)
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func collect(t *testing.T, path string, f Filter) []Event {
+	t.Helper()
+	var got []Event
+	if err := Query(path, f, func(e Event) error {
+		got = append(got, e)
+		return nil
+	}); err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	return got
+}
+
+func TestQueryFilters(t *testing.T) {
+	path := writeLog(t, 30)
+	tests := []struct {
+		name string
+		f    Filter
+		want int
+	}{
+		{"all", Filter{}, 30},
+		{"type", Filter{Types: []string{TypeSling, TypeNudge}}, 20},
+		{"actor exact", Filter{Actor: "mayor"}, 10},
+		{"actor prefix", Filter{Actor: "gastown/"}, 20},
+		{"actor glob", Filter{Actor: "gastown/*"}, 20},
+		{"actor segment glob", Filter{Actor: "gastown/*/Toast"}, 10},
+		{"rig from actor", Filter{Rig: "gastown"}, 20},
+		{"rig from payload", Filter{Rig: "beads"}, 10},
+		{"where", Filter{Where: map[string]string{".payload.bead": "gt-4"}}, 1},
+		{"time range", Filter{Since: queryBase.Add(10 * time.Minute), Until: queryBase.Add(20 * time.Minute)}, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := collect(t, path, tt.f); len(got) != tt.want {
+				t.Errorf("got %d events, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryStop(t *testing.T) {
+	path := writeLog(t, 30)
+	n := 0
+	err := Query(path, Filter{}, func(Event) error {
+		n++
+		if n == 5 {
+			return io.EOF
+		}
+		return nil
+	})
+	if err != nil || n != 5 {
+		t.Errorf("Query stopped after %d events, err %v; want 5, nil", n, err)
+	}
+}
+
+func TestQueryMissingLog(t *testing.T) {
+	if got := collect(t, filepath.Join(t.TempDir(), EventsFile), Filter{}); len(got) != 0 {
+		t.Errorf("missing log returned %d events", len(got))
+	}
+}
+
+func TestSeekTime(t *testing.T) {
+	path := writeLog(t, 5000) // Large enough to bisect
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	target := queryBase.Add(4000 * time.Minute)
+	off, err := SeekTime(f, target)
+	if err != nil {
+		t.Fatalf("SeekTime: %v", err)
+	}
+	if off == 0 {
+		t.Fatal("SeekTime did not seek")
+	}
+	if _, err := f.Seek(off, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	var e Event
+	if err := json.NewDecoder(f).Decode(&e); err != nil {
+		t.Fatalf("offset %d is not at a line start: %v", off, err)
+	}
+	ts, _ := e.Time()
+	if !ts.Before(target) || target.Sub(ts) > time.Hour {
+		t.Errorf("seeked to %v, want shortly before %v", ts, target)
+	}
+
+	got := collect(t, path, Filter{Since: target})
+	if len(got) != 1000 || got[0].Timestamp != target.Format(time.RFC3339) {
+		t.Errorf("Since query returned %d events starting %v", len(got), got[0].Timestamp)
+	}
+}
+
+func TestFieldAndProject(t *testing.T) {
+	e := Event{Timestamp: "2026-03-01T00:00:00Z", Type: TypeBoot, Actor: "gt",
+		Payload: map[string]interface{}{"rig": "town", "agents": []interface{}{"mayor", "deacon"}}}
+	got := e.Project([]string{".type", "payload.rig", ".payload.agents[1]", ".payload.agents[-1]", ".payload.agents", ".payload.nope", ".payload.agents[9]"})
+	want := []interface{}{"boot", "town", "deacon", "deacon", []interface{}{"mayor", "deacon"}, nil, nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Project = %#v, want %#v", got, want)
+	}
+	if s := FormatField(got[4]); s != `["mayor","deacon"]` {
+		t.Errorf("FormatField = %s", s)
+	}
+	if e.Rig() != "" {
+		t.Errorf("Rig() = %q, want empty for town-level boot", e.Rig())
+	}
+}
+
+func TestFollow(t *testing.T) {
+	path := writeLog(t, 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	got := make(chan string, 10)
+	done := make(chan error)
+	go func() {
+		done <- Follow(ctx, path, Filter{Types: []string{TypeSling}}, func(e Event) error {
+			got <- e.Payload["bead"].(string)
+			return nil
+		})
+	}()
+
+	expect := func(want string) {
+		t.Helper()
+		select {
+		case bead := <-got:
+			if bead != want {
+				t.Errorf("got %s, want %s", bead, want)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+	expect("gt-0")
+
+	appendLine := func(bead, ts string) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(f, `{"ts":%q,"type":"sling","actor":"a","payload":{"bead":%q,"target":"t"}}`+"\n", ts, bead)
+		f.Close()
+	}
+	appendLine("gt-new", "2026-03-02T00:00:00Z")
+	expect("gt-new")
+
+	// A prune rewrites the log smaller; only newer events are delivered
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * followInterval)
+	appendLine("gt-old", "2026-03-01T12:00:00Z")
+	appendLine("gt-newer", "2026-03-03T00:00:00Z")
+	expect("gt-newer")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Follow: %v", err)
+	}
+}
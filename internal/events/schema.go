@@ -0,0 +1,311 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Schema describes the payload of a registered event type. Payloads are
+// checked against it when logged: unknown fields and missing required
+// fields (tagged `required:"true"`) are rejected. Version is recorded in
+// each event's "v" field and bumped whenever the payload changes shape, so
+// readers can tell old events from new.
+type Schema struct {
+	Type    string
+	Version int
+	payload reflect.Type
+}
+
+// Fields returns the payload's JSON field names.
+func (s *Schema) Fields() []string {
+	var names []string
+	for i := 0; i < s.payload.NumField(); i++ {
+		if name := jsonName(s.payload.Field(i)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Required returns the JSON names of the fields a payload must set.
+func (s *Schema) Required() []string {
+	var names []string
+	for i := 0; i < s.payload.NumField(); i++ {
+		if f := s.payload.Field(i); f.Tag.Get("required") == "true" {
+			names = append(names, jsonName(f))
+		}
+	}
+	return names
+}
+
+var schemas = make(map[string]*Schema)
+
+// register adds the payload struct (passed as a zero value) for eventType.
+func register(eventType string, version int, payload interface{}) {
+	t := reflect.TypeOf(payload)
+	if t.Kind() != reflect.Struct {
+		panic("events: payload schema must be a struct: " + eventType)
+	}
+	schemas[eventType] = &Schema{Type: eventType, Version: version, payload: t}
+}
+
+// LookupSchema returns the schema of a registered event type.
+func LookupSchema(eventType string) (*Schema, bool) {
+	s, ok := schemas[eventType]
+	return s, ok
+}
+
+// Schemas returns the registered schemas sorted by type.
+func Schemas() []*Schema {
+	list := make([]*Schema, 0, len(schemas))
+	for _, s := range schemas {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Type < list[j].Type })
+	return list
+}
+
+// Validate checks a payload against its event type's schema. Unregistered
+// types (custom events from gt activity emit and webhooks) are not checked.
+func Validate(eventType string, payload map[string]interface{}) error {
+	s, ok := schemas[eventType]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("invalid %s payload: %w", eventType, err)
+	}
+	v := reflect.New(s.payload)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v.Interface()); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", eventType, err)
+	}
+	for i := 0; i < s.payload.NumField(); i++ {
+		f := s.payload.Field(i)
+		if f.Tag.Get("required") == "true" && v.Elem().Field(i).IsZero() {
+			name := jsonName(f)
+			return fmt.Errorf("invalid %s payload: missing %q", eventType, name)
+		}
+	}
+	return nil
+}
+
+func jsonName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name
+}
+
+// DecodePayload decodes an event's payload into T, the payload struct
+// registered for its type:
+//
+//	if p, err := events.DecodePayload[events.Sling](ev); err == nil { ... p.Bead ... }
+//
+// Fields missing from older events are left zero.
+func DecodePayload[T any](e Event) (T, error) {
+	var p T
+	if e.Payload == nil {
+		return p, nil
+	}
+	data, err := json.Marshal(e.Payload)
+	if err != nil {
+		return p, err
+	}
+	err = json.Unmarshal(data, &p)
+	return p, err
+}
+
+// Payload schemas of the built-in event types.
+
+// Sling is the payload of sling events.
+type Sling struct {
+	Bead    string `json:"bead" required:"true"`
+	Target  string `json:"target" required:"true"`
+	Formula string `json:"formula,omitempty"` // Set when a formula was slung
+}
+
+// Hook is the payload of hook and unhook events.
+type Hook struct {
+	Bead string `json:"bead" required:"true"`
+}
+
+// Handoff is the payload of handoff events.
+type Handoff struct {
+	ToSession bool   `json:"to_session"`
+	Subject   string `json:"subject,omitempty"`
+}
+
+// Done is the payload of done events.
+type Done struct {
+	Bead   string `json:"bead" required:"true"`
+	Branch string `json:"branch"`
+}
+
+// Mail is the payload of mail events.
+type Mail struct {
+	To      string `json:"to" required:"true"`
+	Subject string `json:"subject"`
+}
+
+// ReplyTimeout is the payload of mail_reply_timeout events.
+type ReplyTimeout struct {
+	CorrelationID string `json:"correlation_id" required:"true"`
+	To            string `json:"to"`
+	Subject       string `json:"subject"`
+	Deadline      string `json:"deadline"` // RFC3339
+}
+
+// Spawn is the payload of spawn events.
+type Spawn struct {
+	Rig     string `json:"rig" required:"true"`
+	Polecat string `json:"polecat" required:"true"`
+}
+
+// Boot is the payload of boot events.
+type Boot struct {
+	Rig    string   `json:"rig" required:"true"`
+	Agents []string `json:"agents"`
+}
+
+// Halt is the payload of halt events.
+type Halt struct {
+	Services []string `json:"services"`
+}
+
+// Kill is the payload of kill events.
+type Kill struct {
+	Rig    string `json:"rig"`
+	Target string `json:"target" required:"true"`
+	Reason string `json:"reason"`
+}
+
+// Nudge is the payload of nudge and polecat_nudged events.
+type Nudge struct {
+	Rig    string `json:"rig"`
+	Target string `json:"target" required:"true"`
+	Reason string `json:"reason"`
+}
+
+// Patrol is the payload of patrol_started and patrol_complete events.
+type Patrol struct {
+	Rig          string `json:"rig" required:"true"`
+	PolecatCount int    `json:"polecat_count"`
+	Message      string `json:"message,omitempty"`
+}
+
+// PolecatCheck is the payload of polecat_checked events.
+type PolecatCheck struct {
+	Rig     string `json:"rig" required:"true"`
+	Polecat string `json:"polecat" required:"true"`
+	Status  string `json:"status"`
+	Issue   string `json:"issue,omitempty"`
+}
+
+// Escalation is the payload of escalation_sent events. New escalations
+// set Rig to the escalation bead ID (historical); re-escalations set
+// EscalationID and the severity change instead.
+type Escalation struct {
+	Rig             string `json:"rig,omitempty"`
+	Target          string `json:"target,omitempty"`
+	To              string `json:"to,omitempty"`
+	Reason          string `json:"reason,omitempty"`
+	Severity        string `json:"severity,omitempty"`
+	Actions         string `json:"actions,omitempty"`
+	Source          string `json:"source,omitempty"`
+	EscalationID    string `json:"escalation_id,omitempty"`
+	Reescalated     bool   `json:"reescalated,omitempty"`
+	OldSeverity     string `json:"old_severity,omitempty"`
+	NewSeverity     string `json:"new_severity,omitempty"`
+	ReescalationNum int    `json:"reescalation_num,omitempty"`
+	Targets         string `json:"targets,omitempty"`
+}
+
+// EscalationAck is the payload of escalation_acked events.
+type EscalationAck struct {
+	EscalationID string `json:"escalation_id" required:"true"`
+	AckedBy      string `json:"acked_by"`
+}
+
+// EscalationClose is the payload of escalation_closed events.
+type EscalationClose struct {
+	EscalationID string `json:"escalation_id" required:"true"`
+	ClosedBy     string `json:"closed_by"`
+	Reason       string `json:"reason"`
+}
+
+// Merge is the payload of merge queue events.
+type Merge struct {
+	MR      string `json:"mr,omitempty"`
+	Worker  string `json:"worker,omitempty"`
+	Branch  string `json:"branch,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Rig     string `json:"rig,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// SessionDeath is the payload of session_death events.
+type SessionDeath struct {
+	Session string `json:"session" required:"true"`
+	Agent   string `json:"agent"`
+	Reason  string `json:"reason"`
+	Caller  string `json:"caller"`
+}
+
+// MassDeath is the payload of mass_death events.
+type MassDeath struct {
+	Count         int      `json:"count" required:"true"`
+	Window        string   `json:"window"`
+	Sessions      []string `json:"sessions"`
+	PossibleCause string   `json:"possible_cause,omitempty"`
+}
+
+// Session is the payload of session_start and session_end events.
+type Session struct {
+	SessionID string `json:"session_id"`
+	Role      string `json:"role"`
+	ActorPID  string `json:"actor_pid"`
+	Topic     string `json:"topic,omitempty"`
+	Cwd       string `json:"cwd,omitempty"`
+}
+
+func init() {
+	register(TypeSling, 1, Sling{})
+	register(TypeHook, 1, Hook{})
+	register(TypeUnhook, 1, Hook{})
+	register(TypeHandoff, 1, Handoff{})
+	register(TypeDone, 1, Done{})
+	register(TypeMail, 1, Mail{})
+	register(TypeMailReplyTimeout, 1, ReplyTimeout{})
+	register(TypeSpawn, 1, Spawn{})
+	register(TypeBoot, 1, Boot{})
+	register(TypeHalt, 1, Halt{})
+	register(TypeKill, 1, Kill{})
+	register(TypeNudge, 1, Nudge{})
+	register(TypePolecatNudged, 1, Nudge{})
+	register(TypePatrolStarted, 1, Patrol{})
+	register(TypePatrolComplete, 1, Patrol{})
+	register(TypePolecatChecked, 1, PolecatCheck{})
+	register(TypeEscalationSent, 1, Escalation{})
+	register(TypeEscalationAcked, 1, EscalationAck{})
+	register(TypeEscalationClosed, 1, EscalationClose{})
+	register(TypeMergeStarted, 1, Merge{})
+	register(TypeMerged, 1, Merge{})
+	register(TypeMergeFailed, 1, Merge{})
+	register(TypeMergeSkipped, 1, Merge{})
+	register(TypeSessionDeath, 1, SessionDeath{})
+	register(TypeMassDeath, 1, MassDeath{})
+	register(TypeSessionStart, 1, Session{})
+	register(TypeSessionEnd, 1, Session{})
+}
@@ -0,0 +1,121 @@
+package events
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPayloadHelpersMatchSchemas guards against helpers and schemas drifting
+// apart: an invalid payload is rejected at write time and the event is lost.
+func TestPayloadHelpersMatchSchemas(t *testing.T) {
+	sling := SlingPayload("gt-abc", "gastown/polecats/Toast")
+	sling["formula"] = "mol-polecat-work"
+	escalation := EscalationPayload("hq-esc1", "gastown/polecats/Toast", "mayor", "stuck")
+	escalation["severity"] = "high"
+	escalation["actions"] = "mail"
+	escalation["source"] = "witness"
+
+	tests := []struct {
+		eventType string
+		payload   map[string]interface{}
+	}{
+		{TypeSling, SlingPayload("gt-abc", "gastown/polecats/Toast")},
+		{TypeSling, sling},
+		{TypeHook, HookPayload("gt-abc")},
+		{TypeUnhook, UnhookPayload("gt-abc")},
+		{TypeHandoff, HandoffPayload("context full", true)},
+		{TypeDone, DonePayload("gt-abc", "polecat/Toast")},
+		{TypeMail, MailPayload("mayor/", "hello")},
+		{TypeMailReplyTimeout, ReplyTimeoutPayload("c-1", "mayor/", "question", time.Now())},
+		{TypeSpawn, SpawnPayload("gastown", "Toast")},
+		{TypeBoot, BootPayload("town", []string{"mayor", "deacon"})},
+		{TypeHalt, HaltPayload([]string{"daemon"})},
+		{TypeKill, KillPayload("gastown", "Toast", "zombie")},
+		{TypeNudge, NudgePayload("gastown", "Toast", "wake up")},
+		{TypePolecatNudged, NudgePayload("gastown", "Toast", "idle")},
+		{TypePatrolStarted, PatrolPayload("gastown", 3, "")},
+		{TypePatrolComplete, PatrolPayload("gastown", 3, "All polecats healthy")},
+		{TypePolecatChecked, PolecatCheckPayload("gastown", "Toast", "working", "gt-abc")},
+		{TypeEscalationSent, escalation},
+		{TypeEscalationSent, map[string]interface{}{
+			"escalation_id": "hq-esc1", "reescalated": true, "old_severity": "low",
+			"new_severity": "high", "reescalation_num": 2, "targets": "mayor",
+		}},
+		{TypeEscalationAcked, map[string]interface{}{"escalation_id": "hq-esc1", "acked_by": "mayor"}},
+		{TypeEscalationClosed, map[string]interface{}{"escalation_id": "hq-esc1", "closed_by": "mayor", "reason": "fixed"}},
+		{TypeMerged, MergePayload("mr-1", "Toast", "polecat/Toast", "")},
+		{TypeMergeFailed, MergePayload("mr-1", "Toast", "polecat/Toast", "conflict")},
+		{TypeSessionDeath, SessionDeathPayload("gt-gastown-Toast", "gastown/polecats/Toast", "zombie cleanup", "daemon")},
+		{TypeMassDeath, MassDeathPayload(4, "5s", []string{"a", "b", "c", "d"}, "tmux restart")},
+		{TypeSessionStart, SessionPayload("uuid", "gastown/crew/joe", "patrol", "/tmp")},
+		{TypeSessionEnd, SessionPayload("uuid", "deacon", "", "")},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.eventType, tt.payload); err != nil {
+			t.Errorf("Validate(%s, %v): %v", tt.eventType, tt.payload, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType string
+		payload   map[string]interface{}
+		wantErr   string
+	}{
+		{"missing required", TypeSling, map[string]interface{}{"bead": "gt-abc"}, `missing "target"`},
+		{"unknown field", TypeHook, map[string]interface{}{"bead": "gt-abc", "beed": "x"}, "unknown field"},
+		{"wrong type", TypeMassDeath, map[string]interface{}{"count": "four"}, "invalid mass_death payload"},
+		{"nil payload", TypeDone, nil, `missing "bead"`},
+		{"custom type", "deploy_finished", map[string]interface{}{"anything": 1}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.eventType, tt.payload)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLogRejectsInvalidPayload(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := LogFeed(TypeSling, "mayor", map[string]interface{}{"bead": "gt-abc"}); err == nil {
+		t.Error("LogFeed with invalid sling payload should fail")
+	}
+}
+
+func TestDecodePayload(t *testing.T) {
+	e := Event{Type: TypeMassDeath, Payload: map[string]interface{}{
+		"count": float64(3), "window": "5s", "sessions": []interface{}{"a", "b", "c"},
+	}}
+	p, err := DecodePayload[MassDeath](e)
+	if err != nil {
+		t.Fatalf("DecodePayload: %v", err)
+	}
+	if p.Count != 3 || p.Window != "5s" || len(p.Sessions) != 3 || p.PossibleCause != "" {
+		t.Errorf("DecodePayload = %+v", p)
+	}
+}
+
+func TestSchemaFields(t *testing.T) {
+	s, ok := LookupSchema(TypeSling)
+	if !ok {
+		t.Fatal("no sling schema")
+	}
+	if got := strings.Join(s.Fields(), ","); got != "bead,target,formula" {
+		t.Errorf("Fields = %s", got)
+	}
+	if got := strings.Join(s.Required(), ","); got != "bead,target" {
+		t.Errorf("Required = %s", got)
+	}
+}
@@ -0,0 +1,253 @@
+package federation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestParseURI(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want URI
+		ok   bool
+	}{
+		{"hop://steve@example.com/main-town/greenplace/gp-xyz", URI{"steve@example.com", "main-town", "greenplace", "gp-xyz"}, true},
+		{"hop://acme/hq-town/hq-abc", URI{Entity: "acme", Chain: "hq-town", Issue: "hq-abc"}, true},
+		{"hop://acme/town", URI{}, false},
+		{"hop://acme//rig/gp-1", URI{}, false},
+		{"hop://acme/town/../gp-1", URI{}, false},
+		{"gp-xyz", URI{}, false},
+	} {
+		got, err := ParseURI(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseURI(%q) = %+v, %v", tt.in, got, err)
+			continue
+		}
+		if tt.ok && got.String() != tt.in {
+			t.Errorf("String() = %q, want %q", got.String(), tt.in)
+		}
+	}
+
+	if e, c, err := ParseTown("hop://acme/main-town/"); err != nil || e != "acme" || c != "main-town" {
+		t.Errorf("ParseTown = %q, %q, %v", e, c, err)
+	}
+	if _, _, err := ParseTown("hop://acme/main-town/rig"); err == nil {
+		t.Error("ParseTown accepted a bead URI")
+	}
+}
+
+func TestRemotesRoundTrip(t *testing.T) {
+	town := t.TempDir()
+	rs, err := LoadRemotes(town)
+	if err != nil || len(rs.Remotes) != 0 {
+		t.Fatalf("LoadRemotes on empty town = %+v, %v", rs, err)
+	}
+	if err := rs.Add(&Remote{Name: "acme", Entity: "acme", Chain: "main", Source: "/srv/acme"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rs.Add(&Remote{Name: "other", Entity: "acme", Chain: "main"}); err == nil {
+		t.Error("registered the same town twice")
+	}
+	if err := rs.Save(town); err != nil {
+		t.Fatal(err)
+	}
+
+	rs, err = LoadRemotes(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := ParseURI("hop://acme/main/gastown/gt-1")
+	if r := rs.ForURI(u); r == nil || r.Source != "/srv/acme" {
+		t.Errorf("ForURI = %+v", r)
+	}
+	if !rs.Remove("acme") || rs.Remove("acme") {
+		t.Error("Remove did not report existence")
+	}
+}
+
+func TestSyncFromHTTPExport(t *testing.T) {
+	remote := beads.NewMemStore("gp")
+	open, _ := remote.Create(beads.CreateOptions{Title: "Open leg"})
+	done, _ := remote.Create(beads.CreateOptions{Title: "Done leg"})
+	if err := remote.Close(done.ID); err != nil {
+		t.Fatal(err)
+	}
+	exp, err := BuildExport("acme", "main", map[string]beads.Store{"greenplace": remote})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(exp)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer srv.Close()
+
+	town := t.TempDir()
+	rs := &Remotes{Version: 1}
+	_ = rs.Add(&Remote{Name: "acme", Entity: "acme", Chain: "main", Source: srv.URL})
+	if err := rs.Save(town); err != nil {
+		t.Fatal(err)
+	}
+
+	uris := URIs([]string{
+		"hop://acme/main/greenplace/" + open.ID,
+		"hop://acme/main/greenplace/" + done.ID,
+		"hop://acme/main/greenplace/gp-missing",
+		"hop://acme/main/otherrig/" + open.ID,
+		"hop://nobody/town/rig/x-1",
+	})
+	statuses, err := Sync(town, uris)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byIssue := make(map[string]*Status)
+	for i, u := range uris {
+		if u.Rig == "otherrig" {
+			if s := statuses[i]; s.Issue != nil || !strings.Contains(s.Error, "is in greenplace") {
+				t.Errorf("leg with the wrong rig = %+v", s)
+			}
+			continue
+		}
+		byIssue[u.Issue] = statuses[i]
+	}
+	if s := byIssue[open.ID]; s.Issue == nil || s.Issue.Title != "Open leg" || s.Closed() {
+		t.Errorf("open leg = %+v", s)
+	}
+	if s := byIssue[done.ID]; !s.Closed() {
+		t.Errorf("done leg = %+v", s)
+	}
+	if s := byIssue["gp-missing"]; s.Issue != nil || !strings.Contains(s.Error, "not found") {
+		t.Errorf("missing leg = %+v", s)
+	}
+	if s := byIssue["x-1"]; !strings.Contains(s.Error, "no remote") {
+		t.Errorf("unregistered leg = %+v", s)
+	}
+
+	// A failed sync keeps the last known state
+	srv.Close()
+	if _, err := Sync(town, uris); err != nil {
+		t.Fatal(err)
+	}
+	cache, err := LoadCache(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := cache.Get(uris[0]); s.Issue == nil || s.Error == "" {
+		t.Errorf("after failed sync = %+v", s)
+	}
+	if s := cache.Get(uris[1]); s.Issue == nil || s.Closed() {
+		t.Errorf("done leg after failed sync = %+v, want it counted as open", s)
+	}
+}
+
+func TestSyncFromTownOnDisk(t *testing.T) {
+	remote := beads.NewMemStore("gp")
+	leg, _ := remote.Create(beads.CreateOptions{Title: "Remote leg"})
+	orig := openTown
+	openTown = func(string) beads.Store { return remote }
+	defer func() { openTown = orig }()
+
+	other := t.TempDir()
+	writeTownConfig(t, other, "acme", "side")
+	if err := beads.AppendRoute(other, beads.Route{Prefix: "gp-", Path: "greenplace/mayor/rig"}); err != nil {
+		t.Fatal(err)
+	}
+	town := t.TempDir()
+	rs := &Remotes{Version: 1}
+	_ = rs.Add(&Remote{Name: "sibling", Entity: "acme", Chain: "side", Source: other})
+	if err := rs.Save(town); err != nil {
+		t.Fatal(err)
+	}
+
+	u, _ := ParseURI("hop://acme/side/greenplace/" + leg.ID)
+	statuses, err := Sync(town, []URI{u})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := statuses[0]; s.Remote != "sibling" || s.Issue == nil || s.Issue.Status != "open" {
+		t.Errorf("status = %+v", s)
+	}
+	if _, err := os.Stat(filepath.Join(town, "federation", "status.json")); err != nil {
+		t.Errorf("cache not written: %v", err)
+	}
+}
+
+func TestFetchRejectsMismatchedTown(t *testing.T) {
+	orig := openTown
+	openTown = func(string) beads.Store { return beads.NewMemStore("gp") }
+	defer func() { openTown = orig }()
+
+	other := t.TempDir()
+	writeTownConfig(t, other, "someone", "else")
+	r := &Remote{Name: "acme", Entity: "acme", Chain: "main", Source: other}
+	if _, err := r.Fetch([]string{"gp-1"}); err == nil || !strings.Contains(err.Error(), "not hop://acme/main") {
+		t.Errorf("Fetch from a different town = %v", err)
+	}
+}
+
+func writeTownConfig(t *testing.T, townRoot, owner, name string) {
+	t.Helper()
+	tc := &config.TownConfig{Type: "town", Version: config.CurrentTownVersion, Name: name, Owner: owner}
+	if err := config.SaveTownConfig(filepath.Join(townRoot, "mayor", "town.json"), tc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFetchRejectsMismatchedExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.json")
+	data, _ := json.Marshal(&Export{Entity: "someone", Chain: "else"})
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := &Remote{Name: "acme", Entity: "acme", Chain: "main", Source: path}
+	if _, err := r.Fetch([]string{"gp-1"}); err == nil {
+		t.Error("accepted an export from a different town")
+	}
+}
+
+func TestFetchRejectsOversizedExport(t *testing.T) {
+	orig := maxExport
+	maxExport = 16
+	defer func() { maxExport = orig }()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"entity":"acme","chain":"main","issues":[]}`))
+	}))
+	defer srv.Close()
+
+	r := &Remote{Name: "acme", Entity: "acme", Chain: "main", Source: srv.URL}
+	if _, err := r.Fetch([]string{"gp-1"}); err == nil || !strings.Contains(err.Error(), "export too large") {
+		t.Errorf("Fetch of an oversized export = %v", err)
+	}
+}
+
+func TestBuildExport(t *testing.T) {
+	store := beads.NewMemStore("gp")
+	var work []string
+	for i := 0; i < 60; i++ { // More than bd's default page
+		issue, _ := store.Create(beads.CreateOptions{Title: "Work"})
+		work = append(work, issue.ID)
+	}
+	_, _ = store.Create(beads.CreateOptions{Title: "Private mail", Labels: []string{"gt:message"}})
+	_, _ = store.Create(beads.CreateOptions{Title: "gp-greenplace-witness", Labels: []string{"gt:agent"}})
+
+	exp, err := BuildExport("acme", "main", map[string]beads.Store{"greenplace": store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exp.Issues) != len(work) {
+		t.Fatalf("exported %d issues, want the %d work issues", len(exp.Issues), len(work))
+	}
+	for _, issue := range exp.Issues {
+		if issue.Title != "Work" {
+			t.Errorf("exported bookkeeping bead %+v", issue)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package federation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Remote is a registered town that URIs can point into.
+type Remote struct {
+	Name   string `json:"name"`
+	Entity string `json:"entity"`
+	Chain  string `json:"chain"`
+	// Source is where the town is read: the root of a town on this machine,
+	// or the URL or path of a status export (gt remote export).
+	Source string `json:"source"`
+}
+
+// Town returns the remote's hop://entity/chain address.
+func (r *Remote) Town() string {
+	return Scheme + r.Entity + "/" + r.Chain
+}
+
+// Remotes is the town's registry of remotes, settings/remotes.json.
+type Remotes struct {
+	Version int       `json:"version"`
+	Remotes []*Remote `json:"remotes"`
+}
+
+// RemotesPath returns the town's remotes file.
+func RemotesPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "remotes.json")
+}
+
+// LoadRemotes loads the town's remotes. A missing file has none.
+func LoadRemotes(townRoot string) (*Remotes, error) {
+	data, err := os.ReadFile(RemotesPath(townRoot)) //nolint:gosec // G304: path is the town settings file
+	if os.IsNotExist(err) {
+		return &Remotes{Version: 1}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading remotes: %w", err)
+	}
+	var r Remotes
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing remotes: %w", err)
+	}
+	return &r, nil
+}
+
+// Save writes the remotes file.
+func (rs *Remotes) Save(townRoot string) error {
+	path := RemotesPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating settings directory: %w", err)
+	}
+	sort.Slice(rs.Remotes, func(i, j int) bool { return rs.Remotes[i].Name < rs.Remotes[j].Name })
+	data, err := json.MarshalIndent(rs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding remotes: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644) //nolint:gosec // G306: remotes are not secret
+}
+
+// Add registers a remote, replacing one with the same name.
+func (rs *Remotes) Add(r *Remote) error {
+	if r.Name == "" || strings.ContainsAny(r.Name, "/ \t") {
+		return fmt.Errorf("invalid remote name %q", r.Name)
+	}
+	for _, o := range rs.Remotes {
+		if o.Name != r.Name && o.Entity == r.Entity && o.Chain == r.Chain {
+			return fmt.Errorf("%s is already registered as %s", r.Town(), o.Name)
+		}
+	}
+	rs.Remove(r.Name)
+	rs.Remotes = append(rs.Remotes, r)
+	return nil
+}
+
+// Remove drops the named remote, reporting whether it existed.
+func (rs *Remotes) Remove(name string) bool {
+	for i, r := range rs.Remotes {
+		if r.Name == name {
+			rs.Remotes = append(rs.Remotes[:i], rs.Remotes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ForURI returns the remote serving u's town, or nil.
+func (rs *Remotes) ForURI(u URI) *Remote {
+	for _, r := range rs.Remotes {
+		if r.Entity == u.Entity && r.Chain == u.Chain {
+			return r
+		}
+	}
+	return nil
+}
+
+// RemoteIssue is a bead's state as read from its home town.
+type RemoteIssue struct {
+	ID        string `json:"id"`
+	Rig       string `json:"rig,omitempty"` // Empty for town-level beads
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	Assignee  string `json:"assignee,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+	ClosedAt  string `json:"closed_at,omitempty"`
+}
+
+// Export is the status snapshot a town publishes for its federation peers.
+type Export struct {
+	Entity     string        `json:"entity"`
+	Chain      string        `json:"chain"`
+	ExportedAt time.Time     `json:"exported_at"`
+	Issues     []RemoteIssue `json:"issues"`
+}
+
+// openTown returns the beads store of a town on this machine (swapped in
+// tests). bd routes each ID to its rig from the town root.
+var openTown = func(townRoot string) beads.Store {
+	return beads.New(townRoot)
+}
+
+// Fetch reads the current state of ids from the remote. IDs the remote
+// does not have are missing from the result.
+func (r *Remote) Fetch(ids []string) (map[string]RemoteIssue, error) {
+	if info, err := os.Stat(r.Source); err == nil && info.IsDir() {
+		entity, chain, err := Identity(r.Source)
+		if err != nil {
+			return nil, fmt.Errorf("reading identity of %s: %w", r.Source, err)
+		}
+		if entity != r.Entity || chain != r.Chain {
+			return nil, fmt.Errorf("%s is %s%s/%s, not %s", r.Source, Scheme, entity, chain, r.Town())
+		}
+		return fetchTown(r.Source, ids)
+	}
+	exp, err := r.readExport()
+	if err != nil {
+		return nil, err
+	}
+	if exp.Entity != r.Entity || exp.Chain != r.Chain {
+		return nil, fmt.Errorf("%s exports %s%s/%s, not %s", r.Source, Scheme, exp.Entity, exp.Chain, r.Town())
+	}
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	result := make(map[string]RemoteIssue)
+	for _, issue := range exp.Issues {
+		if want[issue.ID] {
+			result[issue.ID] = issue
+		}
+	}
+	return result, nil
+}
+
+// fetchTown reads ids from a town on this machine, one at a time so a
+// missing bead does not hide the others. Each bead's rig comes from the
+// town's routes.
+func fetchTown(townRoot string, ids []string) (map[string]RemoteIssue, error) {
+	store := openTown(townRoot)
+	result := make(map[string]RemoteIssue)
+	var lastErr error
+	for _, id := range ids {
+		issue, err := store.Show(id)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		result[id] = remoteIssue(issue, beads.GetRigNameForPrefix(townRoot, beads.ExtractPrefix(issue.ID)))
+	}
+	if len(result) == 0 && lastErr != nil {
+		return nil, fmt.Errorf("reading %s: %w", townRoot, lastErr)
+	}
+	return result, nil
+}
+
+func remoteIssue(issue *beads.Issue, rig string) RemoteIssue {
+	return RemoteIssue{
+		ID:        issue.ID,
+		Rig:       rig,
+		Title:     issue.Title,
+		Status:    issue.Status,
+		Assignee:  issue.Assignee,
+		UpdatedAt: issue.UpdatedAt,
+		ClosedAt:  issue.ClosedAt,
+	}
+}
+
+// maxExport bounds a downloaded export (a var for tests).
+var maxExport = 64 << 20
+
+// readExport reads the remote's export from a URL or file.
+func (r *Remote) readExport() (*Export, error) {
+	var data []byte
+	if strings.HasPrefix(r.Source, "http://") || strings.HasPrefix(r.Source, "https://") {
+		client := &http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Get(r.Source) //nolint:gosec // G107: source is a registered remote
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", r.Source, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetching %s: %s", r.Source, resp.Status)
+		}
+		if data, err = io.ReadAll(io.LimitReader(resp.Body, int64(maxExport)+1)); err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", r.Source, err)
+		}
+		if len(data) > maxExport {
+			return nil, fmt.Errorf("fetching %s: export too large (over %d bytes)", r.Source, maxExport)
+		}
+	} else {
+		var err error
+		if data, err = os.ReadFile(strings.TrimPrefix(r.Source, "file://")); err != nil { //nolint:gosec // G304: source is a registered remote
+			return nil, fmt.Errorf("reading export: %w", err)
+		}
+	}
+	var exp Export
+	if err := json.Unmarshal(data, &exp); err != nil {
+		return nil, fmt.Errorf("parsing export from %s: %w", r.Source, err)
+	}
+	return &exp, nil
+}
+
+// Identity returns this town's entity and chain from mayor/town.json: the
+// owner (falling back to the town name) and the town name.
+func Identity(townRoot string) (entity, chain string, err error) {
+	tc, err := config.LoadTownConfig(filepath.Join(townRoot, "mayor", "town.json"))
+	if err != nil {
+		return "", "", err
+	}
+	entity = tc.Owner
+	if entity == "" {
+		entity = tc.Name
+	}
+	return entity, tc.Name, nil
+}
+
+// unexportedLabels mark town bookkeeping beads that BuildExport leaves out:
+// mail with its channels, groups and queues, and the agent, role and rig
+// records. They say nothing about the town's work and may be private.
+var unexportedLabels = []string{"gt:message", "gt:agent", "gt:role", "gt:rig", "gt:channel", "gt:group", "gt:queue"}
+
+// exported reports whether issue belongs in a status export.
+func exported(issue *beads.Issue) bool {
+	if issue.Ephemeral {
+		return false
+	}
+	for _, label := range unexportedLabels {
+		if beads.HasLabel(issue, label) {
+			return false
+		}
+	}
+	return true
+}
+
+// BuildExport snapshots the work issues of the town and each rig in stores
+// (keyed by rig name; "" for town beads) for publishing. Wisps and
+// bookkeeping beads (unexportedLabels) are left out.
+func BuildExport(entity, chain string, stores map[string]beads.Store) (*Export, error) {
+	exp := &Export{Entity: entity, Chain: chain, ExportedAt: time.Now().UTC(), Issues: []RemoteIssue{}}
+	names := make([]string, 0, len(stores))
+	for name := range stores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	seen := make(map[string]bool)
+	for _, name := range names {
+		issues, err := stores[name].List(beads.ListOptions{Status: "all", Priority: -1, Limit: -1})
+		if err != nil {
+			return nil, fmt.Errorf("listing %s beads: %w", displayRig(name), err)
+		}
+		for _, issue := range issues {
+			if !exported(issue) || seen[issue.ID] {
+				continue
+			}
+			seen[issue.ID] = true
+			exp.Issues = append(exp.Issues, remoteIssue(issue, name))
+		}
+	}
+	sort.Slice(exp.Issues, func(i, j int) bool { return exp.Issues[i].ID < exp.Issues[j].ID })
+	return exp, nil
+}
+
+func displayRig(name string) string {
+	if name == "" {
+		return "town"
+	}
+	return name
+}
@@ -0,0 +1,165 @@
+package federation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Status is the last known state of a remote bead.
+type Status struct {
+	URI      string       `json:"uri"`
+	Remote   string       `json:"remote,omitempty"` // Remote it was read from
+	Issue    *RemoteIssue `json:"issue,omitempty"`  // Nil if never read
+	SyncedAt time.Time    `json:"synced_at"`
+	Error    string       `json:"error,omitempty"` // Why the last sync failed
+}
+
+// Closed reports whether the bead was closed as of the latest sync. A bead
+// whose latest sync failed counts as open, even if it was closed before.
+func (s *Status) Closed() bool {
+	return s != nil && s.Error == "" && s.Issue != nil && (s.Issue.Status == "closed" || s.Issue.Status == "tombstone")
+}
+
+// Cache holds remote bead statuses by URI, <town>/federation/status.json.
+type Cache struct {
+	Statuses map[string]*Status `json:"statuses"`
+}
+
+// CachePath returns the town's remote status cache.
+func CachePath(townRoot string) string {
+	return filepath.Join(townRoot, "federation", "status.json")
+}
+
+// LoadCache loads the status cache. A missing cache is empty.
+func LoadCache(townRoot string) (*Cache, error) {
+	c := &Cache{Statuses: make(map[string]*Status)}
+	data, err := os.ReadFile(CachePath(townRoot)) //nolint:gosec // G304: path is the town cache
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading remote status cache: %w", err)
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("parsing remote status cache: %w", err)
+	}
+	if c.Statuses == nil {
+		c.Statuses = make(map[string]*Status)
+	}
+	return c, nil
+}
+
+func (c *Cache) save(townRoot string) error {
+	path := CachePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating federation directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding remote status cache: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil { //nolint:gosec // G306: cache is not secret
+		return fmt.Errorf("writing remote status cache: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Get returns the cached status of u, or nil.
+func (c *Cache) Get(u URI) *Status {
+	return c.Statuses[u.String()]
+}
+
+// Sync pulls the status of uris from their remotes into the cache and
+// returns the updated statuses in the order given. A remote that cannot be
+// read keeps its beads' last known state and records the error, as does a
+// URI whose rig is not the one the bead lives in; only a failure to load or
+// save the cache is returned.
+func Sync(townRoot string, uris []URI) ([]*Status, error) {
+	remotes, err := LoadRemotes(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := LoadCache(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	// One fetch per remote
+	byRemote := make(map[*Remote][]URI)
+	var unknown []URI
+	for _, u := range uris {
+		if r := remotes.ForURI(u); r != nil {
+			byRemote[r] = append(byRemote[r], u)
+		} else {
+			unknown = append(unknown, u)
+		}
+	}
+	now := time.Now().UTC()
+	status := func(u URI) *Status {
+		s := cache.Statuses[u.String()]
+		if s == nil {
+			s = &Status{URI: u.String()}
+			cache.Statuses[u.String()] = s
+		}
+		return s
+	}
+	for _, u := range unknown {
+		s := status(u)
+		s.Remote = ""
+		s.Error = fmt.Sprintf("no remote registered for %s (gt remote add)", u.Town())
+	}
+	for r, refs := range byRemote {
+		ids := make([]string, 0, len(refs))
+		for _, u := range refs {
+			ids = append(ids, u.Issue)
+		}
+		found, err := r.Fetch(ids)
+		for _, u := range refs {
+			s := status(u)
+			s.Remote = r.Name
+			switch issue, ok := found[u.Issue]; {
+			case err != nil:
+				s.Error = err.Error()
+			case !ok:
+				s.Error = fmt.Sprintf("%s not found in %s", u.Issue, r.Name)
+			case issue.Rig != u.Rig:
+				s.Error = fmt.Sprintf("%s is in %s of %s, not %s", u.Issue, displayRig(issue.Rig), r.Name, displayRig(u.Rig))
+			default:
+				s.Issue = &issue
+				s.SyncedAt = now
+				s.Error = ""
+			}
+		}
+	}
+
+	if err := cache.save(townRoot); err != nil {
+		return nil, err
+	}
+	out := make([]*Status, len(uris))
+	for i, u := range uris {
+		out[i] = cache.Statuses[u.String()]
+	}
+	return out, nil
+}
+
+// URIs parses refs, skipping any that are not valid HOP URIs, and returns
+// them sorted and without duplicates.
+func URIs(refs []string) []URI {
+	seen := make(map[string]bool)
+	var out []URI
+	for _, ref := range refs {
+		u, err := ParseURI(ref)
+		if err != nil || seen[u.String()] {
+			continue
+		}
+		seen[u.String()] = true
+		out = append(out, u)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].String() < out[j].String() })
+	return out
+}
@@ -0,0 +1,85 @@
+// Package federation lets one town track work in another.
+//
+// Work in another town is named with a HOP URI (docs/design/federation.md):
+//
+//	hop://entity/chain/rig/issue-id
+//	hop://steve@example.com/main-town/greenplace/gp-xyz
+//
+// The entity is the town's owner and the chain is the town's name, both from
+// mayor/town.json. A remote registers where such a town can be read: another
+// town on the same disk, or a status export it publishes over HTTP (gt remote
+// export). Sync pulls the status of referenced beads into a local cache so
+// convoys can show and wait on remote legs without contacting the remote on
+// every read.
+package federation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scheme is the URI scheme for cross-town references.
+const Scheme = "hop://"
+
+// URI is a reference to a bead in another town. Rig is empty for town-level
+// beads (hq-*).
+type URI struct {
+	Entity string `json:"entity"`
+	Chain  string `json:"chain"`
+	Rig    string `json:"rig,omitempty"`
+	Issue  string `json:"issue"`
+}
+
+// IsURI reports whether s is written as a HOP URI.
+func IsURI(s string) bool {
+	return strings.HasPrefix(s, Scheme)
+}
+
+// ParseURI parses hop://entity/chain/rig/issue-id, or
+// hop://entity/chain/issue-id for a town-level bead.
+func ParseURI(s string) (URI, error) {
+	rest, ok := strings.CutPrefix(s, Scheme)
+	if !ok {
+		return URI{}, fmt.Errorf("%q is not a %s URI", s, Scheme)
+	}
+	parts := strings.Split(rest, "/")
+	for _, p := range parts {
+		if p == "" || p == "." || p == ".." || strings.ContainsAny(p, " \t\n?#") {
+			return URI{}, fmt.Errorf("invalid HOP URI %q", s)
+		}
+	}
+	switch len(parts) {
+	case 3:
+		return URI{Entity: parts[0], Chain: parts[1], Issue: parts[2]}, nil
+	case 4:
+		return URI{Entity: parts[0], Chain: parts[1], Rig: parts[2], Issue: parts[3]}, nil
+	default:
+		return URI{}, fmt.Errorf("invalid HOP URI %q: want hop://entity/chain/rig/issue-id", s)
+	}
+}
+
+// String returns the URI in canonical form.
+func (u URI) String() string {
+	if u.Rig == "" {
+		return Scheme + u.Entity + "/" + u.Chain + "/" + u.Issue
+	}
+	return Scheme + u.Entity + "/" + u.Chain + "/" + u.Rig + "/" + u.Issue
+}
+
+// Town returns the hop://entity/chain address of the URI's town.
+func (u URI) Town() string {
+	return Scheme + u.Entity + "/" + u.Chain
+}
+
+// ParseTown parses a town address, hop://entity/chain.
+func ParseTown(s string) (entity, chain string, err error) {
+	rest, ok := strings.CutPrefix(s, Scheme)
+	if !ok {
+		return "", "", fmt.Errorf("%q is not a %s address", s, Scheme)
+	}
+	entity, chain, ok = strings.Cut(strings.TrimSuffix(rest, "/"), "/")
+	if !ok || entity == "" || chain == "" || strings.Contains(chain, "/") {
+		return "", "", fmt.Errorf("invalid town address %q: want hop://entity/chain", s)
+	}
+	return entity, chain, nil
+}
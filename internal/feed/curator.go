@@ -239,47 +239,14 @@ func (c *Curator) readRecentFeedEvents(window time.Duration) []FeedEvent {
 
 // readRecentEvents reads events from the events file within the given time window.
 // ZFC: This is the observable state that replaces in-memory caching.
-// Uses tail-like reading for performance (reads last N lines).
+// The events reader seeks to the window by timestamp, so only the tail is read.
 func (c *Curator) readRecentEvents(window time.Duration) []events.Event {
 	eventsPath := filepath.Join(c.townRoot, events.EventsFile)
-
-	// Read the file (for small files, this is fine; for large files, consider tail-like reading)
-	data, err := os.ReadFile(eventsPath)
-	if err != nil {
-		return nil
-	}
-
-	now := time.Now()
-	cutoff := now.Add(-window)
 	var result []events.Event
-
-	// Parse lines from the end (most recent first) for efficiency
-	lines := strings.Split(string(data), "\n")
-	for i := len(lines) - 1; i >= 0; i-- {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
-		}
-
-		var event events.Event
-		if err := json.Unmarshal([]byte(line), &event); err != nil {
-			continue
-		}
-
-		// Parse timestamp
-		ts, err := time.Parse(time.RFC3339, event.Timestamp)
-		if err != nil {
-			continue
-		}
-
-		// Stop if we've gone past the window
-		if ts.Before(cutoff) {
-			break
-		}
-
-		result = append(result, event)
-	}
-
+	_ = events.Query(eventsPath, events.Filter{Since: time.Now().Add(-window)}, func(e events.Event) error {
+		result = append(result, e)
+		return nil
+	})
 	return result
 }
 
@@ -345,19 +312,21 @@ func (c *Curator) writeFeedEvent(event *events.Event) {
 }
 
 // generateSummary creates a human-readable summary of an event.
+// Payloads are decoded with their registered schemas; fields missing from
+// the event decode as zero values.
 func (c *Curator) generateSummary(event *events.Event) string {
 	switch event.Type {
 	case events.TypeSling:
-		if target, ok := event.Payload["target"].(string); ok {
-			if bead, ok := event.Payload["bead"].(string); ok {
-				return fmt.Sprintf("%s assigned %s to %s", event.Actor, bead, target)
-			}
+		p, _ := events.DecodePayload[events.Sling](*event)
+		if p.Target != "" && p.Bead != "" {
+			return fmt.Sprintf("%s assigned %s to %s", event.Actor, p.Bead, p.Target)
 		}
 		return fmt.Sprintf("%s dispatched work", event.Actor)
 
 	case events.TypeDone:
-		if bead, ok := event.Payload["bead"].(string); ok {
-			return fmt.Sprintf("%s completed work on %s", event.Actor, bead)
+		p, _ := events.DecodePayload[events.Done](*event)
+		if p.Bead != "" {
+			return fmt.Sprintf("%s completed work on %s", event.Actor, p.Bead)
 		}
 		return fmt.Sprintf("%s signaled done", event.Actor)
 
@@ -365,56 +334,57 @@ func (c *Curator) generateSummary(event *events.Event) string {
 		return fmt.Sprintf("%s handed off to fresh session", event.Actor)
 
 	case events.TypeMail:
-		if to, ok := event.Payload["to"].(string); ok {
-			if subj, ok := event.Payload["subject"].(string); ok {
-				return fmt.Sprintf("%s → %s: %s", event.Actor, to, subj)
-			}
+		p, _ := events.DecodePayload[events.Mail](*event)
+		if p.To != "" && p.Subject != "" {
+			return fmt.Sprintf("%s → %s: %s", event.Actor, p.To, p.Subject)
 		}
 		return fmt.Sprintf("%s sent mail", event.Actor)
 
 	case events.TypePatrolStarted:
-		if rig, ok := event.Payload["rig"].(string); ok {
-			return fmt.Sprintf("%s patrol started for %s", event.Actor, rig)
+		p, _ := events.DecodePayload[events.Patrol](*event)
+		if p.Rig != "" {
+			return fmt.Sprintf("%s patrol started for %s", event.Actor, p.Rig)
 		}
 		return fmt.Sprintf("%s started patrol", event.Actor)
 
 	case events.TypePatrolComplete:
-		if msg, ok := event.Payload["message"].(string); ok {
-			return msg
+		p, _ := events.DecodePayload[events.Patrol](*event)
+		if p.Message != "" {
+			return p.Message
 		}
 		return fmt.Sprintf("%s completed patrol", event.Actor)
 
 	case events.TypeMerged:
-		if worker, ok := event.Payload["worker"].(string); ok {
-			return fmt.Sprintf("Merged work from %s", worker)
+		p, _ := events.DecodePayload[events.Merge](*event)
+		if p.Worker != "" {
+			return fmt.Sprintf("Merged work from %s", p.Worker)
 		}
 		return "Work merged"
 
 	case events.TypeMergeFailed:
-		if reason, ok := event.Payload["reason"].(string); ok {
-			return fmt.Sprintf("Merge failed: %s", reason)
+		p, _ := events.DecodePayload[events.Merge](*event)
+		if p.Reason != "" {
+			return fmt.Sprintf("Merge failed: %s", p.Reason)
 		}
 		return "Merge failed"
 
 	case events.TypeSessionDeath:
-		session, _ := event.Payload["session"].(string)
-		reason, _ := event.Payload["reason"].(string)
-		if session != "" && reason != "" {
-			return fmt.Sprintf("Session %s terminated: %s", session, reason)
+		p, _ := events.DecodePayload[events.SessionDeath](*event)
+		if p.Session != "" && p.Reason != "" {
+			return fmt.Sprintf("Session %s terminated: %s", p.Session, p.Reason)
 		}
-		if session != "" {
-			return fmt.Sprintf("Session %s terminated", session)
+		if p.Session != "" {
+			return fmt.Sprintf("Session %s terminated", p.Session)
 		}
 		return "Session terminated"
 
 	case events.TypeMassDeath:
-		count, _ := event.Payload["count"].(float64) // JSON numbers are float64
-		possibleCause, _ := event.Payload["possible_cause"].(string)
-		if count > 0 && possibleCause != "" {
-			return fmt.Sprintf("MASS DEATH: %d sessions died - %s", int(count), possibleCause)
+		p, _ := events.DecodePayload[events.MassDeath](*event)
+		if p.Count > 0 && p.PossibleCause != "" {
+			return fmt.Sprintf("MASS DEATH: %d sessions died - %s", p.Count, p.PossibleCause)
 		}
-		if count > 0 {
-			return fmt.Sprintf("MASS DEATH: %d sessions died simultaneously", int(count))
+		if p.Count > 0 {
+			return fmt.Sprintf("MASS DEATH: %d sessions died simultaneously", p.Count)
 		}
 		return "Multiple sessions died simultaneously"
 
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 // Generate formulas directory from canonical source at .beads/formulas/
@@ -19,7 +21,26 @@ var formulasFS embed.FS
 // InstalledRecord tracks which formulas were installed and their checksums.
 // Stored in .beads/formulas/.installed.json
 type InstalledRecord struct {
-	Formulas map[string]string `json:"formulas"` // filename -> sha256 at install time
+	Formulas map[string]string           `json:"formulas"`           // filename -> sha256 at install time
+	Registry map[string]*RegistryInstall `json:"registry,omitempty"` // name -> registry install, see install.go
+}
+
+// RegistryInstall records where a formula installed from a registry came
+// from. Its checksum is the Formulas entry for the same file.
+type RegistryInstall struct {
+	Version     string    `json:"version"`
+	Constraint  string    `json:"constraint,omitempty"` // Requested version prefix; empty tracks the latest
+	Source      string    `json:"source"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// fromRegistry reports whether filename was installed from a registry.
+// Such files are left to gt formula update, not the embedded-formula
+// health check, which would otherwise "update" them back to the embedded
+// copy.
+func (r *InstalledRecord) fromRegistry(filename string) bool {
+	_, ok := r.Registry[strings.TrimSuffix(filename, FormulaExt)]
+	return ok
 }
 
 // FormulaStatus represents the status of a single formula during health check.
@@ -76,7 +97,7 @@ func loadInstalledRecord(formulasDir string) (*InstalledRecord, error) {
 	path := filepath.Join(formulasDir, ".installed.json")
 	data, err := os.ReadFile(path)
 	if os.IsNotExist(err) {
-		return &InstalledRecord{Formulas: make(map[string]string)}, nil
+		return &InstalledRecord{Formulas: make(map[string]string), Registry: make(map[string]*RegistryInstall)}, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("reading installed record: %w", err)
@@ -88,6 +109,9 @@ func loadInstalledRecord(formulasDir string) (*InstalledRecord, error) {
 	if r.Formulas == nil {
 		r.Formulas = make(map[string]string)
 	}
+	if r.Registry == nil {
+		r.Registry = make(map[string]*RegistryInstall)
+	}
 	return &r, nil
 }
 
@@ -176,6 +200,26 @@ func ProvisionFormulas(beadsPath string) (int, error) {
 	return count, nil
 }
 
+// classifyFormula compares a formula file on disk with the embedded version.
+//   - "ok":        file matches embedded
+//   - "outdated":  file matches what we installed, but embedded has changed
+//     (user hasn't modified it, safe to update)
+//   - "modified":  file was tracked and the user changed it (don't overwrite)
+//   - "untracked": file exists but isn't in .installed.json (e.g., from an
+//     older gt version); safe to update since there's no record of edits
+func classifyFormula(embeddedHash, installedHash string, wasInstalled bool, currentHash string) string {
+	switch {
+	case currentHash == embeddedHash:
+		return "ok"
+	case wasInstalled && currentHash == installedHash:
+		return "outdated"
+	case wasInstalled:
+		return "modified"
+	default:
+		return "untracked"
+	}
+}
+
 // CheckFormulaHealth checks the status of all formulas.
 // Returns a report of which formulas are ok, outdated, modified, or missing.
 func CheckFormulaHealth(beadsPath string) (*HealthReport, error) {
@@ -193,6 +237,9 @@ func CheckFormulaHealth(beadsPath string) (*HealthReport, error) {
 	report := &HealthReport{}
 
 	for filename, embeddedHash := range embedded {
+		if installed.fromRegistry(filename) {
+			continue
+		}
 		status := FormulaStatus{
 			Name:         filename,
 			EmbeddedHash: embeddedHash,
@@ -221,24 +268,15 @@ func CheckFormulaHealth(beadsPath string) (*HealthReport, error) {
 			report.Error++
 		} else {
 			status.CurrentHash = currentHash
-
-			if currentHash == embeddedHash {
-				// File matches embedded - all good
-				status.Status = "ok"
+			status.Status = classifyFormula(embeddedHash, installedHash, wasInstalled, currentHash)
+			switch status.Status {
+			case "ok":
 				report.OK++
-			} else if wasInstalled && currentHash == installedHash {
-				// File matches what we installed, but embedded has changed
-				// User hasn't modified, safe to update
-				status.Status = "outdated"
+			case "outdated":
 				report.Outdated++
-			} else if wasInstalled {
-				// File was tracked and user modified it - don't overwrite
-				status.Status = "modified"
+			case "modified":
 				report.Modified++
-			} else {
-				// File exists but not tracked (e.g., from older gt version)
-				// Safe to update since we have no record of user modification
-				status.Status = "untracked"
+			case "untracked":
 				report.Untracked++
 			}
 		}
@@ -269,6 +307,10 @@ func UpdateFormulas(beadsPath string) (updated, skipped, reinstalled int, err er
 	}
 
 	for filename, embeddedHash := range embedded {
+		if installed.fromRegistry(filename) {
+			// Installed from a registry: leave it to gt formula update
+			continue
+		}
 		installedHash, wasInstalled := installed.Formulas[filename]
 		destPath := filepath.Join(formulasDir, filename)
 		currentHash, fileErr := computeFileHash(destPath)
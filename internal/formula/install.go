@@ -0,0 +1,182 @@
+package formula
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// registryNames returns the names of the formulas installed from a
+// registry, sorted.
+func (r *InstalledRecord) registryNames() []string {
+	names := make([]string, 0, len(r.Registry))
+	for name := range r.Registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// InstallResult describes an install or update.
+type InstallResult struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Previous string `json:"previous,omitempty"` // Version replaced, if any
+	Path     string `json:"path"`
+	Checksum string `json:"checksum"`
+	Source   string `json:"source"`
+}
+
+// Install downloads ref from reg into formulasDir and records it in
+// .installed.json. A version in ref pins the formula to it. An existing
+// file is only replaced if it is an unmodified registry install, or with
+// force.
+func Install(formulasDir string, reg *Registry, ref Ref, force bool) (*InstallResult, error) {
+	idx, err := reg.Index()
+	if err != nil {
+		return nil, err
+	}
+	version, rv, err := idx.Resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+	installed, err := loadInstalledRecord(formulasDir)
+	if err != nil {
+		return nil, err
+	}
+	return install(formulasDir, reg, installed, ref, version, rv, force)
+}
+
+func install(formulasDir string, reg *Registry, installed *InstalledRecord, ref Ref, version string, rv *RegistryVersion, force bool) (*InstallResult, error) {
+	content, err := reg.Download(ref.Name, version, rv)
+	if err != nil {
+		return nil, err
+	}
+	f, err := Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s@%s is not a valid formula: %w", ref.Name, version, err)
+	}
+	if f.Name != ref.Name {
+		return nil, fmt.Errorf("%s@%s defines formula %q", ref.Name, version, f.Name)
+	}
+
+	filename := ref.Name + FormulaExt
+	dest := filepath.Join(formulasDir, filename)
+	prev := installed.Registry[ref.Name]
+	if !force {
+		if err := checkReplaceable(dest, installed, ref.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating formulas directory: %w", err)
+	}
+	if err := os.WriteFile(dest, content, 0644); err != nil { //nolint:gosec // G306: formulas are not secret
+		return nil, fmt.Errorf("writing %s: %w", dest, err)
+	}
+
+	res := &InstallResult{Name: ref.Name, Version: version, Path: dest, Checksum: rv.Checksum, Source: reg.Source(ref.Name, version)}
+	if prev != nil {
+		res.Previous = prev.Version
+	}
+	installed.Formulas[filename] = computeHash(content)
+	installed.Registry[ref.Name] = &RegistryInstall{
+		Version:     version,
+		Constraint:  ref.Version,
+		Source:      res.Source,
+		InstalledAt: time.Now().UTC(),
+	}
+	if err := saveInstalledRecord(formulasDir, installed); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// checkReplaceable refuses to overwrite a formula the registry does not
+// own, or one edited since it was installed.
+func checkReplaceable(dest string, installed *InstalledRecord, name string) error {
+	hash, err := computeFileHash(dest)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", dest, err)
+	}
+	if installed.Registry[name] == nil {
+		return fmt.Errorf("%s exists and was not installed from a registry (use --force to replace it)", dest)
+	}
+	if hash != installed.Formulas[name+FormulaExt] {
+		return fmt.Errorf("%s has local modifications (use --force to replace it)", dest)
+	}
+	return nil
+}
+
+// Update moves installed formulas to the newest version their constraint
+// allows. With a non-nil constraint, the named formula's constraint is
+// replaced first (an empty one unpins it). With no names, every installed
+// formula is updated. Formulas already up to date are not in the result.
+func Update(formulasDir string, reg *Registry, names []string, constraint *string, force bool) ([]*InstallResult, error) {
+	installed, err := loadInstalledRecord(formulasDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		names = installed.registryNames()
+	}
+	idx, err := reg.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*InstallResult
+	for _, name := range names {
+		entry, ok := installed.Registry[name]
+		if !ok {
+			return results, fmt.Errorf("%s is not installed from a registry", name)
+		}
+		ref := Ref{Name: name, Version: entry.Constraint}
+		if constraint != nil {
+			ref.Version = *constraint
+		}
+		version, rv, err := idx.Resolve(ref)
+		if err != nil {
+			return results, err
+		}
+		if version == entry.Version && ref.Version == entry.Constraint {
+			continue
+		}
+		res, err := install(formulasDir, reg, installed, ref, version, rv, force)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// Uninstall removes a registry-installed formula and its record. A
+// locally modified formula is kept unless force is set.
+func Uninstall(formulasDir, name string, force bool) error {
+	installed, err := loadInstalledRecord(formulasDir)
+	if err != nil {
+		return err
+	}
+	if _, ok := installed.Registry[name]; !ok {
+		return fmt.Errorf("%s is not installed from a registry", name)
+	}
+	dest := filepath.Join(formulasDir, name+FormulaExt)
+	if !force {
+		if err := checkReplaceable(dest, installed, name); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(dest); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing %s: %w", dest, err)
+	}
+	delete(installed.Formulas, name+FormulaExt)
+	delete(installed.Registry, name)
+	return saveInstalledRecord(formulasDir, installed)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -142,6 +143,13 @@ func (f *Formula) validateWorkflow() error {
 		}
 	}
 
+	// Validate step execution policies
+	for i := range f.Steps {
+		if err := f.Steps[i].validatePolicy(f.Vars); err != nil {
+			return err
+		}
+	}
+
 	// Check for cycles
 	if err := f.checkCycles(); err != nil {
 		return err
@@ -357,26 +365,15 @@ func (f *Formula) TopologicalSort() ([]string, error) {
 
 // ReadySteps returns steps that have no unmet dependencies.
 // completed is a set of step IDs that have been completed.
+// For workflows, steps whose when condition is false under the var defaults
+// are skipped and count as completed for their dependents; use Plan to
+// account for var values, failures and timeouts.
 func (f *Formula) ReadySteps(completed map[string]bool) []string {
 	var ready []string
 
 	switch f.Type {
 	case TypeWorkflow:
-		for _, step := range f.Steps {
-			if completed[step.ID] {
-				continue
-			}
-			allMet := true
-			for _, need := range step.Needs {
-				if !completed[need] {
-					allMet = false
-					break
-				}
-			}
-			if allMet {
-				ready = append(ready, step.ID)
-			}
-		}
+		ready = f.Plan(StepState{Completed: completed}, time.Time{}).Ready
 	case TypeExpansion:
 		for _, tmpl := range f.Template {
 			if completed[tmpl.ID] {
@@ -0,0 +1,375 @@
+package formula
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mol Mall registry client (docs/mol-mall-design.md).
+//
+// A registry is a static file tree, so any web server, object store or
+// local directory can host one:
+//
+//	<registry>/index.json                                 catalog of every formula and version
+//	<registry>/formulas/<name>/<version>.formula.toml     formula content
+//
+// The index carries a sha256 checksum for every version; downloads are
+// verified against it before anything is installed.
+
+// DefaultRegistry is the public Mol Mall.
+const DefaultRegistry = "https://molmall.gastown.io"
+
+// RegistryIndexFile is the catalog at the root of a registry.
+const RegistryIndexFile = "index.json"
+
+// maxRegistryFile bounds index and formula downloads.
+const maxRegistryFile = 8 << 20
+
+// ErrNotInRegistry is returned when a registry has no formula or version
+// matching a reference.
+var ErrNotInRegistry = errors.New("not in registry")
+
+// RegistryIndex is a registry's catalog.
+type RegistryIndex struct {
+	Version  int                         `json:"version"`
+	Formulas map[string]*RegistryFormula `json:"formulas"`
+}
+
+// RegistryFormula is a formula's entry in the index.
+type RegistryFormula struct {
+	Description  string                      `json:"description,omitempty"`
+	Author       string                      `json:"author,omitempty"`
+	Capabilities []string                    `json:"capabilities,omitempty"`
+	Latest       string                      `json:"latest"`
+	Versions     map[string]*RegistryVersion `json:"versions"`
+}
+
+// RegistryVersion is one published version of a formula.
+type RegistryVersion struct {
+	Checksum    string    `json:"checksum"` // "sha256:<hex>" of the content
+	Path        string    `json:"path"`     // Content path relative to the registry root
+	PublishedAt time.Time `json:"published_at"`
+	Changelog   string    `json:"changelog,omitempty"`
+}
+
+// Ref is a formula reference: name, name@major, name@major.minor or
+// name@major.minor.patch. An empty Version means the latest.
+type Ref struct {
+	Name    string
+	Version string
+}
+
+func (r Ref) String() string {
+	if r.Version == "" {
+		return r.Name
+	}
+	return r.Name + "@" + r.Version
+}
+
+var (
+	refNameRe    = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+	refVersionRe = regexp.MustCompile(`^\d+(\.\d+){0,2}$`)
+)
+
+// ParseRef parses a formula reference such as "mol-polecat-work@4.0.0".
+func ParseRef(s string) (Ref, error) {
+	name, version, _ := strings.Cut(strings.TrimSuffix(s, FormulaExt), "@")
+	if !refNameRe.MatchString(name) {
+		return Ref{}, fmt.Errorf("invalid formula name %q", name)
+	}
+	version = strings.TrimPrefix(version, "v")
+	if version != "" && !refVersionRe.MatchString(version) {
+		return Ref{}, fmt.Errorf("invalid version %q: use MAJOR, MAJOR.MINOR or MAJOR.MINOR.PATCH", version)
+	}
+	return Ref{Name: name, Version: version}, nil
+}
+
+// versionMatches reports whether the full version v satisfies constraint,
+// a version prefix ("4" matches 4.1.0, "4.1" matches 4.1.2). An empty
+// constraint matches everything.
+func versionMatches(v, constraint string) bool {
+	return constraint == "" || v == constraint || strings.HasPrefix(v, constraint+".")
+}
+
+// compareVersions orders dotted numeric versions.
+func compareVersions(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			y, _ = strconv.Atoi(pb[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// Best returns the newest version matching constraint.
+func (f *RegistryFormula) Best(constraint string) (string, *RegistryVersion, bool) {
+	best := ""
+	for v := range f.Versions {
+		if versionMatches(v, constraint) && (best == "" || compareVersions(v, best) > 0) {
+			best = v
+		}
+	}
+	if best == "" {
+		return "", nil, false
+	}
+	return best, f.Versions[best], true
+}
+
+// Registry reads a Mol Mall registry at an http(s):// URL, a file:// URL or
+// a local directory.
+type Registry struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewRegistry returns a client for the registry at url.
+func NewRegistry(url string) *Registry {
+	return &Registry{URL: strings.TrimRight(url, "/"), Client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// remote reports whether the registry is served over HTTP.
+func (r *Registry) remote() bool {
+	return strings.HasPrefix(r.URL, "http://") || strings.HasPrefix(r.URL, "https://")
+}
+
+// dir returns the local directory of a file registry.
+func (r *Registry) dir() string {
+	return strings.TrimPrefix(r.URL, "file://")
+}
+
+// fetch reads a file from the registry. rel is slash-separated.
+func (r *Registry) fetch(rel string) ([]byte, error) {
+	if !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return nil, fmt.Errorf("registry path %q escapes the registry", rel)
+	}
+	if !r.remote() {
+		data, err := os.ReadFile(filepath.Join(r.dir(), filepath.FromSlash(rel))) //nolint:gosec // G304: path is inside the registry
+		if err != nil {
+			return nil, fmt.Errorf("reading %s from registry: %w", rel, err)
+		}
+		return data, nil
+	}
+
+	url := r.URL + "/" + rel
+	resp, err := r.Client.Get(url) //nolint:gosec // G107: registry URL is configured by the user
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRegistryFile+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	if len(data) > maxRegistryFile {
+		return nil, fmt.Errorf("fetching %s: larger than %d bytes", url, maxRegistryFile)
+	}
+	return data, nil
+}
+
+// Index fetches the registry's catalog.
+func (r *Registry) Index() (*RegistryIndex, error) {
+	data, err := r.fetch(RegistryIndexFile)
+	if err != nil {
+		return nil, err
+	}
+	var idx RegistryIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("parsing registry index: %w", err)
+	}
+	if idx.Formulas == nil {
+		idx.Formulas = make(map[string]*RegistryFormula)
+	}
+	return &idx, nil
+}
+
+// Resolve finds the newest version in idx matching ref.
+func (idx *RegistryIndex) Resolve(ref Ref) (string, *RegistryVersion, error) {
+	f, ok := idx.Formulas[ref.Name]
+	if !ok {
+		return "", nil, fmt.Errorf("%s: %w", ref.Name, ErrNotInRegistry)
+	}
+	v, rv, ok := f.Best(ref.Version)
+	if !ok {
+		return "", nil, fmt.Errorf("%s: %w", ref, ErrNotInRegistry)
+	}
+	return v, rv, nil
+}
+
+// Download fetches a version's content and verifies its checksum.
+func (r *Registry) Download(name, version string, rv *RegistryVersion) ([]byte, error) {
+	rel := rv.Path
+	if rel == "" {
+		rel = registryContentPath(name, version)
+	}
+	data, err := r.fetch(rel)
+	if err != nil {
+		return nil, err
+	}
+	if got := checksum(data); got != rv.Checksum {
+		return nil, fmt.Errorf("checksum mismatch for %s@%s: registry says %s, downloaded %s", name, version, rv.Checksum, got)
+	}
+	return data, nil
+}
+
+// Source returns the canonical address of a formula version in the registry.
+func (r *Registry) Source(name, version string) string {
+	return r.URL + "/formulas/" + name + "@" + version
+}
+
+// SearchResult is a formula matching a search.
+type SearchResult struct {
+	Name string `json:"name"`
+	*RegistryFormula
+}
+
+// Search returns formulas whose name or description contains query (case
+// insensitive) and that declare every capability in caps, sorted by name.
+func (idx *RegistryIndex) Search(query string, caps []string) []SearchResult {
+	query = strings.ToLower(query)
+	var results []SearchResult
+	for name, f := range idx.Formulas {
+		if query != "" && !strings.Contains(strings.ToLower(name), query) &&
+			!strings.Contains(strings.ToLower(f.Description), query) {
+			continue
+		}
+		if !hasAll(f.Capabilities, caps) {
+			continue
+		}
+		results = append(results, SearchResult{Name: name, RegistryFormula: f})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return results
+}
+
+func hasAll(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if strings.EqualFold(h, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// checksum returns the registry checksum of content.
+func checksum(content []byte) string {
+	return "sha256:" + computeHash(content)
+}
+
+// registryContentPath is where a version's content lives in a registry.
+func registryContentPath(name, version string) string {
+	return path.Join("formulas", name, version+FormulaExt)
+}
+
+// PublishOptions describes a formula version to publish.
+type PublishOptions struct {
+	Version      string // MAJOR.MINOR.PATCH
+	Author       string
+	Changelog    string
+	Capabilities []string
+}
+
+// Publish packages content into the registry directory registryDir: the
+// content is stored under formulas/<name>/<version>.formula.toml and the
+// index gains the version with its checksum. The directory can then be
+// served as-is. Published versions are immutable; publishing identical
+// content again is a no-op. It returns the formula's name.
+func Publish(registryDir string, content []byte, opts PublishOptions) (string, error) {
+	f, err := Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("invalid formula: %w", err)
+	}
+	if !refNameRe.MatchString(f.Name) {
+		return "", fmt.Errorf("invalid formula name %q", f.Name)
+	}
+	if strings.Count(opts.Version, ".") != 2 || !refVersionRe.MatchString(opts.Version) {
+		return "", fmt.Errorf("invalid version %q: publish a full MAJOR.MINOR.PATCH version", opts.Version)
+	}
+
+	reg := NewRegistry(registryDir)
+	idx := &RegistryIndex{Version: 1, Formulas: make(map[string]*RegistryFormula)}
+	if _, err := os.Stat(filepath.Join(registryDir, RegistryIndexFile)); err == nil {
+		if idx, err = reg.Index(); err != nil {
+			return "", err
+		}
+	}
+
+	entry := idx.Formulas[f.Name]
+	if entry == nil {
+		entry = &RegistryFormula{Versions: make(map[string]*RegistryVersion)}
+		idx.Formulas[f.Name] = entry
+	}
+	sum := checksum(content)
+	if existing, ok := entry.Versions[opts.Version]; ok {
+		if existing.Checksum == sum {
+			return f.Name, nil
+		}
+		return "", fmt.Errorf("%s@%s is already published with different content; publish a new version", f.Name, opts.Version)
+	}
+
+	rel := registryContentPath(f.Name, opts.Version)
+	dest := filepath.Join(registryDir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", fmt.Errorf("creating registry directory: %w", err)
+	}
+	if err := os.WriteFile(dest, content, 0644); err != nil { //nolint:gosec // G306: formulas are not secret
+		return "", fmt.Errorf("writing %s: %w", rel, err)
+	}
+
+	entry.Versions[opts.Version] = &RegistryVersion{
+		Checksum:    sum,
+		Path:        rel,
+		PublishedAt: time.Now().UTC(),
+		Changelog:   opts.Changelog,
+	}
+	if entry.Latest == "" || compareVersions(opts.Version, entry.Latest) > 0 {
+		entry.Latest = opts.Version
+		entry.Description = f.Description
+		if opts.Author != "" {
+			entry.Author = opts.Author
+		}
+		if len(opts.Capabilities) > 0 {
+			entry.Capabilities = opts.Capabilities
+		}
+	}
+
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding registry index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(registryDir, RegistryIndexFile), append(data, '\n'), 0644); err != nil { //nolint:gosec // G306: index is public
+		return "", fmt.Errorf("writing registry index: %w", err)
+	}
+	return f.Name, nil
+}
@@ -0,0 +1,214 @@
+package formula
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func registryFormula(desc string) []byte {
+	return []byte(`formula = "mol-review"
+description = "` + desc + `"
+type = "workflow"
+version = 1
+
+[[steps]]
+id = "review"
+title = "Review the change"
+`)
+}
+
+// testRegistry publishes versions of mol-review to a static registry
+// directory and serves it over HTTP.
+func testRegistry(t *testing.T, versions ...string) (dir string, reg *Registry) {
+	t.Helper()
+	dir = t.TempDir()
+	for _, v := range versions {
+		if _, err := Publish(dir, registryFormula("Review v"+v), PublishOptions{Version: v, Capabilities: []string{"code-review"}}); err != nil {
+			t.Fatalf("Publish %s: %v", v, err)
+		}
+	}
+	srv := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(srv.Close)
+	return dir, NewRegistry(srv.URL)
+}
+
+func TestParseRefAndVersions(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want Ref
+		ok   bool
+	}{
+		{"mol-review", Ref{Name: "mol-review"}, true},
+		{"mol-review@2", Ref{Name: "mol-review", Version: "2"}, true},
+		{"mol-review@v2.1.0", Ref{Name: "mol-review", Version: "2.1.0"}, true},
+		{"mol-review@latest", Ref{}, false},
+		{"../etc@1", Ref{}, false},
+	} {
+		got, err := ParseRef(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseRef(%q) = %+v, %v", tt.in, got, err)
+		}
+	}
+
+	f := &RegistryFormula{Versions: map[string]*RegistryVersion{"1.9.0": {}, "1.10.0": {}, "2.0.0": {}, "1.10.2": {}}}
+	for constraint, want := range map[string]string{"": "2.0.0", "1": "1.10.2", "1.9": "1.9.0", "1.10.0": "1.10.0", "3": ""} {
+		if got, _, _ := f.Best(constraint); got != want {
+			t.Errorf("Best(%q) = %q, want %q", constraint, got, want)
+		}
+	}
+}
+
+func TestInstallPinUpdateUninstall(t *testing.T) {
+	regDir, reg := testRegistry(t, "1.0.0", "1.1.0")
+	formulas := t.TempDir()
+
+	// Pinned install
+	res, err := Install(formulas, reg, Ref{Name: "mol-review", Version: "1.0.0"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Version != "1.0.0" || !strings.HasSuffix(res.Source, "/formulas/mol-review@1.0.0") {
+		t.Errorf("install = %+v", res)
+	}
+	installed, _ := loadInstalledRecord(formulas)
+	if e := installed.Registry["mol-review"]; e == nil || e.Constraint != "1.0.0" || e.Version != "1.0.0" {
+		t.Fatalf("registry entry = %+v", e)
+	}
+	if got := installed.Formulas["mol-review"+FormulaExt]; "sha256:"+got != checksum(registryFormula("Review v1.0.0")) {
+		t.Errorf("installed checksum = %q", got)
+	}
+
+	// A pinned formula stays put; unpinning moves it to the latest
+	if got, err := Update(formulas, reg, nil, nil, false); err != nil || len(got) != 0 {
+		t.Fatalf("Update of pinned = %v, %v", got, err)
+	}
+	latest := ""
+	got, err := Update(formulas, reg, []string{"mol-review"}, &latest, false)
+	if err != nil || len(got) != 1 || got[0].Version != "1.1.0" || got[0].Previous != "1.0.0" {
+		t.Fatalf("Update unpinned = %+v, %v", got, err)
+	}
+
+	// New releases are picked up; local edits are protected
+	if _, err := Publish(regDir, registryFormula("Review v1.2.0"), PublishOptions{Version: "1.2.0"}); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(formulas, "mol-review"+FormulaExt)
+	if err := os.WriteFile(path, []byte("# edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Update(formulas, reg, nil, nil, false); err == nil || !strings.Contains(err.Error(), "local modifications") {
+		t.Fatalf("Update over local edit: %v", err)
+	}
+	if err := Uninstall(formulas, "mol-review", false); err == nil {
+		t.Fatal("Uninstall removed a modified formula")
+	}
+	if got, err := Update(formulas, reg, nil, nil, true); err != nil || len(got) != 1 || got[0].Version != "1.2.0" {
+		t.Fatalf("forced Update = %+v, %v", got, err)
+	}
+
+	if err := Uninstall(formulas, "mol-review", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("formula file left behind")
+	}
+	if installed, _ := loadInstalledRecord(formulas); len(installed.Registry) != 0 || len(installed.Formulas) != 0 {
+		t.Errorf("installed record not cleared: %+v", installed)
+	}
+}
+
+func TestInstallVerifiesChecksum(t *testing.T) {
+	regDir, reg := testRegistry(t, "1.0.0")
+	content := filepath.Join(regDir, "formulas", "mol-review", "1.0.0"+FormulaExt)
+	if err := os.WriteFile(content, registryFormula("Tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	formulas := t.TempDir()
+	if _, err := Install(formulas, reg, Ref{Name: "mol-review"}, false); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Install of tampered content: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(formulas, "mol-review"+FormulaExt)); !os.IsNotExist(err) {
+		t.Error("tampered formula was installed")
+	}
+}
+
+func TestInstallRefusesUnmanagedFile(t *testing.T) {
+	_, reg := testRegistry(t, "1.0.0")
+	formulas := t.TempDir()
+	if err := os.WriteFile(filepath.Join(formulas, "mol-review"+FormulaExt), registryFormula("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Install(formulas, reg, Ref{Name: "mol-review"}, false); err == nil {
+		t.Fatal("Install replaced a formula it does not own")
+	}
+	if _, err := Install(formulas, reg, Ref{Name: "mol-nope"}, false); !errors.Is(err, ErrNotInRegistry) {
+		t.Errorf("Install of unknown formula: %v", err)
+	}
+}
+
+func TestPublishAndSearch(t *testing.T) {
+	dir, _ := testRegistry(t, "1.0.0")
+	reg := NewRegistry("file://" + dir)
+
+	// Republishing identical content is a no-op; changed content is refused
+	if _, err := Publish(dir, registryFormula("Review v1.0.0"), PublishOptions{Version: "1.0.0"}); err != nil {
+		t.Errorf("identical republish: %v", err)
+	}
+	if _, err := Publish(dir, registryFormula("changed"), PublishOptions{Version: "1.0.0"}); err == nil {
+		t.Error("published different content under an existing version")
+	}
+	if _, err := Publish(dir, registryFormula("x"), PublishOptions{Version: "2"}); err == nil {
+		t.Error("published a partial version")
+	}
+
+	idx, err := reg.Index()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := idx.Search("review", []string{"Code-Review"}); len(got) != 1 || got[0].Latest != "1.0.0" {
+		t.Errorf("Search = %+v", got)
+	}
+	if got := idx.Search("deploy", nil); len(got) != 0 {
+		t.Errorf("Search(deploy) = %+v", got)
+	}
+}
+
+func TestRegistryManagedSkipsEmbeddedHealth(t *testing.T) {
+	embedded, err := getEmbeddedFormulas()
+	if err != nil || len(embedded) == 0 {
+		t.Skip("no embedded formulas")
+	}
+	var filename string
+	for f := range embedded {
+		filename = f
+		break
+	}
+	beadsPath := t.TempDir()
+	formulasDir := filepath.Join(beadsPath, ".beads", "formulas")
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(formulasDir, filename)
+	if err := os.WriteFile(path, []byte("# from a registry\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	installed := &InstalledRecord{
+		Formulas: map[string]string{filename: computeHash([]byte("# from a registry\n"))},
+		Registry: map[string]*RegistryInstall{strings.TrimSuffix(filename, FormulaExt): {Version: "1.0.0"}},
+	}
+	if err := saveInstalledRecord(formulasDir, installed); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, err := UpdateFormulas(beadsPath); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "# from a registry\n" {
+		t.Error("UpdateFormulas overwrote a registry-installed formula")
+	}
+}
@@ -0,0 +1,280 @@
+package formula
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Tier identifies where a formula was found.
+// Tiers are searched most specific first: project, town, user, system.
+type Tier string
+
+const (
+	// TierProject is <project>/.beads/formulas/, found by walking up from cwd.
+	TierProject Tier = "project"
+	// TierTown is <town>/.beads/formulas/.
+	TierTown Tier = "town"
+	// TierUser is ~/.beads/formulas/, shared by every town of the user.
+	TierUser Tier = "user"
+	// TierSystem is the copy embedded in the gt binary.
+	TierSystem Tier = "system"
+)
+
+// FormulaExt is the file extension for formula files.
+const FormulaExt = ".formula.toml"
+
+// FormulaJSONExt is the extension of bd's JSON formulas. They resolve like
+// TOML formulas (a .formula.toml in the same directory wins) and are cooked
+// by bd, but gt cannot parse them.
+const FormulaJSONExt = ".formula.json"
+
+// ErrJSONFormula is returned when loading a JSON formula.
+var ErrJSONFormula = errors.New("JSON formulas are read by bd only")
+
+// EmbeddedPath is the placeholder Path for system-tier candidates.
+const EmbeddedPath = "<embedded>"
+
+// ErrFormulaNotFound is returned when no tier provides the requested formula.
+var ErrFormulaNotFound = errors.New("formula not found")
+
+// Candidate is one copy of a formula at a single tier.
+type Candidate struct {
+	Tier Tier   `json:"tier"`
+	Path string `json:"path"` // file path, or EmbeddedPath for the system tier
+	Hash string `json:"hash"` // sha256 of the file content
+
+	// Status compares a local copy with the embedded version using the same
+	// classification as CheckFormulaHealth ("ok", "outdated", "modified",
+	// "untracked"). Empty for the system tier and for formulas with no
+	// embedded version.
+	Status string `json:"status,omitempty"`
+}
+
+// Resolution is the result of resolving a formula name across all tiers.
+type Resolution struct {
+	Name     string      `json:"name"`
+	Winner   Candidate   `json:"winner"`
+	Shadowed []Candidate `json:"shadowed,omitempty"` // lower-priority copies, in tier order
+	Warnings []string    `json:"warnings,omitempty"`
+}
+
+// Load reads and parses the winning copy.
+func (r *Resolution) Load() (*Formula, error) {
+	if strings.HasSuffix(r.Winner.Path, FormulaJSONExt) {
+		return nil, fmt.Errorf("%s: %w", r.Winner.Path, ErrJSONFormula)
+	}
+	data, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Read returns the raw content of the winning copy.
+func (r *Resolution) Read() ([]byte, error) {
+	if r.Winner.Tier == TierSystem {
+		return formulasFS.ReadFile("formulas/" + r.Name + FormulaExt)
+	}
+	return os.ReadFile(r.Winner.Path) //nolint:gosec // G304: path is from a formula search directory
+}
+
+// Resolver finds formulas across the project, town, user and system tiers.
+type Resolver struct {
+	ProjectDir string // formulas directory for the project tier ("" = none)
+	TownDir    string // formulas directory for the town tier ("" = none)
+	UserDir    string // formulas directory for the user tier ("" = none)
+}
+
+// NewResolver builds a resolver for cwd. The project tier is the nearest
+// .beads/formulas/ at or above cwd but below the town root; the town tier is
+// <town>/.beads/formulas/; the user tier is ~/.beads/formulas/. The town
+// root is found from cwd, falling back to $GT_ROOT.
+func NewResolver(cwd string) *Resolver {
+	townRoot, _ := workspace.Find(cwd)
+	if townRoot == "" {
+		townRoot = os.Getenv("GT_ROOT")
+	}
+
+	r := &Resolver{}
+	if townRoot != "" {
+		r.TownDir = filepath.Join(townRoot, ".beads", "formulas")
+	}
+	r.ProjectDir = findProjectFormulasDir(cwd, townRoot)
+	if home, err := os.UserHomeDir(); err == nil {
+		if dir := filepath.Join(home, ".beads", "formulas"); dir != r.TownDir && dir != r.ProjectDir {
+			r.UserDir = dir
+		}
+	}
+	return r
+}
+
+// findProjectFormulasDir walks up from cwd looking for .beads/formulas/,
+// stopping before townRoot (whose formulas are the town tier).
+func findProjectFormulasDir(cwd, townRoot string) string {
+	dir, err := filepath.Abs(cwd)
+	if err != nil {
+		return ""
+	}
+	if townRoot != "" {
+		if abs, err := filepath.Abs(townRoot); err == nil {
+			townRoot = abs
+		}
+	}
+
+	for {
+		if townRoot != "" && dir == townRoot {
+			return ""
+		}
+		candidate := filepath.Join(dir, ".beads", "formulas")
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// ResolveFormula resolves name from cwd using project → town → user → system order.
+func ResolveFormula(name, cwd string) (*Resolution, error) {
+	return NewResolver(cwd).Resolve(name)
+}
+
+// Resolve finds every copy of the named formula and picks the most specific.
+func (r *Resolver) Resolve(name string) (*Resolution, error) {
+	name = strings.TrimSuffix(strings.TrimSuffix(name, FormulaExt), FormulaJSONExt)
+
+	embedded, err := getEmbeddedFormulas()
+	if err != nil {
+		return nil, err
+	}
+	embeddedHash, hasEmbedded := embedded[name+FormulaExt]
+
+	var candidates []Candidate
+	for _, tier := range []struct {
+		tier Tier
+		dir  string
+	}{
+		{TierProject, r.ProjectDir},
+		{TierTown, r.TownDir},
+		{TierUser, r.UserDir},
+	} {
+		if tier.dir == "" {
+			continue
+		}
+		c, err := localCandidate(tier.tier, tier.dir, name, embeddedHash, hasEmbedded)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			candidates = append(candidates, *c)
+		}
+	}
+	if hasEmbedded {
+		candidates = append(candidates, Candidate{Tier: TierSystem, Path: EmbeddedPath, Hash: embeddedHash})
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrFormulaNotFound, name)
+	}
+
+	res := &Resolution{Name: name, Winner: candidates[0], Shadowed: candidates[1:]}
+	if res.Winner.Status == "outdated" {
+		hint := "delete it to use the embedded version"
+		if res.Winner.Tier == TierTown {
+			hint = "run 'gt doctor --fix' to update"
+		}
+		res.Warnings = append(res.Warnings, fmt.Sprintf(
+			"%s copy of %s is older than the embedded version (%s)", res.Winner.Tier, name, hint))
+	}
+	return res, nil
+}
+
+// localCandidate returns the candidate for name in dir, or nil if absent.
+func localCandidate(tier Tier, dir, name, embeddedHash string, hasEmbedded bool) (*Candidate, error) {
+	path := filepath.Join(dir, name+FormulaExt)
+	hash, err := computeFileHash(path)
+	if os.IsNotExist(err) {
+		path = filepath.Join(dir, name+FormulaJSONExt)
+		hash, err = computeFileHash(path)
+	}
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	c := &Candidate{Tier: tier, Path: path, Hash: hash}
+	if hasEmbedded && strings.HasSuffix(path, FormulaExt) {
+		installed, err := loadInstalledRecord(dir)
+		if err != nil {
+			return nil, err
+		}
+		if installed.fromRegistry(name + FormulaExt) {
+			return c, nil // Registry install, not a copy of the embedded one
+		}
+		installedHash, wasInstalled := installed.Formulas[name+FormulaExt]
+		c.Status = classifyFormula(embeddedHash, installedHash, wasInstalled, hash)
+	}
+	return c, nil
+}
+
+// List resolves every formula visible from any tier, sorted by name.
+func (r *Resolver) List() ([]*Resolution, error) {
+	names := make(map[string]bool)
+
+	embedded, err := getEmbeddedFormulas()
+	if err != nil {
+		return nil, err
+	}
+	for filename := range embedded {
+		names[strings.TrimSuffix(filename, FormulaExt)] = true
+	}
+	for _, dir := range []string{r.ProjectDir, r.TownDir, r.UserDir} {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("reading %s: %w", dir, err)
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			for _, ext := range []string{FormulaExt, FormulaJSONExt} {
+				if strings.HasSuffix(e.Name(), ext) {
+					names[strings.TrimSuffix(e.Name(), ext)] = true
+				}
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var results []*Resolution
+	for _, name := range sorted {
+		res, err := r.Resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
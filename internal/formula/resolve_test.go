@@ -0,0 +1,259 @@
+package formula
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testWorkflowFormula = `formula = "mol-local-only"
+type = "workflow"
+version = 1
+
+[[steps]]
+id = "one"
+title = "Step one"
+`
+
+// setupTiers creates a town with a rig project inside it and returns the
+// town root and the project directory.
+func setupTiers(t *testing.T) (townRoot, projectDir string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir()) // Empty user tier
+	townRoot = t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town","name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	projectDir = filepath.Join(townRoot, "gastown", "crew", "max")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot, projectDir
+}
+
+func writeFormula(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	formulasDir := filepath.Join(dir, ".beads", "formulas")
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(formulasDir, name+FormulaExt)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func embeddedContent(t *testing.T, name string) string {
+	t.Helper()
+	data, err := formulasFS.ReadFile("formulas/" + name + FormulaExt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestResolveFormula_SystemFallback(t *testing.T) {
+	_, projectDir := setupTiers(t)
+
+	res, err := ResolveFormula("mol-polecat-work", projectDir)
+	if err != nil {
+		t.Fatalf("ResolveFormula: %v", err)
+	}
+	if res.Winner.Tier != TierSystem || res.Winner.Path != EmbeddedPath {
+		t.Errorf("Winner = %+v, want system tier", res.Winner)
+	}
+	if len(res.Shadowed) != 0 {
+		t.Errorf("Shadowed = %+v, want none", res.Shadowed)
+	}
+	data, err := res.Read()
+	if err != nil || len(data) == 0 {
+		t.Errorf("Read() = %d bytes, %v", len(data), err)
+	}
+}
+
+func TestResolveFormula_ProjectShadowsTownAndSystem(t *testing.T) {
+	townRoot, projectDir := setupTiers(t)
+	content := embeddedContent(t, "mol-polecat-work")
+	townPath := writeFormula(t, townRoot, "mol-polecat-work", content)
+	projectPath := writeFormula(t, filepath.Join(townRoot, "gastown", "crew"), "mol-polecat-work", content+"\n# crew tweak\n")
+
+	res, err := ResolveFormula("mol-polecat-work", projectDir)
+	if err != nil {
+		t.Fatalf("ResolveFormula: %v", err)
+	}
+	if res.Winner.Tier != TierProject || res.Winner.Path != projectPath {
+		t.Errorf("Winner = %+v, want project copy %s", res.Winner, projectPath)
+	}
+	if res.Winner.Status != "untracked" {
+		t.Errorf("project Status = %q, want untracked", res.Winner.Status)
+	}
+	if len(res.Shadowed) != 2 {
+		t.Fatalf("Shadowed = %+v, want town and system", res.Shadowed)
+	}
+	if res.Shadowed[0].Tier != TierTown || res.Shadowed[0].Path != townPath || res.Shadowed[0].Status != "ok" {
+		t.Errorf("Shadowed[0] = %+v, want town copy matching embedded", res.Shadowed[0])
+	}
+	if res.Shadowed[1].Tier != TierSystem {
+		t.Errorf("Shadowed[1] = %+v, want system", res.Shadowed[1])
+	}
+}
+
+func TestResolveFormula_WarnsWhenOutdated(t *testing.T) {
+	townRoot, projectDir := setupTiers(t)
+	oldContent := "# old version\n" + embeddedContent(t, "mol-polecat-work")
+	writeFormula(t, townRoot, "mol-polecat-work", oldContent)
+
+	// Record the old content as what gt installed, so the copy is unmodified but stale
+	record := &InstalledRecord{Formulas: map[string]string{
+		"mol-polecat-work" + FormulaExt: computeHash([]byte(oldContent)),
+	}}
+	if err := saveInstalledRecord(filepath.Join(townRoot, ".beads", "formulas"), record); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := ResolveFormula("mol-polecat-work", projectDir)
+	if err != nil {
+		t.Fatalf("ResolveFormula: %v", err)
+	}
+	if res.Winner.Tier != TierTown || res.Winner.Status != "outdated" {
+		t.Errorf("Winner = %+v, want outdated town copy", res.Winner)
+	}
+	if len(res.Warnings) != 1 || !strings.Contains(res.Warnings[0], "older than the embedded version") {
+		t.Errorf("Warnings = %v, want outdated warning", res.Warnings)
+	}
+}
+
+func TestResolveFormula_LocalOnly(t *testing.T) {
+	_, projectDir := setupTiers(t)
+	writeFormula(t, projectDir, "mol-local-only", testWorkflowFormula)
+
+	res, err := ResolveFormula("mol-local-only", projectDir)
+	if err != nil {
+		t.Fatalf("ResolveFormula: %v", err)
+	}
+	if res.Winner.Tier != TierProject || res.Winner.Status != "" {
+		t.Errorf("Winner = %+v, want project tier with no embedded status", res.Winner)
+	}
+	f, err := res.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if f.Name != "mol-local-only" {
+		t.Errorf("Name = %q", f.Name)
+	}
+}
+
+func TestResolveFormula_NotFound(t *testing.T) {
+	_, projectDir := setupTiers(t)
+	_, err := ResolveFormula("no-such-formula", projectDir)
+	if !errors.Is(err, ErrFormulaNotFound) {
+		t.Errorf("err = %v, want ErrFormulaNotFound", err)
+	}
+}
+
+func TestResolveFormula_TownFormulasAreNotProjectTier(t *testing.T) {
+	townRoot, _ := setupTiers(t)
+	writeFormula(t, townRoot, "mol-local-only", testWorkflowFormula)
+
+	res, err := ResolveFormula("mol-local-only", townRoot)
+	if err != nil {
+		t.Fatalf("ResolveFormula: %v", err)
+	}
+	if res.Winner.Tier != TierTown || len(res.Shadowed) != 0 {
+		t.Errorf("Resolution = %+v, want single town candidate", res)
+	}
+}
+
+func TestResolveFormula_UserTier(t *testing.T) {
+	townRoot, projectDir := setupTiers(t)
+	home := os.Getenv("HOME")
+	writeFormula(t, home, "mol-local-only", testWorkflowFormula)
+
+	res, err := ResolveFormula("mol-local-only", projectDir)
+	if err != nil {
+		t.Fatalf("ResolveFormula: %v", err)
+	}
+	if res.Winner.Tier != TierUser {
+		t.Errorf("Winner = %+v, want user tier", res.Winner)
+	}
+
+	// The town tier is more specific than the user's
+	writeFormula(t, townRoot, "mol-local-only", testWorkflowFormula)
+	res, err = ResolveFormula("mol-local-only", projectDir)
+	if err != nil {
+		t.Fatalf("ResolveFormula: %v", err)
+	}
+	if res.Winner.Tier != TierTown || len(res.Shadowed) != 1 || res.Shadowed[0].Tier != TierUser {
+		t.Errorf("Resolution = %+v, want town shadowing user", res)
+	}
+}
+
+func TestResolveFormula_JSON(t *testing.T) {
+	townRoot, projectDir := setupTiers(t)
+	dir := filepath.Join(townRoot, ".beads", "formulas")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "mol-bd-only"+FormulaJSONExt)
+	if err := os.WriteFile(path, []byte(`{"formula":"mol-bd-only"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := ResolveFormula("mol-bd-only", projectDir)
+	if err != nil {
+		t.Fatalf("ResolveFormula: %v", err)
+	}
+	if res.Winner.Path != path {
+		t.Errorf("Winner.Path = %q, want %q", res.Winner.Path, path)
+	}
+	if _, err := res.Load(); !errors.Is(err, ErrJSONFormula) {
+		t.Errorf("Load err = %v, want ErrJSONFormula", err)
+	}
+
+	// A TOML copy in the same tier wins
+	writeFormula(t, townRoot, "mol-bd-only", strings.Replace(testWorkflowFormula, "mol-local-only", "mol-bd-only", 1))
+	if res, err = ResolveFormula("mol-bd-only", projectDir); err != nil || !strings.HasSuffix(res.Winner.Path, FormulaExt) {
+		t.Errorf("ResolveFormula = %+v, %v; want the TOML copy", res, err)
+	}
+}
+
+func TestResolverList(t *testing.T) {
+	townRoot, projectDir := setupTiers(t)
+	writeFormula(t, projectDir, "mol-local-only", testWorkflowFormula)
+	writeFormula(t, townRoot, "mol-polecat-work", embeddedContent(t, "mol-polecat-work"))
+
+	list, err := NewResolver(projectDir).List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	embedded, _ := getEmbeddedFormulas()
+	if len(list) != len(embedded)+1 {
+		t.Errorf("List() returned %d formulas, want %d", len(list), len(embedded)+1)
+	}
+	for i := 1; i < len(list); i++ {
+		if list[i-1].Name >= list[i].Name {
+			t.Fatalf("List() not sorted at %d: %s >= %s", i, list[i-1].Name, list[i].Name)
+		}
+	}
+
+	tiers := make(map[string]Tier)
+	for _, res := range list {
+		tiers[res.Name] = res.Winner.Tier
+	}
+	if tiers["mol-local-only"] != TierProject {
+		t.Errorf("mol-local-only tier = %q, want project", tiers["mol-local-only"])
+	}
+	if tiers["mol-polecat-work"] != TierTown {
+		t.Errorf("mol-polecat-work tier = %q, want town", tiers["mol-polecat-work"])
+	}
+	if tiers["mol-deacon-patrol"] != TierSystem {
+		t.Errorf("mol-deacon-patrol tier = %q, want system", tiers["mol-deacon-patrol"])
+	}
+}
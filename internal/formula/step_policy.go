@@ -0,0 +1,315 @@
+package formula
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// On-failure policies for workflow steps.
+const (
+	// OnFailureAbort stops the molecule at the failed step. This is the default.
+	OnFailureAbort = "abort"
+	// OnFailureContinue closes the failed step and lets dependents run.
+	OnFailureContinue = "continue"
+	// OnFailureEscalate stops the molecule and escalates the failure.
+	OnFailureEscalate = "escalate"
+)
+
+// MaxStepRetries caps the retries a single step may declare.
+const MaxStepRetries = 10
+
+// FailureAction is what happens after a step fails.
+type FailureAction string
+
+const (
+	// FailureRetry runs the step again.
+	FailureRetry FailureAction = "retry"
+	// FailureContinue treats the step as done and moves on.
+	FailureContinue FailureAction = "continue"
+	// FailureAbort stops the molecule.
+	FailureAbort FailureAction = "abort"
+	// FailureEscalate stops the molecule and escalates.
+	FailureEscalate FailureAction = "escalate"
+)
+
+// ResolveFailure decides what to do after a step's failedAttempts-th failure.
+// A step may fail retries times and still be retried; after that its
+// on_failure policy applies.
+func ResolveFailure(retries int, onFailure string, failedAttempts int) FailureAction {
+	if failedAttempts <= retries {
+		return FailureRetry
+	}
+	switch onFailure {
+	case OnFailureContinue:
+		return FailureContinue
+	case OnFailureEscalate:
+		return FailureEscalate
+	default:
+		return FailureAbort
+	}
+}
+
+// FailureAction returns what happens after the step's failedAttempts-th failure.
+func (s *Step) FailureAction(failedAttempts int) FailureAction {
+	return ResolveFailure(s.Retries, s.OnFailure, failedAttempts)
+}
+
+// TimeoutDuration returns the step timeout, or 0 if none is set or it
+// does not parse (Validate rejects bad values).
+func (s *Step) TimeoutDuration() time.Duration {
+	if s.Timeout == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(s.Timeout)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// HasPolicy reports whether the step sets any execution policy field.
+func (s *Step) HasPolicy() bool {
+	return s.Timeout != "" || s.Retries != 0 || s.OnFailure != "" || s.When != ""
+}
+
+// validatePolicy checks the step's timeout, retries, on_failure and when fields.
+func (s *Step) validatePolicy(vars map[string]Var) error {
+	if s.Timeout != "" {
+		d, err := time.ParseDuration(s.Timeout)
+		if err != nil {
+			return fmt.Errorf("step %q has invalid timeout %q: %w", s.ID, s.Timeout, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("step %q timeout must be positive, got %q", s.ID, s.Timeout)
+		}
+	}
+	if s.Retries < 0 || s.Retries > MaxStepRetries {
+		return fmt.Errorf("step %q retries must be between 0 and %d, got %d", s.ID, MaxStepRetries, s.Retries)
+	}
+	switch s.OnFailure {
+	case "", OnFailureAbort, OnFailureContinue, OnFailureEscalate:
+	default:
+		return fmt.Errorf("step %q has invalid on_failure %q (must be continue, abort, or escalate)", s.ID, s.OnFailure)
+	}
+	if s.When != "" {
+		cond, err := ParseCondition(s.When)
+		if err != nil {
+			return fmt.Errorf("step %q: %w", s.ID, err)
+		}
+		if _, ok := vars[cond.Var]; !ok {
+			return fmt.Errorf("step %q when references undefined var %q", s.ID, cond.Var)
+		}
+	}
+	return nil
+}
+
+// Condition is a parsed step "when" expression. Supported forms:
+//
+//	var              true if var is set and not "false", "0" or "no"
+//	!var             the negation of the above
+//	var == "value"   string equality (quotes optional)
+//	var != "value"   string inequality
+type Condition struct {
+	Var    string
+	Op     string // "truthy", "falsy", "==", "!="
+	Value  string
+	source string
+}
+
+var (
+	conditionCompare = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*)\s*(==|!=)\s*(.*)$`)
+	conditionVar     = regexp.MustCompile(`^(!?)\s*([a-zA-Z_][a-zA-Z0-9_]*)$`)
+)
+
+// ParseCondition parses a step "when" expression.
+func ParseCondition(expr string) (*Condition, error) {
+	expr = strings.TrimSpace(expr)
+	if m := conditionCompare.FindStringSubmatch(expr); m != nil {
+		value := strings.TrimSpace(m[3])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+			if value[len(value)-1] != value[0] {
+				return nil, fmt.Errorf("invalid when %q: unterminated string", expr)
+			}
+			value = value[1 : len(value)-1]
+		} else if value == "" || strings.ContainsAny(value, " \t\"'") {
+			return nil, fmt.Errorf("invalid when %q: expected a value after %s", expr, m[2])
+		}
+		return &Condition{Var: m[1], Op: m[2], Value: value, source: expr}, nil
+	}
+	if m := conditionVar.FindStringSubmatch(expr); m != nil {
+		op := "truthy"
+		if m[1] == "!" {
+			op = "falsy"
+		}
+		return &Condition{Var: m[2], Op: op, source: expr}, nil
+	}
+	return nil, fmt.Errorf("invalid when %q (expected var, !var, var == \"x\" or var != \"x\")", expr)
+}
+
+// Eval evaluates the condition against variable values.
+func (c *Condition) Eval(vars map[string]string) bool {
+	value := vars[c.Var]
+	switch c.Op {
+	case "==":
+		return value == c.Value
+	case "!=":
+		return value != c.Value
+	case "falsy":
+		return !isTruthy(value)
+	default:
+		return isTruthy(value)
+	}
+}
+
+// String returns the expression the condition was parsed from.
+func (c *Condition) String() string {
+	return c.source
+}
+
+func isTruthy(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "false", "0", "no", "off":
+		return false
+	}
+	return true
+}
+
+// ResolveVars returns the formula's var defaults overlaid with the given values.
+func (f *Formula) ResolveVars(values map[string]string) map[string]string {
+	resolved := make(map[string]string, len(f.Vars)+len(values))
+	for name, v := range f.Vars {
+		resolved[name] = v.Default
+	}
+	for name, value := range values {
+		resolved[name] = value
+	}
+	return resolved
+}
+
+// StepEnabled reports whether a step's when condition holds for the given
+// var values (defaults fill in the rest). Steps without a condition, or
+// whose condition does not parse, are enabled.
+func (f *Formula) StepEnabled(id string, values map[string]string) bool {
+	step := f.GetStep(id)
+	if step == nil || step.When == "" {
+		return true
+	}
+	cond, err := ParseCondition(step.When)
+	if err != nil {
+		return true
+	}
+	return cond.Eval(f.ResolveVars(values))
+}
+
+// StepState is the execution state of a workflow molecule, keyed by step ID.
+type StepState struct {
+	Completed map[string]bool      // Steps closed successfully
+	Failures  map[string]int       // Failed attempts per step
+	Started   map[string]time.Time // When the current attempt started
+	Vars      map[string]string    // Var values the molecule was created with
+}
+
+// StepPlan is what a workflow can do next given its StepState.
+type StepPlan struct {
+	Ready     []string // Steps that can run now, including retries
+	Retry     []string // Subset of Ready that failed before and may run again
+	Skipped   []string // Steps whose when condition is false
+	Continued []string // Failed steps treated as done (on_failure=continue)
+	Overdue   []string // Started steps running past their timeout
+	Failed    []string // Steps that exhausted retries with on_failure=abort
+	Escalate  []string // Steps that exhausted retries with on_failure=escalate
+	Aborted   bool     // A failed step stopped the molecule; Ready is empty
+}
+
+// Plan works out which workflow steps are ready, skipped, overdue or failed.
+// Skipped and continued steps satisfy their dependents. A step that has
+// exhausted its retries with on_failure=abort or escalate blocks the whole
+// molecule. now is used for timeouts; the zero time disables them.
+func (f *Formula) Plan(state StepState, now time.Time) *StepPlan {
+	plan := &StepPlan{}
+	if f.Type != TypeWorkflow {
+		plan.Ready = f.ReadySteps(state.Completed)
+		return plan
+	}
+
+	order, err := f.TopologicalSort()
+	if err != nil {
+		for _, step := range f.Steps {
+			order = append(order, step.ID)
+		}
+	}
+	vars := f.ResolveVars(state.Vars)
+
+	// satisfied tracks steps that no longer hold back their dependents
+	satisfied := make(map[string]bool)
+	readySet := make(map[string]bool)
+	for _, id := range order {
+		step := f.GetStep(id)
+		needsMet := true
+		for _, need := range step.Needs {
+			if !satisfied[need] {
+				needsMet = false
+				break
+			}
+		}
+
+		if state.Completed[id] {
+			satisfied[id] = true
+			continue
+		}
+		if step.When != "" {
+			if cond, err := ParseCondition(step.When); err == nil && !cond.Eval(vars) {
+				if needsMet {
+					plan.Skipped = append(plan.Skipped, id)
+					satisfied[id] = true
+				}
+				continue
+			}
+		}
+
+		if started, ok := state.Started[id]; ok && !now.IsZero() {
+			if timeout := step.TimeoutDuration(); timeout > 0 && now.Sub(started) > timeout {
+				plan.Overdue = append(plan.Overdue, id)
+			}
+		}
+
+		if failures := state.Failures[id]; failures > 0 {
+			switch step.FailureAction(failures) {
+			case FailureContinue:
+				plan.Continued = append(plan.Continued, id)
+				satisfied[id] = true
+				continue
+			case FailureAbort:
+				plan.Failed = append(plan.Failed, id)
+				plan.Aborted = true
+				continue
+			case FailureEscalate:
+				plan.Escalate = append(plan.Escalate, id)
+				plan.Aborted = true
+				continue
+			case FailureRetry:
+				if needsMet {
+					plan.Retry = append(plan.Retry, id)
+				}
+			}
+		}
+
+		if needsMet {
+			readySet[id] = true
+		}
+	}
+
+	if plan.Aborted {
+		plan.Retry = nil
+		return plan
+	}
+	// Report ready steps in file order, as ReadySteps always has
+	for _, step := range f.Steps {
+		if readySet[step.ID] {
+			plan.Ready = append(plan.Ready, step.ID)
+		}
+	}
+	return plan
+}
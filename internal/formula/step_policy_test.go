@@ -0,0 +1,253 @@
+package formula
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const policyFormula = `
+formula = "test-policy"
+type = "workflow"
+version = 1
+
+[vars.run_tests]
+default = "true"
+
+[vars.target]
+default = "staging"
+
+[[steps]]
+id = "build"
+title = "Build"
+timeout = "30m"
+
+[[steps]]
+id = "test"
+title = "Test"
+needs = ["build"]
+when = "run_tests"
+retries = 2
+
+[[steps]]
+id = "deploy"
+title = "Deploy"
+needs = ["test"]
+when = 'target == "prod"'
+on_failure = "escalate"
+
+[[steps]]
+id = "notify"
+title = "Notify"
+needs = ["deploy"]
+on_failure = "continue"
+
+[[steps]]
+id = "report"
+title = "Report"
+needs = ["notify"]
+`
+
+func TestParse_StepPolicy(t *testing.T) {
+	f, err := Parse([]byte(policyFormula))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	build := f.GetStep("build")
+	if build.TimeoutDuration() != 30*time.Minute {
+		t.Errorf("build timeout = %v, want 30m", build.TimeoutDuration())
+	}
+	test := f.GetStep("test")
+	if test.Retries != 2 || test.When != "run_tests" {
+		t.Errorf("test step = %+v", test)
+	}
+	if f.GetStep("deploy").OnFailure != OnFailureEscalate {
+		t.Errorf("deploy on_failure = %q", f.GetStep("deploy").OnFailure)
+	}
+}
+
+func TestValidate_StepPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		wantErr string
+	}{
+		{"bad timeout", `timeout = "soon"`, "invalid timeout"},
+		{"zero timeout", `timeout = "0s"`, "must be positive"},
+		{"negative retries", `retries = -1`, "retries must be"},
+		{"too many retries", `retries = 50`, "retries must be"},
+		{"bad on_failure", `on_failure = "ignore"`, "invalid on_failure"},
+		{"bad when", `when = "a && b"`, "invalid when"},
+		{"undefined var", `when = "nope"`, "undefined var"},
+		{"ok", `when = 'mode != "fast"'`, ""},
+	}
+	for _, tt := range tests {
+		data := `
+formula = "t"
+type = "workflow"
+[vars.mode]
+default = "slow"
+[[steps]]
+id = "a"
+title = "A"
+` + tt.field + "\n"
+		_, err := Parse([]byte(data))
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	vars := map[string]string{"on": "yes", "off": "false", "env": "prod"}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"on", true},
+		{"off", false},
+		{"missing", false},
+		{"!off", true},
+		{"! on", false},
+		{`env == "prod"`, true},
+		{`env == 'dev'`, false},
+		{"env == prod", true},
+		{`env != "prod"`, false},
+		{`missing != ""`, false},
+	}
+	for _, tt := range tests {
+		cond, err := ParseCondition(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCondition(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if got := cond.Eval(vars); got != tt.want {
+			t.Errorf("%q = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "a b", `a == "x`, "a ==", "a > 1", "1a"} {
+		if _, err := ParseCondition(bad); err == nil {
+			t.Errorf("ParseCondition(%q) accepted", bad)
+		}
+	}
+}
+
+func TestResolveFailure(t *testing.T) {
+	tests := []struct {
+		retries   int
+		onFailure string
+		attempts  int
+		want      FailureAction
+	}{
+		{0, "", 1, FailureAbort},
+		{2, "", 1, FailureRetry},
+		{2, "", 2, FailureRetry},
+		{2, "", 3, FailureAbort},
+		{0, OnFailureContinue, 1, FailureContinue},
+		{1, OnFailureEscalate, 2, FailureEscalate},
+	}
+	for _, tt := range tests {
+		if got := ResolveFailure(tt.retries, tt.onFailure, tt.attempts); got != tt.want {
+			t.Errorf("ResolveFailure(%d, %q, %d) = %s, want %s", tt.retries, tt.onFailure, tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestReadySteps_SkipsFalseConditions(t *testing.T) {
+	f, err := Parse([]byte(policyFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// deploy is skipped under the defaults (target=staging), so notify
+	// becomes ready as soon as test is done.
+	ready := f.ReadySteps(map[string]bool{"build": true, "test": true})
+	if !reflect.DeepEqual(ready, []string{"notify"}) {
+		t.Errorf("ReadySteps = %v, want [notify]", ready)
+	}
+
+	// A skipped step still waits for its own needs.
+	ready = f.ReadySteps(map[string]bool{})
+	if !reflect.DeepEqual(ready, []string{"build"}) {
+		t.Errorf("ReadySteps({}) = %v, want [build]", ready)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	f, err := Parse([]byte(policyFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("vars enable steps", func(t *testing.T) {
+		plan := f.Plan(StepState{
+			Completed: map[string]bool{"build": true, "test": true},
+			Vars:      map[string]string{"target": "prod"},
+		}, now)
+		if !reflect.DeepEqual(plan.Ready, []string{"deploy"}) || len(plan.Skipped) != 0 {
+			t.Errorf("plan = %+v, want deploy ready", plan)
+		}
+
+		plan = f.Plan(StepState{Vars: map[string]string{"run_tests": "false"}, Completed: map[string]bool{"build": true}}, now)
+		if !reflect.DeepEqual(plan.Skipped, []string{"test", "deploy"}) || !reflect.DeepEqual(plan.Ready, []string{"notify"}) {
+			t.Errorf("plan = %+v, want test and deploy skipped, notify ready", plan)
+		}
+	})
+
+	t.Run("overdue", func(t *testing.T) {
+		plan := f.Plan(StepState{Started: map[string]time.Time{"build": now.Add(-time.Hour)}}, now)
+		if !reflect.DeepEqual(plan.Overdue, []string{"build"}) {
+			t.Errorf("Overdue = %v, want [build]", plan.Overdue)
+		}
+		plan = f.Plan(StepState{Started: map[string]time.Time{"build": now.Add(-time.Minute)}}, now)
+		if len(plan.Overdue) != 0 {
+			t.Errorf("Overdue = %v, want none", plan.Overdue)
+		}
+		if plan = f.Plan(StepState{Started: map[string]time.Time{"build": now.Add(-time.Hour)}}, time.Time{}); len(plan.Overdue) != 0 {
+			t.Errorf("zero now reported overdue steps: %v", plan.Overdue)
+		}
+	})
+
+	t.Run("retry then abort", func(t *testing.T) {
+		done := map[string]bool{"build": true}
+		plan := f.Plan(StepState{Completed: done, Failures: map[string]int{"test": 2}}, now)
+		if !reflect.DeepEqual(plan.Ready, []string{"test"}) || !reflect.DeepEqual(plan.Retry, []string{"test"}) {
+			t.Errorf("plan = %+v, want test retried", plan)
+		}
+		plan = f.Plan(StepState{Completed: done, Failures: map[string]int{"test": 3}}, now)
+		if !plan.Aborted || len(plan.Ready) != 0 || !reflect.DeepEqual(plan.Failed, []string{"test"}) {
+			t.Errorf("plan = %+v, want aborted on test", plan)
+		}
+	})
+
+	t.Run("escalate", func(t *testing.T) {
+		plan := f.Plan(StepState{
+			Completed: map[string]bool{"build": true, "test": true},
+			Failures:  map[string]int{"deploy": 1},
+			Vars:      map[string]string{"target": "prod"},
+		}, now)
+		if !plan.Aborted || !reflect.DeepEqual(plan.Escalate, []string{"deploy"}) {
+			t.Errorf("plan = %+v, want deploy escalated", plan)
+		}
+	})
+
+	t.Run("continue", func(t *testing.T) {
+		plan := f.Plan(StepState{
+			Completed: map[string]bool{"build": true, "test": true},
+			Failures:  map[string]int{"notify": 1},
+		}, now)
+		if plan.Aborted || !reflect.DeepEqual(plan.Continued, []string{"notify"}) || !reflect.DeepEqual(plan.Ready, []string{"report"}) {
+			t.Errorf("plan = %+v, want notify continued and report ready", plan)
+		}
+	})
+}
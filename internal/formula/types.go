@@ -94,6 +94,12 @@ type Step struct {
 	Description string   `toml:"description"`
 	Needs       []string `toml:"needs"`
 	Parallel    bool     `toml:"parallel"` // If true, this step can run concurrently with other parallel steps that share the same needs
+
+	// Execution policy (all optional)
+	Timeout   string `toml:"timeout"`    // Go duration ("30m"); the witness escalates steps running longer
+	Retries   int    `toml:"retries"`    // Extra attempts allowed after a failure before on_failure applies
+	OnFailure string `toml:"on_failure"` // "continue", "abort" (default) or "escalate"
+	When      string `toml:"when"`       // Condition over formula vars; the step is skipped when false
 }
 
 // Template represents a template step in an expansion formula.
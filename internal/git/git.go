@@ -553,6 +553,13 @@ func (g *Git) MergeSquash(branch, message string) error {
 	return err
 }
 
+// MergeFFOnly fast-forwards the current branch to the given branch.
+// Fails if the current branch has diverged and a real merge would be required.
+func (g *Git) MergeFFOnly(branch string) error {
+	_, err := g.run("merge", "--ff-only", branch)
+	return err
+}
+
 // GetBranchCommitMessage returns the commit message of the HEAD commit on the given branch.
 // This is useful for preserving the original conventional commit message (feat:/fix:) when
 // performing squash merges.
@@ -739,6 +746,13 @@ func (g *Git) ResetBranch(name, ref string) error {
 	return err
 }
 
+// ResetHard resets the current branch and working tree to the given ref,
+// discarding any staged or unstaged changes.
+func (g *Git) ResetHard(ref string) error {
+	_, err := g.run("reset", "--hard", ref)
+	return err
+}
+
 // Rev returns the commit hash for the given ref.
 func (g *Git) Rev(ref string) (string, error) {
 	return g.run("rev-parse", ref)
@@ -0,0 +1,88 @@
+package headless
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// DetachKey ends an attach without stopping the session (Ctrl-]).
+const DetachKey = 0x1d
+
+// Attach connects the terminal to a running session until the user presses
+// Ctrl-] or the session ends. When in is a terminal it is put in raw mode
+// and the session is resized to match it.
+func (b *Backend) Attach(name string, in *os.File, out io.Writer) error {
+	conn, err := net.DialTimeout("unix", b.sockPath(name), dialTimeout)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, name)
+	}
+	defer conn.Close()
+
+	req := request{Op: "attach"}
+	fd := int(in.Fd())
+	isTerm := term.IsTerminal(fd)
+	if isTerm {
+		if cols, rows, err := term.GetSize(fd); err == nil {
+			req.Rows, req.Cols = uint16(rows), uint16(cols) //nolint:gosec // G115: terminal sizes fit in uint16
+		}
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return fmt.Errorf("attaching to %s: %w", name, err)
+	}
+	r := bufio.NewReader(conn)
+	if err := readResponse(r, name, "attach"); err != nil {
+		return err
+	}
+
+	if isTerm {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("setting raw mode: %w", err)
+		}
+		defer term.Restore(fd, state) //nolint:errcheck // best-effort restore
+	}
+	fmt.Fprintf(out, "[attached to %s — Ctrl-] to detach]\r\n", name)
+
+	ended := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(out, r)
+		close(ended)
+	}()
+
+	detached := make(chan struct{})
+	go func() {
+		defer close(detached)
+		buf := make([]byte, 1024)
+		for {
+			n, err := in.Read(buf)
+			if n > 0 {
+				chunk := buf[:n]
+				if i := bytes.IndexByte(chunk, DetachKey); i >= 0 {
+					_, _ = conn.Write(chunk[:i])
+					return
+				}
+				if _, err := conn.Write(chunk); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-ended:
+		fmt.Fprintf(out, "\r\n[session %s ended]\r\n", name)
+	case <-detached:
+		fmt.Fprintf(out, "\r\n[detached from %s]\r\n", name)
+	}
+	return nil
+}
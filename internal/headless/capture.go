@@ -0,0 +1,72 @@
+package headless
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// captureTail bounds how much of the scrollback log CapturePane reads.
+const captureTail = 256 << 10
+
+// ansiRe matches terminal escape sequences: CSI (colors, cursor movement),
+// OSC (titles, hyperlinks), charset selection and short escapes.
+var ansiRe = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[()][0-9A-Za-z]|\x1b[=>78DEHMc]`)
+
+// captureLog returns the last n lines of a scrollback log as plain text,
+// reading the rotated log too when the current one is short.
+func captureLog(path string, n int) (string, error) {
+	data, err := readTail(path, captureTail)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < captureTail {
+		if prev, err := readTail(path+".1", captureTail-int64(len(data))); err == nil {
+			data = append(prev, data...)
+		}
+	}
+
+	lines := strings.Split(plainText(string(data)), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// plainText strips escape sequences and carriage returns from terminal
+// output. A line redrawn after a bare \r keeps only its last rendering.
+func plainText(s string) string {
+	s = ansiRe.ReplaceAllString(s, "")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if idx := strings.LastIndex(line, "\r"); idx >= 0 {
+			lines[i] = line[idx+1:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// readTail returns up to the last max bytes of a file.
+func readTail(path string, max int64) ([]byte, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > max {
+		if _, err := f.Seek(info.Size()-max, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(f)
+}
@@ -0,0 +1,366 @@
+// Package headless hosts agent sessions without tmux.
+//
+// Each session runs under a pseudo-terminal owned by a small host process
+// (gt re-executed with GT_HEADLESS_HOST set). The host logs everything the
+// agent prints to a scrollback file and serves send-keys, kill, environment,
+// respawn and attach requests on a unix socket. This lets towns run in
+// containers and CI where tmux is not installed.
+//
+// Session files live in one directory (GT_HEADLESS_DIR, by default
+// $TMPDIR/gt-headless-<uid>), which must be owned by the user and closed to
+// everyone else:
+//
+//	<name>.sock  control and attach socket
+//	<name>.log   scrollback log (rotated to <name>.log.1)
+//	<name>.json  session metadata
+package headless
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+const (
+	// envHost is set when gt is re-executed as a session host; its value
+	// is the session name.
+	envHost = "GT_HEADLESS_HOST"
+
+	// EnvDir overrides the directory holding session sockets and logs.
+	EnvDir = "GT_HEADLESS_DIR"
+)
+
+// Errors are shared with the tmux backend so callers can check either
+// with errors.Is.
+var (
+	ErrSessionExists      = tmux.ErrSessionExists
+	ErrSessionNotFound    = tmux.ErrSessionNotFound
+	ErrInvalidSessionName = tmux.ErrInvalidSessionName
+)
+
+// validSessionNameRe matches the names tmux accepts, so a town can switch
+// backends without renaming sessions.
+var validSessionNameRe = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Timeouts for talking to session hosts. Variables so tests can shorten them.
+var (
+	dialTimeout  = 2 * time.Second
+	callTimeout  = 10 * time.Second
+	startTimeout = 5 * time.Second
+)
+
+// sendKeysDebounce matches the tmux backend's delay between pasting text
+// and pressing Enter.
+const sendKeysDebounce = 100 * time.Millisecond
+
+// request is a control message sent to a session host, one JSON line per
+// connection. After a successful "attach" the connection carries raw
+// terminal input and output.
+type request struct {
+	Op    string `json:"op"` // send, kill, setenv, respawn, attach, ping
+	Keys  string `json:"keys,omitempty"`
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value,omitempty"`
+	Rows  uint16 `json:"rows,omitempty"`
+	Cols  uint16 `json:"cols,omitempty"`
+}
+
+// response answers a request.
+type response struct {
+	Error string `json:"error,omitempty"`
+}
+
+// sessionMeta describes a session. The client writes it before starting the
+// host; the host keeps it current (environment, respawn script, PIDs).
+type sessionMeta struct {
+	Name    string            `json:"name"`
+	WorkDir string            `json:"work_dir,omitempty"`
+	Command string            `json:"command,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	Respawn string            `json:"respawn,omitempty"` // Script run when the agent exits
+	HostPID int               `json:"host_pid,omitempty"`
+	PID     int               `json:"pid,omitempty"` // Current agent process
+	Started time.Time         `json:"started"`
+}
+
+// Backend manages headless sessions in a directory. It implements the same
+// session operations as tmux.Tmux.
+type Backend struct {
+	dir string
+}
+
+// New returns a backend using DefaultDir.
+func New() *Backend {
+	return NewWithDir(DefaultDir())
+}
+
+// NewWithDir returns a backend keeping its sessions in dir.
+func NewWithDir(dir string) *Backend {
+	return &Backend{dir: dir}
+}
+
+// DefaultDir returns GT_HEADLESS_DIR, or a per-user directory under the
+// system temp dir (kept short: unix socket paths are length-limited).
+func DefaultDir() string {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gt-headless-%d", os.Getuid()))
+}
+
+// Dir returns the directory holding the backend's sessions.
+func (b *Backend) Dir() string {
+	return b.dir
+}
+
+// ensureDir creates the session directory and checks that only this user
+// can reach it. The default path is predictable, so, like tmux, refuse a
+// directory another user created or opened up rather than trust its sockets.
+func (b *Backend) ensureDir() error {
+	if err := os.MkdirAll(b.dir, 0700); err != nil {
+		return fmt.Errorf("creating headless session directory: %w", err)
+	}
+	return b.checkDir()
+}
+
+// checkDir reports an error unless the session directory is a real directory
+// owned by this user with no group or other permissions.
+func (b *Backend) checkDir() error {
+	fi, err := os.Lstat(b.dir)
+	if err != nil {
+		return fmt.Errorf("checking headless session directory: %w", err)
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("headless session directory %s is not a directory", b.dir)
+	}
+	if uid, ok := fileOwner(fi); ok && uid != os.Getuid() {
+		return fmt.Errorf("headless session directory %s is owned by uid %d, not %d", b.dir, uid, os.Getuid())
+	}
+	if perm := fi.Mode().Perm(); perm&0077 != 0 {
+		return fmt.Errorf("headless session directory %s is accessible by other users (mode %v)", b.dir, perm)
+	}
+	return nil
+}
+
+func (b *Backend) sockPath(name string) string { return filepath.Join(b.dir, name+".sock") }
+func (b *Backend) metaPath(name string) string { return filepath.Join(b.dir, name+".json") }
+
+// LogPath returns the scrollback log of a session.
+func (b *Backend) LogPath(name string) string { return filepath.Join(b.dir, name+".log") }
+
+// NewSessionWithCommand starts a session running command in workDir. An
+// empty command starts the user's shell.
+func (b *Backend) NewSessionWithCommand(name, workDir, command string) error {
+	if !supported {
+		return errors.New("headless sessions are not supported on this platform")
+	}
+	if name == "" || !validSessionNameRe.MatchString(name) {
+		return fmt.Errorf("%w %q: must match %s", ErrInvalidSessionName, name, validSessionNameRe.String())
+	}
+	if running, _ := b.HasSession(name); running {
+		return ErrSessionExists
+	}
+	if err := b.ensureDir(); err != nil {
+		return err
+	}
+
+	meta := &sessionMeta{Name: name, WorkDir: workDir, Command: command, Started: time.Now()}
+	if err := writeMeta(b.metaPath(name), meta); err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating gt executable: %w", err)
+	}
+	logFile, err := os.Create(b.LogPath(name))
+	if err != nil {
+		return fmt.Errorf("creating scrollback log: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := newHostCommand(exe, b.dir, name, logFile)
+	if err := cmd.Start(); err != nil {
+		_ = os.Remove(b.metaPath(name))
+		return fmt.Errorf("starting session host: %w", err)
+	}
+	hostDone := make(chan error, 1)
+	go func() { hostDone <- cmd.Wait() }()
+
+	// Wait for the host to accept connections. A host that exits cleanly
+	// first ran an agent that exited at once; like tmux, that still counts
+	// as a started session.
+	deadline := time.Now().Add(startTimeout)
+	for time.Now().Before(deadline) {
+		if err := b.call(name, request{Op: "ping"}); err == nil {
+			return nil
+		}
+		select {
+		case err := <-hostDone:
+			if err != nil {
+				return fmt.Errorf("session host for %s failed: %w (see %s)", name, err, b.LogPath(name))
+			}
+			return nil
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	return fmt.Errorf("session host for %s did not start (see %s)", name, b.LogPath(name))
+}
+
+// KillSessionWithProcesses terminates the agent's process group (SIGTERM,
+// then SIGKILL after a grace period) and ends the session.
+func (b *Backend) KillSessionWithProcesses(name string) error {
+	if err := b.call(name, request{Op: "kill"}); err != nil {
+		return err
+	}
+	// The host removes its socket as it exits; wait so the name can be reused
+	deadline := time.Now().Add(startTimeout)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(b.sockPath(name)); os.IsNotExist(err) {
+			return nil
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return nil
+}
+
+// SendKeys types keys into the session and presses Enter.
+func (b *Backend) SendKeys(session, keys string) error {
+	if err := b.call(session, request{Op: "send", Keys: keys}); err != nil {
+		return err
+	}
+	time.Sleep(sendKeysDebounce)
+	return b.call(session, request{Op: "send", Keys: "\r"})
+}
+
+// CapturePane returns the last lines of the session's scrollback, with
+// terminal escape sequences removed.
+func (b *Backend) CapturePane(session string, lines int) (string, error) {
+	if running, err := b.HasSession(session); err != nil || !running {
+		return "", fmt.Errorf("%w: %s", ErrSessionNotFound, session)
+	}
+	return captureLog(b.LogPath(session), lines)
+}
+
+// HasSession reports whether a session is running. Sockets left behind by
+// a host that died are cleaned up.
+func (b *Backend) HasSession(name string) (bool, error) {
+	sock := b.sockPath(name)
+	if _, err := os.Stat(sock); err != nil {
+		return false, nil
+	}
+	conn, err := net.DialTimeout("unix", sock, dialTimeout)
+	if err != nil {
+		_ = os.Remove(sock)
+		_ = os.Remove(b.metaPath(name))
+		return false, nil
+	}
+	_ = conn.Close()
+	return true, nil
+}
+
+// ListSessions returns the names of running sessions, sorted.
+func (b *Backend) ListSessions() ([]string, error) {
+	socks, err := filepath.Glob(filepath.Join(b.dir, "*.sock"))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, sock := range socks {
+		name := strings.TrimSuffix(filepath.Base(sock), ".sock")
+		if running, _ := b.HasSession(name); running {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// SetEnvironment sets a variable for processes the session starts from now
+// on (respawns), like tmux set-environment.
+func (b *Backend) SetEnvironment(session, key, value string) error {
+	return b.call(session, request{Op: "setenv", Key: key, Value: value})
+}
+
+// SetAutoRespawnHook makes the session restart the agent with startupCmd
+// whenever it exits. As with tmux, the command is written to a script under
+// <townRoot>/.runtime/respawn so respawns pick up the current agent config.
+func (b *Backend) SetAutoRespawnHook(session, townRoot, startupCmd string) error {
+	respawnDir := filepath.Join(townRoot, ".runtime", "respawn")
+	if err := os.MkdirAll(respawnDir, 0755); err != nil {
+		return fmt.Errorf("creating respawn directory: %w", err)
+	}
+	scriptPath := filepath.Join(respawnDir, session+".sh")
+	scriptContent := "#!/bin/sh\n# Auto-generated by gt — do not edit.\n# This file is used by the headless session host to respawn with current agent config.\nexec " + startupCmd + "\n"
+	if err := os.WriteFile(scriptPath, []byte(scriptContent), 0755); err != nil { //nolint:gosec // G306: script must be executable
+		return fmt.Errorf("writing respawn script: %w", err)
+	}
+	return b.call(session, request{Op: "respawn", Value: scriptPath})
+}
+
+// call sends req to the session's host and waits for its response.
+func (b *Backend) call(name string, req request) error {
+	conn, err := net.DialTimeout("unix", b.sockPath(name), dialTimeout)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, name)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(callTimeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return fmt.Errorf("sending %s to %s: %w", req.Op, name, err)
+	}
+	return readResponse(bufio.NewReader(conn), name, req.Op)
+}
+
+// readResponse reads one response line from a host.
+func readResponse(r *bufio.Reader, name, op string) error {
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("reading %s response from %s: %w", op, name, err)
+	}
+	var resp response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return fmt.Errorf("parsing %s response from %s: %w", op, name, err)
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("%s %s: %s", op, name, resp.Error)
+	}
+	return nil
+}
+
+// readMeta loads a session's metadata.
+func readMeta(path string) (*sessionMeta, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	var meta sessionMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("parsing session metadata: %w", err)
+	}
+	return &meta, nil
+}
+
+// writeMeta saves a session's metadata atomically.
+func writeMeta(path string, meta *sessionMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("writing session metadata: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
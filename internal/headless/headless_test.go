@@ -0,0 +1,258 @@
+package headless
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestMain lets the test binary act as a session host, as gt does.
+func TestMain(m *testing.M) {
+	if IsHost() {
+		if os.Getenv("GT_HEADLESS_TEST_FAST_RESPAWN") != "" {
+			respawnDelay = 0
+		}
+		os.Exit(RunHost())
+	}
+	os.Exit(m.Run())
+}
+
+func newTestBackend(t *testing.T) *Backend {
+	t.Helper()
+	if !supported {
+		t.Skip("headless sessions are not supported on this platform")
+	}
+	dir, err := os.MkdirTemp("", "gth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewWithDir(dir)
+	t.Cleanup(func() {
+		names, _ := b.ListSessions()
+		for _, name := range names {
+			_ = b.KillSessionWithProcesses(name)
+		}
+		_ = os.RemoveAll(dir)
+	})
+	return b
+}
+
+// waitForOutput polls the session's scrollback until it contains want.
+func waitForOutput(t *testing.T, b *Backend, name, want string) string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	var out string
+	for time.Now().Before(deadline) {
+		out, _ = b.CapturePane(name, 50)
+		if strings.Contains(out, want) {
+			return out
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("session %s never printed %q; got:\n%s", name, want, out)
+	return ""
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	b := newTestBackend(t)
+	workDir := t.TempDir()
+
+	if err := b.NewSessionWithCommand("gt-test-agent", workDir, "echo started in $(pwd); cat"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	if err := b.NewSessionWithCommand("gt-test-agent", workDir, "cat"); err != ErrSessionExists {
+		t.Errorf("second NewSessionWithCommand err = %v, want ErrSessionExists", err)
+	}
+
+	waitForOutput(t, b, "gt-test-agent", "started in "+workDir)
+
+	// cat echoes input back through the pty
+	if err := b.SendKeys("gt-test-agent", "hello headless"); err != nil {
+		t.Fatalf("SendKeys: %v", err)
+	}
+	waitForOutput(t, b, "gt-test-agent", "hello headless")
+
+	names, err := b.ListSessions()
+	if err != nil || len(names) != 1 || names[0] != "gt-test-agent" {
+		t.Errorf("ListSessions = %v, %v", names, err)
+	}
+
+	if err := b.KillSessionWithProcesses("gt-test-agent"); err != nil {
+		t.Fatalf("KillSessionWithProcesses: %v", err)
+	}
+	if running, _ := b.HasSession("gt-test-agent"); running {
+		t.Error("session still running after kill")
+	}
+
+	// The scrollback log outlives the session
+	data, err := os.ReadFile(b.LogPath("gt-test-agent"))
+	if err != nil || !strings.Contains(string(data), "hello headless") {
+		t.Errorf("scrollback log = %q, %v", data, err)
+	}
+}
+
+func TestSessionRespawn(t *testing.T) {
+	b := newTestBackend(t)
+	townRoot := t.TempDir()
+
+	t.Setenv("GT_HEADLESS_TEST_FAST_RESPAWN", "1") // inherited by the host
+
+	if err := b.NewSessionWithCommand("gt-test-respawn", "", "read line; echo first run done"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	if err := b.SetEnvironment("gt-test-respawn", "GT_ROLE", "witness"); err != nil {
+		t.Fatalf("SetEnvironment: %v", err)
+	}
+	if err := b.SetAutoRespawnHook("gt-test-respawn", townRoot, `sh -c 'echo respawned as $GT_ROLE; sleep 60'`); err != nil {
+		t.Fatalf("SetAutoRespawnHook: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, ".runtime", "respawn", "gt-test-respawn.sh")); err != nil {
+		t.Errorf("respawn script not written: %v", err)
+	}
+
+	// End the first run; the host restarts the agent with the new environment
+	if err := b.SendKeys("gt-test-respawn", ""); err != nil {
+		t.Fatalf("SendKeys: %v", err)
+	}
+	waitForOutput(t, b, "gt-test-respawn", "respawned as witness")
+
+	if running, _ := b.HasSession("gt-test-respawn"); !running {
+		t.Error("session ended instead of respawning")
+	}
+}
+
+func TestSessionEndsWithAgent(t *testing.T) {
+	b := newTestBackend(t)
+
+	if err := b.NewSessionWithCommand("gt-test-exit", "", "exit 0"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if running, _ := b.HasSession("gt-test-exit"); !running {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Error("session without a respawn hook should end when the agent exits")
+}
+
+func TestHasSessionCleansStaleSocket(t *testing.T) {
+	b := newTestBackend(t)
+	if err := os.MkdirAll(b.Dir(), 0700); err != nil {
+		t.Fatal(err)
+	}
+	sock := b.sockPath("gt-test-stale")
+	if err := os.WriteFile(sock, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if running, err := b.HasSession("gt-test-stale"); running || err != nil {
+		t.Errorf("HasSession = %v, %v; want false", running, err)
+	}
+	if _, err := os.Stat(sock); !os.IsNotExist(err) {
+		t.Error("stale socket not removed")
+	}
+	if err := b.SendKeys("gt-test-stale", "x"); err == nil {
+		t.Error("SendKeys to a missing session should fail")
+	}
+}
+
+func TestInvalidSessionName(t *testing.T) {
+	b := newTestBackend(t)
+	if err := b.NewSessionWithCommand("bad/name", "", "true"); err == nil {
+		t.Error("NewSessionWithCommand accepted an invalid name")
+	}
+}
+
+func TestRejectsSharedSessionDir(t *testing.T) {
+	b := newTestBackend(t)
+	if err := os.Chmod(b.Dir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.NewSessionWithCommand("shared", "", "sleep 30"); err == nil {
+		t.Fatal("NewSessionWithCommand used a directory other users can read")
+	}
+	if _, err := os.Stat(b.metaPath("shared")); !os.IsNotExist(err) {
+		t.Errorf("session metadata written to a shared directory: %v", err)
+	}
+
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Chmod(b.Dir(), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(b.Dir(), link); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewWithDir(link).NewSessionWithCommand("linked", "", "sleep 30"); err == nil {
+		t.Error("NewSessionWithCommand followed a symlinked session directory")
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes Attach makes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.String()
+}
+
+func TestAttach(t *testing.T) {
+	b := newTestBackend(t)
+	if err := b.NewSessionWithCommand("gt-test-attach", "", "cat"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+
+	in, inW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	out := &syncBuffer{}
+
+	done := make(chan error, 1)
+	go func() { done <- b.Attach("gt-test-attach", in, out) }()
+
+	// Input typed while attached reaches the agent
+	_, _ = inW.Write([]byte("typed while attached\r"))
+	waitForOutput(t, b, "gt-test-attach", "typed while attached")
+
+	_, _ = inW.Write([]byte{DetachKey})
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Attach: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Attach did not return after the detach key")
+	}
+	if !strings.Contains(out.String(), "typed while attached") || !strings.Contains(out.String(), "[detached from gt-test-attach]") {
+		t.Errorf("attach output = %q", out.String())
+	}
+
+	// Detaching leaves the session running
+	if running, _ := b.HasSession("gt-test-attach"); !running {
+		t.Error("session ended on detach")
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	in := "\x1b[1;32mgreen\x1b[0m text\r\n\x1b]0;title\x07progress 10%\rprogress 100%\n\x1b(Bdone"
+	want := "green text\nprogress 100%\ndone"
+	if got := plainText(in); got != want {
+		t.Errorf("plainText = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,371 @@
+package headless
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Host tuning. Variables so tests can shorten them.
+var (
+	// respawnDelay matches the tmux pane-died hook's sleep, so an agent
+	// that crashes on start does not respawn in a tight loop.
+	respawnDelay = 3 * time.Second
+
+	// killGrace is how long the agent gets after SIGTERM before SIGKILL.
+	killGrace = 2 * time.Second
+)
+
+// maxLogSize is the scrollback log size at which it is rotated to .log.1.
+const maxLogSize = 8 << 20
+
+// Default terminal size until a client attaches.
+const (
+	defaultRows = 50
+	defaultCols = 200
+)
+
+// IsHost reports whether this process was started as a session host.
+// gt's main checks it before running the CLI.
+func IsHost() bool {
+	return os.Getenv(envHost) != ""
+}
+
+// newHostCommand returns the command that runs exe as the host of session
+// name, detached from the caller. Host errors go to stderr.
+func newHostCommand(exe, dir, name string, stderr *os.File) *exec.Cmd {
+	cmd := exec.Command(exe) //nolint:gosec // G204: re-executes our own binary
+	cmd.Env = append(os.Environ(), envHost+"="+name, EnvDir+"="+dir)
+	cmd.Stderr = stderr
+	detach(cmd)
+	return cmd
+}
+
+// host owns one session: the pty, the agent process and attached clients.
+type host struct {
+	b    *Backend
+	name string
+
+	master, slave *os.File
+	log           *os.File
+	logSize       int64
+
+	mu      sync.Mutex
+	meta    *sessionMeta
+	pid     int
+	exited  chan struct{} // closed when the current agent process exits
+	killing bool
+	clients map[chan []byte]struct{}
+
+	// replies tracks kill requests still answering, so the host does not
+	// exit before telling the caller the session is gone.
+	replies sync.WaitGroup
+}
+
+// RunHost runs the session host named by GT_HEADLESS_HOST until the session
+// ends, and returns the process exit code.
+func RunHost() int {
+	name := os.Getenv(envHost)
+	b := NewWithDir(DefaultDir())
+	if err := runHost(b, name); err != nil {
+		fmt.Fprintf(os.Stderr, "headless host %s: %v\n", name, err)
+		return 1
+	}
+	return 0
+}
+
+func runHost(b *Backend, name string) error {
+	if err := b.checkDir(); err != nil {
+		return err
+	}
+	meta, err := readMeta(b.metaPath(name))
+	if err != nil {
+		return fmt.Errorf("reading session metadata: %w", err)
+	}
+
+	master, slave, err := openPTY()
+	if err != nil {
+		return err
+	}
+	_ = setWinsize(master, defaultRows, defaultCols)
+
+	// The client truncated the log and routed our stderr into it
+	log, err := os.OpenFile(b.LogPath(name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("opening scrollback log: %w", err)
+	}
+
+	sock := b.sockPath(name)
+	_ = os.Remove(sock)
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", sock, err)
+	}
+
+	h := &host{
+		b:       b,
+		name:    name,
+		master:  master,
+		slave:   slave,
+		log:     log,
+		meta:    meta,
+		clients: make(map[chan []byte]struct{}),
+	}
+	defer h.shutdown(ln)
+
+	command := meta.Command
+	if command == "" {
+		command = "exec ${SHELL:-/bin/sh}"
+	}
+	if err := h.startAgent(command); err != nil {
+		return err
+	}
+
+	go h.pump()
+	go h.serve(ln)
+
+	for {
+		h.mu.Lock()
+		exited := h.exited
+		h.mu.Unlock()
+		<-exited
+
+		h.mu.Lock()
+		script := h.meta.Respawn
+		stop := h.killing || script == ""
+		h.mu.Unlock()
+		if stop {
+			return nil
+		}
+
+		time.Sleep(respawnDelay)
+		h.mu.Lock()
+		stop = h.killing
+		h.mu.Unlock()
+		if stop {
+			return nil
+		}
+		if err := h.startAgent("exec " + shellQuote(script)); err != nil {
+			return fmt.Errorf("respawning agent: %w", err)
+		}
+	}
+}
+
+// startAgent runs command through the shell on the pty slave.
+func (h *host) startAgent(command string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cmd := exec.Command("/bin/sh", "-c", command) //nolint:gosec // G204: command is the agent startup command
+	cmd.Dir = h.meta.WorkDir
+	cmd.Env = append(os.Environ(), "TERM=xterm-256color")
+	for k, v := range h.meta.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	attachTTY(cmd, h.slave)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting agent: %w", err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	h.pid = cmd.Process.Pid
+	h.exited = exited
+	h.meta.PID = h.pid
+	h.meta.HostPID = os.Getpid()
+	return writeMeta(h.b.metaPath(h.name), h.meta)
+}
+
+// pump copies agent output to the scrollback log and attached clients.
+func (h *host) pump() {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := h.master.Read(buf)
+		if n > 0 {
+			chunk := append([]byte(nil), buf[:n]...)
+			h.writeLog(chunk)
+			h.mu.Lock()
+			for c := range h.clients {
+				select {
+				case c <- chunk:
+				default: // Slow client: drop output rather than stall the agent
+				}
+			}
+			h.mu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// writeLog appends to the scrollback log, rotating it at maxLogSize.
+func (h *host) writeLog(p []byte) {
+	if h.logSize+int64(len(p)) > maxLogSize {
+		path := h.b.LogPath(h.name)
+		_ = h.log.Close()
+		_ = os.Rename(path, path+".1")
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed internally
+		if err != nil {
+			return
+		}
+		h.log = f
+		h.logSize = 0
+	}
+	n, _ := h.log.Write(p)
+	h.logSize += int64(n)
+}
+
+// serve answers control requests until the listener is closed.
+func (h *host) serve(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go h.handle(conn)
+	}
+}
+
+func (h *host) handle(conn net.Conn) {
+	// Read exactly one line: after an attach the rest of the stream is
+	// terminal input
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	var req request
+	if err != nil || json.Unmarshal(line, &req) != nil {
+		_ = conn.Close()
+		return
+	}
+
+	switch req.Op {
+	case "ping":
+	case "send":
+		_, err = h.master.Write([]byte(req.Keys))
+	case "setenv":
+		h.mu.Lock()
+		if h.meta.Env == nil {
+			h.meta.Env = make(map[string]string)
+		}
+		h.meta.Env[req.Key] = req.Value
+		err = writeMeta(h.b.metaPath(h.name), h.meta)
+		h.mu.Unlock()
+	case "respawn":
+		h.mu.Lock()
+		h.meta.Respawn = req.Value
+		err = writeMeta(h.b.metaPath(h.name), h.meta)
+		h.mu.Unlock()
+	case "kill":
+		h.replies.Add(1)
+		defer h.replies.Done()
+		h.kill()
+	case "attach":
+		h.attach(conn, r, req)
+		return
+	default:
+		err = fmt.Errorf("unknown op %q", req.Op)
+	}
+
+	reply(conn, err)
+	_ = conn.Close()
+}
+
+// kill ends the agent's process group and stops respawns.
+func (h *host) kill() {
+	h.mu.Lock()
+	h.killing = true
+	pid, exited := h.pid, h.exited
+	h.mu.Unlock()
+
+	signalGroup(pid, syscall.SIGTERM)
+	select {
+	case <-exited:
+	case <-time.After(killGrace):
+		signalGroup(pid, syscall.SIGKILL)
+		<-exited
+	}
+}
+
+// attach streams the terminal to conn until either side closes.
+func (h *host) attach(conn net.Conn, r *bufio.Reader, req request) {
+	defer conn.Close()
+	if req.Rows > 0 && req.Cols > 0 {
+		_ = setWinsize(h.master, req.Rows, req.Cols)
+	}
+
+	out := make(chan []byte, 256)
+	h.mu.Lock()
+	h.clients[out] = struct{}{}
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		delete(h.clients, out)
+		h.mu.Unlock()
+	}()
+
+	reply(conn, nil)
+
+	done := make(chan struct{})
+	go func() {
+		// Client input goes to the agent
+		_, _ = io.Copy(h.master, r)
+		close(done)
+	}()
+	for {
+		select {
+		case chunk, ok := <-out:
+			if !ok {
+				return
+			}
+			if _, err := conn.Write(chunk); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// shutdown disconnects clients and removes the socket and metadata. The
+// scrollback log stays for post-mortems.
+func (h *host) shutdown(ln net.Listener) {
+	h.replies.Wait()
+	_ = ln.Close()
+	_ = os.Remove(h.b.sockPath(h.name))
+	_ = os.Remove(h.b.metaPath(h.name))
+
+	h.mu.Lock()
+	for c := range h.clients {
+		close(c)
+		delete(h.clients, c)
+	}
+	h.mu.Unlock()
+
+	_ = h.slave.Close()
+	_ = h.master.Close()
+	_ = h.log.Close()
+}
+
+func reply(conn net.Conn, err error) {
+	var resp response
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+// shellQuote quotes s for /bin/sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
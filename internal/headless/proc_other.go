@@ -0,0 +1,23 @@
+//go:build !linux && !darwin
+
+package headless
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// supported reports whether headless sessions can run on this platform.
+const supported = false
+
+func openPTY() (master, slave *os.File, err error) {
+	return nil, nil, errors.New("headless sessions are not supported on this platform")
+}
+
+func detach(_ *exec.Cmd)                       {}
+func attachTTY(_ *exec.Cmd, _ *os.File)        {}
+func signalGroup(_ int, _ syscall.Signal)      {}
+func setWinsize(_ *os.File, _, _ uint16) error { return nil }
+func fileOwner(_ os.FileInfo) (int, bool)      { return 0, false }
@@ -0,0 +1,41 @@
+//go:build darwin
+
+package headless
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY opens a new pseudo-terminal and returns its master and slave ends.
+func openPTY() (master, slave *os.File, err error) {
+	m, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening /dev/ptmx: %w", err)
+	}
+	fd := int(m.Fd())
+	if err := unix.IoctlSetInt(fd, unix.TIOCPTYGRANT, 0); err != nil {
+		_ = m.Close()
+		return nil, nil, fmt.Errorf("granting pty: %w", err)
+	}
+	if err := unix.IoctlSetInt(fd, unix.TIOCPTYUNLK, 0); err != nil {
+		_ = m.Close()
+		return nil, nil, fmt.Errorf("unlocking pty: %w", err)
+	}
+	buf := make([]byte, 128)
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), uintptr(unix.TIOCPTYGNAME), uintptr(unsafe.Pointer(&buf[0]))); errno != 0 {
+		_ = m.Close()
+		return nil, nil, fmt.Errorf("getting pty name: %w", errno)
+	}
+	name := string(buf[:bytes.IndexByte(buf, 0)])
+	s, err := os.OpenFile(name, os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		_ = m.Close()
+		return nil, nil, fmt.Errorf("opening pty slave: %w", err)
+	}
+	return m, s, nil
+}
@@ -0,0 +1,233 @@
+// Package hooklog records hook activity: every time work is attached to,
+// detached from or cleared off an agent's hook.
+//
+// Entries are appended as JSON lines to <town>/logs/hooks.jsonl. The file is
+// rotated when it grows past MaxBytes, keeping Backups older files
+// (hooks.jsonl.1 is the most recent), so the log answers "who had this bead
+// and when" without reconstructing history from git.
+package hooklog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// Hook actions.
+const (
+	// ActionAttach is work placed on a hook (gt hook, gt sling, gt handoff, gt mol attach).
+	ActionAttach = "attach"
+	// ActionDetach is work taken off a hook (gt unsling, gt mol detach).
+	ActionDetach = "detach"
+	// ActionClear is a hook emptied without a specific bead (gt hook clear, stale cleanup).
+	ActionClear = "clear"
+)
+
+// FileName is the hook activity log's name inside the town logs directory.
+const FileName = "hooks.jsonl"
+
+// Rotation defaults.
+const (
+	DefaultMaxBytes = 5 << 20 // 5 MiB
+	DefaultBackups  = 3
+)
+
+// Entry is one hook change.
+type Entry struct {
+	Timestamp time.Time `json:"ts"`
+	Action    string    `json:"action"`
+	Command   string    `json:"command,omitempty"` // e.g. "gt sling"
+	Actor     string    `json:"actor"`             // Who made the change
+	Agent     string    `json:"agent,omitempty"`   // Whose hook changed
+	Rig       string    `json:"rig,omitempty"`
+	Bead      string    `json:"bead,omitempty"`
+	Molecule  string    `json:"molecule,omitempty"`
+	// Previous is what occupied the slot before: the bead previously on the
+	// hook, the molecule previously attached, or the agent that held the bead
+	// before a forced re-sling.
+	Previous string `json:"previous,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Log is a rotating hook activity log.
+type Log struct {
+	path     string
+	MaxBytes int64
+	Backups  int
+}
+
+// New returns the hook activity log for a town.
+func New(townRoot string) *Log {
+	return &Log{
+		path:     filepath.Join(townRoot, "logs", FileName),
+		MaxBytes: DefaultMaxBytes,
+		Backups:  DefaultBackups,
+	}
+}
+
+// Path returns the current log file path.
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append writes an entry, rotating the file first if it would grow past
+// MaxBytes. Timestamp and Rig are filled in when empty.
+func (l *Log) Append(e Entry) error {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+	if e.Rig == "" {
+		e.Rig = RigFromAgent(e.Agent)
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshaling hook entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+
+	// Several gt processes append concurrently; serialize writes and rotation
+	fl := flock.New(l.path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring hook log lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	if info, err := os.Stat(l.path); err == nil && l.MaxBytes > 0 && info.Size() > 0 && info.Size()+int64(len(data)) > l.MaxBytes {
+		if err := l.rotate(); err != nil {
+			return fmt.Errorf("rotating hook log: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: hook log is non-sensitive operational data
+	if err != nil {
+		return fmt.Errorf("opening hook log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing hook entry: %w", err)
+	}
+	return nil
+}
+
+// rotate shifts hooks.jsonl → .1 → .2 ..., dropping the oldest backup.
+func (l *Log) rotate() error {
+	if l.Backups <= 0 {
+		return os.Remove(l.path)
+	}
+	_ = os.Remove(l.backupPath(l.Backups))
+	for i := l.Backups - 1; i >= 1; i-- {
+		if err := os.Rename(l.backupPath(i), l.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(l.path, l.backupPath(1))
+}
+
+func (l *Log) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
+
+// Filter selects entries from the log. Zero values match everything.
+type Filter struct {
+	Since time.Time
+	Actor string // Prefix of the actor or the hook's agent
+	Rig   string
+	Bead  string // Matches the bead, molecule or previous occupant
+	Limit int    // Keep only the most recent Limit entries
+}
+
+// Match reports whether an entry passes the filter (ignoring Limit).
+func (f Filter) Match(e Entry) bool {
+	if !f.Since.IsZero() && e.Timestamp.Before(f.Since) {
+		return false
+	}
+	if f.Actor != "" && !strings.HasPrefix(e.Actor, f.Actor) && !strings.HasPrefix(e.Agent, f.Actor) {
+		return false
+	}
+	if f.Rig != "" && e.Rig != f.Rig {
+		return false
+	}
+	if f.Bead != "" && e.Bead != f.Bead && e.Molecule != f.Bead && e.Previous != f.Bead {
+		return false
+	}
+	return true
+}
+
+// Read returns matching entries across the current file and its backups,
+// oldest first. A missing log yields no entries.
+func (l *Log) Read(f Filter) ([]Entry, error) {
+	paths := make([]string, 0, l.Backups+1)
+	for i := l.Backups; i >= 1; i-- {
+		paths = append(paths, l.backupPath(i))
+	}
+	paths = append(paths, l.path)
+
+	var entries []Entry
+	for _, path := range paths {
+		fileEntries, err := readFile(path, f)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	if f.Limit > 0 && len(entries) > f.Limit {
+		entries = entries[len(entries)-f.Limit:]
+	}
+	return entries, nil
+}
+
+func readFile(path string, f Filter) ([]Entry, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is built from the town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening hook log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Skip malformed lines (e.g. a torn write)
+		}
+		if f.Match(e) {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading hook log: %w", err)
+	}
+	return entries, nil
+}
+
+// RigFromAgent returns the rig in an agent address ("gastown/polecats/Toast"
+// → "gastown"). Town-level agents (mayor, deacon) have no rig.
+func RigFromAgent(agent string) string {
+	agent = strings.TrimSuffix(agent, "/")
+	parts := strings.Split(agent, "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	switch parts[0] {
+	case "mayor", "deacon", "":
+		return ""
+	}
+	return parts[0]
+}
@@ -0,0 +1,136 @@
+package hooklog
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	l := New(t.TempDir())
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	entries := []Entry{
+		{Timestamp: base, Action: ActionAttach, Command: "gt sling", Actor: "mayor", Agent: "gastown/polecats/Toast", Bead: "gt-abc"},
+		{Timestamp: base.Add(time.Hour), Action: ActionAttach, Command: "gt sling", Actor: "mayor", Agent: "beads/polecats/Nux", Bead: "bd-xyz", Molecule: "bd-wisp-1"},
+		{Timestamp: base.Add(2 * time.Hour), Action: ActionDetach, Command: "gt unsling", Actor: "gastown/polecats/Toast", Agent: "gastown/polecats/Toast", Bead: "gt-abc"},
+	}
+	for _, e := range entries {
+		if err := l.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := l.Read(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || all[0].Rig != "gastown" || all[1].Rig != "beads" {
+		t.Fatalf("Read = %+v", all)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"since", Filter{Since: base.Add(30 * time.Minute)}, 2},
+		{"actor matches actor", Filter{Actor: "mayor"}, 2},
+		{"actor matches hook owner", Filter{Actor: "gastown/polecats/Toast"}, 2},
+		{"actor prefix", Filter{Actor: "beads/"}, 1},
+		{"rig", Filter{Rig: "gastown"}, 2},
+		{"bead", Filter{Bead: "gt-abc"}, 2},
+		{"molecule", Filter{Bead: "bd-wisp-1"}, 1},
+		{"limit keeps newest", Filter{Limit: 1}, 1},
+	}
+	for _, tt := range tests {
+		got, err := l.Read(tt.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("%s: got %d entries, want %d", tt.name, len(got), tt.want)
+		}
+	}
+
+	if got, _ := l.Read(Filter{Limit: 1}); got[0].Action != ActionDetach {
+		t.Errorf("Limit kept %+v, want the newest entry", got[0])
+	}
+}
+
+func TestRotation(t *testing.T) {
+	l := New(t.TempDir())
+	l.MaxBytes = 400
+	l.Backups = 2
+
+	for i := 0; i < 20; i++ {
+		if err := l.Append(Entry{Action: ActionAttach, Actor: "mayor", Bead: fmt.Sprintf("gt-%02d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, path := range []string{l.Path(), l.backupPath(1), l.backupPath(2)} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("expected %s: %v", path, err)
+		}
+		if info.Size() > l.MaxBytes {
+			t.Errorf("%s is %d bytes, over the %d limit", path, info.Size(), l.MaxBytes)
+		}
+	}
+	if _, err := os.Stat(l.backupPath(3)); !os.IsNotExist(err) {
+		t.Errorf("kept more than %d backups", l.Backups)
+	}
+
+	got, err := l.Read(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 || len(got) == 20 || got[len(got)-1].Bead != "gt-19" {
+		t.Errorf("after rotation read %d entries ending %+v", len(got), got[len(got)-1])
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].Bead < got[i-1].Bead {
+			t.Fatalf("entries out of order: %s before %s", got[i-1].Bead, got[i].Bead)
+		}
+	}
+}
+
+func TestRead_MissingAndMalformed(t *testing.T) {
+	l := New(t.TempDir())
+	if got, err := l.Read(Filter{}); err != nil || len(got) != 0 {
+		t.Fatalf("missing log: %v, %v", got, err)
+	}
+
+	if err := l.Append(Entry{Action: ActionClear, Actor: "deacon"}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(l.Path(), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("{torn\n")
+	f.Close()
+
+	got, err := l.Read(Filter{})
+	if err != nil || len(got) != 1 {
+		t.Errorf("Read = %v, %v; want the one valid entry", got, err)
+	}
+}
+
+func TestRigFromAgent(t *testing.T) {
+	tests := map[string]string{
+		"gastown/polecats/Toast": "gastown",
+		"gastown/witness":        "gastown",
+		"mayor":                  "",
+		"mayor/":                 "",
+		"deacon/dogs/alpha":      "",
+		"":                       "",
+	}
+	for agent, want := range tests {
+		if got := RigFromAgent(agent); got != want {
+			t.Errorf("RigFromAgent(%q) = %q, want %q", agent, got, want)
+		}
+	}
+}
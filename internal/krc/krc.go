@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/transcript"
 )
 
 // Config defines TTL settings for ephemeral records.
@@ -80,6 +81,9 @@ func DefaultConfig() *Config {
 
 			// Merge events - important for audit
 			"merge_*":       30 * 24 * time.Hour, // 30 days
+
+			// Session transcripts (recordings, not events) - replay recent work
+			transcript.KRCType: 14 * 24 * time.Hour, // 14 days
 		},
 	}
 }
@@ -171,6 +175,10 @@ type PruneResult struct {
 	BytesAfter      int64          `json:"bytes_after"`
 	PrunedByType    map[string]int `json:"pruned_by_type"`
 	Duration        time.Duration  `json:"duration"`
+
+	// Session transcripts removed under the "transcript" TTL
+	TranscriptsPruned    int   `json:"transcripts_pruned,omitempty"`
+	TranscriptBytesFreed int64 `json:"transcript_bytes_freed,omitempty"`
 }
 
 // Pruner handles the pruning of expired events.
@@ -223,6 +231,14 @@ func (p *Pruner) Prune() (*PruneResult, error) {
 		result.PrunedByType[k] += v
 	}
 
+	// Prune session transcripts
+	removed, freed, err := transcript.Prune(p.townRoot, p.config.GetTTL(transcript.KRCType), time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("pruning transcripts: %w", err)
+	}
+	result.TranscriptsPruned = removed
+	result.TranscriptBytesFreed = freed
+
 	result.Duration = time.Since(start)
 	return result, nil
 }
@@ -461,18 +477,15 @@ func getFileStats(filePath string, config *Config, now time.Time, byType, byAge
 		}
 		stats.EventCount++
 
-		var event struct {
-			Timestamp string `json:"ts"`
-			Type      string `json:"type"`
-		}
+		var event events.Event
 		if err := json.Unmarshal([]byte(line), &event); err != nil {
 			continue
 		}
 
 		byType[event.Type]++
 
-		ts, err := time.Parse(time.RFC3339, event.Timestamp)
-		if err != nil {
+		ts, ok := event.Time()
+		if !ok {
 			continue
 		}
 
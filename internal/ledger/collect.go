@@ -0,0 +1,104 @@
+package ledger
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/budget"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Helpers that gather record fields from the rest of the town. They are
+// best-effort: a missing source leaves the field empty rather than
+// failing the export.
+
+// costLogPath is the session cost log read by WorkCost (swapped in tests).
+var costLogPath = budget.LogPath
+
+// Slung returns when bead was first slung, and the formula slung with it,
+// from the town event log. The time is nil if no sling was logged.
+func Slung(townRoot, bead string) (*time.Time, string) {
+	var started *time.Time
+	var formula string
+	f := events.Filter{
+		Types: []string{events.TypeSling},
+		Where: map[string]string{".payload.bead": bead},
+	}
+	_ = events.Query(filepath.Join(townRoot, events.EventsFile), f, func(e events.Event) error {
+		if ts, ok := e.Time(); ok {
+			started = &ts
+		}
+		if p, err := events.DecodePayload[events.Sling](e); err == nil {
+			formula = p.Formula
+		}
+		return io.EOF
+	})
+	return started, formula
+}
+
+// WorkCost returns the recorded session spend attributed to beads, from
+// session cost entries that have not been rolled into a digest yet.
+func WorkCost(beads ...string) float64 {
+	entries, err := budget.ReadLog(costLogPath())
+	if err != nil {
+		return 0
+	}
+	items := make(map[string]bool, len(beads))
+	for _, b := range beads {
+		items[b] = true
+	}
+	var total float64
+	for _, e := range entries {
+		if e.WorkItem != "" && items[e.WorkItem] {
+			total += e.CostUSD
+		}
+	}
+	return total
+}
+
+// DiffStat returns the size of commit in the git repository at workDir.
+func DiffStat(workDir, commit string) (*Diff, error) {
+	cmd := exec.Command("git", "show", "--numstat", "--format=", commit) //nolint:gosec // G204: commit is a SHA from git
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseNumstat(out), nil
+}
+
+// parseNumstat parses git --numstat output. Binary files count as touched
+// with no line changes.
+func parseNumstat(out []byte) *Diff {
+	d := &Diff{}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		parts := strings.SplitN(sc.Text(), "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		added, _ := strconv.Atoi(parts[0])
+		removed, _ := strconv.Atoi(parts[1])
+		d.Add(&Diff{Files: []string{parts[2]}, Changed: 1, Added: added, Removed: removed})
+	}
+	return d
+}
+
+// ParseTime parses a bead timestamp, returning nil if it is empty or
+// malformed.
+func ParseTime(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,380 @@
+// Package ledger keeps the town's permanent record of completed work.
+//
+// When work crosses a completion boundary (the refinery merges an MR, a
+// convoy lands, a molecule is squashed) a compressed completion record is
+// appended to the ledger: who did it, which bead, the formula, how long it
+// took, what it cost, the diff stats and the outcome. This is the Level 2
+// ledger of docs/design/ledger-export-triggers.md, and the source polecat
+// CVs are built from.
+//
+// The ledger is append-only and hash-chained. Each entry carries the hash of
+// the one before it, so editing, dropping or reordering entries breaks the
+// chain and is caught by Verify. Records are never updated: a bead that is
+// reworked and merged again gets a new record.
+package ledger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// Trigger is the work boundary that produced a record.
+type Trigger string
+
+// Export triggers.
+const (
+	TriggerMerged   Trigger = "mr_merged"         // The refinery merged a polecat's work
+	TriggerLanded   Trigger = "convoy_landed"     // Every bead a convoy tracks is closed
+	TriggerSquashed Trigger = "molecule_squashed" // A molecule was squashed into a digest
+)
+
+// Record is a compressed completion record.
+type Record struct {
+	Trigger     Trigger    `json:"trigger"`
+	Bead        string     `json:"bead"` // Work bead, convoy or molecule
+	Title       string     `json:"title,omitempty"`
+	Actor       string     `json:"actor,omitempty"`  // Who did the work, e.g. "gastown/polecats/Toast"
+	Agents      []string   `json:"agents,omitempty"` // Everyone who worked on a convoy
+	Rig         string     `json:"rig,omitempty"`
+	Formula     string     `json:"formula,omitempty"`
+	Convoy      string     `json:"convoy,omitempty"`
+	Beads       []string   `json:"beads,omitempty"` // Beads a convoy tracked
+	Started     *time.Time `json:"started,omitempty"`
+	Completed   time.Time  `json:"completed"`
+	DurationSec int64      `json:"duration_sec,omitempty"`
+	CostUSD     float64    `json:"cost_usd,omitempty"`
+	Diff        *Diff      `json:"diff,omitempty"`
+	Outcome     string     `json:"outcome"`       // merged, landed; complete, partial or squashed for molecules
+	Ref         string     `json:"ref,omitempty"` // Merge commit, digest bead or convoy close time
+}
+
+// Diff is the size of a change.
+type Diff struct {
+	Files   []string `json:"files,omitempty"` // Paths touched, capped at maxDiffFiles
+	Changed int      `json:"changed"`         // Number of files touched
+	Added   int      `json:"added"`
+	Removed int      `json:"removed"`
+}
+
+// Add accumulates o into d. Files stay capped.
+func (d *Diff) Add(o *Diff) {
+	if o == nil {
+		return
+	}
+	d.Changed += o.Changed
+	d.Added += o.Added
+	d.Removed += o.Removed
+	for _, f := range o.Files {
+		if len(d.Files) >= maxDiffFiles {
+			break
+		}
+		d.Files = append(d.Files, f)
+	}
+}
+
+// Key identifies a record for deduplication: the same trigger firing again
+// for the same completion (a second observer, a retry) is not recorded
+// twice, while a later completion of the same bead has a different Ref.
+func (r *Record) Key() string {
+	return string(r.Trigger) + ":" + r.Bead + ":" + r.Ref
+}
+
+// Duration returns how long the work took, or 0 if the start is unknown.
+func (r *Record) Duration() time.Duration {
+	return time.Duration(r.DurationSec) * time.Second
+}
+
+// Involves reports whether actor did the work or took part in it.
+func (r *Record) Involves(actor string) bool {
+	if r.Actor == actor {
+		return true
+	}
+	for _, a := range r.Agents {
+		if a == actor {
+			return true
+		}
+	}
+	return false
+}
+
+// Entry is one link of the chain: a record with its position, export time
+// and hashes. Record is kept as written, so hashes verify byte for byte.
+type Entry struct {
+	Seq    int             `json:"seq"` // From 1
+	At     time.Time       `json:"at"`
+	Prev   string          `json:"prev"` // Hash of the previous entry; empty for the first
+	Hash   string          `json:"hash"`
+	Record json.RawMessage `json:"record"`
+}
+
+// Decode returns the entry's record.
+func (e *Entry) Decode() (Record, error) {
+	var r Record
+	if err := json.Unmarshal(e.Record, &r); err != nil {
+		return r, fmt.Errorf("ledger entry %d: %w", e.Seq, err)
+	}
+	return r, nil
+}
+
+// computeHash returns the hash of an entry's contents and its link.
+func (e *Entry) computeHash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\n%s\n%s\n", e.Seq, e.At.UTC().Format(time.RFC3339Nano), e.Prev)
+	h.Write(e.Record)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ChainError reports where a ledger fails verification.
+type ChainError struct {
+	Line   int
+	Reason string
+}
+
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("ledger line %d: %s", e.Line, e.Reason)
+}
+
+// Path returns the town's ledger file.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, "ledger", "completions.jsonl")
+}
+
+// maxDiffFiles caps the paths kept per record; the counts stay exact.
+const maxDiffFiles = 100
+
+// Append adds a record to the town ledger and returns its entry. If a record
+// with the same Key is already in the ledger, that entry is returned with
+// added false. DurationSec is filled in from Started when unset.
+func Append(townRoot string, rec Record) (*Entry, bool, error) {
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, false, fmt.Errorf("creating ledger directory: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return nil, false, fmt.Errorf("acquiring ledger lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	size := int64(0)
+	if fi, err := os.Stat(path); err == nil {
+		size = fi.Size()
+	}
+	idx, err := loadIndex(path, size)
+	if err != nil {
+		return nil, false, err
+	}
+	key := rec.Key()
+	if seq, ok := idx.Keys[key]; ok {
+		// Rare: the full read is only paid for a duplicate
+		entries, err := Read(path)
+		if err != nil {
+			return nil, false, err
+		}
+		if seq >= 1 && seq <= len(entries) {
+			return &entries[seq-1], false, nil
+		}
+	}
+
+	if rec.DurationSec == 0 && rec.Started != nil && rec.Completed.After(*rec.Started) {
+		rec.DurationSec = int64(rec.Completed.Sub(*rec.Started).Seconds())
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return nil, false, fmt.Errorf("marshaling ledger record: %w", err)
+	}
+	e := &Entry{Seq: idx.Seq + 1, At: time.Now().UTC(), Prev: idx.Hash, Record: data}
+	e.Hash = e.computeHash()
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return nil, false, fmt.Errorf("marshaling ledger entry: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // G302: ledger is non-sensitive
+	if err != nil {
+		return nil, false, fmt.Errorf("opening ledger: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return nil, false, fmt.Errorf("writing ledger: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return nil, false, fmt.Errorf("writing ledger: %w", err)
+	}
+
+	idx.Size = size + int64(len(line)) + 1
+	idx.Seq = e.Seq
+	idx.Hash = e.Hash
+	idx.Keys[key] = e.Seq
+	idx.save(path) // Best-effort: a stale index is rebuilt on the next append
+	return e, true, nil
+}
+
+// index is the side file that lets Append link and deduplicate without
+// re-reading the ledger: the chain head and the key of every record. It
+// describes the ledger at a given size; when the sizes differ (an append
+// whose index write failed, a hand-edited ledger) it is rebuilt.
+type index struct {
+	Size int64          `json:"size"`
+	Seq  int            `json:"seq"`
+	Hash string         `json:"hash"`
+	Keys map[string]int `json:"keys"` // Record key → entry seq
+}
+
+func indexPath(path string) string {
+	return path + ".idx"
+}
+
+// loadIndex returns the index of the ledger at path, which is size bytes
+// long, rebuilding it from the ledger when missing or stale.
+func loadIndex(path string, size int64) (*index, error) {
+	var idx index
+	if data, err := os.ReadFile(indexPath(path)); err == nil { //nolint:gosec // G304: path is the town ledger
+		if json.Unmarshal(data, &idx) == nil && idx.Size == size && idx.Keys != nil {
+			return &idx, nil
+		}
+	}
+
+	entries, err := Read(path)
+	if err != nil {
+		return nil, err
+	}
+	idx = index{Size: size, Keys: make(map[string]int, len(entries))}
+	for i := range entries {
+		if r, err := entries[i].Decode(); err == nil {
+			idx.Keys[r.Key()] = entries[i].Seq
+		}
+	}
+	if n := len(entries); n > 0 {
+		idx.Seq = entries[n-1].Seq
+		idx.Hash = entries[n-1].Hash
+	}
+	return &idx, nil
+}
+
+func (idx *index) save(path string) {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return
+	}
+	tmp := indexPath(path) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: index is non-sensitive
+		return
+	}
+	_ = os.Rename(tmp, indexPath(path))
+}
+
+// Read returns the entries of the ledger at path, oldest first. A missing
+// ledger is empty. Unlike the event logs, an unparseable line is an error:
+// the ledger is a permanent record and corruption must not go unnoticed.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is the town ledger
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening ledger: %w", err)
+	}
+	defer f.Close()
+	return readEntries(f)
+}
+
+func readEntries(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, &ChainError{Line: line, Reason: "malformed entry: " + err.Error()}
+		}
+		entries = append(entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading ledger: %w", err)
+	}
+	return entries, nil
+}
+
+// Records returns the records in the ledger at path, oldest first.
+func Records(path string) ([]Record, error) {
+	entries, err := Read(path)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, 0, len(entries))
+	for i := range entries {
+		r, err := entries[i].Decode()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+// Verify checks a complete ledger read from r: every entry's hash matches
+// its contents and links to the entry before it, and sequence numbers run
+// from 1 without gaps. It returns the number of entries verified; a broken
+// chain is reported as a *ChainError.
+func Verify(r io.Reader) (int, error) {
+	var prev *Entry
+	n := 0
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return n, &ChainError{Line: line, Reason: "malformed entry: " + err.Error()}
+		}
+		want := 1
+		wantPrev := ""
+		if prev != nil {
+			want = prev.Seq + 1
+			wantPrev = prev.Hash
+		}
+		switch {
+		case e.Seq != want:
+			return n, &ChainError{Line: line, Reason: fmt.Sprintf("sequence %d, want %d", e.Seq, want)}
+		case e.Prev != wantPrev:
+			return n, &ChainError{Line: line, Reason: fmt.Sprintf("entry %d does not link to the entry before it", e.Seq)}
+		case e.Hash != e.computeHash():
+			return n, &ChainError{Line: line, Reason: fmt.Sprintf("entry %d hash does not match its contents", e.Seq)}
+		}
+		prev = &e
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		return n, fmt.Errorf("reading ledger: %w", err)
+	}
+	return n, nil
+}
+
+// VerifyFile verifies the ledger at path. A missing ledger is valid and empty.
+func VerifyFile(path string) (int, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is a ledger chosen by the user
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("opening ledger: %w", err)
+	}
+	defer f.Close()
+	return Verify(f)
+}
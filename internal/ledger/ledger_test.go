@@ -0,0 +1,182 @@
+package ledger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var base = time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+func merged(bead, commit string) Record {
+	started := base
+	return Record{
+		Trigger:   TriggerMerged,
+		Bead:      bead,
+		Actor:     "gastown/polecats/Toast",
+		Started:   &started,
+		Completed: base.Add(90 * time.Minute),
+		Outcome:   "merged",
+		Ref:       commit,
+	}
+}
+
+func TestAppendChainsAndDedupes(t *testing.T) {
+	town := t.TempDir()
+	for i, rec := range []Record{merged("gt-1", "aaa"), merged("gt-2", "bbb"), merged("gt-1", "ccc")} {
+		e, added, err := Append(town, rec)
+		if err != nil || !added {
+			t.Fatalf("Append %d: added %v, err %v", i, added, err)
+		}
+		if e.Seq != i+1 {
+			t.Errorf("seq = %d, want %d", e.Seq, i+1)
+		}
+	}
+
+	// The same completion reported again is not recorded twice
+	e, added, err := Append(town, merged("gt-2", "bbb"))
+	if err != nil || added || e.Seq != 2 {
+		t.Fatalf("duplicate Append: seq %d, added %v, err %v", e.Seq, added, err)
+	}
+
+	records, err := Records(Path(town))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[0].Duration() != 90*time.Minute {
+		t.Fatalf("got %d records, first duration %v", len(records), records[0].Duration())
+	}
+	if !records[1].Involves("gastown/polecats/Toast") || records[1].Involves("gastown/polecats/Nux") {
+		t.Error("Involves matched the wrong actor")
+	}
+
+	n, err := VerifyFile(Path(town))
+	if err != nil || n != 3 {
+		t.Errorf("VerifyFile = %d, %v; want 3, nil", n, err)
+	}
+}
+
+func TestAppendRebuildsStaleIndex(t *testing.T) {
+	town := t.TempDir()
+	for _, bead := range []string{"gt-1", "gt-2"} {
+		if _, _, err := Append(town, merged(bead, "sha-"+bead)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(indexPath(Path(town))); err != nil {
+		t.Fatalf("index not written: %v", err)
+	}
+
+	// A lost or stale index is rebuilt from the ledger
+	for name, corrupt := range map[string]func() error{
+		"missing": func() error { return os.Remove(indexPath(Path(town))) },
+		"stale":   func() error { return os.WriteFile(indexPath(Path(town)), []byte(`{"size":1,"seq":9,"keys":{}}`), 0644) },
+	} {
+		if err := corrupt(); err != nil {
+			t.Fatal(err)
+		}
+		if _, added, err := Append(town, merged("gt-1", "sha-gt-1")); err != nil || added {
+			t.Errorf("%s index: duplicate added %v, err %v", name, added, err)
+		}
+	}
+	if err := os.Remove(indexPath(Path(town))); err != nil {
+		t.Fatal(err)
+	}
+	e, added, err := Append(town, merged("gt-3", "sha-gt-3"))
+	if err != nil || !added || e.Seq != 3 {
+		t.Fatalf("Append after rebuild: seq %d, added %v, err %v", e.Seq, added, err)
+	}
+	if n, err := VerifyFile(Path(town)); err != nil || n != 3 {
+		t.Errorf("VerifyFile = %d, %v; want 3, nil", n, err)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	town := t.TempDir()
+	for _, bead := range []string{"gt-1", "gt-2", "gt-3"} {
+		if _, _, err := Append(town, merged(bead, "sha-"+bead)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(Path(town))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(string(data), "\n"), "\n")
+
+	tests := []struct {
+		name   string
+		ledger string
+		line   int
+	}{
+		{"edited record", strings.Replace(string(data), `"outcome":"merged","ref":"sha-gt-2"`, `"outcome":"failed","ref":"sha-gt-2"`, 1), 2},
+		{"dropped entry", lines[0] + lines[2], 2},
+		{"reordered", lines[1] + lines[0] + lines[2], 1},
+		{"garbage", lines[0] + "not json\n", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Verify(strings.NewReader(tt.ledger))
+			var ce *ChainError
+			if !errors.As(err, &ce) || ce.Line != tt.line {
+				t.Errorf("Verify error = %v, want a chain error at line %d", err, tt.line)
+			}
+		})
+	}
+}
+
+func TestParseNumstat(t *testing.T) {
+	out := []byte("10\t2\tinternal/ledger/ledger.go\n-\t-\tdocs/logo.png\n3\t0\tREADME.md\n")
+	d := parseNumstat(out)
+	if d.Changed != 3 || d.Added != 13 || d.Removed != 2 || len(d.Files) != 3 {
+		t.Errorf("parseNumstat = %+v", d)
+	}
+
+	var big bytes.Buffer
+	for i := 0; i < maxDiffFiles+10; i++ {
+		fmt.Fprintf(&big, "1\t1\tf%d.go\n", i)
+	}
+	if d := parseNumstat(big.Bytes()); d.Changed != maxDiffFiles+10 || len(d.Files) != maxDiffFiles {
+		t.Errorf("files not capped: changed %d, kept %d", d.Changed, len(d.Files))
+	}
+}
+
+func TestSlungAndWorkCost(t *testing.T) {
+	town := t.TempDir()
+	log := `{"ts":"2026-06-01T08:00:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-9","target":"gastown/polecats/Toast","formula":"mol-polecat-work"}}
+{"ts":"2026-06-01T10:00:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-9","target":"gastown/polecats/Nux"}}
+`
+	if err := os.WriteFile(filepath.Join(town, ".events.jsonl"), []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+	started, formula := Slung(town, "gt-9")
+	if started == nil || !started.Equal(base.Add(-time.Hour)) || formula != "mol-polecat-work" {
+		t.Errorf("Slung = %v, %q", started, formula)
+	}
+	if started, _ := Slung(town, "gt-404"); started != nil {
+		t.Errorf("Slung of unknown bead = %v", started)
+	}
+
+	costs := filepath.Join(town, "costs.jsonl")
+	entries := `{"session_id":"s1","role":"polecat","cost_usd":1.5,"work_item":"gt-9"}
+{"session_id":"s2","role":"polecat","cost_usd":2.25,"work_item":"gt-9"}
+{"session_id":"s3","role":"polecat","cost_usd":7,"work_item":"gt-10"}
+`
+	if err := os.WriteFile(costs, []byte(entries), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := costLogPath
+	costLogPath = func() string { return costs }
+	t.Cleanup(func() { costLogPath = old })
+	if got := WorkCost("gt-9"); got != 3.75 {
+		t.Errorf("WorkCost(gt-9) = %v, want 3.75", got)
+	}
+	if got := WorkCost("gt-9", "gt-10"); got != 10.75 {
+		t.Errorf("WorkCost(gt-9, gt-10) = %v, want 10.75", got)
+	}
+}
@@ -0,0 +1,67 @@
+package mail
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Envelope carries the structured form of a protocol message (MERGE_READY,
+// POLECAT_DONE, ...) alongside the human-readable subject and body. Receivers
+// dispatch on Envelope.Type rather than on the subject wording. The protocol
+// package defines the message types and payload schemas.
+type Envelope struct {
+	// Type is the protocol message type (e.g., "MERGE_READY").
+	Type string `json:"type"`
+
+	// Version is the payload schema version.
+	Version int `json:"version"`
+
+	// CorrelationID ties related protocol messages together, e.g. a
+	// MERGE_READY and the MERGED or MERGE_FAILED that answers it.
+	CorrelationID string `json:"correlation_id,omitempty"`
+
+	// Payload is the JSON-encoded message payload.
+	Payload json.RawMessage `json:"payload"`
+}
+
+// envelopePrefix marks the body line that carries the envelope when a
+// message is stored as a bead. Beads only has a description field, so the
+// envelope rides on the last line of the body and is stripped on read.
+const envelopePrefix = "gt-envelope: "
+
+// storedBody returns the body as written to beads, with the envelope (if
+// any) appended as a trailer line.
+func (m *Message) storedBody() string {
+	if m.Envelope == nil {
+		return m.Body
+	}
+	data, err := json.Marshal(m.Envelope)
+	if err != nil {
+		return m.Body
+	}
+	body := strings.TrimRight(m.Body, "\n")
+	if body != "" {
+		body += "\n\n"
+	}
+	return body + envelopePrefix + string(data)
+}
+
+// splitEnvelope separates a stored body into the human-readable body and
+// the envelope trailer. Bodies without a valid trailer are returned as-is.
+func splitEnvelope(stored string) (string, *Envelope) {
+	trimmed := strings.TrimRight(stored, "\n")
+	idx := strings.LastIndex(trimmed, "\n")
+	last := trimmed[idx+1:]
+	if !strings.HasPrefix(last, envelopePrefix) {
+		return stored, nil
+	}
+
+	var env Envelope
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(last, envelopePrefix)), &env); err != nil || env.Type == "" {
+		return stored, nil
+	}
+	if idx < 0 {
+		return "", &env
+	}
+	return strings.TrimRight(trimmed[:idx], "\n"), &env
+}
@@ -9,11 +9,11 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
-	"github.com/steveyegge/gastown/internal/runtime"
 )
 
 // timeNow is a function that returns the current time. It can be overridden in tests.
@@ -27,11 +27,12 @@ var (
 
 // Mailbox manages messages for an identity via beads.
 type Mailbox struct {
-	identity string // beads identity (e.g., "gastown/polecats/Toast")
-	workDir  string // directory to run bd commands in
-	beadsDir string // explicit .beads directory path (set via BEADS_DIR)
-	path     string // for legacy JSONL mode (crew workers)
-	legacy   bool   // true = use JSONL files, false = use beads
+	identity string      // beads identity (e.g., "gastown/polecats/Toast")
+	workDir  string      // directory to run bd commands in
+	beadsDir string      // explicit .beads directory path (set via BEADS_DIR)
+	path     string      // for legacy JSONL mode (crew workers)
+	legacy   bool        // true = use JSONL files, false = use beads
+	store    beads.Store // message store; nil = bd run in workDir against beadsDir
 }
 
 // NewMailbox creates a mailbox for the given JSONL path (legacy mode).
@@ -74,6 +75,24 @@ func NewMailboxWithBeadsDir(address, workDir, beadsDir string) *Mailbox {
 	}
 }
 
+// NewMailboxWithStore creates a mailbox for address whose messages live in
+// store rather than a bd database.
+func NewMailboxWithStore(address string, store beads.Store) *Mailbox {
+	return &Mailbox{
+		identity: AddressToIdentity(address),
+		store:    store,
+		legacy:   false,
+	}
+}
+
+// beadsStore returns the store holding the mailbox's messages.
+func (m *Mailbox) beadsStore() beads.Store {
+	if m.store != nil {
+		return m.store
+	}
+	return beads.NewWithBeadsDir(m.workDir, m.beadsDir).WithTimeout(bdWriteTimeout)
+}
+
 // Identity returns the beads identity for this mailbox.
 func (m *Mailbox) Identity() string {
 	return m.identity
@@ -106,7 +125,7 @@ func (m *Mailbox) List() ([]*Message, error) {
 func (m *Mailbox) listBeads() ([]*Message, error) {
 	// Single query to beads - returns both persistent and wisp messages
 	// Wisps are stored in same DB with wisp=true flag, filtered from JSONL export
-	messages, err := m.listFromDir(m.beadsDir)
+	messages, err := m.listFromStore()
 	if err != nil {
 		return nil, err
 	}
@@ -119,43 +138,29 @@ func (m *Mailbox) listBeads() ([]*Message, error) {
 	return messages, nil
 }
 
-// listFromDir queries messages from a beads directory.
+// listFromStore queries the mailbox's messages.
 // Returns messages where identity is the assignee OR a CC recipient.
 // Includes both open and hooked messages (hooked = auto-assigned handoff mail).
-// Uses a single bd list call and filters client-side, replacing the previous
+// Uses a single list call and filters client-side, replacing the previous
 // approach of N parallel queries (2-3 per identity variant).
-func (m *Mailbox) listFromDir(beadsDir string) ([]*Message, error) {
+func (m *Mailbox) listFromStore() ([]*Message, error) {
 	identities := m.identityVariants()
 
-	if err := beads.EnsureCustomTypes(beadsDir); err != nil {
-		return nil, fmt.Errorf("ensuring custom types: %w", err)
+	if m.store == nil {
+		if err := beads.EnsureCustomTypes(m.beadsDir); err != nil {
+			return nil, fmt.Errorf("ensuring custom types: %w", err)
+		}
 	}
 
-	// Single bd query: fetch all messages with gt:message label,
+	// Single query: fetch all messages with gt:message label,
 	// then filter client-side for assignee/CC match. Process-spawn overhead
 	// dominates query time, so 1 broad call beats N narrow calls.
-	// NOTE: Uses --label instead of --type per migration in 221ff022.
-	args := []string{"list",
-		"--label", "gt:message",
-		"--json",
-		"--limit", "0",
-	}
-
-	ctx, cancel := bdReadCtx()
-	defer cancel()
-	stdout, err := runBdCommand(ctx, args, m.workDir, beadsDir)
+	// NOTE: Uses the label instead of the type per migration in 221ff022.
+	issues, err := m.beadsStore().List(beads.ListOptions{Label: "gt:message", Priority: -1, Limit: -1})
 	if err != nil {
 		return nil, err
 	}
 
-	var allMsgs []BeadsMessage
-	if err := json.Unmarshal(stdout, &allMsgs); err != nil {
-		if len(stdout) == 0 || string(stdout) == "null" {
-			return nil, nil
-		}
-		return nil, err
-	}
-
 	// Build identity lookup sets for O(1) matching
 	identitySet := make(map[string]bool, len(identities))
 	ccLabelSet := make(map[string]bool, len(identities))
@@ -166,8 +171,8 @@ func (m *Mailbox) listFromDir(beadsDir string) ([]*Message, error) {
 
 	// Filter: assignee match (open/hooked) OR CC match (open only)
 	var messages []*Message
-	for i := range allMsgs {
-		bm := &allMsgs[i]
+	for _, issue := range issues {
+		bm := beadsMessageFromIssue(issue)
 
 		// Assignee match: open or hooked status
 		if identitySet[bm.Assignee] && (bm.Status == "open" || bm.Status == "hooked") {
@@ -271,34 +276,16 @@ func (m *Mailbox) Get(id string) (*Message, error) {
 
 func (m *Mailbox) getBeads(id string) (*Message, error) {
 	// Single DB query - wisps and persistent messages in same store
-	return m.getFromDir(id, m.beadsDir)
-}
-
-// getFromDir retrieves a message from a beads directory.
-func (m *Mailbox) getFromDir(id, beadsDir string) (*Message, error) {
-	args := []string{"show", id, "--json"}
-
-	ctx, cancel := bdReadCtx()
-	defer cancel()
-	stdout, err := runBdCommand(ctx, args, m.workDir, beadsDir)
+	issue, err := m.beadsStore().Show(id)
 	if err != nil {
-		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("not found") {
+		if errors.Is(err, beads.ErrNotFound) {
 			return nil, ErrMessageNotFound
 		}
 		return nil, err
 	}
 
-	// bd show --json returns an array
-	var bms []BeadsMessage
-	if err := json.Unmarshal(stdout, &bms); err != nil {
-		return nil, err
-	}
-	if len(bms) == 0 {
-		return nil, ErrMessageNotFound
-	}
-
-	// Wisp status comes from beads issue.wisp field via ToMessage()
-	return bms[0].ToMessage(), nil
+	// Wisp status comes from the issue's ephemeral flag via ToMessage()
+	return beadsMessageFromIssue(issue).ToMessage(), nil
 }
 
 func (m *Mailbox) getLegacy(id string) (*Message, error) {
@@ -323,29 +310,17 @@ func (m *Mailbox) MarkRead(id string) error {
 }
 
 func (m *Mailbox) markReadBeads(id string) error {
-	// Single DB - wisps and persistent messages in same store
-	return m.closeInDir(id, m.beadsDir)
+	// Single DB - wisps and persistent messages in same store.
+	// Close passes the session ID for work attribution if available.
+	return notFoundAsMessage(m.beadsStore().Close(id))
 }
 
-// closeInDir closes a message in a specific beads directory.
-func (m *Mailbox) closeInDir(id, beadsDir string) error {
-	args := []string{"close", id}
-	// Pass session ID for work attribution if available
-	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
-		args = append(args, "--session="+sessionID)
-	}
-
-	ctx, cancel := bdWriteCtx()
-	defer cancel()
-	_, err := runBdCommand(ctx, args, m.workDir, beadsDir)
-	if err != nil {
-		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("not found") {
-			return ErrMessageNotFound
-		}
-		return err
+// notFoundAsMessage maps a store's ErrNotFound to ErrMessageNotFound.
+func notFoundAsMessage(err error) error {
+	if errors.Is(err, beads.ErrNotFound) {
+		return ErrMessageNotFound
 	}
-
-	return nil
+	return err
 }
 
 func (m *Mailbox) markReadLegacy(id string) error {
@@ -388,19 +363,7 @@ func (m *Mailbox) MarkReadOnly(id string) error {
 
 func (m *Mailbox) markReadOnlyBeads(id string) error {
 	// Add "read" label to mark as read without closing
-	args := []string{"label", "add", id, "read"}
-
-	ctx, cancel := bdWriteCtx()
-	defer cancel()
-	_, err := runBdCommand(ctx, args, m.workDir, m.beadsDir)
-	if err != nil {
-		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("not found") {
-			return ErrMessageNotFound
-		}
-		return err
-	}
-
-	return nil
+	return notFoundAsMessage(m.beadsStore().Update(id, beads.UpdateOptions{AddLabels: []string{"read"}}))
 }
 
 // MarkUnreadOnly marks a message as unread (removes "read" label).
@@ -415,23 +378,12 @@ func (m *Mailbox) MarkUnreadOnly(id string) error {
 
 func (m *Mailbox) markUnreadOnlyBeads(id string) error {
 	// Remove "read" label to mark as unread
-	args := []string{"label", "remove", id, "read"}
-
-	ctx, cancel := bdWriteCtx()
-	defer cancel()
-	_, err := runBdCommand(ctx, args, m.workDir, m.beadsDir)
-	if err != nil {
-		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("not found") {
-			return ErrMessageNotFound
-		}
-		// Ignore error if label doesn't exist
-		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("does not have label") {
-			return nil
-		}
-		return err
+	err := m.beadsStore().Update(id, beads.UpdateOptions{RemoveLabels: []string{"read"}})
+	// Ignore error if label doesn't exist
+	if err != nil && strings.Contains(err.Error(), "does not have label") {
+		return nil
 	}
-
-	return nil
+	return notFoundAsMessage(err)
 }
 
 // MarkUnread marks a message as unread (reopens in beads).
@@ -443,19 +395,8 @@ func (m *Mailbox) MarkUnread(id string) error {
 }
 
 func (m *Mailbox) markUnreadBeads(id string) error {
-	args := []string{"reopen", id}
-
-	ctx, cancel := bdWriteCtx()
-	defer cancel()
-	_, err := runBdCommand(ctx, args, m.workDir, m.beadsDir)
-	if err != nil {
-		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("not found") {
-			return ErrMessageNotFound
-		}
-		return err
-	}
-
-	return nil
+	status := "open"
+	return notFoundAsMessage(m.beadsStore().Update(id, beads.UpdateOptions{Status: &status}))
 }
 
 func (m *Mailbox) markUnreadLegacy(id string) error {
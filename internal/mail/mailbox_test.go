@@ -9,6 +9,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 func TestNewMailbox(t *testing.T) {
@@ -704,3 +706,67 @@ func TestMailboxLegacyAtomicArchive(t *testing.T) {
 	}
 }
 
+
+func TestMailboxWithStore_RoundTrip(t *testing.T) {
+	store := beads.NewMemStore("hq")
+	if _, err := store.CreateWithID("hq-mayor", beads.CreateOptions{Title: "Mayor", Type: "agent"}); err != nil {
+		t.Fatal(err)
+	}
+	town := t.TempDir()
+	r := NewRouterWithTownRoot(town, town)
+	r.stores = func(string) beads.Store { return store }
+
+	msg := &Message{From: "mayor/", To: "mayor/", Subject: "Handoff", Body: "Pick up hq-1", CC: []string{"deacon/"}, ThreadID: "thread-1"}
+	if err := r.Send(msg); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Send(&Message{From: "mayor/", To: "gastown/witness", Subject: "Nobody"}); err == nil {
+		t.Error("sent to a recipient with no agent bead")
+	}
+
+	m := NewMailboxWithStore("mayor/", store)
+	msgs, err := m.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 || msgs[0].Subject != "Handoff" || msgs[0].From != "mayor/" || msgs[0].ThreadID != "thread-1" || msgs[0].Read {
+		t.Fatalf("List = %+v", msgs)
+	}
+	id := msgs[0].ID
+	if cc, _ := NewMailboxWithStore("deacon/", store).List(); len(cc) != 1 {
+		t.Errorf("CC recipient sees %d messages, want 1", len(cc))
+	}
+
+	if err := m.MarkReadOnly(id); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := m.Get(id); err != nil || !got.Read {
+		t.Errorf("after MarkReadOnly: %+v, %v", got, err)
+	}
+	if err := m.MarkUnreadOnly(id); err != nil {
+		t.Fatal(err)
+	}
+	if unread, _ := m.ListUnread(); len(unread) != 1 {
+		t.Errorf("ListUnread after MarkUnreadOnly = %d messages, want 1", len(unread))
+	}
+
+	if err := m.MarkRead(id); err != nil {
+		t.Fatal(err)
+	}
+	if msgs, _ := m.List(); len(msgs) != 0 {
+		t.Errorf("List after MarkRead = %+v, want empty", msgs)
+	}
+	if err := m.MarkUnread(id); err != nil {
+		t.Fatal(err)
+	}
+	if msgs, _ := m.List(); len(msgs) != 1 {
+		t.Errorf("List after MarkUnread = %d messages, want 1", len(msgs))
+	}
+
+	if _, err := m.Get("hq-404"); err != ErrMessageNotFound {
+		t.Errorf("Get of missing message = %v, want ErrMessageNotFound", err)
+	}
+	if err := m.MarkRead("hq-404"); err != ErrMessageNotFound {
+		t.Errorf("MarkRead of missing message = %v, want ErrMessageNotFound", err)
+	}
+}
@@ -0,0 +1,284 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// ErrReplyTimeout is returned when a request's reply deadline passes
+// without a reply.
+var ErrReplyTimeout = errors.New("no reply before deadline")
+
+// ErrNoPendingRequest indicates no request is tracked under a correlation ID.
+var ErrNoPendingRequest = errors.New("no pending request")
+
+// pendingRetention is how long settled (replied or expired) requests stay
+// in the store, so late callers of AwaitReply still see the outcome.
+const pendingRetention = 24 * time.Hour
+
+// pendingPollInterval is how often AwaitReply re-reads the store.
+// It can be overridden in tests.
+var pendingPollInterval = time.Second
+
+// Status values for pending requests.
+const (
+	PendingStatusWaiting = "waiting"
+	PendingStatusReplied = "replied"
+	PendingStatusExpired = "expired"
+)
+
+// PendingRequest is a sent message awaiting a reply with the same
+// correlation ID.
+type PendingRequest struct {
+	CorrelationID string     `json:"correlation_id"`
+	MessageID     string     `json:"message_id"`
+	From          string     `json:"from"`
+	To            string     `json:"to"`
+	Subject       string     `json:"subject"`
+	SentAt        time.Time  `json:"sent_at"`
+	Deadline      time.Time  `json:"deadline"`
+	ReplyID       string     `json:"reply_id,omitempty"`
+	ReplyFrom     string     `json:"reply_from,omitempty"`
+	RepliedAt     *time.Time `json:"replied_at,omitempty"`
+	ExpiredAt     *time.Time `json:"expired_at,omitempty"`
+}
+
+// Status returns the request's state: waiting, replied, or expired.
+func (p *PendingRequest) Status() string {
+	switch {
+	case p.RepliedAt != nil:
+		return PendingStatusReplied
+	case p.ExpiredAt != nil:
+		return PendingStatusExpired
+	default:
+		return PendingStatusWaiting
+	}
+}
+
+// settledAt returns when the request was replied to or expired, or nil.
+func (p *PendingRequest) settledAt() *time.Time {
+	if p.RepliedAt != nil {
+		return p.RepliedAt
+	}
+	return p.ExpiredAt
+}
+
+// PendingStore tracks requests awaiting replies for a town. It is a JSON
+// file shared by every gt process, guarded by a flock.
+type PendingStore struct {
+	path string
+}
+
+// PendingPath returns the path of the town's pending replies file.
+func PendingPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "mail-pending.json")
+}
+
+// NewPendingStore returns the pending replies store for a town.
+func NewPendingStore(townRoot string) *PendingStore {
+	return &PendingStore{path: PendingPath(townRoot)}
+}
+
+// Add records a request. An existing request with the same correlation ID
+// is replaced.
+func (s *PendingStore) Add(req *PendingRequest) error {
+	return s.update(func(reqs map[string]*PendingRequest) bool {
+		reqs[req.CorrelationID] = req
+		return true
+	})
+}
+
+// Remove drops the request with correlationID, if any.
+func (s *PendingStore) Remove(correlationID string) error {
+	return s.update(func(reqs map[string]*PendingRequest) bool {
+		if _, ok := reqs[correlationID]; !ok {
+			return false
+		}
+		delete(reqs, correlationID)
+		return true
+	})
+}
+
+// Get returns the request with correlationID, or ErrNoPendingRequest.
+func (s *PendingStore) Get(correlationID string) (*PendingRequest, error) {
+	reqs, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	req, ok := reqs[correlationID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoPendingRequest, correlationID)
+	}
+	return req, nil
+}
+
+// List returns all tracked requests, oldest first.
+func (s *PendingStore) List() ([]*PendingRequest, error) {
+	reqs, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*PendingRequest, 0, len(reqs))
+	for _, req := range reqs {
+		list = append(list, req)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].SentAt.Before(list[j].SentAt) })
+	return list, nil
+}
+
+// Resolve marks the waiting request answered by reply. Messages from the
+// requester itself (e.g. a resent request) are not replies. Reports whether
+// a request was resolved.
+func (s *PendingStore) Resolve(reply *Message) (bool, error) {
+	if reply.CorrelationID == "" {
+		return false, nil
+	}
+	// Most correlated mail answers nothing; skip the lock for it
+	if reqs, err := s.load(); err != nil || reqs[reply.CorrelationID] == nil {
+		return false, err
+	}
+	resolved := false
+	err := s.update(func(reqs map[string]*PendingRequest) bool {
+		req, ok := reqs[reply.CorrelationID]
+		if !ok || req.Status() != PendingStatusWaiting {
+			return false
+		}
+		if AddressToIdentity(reply.From) == AddressToIdentity(req.From) {
+			return false
+		}
+		now := time.Now()
+		req.ReplyID = reply.ID
+		req.ReplyFrom = reply.From
+		req.RepliedAt = &now
+		resolved = true
+		return true
+	})
+	return resolved, err
+}
+
+// ExpireOverdue marks waiting requests past their deadline as expired and
+// logs a mail_reply_timeout event for each, so patrols can retry or
+// escalate. Returns the requests that expired in this call.
+func (s *PendingStore) ExpireOverdue(now time.Time) ([]*PendingRequest, error) {
+	var expired []*PendingRequest
+	err := s.update(func(reqs map[string]*PendingRequest) bool {
+		for _, req := range reqs {
+			if req.Status() == PendingStatusWaiting && now.After(req.Deadline) {
+				t := now
+				req.ExpiredAt = &t
+				expired = append(expired, req)
+			}
+		}
+		return len(expired) > 0
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range expired {
+		_ = events.LogFeed(events.TypeMailReplyTimeout, req.From,
+			events.ReplyTimeoutPayload(req.CorrelationID, req.To, req.Subject, req.Deadline))
+	}
+	return expired, nil
+}
+
+// AwaitReply blocks until the request with correlationID is replied to,
+// its deadline passes, or ctx is done. A passed deadline expires the
+// request and returns ErrReplyTimeout along with the request.
+func (s *PendingStore) AwaitReply(ctx context.Context, correlationID string) (*PendingRequest, error) {
+	for {
+		req, err := s.Get(correlationID)
+		if err != nil {
+			return nil, err
+		}
+		switch req.Status() {
+		case PendingStatusReplied:
+			return req, nil
+		case PendingStatusExpired:
+			return req, ErrReplyTimeout
+		}
+
+		wait := time.Until(req.Deadline)
+		if wait < 0 {
+			if _, err := s.ExpireOverdue(time.Now()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if wait > pendingPollInterval {
+			wait = pendingPollInterval
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return req, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// update applies fn to the stored requests under the store lock and saves
+// them if fn reports a change. Settled requests older than pendingRetention
+// are dropped on every save.
+func (s *PendingStore) update(fn func(map[string]*PendingRequest) bool) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	fl := flock.New(s.path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring pending replies lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	reqs, err := s.load()
+	if err != nil {
+		return err
+	}
+	if !fn(reqs) {
+		return nil
+	}
+
+	cutoff := time.Now().Add(-pendingRetention)
+	for id, req := range reqs {
+		if at := req.settledAt(); at != nil && at.Before(cutoff) {
+			delete(reqs, id)
+		}
+	}
+
+	data, err := json.MarshalIndent(reqs, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: pending replies are non-sensitive
+		return fmt.Errorf("writing pending replies: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// load reads the stored requests; a missing file yields an empty map.
+func (s *PendingStore) load() (map[string]*PendingRequest, error) {
+	reqs := make(map[string]*PendingRequest)
+	data, err := os.ReadFile(s.path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return reqs, nil
+		}
+		return nil, fmt.Errorf("reading pending replies: %w", err)
+	}
+	if err := json.Unmarshal(data, &reqs); err != nil {
+		return nil, fmt.Errorf("parsing pending replies: %w", err)
+	}
+	return reqs, nil
+}
@@ -0,0 +1,134 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPendingStoreResolve(t *testing.T) {
+	store := NewPendingStore(t.TempDir())
+	now := time.Now()
+	if err := store.Add(&PendingRequest{
+		CorrelationID: "gt-mr-1",
+		MessageID:     "msg-1",
+		From:          "gastown/witness",
+		To:            "gastown/refinery",
+		Subject:       "MERGE_READY nux",
+		SentAt:        now,
+		Deadline:      now.Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	// A resent request from the requester is not a reply
+	resent := NewMessage("gastown/witness", "gastown/refinery", "MERGE_READY nux", "")
+	resent.CorrelationID = "gt-mr-1"
+	if ok, err := store.Resolve(resent); err != nil || ok {
+		t.Fatalf("Resolve(resent) = %v, %v; want false", ok, err)
+	}
+
+	// Uncorrelated mail is ignored
+	if ok, err := store.Resolve(NewMessage("gastown/refinery", "gastown/witness", "hi", "")); err != nil || ok {
+		t.Fatalf("Resolve(uncorrelated) = %v, %v; want false", ok, err)
+	}
+
+	reply := NewMessage("gastown/refinery", "gastown/witness", "MERGED nux", "")
+	reply.CorrelationID = "gt-mr-1"
+	if ok, err := store.Resolve(reply); err != nil || !ok {
+		t.Fatalf("Resolve(reply) = %v, %v; want true", ok, err)
+	}
+
+	req, err := store.Get("gt-mr-1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if req.Status() != PendingStatusReplied || req.ReplyID != reply.ID || req.ReplyFrom != "gastown/refinery" {
+		t.Errorf("request = %+v, want replied by %s", req, reply.ID)
+	}
+
+	// A settled request is not resolved twice
+	if ok, _ := store.Resolve(reply); ok {
+		t.Error("Resolve resolved an already replied request")
+	}
+}
+
+func TestPendingStoreExpireOverdue(t *testing.T) {
+	t.Chdir(t.TempDir()) // keep timeout events out of any enclosing workspace
+	store := NewPendingStore(t.TempDir())
+	now := time.Now()
+	for _, req := range []*PendingRequest{
+		{CorrelationID: "late", From: "deacon/", To: "gastown/witness", SentAt: now.Add(-2 * time.Hour), Deadline: now.Add(-time.Hour)},
+		{CorrelationID: "early", From: "deacon/", To: "gastown/witness", SentAt: now, Deadline: now.Add(time.Hour)},
+	} {
+		if err := store.Add(req); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	expired, err := store.ExpireOverdue(now)
+	if err != nil {
+		t.Fatalf("ExpireOverdue: %v", err)
+	}
+	if len(expired) != 1 || expired[0].CorrelationID != "late" {
+		t.Fatalf("expired = %+v, want [late]", expired)
+	}
+
+	// Already-expired requests are not reported again
+	if again, _ := store.ExpireOverdue(now); len(again) != 0 {
+		t.Errorf("second ExpireOverdue = %+v, want none", again)
+	}
+
+	list, err := store.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 2 || list[0].Status() != PendingStatusExpired || list[1].Status() != PendingStatusWaiting {
+		t.Errorf("List = %+v", list)
+	}
+}
+
+func TestPendingStoreAwaitReply(t *testing.T) {
+	old := pendingPollInterval
+	pendingPollInterval = 10 * time.Millisecond
+	defer func() { pendingPollInterval = old }()
+	t.Chdir(t.TempDir())
+
+	store := NewPendingStore(t.TempDir())
+	now := time.Now()
+	_ = store.Add(&PendingRequest{CorrelationID: "answered", From: "deacon/", SentAt: now, Deadline: now.Add(time.Minute)})
+	_ = store.Add(&PendingRequest{CorrelationID: "unanswered", From: "deacon/", SentAt: now, Deadline: now.Add(30 * time.Millisecond)})
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		reply := NewMessage("gastown/witness", "deacon/", "pong", "")
+		reply.CorrelationID = "answered"
+		_, _ = store.Resolve(reply)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := store.AwaitReply(ctx, "answered")
+	if err != nil || req.ReplyFrom != "gastown/witness" {
+		t.Errorf("AwaitReply(answered) = %+v, %v", req, err)
+	}
+
+	req, err = store.AwaitReply(ctx, "unanswered")
+	if !errors.Is(err, ErrReplyTimeout) || req.Status() != PendingStatusExpired {
+		t.Errorf("AwaitReply(unanswered) = %+v, %v; want ErrReplyTimeout", req, err)
+	}
+
+	if _, err := store.AwaitReply(ctx, "unknown"); !errors.Is(err, ErrNoPendingRequest) {
+		t.Errorf("AwaitReply(unknown) err = %v, want ErrNoPendingRequest", err)
+	}
+
+	// The caller's context bounds the wait
+	_ = store.Add(&PendingRequest{CorrelationID: "slow", From: "deacon/", SentAt: now, Deadline: now.Add(time.Hour)})
+	short, cancelShort := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelShort()
+	if _, err := store.AwaitReply(short, "slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("AwaitReply(slow) err = %v, want DeadlineExceeded", err)
+	}
+}
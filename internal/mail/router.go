@@ -1,11 +1,12 @@
 package mail
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
@@ -31,6 +32,9 @@ type Router struct {
 	workDir  string // fallback directory to run bd commands in
 	townRoot string // town root directory (e.g., ~/gt)
 	tmux     *tmux.Tmux
+
+	// stores returns the beads store for a .beads directory; nil for bd
+	stores func(beadsDir string) beads.Store
 }
 
 // NewRouter creates a new mail router.
@@ -189,7 +193,38 @@ func (r *Router) resolveBeadsDir(_ string) string { // address unused: all mail
 	return filepath.Join(r.townRoot, ".beads")
 }
 
+// store returns the beads store for beadsDir: bd run against it, unless
+// the router was given stores (tests).
+func (r *Router) store(beadsDir string) beads.Store {
+	if r.stores != nil {
+		return r.stores(beadsDir)
+	}
+	return beads.NewWithBeadsDir(filepath.Dir(beadsDir), beadsDir).WithTimeout(bdWriteTimeout)
+}
+
+// createMessage stores a message bead in the town beads. All mail uses
+// town-level beads, whatever the assignee.
+func (r *Router) createMessage(msg *Message, assignee string, labels []string, ephemeral bool) error {
+	beadsDir := r.resolveBeadsDir(assignee)
+	if err := r.ensureCustomTypes(beadsDir); err != nil {
+		return err
+	}
+	_, err := r.store(beadsDir).Create(beads.CreateOptions{
+		Title:       msg.Subject,
+		Description: msg.storedBody(),
+		Priority:    PriorityToBeads(msg.Priority),
+		Assignee:    assignee,
+		Labels:      labels,
+		Actor:       msg.From,
+		Ephemeral:   ephemeral,
+	})
+	return err
+}
+
 func (r *Router) ensureCustomTypes(beadsDir string) error {
+	if r.stores != nil {
+		return nil // Types are a bd database setting
+	}
 	if err := beads.EnsureCustomTypes(beadsDir); err != nil {
 		return fmt.Errorf("ensuring custom types: %w", err)
 	}
@@ -578,30 +613,27 @@ func (r *Router) queryAgents(descContains string) []*agentBead {
 
 // queryAgentsInDir queries agent beads in a specific beads directory with optional description filtering.
 func (r *Router) queryAgentsInDir(beadsDir, descContains string) ([]*agentBead, error) {
-	args := []string{"list", "--label=gt:agent", "--json", "--limit=0"}
-
-	if descContains != "" {
-		args = append(args, "--desc-contains="+descContains)
-	}
-
-	ctx, cancel := bdReadCtx()
-	defer cancel()
-	stdout, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	issues, err := r.store(beadsDir).List(beads.ListOptions{Label: "gt:agent", Priority: -1, Limit: -1})
 	if err != nil {
 		return nil, fmt.Errorf("querying agents in %s: %w", beadsDir, err)
 	}
 
-	var agents []*agentBead
-	if err := json.Unmarshal(stdout, &agents); err != nil {
-		return nil, fmt.Errorf("parsing agent query result: %w", err)
-	}
-
 	// Filter for open agents only (closed agents are inactive)
 	var active []*agentBead
-	for _, agent := range agents {
-		if agent.Status == "open" || agent.Status == "in_progress" {
-			active = append(active, agent)
+	for _, issue := range issues {
+		if issue.Status != "open" && issue.Status != "in_progress" {
+			continue
+		}
+		if descContains != "" && !strings.Contains(issue.Description, descContains) {
+			continue
 		}
+		active = append(active, &agentBead{
+			ID:          issue.ID,
+			Title:       issue.Title,
+			Description: issue.Description,
+			Status:      issue.Status,
+			CreatedBy:   issue.CreatedBy,
+		})
 	}
 
 	return active, nil
@@ -644,7 +676,66 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 // Supports single-copy delivery for:
 // - Queues (queue:name) - stores single message for worker claiming
 // - Announces (announce:name) - bulletin board, no claiming, retention-limited
+//
+// A message with a CorrelationID settles the pending request it answers
+// (see SendRequest).
 func (r *Router) Send(msg *Message) error {
+	if err := r.send(msg); err != nil {
+		return err
+	}
+	r.resolvePending(msg)
+	return nil
+}
+
+// SendRequest sends msg and records it as awaiting a reply within timeout.
+// The correlation ID defaults to the message ID; replies built with
+// NewReplyMessage carry it back. Use PendingStore.AwaitReply to wait for
+// the reply. Requests that pass their deadline unanswered are expired by
+// the daemon, which logs a mail_reply_timeout event.
+func (r *Router) SendRequest(msg *Message, timeout time.Duration) error {
+	if r.townRoot == "" {
+		return fmt.Errorf("tracking replies requires a town root")
+	}
+	if msg.ID == "" {
+		msg.ID = generateID()
+	}
+	if msg.CorrelationID == "" {
+		msg.CorrelationID = msg.ID
+	}
+
+	// Record the request before sending so a fast reply finds it
+	store := NewPendingStore(r.townRoot)
+	now := time.Now()
+	if err := store.Add(&PendingRequest{
+		CorrelationID: msg.CorrelationID,
+		MessageID:     msg.ID,
+		From:          msg.From,
+		To:            msg.To,
+		Subject:       msg.Subject,
+		SentAt:        now,
+		Deadline:      now.Add(timeout),
+	}); err != nil {
+		return fmt.Errorf("recording pending request: %w", err)
+	}
+
+	if err := r.Send(msg); err != nil {
+		_ = store.Remove(msg.CorrelationID)
+		return err
+	}
+	return nil
+}
+
+// resolvePending settles the pending request msg answers, if any.
+// Best-effort: a failure to update the store never fails the send.
+func (r *Router) resolvePending(msg *Message) {
+	if msg.CorrelationID == "" || r.townRoot == "" {
+		return
+	}
+	_, _ = NewPendingStore(r.townRoot).Resolve(msg)
+}
+
+// send routes msg by address type.
+func (r *Router) send(msg *Message) error {
 	// Check for mailing list address
 	if isListAddress(msg.To) {
 		return r.sendToList(msg)
@@ -775,7 +866,7 @@ func (r *Router) sendToSingle(msg *Message) error {
 		return fmt.Errorf("invalid recipient %q: %w", msg.To, err)
 	}
 
-	// Build labels for type, from/thread/reply-to/cc
+	// Build labels for type, from/thread/reply-to/correlation/cc
 	var labels []string
 	labels = append(labels, "gt:message")
 	labels = append(labels, "from:"+msg.From)
@@ -785,42 +876,18 @@ func (r *Router) sendToSingle(msg *Message) error {
 	if msg.ReplyTo != "" {
 		labels = append(labels, "reply-to:"+msg.ReplyTo)
 	}
+	if msg.CorrelationID != "" {
+		labels = append(labels, "correlation:"+msg.CorrelationID)
+	}
 	// Add CC labels (one per recipient)
 	for _, cc := range msg.CC {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
-	// Build command: bd create <subject> --assignee=<recipient> -d <body> --labels=gt:message,...
-	args := []string{"create", msg.Subject,
-		"--assignee", toIdentity,
-		"-d", msg.Body,
-	}
-
-	// Add priority flag
-	beadsPriority := PriorityToBeads(msg.Priority)
-	args = append(args, "--priority", fmt.Sprintf("%d", beadsPriority))
-
-	// Add labels
-	if len(labels) > 0 {
-		args = append(args, "--labels", strings.Join(labels, ","))
-	}
-
-	// Add actor for attribution (sender identity)
-	args = append(args, "--actor", msg.From)
-
-	// Add --ephemeral flag for ephemeral messages (stored in single DB, filtered from JSONL export)
-	if r.shouldBeWisp(msg) {
-		args = append(args, "--ephemeral")
-	}
-
-	beadsDir := r.resolveBeadsDir(msg.To)
-	if err := r.ensureCustomTypes(beadsDir); err != nil {
-		return err
-	}
-	ctx, cancel := bdWriteCtx()
-	defer cancel()
-	_, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	// Create <subject> assigned to the recipient, with body and labels.
+	// Ephemeral messages are stored in the same DB but filtered from JSONL export.
+	err := r.createMessage(msg, toIdentity, labels, r.shouldBeWisp(msg))
 	if err != nil {
 		return fmt.Errorf("sending message: %w", err)
 	}
@@ -887,7 +954,7 @@ func (r *Router) sendToQueue(msg *Message) error {
 		return err
 	}
 
-	// Build labels for type, from/thread/reply-to/cc plus queue metadata
+	// Build labels for type, from/thread/reply-to/correlation/cc plus queue metadata
 	var labels []string
 	labels = append(labels, "gt:message")
 	labels = append(labels, "from:"+msg.From)
@@ -898,41 +965,18 @@ func (r *Router) sendToQueue(msg *Message) error {
 	if msg.ReplyTo != "" {
 		labels = append(labels, "reply-to:"+msg.ReplyTo)
 	}
+	if msg.CorrelationID != "" {
+		labels = append(labels, "correlation:"+msg.CorrelationID)
+	}
 	for _, cc := range msg.CC {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
-	// Build command: bd create <subject> --assignee=queue:<name> -d <body>
-	// Use queue:<name> as assignee so inbox queries can filter by queue
-	args := []string{"create", msg.Subject,
-		"--assignee", msg.To, // queue:name
-		"-d", msg.Body,
-	}
-
-	// Add priority flag
-	beadsPriority := PriorityToBeads(msg.Priority)
-	args = append(args, "--priority", fmt.Sprintf("%d", beadsPriority))
-
-	// Add labels (includes queue name for filtering)
-	if len(labels) > 0 {
-		args = append(args, "--labels", strings.Join(labels, ","))
-	}
-
-	// Add actor for attribution (sender identity)
-	args = append(args, "--actor", msg.From)
-
+	// Use queue:<name> as assignee so inbox queries can filter by queue.
 	// Queue messages are never ephemeral - they need to persist until claimed
 	// (deliberately not checking shouldBeWisp)
-
-	// Queue messages go to town-level beads (shared location)
-	beadsDir := r.resolveBeadsDir("")
-	if err := r.ensureCustomTypes(beadsDir); err != nil {
-		return err
-	}
-	ctx, cancel := bdWriteCtx()
-	defer cancel()
-	_, err = runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	err = r.createMessage(msg, msg.To, labels, false)
 	if err != nil {
 		return fmt.Errorf("sending to queue %s: %w", queueName, err)
 	}
@@ -963,7 +1007,7 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 		}
 	}
 
-	// Build labels for type, from/thread/reply-to/cc plus announce metadata
+	// Build labels for type, from/thread/reply-to/correlation/cc plus announce metadata
 	var labels []string
 	labels = append(labels, "gt:message")
 	labels = append(labels, "from:"+msg.From)
@@ -974,41 +1018,18 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 	if msg.ReplyTo != "" {
 		labels = append(labels, "reply-to:"+msg.ReplyTo)
 	}
+	if msg.CorrelationID != "" {
+		labels = append(labels, "correlation:"+msg.CorrelationID)
+	}
 	for _, cc := range msg.CC {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
-	// Build command: bd create <subject> --assignee=announce:<name> -d <body>
-	// Use announce:<name> as assignee so queries can filter by channel
-	args := []string{"create", msg.Subject,
-		"--assignee", msg.To, // announce:name
-		"-d", msg.Body,
-	}
-
-	// Add priority flag
-	beadsPriority := PriorityToBeads(msg.Priority)
-	args = append(args, "--priority", fmt.Sprintf("%d", beadsPriority))
-
-	// Add labels (includes announce name for filtering)
-	if len(labels) > 0 {
-		args = append(args, "--labels", strings.Join(labels, ","))
-	}
-
-	// Add actor for attribution (sender identity)
-	args = append(args, "--actor", msg.From)
-
+	// Use announce:<name> as assignee so queries can filter by channel.
 	// Announce messages are never ephemeral - they need to persist for readers
 	// (deliberately not checking shouldBeWisp)
-
-	// Announce messages go to town-level beads (shared location)
-	beadsDir := r.resolveBeadsDir("")
-	if err := r.ensureCustomTypes(beadsDir); err != nil {
-		return err
-	}
-	ctx, cancel := bdWriteCtx()
-	defer cancel()
-	_, err = runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	err = r.createMessage(msg, msg.To, labels, false)
 	if err != nil {
 		return fmt.Errorf("sending to announce %s: %w", announceName, err)
 	}
@@ -1041,7 +1062,7 @@ func (r *Router) sendToChannel(msg *Message) error {
 		return fmt.Errorf("channel %s is closed", channelName)
 	}
 
-	// Build labels for type, from/thread/reply-to/cc plus channel metadata
+	// Build labels for type, from/thread/reply-to/correlation/cc plus channel metadata
 	var labels []string
 	labels = append(labels, "gt:message")
 	labels = append(labels, "from:"+msg.From)
@@ -1052,41 +1073,18 @@ func (r *Router) sendToChannel(msg *Message) error {
 	if msg.ReplyTo != "" {
 		labels = append(labels, "reply-to:"+msg.ReplyTo)
 	}
+	if msg.CorrelationID != "" {
+		labels = append(labels, "correlation:"+msg.CorrelationID)
+	}
 	for _, cc := range msg.CC {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
-	// Build command: bd create <subject> --assignee=channel:<name> -d <body>
-	// Use channel:<name> as assignee so queries can filter by channel
-	args := []string{"create", msg.Subject,
-		"--assignee", msg.To, // channel:name
-		"-d", msg.Body,
-	}
-
-	// Add priority flag
-	beadsPriority := PriorityToBeads(msg.Priority)
-	args = append(args, "--priority", fmt.Sprintf("%d", beadsPriority))
-
-	// Add labels (includes channel name for filtering)
-	if len(labels) > 0 {
-		args = append(args, "--labels", strings.Join(labels, ","))
-	}
-
-	// Add actor for attribution (sender identity)
-	args = append(args, "--actor", msg.From)
-
+	// Use channel:<name> as assignee so queries can filter by channel.
 	// Channel messages are never ephemeral - they persist according to retention policy
 	// (deliberately not checking shouldBeWisp)
-
-	// Channel messages go to town-level beads (shared location)
-	beadsDir := r.resolveBeadsDir("")
-	if err := r.ensureCustomTypes(beadsDir); err != nil {
-		return err
-	}
-	ctx, cancel := bdWriteCtx()
-	defer cancel()
-	_, err = runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	err = r.createMessage(msg, msg.To, labels, false)
 	if err != nil {
 		return fmt.Errorf("sending to channel %s: %w", channelName, err)
 	}
@@ -1133,30 +1131,22 @@ func (r *Router) pruneAnnounce(announceName string, retainCount int) error {
 		return err
 	}
 
-	// Query existing messages in this announce channel
-	// Use bd list with labels filter to find messages with gt:message and announce:<name> labels
-	args := []string{"list",
-		"--labels=gt:message,announce:" + announceName,
-		"--json",
-		"--limit=0", // Get all
-		"--sort=created",
-		"--asc", // Oldest first
-	}
-
-	ctx, cancel := bdReadCtx()
-	defer cancel()
-	stdout, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	// Query existing messages in this announce channel: open beads with the
+	// gt:message and announce:<name> labels, oldest first
+	store := r.store(beadsDir)
+	issues, err := store.List(beads.ListOptions{Label: "announce:" + announceName, Priority: -1, Limit: -1})
 	if err != nil {
 		return fmt.Errorf("querying announce messages: %w", err)
 	}
-
-	// Parse message list
-	var messages []struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal(stdout, &messages); err != nil {
-		return fmt.Errorf("parsing announce messages: %w", err)
+	var messages []*BeadsMessage
+	for _, issue := range issues {
+		if beads.HasLabel(issue, "gt:message") {
+			messages = append(messages, beadsMessageFromIssue(issue))
+		}
 	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
 
 	// Calculate how many to delete (we're about to add 1 more)
 	// If we have N messages and retainCount is R, we need to keep at most R-1 after pruning
@@ -1168,11 +1158,8 @@ func (r *Router) pruneAnnounce(announceName string, retainCount int) error {
 
 	// Delete oldest messages
 	for i := 0; i < toDelete && i < len(messages); i++ {
-		deleteArgs := []string{"close", messages[i].ID, "--reason=retention pruning"}
 		// Best-effort deletion - don't fail if one delete fails
-		delCtx, delCancel := bdWriteCtx()
-		_, _ = runBdCommand(delCtx, deleteArgs, filepath.Dir(beadsDir), beadsDir)
-		delCancel()
+		_ = store.CloseWithReason("retention pruning", messages[i].ID)
 	}
 
 	return nil
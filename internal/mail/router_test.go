@@ -5,6 +5,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 func TestDetectTownRoot(t *testing.T) {
@@ -1047,3 +1050,32 @@ func TestAddressToAgentBeadID(t *testing.T) {
 		})
 	}
 }
+
+func TestPruneAnnounceClosesOldest(t *testing.T) {
+	store := beads.NewMemStore("hq")
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.Now = func() time.Time { now = now.Add(time.Minute); return now }
+	var ids []string
+	for _, subject := range []string{"first", "second", "third"} {
+		issue, err := store.Create(beads.CreateOptions{Title: subject, Labels: []string{"gt:message", "announce:news"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	other, _ := store.Create(beads.CreateOptions{Title: "elsewhere", Labels: []string{"gt:message", "announce:other"}})
+
+	town := t.TempDir()
+	r := NewRouterWithTownRoot(town, town)
+	r.stores = func(string) beads.Store { return store }
+	// Room for one new message within a retention of 2
+	if err := r.pruneAnnounce("news", 2); err != nil {
+		t.Fatal(err)
+	}
+	for i, id := range append(ids, other.ID) {
+		issue, _ := store.Show(id)
+		if closed := issue.Status == "closed"; closed != (i < 2) {
+			t.Errorf("%s closed = %v", issue.Title, closed)
+		}
+	}
+}
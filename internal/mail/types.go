@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 // Priority levels for messages.
@@ -97,6 +99,11 @@ type Message struct {
 	// ReplyTo is the ID of the message this is replying to.
 	ReplyTo string `json:"reply_to,omitempty"`
 
+	// CorrelationID ties a request to its reply. A request sent with
+	// Router.SendRequest waits for a reply carrying the same ID; replies
+	// inherit it from the message they answer.
+	CorrelationID string `json:"correlation_id,omitempty"`
+
 	// Pinned marks the message as pinned (won't be auto-archived).
 	Pinned bool `json:"pinned,omitempty"`
 
@@ -123,6 +130,10 @@ type Message struct {
 	// ClaimedAt is when the queue message was claimed.
 	// Only set for queue messages after claiming.
 	ClaimedAt *time.Time `json:"claimed_at,omitempty"`
+
+	// Envelope is the structured protocol payload, set on protocol messages.
+	// Stored as a trailer line of the bead description; see envelope.go.
+	Envelope *Envelope `json:"envelope,omitempty"`
 }
 
 // NewMessage creates a new message with a generated ID and thread ID.
@@ -144,17 +155,18 @@ func NewMessage(from, to, subject, body string) *Message {
 // NewReplyMessage creates a reply message that inherits the thread from the original.
 func NewReplyMessage(from, to, subject, body string, original *Message) *Message {
 	return &Message{
-		ID:        generateID(),
-		From:      from,
-		To:        to,
-		Subject:   subject,
-		Body:      body,
-		Timestamp: time.Now(),
-		Read:      false,
-		Priority:  PriorityNormal,
-		Type:      TypeReply,
-		ThreadID:  original.ThreadID,
-		ReplyTo:   original.ID,
+		ID:            generateID(),
+		From:          from,
+		To:            to,
+		Subject:       subject,
+		Body:          body,
+		Timestamp:     time.Now(),
+		Read:          false,
+		Priority:      PriorityNormal,
+		Type:          TypeReply,
+		ThreadID:      original.ThreadID,
+		ReplyTo:       original.ID,
+		CorrelationID: original.CorrelationID,
 	}
 }
 
@@ -288,7 +300,7 @@ type BeadsMessage struct {
 	Priority    int       `json:"priority"`    // 0=urgent, 1=high, 2=normal, 3=low
 	Status      string    `json:"status"`      // open=unread, closed=read
 	CreatedAt   time.Time `json:"created_at"`
-	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X)
+	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, correlation:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X)
 	Pinned      bool      `json:"pinned,omitempty"`
 	Wisp        bool      `json:"wisp,omitempty"` // Ephemeral message (filtered from JSONL export)
 
@@ -296,6 +308,7 @@ type BeadsMessage struct {
 	sender    string
 	threadID  string
 	replyTo   string
+	corrID    string // Correlation ID (for requests and their replies)
 	msgType   string
 	cc        []string   // CC recipients
 	queue     string     // Queue name (for queue messages)
@@ -310,6 +323,7 @@ func (bm *BeadsMessage) ParseLabels() {
 	bm.sender = ""
 	bm.threadID = ""
 	bm.replyTo = ""
+	bm.corrID = ""
 	bm.msgType = ""
 	bm.cc = nil
 	bm.queue = ""
@@ -324,6 +338,8 @@ func (bm *BeadsMessage) ParseLabels() {
 			bm.threadID = strings.TrimPrefix(label, "thread:")
 		} else if strings.HasPrefix(label, "reply-to:") {
 			bm.replyTo = strings.TrimPrefix(label, "reply-to:")
+		} else if strings.HasPrefix(label, "correlation:") {
+			bm.corrID = strings.TrimPrefix(label, "correlation:")
 		} else if strings.HasPrefix(label, "msg-type:") {
 			bm.msgType = strings.TrimPrefix(label, "msg-type:")
 		} else if strings.HasPrefix(label, "cc:") {
@@ -358,6 +374,22 @@ func (bm *BeadsMessage) IsCCRecipient(identity string) bool {
 	return false
 }
 
+// beadsMessageFromIssue converts a message bead read through a beads.Store.
+func beadsMessageFromIssue(issue *beads.Issue) *BeadsMessage {
+	createdAt, _ := time.Parse(time.RFC3339Nano, issue.CreatedAt)
+	return &BeadsMessage{
+		ID:          issue.ID,
+		Title:       issue.Title,
+		Description: issue.Description,
+		Assignee:    issue.Assignee,
+		Priority:    issue.Priority,
+		Status:      issue.Status,
+		CreatedAt:   createdAt,
+		Labels:      issue.Labels,
+		Wisp:        issue.Ephemeral,
+	}
+}
+
 // ToMessage converts a BeadsMessage to a GGT Message.
 func (bm *BeadsMessage) ToMessage() *Message {
 	// Parse labels to extract metadata
@@ -383,6 +415,9 @@ func (bm *BeadsMessage) ToMessage() *Message {
 		msgType = MessageType(bm.msgType)
 	}
 
+	// Separate a protocol envelope from the readable body
+	body, envelope := splitEnvelope(bm.Description)
+
 	// Convert CC identities to addresses
 	var ccAddrs []string
 	for _, cc := range bm.cc {
@@ -390,23 +425,25 @@ func (bm *BeadsMessage) ToMessage() *Message {
 	}
 
 	return &Message{
-		ID:        bm.ID,
-		From:      identityToAddress(bm.sender),
-		To:        identityToAddress(bm.Assignee),
-		Subject:   bm.Title,
-		Body:      bm.Description,
-		Timestamp: bm.CreatedAt,
-		Read:      bm.Status == "closed" || bm.HasLabel("read"),
-		Priority:  priority,
-		Type:      msgType,
-		ThreadID:  bm.threadID,
-		ReplyTo:   bm.replyTo,
-		Wisp:      bm.Wisp,
-		CC:        ccAddrs,
-		Queue:     bm.queue,
-		Channel:   bm.channel,
-		ClaimedBy: bm.claimedBy,
-		ClaimedAt: bm.claimedAt,
+		ID:            bm.ID,
+		From:          identityToAddress(bm.sender),
+		To:            identityToAddress(bm.Assignee),
+		Subject:       bm.Title,
+		Body:          body,
+		Timestamp:     bm.CreatedAt,
+		Read:          bm.Status == "closed" || bm.HasLabel("read"),
+		Priority:      priority,
+		Type:          msgType,
+		ThreadID:      bm.threadID,
+		ReplyTo:       bm.replyTo,
+		Wisp:          bm.Wisp,
+		CC:            ccAddrs,
+		Queue:         bm.queue,
+		Channel:       bm.channel,
+		ClaimedBy:     bm.claimedBy,
+		ClaimedAt:     bm.claimedAt,
+		Envelope:      envelope,
+		CorrelationID: bm.corrID,
 	}
 }
 
@@ -195,11 +195,12 @@ func TestNewMessage(t *testing.T) {
 
 func TestNewReplyMessage(t *testing.T) {
 	original := &Message{
-		ID:       "orig-001",
-		ThreadID: "thread-001",
-		From:     "gastown/Toast",
-		To:       "mayor/",
-		Subject:  "Original Subject",
+		ID:            "orig-001",
+		ThreadID:      "thread-001",
+		From:          "gastown/Toast",
+		To:            "mayor/",
+		Subject:       "Original Subject",
+		CorrelationID: "corr-001",
 	}
 
 	reply := NewReplyMessage("mayor/", "gastown/Toast", "Re: Original Subject", "Reply body", original)
@@ -210,6 +211,9 @@ func TestNewReplyMessage(t *testing.T) {
 	if reply.ReplyTo != "orig-001" {
 		t.Errorf("ReplyTo = %q, want 'orig-001'", reply.ReplyTo)
 	}
+	if reply.CorrelationID != "corr-001" {
+		t.Errorf("CorrelationID = %q, want 'corr-001'", reply.CorrelationID)
+	}
 	if reply.From != "mayor/" {
 		t.Errorf("From = %q, want 'mayor/'", reply.From)
 	}
@@ -266,7 +270,7 @@ func TestBeadsMessageToMessageWithReplyTo(t *testing.T) {
 		Description: "Reply Body",
 		Status:      "open",
 		Assignee:    "gastown/Toast",
-		Labels:      []string{"from:mayor/", "thread:t-002", "reply-to:orig-001", "msg-type:reply"},
+		Labels:      []string{"from:mayor/", "thread:t-002", "reply-to:orig-001", "correlation:corr-001", "msg-type:reply"},
 		CreatedAt:   time.Now(),
 		Priority:    2,
 	}
@@ -279,6 +283,9 @@ func TestBeadsMessageToMessageWithReplyTo(t *testing.T) {
 	if msg.Type != TypeReply {
 		t.Errorf("Type = %q, want TypeReply", msg.Type)
 	}
+	if msg.CorrelationID != "corr-001" {
+		t.Errorf("CorrelationID = %q, want 'corr-001'", msg.CorrelationID)
+	}
 }
 
 func TestBeadsMessageToMessagePriorities(t *testing.T) {
@@ -870,3 +877,31 @@ func TestNewMessageFanOutCopiesGetUniqueIDs(t *testing.T) {
 		t.Error("copy with empty ID should fail validation before sendToSingle regenerates it")
 	}
 }
+
+func TestEnvelopeStoredInBody(t *testing.T) {
+	msg := NewMessage("gastown/refinery", "gastown/witness", "MERGED nux", "Branch: polecat/nux\n")
+	msg.Envelope = &Envelope{
+		Type:          "MERGED",
+		Version:       1,
+		CorrelationID: "msg-abc",
+		Payload:       []byte(`{"polecat":"nux"}`),
+	}
+
+	bm := BeadsMessage{ID: "hq-1", Title: msg.Subject, Description: msg.storedBody()}
+	got := bm.ToMessage()
+	if got.Body != "Branch: polecat/nux" {
+		t.Errorf("Body = %q, want envelope stripped", got.Body)
+	}
+	if got.Envelope == nil || got.Envelope.Type != "MERGED" || got.Envelope.CorrelationID != "msg-abc" {
+		t.Fatalf("Envelope = %+v", got.Envelope)
+	}
+	if string(got.Envelope.Payload) != `{"polecat":"nux"}` {
+		t.Errorf("Payload = %s", got.Envelope.Payload)
+	}
+
+	// A body that merely mentions the marker mid-text is left alone
+	plain := "see gt-envelope: docs\nthanks"
+	if body, env := splitEnvelope(plain); env != nil || body != plain {
+		t.Errorf("splitEnvelope(plain) = %q, %+v", body, env)
+	}
+}
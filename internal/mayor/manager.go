@@ -12,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 )
 
 // Common errors
@@ -101,6 +102,9 @@ func (m *Manager) Start(agentOverride string) error {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
 
+	// Record pane output so the session can be replayed after it is gone (non-fatal)
+	_ = transcript.Start(t, m.townRoot, sessionID)
+
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	envVars := config.AgentEnv(config.AgentEnvConfig{
@@ -125,7 +129,7 @@ func (m *Manager) Start(agentOverride string) error {
 	// Set auto-respawn hook so the mayor session survives tmux detach and crashes.
 	// When Claude exits (for any reason), tmux will automatically respawn it.
 	// This matches the deacon's resilience behavior (PATCH-010).
-	if err := t.SetAutoRespawnHook(sessionID); err != nil {
+	if err := t.SetAutoRespawnHook(sessionID, m.townRoot, startupCmd); err != nil {
 		// Non-fatal: Mayor still works, just won't auto-respawn
 		fmt.Printf("warning: failed to set auto-respawn hook for mayor: %v\n", err)
 	}
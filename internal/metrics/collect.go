@@ -0,0 +1,281 @@
+package metrics
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/doltserver"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/krc"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// collector adds one source's metrics to a set.
+type collector struct {
+	name string
+	fn   func(s *Set, townRoot string, now time.Time) error
+}
+
+var collectors = []collector{
+	{"rigs", collectRigs},
+	{"sessions", collectSessions},
+	{"agent_health", collectAgentHealth},
+	{"dolt", collectDolt},
+	{"escalations", collectEscalations},
+	{"krc", collectKRC},
+	{"session_deaths", collectSessionDeaths},
+}
+
+// Collect gathers the town's health metrics. Sources are best-effort: one
+// that fails (no tmux server, bd unavailable, Dolt down) reports 0 in
+// gt_metrics_collector_success and the rest are still collected.
+func Collect(townRoot string) *Set {
+	start := time.Now()
+	s := NewSet()
+	success := s.Gauge("gt_metrics_collector_success", "Whether a metrics source was collected (1) or failed (0).")
+	for _, c := range collectors {
+		ok := 1.0
+		if err := c.fn(s, townRoot, start); err != nil {
+			ok = 0
+		}
+		success.Add(ok, "collector", c.name)
+	}
+	s.Gauge("gt_metrics_collect_duration_seconds", "Time taken to collect town metrics.").
+		Add(time.Since(start).Seconds())
+	return s
+}
+
+// collectRigs reports polecats and merge queues per rig.
+func collectRigs(s *Set, townRoot string, now time.Time) error {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	rigs, err := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot)).DiscoverRigs()
+	if err != nil {
+		return err
+	}
+
+	s.Gauge("gt_rigs", "Number of registered rigs.").Add(float64(len(rigs)))
+	polecats := s.Gauge("gt_polecats", "Polecats by lifecycle state.")
+	poolActive := s.Gauge("gt_polecat_pool_active", "Polecat names in use from the rig's name pool.")
+	queueDepth := s.Gauge("gt_merge_queue_depth", "Open merge requests in the rig's merge queue.")
+	queueAge := s.Gauge("gt_merge_queue_oldest_age_seconds", "Age of the oldest open merge request.")
+
+	var firstErr error
+	for _, r := range rigs {
+		mgr := polecat.NewManager(r, git.NewGit(r.Path), nil) // nil tmux: listing only
+		if list, err := mgr.List(); err == nil {
+			byState := make(map[polecat.State]int)
+			for _, p := range list {
+				byState[p.State]++
+			}
+			for state, n := range byState {
+				polecats.Add(float64(n), "rig", r.Name, "state", string(state))
+			}
+		} else if firstErr == nil {
+			firstErr = err
+		}
+		active, _ := mgr.PoolStatus()
+		poolActive.Add(float64(active), "rig", r.Name)
+
+		queue, err := refinery.NewManager(r).Queue()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		queueDepth.Add(float64(len(queue)), "rig", r.Name)
+		var oldest time.Time
+		for _, item := range queue {
+			if item.MR != nil && !item.MR.CreatedAt.IsZero() && (oldest.IsZero() || item.MR.CreatedAt.Before(oldest)) {
+				oldest = item.MR.CreatedAt
+			}
+		}
+		if !oldest.IsZero() {
+			queueAge.Add(now.Sub(oldest).Seconds(), "rig", r.Name)
+		}
+	}
+	return firstErr
+}
+
+// collectSessions reports running agent sessions and zombies (session
+// alive, agent process dead) by rig and role.
+func collectSessions(s *Set, townRoot string, now time.Time) error {
+	t := tmux.NewTmux()
+	names, err := t.ListSessions()
+	if err != nil {
+		return err
+	}
+	sessions := s.Gauge("gt_sessions", "Running agent sessions.")
+	zombies := s.Gauge("gt_zombie_sessions", "Agent sessions whose agent process has died.")
+	for _, name := range names {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue // Not a Gas Town session
+		}
+		sessions.Inc("rig", id.Rig, "role", string(id.Role))
+		// Crew sessions may legitimately sit without an agent
+		if id.Role != session.RoleCrew && !t.IsAgentAlive(name) {
+			zombies.Inc("rig", id.Rig, "role", string(id.Role))
+		}
+	}
+	return nil
+}
+
+// collectAgentHealth reports the Deacon's health-check state per agent.
+func collectAgentHealth(s *Set, townRoot string, now time.Time) error {
+	state, err := deacon.LoadHealthCheckState(townRoot)
+	if err != nil {
+		return err
+	}
+	failures := s.Gauge("gt_agent_health_check_failures", "Consecutive failed Deacon health checks.")
+	kills := s.Counter("gt_agent_force_kills_total", "Times the Deacon force-killed the agent.")
+	for agentID, a := range state.Agents {
+		rigName, role := agentLabels(agentID)
+		failures.Add(float64(a.ConsecutiveFailures), "agent", agentID, "rig", rigName, "role", role)
+		kills.Add(float64(a.ForceKillCount), "agent", agentID, "rig", rigName, "role", role)
+	}
+	if !state.LastUpdated.IsZero() {
+		s.Gauge("gt_deacon_health_check_age_seconds", "Time since the Deacon last updated health-check state.").
+			Add(now.Sub(state.LastUpdated).Seconds())
+	}
+	return nil
+}
+
+// agentLabels returns the rig and role labels for an agent address.
+func agentLabels(address string) (rigName, role string) {
+	id, err := session.ParseAddress(address)
+	if err != nil {
+		return "", "unknown"
+	}
+	return id.Rig, string(id.Role)
+}
+
+// collectDolt reports Dolt server health.
+func collectDolt(s *Set, townRoot string, now time.Time) error {
+	running, _, err := doltserver.IsRunning(townRoot)
+	if err != nil {
+		return err
+	}
+	s.Gauge("gt_dolt_up", "Whether the Dolt SQL server is running.").Add(boolValue(running))
+	if !running {
+		return nil
+	}
+	m := doltserver.GetHealthMetrics(townRoot)
+	s.Gauge("gt_dolt_connections", "Active Dolt server connections.").Add(float64(m.Connections))
+	s.Gauge("gt_dolt_max_connections", "Configured maximum Dolt server connections.").Add(float64(m.MaxConnections))
+	s.Gauge("gt_dolt_query_latency_seconds", "Round-trip time of a SELECT 1.").Add(m.QueryLatency.Seconds())
+	s.Gauge("gt_dolt_disk_usage_bytes", "Size of the Dolt data directory.").Add(float64(m.DiskUsageBytes))
+	s.Gauge("gt_dolt_read_only", "Whether the Dolt server is in read-only mode.").Add(boolValue(m.ReadOnly))
+	s.Gauge("gt_dolt_healthy", "Whether the Dolt server is within resource limits.").Add(boolValue(m.Healthy))
+	return nil
+}
+
+// collectEscalations reports open escalations by severity.
+func collectEscalations(s *Set, townRoot string, now time.Time) error {
+	issues, err := beads.New(beads.GetTownBeadsPath(townRoot)).ListEscalations()
+	if err != nil {
+		return err
+	}
+	open := s.Gauge("gt_escalations_open", "Open escalations.")
+	for _, issue := range issues {
+		severity, acked := "unknown", "false"
+		if fields := beads.ParseEscalationFields(issue.Description); fields != nil {
+			if fields.Severity != "" {
+				severity = fields.Severity
+			}
+			if fields.AckedBy != "" {
+				acked = "true"
+			}
+		}
+		open.Inc("severity", severity, "acked", acked)
+	}
+	return nil
+}
+
+// collectKRC reports ephemeral event data held for krc pruning.
+func collectKRC(s *Set, townRoot string, now time.Time) error {
+	cfg, err := krc.LoadConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	stats, err := krc.GetStats(townRoot, cfg)
+	if err != nil {
+		return err
+	}
+	bytes := s.Gauge("gt_krc_file_bytes", "Size of the ephemeral event logs.")
+	bytes.Add(float64(stats.EventsFile.Size), "file", "events")
+	bytes.Add(float64(stats.FeedFile.Size), "file", "feed")
+	byType := s.Gauge("gt_krc_events", "Retained ephemeral events by type.")
+	expired := s.Gauge("gt_krc_expired_events", "Retained events past their TTL, awaiting pruning.")
+	for typ, info := range stats.TTLBreakdown {
+		byType.Add(float64(info.Count), "type", typ)
+		expired.Add(float64(info.Expired), "type", typ)
+	}
+	return nil
+}
+
+// deathWindows are the windows session deaths are counted over, shortest
+// first. The events log is pruned, so deaths are reported per window rather
+// than as a counter that would appear to reset.
+var deathWindows = []struct {
+	label string
+	d     time.Duration
+}{
+	{"1h", time.Hour},
+	{"24h", 24 * time.Hour},
+}
+
+// collectSessionDeaths counts session_death and mass_death events from the
+// events log by rig and role.
+func collectSessionDeaths(s *Set, townRoot string, now time.Time) error {
+	deaths := s.Gauge("gt_session_deaths", "Agent sessions that died within the window.")
+	massDeaths := s.Gauge("gt_mass_deaths", "Mass session death events within the window.")
+	for _, w := range deathWindows {
+		massDeaths.Add(0, "window", w.label)
+	}
+
+	filter := events.Filter{
+		Types: []string{events.TypeSessionDeath, events.TypeMassDeath},
+		Since: now.Add(-deathWindows[len(deathWindows)-1].d),
+	}
+	return events.Query(filepath.Join(townRoot, events.EventsFile), filter, func(ev events.Event) error {
+		ts, _ := ev.Time()
+		age := now.Sub(ts)
+		for _, w := range deathWindows {
+			if age > w.d {
+				continue
+			}
+			if ev.Type == events.TypeMassDeath {
+				massDeaths.Inc("window", w.label)
+				continue
+			}
+			rigName, role := "", "unknown"
+			if p, _ := events.DecodePayload[events.SessionDeath](ev); p.Session != "" {
+				if id, err := session.ParseSessionName(p.Session); err == nil {
+					rigName, role = id.Rig, string(id.Role)
+				}
+			}
+			deaths.Inc("rig", rigName, "role", role, "window", w.label)
+		}
+		return nil
+	})
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
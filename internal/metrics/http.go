@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minCollectInterval is how long a collected set is reused. Collection
+// shells out to bd and tmux, so scrapes closer together than this (several
+// Prometheus servers, a dashboard refresh) share one collection.
+const minCollectInterval = 10 * time.Second
+
+// Handler serves the town's metrics in the text exposition format.
+func Handler(townRoot string) http.Handler {
+	h := &handler{townRoot: townRoot}
+	return http.HandlerFunc(h.serve)
+}
+
+type handler struct {
+	townRoot string
+
+	mu        sync.Mutex
+	cached    []byte
+	collected time.Time
+}
+
+func (h *handler) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := h.render()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(body)
+}
+
+func (h *handler) render() ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.cached != nil && time.Since(h.collected) < minCollectInterval {
+		return h.cached, nil
+	}
+	var buf bytes.Buffer
+	if err := Collect(h.townRoot).WriteText(&buf); err != nil {
+		return nil, err
+	}
+	h.cached, h.collected = buf.Bytes(), time.Now()
+	return h.cached, nil
+}
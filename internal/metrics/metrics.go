@@ -0,0 +1,204 @@
+// Package metrics exports town health as Prometheus metrics.
+//
+// Health signals live in many packages: the Dolt server, the Deacon's
+// health-check state, refinery merge queues, polecat pools, escalation
+// beads, krc event stats and the session death log. Collect gathers them
+// into a Set of gauges and counters labelled by rig and role, which is
+// written in the Prometheus text exposition format, either served at
+// /metrics (gt dashboard) or written to a file for node_exporter's textfile
+// collector (gt metrics --textfile, or the daemon's metrics patrol).
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// ContentType is the content type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultTextfile returns where the daemon writes metrics unless configured
+// otherwise.
+func DefaultTextfile(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "metrics", "gastown.prom")
+}
+
+// Metric types.
+const (
+	TypeGauge   = "gauge"
+	TypeCounter = "counter"
+)
+
+// Sample is one labelled value of a metric family.
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// Label is a metric label.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Family is a named metric with its samples.
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Add appends a sample. labels alternate names and values.
+func (f *Family) Add(value float64, labels ...string) {
+	if len(labels)%2 != 0 {
+		panic(fmt.Sprintf("metrics: odd label list for %s", f.Name))
+	}
+	s := Sample{Value: value}
+	for i := 0; i < len(labels); i += 2 {
+		s.Labels = append(s.Labels, Label{Name: labels[i], Value: labels[i+1]})
+	}
+	f.Samples = append(f.Samples, s)
+}
+
+// Inc adds 1 to the sample with the given labels, creating it at 1.
+func (f *Family) Inc(labels ...string) {
+	for i := range f.Samples {
+		if sameLabels(f.Samples[i].Labels, labels) {
+			f.Samples[i].Value++
+			return
+		}
+	}
+	f.Add(1, labels...)
+}
+
+func sameLabels(have []Label, want []string) bool {
+	if len(have)*2 != len(want) {
+		return false
+	}
+	for i, l := range have {
+		if l.Name != want[2*i] || l.Value != want[2*i+1] {
+			return false
+		}
+	}
+	return true
+}
+
+// Set is a collection of metric families.
+type Set struct {
+	families []*Family
+	byName   map[string]*Family
+}
+
+// NewSet returns an empty set.
+func NewSet() *Set {
+	return &Set{byName: make(map[string]*Family)}
+}
+
+// Gauge returns the gauge family name, creating it if needed.
+func (s *Set) Gauge(name, help string) *Family {
+	return s.family(name, help, TypeGauge)
+}
+
+// Counter returns the counter family name, creating it if needed.
+// By convention counter names end in _total.
+func (s *Set) Counter(name, help string) *Family {
+	return s.family(name, help, TypeCounter)
+}
+
+func (s *Set) family(name, help, typ string) *Family {
+	if f, ok := s.byName[name]; ok {
+		return f
+	}
+	f := &Family{Name: name, Help: help, Type: typ}
+	s.byName[name] = f
+	s.families = append(s.families, f)
+	return f
+}
+
+// Families returns the families in the order they were created.
+func (s *Set) Families() []*Family {
+	return s.families
+}
+
+// WriteText writes the set in the Prometheus text exposition format.
+// Families without samples are omitted; samples are sorted by labels so
+// output is stable.
+func (s *Set) WriteText(w io.Writer) error {
+	var buf bytes.Buffer
+	for _, f := range s.families {
+		if len(f.Samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "# HELP %s %s\n", f.Name, escapeHelp(f.Help))
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", f.Name, f.Type)
+		samples := append([]Sample(nil), f.Samples...)
+		sort.SliceStable(samples, func(i, j int) bool {
+			return formatLabels(samples[i].Labels) < formatLabels(samples[j].Labels)
+		})
+		for _, sm := range samples {
+			fmt.Fprintf(&buf, "%s%s %s\n", f.Name, formatLabels(sm.Labels), formatValue(sm.Value))
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteTextfile writes the set to path for node_exporter's textfile
+// collector. The file is replaced atomically so the collector never reads
+// a partial write; path should end in .prom.
+func (s *Set) WriteTextfile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating metrics directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("writing metrics textfile: %w", err)
+	}
+	if err := s.WriteText(tmp); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	// node_exporter runs as its own user; the file must be world-readable
+	if err := os.Chmod(tmp.Name(), 0644); err != nil { //nolint:gosec // G302: metrics are non-sensitive
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = l.Name + `="` + escapeLabel(l.Value) + `"`
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
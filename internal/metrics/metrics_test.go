@@ -0,0 +1,151 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestWriteText(t *testing.T) {
+	s := NewSet()
+	depth := s.Gauge("gt_merge_queue_depth", "Open merge requests.")
+	depth.Add(3, "rig", "zeta")
+	depth.Add(1, "rig", "alpha")
+	s.Gauge("gt_unused", "No samples, not written.")
+	kills := s.Counter("gt_force_kills_total", "Force kills\nper agent.")
+	kills.Inc("agent", `say "hi"\now`)
+	kills.Inc("agent", `say "hi"\now`)
+
+	var buf bytes.Buffer
+	if err := s.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP gt_merge_queue_depth Open merge requests.
+# TYPE gt_merge_queue_depth gauge
+gt_merge_queue_depth{rig="alpha"} 1
+gt_merge_queue_depth{rig="zeta"} 3
+# HELP gt_force_kills_total Force kills\nper agent.
+# TYPE gt_force_kills_total counter
+gt_force_kills_total{agent="say \"hi\"\\now"} 2
+`
+	if buf.String() != want {
+		t.Errorf("WriteText =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteTextfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node", "gastown.prom")
+	s := NewSet()
+	s.Gauge("gt_rigs", "Rigs.").Add(2)
+	if err := s.WriteTextfile(path); err != nil {
+		t.Fatalf("WriteTextfile: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), "gt_rigs 2\n") {
+		t.Errorf("textfile = %q, %v", data, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("temp files left behind: %v", entries)
+	}
+}
+
+func TestCollectSessionDeaths(t *testing.T) {
+	town := t.TempDir()
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	lines := []string{
+		deathEvent(now.Add(-10*time.Minute), events.TypeSessionDeath, "gt-gastown-Toast"),
+		deathEvent(now.Add(-2*time.Hour), events.TypeSessionDeath, "gt-gastown-witness"),
+		deathEvent(now.Add(-48*time.Hour), events.TypeSessionDeath, "gt-gastown-Nux"),
+		deathEvent(now.Add(-30*time.Minute), events.TypeMassDeath, ""),
+		`{"ts":"2026-01-02T11:59:00Z","type":"sling","payload":{}}`,
+		`not json`,
+	}
+	if err := os.WriteFile(filepath.Join(town, events.EventsFile), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewSet()
+	if err := collectSessionDeaths(s, town, now); err != nil {
+		t.Fatalf("collectSessionDeaths: %v", err)
+	}
+	var buf bytes.Buffer
+	_ = s.WriteText(&buf)
+	out := buf.String()
+	for _, want := range []string{
+		`gt_session_deaths{rig="gastown",role="polecat",window="1h"} 1`,
+		`gt_session_deaths{rig="gastown",role="polecat",window="24h"} 1`,
+		`gt_session_deaths{rig="gastown",role="witness",window="24h"} 1`,
+		`gt_mass_deaths{window="1h"} 1`,
+		`gt_mass_deaths{window="24h"} 1`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("missing %s in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `role="witness",window="1h"`) {
+		t.Errorf("2h-old death counted in the 1h window:\n%s", out)
+	}
+}
+
+func deathEvent(ts time.Time, typ, session string) string {
+	return fmt.Sprintf(`{"ts":%q,"type":%q,"actor":"daemon","payload":{"session":%q}}`,
+		ts.Format(time.RFC3339), typ, session)
+}
+
+func TestCollectAgentHealth(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "deacon"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	state := &deacon.HealthCheckState{Agents: map[string]*deacon.AgentHealthState{
+		"gastown/polecats/max": {AgentID: "gastown/polecats/max", ConsecutiveFailures: 2, ForceKillCount: 1},
+		"deacon":               {AgentID: "deacon"},
+	}}
+	if err := deacon.SaveHealthCheckState(town, state); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewSet()
+	if err := collectAgentHealth(s, town, time.Now()); err != nil {
+		t.Fatalf("collectAgentHealth: %v", err)
+	}
+	var buf bytes.Buffer
+	_ = s.WriteText(&buf)
+	for _, want := range []string{
+		`gt_agent_health_check_failures{agent="gastown/polecats/max",rig="gastown",role="polecat"} 2`,
+		`gt_agent_force_kills_total{agent="gastown/polecats/max",rig="gastown",role="polecat"} 1`,
+		`gt_agent_health_check_failures{agent="deacon",rig="",role="deacon"} 0`,
+	} {
+		if !strings.Contains(buf.String(), want+"\n") {
+			t.Errorf("missing %s in:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := Handler(t.TempDir())
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != ContentType {
+		t.Fatalf("GET /metrics = %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), `gt_metrics_collector_success{collector="krc"}`) {
+		t.Errorf("body missing collector status:\n%s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /metrics = %d, want 405", rec.Code)
+	}
+}
@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSMTPPort is the mail submission port.
+const defaultSMTPPort = 587
+
+// EmailNotifier sends notifications through an SMTP server.
+// STARTTLS is used whenever the server offers it.
+type EmailNotifier struct {
+	channel  string
+	Host     string
+	Port     int
+	Username string // empty = no authentication
+	Password string
+	From     string
+	To       string
+}
+
+// Channel implements Notifier.
+func (e *EmailNotifier) Channel() string {
+	if e.channel == "" {
+		return "email"
+	}
+	return e.channel
+}
+
+// Notify implements Notifier.
+func (e *EmailNotifier) Notify(ctx context.Context, n *Notification) error {
+	port := e.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	addr := net.JoinHostPort(e.Host, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if e.Username != "" {
+		auth = smtp.PlainAuth("", e.Username, e.Password, e.Host)
+	}
+
+	// smtp.SendMail has no context support, so run it in the background and
+	// give up waiting if ctx is cancelled.
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, e.From, []string{e.To}, e.message(n))
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("sending email to %s via %s: %w", e.To, addr, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// message renders n as an RFC 5322 message.
+func (e *EmailNotifier) message(n *Notification) []byte {
+	date := n.Time
+	if date.IsZero() {
+		date = time.Now()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.From)
+	fmt.Fprintf(&b, "To: %s\r\n", e.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerValue(n.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	if n.EscalationID != "" {
+		fmt.Fprintf(&b, "X-Gastown-Escalation: %s\r\n", headerValue(n.EscalationID))
+	}
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(n.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// headerValue strips line breaks so user text cannot inject extra headers.
+func headerValue(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// LogNotifier appends notifications as JSON lines to the escalation log.
+type LogNotifier struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// Channel implements Notifier.
+func (l *LogNotifier) Channel() string {
+	return "log"
+}
+
+// Notify implements Notifier.
+func (l *LogNotifier) Notify(_ context.Context, n *Notification) error {
+	line, err := json.Marshal(n)
+	if err != nil {
+		return Permanent(fmt.Errorf("encoding log entry: %w", err))
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+	f, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening escalation log: %w", err)
+	}
+	defer f.Close()
+
+	// A single write of the whole line keeps concurrent appenders from interleaving.
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("writing escalation log: %w", err)
+	}
+	return nil
+}
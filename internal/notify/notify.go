@@ -0,0 +1,205 @@
+// Package notify delivers escalation notifications to humans outside Gas Town.
+//
+// Each external escalation action (email:, sms:, slack, log) maps to a
+// Notifier. Deliver wraps a Notifier with retry and exponential backoff and
+// reports the outcome so callers can record it on the escalation bead.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Delivery status values recorded on escalation beads.
+const (
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+	StatusSkipped   = "skipped"
+)
+
+// DefaultLogPath is the escalation log location relative to the town root.
+const DefaultLogPath = "logs/escalations.jsonl"
+
+// httpTimeout bounds each webhook and SMS gateway request.
+const httpTimeout = 15 * time.Second
+
+// ErrNotConfigured is returned by ForAction when the action's contact or
+// transport settings are missing from settings/escalation.json.
+var ErrNotConfigured = errors.New("not configured")
+
+// Notification is a single escalation message to deliver.
+type Notification struct {
+	EscalationID string    `json:"escalation_id"`
+	Severity     string    `json:"severity"`
+	Subject      string    `json:"subject"`
+	Body         string    `json:"body"`
+	From         string    `json:"from,omitempty"`
+	Source       string    `json:"source,omitempty"`
+	Time         time.Time `json:"time"`
+}
+
+// Notifier sends a notification over one channel.
+type Notifier interface {
+	// Channel identifies the action this notifier serves (e.g., "email:human").
+	Channel() string
+	// Notify makes a single delivery attempt.
+	Notify(ctx context.Context, n *Notification) error
+}
+
+// permanentError marks a failure that retrying cannot fix.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so Deliver stops retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var pe *permanentError
+	return errors.As(err, &pe)
+}
+
+// RetryPolicy controls how Deliver retries failed attempts.
+type RetryPolicy struct {
+	MaxAttempts    int           // total attempts, including the first
+	InitialBackoff time.Duration // delay before the first retry; doubles after each
+}
+
+// PolicyFromConfig returns the retry policy configured in settings/escalation.json.
+func PolicyFromConfig(cfg *config.EscalationConfig) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    cfg.GetDeliveryMaxAttempts(),
+		InitialBackoff: cfg.GetDeliveryInitialBackoff(),
+	}
+}
+
+// Result is the outcome of delivering one notification.
+type Result struct {
+	Channel  string
+	Status   string
+	Attempts int
+	Err      error
+}
+
+// Deliver sends n through notifier, retrying transient failures with
+// exponential backoff until the policy's attempts are used up or ctx ends.
+func Deliver(ctx context.Context, notifier Notifier, n *Notification, policy RetryPolicy) Result {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := policy.InitialBackoff
+
+	res := Result{Channel: notifier.Channel()}
+	for res.Attempts < attempts {
+		res.Attempts++
+		res.Err = notifier.Notify(ctx, n)
+		if res.Err == nil {
+			res.Status = StatusDelivered
+			return res
+		}
+		if IsPermanent(res.Err) || res.Attempts == attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			res.Err = fmt.Errorf("%w (last error: %v)", ctx.Err(), res.Err)
+			res.Status = StatusFailed
+			return res
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	res.Status = StatusFailed
+	return res
+}
+
+// ForAction builds the notifier for an external escalation action.
+// Returns (nil, nil) for actions that are not external (bead, mail:).
+// Returns an error wrapping ErrNotConfigured if required settings are missing.
+func ForAction(action string, cfg *config.EscalationConfig, townRoot string) (Notifier, error) {
+	switch {
+	case strings.HasPrefix(action, "email:"):
+		if cfg.Contacts.HumanEmail == "" {
+			return nil, fmt.Errorf("contacts.human_email %w", ErrNotConfigured)
+		}
+		smtpCfg := cfg.Delivery.SMTP
+		if smtpCfg == nil || smtpCfg.Host == "" || smtpCfg.From == "" {
+			return nil, fmt.Errorf("delivery.smtp %w", ErrNotConfigured)
+		}
+		return &EmailNotifier{
+			channel:  action,
+			Host:     smtpCfg.Host,
+			Port:     smtpCfg.Port,
+			Username: smtpCfg.Username,
+			Password: envValue(smtpCfg.PasswordEnv),
+			From:     smtpCfg.From,
+			To:       cfg.Contacts.HumanEmail,
+		}, nil
+
+	case strings.HasPrefix(action, "sms:"):
+		if cfg.Contacts.HumanSMS == "" {
+			return nil, fmt.Errorf("contacts.human_sms %w", ErrNotConfigured)
+		}
+		gw := cfg.Delivery.SMSGateway
+		if gw == nil || gw.URL == "" {
+			return nil, fmt.Errorf("delivery.sms_gateway %w", ErrNotConfigured)
+		}
+		return &SMSNotifier{
+			channel:  action,
+			URL:      gw.URL,
+			Format:   gw.Format,
+			From:     gw.From,
+			To:       cfg.Contacts.HumanSMS,
+			Username: gw.Username,
+			Password: envValue(gw.PasswordEnv),
+			Token:    envValue(gw.TokenEnv),
+			Client:   &http.Client{Timeout: httpTimeout},
+		}, nil
+
+	case action == "slack":
+		if cfg.Contacts.SlackWebhook == "" {
+			return nil, fmt.Errorf("contacts.slack_webhook %w", ErrNotConfigured)
+		}
+		return &WebhookNotifier{
+			channel: action,
+			URL:     cfg.Contacts.SlackWebhook,
+			Client:  &http.Client{Timeout: httpTimeout},
+		}, nil
+
+	case action == "log":
+		path := cfg.Delivery.LogPath
+		if path == "" {
+			path = DefaultLogPath
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(townRoot, path)
+		}
+		return &LogNotifier{Path: path}, nil
+	}
+	return nil, nil
+}
+
+// envValue returns the value of the named environment variable, or "" if name is empty.
+func envValue(name string) string {
+	if name == "" {
+		return ""
+	}
+	return os.Getenv(name)
+}
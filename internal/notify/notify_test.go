@@ -0,0 +1,344 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func testNotification() *Notification {
+	return &Notification{
+		EscalationID: "hq-esc1",
+		Severity:     "critical",
+		Subject:      "[CRITICAL] Refinery wedged",
+		Body:         "Escalation ID: hq-esc1\nSeverity: critical",
+		From:         "gastown/witness",
+		Time:         time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+// flakyNotifier fails the first failures attempts, then succeeds.
+type flakyNotifier struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyNotifier) Channel() string { return "flaky" }
+
+func (f *flakyNotifier) Notify(context.Context, *Notification) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func TestDeliver_RetriesUntilSuccess(t *testing.T) {
+	f := &flakyNotifier{failures: 2, err: errors.New("temporary")}
+	res := Deliver(context.Background(), f, testNotification(), RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+	if res.Status != StatusDelivered {
+		t.Fatalf("Status = %q, want delivered (err: %v)", res.Status, res.Err)
+	}
+	if res.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", res.Attempts)
+	}
+}
+
+func TestDeliver_GivesUp(t *testing.T) {
+	f := &flakyNotifier{failures: 10, err: errors.New("temporary")}
+	res := Deliver(context.Background(), f, testNotification(), RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+	if res.Status != StatusFailed || res.Err == nil {
+		t.Fatalf("expected failure, got %+v", res)
+	}
+	if f.calls != 3 {
+		t.Errorf("calls = %d, want 3", f.calls)
+	}
+}
+
+func TestDeliver_PermanentErrorStopsRetry(t *testing.T) {
+	f := &flakyNotifier{failures: 10, err: Permanent(errors.New("bad request"))}
+	res := Deliver(context.Background(), f, testNotification(), RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond})
+	if res.Status != StatusFailed {
+		t.Fatalf("Status = %q, want failed", res.Status)
+	}
+	if f.calls != 1 {
+		t.Errorf("calls = %d, want 1 (permanent errors are not retried)", f.calls)
+	}
+}
+
+func TestDeliver_ContextCancelledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f := &flakyNotifier{failures: 10, err: errors.New("temporary")}
+	res := Deliver(ctx, f, testNotification(), RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour})
+	if res.Status != StatusFailed || !errors.Is(res.Err, context.Canceled) {
+		t.Fatalf("expected cancellation failure, got %+v", res)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got webhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	w := &WebhookNotifier{URL: srv.URL}
+	if err := w.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if !strings.Contains(got.Text, "[CRITICAL] Refinery wedged") {
+		t.Errorf("text missing subject: %q", got.Text)
+	}
+	if got.Escalation == nil || got.Escalation.EscalationID != "hq-esc1" {
+		t.Errorf("escalation object missing: %+v", got.Escalation)
+	}
+}
+
+func TestWebhookNotifier_StatusClassification(t *testing.T) {
+	tests := []struct {
+		status    int
+		permanent bool
+	}{
+		{http.StatusBadRequest, true},
+		{http.StatusNotFound, true},
+		{http.StatusTooManyRequests, false},
+		{http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.status), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "nope", tt.status)
+			}))
+			defer srv.Close()
+
+			err := (&WebhookNotifier{URL: srv.URL}).Notify(context.Background(), testNotification())
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if IsPermanent(err) != tt.permanent {
+				t.Errorf("IsPermanent = %v, want %v (%v)", IsPermanent(err), tt.permanent, err)
+			}
+		})
+	}
+}
+
+func TestSMSNotifier_Form(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "AC123" || pass != "secret" {
+			t.Errorf("basic auth = %q/%q/%v", user, pass, ok)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("To") != "+15550001111" || r.PostForm.Get("From") != "+15559998888" {
+			t.Errorf("form = %v", r.PostForm)
+		}
+		if body := r.PostForm.Get("Body"); !strings.Contains(body, "hq-esc1") {
+			t.Errorf("Body = %q, want escalation ID", body)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	s := &SMSNotifier{URL: srv.URL, From: "+15559998888", To: "+15550001111", Username: "AC123", Password: "secret"}
+	if err := s.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+}
+
+func TestSMSNotifier_JSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer tok" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatal(err)
+		}
+		if payload["to"] != "+15550001111" || payload["message"] == "" {
+			t.Errorf("payload = %v", payload)
+		}
+	}))
+	defer srv.Close()
+
+	s := &SMSNotifier{URL: srv.URL, Format: "json", To: "+15550001111", Token: "tok"}
+	if err := s.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+}
+
+func TestSMSText_Truncates(t *testing.T) {
+	n := &Notification{Subject: strings.Repeat("é", 400)}
+	if got := []rune(smsText(n)); len(got) != maxSMSLength {
+		t.Errorf("len = %d, want %d", len(got), maxSMSLength)
+	}
+}
+
+func TestLogNotifier_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "escalations.jsonl")
+	l := &LogNotifier{Path: path}
+
+	for i := 0; i < 2; i++ {
+		if err := l.Notify(context.Background(), testNotification()); err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d", len(lines))
+	}
+	var entry Notification
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("invalid JSON line: %v", err)
+	}
+	if entry.EscalationID != "hq-esc1" || entry.Severity != "critical" {
+		t.Errorf("entry = %+v", entry)
+	}
+}
+
+// fakeSMTP accepts a single message and sends the DATA section on the returned channel.
+func fakeSMTP(t *testing.T) (host string, port int, data <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	ch := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { _, _ = io.WriteString(conn, s+"\r\n") }
+
+		reply("220 fake ESMTP")
+		var body strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					ch <- body.String()
+					reply("250 queued")
+					continue
+				}
+				body.WriteString(line)
+				continue
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 fake")
+			case cmd == "DATA":
+				inData = true
+				reply("354 go ahead")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, ch
+}
+
+func TestEmailNotifier(t *testing.T) {
+	host, port, data := fakeSMTP(t)
+	e := &EmailNotifier{Host: host, Port: port, From: "gt@example.com", To: "oncall@example.com"}
+	n := testNotification()
+	n.Subject = "[CRITICAL] line\r\nBcc: evil@example.com"
+
+	if err := e.Notify(context.Background(), n); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	select {
+	case msg := <-data:
+		if !strings.Contains(msg, "To: oncall@example.com\r\n") {
+			t.Errorf("missing To header:\n%s", msg)
+		}
+		if strings.Contains(msg, "\r\nBcc:") {
+			t.Errorf("subject injected a header:\n%s", msg)
+		}
+		if !strings.Contains(msg, "X-Gastown-Escalation: hq-esc1") {
+			t.Errorf("missing escalation header:\n%s", msg)
+		}
+		if !strings.Contains(msg, "Severity: critical") {
+			t.Errorf("missing body:\n%s", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+}
+
+func TestForAction(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg := config.NewEscalationConfig()
+
+	if n, err := ForAction("mail:mayor", cfg, townRoot); n != nil || err != nil {
+		t.Errorf("mail: should not be external, got %v, %v", n, err)
+	}
+	if _, err := ForAction("email:human", cfg, townRoot); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("email without contacts: err = %v, want ErrNotConfigured", err)
+	}
+
+	cfg.Contacts.HumanEmail = "oncall@example.com"
+	if _, err := ForAction("email:human", cfg, townRoot); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("email without smtp: err = %v, want ErrNotConfigured", err)
+	}
+
+	t.Setenv("GT_TEST_SMTP_PASSWORD", "hunter2")
+	cfg.Delivery.SMTP = &config.EscalationSMTP{Host: "smtp.example.com", From: "gt@example.com", Username: "gt", PasswordEnv: "GT_TEST_SMTP_PASSWORD"}
+	n, err := ForAction("email:human", cfg, townRoot)
+	if err != nil {
+		t.Fatalf("email: %v", err)
+	}
+	if e := n.(*EmailNotifier); e.Password != "hunter2" || e.Channel() != "email:human" {
+		t.Errorf("email notifier = %+v", e)
+	}
+
+	n, err = ForAction("log", cfg, townRoot)
+	if err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	if got, want := n.(*LogNotifier).Path, filepath.Join(townRoot, DefaultLogPath); got != want {
+		t.Errorf("log path = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxSMSLength keeps messages within a few SMS segments.
+const maxSMSLength = 320
+
+// SMSNotifier sends notifications through an HTTP SMS gateway.
+//
+// Format "form" (the default) posts To, From and Body form fields, which
+// matches Twilio's Messages API; format "json" posts {"to","from","message"}.
+type SMSNotifier struct {
+	channel  string
+	URL      string
+	Format   string
+	From     string
+	To       string
+	Username string // basic auth user; used with Password
+	Password string
+	Token    string // bearer token; used when Username is empty
+	Client   *http.Client
+}
+
+// Channel implements Notifier.
+func (s *SMSNotifier) Channel() string {
+	if s.channel == "" {
+		return "sms"
+	}
+	return s.channel
+}
+
+// Notify implements Notifier.
+func (s *SMSNotifier) Notify(ctx context.Context, n *Notification) error {
+	text := smsText(n)
+
+	var body []byte
+	var contentType string
+	if s.Format == "json" {
+		payload := map[string]string{"to": s.To, "message": text}
+		if s.From != "" {
+			payload["from"] = s.From
+		}
+		var err error
+		body, err = json.Marshal(payload)
+		if err != nil {
+			return Permanent(fmt.Errorf("encoding sms payload: %w", err))
+		}
+		contentType = "application/json"
+	} else {
+		form := url.Values{"To": {s.To}, "Body": {text}}
+		if s.From != "" {
+			form.Set("From", s.From)
+		}
+		body = []byte(form.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(fmt.Errorf("creating sms request: %w", err))
+	}
+	req.Header.Set("Content-Type", contentType)
+	switch {
+	case s.Username != "":
+		req.SetBasicAuth(s.Username, s.Password)
+	case s.Token != "":
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+
+	return doRequest(s.Client, req, "sms gateway")
+}
+
+// smsText condenses a notification into a short text message.
+func smsText(n *Notification) string {
+	text := n.Subject
+	if n.EscalationID != "" {
+		text += " (" + n.EscalationID + ")"
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if r := []rune(text); len(r) > maxSMSLength {
+		text = string(r[:maxSMSLength-3]) + "..."
+	}
+	return text
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WebhookNotifier posts notifications as Slack-compatible JSON.
+//
+// The payload's "text" field is what Slack incoming webhooks display; the
+// "escalation" object carries the structured fields for generic receivers.
+type WebhookNotifier struct {
+	channel string
+	URL     string
+	Client  *http.Client
+}
+
+// webhookPayload is the JSON body posted to the webhook.
+type webhookPayload struct {
+	Text       string        `json:"text"`
+	Escalation *Notification `json:"escalation"`
+}
+
+// Channel implements Notifier.
+func (w *WebhookNotifier) Channel() string {
+	if w.channel == "" {
+		return "webhook"
+	}
+	return w.channel
+}
+
+// Notify implements Notifier.
+func (w *WebhookNotifier) Notify(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(webhookPayload{
+		Text:       fmt.Sprintf("*%s*\n%s", n.Subject, n.Body),
+		Escalation: n,
+	})
+	if err != nil {
+		return Permanent(fmt.Errorf("encoding webhook payload: %w", err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(fmt.Errorf("creating webhook request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return doRequest(w.Client, req, "webhook")
+}
+
+// doRequest performs req and maps the response status to an error.
+// Client errors other than 408 and 429 are permanent; everything else is retried.
+func doRequest(client *http.Client, req *http.Request, what string) error {
+	if client == nil {
+		client = &http.Client{Timeout: httpTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting to %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("%s returned %s: %s", what, resp.Status, bytes.TrimSpace(snippet))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+	return err
+}
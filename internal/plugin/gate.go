@@ -0,0 +1,351 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// DefaultCooldown is used by cooldown gates that don't specify a duration.
+const DefaultCooldown = time.Hour
+
+// DefaultConditionTimeout bounds how long a condition gate's check may run.
+const DefaultConditionTimeout = 30 * time.Second
+
+// DefaultEventLookback is how far back an event gate looks for plugins that
+// have never run. Without a bound, the first evaluation would fire on events
+// from long before the plugin was installed.
+const DefaultEventLookback = 24 * time.Hour
+
+// StartupEvent is the event gate value for "run when the town starts".
+const StartupEvent = "startup"
+
+// RunHistory looks up the most recent run of a plugin. *Recorder implements it.
+type RunHistory interface {
+	GetLastRun(pluginName string) (*PluginRunBead, error)
+}
+
+// GateDecision is the result of evaluating one plugin's gate.
+type GateDecision struct {
+	Plugin  string     `json:"plugin"`
+	RigName string     `json:"rig_name,omitempty"`
+	Gate    GateType   `json:"gate"`
+	Due     bool       `json:"due"`
+	Reason  string     `json:"reason"`
+	LastRun *time.Time `json:"last_run,omitempty"`
+	// NextAt is when the gate is expected to open, if it can be predicted
+	// (cooldown and cron gates that are currently closed).
+	NextAt *time.Time `json:"next_at,omitempty"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// GateEvaluator decides which plugins are due to run.
+//
+// Gates are evaluated against the plugin run history on the ledger and the
+// town event log; the evaluator holds no state of its own.
+type GateEvaluator struct {
+	// History provides last-run lookups for cooldown, cron and event gates.
+	History RunHistory
+
+	// EventsPath is the town's .events.jsonl, used by event gates.
+	EventsPath string
+
+	// WorkDir is where condition checks run (usually the town root).
+	WorkDir string
+
+	// ConditionTimeout bounds condition checks (default DefaultConditionTimeout).
+	ConditionTimeout time.Duration
+
+	// EventLookback bounds event gates for plugins with no recorded run
+	// (default DefaultEventLookback).
+	EventLookback time.Duration
+
+	// Now returns the current time (default time.Now).
+	Now func() time.Time
+
+	events    []events.Event
+	eventsErr error
+	loaded    bool
+}
+
+// NewGateEvaluator creates an evaluator for the town at townRoot.
+func NewGateEvaluator(townRoot string) *GateEvaluator {
+	return &GateEvaluator{
+		History:    NewRecorder(townRoot),
+		EventsPath: filepath.Join(townRoot, events.EventsFile),
+		WorkDir:    townRoot,
+	}
+}
+
+// EvaluateAll evaluates every plugin's gate. The event log is read once and
+// shared across plugins.
+func (e *GateEvaluator) EvaluateAll(ctx context.Context, plugins []*Plugin) []GateDecision {
+	decisions := make([]GateDecision, 0, len(plugins))
+	for _, p := range plugins {
+		decisions = append(decisions, e.Evaluate(ctx, p))
+	}
+	return decisions
+}
+
+// Evaluate decides whether p's gate is open now.
+// Errors are reported in the decision (with Due=false) rather than returned,
+// so one broken plugin doesn't stop the others from being evaluated.
+func (e *GateEvaluator) Evaluate(ctx context.Context, p *Plugin) GateDecision {
+	d := GateDecision{Plugin: p.Name, RigName: p.RigName, Gate: GateManual}
+	if p.Gate == nil || p.Gate.Type == "" || p.Gate.Type == GateManual {
+		d.Reason = "manual gate: dispatch explicitly with 'gt plugin run'"
+		return d
+	}
+	d.Gate = p.Gate.Type
+
+	var err error
+	switch p.Gate.Type {
+	case GateCooldown:
+		err = e.evalCooldown(p, &d)
+	case GateCron:
+		err = e.evalCron(p, &d)
+	case GateCondition:
+		err = e.evalCondition(ctx, p, &d)
+	case GateEvent:
+		err = e.evalEvent(p, &d)
+	default:
+		err = fmt.Errorf("unknown gate type %q", p.Gate.Type)
+	}
+	if err != nil {
+		d.Due = false
+		d.Error = err.Error()
+		d.Reason = "gate error: " + err.Error()
+	}
+	return d
+}
+
+func (e *GateEvaluator) now() time.Time {
+	if e.Now != nil {
+		return e.Now()
+	}
+	return time.Now()
+}
+
+// lastRun returns the time of the plugin's most recent run, or nil.
+func (e *GateEvaluator) lastRun(p *Plugin, d *GateDecision) (*time.Time, error) {
+	if e.History == nil {
+		return nil, nil
+	}
+	run, err := e.History.GetLastRun(p.Name)
+	if err != nil {
+		return nil, fmt.Errorf("querying last run: %w", err)
+	}
+	if run == nil {
+		return nil, nil
+	}
+	t := run.CreatedAt
+	d.LastRun = &t
+	return &t, nil
+}
+
+func (e *GateEvaluator) evalCooldown(p *Plugin, d *GateDecision) error {
+	cooldown := DefaultCooldown
+	if p.Gate.Duration != "" {
+		parsed, err := time.ParseDuration(p.Gate.Duration)
+		if err != nil {
+			return fmt.Errorf("invalid cooldown duration %q: %w", p.Gate.Duration, err)
+		}
+		cooldown = parsed
+	}
+
+	last, err := e.lastRun(p, d)
+	if err != nil {
+		return err
+	}
+	if last == nil {
+		d.Due = true
+		d.Reason = "never run"
+		return nil
+	}
+
+	next := last.Add(cooldown)
+	if !e.now().Before(next) {
+		d.Due = true
+		d.Reason = fmt.Sprintf("cooldown %s elapsed (last run %s ago)", cooldown, roundAge(e.now().Sub(*last)))
+		return nil
+	}
+	d.NextAt = &next
+	d.Reason = fmt.Sprintf("cooldown: last run %s ago, %s remaining", roundAge(e.now().Sub(*last)), roundAge(next.Sub(e.now())))
+	return nil
+}
+
+func (e *GateEvaluator) evalCron(p *Plugin, d *GateDecision) error {
+	if p.Gate.Schedule == "" {
+		return fmt.Errorf("cron gate has no schedule")
+	}
+	sched, err := ParseCron(p.Gate.Schedule)
+	if err != nil {
+		return err
+	}
+
+	now := e.now()
+	last, err := e.lastRun(p, d)
+	if err != nil {
+		return err
+	}
+
+	// A plugin that has never run is due if a slot passed in the last day,
+	// so a daily job installed at 10:00 still fires for today's 09:00 slot.
+	since := now.Add(-24 * time.Hour)
+	if last != nil {
+		since = *last
+	}
+
+	if slot := sched.Next(since); !slot.IsZero() && !slot.After(now) {
+		d.Due = true
+		d.Reason = fmt.Sprintf("cron %q: scheduled at %s", p.Gate.Schedule, slot.Format("2006-01-02 15:04"))
+		return nil
+	}
+
+	next := sched.Next(now)
+	if next.IsZero() {
+		d.Reason = fmt.Sprintf("cron %q: no upcoming run", p.Gate.Schedule)
+		return nil
+	}
+	d.NextAt = &next
+	d.Reason = fmt.Sprintf("cron %q: next at %s", p.Gate.Schedule, next.Format("2006-01-02 15:04"))
+	return nil
+}
+
+func (e *GateEvaluator) evalCondition(ctx context.Context, p *Plugin, d *GateDecision) error {
+	if p.Gate.Check == "" {
+		return fmt.Errorf("condition gate has no check command")
+	}
+
+	timeout := e.ConditionTimeout
+	if timeout <= 0 {
+		timeout = DefaultConditionTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", p.Gate.Check) //nolint:gosec // G204: check comes from the plugin definition
+	cmd.Dir = e.WorkDir
+	cmd.Env = append(os.Environ(), "GT_PLUGIN="+p.Name, "GT_PLUGIN_DIR="+p.Path)
+	if p.RigName != "" {
+		cmd.Env = append(cmd.Env, "GT_RIG="+p.RigName)
+	}
+	err := cmd.Run()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("check %q timed out after %s", p.Gate.Check, timeout)
+	}
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			d.Reason = fmt.Sprintf("condition %q: %v", p.Gate.Check, err)
+			return nil
+		}
+		return fmt.Errorf("running check %q: %w", p.Gate.Check, err)
+	}
+	d.Due = true
+	d.Reason = fmt.Sprintf("condition %q passed", p.Gate.Check)
+	return nil
+}
+
+func (e *GateEvaluator) evalEvent(p *Plugin, d *GateDecision) error {
+	if p.Gate.On == "" {
+		return fmt.Errorf("event gate has no 'on' value")
+	}
+
+	last, err := e.lastRun(p, d)
+	if err != nil {
+		return err
+	}
+	lookback := e.EventLookback
+	if lookback <= 0 {
+		lookback = DefaultEventLookback
+	}
+	since := e.now().Add(-lookback)
+	if last != nil {
+		since = *last
+	}
+
+	evs, err := e.loadEvents()
+	if err != nil {
+		return err
+	}
+
+	// Newest matching event wins; the log is append-only so scan backwards.
+	for i := len(evs) - 1; i >= 0; i-- {
+		ev := evs[i]
+		if !eventMatches(p.Gate.On, ev) {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, ev.Timestamp)
+		if err != nil {
+			continue
+		}
+		if !ts.After(since) {
+			break
+		}
+		d.Due = true
+		d.Reason = fmt.Sprintf("event %q at %s", ev.Type, ts.Local().Format("2006-01-02 15:04:05"))
+		if ev.Actor != "" {
+			d.Reason += " by " + ev.Actor
+		}
+		return nil
+	}
+
+	if last != nil {
+		d.Reason = fmt.Sprintf("waiting for %q event since last run", p.Gate.On)
+	} else {
+		d.Reason = fmt.Sprintf("waiting for %q event", p.Gate.On)
+	}
+	return nil
+}
+
+// eventMatches reports whether ev satisfies an event gate's "on" value.
+// "startup" matches town boot (gt up) and Deacon session starts; any other
+// value is compared with the event type.
+func eventMatches(on string, ev events.Event) bool {
+	if on == StartupEvent {
+		if ev.Type == events.TypeBoot {
+			return true
+		}
+		if ev.Type == events.TypeSessionStart {
+			p, _ := events.DecodePayload[events.Session](ev)
+			return p.Role == "deacon" || strings.HasPrefix(ev.Actor, "deacon")
+		}
+		return false
+	}
+	return ev.Type == on
+}
+
+// loadEvents reads the event log once per evaluator.
+func (e *GateEvaluator) loadEvents() ([]events.Event, error) {
+	if e.loaded {
+		return e.events, e.eventsErr
+	}
+	e.loaded = true
+
+	if e.EventsPath == "" {
+		return nil, nil
+	}
+	err := events.Query(e.EventsPath, events.Filter{}, func(ev events.Event) error {
+		e.events = append(e.events, ev)
+		return nil
+	})
+	if err != nil {
+		e.eventsErr = fmt.Errorf("reading events log: %w", err)
+	}
+	return e.events, e.eventsErr
+}
+
+// roundAge formats a duration for gate reasons ("2h5m", "45s").
+func roundAge(d time.Duration) string {
+	if d >= time.Minute {
+		return d.Round(time.Minute).String()
+	}
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,210 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// fakeHistory is a RunHistory backed by a map.
+type fakeHistory struct {
+	runs map[string]time.Time
+	err  error
+}
+
+func (f *fakeHistory) GetLastRun(name string) (*PluginRunBead, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	t, ok := f.runs[name]
+	if !ok {
+		return nil, nil
+	}
+	return &PluginRunBead{CreatedAt: t, Result: ResultSuccess}, nil
+}
+
+var gateNow = time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+func newTestEvaluator(t *testing.T, runs map[string]time.Time) *GateEvaluator {
+	t.Helper()
+	dir := t.TempDir()
+	return &GateEvaluator{
+		History:    &fakeHistory{runs: runs},
+		EventsPath: filepath.Join(dir, ".events.jsonl"),
+		WorkDir:    dir,
+		Now:        func() time.Time { return gateNow },
+	}
+}
+
+func gated(name string, gate *Gate) *Plugin {
+	return &Plugin{Name: name, Gate: gate}
+}
+
+func TestEvaluate_Manual(t *testing.T) {
+	e := newTestEvaluator(t, nil)
+	for _, p := range []*Plugin{
+		gated("no-gate", nil),
+		gated("manual", &Gate{Type: GateManual}),
+	} {
+		d := e.Evaluate(context.Background(), p)
+		if d.Due || d.Gate != GateManual {
+			t.Errorf("%s: decision = %+v, want manual and not due", p.Name, d)
+		}
+	}
+}
+
+func TestEvaluate_Cooldown(t *testing.T) {
+	e := newTestEvaluator(t, map[string]time.Time{
+		"recent": gateNow.Add(-20 * time.Minute),
+		"old":    gateNow.Add(-2 * time.Hour),
+	})
+
+	if d := e.Evaluate(context.Background(), gated("never", &Gate{Type: GateCooldown, Duration: "1h"})); !d.Due {
+		t.Errorf("never run: decision = %+v, want due", d)
+	}
+	if d := e.Evaluate(context.Background(), gated("old", &Gate{Type: GateCooldown, Duration: "1h"})); !d.Due {
+		t.Errorf("old run: decision = %+v, want due", d)
+	}
+
+	d := e.Evaluate(context.Background(), gated("recent", &Gate{Type: GateCooldown, Duration: "1h"}))
+	if d.Due {
+		t.Errorf("recent run: decision = %+v, want not due", d)
+	}
+	if d.NextAt == nil || !d.NextAt.Equal(gateNow.Add(40*time.Minute)) {
+		t.Errorf("recent run: NextAt = %v, want %s", d.NextAt, gateNow.Add(40*time.Minute))
+	}
+
+	if d := e.Evaluate(context.Background(), gated("bad", &Gate{Type: GateCooldown, Duration: "soon"})); d.Due || d.Error == "" {
+		t.Errorf("bad duration: decision = %+v, want error", d)
+	}
+}
+
+func TestEvaluate_HistoryError(t *testing.T) {
+	e := newTestEvaluator(t, nil)
+	e.History = &fakeHistory{err: errors.New("bd not found")}
+	d := e.Evaluate(context.Background(), gated("p", &Gate{Type: GateCooldown, Duration: "1h"}))
+	if d.Due || !strings.Contains(d.Error, "bd not found") {
+		t.Errorf("decision = %+v, want history error", d)
+	}
+}
+
+func TestEvaluate_Cron(t *testing.T) {
+	e := newTestEvaluator(t, map[string]time.Time{
+		"ran-today":     time.Date(2025, 1, 15, 9, 0, 30, 0, time.UTC),
+		"ran-yesterday": time.Date(2025, 1, 14, 9, 0, 30, 0, time.UTC),
+	})
+	gate := &Gate{Type: GateCron, Schedule: "0 9 * * *"}
+
+	// Never run, and today's 09:00 slot has passed
+	if d := e.Evaluate(context.Background(), gated("never", gate)); !d.Due {
+		t.Errorf("never run: decision = %+v, want due", d)
+	}
+	if d := e.Evaluate(context.Background(), gated("ran-yesterday", gate)); !d.Due {
+		t.Errorf("ran yesterday: decision = %+v, want due", d)
+	}
+
+	d := e.Evaluate(context.Background(), gated("ran-today", gate))
+	if d.Due {
+		t.Errorf("ran today: decision = %+v, want not due", d)
+	}
+	want := time.Date(2025, 1, 16, 9, 0, 0, 0, time.UTC)
+	if d.NextAt == nil || !d.NextAt.Equal(want) {
+		t.Errorf("ran today: NextAt = %v, want %s", d.NextAt, want)
+	}
+
+	if d := e.Evaluate(context.Background(), gated("bad", &Gate{Type: GateCron, Schedule: "daily"})); d.Error == "" {
+		t.Errorf("bad schedule: decision = %+v, want error", d)
+	}
+}
+
+func TestEvaluate_Condition(t *testing.T) {
+	e := newTestEvaluator(t, nil)
+
+	if d := e.Evaluate(context.Background(), gated("pass", &Gate{Type: GateCondition, Check: "true"})); !d.Due {
+		t.Errorf("passing check: decision = %+v, want due", d)
+	}
+	if d := e.Evaluate(context.Background(), gated("fail", &Gate{Type: GateCondition, Check: "exit 3"})); d.Due || d.Error != "" {
+		t.Errorf("failing check: decision = %+v, want closed without error", d)
+	}
+
+	// Checks run in WorkDir with the plugin name in the environment
+	marker := filepath.Join(e.WorkDir, "ready-env-plugin")
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if d := e.Evaluate(context.Background(), gated("env-plugin", &Gate{Type: GateCondition, Check: `test -f "ready-$GT_PLUGIN"`})); !d.Due {
+		t.Errorf("env check: decision = %+v, want due", d)
+	}
+
+	e.ConditionTimeout = 100 * time.Millisecond
+	d := e.Evaluate(context.Background(), gated("slow", &Gate{Type: GateCondition, Check: "sleep 5"}))
+	if d.Due || !strings.Contains(d.Error, "timed out") {
+		t.Errorf("slow check: decision = %+v, want timeout error", d)
+	}
+}
+
+func TestEvaluate_Event(t *testing.T) {
+	e := newTestEvaluator(t, map[string]time.Time{
+		"after-boot": gateNow.Add(-30 * time.Minute),
+	})
+	log := strings.Join([]string{
+		`{"ts":"2025-01-13T08:00:00Z","source":"gt","type":"merged","actor":"gastown/refinery"}`,
+		`not json`,
+		`{"ts":"2025-01-15T09:00:00Z","source":"gt","type":"boot","actor":"mayor"}`,
+		`{"ts":"2025-01-15T09:10:00Z","source":"gt","type":"session_start","actor":"gastown/polecats/nux","payload":{"role":"polecat"}}`,
+	}, "\n") + "\n"
+	if err := os.WriteFile(e.EventsPath, []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Never run: boot within the lookback window opens the startup gate
+	d := e.Evaluate(context.Background(), gated("never", &Gate{Type: GateEvent, On: StartupEvent}))
+	if !d.Due || !strings.Contains(d.Reason, "boot") {
+		t.Errorf("never run: decision = %+v, want due on boot", d)
+	}
+
+	// Ran after the boot: closed until the next one
+	if d := e.Evaluate(context.Background(), gated("after-boot", &Gate{Type: GateEvent, On: StartupEvent})); d.Due {
+		t.Errorf("after boot: decision = %+v, want not due", d)
+	}
+
+	// A merge two days ago is outside the default lookback
+	if d := e.Evaluate(context.Background(), gated("merges", &Gate{Type: GateEvent, On: "merged"})); d.Due {
+		t.Errorf("old merge: decision = %+v, want not due", d)
+	}
+	e.EventLookback = 7 * 24 * time.Hour
+	if d := e.Evaluate(context.Background(), gated("merges", &Gate{Type: GateEvent, On: "merged"})); !d.Due {
+		t.Errorf("merge within lookback: decision = %+v, want due", d)
+	}
+
+	// Polecat session starts are not startup events
+	if eventMatches(StartupEvent, mustEvent(`{"type":"session_start","actor":"gastown/polecats/nux","payload":{"role":"polecat"}}`)) {
+		t.Error("polecat session_start should not match startup")
+	}
+	if !eventMatches(StartupEvent, mustEvent(`{"type":"session_start","actor":"deacon","payload":{"role":"deacon"}}`)) {
+		t.Error("deacon session_start should match startup")
+	}
+}
+
+func TestEvaluate_EventMissingLog(t *testing.T) {
+	e := newTestEvaluator(t, nil)
+	d := e.Evaluate(context.Background(), gated("p", &Gate{Type: GateEvent, On: StartupEvent}))
+	if d.Due || d.Error != "" {
+		t.Errorf("decision = %+v, want closed without error when no event log", d)
+	}
+}
+
+func mustEvent(line string) events.Event {
+	var ev events.Event
+	if err := json.Unmarshal([]byte(line), &ev); err != nil {
+		panic(err)
+	}
+	return ev
+}
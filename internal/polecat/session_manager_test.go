@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session/sessiontest"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -292,3 +293,45 @@ func TestSessionManager_resolveBeadsDir(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionManagerWithBackend(t *testing.T) {
+	r := &rig.Rig{
+		Name:     "gastown",
+		Polecats: []string{"Toast"},
+	}
+	backend := sessiontest.New("gt-gastown-Toast", "gt-gastown-witness", "gt-other-Toast")
+	backend.Session("gt-gastown-Toast").Output = "one\ntwo\nthree\n"
+	m := NewSessionManager(backend, r)
+
+	infos, err := m.ListPolecats()
+	if err != nil {
+		t.Fatalf("ListPolecats: %v", err)
+	}
+	if len(infos) != 1 || infos[0].Polecat != "Toast" {
+		t.Errorf("ListPolecats = %+v, want only Toast", infos)
+	}
+
+	if out, err := m.Capture("Toast", 2); err != nil || out != "two\nthree" {
+		t.Errorf("Capture = %q, %v; want last 2 lines", out, err)
+	}
+	if err := m.Inject("Toast", "hello"); err != nil {
+		t.Fatalf("Inject: %v", err)
+	}
+	if keys := backend.Session("gt-gastown-Toast").Keys; len(keys) != 1 || keys[0] != "hello" {
+		t.Errorf("sent keys = %v, want [hello]", keys)
+	}
+
+	info, err := m.Status("Toast")
+	if err != nil || !info.Running {
+		t.Errorf("Status = %+v, %v; want running", info, err)
+	}
+	if err := m.Stop("Toast", false); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if running, _ := m.IsRunning("Toast"); running {
+		t.Error("session still running after Stop")
+	}
+	if err := m.Stop("Toast", false); err != ErrSessionNotFound {
+		t.Errorf("second Stop = %v, want ErrSessionNotFound", err)
+	}
+}
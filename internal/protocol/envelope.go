@@ -0,0 +1,251 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// Version is the current payload schema version. Bump it when a payload
+// changes incompatibly; receivers reject envelopes newer than they know.
+const Version = 1
+
+// ErrUnsupportedVersion is returned when an envelope was written with a newer
+// schema version than this build understands.
+var ErrUnsupportedVersion = errors.New("unsupported protocol version")
+
+// ErrUnknownType is returned when a message is not a known protocol message.
+var ErrUnknownType = errors.New("unknown protocol message type")
+
+// Payload is the typed body of a protocol message.
+type Payload interface {
+	// Type returns the protocol message type the payload belongs to.
+	Type() MessageType
+
+	// Validate checks that the payload's required fields are set.
+	Validate() error
+
+	// subject and body render the legacy text form, which keeps messages
+	// readable by agents and by receivers that predate the envelope.
+	subject() string
+	body() string
+}
+
+// schemas maps each message type to a constructor for its payload and a
+// parser for its legacy text form.
+var schemas = map[MessageType]struct {
+	newPayload  func() Payload
+	parseLegacy func(subject, body string) Payload
+}{
+	TypeMergeReady: {
+		func() Payload { return &MergeReadyPayload{} },
+		func(subject, body string) Payload { return parseMergeReady(body) },
+	},
+	TypeMerged: {
+		func() Payload { return &MergedPayload{} },
+		func(subject, body string) Payload { return parseMerged(body) },
+	},
+	TypeMergeFailed: {
+		func() Payload { return &MergeFailedPayload{} },
+		func(subject, body string) Payload { return parseMergeFailed(body) },
+	},
+	TypeReworkRequest: {
+		func() Payload { return &ReworkRequestPayload{} },
+		func(subject, body string) Payload { return parseReworkRequest(body) },
+	},
+	TypePolecatDone: {
+		func() Payload { return &PolecatDonePayload{} },
+		parsePolecatDone,
+	},
+	TypeLifecycleShutdown: {
+		func() Payload { return &LifecycleShutdownPayload{} },
+		parseLifecycleShutdown,
+	},
+	TypeHelp: {
+		func() Payload { return &HelpPayload{} },
+		parseHelp,
+	},
+	TypeSwarmStart: {
+		func() Payload { return &SwarmStartPayload{} },
+		parseSwarmStart,
+	},
+}
+
+// NewMessage builds a protocol message carrying payload in its envelope,
+// with the legacy subject and body rendered from it so the message stays
+// readable. The payload is validated when the message is sent (see Send).
+func NewMessage(from, to string, payload Payload) *mail.Message {
+	msg := mail.NewMessage(from, to, payload.subject(), payload.body())
+	// Payloads are plain structs; Marshal cannot fail on them
+	data, _ := json.Marshal(payload)
+	msg.Envelope = &mail.Envelope{
+		Type:          string(payload.Type()),
+		Version:       Version,
+		CorrelationID: msg.ID,
+		Payload:       data,
+	}
+	msg.CorrelationID = msg.ID
+	return msg
+}
+
+// Correlate sets the correlation ID of msg and its envelope, tying it to
+// the request it answers (or, for a request, to the reply it expects).
+func Correlate(msg *mail.Message, id string) {
+	msg.CorrelationID = id
+	if msg.Envelope != nil {
+		msg.Envelope.CorrelationID = id
+	}
+}
+
+// Classify returns the protocol type of msg, preferring the envelope over
+// the subject. Returns empty string for non-protocol messages.
+func Classify(msg *mail.Message) MessageType {
+	if msg.Envelope != nil {
+		if _, ok := schemas[MessageType(msg.Envelope.Type)]; ok {
+			return MessageType(msg.Envelope.Type)
+		}
+	}
+	return ParseMessageType(msg.Subject)
+}
+
+// Decode returns the validated payload of a protocol message. Messages with
+// an envelope are decoded from it; others fall back to the legacy text
+// format, taking the polecat name from the subject and the rig from the
+// sender address when the body omits them.
+func Decode(msg *mail.Message) (Payload, error) {
+	if env := msg.Envelope; env != nil {
+		if s, ok := schemas[MessageType(env.Type)]; ok {
+			if env.Version > Version {
+				return nil, fmt.Errorf("%s v%d: %w", env.Type, env.Version, ErrUnsupportedVersion)
+			}
+			payload := s.newPayload()
+			if err := json.Unmarshal(env.Payload, payload); err != nil {
+				return nil, fmt.Errorf("decoding %s payload: %w", env.Type, err)
+			}
+			if err := payload.Validate(); err != nil {
+				return nil, err
+			}
+			return payload, nil
+		}
+	}
+
+	msgType := ParseMessageType(msg.Subject)
+	s, ok := schemas[msgType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownType, msg.Subject)
+	}
+	payload := s.parseLegacy(msg.Subject, msg.Body)
+	fillLegacyDefaults(payload, firstField(subjectArg(msgType, msg.Subject)), rigFromAddress(msg.From))
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// DecodeAs decodes msg and checks that it carries a payload of type P.
+func DecodeAs[P Payload](msg *mail.Message) (P, error) {
+	var zero P
+	payload, err := Decode(msg)
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := payload.(P)
+	if !ok {
+		return zero, fmt.Errorf("expected %T, got %s message", zero, payload.Type())
+	}
+	return typed, nil
+}
+
+// Validate checks a message's envelope against its payload schema.
+// Messages without an envelope are not protocol-typed and pass.
+func Validate(msg *mail.Message) error {
+	if msg.Envelope == nil {
+		return nil
+	}
+	if _, ok := schemas[MessageType(msg.Envelope.Type)]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownType, msg.Envelope.Type)
+	}
+	_, err := Decode(msg)
+	return err
+}
+
+// Sender delivers mail; *mail.Router implements it.
+type Sender interface {
+	Send(msg *mail.Message) error
+}
+
+// Send validates a protocol message against its schema and sends it.
+func Send(router Sender, msg *mail.Message) error {
+	if err := Validate(msg); err != nil {
+		return fmt.Errorf("invalid protocol message: %w", err)
+	}
+	return router.Send(msg)
+}
+
+// Requester delivers mail that expects a reply; *mail.Router implements it.
+type Requester interface {
+	SendRequest(msg *mail.Message, timeout time.Duration) error
+}
+
+// SendRequest validates a protocol message and sends it as a request that
+// expects a correlated reply within timeout.
+func SendRequest(router Requester, msg *mail.Message, timeout time.Duration) error {
+	if err := Validate(msg); err != nil {
+		return fmt.Errorf("invalid protocol message: %w", err)
+	}
+	return router.SendRequest(msg, timeout)
+}
+
+// fillLegacyDefaults fills fields that legacy senders carried only in the
+// subject or sender address.
+func fillLegacyDefaults(payload Payload, subjectArg, rig string) {
+	switch p := payload.(type) {
+	case *MergeReadyPayload:
+		p.Polecat = firstNonEmpty(p.Polecat, subjectArg)
+		p.Rig = firstNonEmpty(p.Rig, rig)
+	case *MergedPayload:
+		p.Polecat = firstNonEmpty(p.Polecat, subjectArg)
+		p.Rig = firstNonEmpty(p.Rig, rig)
+	case *MergeFailedPayload:
+		p.Polecat = firstNonEmpty(p.Polecat, subjectArg)
+		p.Rig = firstNonEmpty(p.Rig, rig)
+	case *ReworkRequestPayload:
+		p.Polecat = firstNonEmpty(p.Polecat, subjectArg)
+		p.Rig = firstNonEmpty(p.Rig, rig)
+	}
+}
+
+// rigFromAddress returns the rig of a rig-scoped address ("gastown/witness"
+// → "gastown"), or "" for town-level addresses like "mayor/".
+func rigFromAddress(addr string) string {
+	parts := strings.Split(addr, "/")
+	if len(parts) < 2 || parts[1] == "" {
+		return ""
+	}
+	return parts[0]
+}
+
+func firstNonEmpty(a, b string) string {
+	if a != "" {
+		return a
+	}
+	return b
+}
+
+// requireFields returns an error naming the required fields that are empty.
+func requireFields(msgType MessageType, fields ...[2]string) error {
+	var missing []string
+	for _, f := range fields {
+		if f[1] == "" {
+			missing = append(missing, f[0])
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("invalid %s payload: missing required fields: %s", msgType, strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -33,10 +33,11 @@ func (r *HandlerRegistry) Register(msgType MessageType, handler Handler) {
 	r.handlers[msgType] = handler
 }
 
-// Handle dispatches a message to the appropriate handler.
+// Handle dispatches a message to the appropriate handler, keyed on the
+// envelope type (or the legacy subject prefix for messages without one).
 // Returns an error if no handler is registered for the message type.
 func (r *HandlerRegistry) Handle(msg *mail.Message) error {
-	msgType := ParseMessageType(msg.Subject)
+	msgType := Classify(msg)
 	if msgType == "" {
 		return fmt.Errorf("unknown message type for subject: %s", msg.Subject)
 	}
@@ -51,7 +52,7 @@ func (r *HandlerRegistry) Handle(msg *mail.Message) error {
 
 // CanHandle returns true if a handler is registered for the message's type.
 func (r *HandlerRegistry) CanHandle(msg *mail.Message) bool {
-	msgType := ParseMessageType(msg.Subject)
+	msgType := Classify(msg)
 	if msgType == "" {
 		return false
 	}
@@ -85,7 +86,7 @@ func WrapWitnessHandlers(h WitnessHandler) *HandlerRegistry {
 	registry := NewHandlerRegistry()
 
 	registry.Register(TypeMerged, func(msg *mail.Message) error {
-		payload, err := ParseMergedPayload(msg.Body)
+		payload, err := DecodeAs[*MergedPayload](msg)
 		if err != nil {
 			return err
 		}
@@ -93,7 +94,7 @@ func WrapWitnessHandlers(h WitnessHandler) *HandlerRegistry {
 	})
 
 	registry.Register(TypeMergeFailed, func(msg *mail.Message) error {
-		payload, err := ParseMergeFailedPayload(msg.Body)
+		payload, err := DecodeAs[*MergeFailedPayload](msg)
 		if err != nil {
 			return err
 		}
@@ -101,7 +102,7 @@ func WrapWitnessHandlers(h WitnessHandler) *HandlerRegistry {
 	})
 
 	registry.Register(TypeReworkRequest, func(msg *mail.Message) error {
-		payload, err := ParseReworkRequestPayload(msg.Body)
+		payload, err := DecodeAs[*ReworkRequestPayload](msg)
 		if err != nil {
 			return err
 		}
@@ -116,7 +117,7 @@ func WrapRefineryHandlers(h RefineryHandler) *HandlerRegistry {
 	registry := NewHandlerRegistry()
 
 	registry.Register(TypeMergeReady, func(msg *mail.Message) error {
-		payload, err := ParseMergeReadyPayload(msg.Body)
+		payload, err := DecodeAs[*MergeReadyPayload](msg)
 		if err != nil {
 			return err
 		}
@@ -132,7 +133,7 @@ func WrapRefineryHandlers(h RefineryHandler) *HandlerRegistry {
 // a recognized protocol message but no handler is registered, or
 // (false, nil) if not a protocol message.
 func (r *HandlerRegistry) ProcessProtocolMessage(msg *mail.Message) (bool, error) {
-	if !IsProtocolMessage(msg.Subject) {
+	if Classify(msg) == "" {
 		return false, nil
 	}
 
@@ -2,6 +2,7 @@ package protocol
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,34 +12,58 @@ import (
 // NewMergeReadyMessage creates a MERGE_READY protocol message.
 // Sent by Witness to Refinery when a polecat's work is verified and ready.
 func NewMergeReadyMessage(rig, polecat, branch, issue string) *mail.Message {
-	payload := MergeReadyPayload{
+	return NewMergeReadyMessageFor(&MergeReadyPayload{
 		Branch:    branch,
 		Issue:     issue,
 		Polecat:   polecat,
 		Rig:       rig,
 		Verified:  "clean git state, issue closed",
 		Timestamp: time.Now(),
-	}
-
-	body := formatMergeReadyBody(payload)
+	})
+}
 
-	msg := mail.NewMessage(
-		fmt.Sprintf("%s/witness", rig),
-		fmt.Sprintf("%s/refinery", rig),
-		fmt.Sprintf("MERGE_READY %s", polecat),
-		body,
+// MergeReplyTimeout is how long a MERGE_READY waits for the refinery's
+// MERGED or MERGE_FAILED before the request expires and patrols are told
+// through a mail_reply_timeout event.
+const MergeReplyTimeout = 4 * time.Hour
+
+// NewMergeReadyMessageFor creates a MERGE_READY protocol message from a
+// filled-in payload, for callers that also know the MR bead.
+func NewMergeReadyMessageFor(payload *MergeReadyPayload) *mail.Message {
+	msg := NewMessage(
+		fmt.Sprintf("%s/witness", payload.Rig),
+		fmt.Sprintf("%s/refinery", payload.Rig),
+		payload,
 	)
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeTask
 
+	// The refinery answers with MERGED or MERGE_FAILED for the same MR
+	if payload.MR != "" {
+		Correlate(msg, payload.MR)
+	}
+
 	return msg
 }
 
-// formatMergeReadyBody formats the body of a MERGE_READY message.
-func formatMergeReadyBody(p MergeReadyPayload) string {
+// Type implements Payload.
+func (p *MergeReadyPayload) Type() MessageType { return TypeMergeReady }
+
+// Validate implements Payload.
+func (p *MergeReadyPayload) Validate() error {
+	return requireFields(TypeMergeReady, [2]string{"Branch", p.Branch}, [2]string{"Polecat", p.Polecat}, [2]string{"Rig", p.Rig})
+}
+
+func (p *MergeReadyPayload) subject() string { return fmt.Sprintf("MERGE_READY %s", p.Polecat) }
+
+// body formats the body of a MERGE_READY message.
+func (p *MergeReadyPayload) body() string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("Branch: %s\n", p.Branch))
 	sb.WriteString(fmt.Sprintf("Issue: %s\n", p.Issue))
+	if p.MR != "" {
+		sb.WriteString(fmt.Sprintf("MR: %s\n", p.MR))
+	}
 	sb.WriteString(fmt.Sprintf("Polecat: %s\n", p.Polecat))
 	sb.WriteString(fmt.Sprintf("Rig: %s\n", p.Rig))
 	if p.Verified != "" {
@@ -50,7 +75,7 @@ func formatMergeReadyBody(p MergeReadyPayload) string {
 // NewMergedMessage creates a MERGED protocol message.
 // Sent by Refinery to Witness when a branch is successfully merged.
 func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit string) *mail.Message {
-	payload := MergedPayload{
+	payload := &MergedPayload{
 		Branch:       branch,
 		Issue:        issue,
 		Polecat:      polecat,
@@ -60,13 +85,10 @@ func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit str
 		TargetBranch: targetBranch,
 	}
 
-	body := formatMergedBody(payload)
-
-	msg := mail.NewMessage(
+	msg := NewMessage(
 		fmt.Sprintf("%s/refinery", rig),
 		fmt.Sprintf("%s/witness", rig),
-		fmt.Sprintf("MERGED %s", polecat),
-		body,
+		payload,
 	)
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeNotification
@@ -74,8 +96,18 @@ func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit str
 	return msg
 }
 
-// formatMergedBody formats the body of a MERGED message.
-func formatMergedBody(p MergedPayload) string {
+// Type implements Payload.
+func (p *MergedPayload) Type() MessageType { return TypeMerged }
+
+// Validate implements Payload.
+func (p *MergedPayload) Validate() error {
+	return requireFields(TypeMerged, [2]string{"Branch", p.Branch}, [2]string{"Polecat", p.Polecat}, [2]string{"Rig", p.Rig})
+}
+
+func (p *MergedPayload) subject() string { return fmt.Sprintf("MERGED %s", p.Polecat) }
+
+// body formats the body of a MERGED message.
+func (p *MergedPayload) body() string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("Branch: %s\n", p.Branch))
 	sb.WriteString(fmt.Sprintf("Issue: %s\n", p.Issue))
@@ -92,7 +124,7 @@ func formatMergedBody(p MergedPayload) string {
 // NewMergeFailedMessage creates a MERGE_FAILED protocol message.
 // Sent by Refinery to Witness when merge fails (tests, build, etc.).
 func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureType, errorMsg string) *mail.Message {
-	payload := MergeFailedPayload{
+	payload := &MergeFailedPayload{
 		Branch:       branch,
 		Issue:        issue,
 		Polecat:      polecat,
@@ -103,13 +135,10 @@ func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureTyp
 		TargetBranch: targetBranch,
 	}
 
-	body := formatMergeFailedBody(payload)
-
-	msg := mail.NewMessage(
+	msg := NewMessage(
 		fmt.Sprintf("%s/refinery", rig),
 		fmt.Sprintf("%s/witness", rig),
-		fmt.Sprintf("MERGE_FAILED %s", polecat),
-		body,
+		payload,
 	)
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeTask
@@ -117,8 +146,18 @@ func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureTyp
 	return msg
 }
 
-// formatMergeFailedBody formats the body of a MERGE_FAILED message.
-func formatMergeFailedBody(p MergeFailedPayload) string {
+// Type implements Payload.
+func (p *MergeFailedPayload) Type() MessageType { return TypeMergeFailed }
+
+// Validate implements Payload.
+func (p *MergeFailedPayload) Validate() error {
+	return requireFields(TypeMergeFailed, [2]string{"Branch", p.Branch}, [2]string{"Polecat", p.Polecat}, [2]string{"Rig", p.Rig})
+}
+
+func (p *MergeFailedPayload) subject() string { return fmt.Sprintf("MERGE_FAILED %s", p.Polecat) }
+
+// body formats the body of a MERGE_FAILED message.
+func (p *MergeFailedPayload) body() string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("Branch: %s\n", p.Branch))
 	sb.WriteString(fmt.Sprintf("Issue: %s\n", p.Issue))
@@ -134,7 +173,7 @@ func formatMergeFailedBody(p MergeFailedPayload) string {
 // NewReworkRequestMessage creates a REWORK_REQUEST protocol message.
 // Sent by Refinery to Witness when a branch needs rebasing due to conflicts.
 func NewReworkRequestMessage(rig, polecat, branch, issue, targetBranch string, conflictFiles []string) *mail.Message {
-	payload := ReworkRequestPayload{
+	payload := &ReworkRequestPayload{
 		Branch:        branch,
 		Issue:         issue,
 		Polecat:       polecat,
@@ -145,13 +184,10 @@ func NewReworkRequestMessage(rig, polecat, branch, issue, targetBranch string, c
 		Instructions:  formatRebaseInstructions(targetBranch),
 	}
 
-	body := formatReworkRequestBody(payload)
-
-	msg := mail.NewMessage(
+	msg := NewMessage(
 		fmt.Sprintf("%s/refinery", rig),
 		fmt.Sprintf("%s/witness", rig),
-		fmt.Sprintf("REWORK_REQUEST %s", polecat),
-		body,
+		payload,
 	)
 	msg.Priority = mail.PriorityHigh
 	msg.Type = mail.TypeTask
@@ -159,8 +195,18 @@ func NewReworkRequestMessage(rig, polecat, branch, issue, targetBranch string, c
 	return msg
 }
 
-// formatReworkRequestBody formats the body of a REWORK_REQUEST message.
-func formatReworkRequestBody(p ReworkRequestPayload) string {
+// Type implements Payload.
+func (p *ReworkRequestPayload) Type() MessageType { return TypeReworkRequest }
+
+// Validate implements Payload.
+func (p *ReworkRequestPayload) Validate() error {
+	return requireFields(TypeReworkRequest, [2]string{"Branch", p.Branch}, [2]string{"Polecat", p.Polecat}, [2]string{"Rig", p.Rig})
+}
+
+func (p *ReworkRequestPayload) subject() string { return fmt.Sprintf("REWORK_REQUEST %s", p.Polecat) }
+
+// body formats the body of a REWORK_REQUEST message.
+func (p *ReworkRequestPayload) body() string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("Branch: %s\n", p.Branch))
 	sb.WriteString(fmt.Sprintf("Issue: %s\n", p.Issue))
@@ -191,38 +237,138 @@ func formatRebaseInstructions(targetBranch string) string {
 The Refinery will retry the merge after rebase is complete.`, targetBranch, targetBranch)
 }
 
+// Type implements Payload.
+func (p *PolecatDonePayload) Type() MessageType { return TypePolecatDone }
+
+// Validate implements Payload.
+func (p *PolecatDonePayload) Validate() error {
+	return requireFields(TypePolecatDone, [2]string{"Polecat", p.Polecat}, [2]string{"Exit", p.Exit})
+}
+
+func (p *PolecatDonePayload) subject() string { return fmt.Sprintf("POLECAT_DONE %s", p.Polecat) }
+
+// body formats the body of a POLECAT_DONE message.
+func (p *PolecatDonePayload) body() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Exit: %s\n", p.Exit))
+	if p.Issue != "" {
+		sb.WriteString(fmt.Sprintf("Issue: %s\n", p.Issue))
+	}
+	if p.MR != "" {
+		sb.WriteString(fmt.Sprintf("MR: %s\n", p.MR))
+	}
+	if p.Gate != "" {
+		sb.WriteString(fmt.Sprintf("Gate: %s\n", p.Gate))
+	}
+	sb.WriteString(fmt.Sprintf("Branch: %s\n", p.Branch))
+	if len(p.Errors) > 0 {
+		sb.WriteString(fmt.Sprintf("Errors: %s\n", strings.Join(p.Errors, "; ")))
+	}
+	return sb.String()
+}
+
+// Type implements Payload.
+func (p *LifecycleShutdownPayload) Type() MessageType { return TypeLifecycleShutdown }
+
+// Validate implements Payload.
+func (p *LifecycleShutdownPayload) Validate() error {
+	return requireFields(TypeLifecycleShutdown, [2]string{"Polecat", p.Polecat})
+}
+
+func (p *LifecycleShutdownPayload) subject() string {
+	return fmt.Sprintf("LIFECYCLE:Shutdown %s", p.Polecat)
+}
+
+// body formats the body of a LIFECYCLE_SHUTDOWN message.
+func (p *LifecycleShutdownPayload) body() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Reason: %s\n", p.Reason))
+	if p.RequestedBy != "" {
+		sb.WriteString(fmt.Sprintf("RequestedBy: %s\n", p.RequestedBy))
+	}
+	if p.Bead != "" {
+		sb.WriteString(fmt.Sprintf("Bead: %s\n", p.Bead))
+	}
+	if p.NewAssignee != "" {
+		sb.WriteString(fmt.Sprintf("NewAssignee: %s\n", p.NewAssignee))
+	}
+	return sb.String()
+}
+
+// Type implements Payload.
+func (p *HelpPayload) Type() MessageType { return TypeHelp }
+
+// Validate implements Payload.
+func (p *HelpPayload) Validate() error {
+	return requireFields(TypeHelp, [2]string{"Topic", p.Topic})
+}
+
+func (p *HelpPayload) subject() string { return fmt.Sprintf("HELP: %s", p.Topic) }
+
+// body formats the body of a HELP message.
+func (p *HelpPayload) body() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Agent: %s\n", p.Agent))
+	sb.WriteString(fmt.Sprintf("Issue: %s\n", p.Issue))
+	sb.WriteString(fmt.Sprintf("Problem: %s\n", p.Problem))
+	sb.WriteString(fmt.Sprintf("Tried: %s\n", p.Tried))
+	return sb.String()
+}
+
+// Type implements Payload.
+func (p *SwarmStartPayload) Type() MessageType { return TypeSwarmStart }
+
+// Validate implements Payload.
+func (p *SwarmStartPayload) Validate() error {
+	return requireFields(TypeSwarmStart, [2]string{"SwarmID", p.SwarmID})
+}
+
+func (p *SwarmStartPayload) subject() string { return fmt.Sprintf("SWARM_START %s", p.SwarmID) }
+
+// body formats the body of a SWARM_START message.
+func (p *SwarmStartPayload) body() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("SwarmID: %s\n", p.SwarmID))
+	sb.WriteString(fmt.Sprintf("Total: %d\n", p.Total))
+	if len(p.Beads) > 0 {
+		sb.WriteString(fmt.Sprintf("Beads: %s\n", strings.Join(p.Beads, ", ")))
+	}
+	return sb.String()
+}
+
 // ParseMergeReadyPayload parses a MERGE_READY message body into a payload.
 // Returns an error if required fields (Branch, Polecat, Rig) are missing.
 func ParseMergeReadyPayload(body string) (*MergeReadyPayload, error) {
-	payload := &MergeReadyPayload{
+	payload := parseMergeReady(body)
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func parseMergeReady(body string) *MergeReadyPayload {
+	return &MergeReadyPayload{
 		Branch:    parseField(body, "Branch"),
 		Issue:     parseField(body, "Issue"),
+		MR:        parseField(body, "MR"),
 		Polecat:   parseField(body, "Polecat"),
 		Rig:       parseField(body, "Rig"),
 		Verified:  parseField(body, "Verified"),
 		Timestamp: time.Now(), // Use current time if not parseable
 	}
-
-	var errs []string
-	if payload.Branch == "" {
-		errs = append(errs, "Branch")
-	}
-	if payload.Polecat == "" {
-		errs = append(errs, "Polecat")
-	}
-	if payload.Rig == "" {
-		errs = append(errs, "Rig")
-	}
-	if len(errs) > 0 {
-		return nil, fmt.Errorf("invalid MERGE_READY payload: missing required fields: %s", strings.Join(errs, ", "))
-	}
-
-	return payload, nil
 }
 
 // ParseMergedPayload parses a MERGED message body into a payload.
 // Returns an error if required fields (Branch, Polecat, Rig) are missing.
 func ParseMergedPayload(body string) (*MergedPayload, error) {
+	payload := parseMerged(body)
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func parseMerged(body string) *MergedPayload {
 	payload := &MergedPayload{
 		Branch:       parseField(body, "Branch"),
 		Issue:        parseField(body, "Issue"),
@@ -239,34 +385,30 @@ func ParseMergedPayload(body string) (*MergedPayload, error) {
 		}
 	}
 
-	var errs []string
-	if payload.Branch == "" {
-		errs = append(errs, "Branch")
-	}
-	if payload.Polecat == "" {
-		errs = append(errs, "Polecat")
-	}
-	if payload.Rig == "" {
-		errs = append(errs, "Rig")
-	}
-	if len(errs) > 0 {
-		return nil, fmt.Errorf("invalid MERGED payload: missing required fields: %s", strings.Join(errs, ", "))
-	}
-
-	return payload, nil
+	return payload
 }
 
 // ParseMergeFailedPayload parses a MERGE_FAILED message body into a payload.
 // Returns an error if required fields (Branch, Polecat, Rig) are missing.
 func ParseMergeFailedPayload(body string) (*MergeFailedPayload, error) {
+	payload := parseMergeFailed(body)
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func parseMergeFailed(body string) *MergeFailedPayload {
 	payload := &MergeFailedPayload{
 		Branch:       parseField(body, "Branch"),
 		Issue:        parseField(body, "Issue"),
 		Polecat:      parseField(body, "Polecat"),
 		Rig:          parseField(body, "Rig"),
 		TargetBranch: parseField(body, "Target"),
-		FailureType:  parseField(body, "Failure-Type"),
-		Error:        parseField(body, "Error"),
+		// Older witness-side senders wrote "FailureType"
+		FailureType: firstNonEmpty(parseField(body, "Failure-Type"), parseField(body, "FailureType")),
+		Error:       parseField(body, "Error"),
+		FailedAt:    time.Now(),
 	}
 
 	// Parse timestamp
@@ -276,26 +418,20 @@ func ParseMergeFailedPayload(body string) (*MergeFailedPayload, error) {
 		}
 	}
 
-	var errs []string
-	if payload.Branch == "" {
-		errs = append(errs, "Branch")
-	}
-	if payload.Polecat == "" {
-		errs = append(errs, "Polecat")
-	}
-	if payload.Rig == "" {
-		errs = append(errs, "Rig")
-	}
-	if len(errs) > 0 {
-		return nil, fmt.Errorf("invalid MERGE_FAILED payload: missing required fields: %s", strings.Join(errs, ", "))
-	}
-
-	return payload, nil
+	return payload
 }
 
 // ParseReworkRequestPayload parses a REWORK_REQUEST message body into a payload.
 // Returns an error if required fields (Branch, Polecat, Rig) are missing.
 func ParseReworkRequestPayload(body string) (*ReworkRequestPayload, error) {
+	payload := parseReworkRequest(body)
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func parseReworkRequest(body string) *ReworkRequestPayload {
 	payload := &ReworkRequestPayload{
 		Branch:       parseField(body, "Branch"),
 		Issue:        parseField(body, "Issue"),
@@ -316,21 +452,88 @@ func ParseReworkRequestPayload(body string) (*ReworkRequestPayload, error) {
 		payload.ConflictFiles = strings.Split(files, ", ")
 	}
 
-	var errs []string
-	if payload.Branch == "" {
-		errs = append(errs, "Branch")
+	return payload
+}
+
+// parsePolecatDone parses a legacy POLECAT_DONE message.
+// Subject format: POLECAT_DONE <polecat-name>
+// Body format:
+//
+//	Exit: COMPLETED|ESCALATED|DEFERRED|PHASE_COMPLETE
+//	Issue: <issue-id>
+//	MR: <mr-id>
+//	Gate: <gate-id>
+//	Branch: <branch>
+//	Errors: <error>; <error>
+func parsePolecatDone(subject, body string) Payload {
+	payload := &PolecatDonePayload{
+		Polecat: firstField(subjectArg(TypePolecatDone, subject)),
+		Exit:    parseField(body, "Exit"),
+		Issue:   parseField(body, "Issue"),
+		MR:      parseField(body, "MR"),
+		Gate:    parseField(body, "Gate"),
+		Branch:  parseField(body, "Branch"),
+	}
+	if errs := parseField(body, "Errors"); errs != "" {
+		payload.Errors = strings.Split(errs, "; ")
+	}
+	return payload
+}
+
+// parseLifecycleShutdown parses a legacy LIFECYCLE:Shutdown message.
+// Subject format: LIFECYCLE:Shutdown <polecat-name>
+func parseLifecycleShutdown(subject, body string) Payload {
+	return &LifecycleShutdownPayload{
+		Polecat:     firstField(subjectArg(TypeLifecycleShutdown, subject)),
+		Reason:      parseField(body, "Reason"),
+		RequestedBy: parseField(body, "RequestedBy"),
+		Bead:        parseField(body, "Bead"),
+		NewAssignee: parseField(body, "NewAssignee"),
 	}
-	if payload.Polecat == "" {
-		errs = append(errs, "Polecat")
+}
+
+// parseHelp parses a legacy HELP message.
+// Subject format: HELP: <topic>
+// Body format:
+//
+//	Agent: <agent-id>
+//	Issue: <issue-id>
+//	Problem: <description>
+//	Tried: <what was attempted>
+func parseHelp(subject, body string) Payload {
+	return &HelpPayload{
+		Topic:       subjectArg(TypeHelp, subject),
+		Agent:       parseField(body, "Agent"),
+		Issue:       parseField(body, "Issue"),
+		Problem:     parseField(body, "Problem"),
+		Tried:       parseField(body, "Tried"),
+		RequestedAt: time.Now(),
 	}
-	if payload.Rig == "" {
-		errs = append(errs, "Rig")
+}
+
+// parseSwarmStart parses a legacy SWARM_START message.
+// Subject format: SWARM_START [<swarm-id>]
+func parseSwarmStart(subject, body string) Payload {
+	swarmID := firstNonEmpty(parseField(body, "SwarmID"), parseField(body, "swarm_id"))
+	payload := &SwarmStartPayload{
+		SwarmID:   firstNonEmpty(swarmID, firstField(subjectArg(TypeSwarmStart, subject))),
+		StartedAt: time.Now(),
 	}
-	if len(errs) > 0 {
-		return nil, fmt.Errorf("invalid REWORK_REQUEST payload: missing required fields: %s", strings.Join(errs, ", "))
+	if total, err := strconv.Atoi(parseField(body, "Total")); err == nil {
+		payload.Total = total
 	}
+	if beads := parseField(body, "Beads"); beads != "" {
+		payload.Beads = strings.Split(beads, ", ")
+	}
+	return payload
+}
 
-	return payload, nil
+// firstField returns the first whitespace-separated word of s.
+func firstField(s string) string {
+	if fields := strings.Fields(s); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
 }
 
 // parseField extracts a field value from a key-value body format.
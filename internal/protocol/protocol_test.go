@@ -1,7 +1,6 @@
 package protocol
 
 import (
-	"bytes"
 	"errors"
 	"strings"
 	"testing"
@@ -495,63 +494,186 @@ func TestWrapRefineryHandlers_InvalidPayload(t *testing.T) {
 	}
 }
 
-func TestDefaultWitnessHandler(t *testing.T) {
-	tmpDir := t.TempDir()
-	handler := NewWitnessHandler("gastown", tmpDir)
+func TestEnvelopeRoundTrip(t *testing.T) {
+	msg := NewMergedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "abc123")
+	if msg.Envelope == nil || msg.Envelope.Type != string(TypeMerged) || msg.Envelope.Version != Version {
+		t.Fatalf("Envelope = %+v", msg.Envelope)
+	}
+	if msg.Envelope.CorrelationID == "" {
+		t.Error("CorrelationID should be set")
+	}
 
-	// Capture output
-	var buf bytes.Buffer
-	handler.SetOutput(&buf)
+	// Rewording the subject and body must not break routing
+	msg.Subject = "Branch nux merged to main"
+	msg.Body = "Merged, thanks!"
+	if got := Classify(msg); got != TypeMerged {
+		t.Errorf("Classify = %q, want %q", got, TypeMerged)
+	}
+	payload, err := DecodeAs[*MergedPayload](msg)
+	if err != nil {
+		t.Fatalf("DecodeAs: %v", err)
+	}
+	if payload.Polecat != "nux" || payload.MergeCommit != "abc123" || payload.TargetBranch != "main" {
+		t.Errorf("payload = %+v", payload)
+	}
 
-	// Test HandleMerged
-	mergedPayload := &MergedPayload{
-		Branch:       "polecat/nux/gt-abc",
-		Issue:        "gt-abc",
-		Polecat:      "nux",
-		Rig:          "gastown",
-		TargetBranch: "main",
-		MergeCommit:  "abc123",
+	if _, err := DecodeAs[*MergeFailedPayload](msg); err == nil {
+		t.Error("DecodeAs with the wrong payload type should fail")
 	}
-	if err := handler.HandleMerged(mergedPayload); err != nil {
-		t.Errorf("HandleMerged error: %v", err)
+}
+
+func TestDecodeUnsupportedVersion(t *testing.T) {
+	msg := NewMergeReadyMessage("gastown", "nux", "polecat/nux", "gt-abc")
+	msg.Envelope.Version = Version + 1
+	if _, err := Decode(msg); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Decode error = %v, want ErrUnsupportedVersion", err)
 	}
-	if !strings.Contains(buf.String(), "MERGED received") {
-		t.Errorf("Output missing expected text: %s", buf.String())
+}
+
+func TestDecodeLegacy(t *testing.T) {
+	tests := []struct {
+		name  string
+		msg   *mail.Message
+		check func(t *testing.T, p Payload)
+	}{
+		{
+			name: "polecat done",
+			msg: &mail.Message{
+				Subject: "POLECAT_DONE nux",
+				Body:    "Exit: COMPLETED\nIssue: gt-abc123\nMR: gt-mr-xyz\nBranch: feature-branch\nErrors: push slow; lint warning",
+			},
+			check: func(t *testing.T, p Payload) {
+				done := p.(*PolecatDonePayload)
+				if done.Polecat != "nux" || done.Exit != "COMPLETED" || done.MR != "gt-mr-xyz" || len(done.Errors) != 2 {
+					t.Errorf("payload = %+v", done)
+				}
+			},
+		},
+		{
+			name: "help",
+			msg: &mail.Message{
+				Subject: "HELP: Tests failing on CI",
+				Body:    "Agent: gastown/polecats/nux\nIssue: gt-abc123\nProblem: timeout\nTried: retry",
+			},
+			check: func(t *testing.T, p Payload) {
+				help := p.(*HelpPayload)
+				if help.Topic != "Tests failing on CI" || help.Agent != "gastown/polecats/nux" || help.Tried != "retry" {
+					t.Errorf("payload = %+v", help)
+				}
+			},
+		},
+		{
+			name: "lifecycle shutdown",
+			msg: &mail.Message{
+				Subject: "LIFECYCLE:Shutdown nux",
+				Body:    "Reason: work_reassigned\nBead: gt-abc",
+			},
+			check: func(t *testing.T, p Payload) {
+				sd := p.(*LifecycleShutdownPayload)
+				if sd.Polecat != "nux" || sd.Reason != "work_reassigned" || sd.Bead != "gt-abc" {
+					t.Errorf("payload = %+v", sd)
+				}
+			},
+		},
+		{
+			// Witness-era MERGE_READY: no Rig line, polecat from subject
+			name: "merge ready from witness",
+			msg: &mail.Message{
+				From:    "gastown/witness",
+				Subject: "MERGE_READY ace",
+				Body:    "Branch: polecat/ace\nIssue: gt-abc\nMR: gt-mr-1",
+			},
+			check: func(t *testing.T, p Payload) {
+				ready := p.(*MergeReadyPayload)
+				if ready.Polecat != "ace" || ready.Rig != "gastown" || ready.MR != "gt-mr-1" {
+					t.Errorf("payload = %+v", ready)
+				}
+			},
+		},
+		{
+			// Older senders wrote FailureType without the dash
+			name: "merge failed field variant",
+			msg: &mail.Message{
+				From:    "gastown/refinery",
+				Subject: "MERGE_FAILED nux",
+				Body:    "Branch: polecat/nux\nFailureType: build\nError: compile error",
+			},
+			check: func(t *testing.T, p Payload) {
+				failed := p.(*MergeFailedPayload)
+				if failed.FailureType != "build" || failed.Error != "compile error" {
+					t.Errorf("payload = %+v", failed)
+				}
+			},
+		},
+		{
+			name: "swarm start",
+			msg:  &mail.Message{Subject: "SWARM_START", Body: "SwarmID: batch-1\nTotal: 3\nBeads: gt-a, gt-b, gt-c"},
+			check: func(t *testing.T, p Payload) {
+				swarm := p.(*SwarmStartPayload)
+				if swarm.SwarmID != "batch-1" || swarm.Total != 3 || len(swarm.Beads) != 3 {
+					t.Errorf("payload = %+v", swarm)
+				}
+			},
+		},
 	}
 
-	// Test HandleMergeFailed
-	buf.Reset()
-	failedPayload := &MergeFailedPayload{
-		Branch:       "polecat/nux/gt-abc",
-		Issue:        "gt-abc",
-		Polecat:      "nux",
-		Rig:          "gastown",
-		TargetBranch: "main",
-		FailureType:  "tests",
-		Error:        "Test failed",
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Decode(tt.msg)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			tt.check(t, p)
+		})
 	}
-	if err := handler.HandleMergeFailed(failedPayload); err != nil {
-		t.Errorf("HandleMergeFailed error: %v", err)
+
+	if _, err := Decode(&mail.Message{Subject: "Hello world"}); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("Decode(non-protocol) error = %v, want ErrUnknownType", err)
 	}
-	if !strings.Contains(buf.String(), "MERGE_FAILED received") {
-		t.Errorf("Output missing expected text: %s", buf.String())
+}
+
+func TestSendValidates(t *testing.T) {
+	sender := &mockSender{}
+
+	bad := NewMessage("gastown/nux", "gastown/witness", &PolecatDonePayload{Polecat: "nux"})
+	if err := Send(sender, bad); err == nil {
+		t.Error("Send should reject a POLECAT_DONE without Exit")
+	}
+	if len(sender.sent) != 0 {
+		t.Error("invalid message should not be sent")
 	}
 
-	// Test HandleReworkRequest
-	buf.Reset()
-	reworkPayload := &ReworkRequestPayload{
-		Branch:        "polecat/nux/gt-abc",
-		Issue:         "gt-abc",
-		Polecat:       "nux",
-		Rig:           "gastown",
-		TargetBranch:  "main",
-		ConflictFiles: []string{"file1.go"},
+	good := NewMessage("gastown/nux", "gastown/witness", &PolecatDonePayload{Polecat: "nux", Exit: "COMPLETED", Branch: "polecat/nux"})
+	if err := Send(sender, good); err != nil {
+		t.Fatalf("Send: %v", err)
 	}
-	if err := handler.HandleReworkRequest(reworkPayload); err != nil {
-		t.Errorf("HandleReworkRequest error: %v", err)
+	if len(sender.sent) != 1 || sender.sent[0].Subject != "POLECAT_DONE nux" {
+		t.Errorf("sent = %+v", sender.sent)
+	}
+	if !strings.Contains(good.Body, "Exit: COMPLETED") {
+		t.Errorf("legacy body not rendered: %q", good.Body)
+	}
+}
+
+func TestMergeReadyCorrelatesByMR(t *testing.T) {
+	req := NewMergeReadyMessageFor(&MergeReadyPayload{Branch: "polecat/nux", Polecat: "nux", Rig: "gastown", MR: "gt-mr-1"})
+	if req.CorrelationID != "gt-mr-1" || req.Envelope.CorrelationID != "gt-mr-1" {
+		t.Errorf("MERGE_READY correlation = %q/%q, want gt-mr-1", req.CorrelationID, req.Envelope.CorrelationID)
+	}
+
+	// The refinery's answer for the same MR carries the same ID
+	reply := NewMergedMessage("gastown", "nux", "polecat/nux", "gt-abc", "main", "abc123")
+	Correlate(reply, "gt-mr-1")
+	if reply.CorrelationID != req.CorrelationID || reply.Envelope.CorrelationID != req.CorrelationID {
+		t.Errorf("MERGED correlation = %q/%q", reply.CorrelationID, reply.Envelope.CorrelationID)
 	}
-	if !strings.Contains(buf.String(), "REWORK_REQUEST received") {
-		t.Errorf("Output missing expected text: %s", buf.String())
+
+	sender := &mockSender{}
+	if err := SendRequest(sender, req, MergeReplyTimeout); err != nil {
+		t.Fatalf("SendRequest: %v", err)
+	}
+	if len(sender.requests) != 1 || sender.timeout != MergeReplyTimeout {
+		t.Errorf("requests = %+v, timeout = %v", sender.requests, sender.timeout)
 	}
 }
 
@@ -578,6 +700,23 @@ func (m *mockWitnessHandler) HandleReworkRequest(payload *ReworkRequestPayload)
 	return nil
 }
 
+type mockSender struct {
+	sent     []*mail.Message
+	requests []*mail.Message
+	timeout  time.Duration
+}
+
+func (m *mockSender) Send(msg *mail.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func (m *mockSender) SendRequest(msg *mail.Message, timeout time.Duration) error {
+	m.requests = append(m.requests, msg)
+	m.timeout = timeout
+	return nil
+}
+
 type mockRefineryHandler struct {
 	readyCalled bool
 }
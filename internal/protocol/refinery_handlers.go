@@ -63,21 +63,21 @@ func (h *DefaultRefineryHandler) HandleMergeReady(payload *MergeReadyPayload) er
 // Called by the Refinery after successfully merging a branch.
 func (h *DefaultRefineryHandler) SendMerged(polecat, branch, issue, targetBranch, mergeCommit string) error {
 	msg := NewMergedMessage(h.Rig, polecat, branch, issue, targetBranch, mergeCommit)
-	return h.Router.Send(msg)
+	return Send(h.Router, msg)
 }
 
 // SendMergeFailed sends a MERGE_FAILED message to the Witness.
 // Called by the Refinery when a merge fails.
 func (h *DefaultRefineryHandler) SendMergeFailed(polecat, branch, issue, targetBranch, failureType, errorMsg string) error {
 	msg := NewMergeFailedMessage(h.Rig, polecat, branch, issue, targetBranch, failureType, errorMsg)
-	return h.Router.Send(msg)
+	return Send(h.Router, msg)
 }
 
 // SendReworkRequest sends a REWORK_REQUEST message to the Witness.
 // Called by the Refinery when a branch has conflicts.
 func (h *DefaultRefineryHandler) SendReworkRequest(polecat, branch, issue, targetBranch string, conflictFiles []string) error {
 	msg := NewReworkRequestMessage(h.Rig, polecat, branch, issue, targetBranch, conflictFiles)
-	return h.Router.Send(msg)
+	return Send(h.Router, msg)
 }
 
 // NotifyMergeOutcome is a convenience method that sends the appropriate message
@@ -1,9 +1,22 @@
 // Package protocol provides inter-agent protocol message handling.
 //
-// This package defines protocol message types for Witness-Refinery communication
-// and provides handlers for processing these messages.
+// This package defines the protocol message types exchanged between agents,
+// their payload schemas, and handlers for processing these messages.
+//
+// Protocol messages carry a versioned JSON envelope (mail.Envelope) holding
+// the type, schema version, correlation ID and payload. Receivers dispatch on
+// the envelope; messages without one (older senders, hand-written mail) are
+// parsed from the legacy subject prefix and "Key: value" body.
+//
+// A MERGE_READY for an MR bead is sent as a request (mail.Router.SendRequest)
+// correlated by the MR ID; the MERGED or MERGE_FAILED the refinery sends for
+// that MR carries the same ID and settles it.
 //
 // Protocol Message Types:
+//   - POLECAT_DONE: Polecat → Witness (work finished, exit status)
+//   - LIFECYCLE_SHUTDOWN: Any → Witness (shut a polecat down)
+//   - HELP: Polecat → Witness (intervention requested)
+//   - SWARM_START: Mayor → Witness (batch work started)
 //   - MERGE_READY: Witness → Refinery (branch ready for merge)
 //   - MERGED: Refinery → Witness (merge succeeded, cleanup ok)
 //   - MERGE_FAILED: Refinery → Witness (merge failed, needs rework)
@@ -38,28 +51,66 @@ const (
 	// branch needs rebasing due to conflicts with the target branch.
 	// Subject format: "REWORK_REQUEST <polecat-name>"
 	TypeReworkRequest MessageType = "REWORK_REQUEST"
+
+	// TypePolecatDone is sent from a Polecat to its Witness when it finishes
+	// (gt done), reporting how it exited.
+	// Subject format: "POLECAT_DONE <polecat-name>"
+	TypePolecatDone MessageType = "POLECAT_DONE"
+
+	// TypeLifecycleShutdown asks the Witness to shut a polecat down, e.g.
+	// when its work is reassigned.
+	// Subject format: "LIFECYCLE:Shutdown <polecat-name>"
+	TypeLifecycleShutdown MessageType = "LIFECYCLE_SHUTDOWN"
+
+	// TypeHelp is sent from a Polecat to its Witness to request intervention.
+	// Subject format: "HELP: <topic>"
+	TypeHelp MessageType = "HELP"
+
+	// TypeSwarmStart is sent from the Mayor to a Witness when batch work starts.
+	// Subject format: "SWARM_START"
+	TypeSwarmStart MessageType = "SWARM_START"
 )
 
+// subjectPrefixes maps each type to its legacy subject prefix. Most types use
+// their own name; the prefix is followed by a space (or ends the subject).
+var subjectPrefixes = []struct {
+	msgType MessageType
+	prefix  string
+}{
+	{TypeMergeReady, "MERGE_READY"},
+	{TypeMerged, "MERGED"},
+	{TypeMergeFailed, "MERGE_FAILED"},
+	{TypeReworkRequest, "REWORK_REQUEST"},
+	{TypePolecatDone, "POLECAT_DONE"},
+	{TypeLifecycleShutdown, "LIFECYCLE:Shutdown"},
+	{TypeHelp, "HELP:"},
+	{TypeSwarmStart, "SWARM_START"},
+}
+
 // ParseMessageType extracts the protocol message type from a mail subject.
 // Returns empty string if subject doesn't match a known protocol type.
+// Prefer Classify, which also honours the message envelope.
 func ParseMessageType(subject string) MessageType {
 	subject = strings.TrimSpace(subject)
 
-	// Check each known prefix
-	prefixes := []MessageType{
-		TypeMergeReady,
-		TypeMerged,
-		TypeMergeFailed,
-		TypeReworkRequest,
+	for _, sp := range subjectPrefixes {
+		if subject == sp.prefix || strings.HasPrefix(subject, sp.prefix+" ") {
+			return sp.msgType
+		}
 	}
 
-	for _, prefix := range prefixes {
-		p := string(prefix)
-		if subject == p || strings.HasPrefix(subject, p+" ") {
-			return prefix
+	return ""
+}
+
+// subjectArg returns what follows the type prefix in a legacy subject
+// (the polecat name, or the topic for HELP).
+func subjectArg(msgType MessageType, subject string) string {
+	subject = strings.TrimSpace(subject)
+	for _, sp := range subjectPrefixes {
+		if sp.msgType == msgType && strings.HasPrefix(subject, sp.prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(subject, sp.prefix))
 		}
 	}
-
 	return ""
 }
 
@@ -78,6 +129,9 @@ type MergeReadyPayload struct {
 	// Rig is the rig name containing the polecat.
 	Rig string `json:"rig"`
 
+	// MR is the merge-request bead ID, if known.
+	MR string `json:"mr,omitempty"`
+
 	// Verified contains verification notes.
 	Verified string `json:"verified,omitempty"`
 
@@ -166,6 +220,88 @@ type ReworkRequestPayload struct {
 	Instructions string `json:"instructions,omitempty"`
 }
 
+// PolecatDonePayload contains the data for a POLECAT_DONE message.
+// Sent by a Polecat when it runs gt done.
+type PolecatDonePayload struct {
+	// Polecat is the worker name.
+	Polecat string `json:"polecat"`
+
+	// Exit is how the polecat finished: COMPLETED, ESCALATED, DEFERRED
+	// or PHASE_COMPLETE.
+	Exit string `json:"exit"`
+
+	// Issue is the beads issue ID the polecat worked on.
+	Issue string `json:"issue,omitempty"`
+
+	// MR is the merge-request bead ID, if one was submitted.
+	MR string `json:"mr,omitempty"`
+
+	// Branch is the polecat's work branch.
+	Branch string `json:"branch,omitempty"`
+
+	// Gate is the gate ID the polecat waits on when Exit is PHASE_COMPLETE.
+	Gate string `json:"gate,omitempty"`
+
+	// Errors lists non-fatal problems hit while finishing.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// LifecycleShutdownPayload contains the data for a LIFECYCLE_SHUTDOWN message.
+type LifecycleShutdownPayload struct {
+	// Polecat is the worker to shut down.
+	Polecat string `json:"polecat"`
+
+	// Reason explains the shutdown (e.g., "work_reassigned").
+	Reason string `json:"reason,omitempty"`
+
+	// RequestedBy is who asked for the shutdown.
+	RequestedBy string `json:"requested_by,omitempty"`
+
+	// Bead is the bead the polecat was working on.
+	Bead string `json:"bead,omitempty"`
+
+	// NewAssignee is who the bead was reassigned to.
+	NewAssignee string `json:"new_assignee,omitempty"`
+}
+
+// HelpPayload contains the data for a HELP message.
+// Sent by a Polecat that is stuck and needs intervention.
+type HelpPayload struct {
+	// Topic is a short summary of the problem.
+	Topic string `json:"topic"`
+
+	// Agent is the address of the agent asking for help.
+	Agent string `json:"agent,omitempty"`
+
+	// Issue is the beads issue ID being worked on.
+	Issue string `json:"issue,omitempty"`
+
+	// Problem describes what is wrong.
+	Problem string `json:"problem,omitempty"`
+
+	// Tried describes what was already attempted.
+	Tried string `json:"tried,omitempty"`
+
+	// RequestedAt is when help was requested.
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// SwarmStartPayload contains the data for a SWARM_START message.
+// Sent by the Mayor when a batch of polecats is dispatched.
+type SwarmStartPayload struct {
+	// SwarmID identifies the batch.
+	SwarmID string `json:"swarm_id"`
+
+	// Beads lists the issues in the batch.
+	Beads []string `json:"beads,omitempty"`
+
+	// Total is the number of polecats in the batch.
+	Total int `json:"total"`
+
+	// StartedAt is when the batch started.
+	StartedAt time.Time `json:"started_at"`
+}
+
 // IsProtocolMessage returns true if the subject matches a known protocol type.
 func IsProtocolMessage(subject string) bool {
 	return ParseMessageType(subject) != ""
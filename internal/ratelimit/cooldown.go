@@ -0,0 +1,167 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// DefaultCooldown is how long an account rests when the limit message does
+// not say when it resets.
+const DefaultCooldown = time.Hour
+
+// Cooldown records an account that hit its limit.
+type Cooldown struct {
+	Until   time.Time `json:"until"`
+	Since   time.Time `json:"since"`
+	Reason  string    `json:"reason,omitempty"`
+	Session string    `json:"session,omitempty"` // Session that hit the limit
+}
+
+// State holds the cooldowns of town accounts, keyed by account handle.
+type State struct {
+	Accounts map[string]*Cooldown `json:"accounts"`
+}
+
+// StatePath returns the path of the account cooldown state file.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "account_cooldowns.json")
+}
+
+// LoadState loads cooldown state; a missing file yields an empty state.
+func LoadState(townRoot string) (*State, error) {
+	state := &State{Accounts: make(map[string]*Cooldown)}
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("reading account cooldowns: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing account cooldowns: %w", err)
+	}
+	if state.Accounts == nil {
+		state.Accounts = make(map[string]*Cooldown)
+	}
+	return state, nil
+}
+
+// SaveState writes cooldown state.
+func SaveState(townRoot string, state *State) error {
+	path := StatePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating daemon directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Active returns the account's cooldown if it has not expired.
+func (s *State) Active(handle string, now time.Time) *Cooldown {
+	cd := s.Accounts[handle]
+	if cd == nil || !now.Before(cd.Until) {
+		return nil
+	}
+	return cd
+}
+
+// Start puts an account on cooldown after hit. A stated reset time is used
+// as-is; otherwise the account rests for DefaultCooldown.
+func (s *State) Start(handle, session string, hit Hit, now time.Time) *Cooldown {
+	until := hit.ResetAt
+	if !until.After(now) {
+		until = now.Add(DefaultCooldown)
+	}
+	cd := &Cooldown{Until: until, Since: now, Reason: hit.Message, Session: session}
+	s.Accounts[handle] = cd
+	return cd
+}
+
+// Prune drops expired cooldowns and reports whether any were dropped.
+func (s *State) Prune(now time.Time) bool {
+	pruned := false
+	for handle, cd := range s.Accounts {
+		if !now.Before(cd.Until) {
+			delete(s.Accounts, handle)
+			pruned = true
+		}
+	}
+	return pruned
+}
+
+// NextAvailable returns the account to use instead of current: current
+// itself if it is not cooling down, else the next handle in sorted order
+// that is not. It returns "" when every account is cooling down.
+func (s *State) NextAvailable(cfg *config.AccountsConfig, current string, now time.Time) string {
+	if s.Active(current, now) == nil {
+		if _, ok := cfg.Accounts[current]; ok {
+			return current
+		}
+	}
+
+	handles := make([]string, 0, len(cfg.Accounts))
+	for h := range cfg.Accounts {
+		handles = append(handles, h)
+	}
+	sort.Strings(handles)
+
+	// Start after current so rotation walks through the accounts in turn
+	start := sort.SearchStrings(handles, current)
+	for i := range handles {
+		h := handles[(start+i)%len(handles)]
+		if h != current && s.Active(h, now) == nil {
+			return h
+		}
+	}
+	return ""
+}
+
+// AccountForConfigDir returns the handle of the account whose config dir is
+// dir. An empty dir means the session used the default account.
+func AccountForConfigDir(cfg *config.AccountsConfig, dir string) string {
+	if dir == "" {
+		return cfg.Default
+	}
+	dir = filepath.Clean(expandHome(dir))
+	for handle, acct := range cfg.Accounts {
+		if filepath.Clean(expandHome(acct.ConfigDir)) == dir {
+			return handle
+		}
+	}
+	return ""
+}
+
+// ConfigDir returns an account's config dir with "~" expanded.
+func ConfigDir(cfg *config.AccountsConfig, handle string) string {
+	acct := cfg.GetAccount(handle)
+	if acct == nil {
+		return ""
+	}
+	return expandHome(acct.ConfigDir)
+}
+
+// expandHome expands a leading "~/" to the home directory.
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
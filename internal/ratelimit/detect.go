@@ -0,0 +1,152 @@
+// Package ratelimit detects agent sessions that hit an account usage or rate
+// limit and tracks per-account cooldowns, so the daemon can move a stuck
+// session to the next available account.
+package ratelimit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Hit is a detected rate limit.
+type Hit struct {
+	Message string    // The line that matched
+	ResetAt time.Time // When the limit resets; zero if not stated
+}
+
+// limitPatterns match the messages Claude Code shows when an account is out
+// of quota or being throttled. They are specific on purpose: a polecat's own
+// output about rate limiting (code, tests, docs) must not trigger rotation.
+var limitPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)usage limit reached`),
+	regexp.MustCompile(`(?i)\blimit reached\b.*\bresets?\b`),
+	regexp.MustCompile(`(?i)your limit will reset at`),
+	regexp.MustCompile(`(?i)API Error:?\s*429\b`),
+	regexp.MustCompile(`rate_limit_error`),
+}
+
+// unixResetPattern matches "usage limit reached|1736445600".
+var unixResetPattern = regexp.MustCompile(`limit reached\|(\d{9,11})`)
+
+// clockResetPattern matches "resets 3pm", "reset at 3:30pm (Europe/London)".
+var clockResetPattern = regexp.MustCompile(`(?i)resets?\s+(?:at\s+)?(\d{1,2})(?::(\d{2}))?\s*(am|pm)(?:\s*\(([^)]+)\))?`)
+
+// DetectText looks for a rate-limit message in agent pane output.
+func DetectText(text string, now time.Time) (Hit, bool) {
+	lines := strings.Split(text, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		for _, p := range limitPatterns {
+			if p.MatchString(line) {
+				return Hit{Message: line, ResetAt: parseReset(line, now)}, true
+			}
+		}
+	}
+	return Hit{}, false
+}
+
+// parseReset extracts the reset time from a limit message.
+func parseReset(line string, now time.Time) time.Time {
+	if m := unixResetPattern.FindStringSubmatch(line); m != nil {
+		if ts, err := strconv.ParseInt(m[1], 10, 64); err == nil {
+			return time.Unix(ts, 0)
+		}
+	}
+
+	m := clockResetPattern.FindStringSubmatch(line)
+	if m == nil {
+		return time.Time{}
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	if hour < 1 || hour > 12 || minute > 59 {
+		return time.Time{}
+	}
+	hour %= 12
+	if strings.EqualFold(m[3], "pm") {
+		hour += 12
+	}
+	loc := now.Location()
+	if m[4] != "" {
+		if l, err := time.LoadLocation(m[4]); err == nil {
+			loc = l
+		}
+	}
+
+	local := now.In(loc)
+	reset := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !reset.After(now) {
+		reset = reset.AddDate(0, 0, 1)
+	}
+	return reset
+}
+
+// transcriptTail is how much of a transcript DetectTranscript reads.
+const transcriptTail = 64 * 1024
+
+// DetectTranscript checks whether a Claude Code transcript ends in an API
+// error message reporting a rate limit.
+func DetectTranscript(path string, now time.Time) (Hit, bool) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is a discovered transcript
+	if err != nil {
+		return Hit{}, false
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && info.Size() > transcriptTail {
+		if _, err := f.Seek(-transcriptTail, io.SeekEnd); err != nil {
+			return Hit{}, false
+		}
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return Hit{}, false
+	}
+
+	// Only the last assistant message matters: an error followed by a
+	// successful turn means the limit has already passed.
+	var last string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), transcriptTail)
+	for scanner.Scan() {
+		var msg struct {
+			Type              string `json:"type"`
+			IsAPIErrorMessage bool   `json:"isApiErrorMessage"`
+			Message           *struct {
+				Content []struct {
+					Type string `json:"type"`
+					Text string `json:"text"`
+				} `json:"content"`
+			} `json:"message"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil || msg.Type != "assistant" {
+			continue // Skip partial first line and non-assistant entries
+		}
+		last = ""
+		if !msg.IsAPIErrorMessage || msg.Message == nil {
+			continue
+		}
+		for _, c := range msg.Message.Content {
+			if c.Type == "text" {
+				last += c.Text + "\n"
+			}
+		}
+	}
+	if last == "" {
+		return Hit{}, false
+	}
+	return DetectText(last, now)
+}
@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestDetectText(t *testing.T) {
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		text    string
+		hit     bool
+		resetAt time.Time
+	}{
+		{"no limit", "⏺ Running tests...\n  all passed", false, time.Time{}},
+		{"code about rate limits", "func handleRateLimit() // retry on 429\nrateLimiter.Wait()", false, time.Time{}},
+		{"unix reset", "Claude AI usage limit reached|1773158400", true, time.Unix(1773158400, 0)},
+		{"clock reset later today", "5-hour limit reached ∙ resets 3pm\n", true, time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)},
+		{"clock reset tomorrow", "Your limit will reset at 9:30am", true, time.Date(2026, 3, 11, 9, 30, 0, 0, time.UTC)},
+		{"api error", "  ⎿  API Error: 429 {\"type\":\"error\",\"error\":{\"type\":\"rate_limit_error\"}}", true, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, ok := DetectText(tt.text, now)
+			if ok != tt.hit {
+				t.Fatalf("DetectText hit = %v, want %v", ok, tt.hit)
+			}
+			if !hit.ResetAt.Equal(tt.resetAt) {
+				t.Errorf("ResetAt = %v, want %v", hit.ResetAt, tt.resetAt)
+			}
+		})
+	}
+
+	hit, ok := DetectText("Opus limit reached ∙ resets 11pm (America/New_York)", now)
+	ny, _ := time.LoadLocation("America/New_York")
+	if !ok || !hit.ResetAt.Equal(time.Date(2026, 3, 10, 23, 0, 0, 0, ny)) {
+		t.Errorf("zoned reset = %v, %v", hit.ResetAt, ok)
+	}
+}
+
+func TestDetectTranscript(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	limited := `{"type":"assistant","isApiErrorMessage":true,"message":{"content":[{"type":"text","text":"Claude AI usage limit reached|1773158400"}]}}`
+	ok := `{"type":"assistant","message":{"content":[{"type":"text","text":"Done."}]}}`
+
+	path := filepath.Join(dir, "s.jsonl")
+	write := func(lines ...string) {
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(ok, limited, `{"type":"user"}`)
+	if _, hit := DetectTranscript(path, now); !hit {
+		t.Error("trailing limit error not detected")
+	}
+	write(limited, ok)
+	if _, hit := DetectTranscript(path, now); hit {
+		t.Error("limit followed by a successful turn was detected")
+	}
+}
+
+func TestCooldownRotation(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	cfg := &config.AccountsConfig{
+		Default: "alpha",
+		Accounts: map[string]config.Account{
+			"alpha": {ConfigDir: "/accts/alpha"},
+			"beta":  {ConfigDir: "/accts/beta"},
+			"gamma": {ConfigDir: "/accts/gamma"},
+		},
+	}
+
+	state, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.NextAvailable(cfg, "alpha", now); got != "alpha" {
+		t.Errorf("NextAvailable(alpha, no cooldown) = %q", got)
+	}
+
+	state.Start("alpha", "gt-gastown-toast", Hit{Message: "usage limit reached"}, now)
+	if cd := state.Active("alpha", now); cd == nil || !cd.Until.Equal(now.Add(DefaultCooldown)) {
+		t.Errorf("alpha cooldown = %+v", cd)
+	}
+	if got := state.NextAvailable(cfg, "alpha", now); got != "beta" {
+		t.Errorf("NextAvailable(alpha) = %q, want beta", got)
+	}
+
+	state.Start("beta", "gt-gastown-nux", Hit{ResetAt: now.Add(3 * time.Hour)}, now)
+	if got := state.NextAvailable(cfg, "beta", now); got != "gamma" {
+		t.Errorf("NextAvailable(beta) = %q, want gamma", got)
+	}
+	state.Start("gamma", "", Hit{}, now)
+	if got := state.NextAvailable(cfg, "gamma", now); got != "" {
+		t.Errorf("NextAvailable(all cooling) = %q, want none", got)
+	}
+
+	if err := SaveState(townRoot, state); err != nil {
+		t.Fatal(err)
+	}
+	state, err = LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	later := now.Add(2 * time.Hour)
+	if !state.Prune(later) || len(state.Accounts) != 1 || state.Accounts["beta"] == nil {
+		t.Errorf("after prune = %+v", state.Accounts)
+	}
+
+	if got := AccountForConfigDir(cfg, "/accts/gamma/"); got != "gamma" {
+		t.Errorf("AccountForConfigDir = %q, want gamma", got)
+	}
+	if got := AccountForConfigDir(cfg, ""); got != "alpha" {
+		t.Errorf("AccountForConfigDir(empty) = %q, want default", got)
+	}
+}
+
+func TestLatestAndCopySession(t *testing.T) {
+	from, to := t.TempDir(), t.TempDir()
+	workDir := "/gt/gastown/polecats/toast/gastown"
+	dir := projectDir(from, workDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	old := filepath.Join(dir, "old-id.jsonl")
+	if err := os.WriteFile(old, []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(old, past, past)
+	if err := os.WriteFile(filepath.Join(dir, "new-id.jsonl"), []byte("{\"n\":1}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	id, _, err := LatestSession(from, workDir)
+	if err != nil || id != "new-id" {
+		t.Fatalf("LatestSession = %q, %v", id, err)
+	}
+	if err := CopySession(from, to, workDir, id); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(projectDir(to, workDir), "new-id.jsonl"))
+	if err != nil || string(data) != "{\"n\":1}\n" {
+		t.Errorf("copied transcript = %q, %v", data, err)
+	}
+}
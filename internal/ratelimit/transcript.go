@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// projectDir returns where Claude Code keeps transcripts for workDir under
+// an account's config dir: <config>/projects/<path-with-dashes>/.
+func projectDir(configDir, workDir string) string {
+	return filepath.Join(configDir, "projects", strings.ReplaceAll(workDir, "/", "-"))
+}
+
+// LatestSession returns the ID and transcript path of the most recent
+// Claude Code session in workDir under configDir. The transcript file name
+// is the session ID.
+func LatestSession(configDir, workDir string) (id, path string, err error) {
+	dir := projectDir(configDir, workDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", "", err
+	}
+	var latest time.Time
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+			path = filepath.Join(dir, e.Name())
+		}
+	}
+	if path == "" {
+		return "", "", fmt.Errorf("no transcripts in %s", dir)
+	}
+	return strings.TrimSuffix(filepath.Base(path), ".jsonl"), path, nil
+}
+
+// CopySession copies a session transcript from one account's config dir to
+// another's, so the session can be resumed under the new account.
+func CopySession(fromConfigDir, toConfigDir, workDir, id string) error {
+	src := filepath.Join(projectDir(fromConfigDir, workDir), id+".jsonl")
+	dstDir := projectDir(toConfigDir, workDir)
+	if err := os.MkdirAll(dstDir, 0700); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src) //nolint:gosec // G304: path is constructed from account config
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(filepath.Join(dstDir, id+".jsonl"), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,430 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// trainBranchPrefix is the namespace for temporary speculative merge branches.
+// Train branches are local to the refinery worktree and never pushed.
+const trainBranchPrefix = "refinery/train-"
+
+// BatchResult pairs a merge request from a speculative merge train with its outcome.
+type BatchResult struct {
+	MR     *MRInfo
+	Result ProcessResult
+
+	// Deferred is true when the MR was neither merged nor rejected. This happens
+	// when it only conflicts with another MR in the same train: it stays in the
+	// queue and is retried in a later train once the earlier MR has landed.
+	Deferred bool
+}
+
+// mergeTrain is a temporary branch with a stack of squash-merged MRs on top of
+// the target branch.
+type mergeTrain struct {
+	branch  string
+	target  string
+	members []*MRInfo
+	commits map[string]string // MR ID -> squash commit SHA on the train
+}
+
+// BatchSize returns the maximum number of MRs stacked into one merge train.
+// Derived from MaxConcurrent; values below 1 mean serial processing.
+func (e *Engineer) BatchSize() int {
+	if e.config.MaxConcurrent < 1 {
+		return 1
+	}
+	return e.config.MaxConcurrent
+}
+
+// NextBatch selects the MRs for the next merge train from a priority-ordered list.
+// All MRs in a train share the target of the first (highest priority) MR; MRs
+// for other targets wait for a later train. At most BatchSize MRs are returned.
+func (e *Engineer) NextBatch(mrs []*MRInfo) []*MRInfo {
+	if len(mrs) == 0 {
+		return nil
+	}
+
+	target := mrs[0].Target
+	if target == "" {
+		target = e.config.TargetBranch
+	}
+
+	limit := e.BatchSize()
+	batch := make([]*MRInfo, 0, limit)
+	for _, mr := range mrs {
+		mrTarget := mr.Target
+		if mrTarget == "" {
+			mrTarget = e.config.TargetBranch
+		}
+		if mrTarget != target {
+			continue
+		}
+		batch = append(batch, mr)
+		if len(batch) == limit {
+			break
+		}
+	}
+	return batch
+}
+
+// OrderByQueue returns the ready MRs (unclaimed and unblocked, see ListReadyMRs)
+// ordered by their position in the scored merge queue from Manager.Queue.
+// Ready MRs missing from the queue snapshot are appended at the end.
+func OrderByQueue(ready []*MRInfo, queue []QueueItem) []*MRInfo {
+	position := make(map[string]int, len(queue))
+	for _, item := range queue {
+		if item.MR != nil {
+			position[item.MR.ID] = item.Position
+		}
+	}
+
+	ordered := make([]*MRInfo, len(ready))
+	copy(ordered, ready)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		pi, iok := position[ordered[i].ID]
+		pj, jok := position[ordered[j].ID]
+		if iok != jok {
+			return iok
+		}
+		return pi < pj
+	})
+	return ordered
+}
+
+// ProcessBatch merges a batch of MRs as a speculative merge train.
+//
+// The MRs are squash-merged one after another onto a temporary branch cut from
+// the target, and the test command runs once against the whole stack. If the
+// tests pass, the target is fast-forwarded to the train and pushed. If they fail,
+// the batch is bisected to find the culprit MR(s); the remaining MRs are landed
+// as a smaller train.
+//
+// Results are returned in the same order as the input. Each MR still needs its
+// own HandleMRInfoSuccess/HandleMRInfoFailure call (see HandleBatchResults) so
+// that protocol messages and bead updates stay per-MR.
+func (e *Engineer) ProcessBatch(ctx context.Context, mrs []*MRInfo) []BatchResult {
+	if len(mrs) == 0 {
+		return nil
+	}
+	if len(mrs) == 1 {
+		return []BatchResult{{MR: mrs[0], Result: e.ProcessMRInfo(ctx, mrs[0])}}
+	}
+
+	target := mrs[0].Target
+	if target == "" {
+		target = e.config.TargetBranch
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Building merge train of %d MRs onto %s:\n", len(mrs), target)
+	for _, mr := range mrs {
+		_, _ = fmt.Fprintf(e.output, "  %s (%s, worker %s)\n", mr.ID, mr.Branch, mr.Worker)
+	}
+
+	results := make(map[string]BatchResult, len(mrs))
+	collect := func() []BatchResult {
+		out := make([]BatchResult, 0, len(mrs))
+		for _, mr := range mrs {
+			r, ok := results[mr.ID]
+			if !ok {
+				r = BatchResult{MR: mr, Deferred: true, Result: ProcessResult{Error: "not processed"}}
+			}
+			out = append(out, r)
+		}
+		return out
+	}
+	failAll := func(members []*MRInfo, result ProcessResult) {
+		for _, mr := range members {
+			results[mr.ID] = BatchResult{MR: mr, Result: result}
+		}
+	}
+
+	// Step 1: Bring the target up to date with origin
+	if err := e.git.Checkout(target); err != nil {
+		failAll(mrs, ProcessResult{Error: fmt.Sprintf("failed to checkout target %s: %v", target, err)})
+		return collect()
+	}
+	if err := e.git.Pull("origin", target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
+	}
+
+	// Step 2: Reject MRs that cannot merge on their own (missing branch, conflicts
+	// with the target). These fail exactly as they would in serial mode.
+	var candidates []*MRInfo
+	for _, mr := range mrs {
+		if result, ok := e.precheckMR(mr, target); !ok {
+			results[mr.ID] = BatchResult{MR: mr, Result: result}
+			continue
+		}
+		candidates = append(candidates, mr)
+	}
+
+	// Step 3: Stack the candidates onto a train branch
+	train, deferred, err := e.buildTrain(target, candidates)
+	defer func() { e.cleanupTrain(train) }()
+	if err != nil {
+		failAll(candidates, ProcessResult{Error: fmt.Sprintf("building merge train: %v", err)})
+		return collect()
+	}
+	for _, mr := range deferred {
+		results[mr.ID] = BatchResult{
+			MR:       mr,
+			Deferred: true,
+			Result:   ProcessResult{Error: "conflicts with an earlier MR in the merge train; retrying in a later train"},
+		}
+	}
+	if len(train.members) == 0 {
+		return collect()
+	}
+
+	// Step 4: Run the tests once for the whole train, bisecting on failure
+	if e.testsEnabled() {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests on train (%d MRs): %s\n", len(train.members), e.config.TestCommand)
+		result := e.runTests(ctx)
+		if !result.Success {
+			if ctx.Err() != nil {
+				failAll(train.members, result)
+				return collect()
+			}
+
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Train failed tests, bisecting %d MRs...\n", len(train.members))
+			culprits, err := e.bisectFailures(ctx, target, train.members, result)
+			if err != nil {
+				failAll(train.members, ProcessResult{Error: fmt.Sprintf("bisecting merge train: %v", err)})
+				return collect()
+			}
+			for id, r := range culprits {
+				results[id] = r
+			}
+
+			var passing []*MRInfo
+			for _, mr := range train.members {
+				if _, bad := culprits[mr.ID]; !bad {
+					passing = append(passing, mr)
+				}
+			}
+			if len(passing) == 0 {
+				return collect()
+			}
+
+			// Rebuild the train without the culprits and confirm it is green.
+			// If it still fails, the failure comes from an interaction between
+			// MRs that bisection cannot isolate: fall back to serial merging.
+			e.cleanupTrain(train)
+			train, deferred, err = e.buildTrain(target, passing)
+			if err == nil && len(deferred) == 0 {
+				result = e.runTests(ctx)
+			}
+			if err != nil || len(deferred) > 0 || !result.Success {
+				_, _ = fmt.Fprintln(e.output, "[Engineer] Remaining train still failing, falling back to serial merge")
+				e.cleanupTrain(train)
+				train = nil
+				for _, mr := range passing {
+					results[mr.ID] = BatchResult{MR: mr, Result: e.doMerge(ctx, mr.Branch, target, mr.SourceIssue)}
+				}
+				return collect()
+			}
+		}
+		_, _ = fmt.Fprintln(e.output, "[Engineer] Train tests passed")
+	}
+
+	// Step 5: Land the train on the target and push
+	if result := e.landTrain(train); !result.Success {
+		failAll(train.members, result)
+		return collect()
+	}
+	for _, mr := range train.members {
+		results[mr.ID] = BatchResult{
+			MR:     mr,
+			Result: ProcessResult{Success: true, MergeCommit: train.commits[mr.ID]},
+		}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully landed train of %d MRs on %s\n", len(train.members), target)
+	return collect()
+}
+
+// HandleBatchResults applies per-MR success/failure handling to the results of
+// ProcessBatch. Failed and deferred MRs are released so they re-enter the queue.
+func (e *Engineer) HandleBatchResults(results []BatchResult) {
+	for _, r := range results {
+		switch {
+		case r.Deferred:
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Deferred: %s - %s\n", r.MR.ID, r.Result.Error)
+			if err := e.ReleaseMR(r.MR.ID); err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to release MR %s: %v\n", r.MR.ID, err)
+			}
+		case r.Result.Success:
+			e.HandleMRInfoSuccess(r.MR, r.Result)
+		default:
+			e.HandleMRInfoFailure(r.MR, r.Result)
+			if err := e.ReleaseMR(r.MR.ID); err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to release MR %s: %v\n", r.MR.ID, err)
+			}
+		}
+	}
+}
+
+// testsEnabled reports whether the test command should gate merges.
+func (e *Engineer) testsEnabled() bool {
+	return e.config.RunTests && e.config.TestCommand != ""
+}
+
+// precheckMR verifies an MR's branch exists and merges cleanly into the target
+// on its own. Returns false with the failure result if it does not.
+func (e *Engineer) precheckMR(mr *MRInfo, target string) (ProcessResult, bool) {
+	exists, err := e.git.BranchExists(mr.Branch)
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("failed to check branch %s: %v", mr.Branch, err)}, false
+	}
+	if !exists {
+		return ProcessResult{Error: fmt.Sprintf("branch %s not found locally", mr.Branch)}, false
+	}
+
+	conflicts, err := e.git.CheckConflicts(mr.Branch, target)
+	if err != nil {
+		return ProcessResult{Conflict: true, Error: fmt.Sprintf("conflict check failed: %v", err)}, false
+	}
+	if len(conflicts) > 0 {
+		return ProcessResult{Conflict: true, Error: fmt.Sprintf("merge conflicts in: %v", conflicts)}, false
+	}
+	return ProcessResult{}, true
+}
+
+// buildTrain creates (or resets) a train branch at the target and squash-merges
+// each MR onto it in order. MRs that conflict with earlier train members are
+// skipped and returned as deferred. The train branch is left checked out.
+func (e *Engineer) buildTrain(target string, mrs []*MRInfo) (*mergeTrain, []*MRInfo, error) {
+	train := &mergeTrain{
+		branch:  fmt.Sprintf("%s%d", trainBranchPrefix, time.Now().UnixNano()),
+		target:  target,
+		commits: make(map[string]string, len(mrs)),
+	}
+
+	if err := e.git.Checkout(target); err != nil {
+		return train, nil, fmt.Errorf("checkout target %s: %w", target, err)
+	}
+	if err := e.git.CreateBranchFrom(train.branch, target); err != nil {
+		return train, nil, fmt.Errorf("creating train branch: %w", err)
+	}
+	if err := e.git.Checkout(train.branch); err != nil {
+		return train, nil, fmt.Errorf("checkout train branch: %w", err)
+	}
+
+	var deferred []*MRInfo
+	for _, mr := range mrs {
+		msg, err := e.git.GetBranchCommitMessage(mr.Branch)
+		if err != nil || strings.TrimSpace(msg) == "" {
+			msg = fmt.Sprintf("Squash merge %s into %s", mr.Branch, target)
+			if mr.SourceIssue != "" {
+				msg = fmt.Sprintf("Squash merge %s into %s (%s)", mr.Branch, target, mr.SourceIssue)
+			}
+		}
+
+		if err := e.git.MergeSquash(mr.Branch, msg); err != nil {
+			// A squash merge leaves no MERGE_HEAD, so reset instead of merge --abort.
+			if resetErr := e.git.ResetHard("HEAD"); resetErr != nil {
+				return train, deferred, fmt.Errorf("resetting train after failed merge of %s: %w", mr.ID, resetErr)
+			}
+			_, _ = fmt.Fprintf(e.output, "[Engineer] %s does not stack onto the train: %v\n", mr.ID, err)
+			deferred = append(deferred, mr)
+			continue
+		}
+
+		sha, err := e.git.Rev("HEAD")
+		if err != nil {
+			return train, deferred, fmt.Errorf("reading train commit for %s: %w", mr.ID, err)
+		}
+		train.commits[mr.ID] = sha
+		train.members = append(train.members, mr)
+	}
+
+	return train, deferred, nil
+}
+
+// bisectFailures finds the MRs responsible for a failing train by splitting it
+// in halves and testing each half on a fresh train. A single-MR train that fails
+// is a culprit. The failure result of the original run is used for culprits
+// found without a dedicated run.
+func (e *Engineer) bisectFailures(ctx context.Context, target string, mrs []*MRInfo, failure ProcessResult) (map[string]BatchResult, error) {
+	culprits := make(map[string]BatchResult)
+	if len(mrs) == 1 {
+		culprits[mrs[0].ID] = BatchResult{MR: mrs[0], Result: culpritResult(failure)}
+		return culprits, nil
+	}
+
+	mid := len(mrs) / 2
+	for _, half := range [][]*MRInfo{mrs[:mid], mrs[mid:]} {
+		train, _, err := e.buildTrain(target, half)
+		if err != nil {
+			e.cleanupTrain(train)
+			return nil, err
+		}
+		if len(train.members) == 0 {
+			e.cleanupTrain(train)
+			continue
+		}
+
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Bisect: testing %d MR(s) starting at %s\n", len(train.members), train.members[0].ID)
+		result := e.runTests(ctx)
+		e.cleanupTrain(train)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if result.Success {
+			continue
+		}
+
+		sub, err := e.bisectFailures(ctx, target, train.members, result)
+		if err != nil {
+			return nil, err
+		}
+		for id, r := range sub {
+			culprits[id] = r
+		}
+	}
+
+	return culprits, nil
+}
+
+// culpritResult converts a failed train test run into the per-MR failure result.
+func culpritResult(failure ProcessResult) ProcessResult {
+	return ProcessResult{
+		Success:     false,
+		TestsFailed: true,
+		Error:       fmt.Sprintf("merge train bisection isolated test failure: %s", failure.Error),
+	}
+}
+
+// landTrain fast-forwards the target branch to the train and pushes it.
+func (e *Engineer) landTrain(train *mergeTrain) ProcessResult {
+	if err := e.git.Checkout(train.target); err != nil {
+		return ProcessResult{Error: fmt.Sprintf("failed to checkout target %s: %v", train.target, err)}
+	}
+	if err := e.git.MergeFFOnly(train.branch); err != nil {
+		return ProcessResult{Error: fmt.Sprintf("failed to fast-forward %s to merge train: %v", train.target, err)}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing train to origin/%s...\n", train.target)
+	if err := e.git.Push("origin", train.target, false); err != nil {
+		// Nothing landed: drop the local fast-forward so the next pull starts clean.
+		_ = e.git.ResetHard("origin/" + train.target)
+		return ProcessResult{Error: fmt.Sprintf("failed to push to origin: %v", err)}
+	}
+	return ProcessResult{Success: true}
+}
+
+// cleanupTrain returns to the target branch and deletes the train branch.
+// Best-effort: a leftover train branch is harmless and uniquely named.
+func (e *Engineer) cleanupTrain(train *mergeTrain) {
+	if train == nil || train.branch == "" {
+		return
+	}
+	_ = e.git.ResetHard("HEAD")
+	_ = e.git.Checkout(train.target)
+	_ = e.git.DeleteBranch(train.branch, true)
+}
@@ -0,0 +1,203 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// runGit runs a git command in dir and fails the test on error.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// setupTrainRepo creates a bare origin and a clone with a main branch, and
+// returns an Engineer operating on the clone.
+func setupTrainRepo(t *testing.T) (*Engineer, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	tmp := t.TempDir()
+	origin := filepath.Join(tmp, "origin.git")
+	work := filepath.Join(tmp, "work")
+
+	runGit(t, tmp, "init", "--bare", "-b", "main", origin)
+	runGit(t, tmp, "clone", origin, work)
+	runGit(t, work, "config", "user.email", "test@test.com")
+	runGit(t, work, "config", "user.name", "Test User")
+	runGit(t, work, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(work, "README.md"), []byte("# Test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, work, "add", ".")
+	runGit(t, work, "commit", "-m", "initial")
+	runGit(t, work, "push", "origin", "main")
+
+	cfg := DefaultMergeQueueConfig()
+	cfg.MaxConcurrent = 4
+	cfg.RunTests = true
+	// A branch "breaks the build" by adding a file named bad-*.txt
+	cfg.TestCommand = "! ls bad-*.txt >/dev/null 2>&1"
+
+	e := &Engineer{
+		git:     git.NewGit(work),
+		config:  cfg,
+		workDir: work,
+		output:  &bytes.Buffer{},
+	}
+	return e, work
+}
+
+// addBranch creates a polecat branch off main that adds a single file.
+func addBranch(t *testing.T, work, branch, file, content string) *MRInfo {
+	t.Helper()
+	runGit(t, work, "checkout", "-b", branch, "main")
+	if err := os.WriteFile(filepath.Join(work, file), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, work, "add", ".")
+	runGit(t, work, "commit", "-m", "feat: add "+file)
+	runGit(t, work, "checkout", "main")
+	return &MRInfo{ID: "mr-" + strings.TrimPrefix(branch, "polecat/"), Branch: branch, Target: "main", Worker: branch}
+}
+
+func TestNextBatch(t *testing.T) {
+	e := &Engineer{config: DefaultMergeQueueConfig()}
+	e.config.MaxConcurrent = 2
+
+	mrs := []*MRInfo{
+		{ID: "a", Target: "main"},
+		{ID: "b", Target: "integration/epic"},
+		{ID: "c", Target: ""},
+		{ID: "d", Target: "main"},
+	}
+
+	batch := e.NextBatch(mrs)
+	if len(batch) != 2 {
+		t.Fatalf("expected 2 MRs in batch, got %d", len(batch))
+	}
+	if batch[0].ID != "a" || batch[1].ID != "c" {
+		t.Errorf("expected [a c] (same target, in order), got [%s %s]", batch[0].ID, batch[1].ID)
+	}
+
+	e.config.MaxConcurrent = 0
+	if got := e.NextBatch(mrs); len(got) != 1 {
+		t.Errorf("MaxConcurrent=0 should mean serial, got batch of %d", len(got))
+	}
+}
+
+func TestOrderByQueue(t *testing.T) {
+	ready := []*MRInfo{{ID: "x"}, {ID: "b"}, {ID: "a"}}
+	queue := []QueueItem{
+		{Position: 1, MR: &MergeRequest{ID: "a"}},
+		{Position: 2, MR: &MergeRequest{ID: "b"}},
+	}
+
+	ordered := OrderByQueue(ready, queue)
+	var ids []string
+	for _, mr := range ordered {
+		ids = append(ids, mr.ID)
+	}
+	if got := strings.Join(ids, ","); got != "a,b,x" {
+		t.Errorf("OrderByQueue = %s, want a,b,x", got)
+	}
+}
+
+func TestProcessBatch_AllPass(t *testing.T) {
+	e, work := setupTrainRepo(t)
+	mrs := []*MRInfo{
+		addBranch(t, work, "polecat/one", "one.txt", "1\n"),
+		addBranch(t, work, "polecat/two", "two.txt", "2\n"),
+		addBranch(t, work, "polecat/three", "three.txt", "3\n"),
+	}
+
+	results := e.ProcessBatch(context.Background(), mrs)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if !r.Result.Success {
+			t.Errorf("%s: expected success, got %q", r.MR.ID, r.Result.Error)
+		}
+		if r.Result.MergeCommit == "" {
+			t.Errorf("%s: expected merge commit", r.MR.ID)
+		}
+	}
+
+	// All three landed on origin/main, one commit each
+	log := runGit(t, work, "log", "--format=%s", "origin/main")
+	for _, f := range []string{"one.txt", "two.txt", "three.txt"} {
+		if !strings.Contains(log, "feat: add "+f) {
+			t.Errorf("origin/main missing commit for %s:\n%s", f, log)
+		}
+	}
+
+	// Train branches are cleaned up
+	if branches := runGit(t, work, "branch", "--list", trainBranchPrefix+"*"); branches != "" {
+		t.Errorf("train branches left behind: %s", branches)
+	}
+}
+
+func TestProcessBatch_BisectsCulprit(t *testing.T) {
+	e, work := setupTrainRepo(t)
+	mrs := []*MRInfo{
+		addBranch(t, work, "polecat/one", "one.txt", "1\n"),
+		addBranch(t, work, "polecat/bad", "bad-build.txt", "boom\n"),
+		addBranch(t, work, "polecat/three", "three.txt", "3\n"),
+		addBranch(t, work, "polecat/four", "four.txt", "4\n"),
+	}
+
+	results := e.ProcessBatch(context.Background(), mrs)
+	for _, r := range results {
+		if r.MR.Branch == "polecat/bad" {
+			if r.Result.Success || !r.Result.TestsFailed {
+				t.Errorf("culprit should fail tests, got %+v", r.Result)
+			}
+			continue
+		}
+		if !r.Result.Success {
+			t.Errorf("%s: expected success, got %q", r.MR.ID, r.Result.Error)
+		}
+	}
+
+	files := runGit(t, work, "ls-tree", "--name-only", "origin/main")
+	if strings.Contains(files, "bad-build.txt") {
+		t.Error("culprit MR landed on origin/main")
+	}
+	for _, f := range []string{"one.txt", "three.txt", "four.txt"} {
+		if !strings.Contains(files, f) {
+			t.Errorf("origin/main missing %s", f)
+		}
+	}
+}
+
+func TestProcessBatch_DefersTrainConflict(t *testing.T) {
+	e, work := setupTrainRepo(t)
+	mrs := []*MRInfo{
+		addBranch(t, work, "polecat/first", "shared.txt", "first\n"),
+		addBranch(t, work, "polecat/second", "shared.txt", "second\n"),
+	}
+
+	results := e.ProcessBatch(context.Background(), mrs)
+	if !results[0].Result.Success {
+		t.Errorf("first MR should merge, got %q", results[0].Result.Error)
+	}
+	if !results[1].Deferred {
+		t.Errorf("second MR should be deferred (conflicts only with train), got %+v", results[1])
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/convoy"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/ledger"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -94,7 +95,7 @@ type MRInfo struct {
 // and processes them according to the merge queue design.
 type Engineer struct {
 	rig     *rig.Rig
-	beads   *beads.Beads
+	beads   beads.Store
 	git     *git.Git
 	config  *MergeQueueConfig
 	workDir string
@@ -486,6 +487,9 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 		}
 	}
 
+	// 3.6. Record the completed work in the town ledger
+	e.recordMerge(mrFields.SourceIssue, mrFields.Worker, mrFields.ConvoyID, result.MergeCommit)
+
 	// 4. Delete source branch if configured (local and remote)
 	// Since the self-cleaning model (Jan 10), polecats push to origin before gt done,
 	// so we need to clean up both local and remote branches after merge.
@@ -510,6 +514,53 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
+// recordMerge appends a completion record for a merged source issue to the
+// town ledger. Like the other post-merge steps it is best-effort: a failed
+// export is logged and does not affect the merge.
+func (e *Engineer) recordMerge(sourceIssue, worker, convoyID, mergeCommit string) {
+	if sourceIssue == "" {
+		return
+	}
+	townRoot := filepath.Dir(e.rig.Path)
+	rec := MergeRecord(e.beads, townRoot, e.workDir, e.rig.Name, sourceIssue, worker, convoyID, mergeCommit)
+	if _, _, err := ledger.Append(townRoot, rec); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record %s in the ledger: %v\n", sourceIssue, err)
+	}
+}
+
+// MergeRecord builds the mr_merged ledger record for a source issue merged
+// as mergeCommit, gathering its title, start, formula, cost and diff from
+// the store, the town event log and the git repository at workDir.
+func MergeRecord(b beads.Store, townRoot, workDir, rigName, sourceIssue, worker, convoyID, mergeCommit string) ledger.Record {
+	// MR workers are bare polecat names ("Nux")
+	actor := worker
+	if actor != "" && !strings.Contains(actor, "/") {
+		actor = fmt.Sprintf("%s/polecats/%s", rigName, worker)
+	}
+	rec := ledger.Record{
+		Trigger:   ledger.TriggerMerged,
+		Bead:      sourceIssue,
+		Actor:     actor,
+		Rig:       rigName,
+		Convoy:    convoyID,
+		Completed: time.Now().UTC(),
+		CostUSD:   ledger.WorkCost(sourceIssue),
+		Outcome:   "merged",
+		Ref:       mergeCommit,
+	}
+	rec.Started, rec.Formula = ledger.Slung(townRoot, sourceIssue)
+	if issue, err := b.Show(sourceIssue); err == nil {
+		rec.Title = issue.Title
+		if rec.Started == nil {
+			rec.Started = ledger.ParseTime(issue.CreatedAt)
+		}
+	}
+	if diff, err := ledger.DiffStat(workDir, mergeCommit); err == nil {
+		rec.Diff = diff
+	}
+	return rec
+}
+
 // syncCrewWorkspaces pulls latest changes to all crew workspaces.
 // This ensures crew members have access to newly merged code without manual sync.
 func (e *Engineer) syncCrewWorkspaces() {
@@ -635,6 +686,9 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
+	// 1.6. Record the completed work in the town ledger
+	e.recordMerge(mr.SourceIssue, mr.Worker, mr.ConvoyID, result.MergeCommit)
+
 	// 2. Delete source branch if configured (local only)
 	if e.config.DeleteMergedBranches && mr.Branch != "" {
 		if err := e.git.DeleteBranch(mr.Branch, true); err != nil {
@@ -644,7 +698,14 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
-	// 3. Log success
+	// 3. Notify Witness so the polecat's work is recorded as landed
+	msg := protocol.NewMergedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, result.MergeCommit)
+	protocol.Correlate(msg, mr.ID) // settles the witness's MERGE_READY for this MR
+	if err := protocol.Send(e.router, msg); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGED to witness: %v\n", err)
+	}
+
+	// 4. Log success
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
@@ -661,7 +722,8 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		failureType = "tests"
 	}
 	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
-	if err := e.router.Send(msg); err != nil {
+	protocol.Correlate(msg, mr.ID)
+	if err := protocol.Send(e.router, msg); err != nil {
 		fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGE_FAILED to witness: %v\n", err)
 	} else {
 		fmt.Fprintf(e.output, "[Engineer] Notified witness of merge failure for %s\n", mr.Worker)
@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/ledger"
 	"github.com/steveyegge/gastown/internal/rig"
 )
 
@@ -215,3 +217,22 @@ func TestEngineer_DeleteMergedBranchesConfig(t *testing.T) {
 		t.Error("expected DeleteMergedBranches to be true by default")
 	}
 }
+
+func TestMergeRecord(t *testing.T) {
+	store := beads.NewMemStore("gt")
+	issue, err := store.Create(beads.CreateOptions{Title: "Fix the widget"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := MergeRecord(store, t.TempDir(), t.TempDir(), "gastown", issue.ID, "Nux", "hq-cv-1", "abc123")
+	if rec.Trigger != ledger.TriggerMerged || rec.Bead != issue.ID || rec.Ref != "abc123" {
+		t.Errorf("record = %+v", rec)
+	}
+	if rec.Actor != "gastown/polecats/Nux" {
+		t.Errorf("Actor = %q, want gastown/polecats/Nux", rec.Actor)
+	}
+	if rec.Title != "Fix the widget" || rec.Convoy != "hq-cv-1" || rec.Rig != "gastown" {
+		t.Errorf("record = %+v", rec)
+	}
+}
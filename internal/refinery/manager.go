@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 )
 
 // Common errors
@@ -30,17 +31,20 @@ var (
 
 // Manager handles refinery lifecycle and queue operations.
 type Manager struct {
-	rig     *rig.Rig
-	workDir string
-	output  io.Writer // Output destination for user-facing messages
+	rig      *rig.Rig
+	workDir  string
+	output   io.Writer // Output destination for user-facing messages
+	sessions session.Backend
 }
 
-// NewManager creates a new refinery manager for a rig.
+// NewManager creates a new refinery manager for a rig, using the session
+// backend selected by GT_SESSION_BACKEND.
 func NewManager(r *rig.Rig) *Manager {
 	return &Manager{
-		rig:     r,
-		workDir: r.Path,
-		output:  os.Stdout,
+		rig:      r,
+		workDir:  r.Path,
+		output:   os.Stdout,
+		sessions: session.DefaultBackend(),
 	}
 }
 
@@ -58,17 +62,16 @@ func (m *Manager) SessionName() string {
 // IsRunning checks if the refinery session is active.
 // ZFC: tmux session existence is the source of truth.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
-	return t.HasSession(m.SessionName())
+	return m.sessions.HasSession(m.SessionName())
 }
 
 // Status returns information about the refinery session.
 // ZFC-compliant: tmux session is the source of truth.
+// Backends other than tmux report only the session name.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
 
-	running, err := t.HasSession(sessionID)
+	running, err := m.sessions.HasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -76,7 +79,10 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	if t, ok := m.sessions.(*tmux.Tmux); ok {
+		return t.GetSessionInfo(sessionID)
+	}
+	return &tmux.SessionInfo{Name: sessionID}, nil
 }
 
 // Start starts the refinery.
@@ -85,7 +91,8 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 // The agentOverride parameter allows specifying an agent alias to use instead of the town default.
 // ZFC-compliant: no state file, tmux session is source of truth.
 func (m *Manager) Start(foreground bool, agentOverride string) error {
-	t := tmux.NewTmux()
+	t := m.sessions
+	realTmux, isTmux := t.(*tmux.Tmux)
 	sessionID := m.SessionName()
 
 	if foreground {
@@ -96,13 +103,14 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	// Check if session already exists
 	running, _ := t.HasSession(sessionID)
 	if running {
-		// Session exists - check if agent is actually running (healthy vs zombie)
-		if t.IsAgentAlive(sessionID) {
+		// Session exists - check if agent is actually running (healthy vs zombie).
+		// Other backends end the session with the agent, so it is healthy.
+		if !isTmux || realTmux.IsAgentAlive(sessionID) {
 			return ErrAlreadyRunning
 		}
 		// Zombie - tmux alive but agent dead. Kill and recreate.
 		_, _ = fmt.Fprintln(m.output, "⚠ Detected zombie session (tmux alive, agent dead). Recreating...")
-		if err := t.KillSession(sessionID); err != nil {
+		if err := t.KillSessionWithProcesses(sessionID); err != nil {
 			return fmt.Errorf("killing zombie session: %w", err)
 		}
 	}
@@ -152,6 +160,11 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
 
+	// Record pane output so the session can be replayed after it is gone (non-fatal)
+	if isTmux {
+		_ = transcript.Start(realTmux, townRoot, sessionID)
+	}
+
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	envVars := config.AgentEnv(config.AgentEnvConfig{
@@ -168,25 +181,29 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 		_ = t.SetEnvironment(sessionID, k, v)
 	}
 
-	// Apply theme (non-fatal: theming failure doesn't affect operation)
-	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "refinery", "refinery")
+	if isTmux {
+		// Apply theme (non-fatal: theming failure doesn't affect operation)
+		theme := tmux.AssignTheme(m.rig.Name)
+		_ = realTmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "refinery", "refinery")
 
-	// Accept bypass permissions warning dialog if it appears.
-	// Must be before WaitForRuntimeReady to avoid race where dialog blocks prompt detection.
-	_ = t.AcceptBypassPermissionsWarning(sessionID)
+		// Accept bypass permissions warning dialog if it appears.
+		// Must be before WaitForRuntimeReady to avoid race where dialog blocks prompt detection.
+		_ = realTmux.AcceptBypassPermissionsWarning(sessionID)
 
-	// Wait for Claude to start and show its prompt - fatal if Claude fails to launch
-	// WaitForRuntimeReady waits for the runtime to be ready
-	if err := t.WaitForRuntimeReady(sessionID, runtimeConfig, constants.ClaudeStartTimeout); err != nil {
-		// Kill the zombie session before returning error
-		_ = t.KillSessionWithProcesses(sessionID)
-		return fmt.Errorf("waiting for refinery to start: %w", err)
+		// Wait for Claude to start and show its prompt - fatal if Claude fails to launch
+		// WaitForRuntimeReady waits for the runtime to be ready
+		if err := realTmux.WaitForRuntimeReady(sessionID, runtimeConfig, constants.ClaudeStartTimeout); err != nil {
+			// Kill the zombie session before returning error
+			_ = t.KillSessionWithProcesses(sessionID)
+			return fmt.Errorf("waiting for refinery to start: %w", err)
+		}
 	}
 
 	// Wait for runtime to be fully ready
 	runtime.SleepForReadyDelay(runtimeConfig)
-	_ = runtime.RunStartupFallback(t, sessionID, "refinery", runtimeConfig)
+	if isTmux {
+		_ = runtime.RunStartupFallback(realTmux, sessionID, "refinery", runtimeConfig)
+	}
 
 	return nil
 }
@@ -194,17 +211,16 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 // Stop stops the refinery.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
 
 	// Check if tmux session exists
-	running, _ := t.HasSession(sessionID)
+	running, _ := m.sessions.HasSession(sessionID)
 	if !running {
 		return ErrNotRunning
 	}
 
 	// Kill the tmux session
-	return t.KillSession(sessionID)
+	return m.sessions.KillSessionWithProcesses(sessionID)
 }
 
 // Queue returns the current merge queue.
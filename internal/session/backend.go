@@ -0,0 +1,66 @@
+package session
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/gastown/internal/headless"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// EnvBackend selects the session backend: "tmux" (default) or "headless".
+const EnvBackend = "GT_SESSION_BACKEND"
+
+// Session backend names.
+const (
+	BackendTmux     = "tmux"
+	BackendHeadless = "headless"
+)
+
+// Backend hosts agent sessions. It is the subset of session operations the
+// managers rely on; tmux.Tmux and headless.Backend implement it.
+type Backend interface {
+	NewSessionWithCommand(name, workDir, command string) error
+	KillSessionWithProcesses(name string) error
+	SendKeys(session, keys string) error
+	CapturePane(session string, lines int) (string, error)
+	HasSession(name string) (bool, error)
+	ListSessions() ([]string, error)
+	SetEnvironment(session, key, value string) error
+	SetAutoRespawnHook(session, townRoot, startupCmd string) error
+}
+
+var (
+	_ Backend = (*tmux.Tmux)(nil)
+	_ Backend = (*headless.Backend)(nil)
+)
+
+// BackendName returns the configured backend name, defaulting to tmux.
+func BackendName() string {
+	if name := os.Getenv(EnvBackend); name != "" {
+		return name
+	}
+	return BackendTmux
+}
+
+// NewBackend returns the backend selected by GT_SESSION_BACKEND.
+func NewBackend() (Backend, error) {
+	switch name := BackendName(); name {
+	case BackendTmux:
+		return tmux.NewTmux(), nil
+	case BackendHeadless:
+		return headless.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown session backend %q (want %s or %s)", name, BackendTmux, BackendHeadless)
+	}
+}
+
+// DefaultBackend returns the backend selected by GT_SESSION_BACKEND,
+// falling back to tmux if it names an unknown backend.
+func DefaultBackend() Backend {
+	b, err := NewBackend()
+	if err != nil {
+		return tmux.NewTmux()
+	}
+	return b
+}
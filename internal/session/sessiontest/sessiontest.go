@@ -0,0 +1,162 @@
+// Package sessiontest provides an in-memory session.Backend for tests.
+package sessiontest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// Session is a fake session's recorded state.
+type Session struct {
+	WorkDir    string
+	Command    string
+	Env        map[string]string
+	Keys       []string // SendKeys input, in order
+	Output     string   // What CapturePane returns
+	RespawnCmd string   // Set by SetAutoRespawnHook
+}
+
+// Backend is a session.Backend that keeps sessions in memory. The error
+// fields, when set, are returned by the matching calls; calls are recorded
+// either way.
+type Backend struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+
+	HasSessionErr error
+	NewSessionErr error
+	KillErr       error
+	SendKeysErr   error
+
+	Created []string // Sessions passed to NewSessionWithCommand
+	Killed  []string // Sessions passed to KillSessionWithProcesses
+}
+
+var _ session.Backend = (*Backend)(nil)
+
+// New returns a fake backend with the named sessions already running.
+func New(running ...string) *Backend {
+	b := &Backend{sessions: make(map[string]*Session)}
+	for _, name := range running {
+		b.sessions[name] = &Session{Env: make(map[string]string)}
+	}
+	return b
+}
+
+// Session returns a session's state, or nil if it is not running.
+func (b *Backend) Session(name string) *Session {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sessions[name]
+}
+
+// NewSessionWithCommand starts a session unless one of that name is running.
+func (b *Backend) NewSessionWithCommand(name, workDir, command string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.Created = append(b.Created, name)
+	if b.NewSessionErr != nil {
+		return b.NewSessionErr
+	}
+	if _, ok := b.sessions[name]; ok {
+		return fmt.Errorf("duplicate session: %s", name)
+	}
+	b.sessions[name] = &Session{WorkDir: workDir, Command: command, Env: make(map[string]string)}
+	return nil
+}
+
+// KillSessionWithProcesses ends a session.
+func (b *Backend) KillSessionWithProcesses(name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.Killed = append(b.Killed, name)
+	if b.KillErr != nil {
+		return b.KillErr
+	}
+	if _, ok := b.sessions[name]; !ok {
+		return fmt.Errorf("session not found: %s", name)
+	}
+	delete(b.sessions, name)
+	return nil
+}
+
+// SendKeys records keys as input to a session.
+func (b *Backend) SendKeys(name, keys string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.SendKeysErr != nil {
+		return b.SendKeysErr
+	}
+	s, ok := b.sessions[name]
+	if !ok {
+		return fmt.Errorf("session not found: %s", name)
+	}
+	s.Keys = append(s.Keys, keys)
+	return nil
+}
+
+// CapturePane returns the last lines of a session's Output.
+func (b *Backend) CapturePane(name string, lines int) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.sessions[name]
+	if !ok {
+		return "", fmt.Errorf("session not found: %s", name)
+	}
+	out := strings.Split(strings.TrimRight(s.Output, "\n"), "\n")
+	if lines > 0 && len(out) > lines {
+		out = out[len(out)-lines:]
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+// HasSession reports whether a session is running.
+func (b *Backend) HasSession(name string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.HasSessionErr != nil {
+		return false, b.HasSessionErr
+	}
+	_, ok := b.sessions[name]
+	return ok, nil
+}
+
+// ListSessions returns the running sessions, sorted.
+func (b *Backend) ListSessions() ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	names := make([]string, 0, len(b.sessions))
+	for name := range b.sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// SetEnvironment records an environment variable on a session.
+func (b *Backend) SetEnvironment(name, key, value string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.sessions[name]
+	if !ok {
+		return fmt.Errorf("session not found: %s", name)
+	}
+	s.Env[key] = value
+	return nil
+}
+
+// SetAutoRespawnHook records the command a session respawns with.
+func (b *Backend) SetAutoRespawnHook(name, _, startupCmd string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.sessions[name]
+	if !ok {
+		return fmt.Errorf("session not found: %s", name)
+	}
+	s.RespawnCmd = startupCmd
+	return nil
+}
@@ -129,8 +129,11 @@ func (t *Tmux) NewSessionWithCommand(name, workDir, command string) error {
 	if workDir != "" {
 		args = append(args, "-c", workDir)
 	}
-	// Add the command as the last argument - tmux runs it as the pane's initial process
-	args = append(args, command)
+	// Add the command as the last argument - tmux runs it as the pane's initial process.
+	// An empty command leaves tmux to start the default shell.
+	if command != "" {
+		args = append(args, command)
+	}
 	_, err := t.run(args...)
 	return err
 }
@@ -1897,6 +1900,29 @@ func (t *Tmux) SetPaneDiedHook(session, agentID string) error {
 	return err
 }
 
+// PipePaneOutput pipes everything the session's pane prints to command's
+// stdin (tmux pipe-pane -o). The pipe lasts for the life of the pane; an
+// existing pipe is left in place.
+func (t *Tmux) PipePaneOutput(session, command string) error {
+	if err := validateSessionName(session); err != nil {
+		return err
+	}
+	_, err := t.run("pipe-pane", "-o", "-t", session, command)
+	return err
+}
+
+// GetPaneSize returns the width and height of a session's pane.
+func (t *Tmux) GetPaneSize(session string) (cols, rows int, err error) {
+	out, err := t.run("display-message", "-p", "-t", session, "#{pane_width} #{pane_height}")
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := fmt.Sscanf(strings.TrimSpace(out), "%d %d", &cols, &rows); err != nil {
+		return 0, 0, fmt.Errorf("parsing pane size %q: %w", out, err)
+	}
+	return cols, rows, nil
+}
+
 // SetAutoRespawnHook configures a session to automatically respawn when the pane dies.
 // This is used for persistent agents like Deacon that should never exit.
 // PATCH-010: Fixes Deacon crash loop by respawning at tmux level.
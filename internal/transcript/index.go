@@ -0,0 +1,150 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// IndexEntry records that a bead was put on an agent's hook, so its
+// transcript can be found by bead ID.
+type IndexEntry struct {
+	Bead    string    `json:"bead"`
+	Agent   string    `json:"agent"`
+	Session string    `json:"session"`
+	At      time.Time `json:"at"`
+}
+
+// Match is a recording that shows work on a bead, starting at Entry.At.
+type Match struct {
+	Entry     IndexEntry `json:"entry"`
+	Recording Recording  `json:"recording"`
+}
+
+func indexPath(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "index.jsonl")
+}
+
+// IndexBead records that agent (a mail-style address such as
+// "gastown/polecats/Toast") took bead now.
+func IndexBead(townRoot, bead, agent string) error {
+	id, err := session.ParseAddress(agent)
+	if err != nil {
+		return fmt.Errorf("indexing transcript for %s: %w", agent, err)
+	}
+	entry := IndexEntry{Bead: bead, Agent: agent, Session: id.SessionName(), At: time.Now().UTC()}
+
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating transcript directory: %w", err)
+	}
+	fl := flock.New(indexPath(townRoot) + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring transcript index lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	f, err := os.OpenFile(indexPath(townRoot), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // G302: index is non-sensitive
+	if err != nil {
+		return fmt.Errorf("opening transcript index: %w", err)
+	}
+	defer f.Close()
+	return writeJSONLine(f, entry)
+}
+
+// ReadIndex returns all index entries, oldest first.
+func ReadIndex(townRoot string) ([]IndexEntry, error) {
+	f, err := os.Open(indexPath(townRoot))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading transcript index: %w", err)
+	}
+	defer f.Close()
+
+	var entries []IndexEntry
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e IndexEntry
+		if json.Unmarshal(sc.Bytes(), &e) == nil && e.Bead != "" {
+			entries = append(entries, e)
+		}
+	}
+	return entries, sc.Err()
+}
+
+// ForBead returns the recordings showing work on bead: for each time it
+// was hooked, the recording of that agent's session running then or, if
+// the session started afterwards, the first one after.
+func ForBead(townRoot, bead string) ([]Match, error) {
+	entries, err := ReadIndex(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var matches []Match
+	for _, e := range entries {
+		if e.Bead != bead {
+			continue
+		}
+		recs, err := List(townRoot, e.Session)
+		if err != nil {
+			return nil, err
+		}
+		for _, rec := range recs {
+			if rec.Covers(e.At) || rec.Started.After(e.At) {
+				matches = append(matches, Match{Entry: e, Recording: rec})
+				break
+			}
+		}
+	}
+	return matches, nil
+}
+
+// pruneIndex drops index entries made before cutoff.
+func pruneIndex(townRoot string, cutoff time.Time) error {
+	path := indexPath(townRoot)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring transcript index lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	entries, err := ReadIndex(townRoot)
+	if err != nil {
+		return err
+	}
+	var kept []IndexEntry
+	for _, e := range entries {
+		if !e.At.Before(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == len(entries) {
+		return nil
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("writing transcript index: %w", err)
+	}
+	for _, e := range kept {
+		if err := writeJSONLine(f, e); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,26 @@
+package transcript
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Start begins recording a tmux session by piping its pane output to
+// gt session record. Like the pane-died hook, it relies on gt being on the
+// tmux server's PATH.
+func Start(t *tmux.Tmux, townRoot, session string) error {
+	cols, rows, err := t.GetPaneSize(session)
+	if err != nil {
+		cols, rows = 80, 24
+	}
+	command := fmt.Sprintf("exec gt session record --town %s --cols %d --rows %d %s",
+		shellQuote(townRoot), cols, rows, shellQuote(session))
+	return t.PipePaneOutput(session, command)
+}
+
+// shellQuote quotes s for /bin/sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,374 @@
+// Package transcript records agent session output and plays it back.
+//
+// While a polecat session runs, tmux pipes its pane output to a recorder
+// (gt session record) that writes a gzip-compressed asciicast v2 file: a
+// JSON header line followed by one [seconds, "o", data] event per chunk of
+// output. Recordings outlive the session, so a nuked polecat's terminal
+// history can still be replayed, and an index keyed by bead ID tells which
+// session worked on which issue and when. Retention follows the krc TTL for
+// the "transcript" type.
+//
+// Files live under <town>/.runtime/transcripts:
+//
+//	<session>/<start-time>.cast.gz  one recording per session start
+//	index.jsonl                     bead → session index
+package transcript
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// KRCType is the krc event type whose TTL governs transcript retention.
+const KRCType = "transcript"
+
+// ErrNoRecording is returned when no recording matches a query.
+var ErrNoRecording = errors.New("no recording found")
+
+const (
+	fileExt    = ".cast.gz"
+	timeLayout = "20060102T150405.000Z"
+)
+
+// flushInterval bounds how much output a recorder that is killed outright
+// can lose; gzip frames are flushed at most this often.
+const flushInterval = time.Second
+
+// Header is the asciicast v2 header line of a recording.
+type Header struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Title     string `json:"title,omitempty"`
+}
+
+// Event is one chunk of output, Time seconds after the recording started.
+type Event struct {
+	Time float64
+	Data string
+}
+
+// Recording is a transcript file on disk.
+type Recording struct {
+	Session string    `json:"session"`
+	Path    string    `json:"path"`
+	Started time.Time `json:"started"`
+	Ended   time.Time `json:"ended"` // Last write
+	Size    int64     `json:"size"`
+}
+
+// Covers reports whether the recording was running at t.
+func (r Recording) Covers(t time.Time) bool {
+	return !t.Before(r.Started) && !t.After(r.Ended)
+}
+
+// Dir returns the town's transcript directory.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "transcripts")
+}
+
+// Record copies pane output from r into a new recording for session until r
+// reaches EOF. cols and rows are the pane size for the header.
+func Record(townRoot, session string, cols, rows int, r io.Reader) error {
+	start := time.Now().UTC()
+	dir := filepath.Join(Dir(townRoot), session)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating transcript directory: %w", err)
+	}
+	path := filepath.Join(dir, start.Format(timeLayout)+fileExt)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("creating transcript: %w", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	if err := writeJSONLine(gz, Header{
+		Version:   2,
+		Width:     cols,
+		Height:    rows,
+		Timestamp: start.Unix(),
+		Title:     session,
+	}); err != nil {
+		return err
+	}
+	if err := gz.Flush(); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 32*1024)
+	var pending []byte // Incomplete UTF-8 sequence carried to the next chunk
+	lastFlush := time.Now()
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			data := append(pending, buf[:n]...)
+			data, pending = splitUTF8(data)
+			if len(data) > 0 {
+				elapsed := time.Since(start).Seconds()
+				if err := writeJSONLine(gz, []interface{}{elapsed, "o", string(data)}); err != nil {
+					return err
+				}
+			}
+			if time.Since(lastFlush) >= flushInterval {
+				if err := gz.Flush(); err != nil {
+					return err
+				}
+				lastFlush = time.Now()
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			_ = gz.Close()
+			return fmt.Errorf("reading pane output: %w", readErr)
+		}
+	}
+	return gz.Close()
+}
+
+// splitUTF8 splits data before a trailing incomplete UTF-8 sequence, so a
+// multi-byte character cut across reads is not mangled.
+func splitUTF8(data []byte) (complete, rest []byte) {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		b := data[len(data)-i]
+		if !utf8.RuneStart(b) {
+			continue
+		}
+		if !utf8.FullRune(data[len(data)-i:]) {
+			return data[:len(data)-i], append([]byte(nil), data[len(data)-i:]...)
+		}
+		break
+	}
+	return data, nil
+}
+
+func writeJSONLine(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// List returns the recordings of session, oldest first. An empty session
+// lists every recording in the town.
+func List(townRoot, session string) ([]Recording, error) {
+	pattern := filepath.Join(Dir(townRoot), "*", "*"+fileExt)
+	if session != "" {
+		pattern = filepath.Join(Dir(townRoot), session, "*"+fileExt)
+	}
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	var recs []Recording
+	for _, path := range paths {
+		started, err := time.Parse(timeLayout, strings.TrimSuffix(filepath.Base(path), fileExt))
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		recs = append(recs, Recording{
+			Session: filepath.Base(filepath.Dir(path)),
+			Path:    path,
+			Started: started,
+			Ended:   info.ModTime(),
+			Size:    info.Size(),
+		})
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Started.Before(recs[j].Started) })
+	return recs, nil
+}
+
+// Find returns the recording of session running at t, or failing that the
+// last one started before t. A zero t selects the latest recording.
+func Find(townRoot, session string, t time.Time) (Recording, error) {
+	recs, err := List(townRoot, session)
+	if err != nil {
+		return Recording{}, err
+	}
+	for i := len(recs) - 1; i >= 0; i-- {
+		if t.IsZero() || recs[i].Covers(t) || recs[i].Started.Before(t) {
+			return recs[i], nil
+		}
+	}
+	return Recording{}, fmt.Errorf("%w for %s", ErrNoRecording, session)
+}
+
+// Reader reads the events of a recording.
+type Reader struct {
+	Header Header
+
+	f  *os.File
+	gz *gzip.Reader
+	sc *bufio.Scanner
+}
+
+// Open opens a recording and reads its header.
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path comes from List
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("opening transcript %s: %w", path, err)
+	}
+	sc := bufio.NewScanner(gz)
+	sc.Buffer(make([]byte, 64*1024), 4<<20)
+
+	r := &Reader{f: f, gz: gz, sc: sc}
+	if !sc.Scan() {
+		_ = r.Close()
+		return nil, fmt.Errorf("transcript %s has no header", path)
+	}
+	if err := json.Unmarshal(sc.Bytes(), &r.Header); err != nil {
+		_ = r.Close()
+		return nil, fmt.Errorf("parsing transcript header: %w", err)
+	}
+	return r, nil
+}
+
+// Started returns when the recording started.
+func (r *Reader) Started() time.Time {
+	return time.Unix(r.Header.Timestamp, 0)
+}
+
+// Next returns the next event, or io.EOF. A recording cut off mid-write
+// (the recorder was killed) ends at its last complete event.
+func (r *Reader) Next() (Event, error) {
+	for r.sc.Scan() {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(r.sc.Bytes(), &raw); err != nil || len(raw) != 3 {
+			continue
+		}
+		var ev Event
+		var kind string
+		if json.Unmarshal(raw[0], &ev.Time) != nil || json.Unmarshal(raw[1], &kind) != nil || kind != "o" {
+			continue
+		}
+		if json.Unmarshal(raw[2], &ev.Data) != nil {
+			continue
+		}
+		return ev, nil
+	}
+	if err := r.sc.Err(); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return Event{}, err
+	}
+	return Event{}, io.EOF
+}
+
+// Close closes the recording.
+func (r *Reader) Close() error {
+	_ = r.gz.Close()
+	return r.f.Close()
+}
+
+// PlayOptions controls playback.
+type PlayOptions struct {
+	// From skips ahead: output before it is written at once, so the screen
+	// shows the session as it was at that time, and playback runs from there.
+	From time.Time
+
+	// Speed multiplies playback speed (default 1).
+	Speed float64
+
+	// MaxIdle caps pauses between events (default 2s).
+	MaxIdle time.Duration
+
+	// Instant writes all output without pauses.
+	Instant bool
+}
+
+// Play writes a recording's output to w in real time, as adjusted by opts.
+func Play(path string, w io.Writer, opts PlayOptions) error {
+	r, err := Open(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	speed := opts.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	maxIdle := opts.MaxIdle
+	if maxIdle <= 0 {
+		maxIdle = 2 * time.Second
+	}
+	var skip float64
+	if !opts.From.IsZero() {
+		skip = opts.From.Sub(r.Started()).Seconds()
+	}
+
+	last := skip
+	for {
+		ev, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !opts.Instant && ev.Time > last {
+			pause := time.Duration((ev.Time - last) / speed * float64(time.Second))
+			if pause > maxIdle {
+				pause = maxIdle
+			}
+			time.Sleep(pause)
+		}
+		if ev.Time > last {
+			last = ev.Time
+		}
+		if _, err := io.WriteString(w, ev.Data); err != nil {
+			return err
+		}
+	}
+}
+
+// Prune removes recordings last written more than ttl before now, along
+// with index entries older than ttl. Returns the number of recordings
+// removed and the bytes freed.
+func Prune(townRoot string, ttl time.Duration, now time.Time) (removed int, freed int64, err error) {
+	recs, err := List(townRoot, "")
+	if err != nil {
+		return 0, 0, err
+	}
+	cutoff := now.Add(-ttl)
+	for _, rec := range recs {
+		if rec.Ended.After(cutoff) {
+			continue
+		}
+		if err := os.Remove(rec.Path); err != nil && !os.IsNotExist(err) {
+			return removed, freed, fmt.Errorf("removing transcript: %w", err)
+		}
+		removed++
+		freed += rec.Size
+		// Drop the session directory once empty; fails harmlessly otherwise
+		_ = os.Remove(filepath.Dir(rec.Path))
+	}
+	if err := pruneIndex(townRoot, cutoff); err != nil {
+		return removed, freed, err
+	}
+	return removed, freed, nil
+}
@@ -0,0 +1,171 @@
+package transcript
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// chunkReader returns one chunk per Read, like a pipe fed by a pane.
+type chunkReader struct {
+	chunks [][]byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	return n, nil
+}
+
+func record(t *testing.T, townRoot, session string, chunks ...string) Recording {
+	t.Helper()
+	r := &chunkReader{}
+	for _, c := range chunks {
+		r.chunks = append(r.chunks, []byte(c))
+	}
+	if err := Record(townRoot, session, 120, 40, r); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	rec, err := Find(townRoot, session, time.Time{})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	return rec
+}
+
+func TestRecordAndPlay(t *testing.T) {
+	town := t.TempDir()
+	// "é" is split across reads and must survive intact
+	rec := record(t, town, "gt-gastown-Toast", "hello ", "caf\xc3", "\xa9\r\n")
+
+	r, err := Open(rec.Path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if r.Header.Version != 2 || r.Header.Width != 120 || r.Header.Height != 40 || r.Header.Title != "gt-gastown-Toast" {
+		t.Errorf("header = %+v", r.Header)
+	}
+	_ = r.Close()
+
+	var out bytes.Buffer
+	if err := Play(rec.Path, &out, PlayOptions{Instant: true}); err != nil {
+		t.Fatalf("Play: %v", err)
+	}
+	if out.String() != "hello café\r\n" {
+		t.Errorf("played %q, want %q", out.String(), "hello café\r\n")
+	}
+}
+
+func TestPlayTruncatedRecording(t *testing.T) {
+	town := t.TempDir()
+	rec := record(t, town, "gt-gastown-Toast", strings.Repeat("output line\r\n", 2000))
+
+	// A recorder killed mid-write leaves a gzip stream without its trailer
+	data, err := os.ReadFile(rec.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(rec.Path, data[:len(data)-8], 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := Play(rec.Path, &out, PlayOptions{Instant: true}); err != nil {
+		t.Fatalf("Play truncated: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "output line\r\n") {
+		t.Errorf("played %q", out.String()[:min(40, out.Len())])
+	}
+}
+
+func TestFind(t *testing.T) {
+	town := t.TempDir()
+	first := record(t, town, "gt-gastown-Toast", "first")
+	time.Sleep(5 * time.Millisecond)
+	second := record(t, town, "gt-gastown-Toast", "second")
+
+	recs, err := List(town, "gt-gastown-Toast")
+	if err != nil || len(recs) != 2 {
+		t.Fatalf("List = %v, %v; want 2 recordings", recs, err)
+	}
+
+	if got, _ := Find(town, "gt-gastown-Toast", time.Time{}); got.Path != second.Path {
+		t.Errorf("Find(latest) = %s, want %s", got.Path, second.Path)
+	}
+	if got, _ := Find(town, "gt-gastown-Toast", first.Started); got.Path != first.Path {
+		t.Errorf("Find(first start) = %s, want %s", got.Path, first.Path)
+	}
+	if _, err := Find(town, "gt-gastown-Toast", first.Started.Add(-time.Hour)); err == nil {
+		t.Error("Find before any recording should fail")
+	}
+	if _, err := Find(town, "gt-gastown-Nux", time.Time{}); err == nil {
+		t.Error("Find for unrecorded session should fail")
+	}
+}
+
+func TestForBead(t *testing.T) {
+	town := t.TempDir()
+	if err := IndexBead(town, "gt-abc", "gastown/polecats/Toast"); err != nil {
+		t.Fatalf("IndexBead: %v", err)
+	}
+	rec := record(t, town, "gt-gastown-Toast", "working on gt-abc")
+
+	matches, err := ForBead(town, "gt-abc")
+	if err != nil {
+		t.Fatalf("ForBead: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Recording.Path != rec.Path || matches[0].Entry.Session != "gt-gastown-Toast" {
+		t.Fatalf("ForBead = %+v", matches)
+	}
+	if matches, _ := ForBead(town, "gt-other"); len(matches) != 0 {
+		t.Errorf("ForBead(unindexed) = %+v", matches)
+	}
+	if err := IndexBead(town, "gt-abc", "not-an-address"); err == nil {
+		t.Error("IndexBead should reject an invalid agent address")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	town := t.TempDir()
+	if err := IndexBead(town, "gt-abc", "gastown/polecats/Toast"); err != nil {
+		t.Fatal(err)
+	}
+	old := record(t, town, "gt-gastown-Toast", "old")
+	fresh := record(t, town, "gt-gastown-Nux", "fresh")
+	past := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(old.Path, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, freed, err := Prune(town, 24*time.Hour, time.Now())
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if removed != 1 || freed != old.Size {
+		t.Errorf("Prune removed %d (%d bytes), want 1 (%d bytes)", removed, freed, old.Size)
+	}
+	if _, err := os.Stat(filepath.Dir(old.Path)); !os.IsNotExist(err) {
+		t.Error("empty session directory should be removed")
+	}
+	if _, err := os.Stat(fresh.Path); err != nil {
+		t.Errorf("fresh recording removed: %v", err)
+	}
+	if entries, _ := ReadIndex(town); len(entries) != 1 {
+		t.Errorf("index entries = %+v, want the fresh one", entries)
+	}
+
+	// A day later the index entry expires too
+	if _, _, err := Prune(town, 24*time.Hour, time.Now().Add(25*time.Hour)); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if entries, _ := ReadIndex(town); len(entries) != 0 {
+		t.Errorf("index entries = %+v, want none", entries)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
 )
 
 // EventSource represents a source of events
@@ -220,14 +221,7 @@ type GtEventsSource struct {
 }
 
 // GtEvent is the structure of events in .events.jsonl
-type GtEvent struct {
-	Timestamp  string                 `json:"ts"`
-	Source     string                 `json:"source"`
-	Type       string                 `json:"type"`
-	Actor      string                 `json:"actor"`
-	Payload    map[string]interface{} `json:"payload"`
-	Visibility string                 `json:"visibility"`
-}
+type GtEvent = events.Event
 
 // NewGtEventsSource creates a source that tails ~/gt/.events.jsonl
 func NewGtEventsSource(townRoot string) (*GtEventsSource, error) {
@@ -306,26 +300,11 @@ func parseGtEventLine(line string) *Event {
 		return nil
 	}
 
-	t, err := time.Parse(time.RFC3339, ge.Timestamp)
-	if err != nil {
+	t, ok := ge.Time()
+	if !ok {
 		t = time.Now()
 	}
 
-	// Extract rig from payload or actor
-	rig := ""
-	if ge.Payload != nil {
-		if r, ok := ge.Payload["rig"].(string); ok {
-			rig = r
-		}
-	}
-	if rig == "" && ge.Actor != "" {
-		// Extract rig from actor like "gastown/witness"
-		parts := strings.Split(ge.Actor, "/")
-		if len(parts) > 0 && parts[0] != "mayor" && parts[0] != "deacon" {
-			rig = parts[0]
-		}
-	}
-
 	// Extract role from actor
 	role := ""
 	if ge.Actor != "" {
@@ -361,7 +340,7 @@ func parseGtEventLine(line string) *Event {
 		Actor:   ge.Actor,
 		Target:  getPayloadString(ge.Payload, "bead"),
 		Message: message,
-		Rig:     rig,
+		Rig:     ge.Rig(),
 		Role:    role,
 		Raw:     line,
 	}
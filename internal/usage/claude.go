@@ -0,0 +1,98 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// claudeExtractor reads Claude Code transcripts, stored per working
+// directory in ~/.claude/projects/<path-with-dashes-instead-of-slashes>/.
+type claudeExtractor struct {
+	dir string
+}
+
+// claudeMessage is one line of a Claude Code transcript.
+type claudeMessage struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int `json:"input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+		} `json:"usage,omitempty"`
+	} `json:"message,omitempty"`
+}
+
+func (e *claudeExtractor) Extract(workDir string) (*Usage, error) {
+	root, err := homeDir(e.dir, ".claude", "projects")
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep leading slash - it becomes a leading dash in Claude's encoding
+	projectDir := filepath.Join(root, strings.ReplaceAll(workDir, "/", "-"))
+	files, err := findTranscripts(projectDir, false, func(name string) bool {
+		return strings.HasSuffix(name, ".jsonl")
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no transcript files found in %s", projectDir)
+	}
+	return parseClaudeTranscript(files[0].path)
+}
+
+// parseClaudeTranscript sums token usage from a transcript's assistant messages.
+func parseClaudeTranscript(path string) (*Usage, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is a discovered transcript
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	u := &Usage{}
+	scanner := bufio.NewScanner(file)
+	// Increase buffer for potentially large JSON lines
+	buf := make([]byte, 0, 256*1024)
+	scanner.Buffer(buf, 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var msg claudeMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue // Skip malformed lines
+		}
+
+		// Only process assistant messages with usage info
+		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
+			continue
+		}
+
+		// Capture the model (use first one found, they should all be the same)
+		if u.Model == "" && msg.Message.Model != "" {
+			u.Model = msg.Message.Model
+		}
+
+		mu := msg.Message.Usage
+		u.InputTokens += mu.InputTokens
+		u.CacheWriteTokens += mu.CacheCreationInputTokens
+		u.CacheReadTokens += mu.CacheReadInputTokens
+		u.OutputTokens += mu.OutputTokens
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
@@ -0,0 +1,112 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxCodexCandidates bounds how many recent rollouts are opened looking for
+// the one that ran in a working directory.
+const maxCodexCandidates = 50
+
+// codexExtractor reads Codex rollout logs, stored by date in
+// ~/.codex/sessions/YYYY/MM/DD/rollout-*.jsonl. Rollouts are not grouped by
+// directory, so the newest one whose session cwd matches is used.
+type codexExtractor struct {
+	dir string
+}
+
+// codexLine is one line of a Codex rollout.
+type codexLine struct {
+	Type    string `json:"type"`
+	Payload struct {
+		Type  string `json:"type"`
+		CWD   string `json:"cwd"`
+		Model string `json:"model"`
+		Info  *struct {
+			Total *struct {
+				InputTokens       int `json:"input_tokens"`
+				CachedInputTokens int `json:"cached_input_tokens"`
+				OutputTokens      int `json:"output_tokens"`
+			} `json:"total_token_usage"`
+		} `json:"info"`
+	} `json:"payload"`
+}
+
+func (e *codexExtractor) Extract(workDir string) (*Usage, error) {
+	root, err := homeDir(e.dir, ".codex", "sessions")
+	if err != nil {
+		return nil, err
+	}
+	files, err := findTranscripts(root, true, func(name string) bool {
+		return strings.HasPrefix(name, "rollout-") && strings.HasSuffix(name, ".jsonl")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	want := filepath.Clean(workDir)
+	for i, f := range files {
+		if i == maxCodexCandidates {
+			break
+		}
+		u, cwd, err := parseCodexRollout(f.path)
+		if err != nil || filepath.Clean(cwd) != want {
+			continue
+		}
+		return u, nil
+	}
+	return nil, fmt.Errorf("no codex rollout for %s in %s", workDir, root)
+}
+
+// parseCodexRollout returns a rollout's usage and the directory it ran in.
+// Token counts in a rollout are running totals, so the last one wins.
+func parseCodexRollout(path string) (*Usage, string, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is a discovered transcript
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	u := &Usage{}
+	var cwd string
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 256*1024)
+	scanner.Buffer(buf, 4*1024*1024)
+
+	for scanner.Scan() {
+		var line codexLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue // Skip malformed lines
+		}
+		p := line.Payload
+		switch line.Type {
+		case "session_meta":
+			cwd = p.CWD
+		case "turn_context":
+			if cwd == "" {
+				cwd = p.CWD
+			}
+			if p.Model != "" {
+				u.Model = p.Model
+			}
+		case "event_msg":
+			if p.Type != "token_count" || p.Info == nil || p.Info.Total == nil {
+				continue
+			}
+			// input_tokens includes the cached portion
+			t := p.Info.Total
+			u.InputTokens = t.InputTokens - t.CachedInputTokens
+			u.CacheReadTokens = t.CachedInputTokens
+			u.OutputTokens = t.OutputTokens
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, "", err
+	}
+	return u, cwd, nil
+}
@@ -0,0 +1,77 @@
+package usage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// geminiExtractor reads Gemini CLI chat logs, stored per project in
+// ~/.gemini/tmp/<sha256 of project dir>/chats/session-*.json.
+type geminiExtractor struct {
+	dir string
+}
+
+// geminiChat is a Gemini CLI chat log.
+type geminiChat struct {
+	Messages []struct {
+		Type   string `json:"type"`
+		Model  string `json:"model"`
+		Tokens *struct {
+			Input    int `json:"input"`
+			Output   int `json:"output"`
+			Cached   int `json:"cached"`
+			Thoughts int `json:"thoughts"`
+		} `json:"tokens"`
+	} `json:"messages"`
+}
+
+func (e *geminiExtractor) Extract(workDir string) (*Usage, error) {
+	root, err := homeDir(e.dir, ".gemini", "tmp")
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(workDir))
+	chatDir := filepath.Join(root, hex.EncodeToString(sum[:]), "chats")
+	files, err := findTranscripts(chatDir, false, func(name string) bool {
+		return strings.HasPrefix(name, "session-") && strings.HasSuffix(name, ".json")
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no chat logs found in %s", chatDir)
+	}
+	return parseGeminiChat(files[0].path)
+}
+
+// parseGeminiChat sums token usage from a chat log's model messages.
+func parseGeminiChat(path string) (*Usage, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a discovered transcript
+	if err != nil {
+		return nil, err
+	}
+	var chat geminiChat
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	u := &Usage{}
+	for _, m := range chat.Messages {
+		if m.Type != "gemini" || m.Tokens == nil {
+			continue
+		}
+		if u.Model == "" {
+			u.Model = m.Model
+		}
+		// input includes the cached portion; thoughts bill as output
+		u.InputTokens += m.Tokens.Input - m.Tokens.Cached
+		u.CacheReadTokens += m.Tokens.Cached
+		u.OutputTokens += m.Tokens.Output + m.Tokens.Thoughts
+	}
+	return u, nil
+}
@@ -0,0 +1,98 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// opencodeExtractor reads OpenCode's storage in ~/.local/share/opencode/storage:
+// session/<project>/<session>.json records the directory a session ran in,
+// and message/<session>/*.json holds its messages with token counts.
+type opencodeExtractor struct {
+	dir string
+}
+
+// opencodeSession is an OpenCode session record.
+type opencodeSession struct {
+	ID        string `json:"id"`
+	Directory string `json:"directory"`
+}
+
+// opencodeMessage is an OpenCode message record.
+type opencodeMessage struct {
+	Role    string  `json:"role"`
+	ModelID string  `json:"modelID"`
+	Cost    float64 `json:"cost"`
+	Tokens  *struct {
+		Input     int `json:"input"`
+		Output    int `json:"output"`
+		Reasoning int `json:"reasoning"`
+		Cache     struct {
+			Read  int `json:"read"`
+			Write int `json:"write"`
+		} `json:"cache"`
+	} `json:"tokens"`
+}
+
+func (e *opencodeExtractor) Extract(workDir string) (*Usage, error) {
+	root, err := homeDir(e.dir, ".local", "share", "opencode", "storage")
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := findTranscripts(filepath.Join(root, "session"), true, func(name string) bool {
+		return strings.HasSuffix(name, ".json")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	want := filepath.Clean(workDir)
+	for _, f := range sessions {
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			continue
+		}
+		var s opencodeSession
+		if err := json.Unmarshal(data, &s); err != nil || s.ID == "" || filepath.Clean(s.Directory) != want {
+			continue
+		}
+		return parseOpencodeMessages(filepath.Join(root, "message", s.ID))
+	}
+	return nil, fmt.Errorf("no opencode session for %s in %s", workDir, root)
+}
+
+// parseOpencodeMessages sums token usage and reported cost over a session's
+// assistant messages.
+func parseOpencodeMessages(dir string) (*Usage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &Usage{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var m opencodeMessage
+		if err := json.Unmarshal(data, &m); err != nil || m.Role != "assistant" || m.Tokens == nil {
+			continue
+		}
+		if u.Model == "" {
+			u.Model = m.ModelID
+		}
+		u.InputTokens += m.Tokens.Input
+		u.CacheReadTokens += m.Tokens.Cache.Read
+		u.CacheWriteTokens += m.Tokens.Cache.Write
+		u.OutputTokens += m.Tokens.Output + m.Tokens.Reasoning
+		u.CostUSD += m.Cost
+	}
+	return u, nil
+}
@@ -0,0 +1,99 @@
+package usage
+
+import (
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// defaultPricing is the built-in price table in USD per million tokens.
+// Town settings can override or extend it (see config.TownSettings.Pricing).
+// Keys are exact model names or prefixes; the longest match wins.
+var defaultPricing = map[string]config.ModelPricing{
+	// Anthropic (https://www.anthropic.com/pricing)
+	"claude-opus-4-5-20251101":  {Input: 15.0, Output: 75.0, CacheRead: 1.5, CacheWrite: 18.75},
+	"claude-opus-4":             {Input: 15.0, Output: 75.0, CacheRead: 1.5, CacheWrite: 18.75},
+	"claude-sonnet-4-20250514":  {Input: 3.0, Output: 15.0, CacheRead: 0.3, CacheWrite: 3.75},
+	"claude-sonnet-4":           {Input: 3.0, Output: 15.0, CacheRead: 0.3, CacheWrite: 3.75},
+	"claude-3-5-haiku-20241022": {Input: 1.0, Output: 5.0, CacheRead: 0.1, CacheWrite: 1.25},
+	"claude-haiku-4":            {Input: 1.0, Output: 5.0, CacheRead: 0.1, CacheWrite: 1.25},
+
+	// OpenAI (https://openai.com/api/pricing)
+	"gpt-5":      {Input: 1.25, Output: 10.0, CacheRead: 0.125},
+	"gpt-5-mini": {Input: 0.25, Output: 2.0, CacheRead: 0.025},
+
+	// Google (https://ai.google.dev/pricing)
+	"gemini-2.5-pro":   {Input: 1.25, Output: 10.0, CacheRead: 0.31},
+	"gemini-2.5-flash": {Input: 0.30, Output: 2.5, CacheRead: 0.075},
+
+	// Fallback for unknown models (Sonnet pricing)
+	"default": {Input: 3.0, Output: 15.0, CacheRead: 0.3, CacheWrite: 3.75},
+}
+
+// Pricing prices token usage.
+type Pricing struct {
+	table map[string]config.ModelPricing
+}
+
+// NewPricing returns the built-in prices with town overrides applied.
+func NewPricing(overrides map[string]*config.ModelPricing) *Pricing {
+	table := make(map[string]config.ModelPricing, len(defaultPricing)+len(overrides))
+	for model, p := range defaultPricing {
+		table[model] = p
+	}
+	for model, p := range overrides {
+		if p != nil {
+			table[model] = *p
+		}
+	}
+	return &Pricing{table: table}
+}
+
+// LoadPricing returns the prices for a town, falling back to the built-in
+// table if town settings can't be read.
+func LoadPricing(townRoot string) *Pricing {
+	if townRoot == "" {
+		return NewPricing(nil)
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return NewPricing(nil)
+	}
+	return NewPricing(settings.Pricing)
+}
+
+// Lookup returns the price for a model: an exact entry, else the longest
+// matching prefix, else "default". ok is false when "default" was used.
+func (p *Pricing) Lookup(model string) (price config.ModelPricing, ok bool) {
+	if price, ok := p.table[model]; ok && model != "default" {
+		return price, true
+	}
+	best := ""
+	for key := range p.table {
+		if key != "default" && strings.HasPrefix(model, key) && len(key) > len(best) {
+			best = key
+		}
+	}
+	if best != "" {
+		return p.table[best], true
+	}
+	return p.table["default"], false
+}
+
+// Cost returns the USD cost of u. When the model has no configured price
+// but the runtime reported a cost, the reported cost is used.
+func (p *Pricing) Cost(u *Usage) float64 {
+	if u == nil {
+		return 0.0
+	}
+	price, ok := p.Lookup(u.Model)
+	if !ok && u.CostUSD > 0 {
+		return u.CostUSD
+	}
+
+	// Prices are per million tokens
+	return float64(u.InputTokens)/1_000_000*price.Input +
+		float64(u.CacheReadTokens)/1_000_000*price.CacheRead +
+		float64(u.CacheWriteTokens)/1_000_000*price.CacheWrite +
+		float64(u.OutputTokens)/1_000_000*price.Output
+}
@@ -0,0 +1,146 @@
+// Package usage reads token usage from agent session transcripts and prices
+// it, so gt costs can account for every runtime in a mixed town.
+//
+// Each agent preset names a transcript format in its Usage source (see
+// config.UsageSource); this package maps the format to an Extractor that
+// knows where that runtime keeps its transcripts and how to parse them.
+package usage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrNotTracked is returned for agents whose runtime keeps no usage record.
+var ErrNotTracked = errors.New("agent does not record token usage")
+
+// Usage is the token usage of one agent session.
+type Usage struct {
+	Model            string
+	InputTokens      int // Uncached input
+	CacheWriteTokens int
+	CacheReadTokens  int
+	OutputTokens     int // Includes reasoning/thinking tokens
+
+	// CostUSD is the cost the runtime itself reported, if any. It is used
+	// when the model has no configured price.
+	CostUSD float64
+}
+
+// Extractor reads the usage of the most recent session an agent ran in a
+// working directory.
+type Extractor interface {
+	Extract(workDir string) (*Usage, error)
+}
+
+// formats maps a UsageSource format to its extractor, given the transcript
+// directory (already expanded; empty means the format's default).
+var formats = map[string]func(dir string) Extractor{
+	"claude":   func(dir string) Extractor { return &claudeExtractor{dir: dir} },
+	"codex":    func(dir string) Extractor { return &codexExtractor{dir: dir} },
+	"gemini":   func(dir string) Extractor { return &geminiExtractor{dir: dir} },
+	"opencode": func(dir string) Extractor { return &opencodeExtractor{dir: dir} },
+}
+
+// Formats returns the supported transcript formats.
+func Formats() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// For returns the extractor for a usage source. A nil source yields
+// ErrNotTracked.
+func For(src *config.UsageSource) (Extractor, error) {
+	if src == nil || src.Format == "" {
+		return nil, ErrNotTracked
+	}
+	newExtractor, ok := formats[src.Format]
+	if !ok {
+		return nil, fmt.Errorf("unknown usage format %q (supported: %s)", src.Format, strings.Join(Formats(), ", "))
+	}
+	return newExtractor(expandHome(src.Dir)), nil
+}
+
+// ForAgent returns the extractor for an agent preset or custom agent name.
+func ForAgent(name string) (Extractor, error) {
+	info := config.GetAgentPresetByName(name)
+	if info == nil {
+		return nil, fmt.Errorf("unknown agent %q", name)
+	}
+	return For(info.Usage)
+}
+
+// expandHome expands a leading "~" to the home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// homeDir returns dir, or the default below the home directory when dir is empty.
+func homeDir(dir string, defaultParts ...string) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(append([]string{home}, defaultParts...)...), nil
+}
+
+// transcriptFile is a candidate transcript found on disk.
+type transcriptFile struct {
+	path    string
+	modTime time.Time
+}
+
+// findTranscripts returns the files under root whose names satisfy match,
+// newest first. With recurse false only root itself is listed.
+func findTranscripts(root string, recurse bool, match func(name string) bool) ([]transcriptFile, error) {
+	var files []transcriptFile
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && !recurse {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !match(d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil // Skip files we can't stat
+		}
+		files = append(files, transcriptFile{path: path, modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+	return files, nil
+}
@@ -0,0 +1,179 @@
+package usage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeFile(t *testing.T, path, content string, age time.Duration) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func extract(t *testing.T, format, dir, workDir string) *Usage {
+	t.Helper()
+	ex, err := For(&config.UsageSource{Format: format, Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := ex.Extract(workDir)
+	if err != nil {
+		t.Fatalf("%s Extract: %v", format, err)
+	}
+	return u
+}
+
+func TestClaudeExtractor(t *testing.T) {
+	root := t.TempDir()
+	workDir := "/gt/gastown/polecats/toast"
+	project := filepath.Join(root, "-gt-gastown-polecats-toast")
+
+	writeFile(t, filepath.Join(project, "old.jsonl"),
+		`{"type":"assistant","message":{"model":"claude-opus-4-5-20251101","usage":{"input_tokens":999}}}`, time.Hour)
+	writeFile(t, filepath.Join(project, "new.jsonl"), strings.Join([]string{
+		`{"type":"user","message":{"role":"user"}}`,
+		`{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"cache_creation_input_tokens":10,"cache_read_input_tokens":1000,"output_tokens":50}}}`,
+		`not json`,
+		`{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":20,"output_tokens":5}}}`,
+	}, "\n"), 0)
+
+	got := extract(t, "claude", root, workDir)
+	want := Usage{Model: "claude-sonnet-4-20250514", InputTokens: 120, CacheWriteTokens: 10, CacheReadTokens: 1000, OutputTokens: 55}
+	if *got != want {
+		t.Errorf("usage = %+v, want %+v", *got, want)
+	}
+}
+
+func TestCodexExtractor(t *testing.T) {
+	root := t.TempDir()
+	workDir := "/gt/gastown/polecats/toast"
+
+	rollout := func(cwd string, tokens ...string) string {
+		lines := []string{
+			`{"type":"session_meta","payload":{"id":"s1","cwd":"` + cwd + `"}}`,
+			`{"type":"turn_context","payload":{"cwd":"` + cwd + `","model":"gpt-5-codex"}}`,
+		}
+		for _, tok := range tokens {
+			lines = append(lines, `{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":`+tok+`}}}`)
+		}
+		return strings.Join(lines, "\n")
+	}
+	day := filepath.Join(root, "2026", "03", "10")
+	writeFile(t, filepath.Join(day, "rollout-a.jsonl"),
+		rollout(workDir,
+			`{"input_tokens":1000,"cached_input_tokens":600,"output_tokens":100}`,
+			`{"input_tokens":3000,"cached_input_tokens":2000,"output_tokens":400}`), time.Hour)
+	// Newer, but a different directory
+	writeFile(t, filepath.Join(day, "rollout-b.jsonl"),
+		rollout("/gt/beads/crew/joe", `{"input_tokens":5,"output_tokens":5}`), 0)
+
+	got := extract(t, "codex", root, workDir)
+	want := Usage{Model: "gpt-5-codex", InputTokens: 1000, CacheReadTokens: 2000, OutputTokens: 400}
+	if *got != want {
+		t.Errorf("usage = %+v, want %+v", *got, want)
+	}
+}
+
+func TestGeminiExtractor(t *testing.T) {
+	root := t.TempDir()
+	workDir := "/gt/gastown/crew/joe"
+	sum := sha256.Sum256([]byte(workDir))
+	chats := filepath.Join(root, hex.EncodeToString(sum[:]), "chats")
+
+	writeFile(t, filepath.Join(chats, "session-1.json"), `{"messages":[
+		{"type":"user"},
+		{"type":"gemini","model":"gemini-2.5-pro","tokens":{"input":500,"output":40,"cached":200,"thoughts":60}},
+		{"type":"gemini","model":"gemini-2.5-pro","tokens":{"input":300,"output":10}}
+	]}`, 0)
+
+	got := extract(t, "gemini", root, workDir)
+	want := Usage{Model: "gemini-2.5-pro", InputTokens: 600, CacheReadTokens: 200, OutputTokens: 110}
+	if *got != want {
+		t.Errorf("usage = %+v, want %+v", *got, want)
+	}
+}
+
+func TestOpencodeExtractor(t *testing.T) {
+	root := t.TempDir()
+	workDir := "/gt/gastown/polecats/nux"
+
+	writeFile(t, filepath.Join(root, "session", "proj1", "ses_a.json"), `{"id":"ses_a","directory":"`+workDir+`"}`, 0)
+	writeFile(t, filepath.Join(root, "session", "proj1", "ses_b.json"), `{"id":"ses_b","directory":"/elsewhere"}`, 0)
+	writeFile(t, filepath.Join(root, "message", "ses_a", "msg_1.json"),
+		`{"role":"assistant","modelID":"kimi-k2","cost":0.02,"tokens":{"input":100,"output":20,"reasoning":5,"cache":{"read":50,"write":7}}}`, 0)
+	writeFile(t, filepath.Join(root, "message", "ses_a", "msg_2.json"), `{"role":"user"}`, 0)
+
+	got := extract(t, "opencode", root, workDir)
+	want := Usage{Model: "kimi-k2", InputTokens: 100, CacheReadTokens: 50, CacheWriteTokens: 7, OutputTokens: 25, CostUSD: 0.02}
+	if *got != want {
+		t.Errorf("usage = %+v, want %+v", *got, want)
+	}
+}
+
+func TestFor(t *testing.T) {
+	if _, err := For(nil); !errors.Is(err, ErrNotTracked) {
+		t.Errorf("For(nil) err = %v, want ErrNotTracked", err)
+	}
+	if _, err := For(&config.UsageSource{Format: "punchcards"}); err == nil {
+		t.Error("For(unknown format) succeeded")
+	}
+	if _, err := ForAgent("cursor"); !errors.Is(err, ErrNotTracked) {
+		t.Errorf("ForAgent(cursor) err = %v, want ErrNotTracked", err)
+	}
+	for _, agent := range []string{"claude", "codex", "gemini", "opencode"} {
+		if _, err := ForAgent(agent); err != nil {
+			t.Errorf("ForAgent(%s): %v", agent, err)
+		}
+	}
+}
+
+func TestPricing(t *testing.T) {
+	p := NewPricing(map[string]*config.ModelPricing{
+		"gpt-5":   {Input: 2, Output: 20, CacheRead: 0.2},
+		"kimi-k2": {Input: 1, Output: 3},
+	})
+
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+	// Prefix match uses the override
+	u := &Usage{Model: "gpt-5-codex", InputTokens: 1_000_000, CacheReadTokens: 1_000_000, OutputTokens: 100_000}
+	if got := p.Cost(u); !near(got, 2+0.2+2) {
+		t.Errorf("gpt-5-codex cost = %v, want 4.2", got)
+	}
+
+	// Longest prefix wins over a shorter one
+	if price, ok := p.Lookup("gpt-5-mini-2025"); !ok || price.Input != 0.25 {
+		t.Errorf("Lookup(gpt-5-mini-2025) = %+v, %v", price, ok)
+	}
+
+	// Priced model ignores the runtime's reported cost
+	if got := p.Cost(&Usage{Model: "kimi-k2", InputTokens: 1_000_000, CostUSD: 9}); !near(got, 1) {
+		t.Errorf("kimi-k2 cost = %v, want 1", got)
+	}
+
+	// Unpriced model with a reported cost uses it; without, default pricing
+	if got := p.Cost(&Usage{Model: "mystery", InputTokens: 1_000_000, CostUSD: 0.5}); !near(got, 0.5) {
+		t.Errorf("reported cost = %v, want 0.5", got)
+	}
+	if got := p.Cost(&Usage{Model: "mystery", InputTokens: 1_000_000}); !near(got, 3) {
+		t.Errorf("default cost = %v, want 3", got)
+	}
+}
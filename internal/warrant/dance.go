@@ -0,0 +1,248 @@
+package warrant
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Phase is a step of the shutdown dance:
+//
+//	filed → interrogating → evaluating → pardoned
+//	             ↑______________|      → executing → executed
+type Phase string
+
+// Shutdown dance phases.
+const (
+	PhaseFiled         Phase = "filed"         // Waiting for the daemon to start the dance
+	PhaseInterrogating Phase = "interrogating" // Health check sent, waiting for a reply
+	PhaseEvaluating    Phase = "evaluating"    // Timeout reached, checking the reply
+	PhasePardoned      Phase = "pardoned"      // Agent replied; warrant cancelled
+	PhaseExecuting     Phase = "executing"     // Killing the session
+	PhaseExecuted      Phase = "executed"      // Session killed; warrant complete
+)
+
+// Timeouts are how long each interrogation waits for a reply. After the
+// last one goes unanswered the warrant is executed.
+var Timeouts = []time.Duration{60 * time.Second, 120 * time.Second, 240 * time.Second}
+
+// ReplyKeyword is what an interrogated agent must print to be pardoned.
+const ReplyKeyword = "ALIVE"
+
+// healthCheckTag starts every interrogation message; pane lines carrying
+// it are our own message, not a reply. The keyword comes early in the
+// message so a narrow pane doesn't wrap it onto an untagged line.
+const healthCheckTag = "[DOG] HEALTH CHECK"
+
+// captureLines is how much of the pane is scanned for a reply.
+const captureLines = 50
+
+// Dance is the persisted state of a warrant's shutdown dance.
+type Dance struct {
+	Phase     Phase     `json:"phase"`
+	Session   string    `json:"session,omitempty"`
+	Attempt   int       `json:"attempt,omitempty"`    // Current interrogation, from 1
+	AskedAt   time.Time `json:"asked_at,omitempty"`   // When the current health check was sent
+	Deadline  time.Time `json:"deadline,omitempty"`   // When the current interrogation times out
+	Outcome   string    `json:"outcome,omitempty"`    // Why the warrant was pardoned or executed
+	LastError string    `json:"last_error,omitempty"` // Last tmux failure; the step is retried
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Sessions is the session surface the dance uses (implemented by *tmux.Tmux;
+// the daemon adapts other session backends).
+type Sessions interface {
+	HasSession(name string) (bool, error)
+	NudgeSession(session, message string) error
+	CapturePane(session string, lines int) (string, error)
+	KillSessionWithProcesses(name string) error
+}
+
+// Dancer runs shutdown dances.
+type Dancer struct {
+	TownRoot string
+	Sessions Sessions
+	Now      func() time.Time // Defaults to time.Now
+}
+
+func (d *Dancer) now() time.Time {
+	if d.Now != nil {
+		return d.Now()
+	}
+	return time.Now()
+}
+
+// Tick advances every open warrant in the town as far as it can go now,
+// saving each phase change before the next so an interrupted dance resumes
+// where it stopped. Each warrant has its own deadlines, so any number of
+// dances run at once. Failures are per warrant and don't stop the others;
+// the last one is returned.
+func (d *Dancer) Tick() error {
+	dir := Dir(d.TownRoot)
+	warrants, err := List(dir)
+	if err != nil {
+		return err
+	}
+	var lastErr error
+	for _, w := range warrants {
+		path := Path(dir, w.Target)
+		for !w.Closed() {
+			changed, err := d.Advance(w)
+			if changed {
+				if serr := Save(path, w); serr != nil {
+					lastErr = serr
+					break
+				}
+			}
+			if err != nil {
+				lastErr = fmt.Errorf("warrant for %s: %w", w.Target, err)
+				break
+			}
+			if !changed {
+				break
+			}
+		}
+	}
+	return lastErr
+}
+
+// Advance makes at most one phase transition and reports whether the
+// warrant changed. It returns an error (after recording it in the dance)
+// when tmux fails; the transition is retried on the next call.
+func (d *Dancer) Advance(w *Warrant) (bool, error) {
+	if w.Closed() {
+		return false, nil
+	}
+	now := d.now()
+	if w.Dance == nil {
+		session, err := SessionName(d.TownRoot, w.Target)
+		if err != nil {
+			return false, err
+		}
+		w.Dance = &Dance{Phase: PhaseFiled, Session: session, UpdatedAt: now}
+	}
+	dn := w.Dance
+
+	switch dn.Phase {
+	case PhaseFiled:
+		alive, err := d.Sessions.HasSession(dn.Session)
+		if err != nil {
+			return d.failed(dn, now, err)
+		}
+		if !alive {
+			return d.transition(dn, now, PhaseExecuting, "session already dead"), nil
+		}
+		return d.interrogate(w, now, 1)
+
+	case PhaseInterrogating:
+		// A reply ends the interrogation early
+		if d.replied(dn) {
+			return d.pardon(dn, now), nil
+		}
+		if now.Before(dn.Deadline) {
+			return false, nil
+		}
+		return d.transition(dn, now, PhaseEvaluating, ""), nil
+
+	case PhaseEvaluating:
+		alive, err := d.Sessions.HasSession(dn.Session)
+		if err != nil {
+			return d.failed(dn, now, err)
+		}
+		switch {
+		case !alive:
+			return d.transition(dn, now, PhaseExecuting, "session died during interrogation"), nil
+		case d.replied(dn):
+			return d.pardon(dn, now), nil
+		case dn.Attempt < len(Timeouts):
+			return d.interrogate(w, now, dn.Attempt+1)
+		default:
+			outcome := fmt.Sprintf("no %s reply to %d health checks", ReplyKeyword, dn.Attempt)
+			return d.transition(dn, now, PhaseExecuting, outcome), nil
+		}
+
+	case PhaseExecuting:
+		if alive, err := d.Sessions.HasSession(dn.Session); err != nil {
+			return d.failed(dn, now, err)
+		} else if alive {
+			if err := d.Sessions.KillSessionWithProcesses(dn.Session); err != nil {
+				return d.failed(dn, now, err)
+			}
+			_ = events.LogFeed(events.TypeSessionDeath, "daemon",
+				events.SessionDeathPayload(dn.Session, w.Target, "death warrant: "+w.Reason, "shutdown dance"))
+		}
+		w.Executed = true
+		w.ExecutedAt = &now
+		return d.transition(dn, now, PhaseExecuted, dn.Outcome), nil
+	}
+	return false, nil
+}
+
+// interrogate sends health check attempt to the session.
+func (d *Dancer) interrogate(w *Warrant, now time.Time, attempt int) (bool, error) {
+	dn := w.Dance
+	timeout := Timeouts[attempt-1]
+	if err := d.Sessions.NudgeSession(dn.Session, HealthCheckMessage(w, attempt, timeout)); err != nil {
+		return d.failed(dn, now, err)
+	}
+	dn.Attempt = attempt
+	dn.AskedAt = now
+	dn.Deadline = now.Add(timeout)
+	return d.transition(dn, now, PhaseInterrogating, ""), nil
+}
+
+func (d *Dancer) pardon(dn *Dance, now time.Time) bool {
+	return d.transition(dn, now, PhasePardoned, fmt.Sprintf("replied %s to health check %d", ReplyKeyword, dn.Attempt))
+}
+
+func (d *Dancer) transition(dn *Dance, now time.Time, phase Phase, outcome string) bool {
+	dn.Phase = phase
+	if outcome != "" {
+		dn.Outcome = outcome
+	}
+	dn.LastError = ""
+	dn.UpdatedAt = now
+	return true
+}
+
+func (d *Dancer) failed(dn *Dance, now time.Time, err error) (bool, error) {
+	dn.LastError = err.Error()
+	dn.UpdatedAt = now
+	return true, err
+}
+
+// replied reports whether the pane shows a reply to the latest health check.
+func (d *Dancer) replied(dn *Dance) bool {
+	out, err := d.Sessions.CapturePane(dn.Session, captureLines)
+	if err != nil {
+		return false
+	}
+	return HasReply(out)
+}
+
+// HasReply reports whether captured pane output contains the reply keyword
+// after the last health-check message. If the message has scrolled out of
+// the capture, the whole capture is searched.
+func HasReply(pane string) bool {
+	lines := strings.Split(pane, "\n")
+	start := 0
+	for i, line := range lines {
+		if strings.Contains(line, healthCheckTag) {
+			start = i + 1
+		}
+	}
+	for _, line := range lines[start:] {
+		if strings.Contains(line, ReplyKeyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// HealthCheckMessage is the interrogation nudge for an attempt.
+func HealthCheckMessage(w *Warrant, attempt int, timeout time.Duration) string {
+	return fmt.Sprintf("%s: reply %s within %ds or this session will be terminated. Warrant: %s (filed by %s, attempt %d/%d)",
+		healthCheckTag, ReplyKeyword, int(timeout.Seconds()), w.Reason, w.FiledBy, attempt, len(Timeouts))
+}
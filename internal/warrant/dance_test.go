@@ -0,0 +1,195 @@
+package warrant
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSessions is an in-memory tmux: sessions with pane contents.
+type fakeSessions struct {
+	panes   map[string]string
+	killed  []string
+	nudges  []string
+	failing error
+}
+
+func (f *fakeSessions) HasSession(name string) (bool, error) {
+	if f.failing != nil {
+		return false, f.failing
+	}
+	_, ok := f.panes[name]
+	return ok, nil
+}
+
+func (f *fakeSessions) NudgeSession(session, message string) error {
+	if f.failing != nil {
+		return f.failing
+	}
+	f.nudges = append(f.nudges, message)
+	f.panes[session] += "> " + message + "\n"
+	return nil
+}
+
+func (f *fakeSessions) CapturePane(session string, lines int) (string, error) {
+	pane, ok := f.panes[session]
+	if !ok {
+		return "", errors.New("no such session")
+	}
+	return pane, nil
+}
+
+func (f *fakeSessions) KillSessionWithProcesses(name string) error {
+	delete(f.panes, name)
+	f.killed = append(f.killed, name)
+	return nil
+}
+
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func setup(t *testing.T, panes map[string]string) (*Dancer, *fakeSessions, *clock, string) {
+	t.Helper()
+	t.Chdir(t.TempDir()) // Keep event logging out of the repo
+	town := t.TempDir()
+	sessions := &fakeSessions{panes: panes}
+	c := &clock{t: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)}
+	d := &Dancer{TownRoot: town, Sessions: sessions, Now: c.now}
+	w := &Warrant{ID: "warrant-1", Target: "gastown/polecats/Toast", Reason: "stuck", FiledBy: "deacon", FiledAt: c.t}
+	path := Path(Dir(town), w.Target)
+	if err := Save(path, w); err != nil {
+		t.Fatal(err)
+	}
+	return d, sessions, c, path
+}
+
+func tick(t *testing.T, d *Dancer, path string) *Warrant {
+	t.Helper()
+	if err := d.Tick(); err != nil {
+		t.Fatalf("Tick: %v", err)
+	}
+	w, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestDanceExecutesUnresponsiveSession(t *testing.T) {
+	d, sessions, c, path := setup(t, map[string]string{"gt-gastown-Toast": "working...\n"})
+
+	w := tick(t, d, path)
+	if w.Phase() != PhaseInterrogating || w.Dance.Attempt != 1 || len(sessions.nudges) != 1 {
+		t.Fatalf("after first tick: phase %s attempt %d nudges %d", w.Phase(), w.Dance.Attempt, len(sessions.nudges))
+	}
+	if !strings.Contains(sessions.nudges[0], "reply ALIVE within 60s") {
+		t.Errorf("health check = %q", sessions.nudges[0])
+	}
+
+	// Nothing happens before the deadline
+	c.advance(30 * time.Second)
+	if w = tick(t, d, path); w.Phase() != PhaseInterrogating || len(sessions.nudges) != 1 {
+		t.Fatalf("before deadline: phase %s nudges %d", w.Phase(), len(sessions.nudges))
+	}
+
+	for attempt, wait := range []time.Duration{30 * time.Second, 120 * time.Second} {
+		c.advance(wait)
+		if w = tick(t, d, path); w.Dance.Attempt != attempt+2 {
+			t.Fatalf("attempt = %d, want %d", w.Dance.Attempt, attempt+2)
+		}
+	}
+	c.advance(240 * time.Second)
+	w = tick(t, d, path)
+	if w.Phase() != PhaseExecuted || !w.Executed || w.ExecutedAt == nil {
+		t.Fatalf("after last timeout: phase %s executed %v", w.Phase(), w.Executed)
+	}
+	if len(sessions.killed) != 1 || w.Dance.Outcome != "no ALIVE reply to 3 health checks" {
+		t.Errorf("killed %v, outcome %q", sessions.killed, w.Dance.Outcome)
+	}
+}
+
+func TestDancePardonsReply(t *testing.T) {
+	// An ALIVE from before the health check doesn't count
+	d, sessions, c, path := setup(t, map[string]string{"gt-gastown-Toast": "ALIVE (an old reply)\n"})
+	tick(t, d, path)
+	c.advance(10 * time.Second)
+	if w := tick(t, d, path); w.Phase() != PhaseInterrogating {
+		t.Fatalf("phase = %s, want interrogating", w.Phase())
+	}
+
+	sessions.panes["gt-gastown-Toast"] += "⏺ ALIVE\n"
+	c.advance(10 * time.Second)
+	w := tick(t, d, path)
+	if w.Phase() != PhasePardoned || !w.Closed() || w.Executed || len(sessions.killed) != 0 {
+		t.Fatalf("phase %s closed %v executed %v killed %v", w.Phase(), w.Closed(), w.Executed, sessions.killed)
+	}
+}
+
+func TestDanceDeadSession(t *testing.T) {
+	d, sessions, _, path := setup(t, map[string]string{})
+	w := tick(t, d, path)
+	if w.Phase() != PhaseExecuted || w.Dance.Outcome != "session already dead" || len(sessions.nudges) != 0 {
+		t.Errorf("phase %s outcome %q nudges %d", w.Phase(), w.Dance.Outcome, len(sessions.nudges))
+	}
+}
+
+func TestDanceResumesAfterRestart(t *testing.T) {
+	d, sessions, c, path := setup(t, map[string]string{"gt-gastown-Toast": ""})
+	tick(t, d, path)
+
+	// A new dancer (restarted daemon) continues from the saved phase
+	d2 := &Dancer{TownRoot: d.TownRoot, Sessions: sessions, Now: c.now}
+	c.advance(61 * time.Second)
+	w := tick(t, d2, path)
+	if w.Dance.Attempt != 2 || len(sessions.nudges) != 2 {
+		t.Errorf("attempt %d nudges %d, want 2, 2", w.Dance.Attempt, len(sessions.nudges))
+	}
+
+	// Interrupted mid-execution: the kill happens on resume
+	w.Dance.Phase = PhaseExecuting
+	if err := Save(path, w); err != nil {
+		t.Fatal(err)
+	}
+	if w = tick(t, d2, path); w.Phase() != PhaseExecuted || len(sessions.killed) != 1 {
+		t.Errorf("phase %s killed %v", w.Phase(), sessions.killed)
+	}
+}
+
+func TestDanceRecordsErrors(t *testing.T) {
+	d, sessions, _, path := setup(t, map[string]string{"gt-gastown-Toast": ""})
+	sessions.failing = errors.New("tmux: no server")
+	if err := d.Tick(); err == nil {
+		t.Fatal("Tick should report the tmux failure")
+	}
+	w, _ := Load(path)
+	if w.Phase() != PhaseFiled || w.Dance.LastError != "tmux: no server" {
+		t.Fatalf("phase %s last error %q", w.Phase(), w.Dance.LastError)
+	}
+
+	sessions.failing = nil
+	if w = tick(t, d, path); w.Phase() != PhaseInterrogating || w.Dance.LastError != "" {
+		t.Errorf("after recovery: phase %s last error %q", w.Phase(), w.Dance.LastError)
+	}
+}
+
+func TestHasReply(t *testing.T) {
+	msg := HealthCheckMessage(&Warrant{Reason: "stuck", FiledBy: "deacon"}, 1, time.Minute)
+	tests := []struct {
+		pane string
+		want bool
+	}{
+		{"> " + msg + "\n", false},
+		{"> " + msg + "\n⏺ ALIVE\n", true},
+		{"ALIVE\n> " + msg + "\nthinking...\n", false},
+		{"ALIVE, still compiling\n", true}, // Health check scrolled out of the capture
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := HasReply(tt.pane); got != tt.want {
+			t.Errorf("HasReply(%q) = %v, want %v", tt.pane, got, tt.want)
+		}
+	}
+}
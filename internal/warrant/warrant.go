@@ -0,0 +1,153 @@
+// Package warrant manages death warrants and the shutdown dance that
+// executes them.
+//
+// A warrant is filed (gt warrant file) against an agent that looks stuck or
+// abandoned. The daemon then runs the shutdown dance for each open warrant:
+// it interrogates the agent's session with a health-check nudge, waits for
+// an ALIVE reply in the pane, and either pardons the agent or kills the
+// session. The dance is deterministic and needs no LLM; its state lives in
+// the warrant file, so dances survive daemon restarts and many can run at
+// once with independent timers.
+package warrant
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Warrant is a death warrant for an agent.
+type Warrant struct {
+	ID         string     `json:"id"`
+	Target     string     `json:"target"` // e.g., "gastown/polecats/alpha", "deacon/dogs/bravo"
+	Reason     string     `json:"reason"`
+	FiledBy    string     `json:"filed_by"`
+	FiledAt    time.Time  `json:"filed_at"`
+	Executed   bool       `json:"executed,omitempty"`
+	ExecutedAt *time.Time `json:"executed_at,omitempty"`
+	Dance      *Dance     `json:"dance,omitempty"` // Shutdown dance progress; nil until the daemon starts it
+}
+
+// Closed reports whether the warrant needs no further action: it was
+// executed or the agent was pardoned.
+func (w *Warrant) Closed() bool {
+	return w.Executed || (w.Dance != nil && w.Dance.Phase == PhasePardoned)
+}
+
+// Phase returns the warrant's dance phase, PhaseFiled if the dance has not
+// started (or PhaseExecuted for warrants executed by hand).
+func (w *Warrant) Phase() Phase {
+	if w.Dance != nil {
+		return w.Dance.Phase
+	}
+	if w.Executed {
+		return PhaseExecuted
+	}
+	return PhaseFiled
+}
+
+// Dir returns the town's warrants directory.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, "warrants")
+}
+
+// Path returns the path of the warrant file for target in dir.
+func Path(dir, target string) string {
+	// Replace / with _ for filename safety
+	safe := strings.ReplaceAll(target, "/", "_")
+	return filepath.Join(dir, safe+".warrant.json")
+}
+
+// Load reads a warrant file.
+func Load(path string) (*Warrant, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is in the warrants directory
+	if err != nil {
+		return nil, err
+	}
+	var w Warrant
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("parsing warrant %s: %w", filepath.Base(path), err)
+	}
+	return &w, nil
+}
+
+// Save writes a warrant file atomically, so a reader never sees a partial
+// dance update.
+func Save(path string, w *Warrant) error {
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling warrant: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating warrants directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: warrants are non-sensitive
+		return fmt.Errorf("writing warrant: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("writing warrant: %w", err)
+	}
+	return nil
+}
+
+// List returns the warrants in dir, sorted by filing time. Unreadable
+// files are skipped; a missing directory has no warrants.
+func List(dir string) ([]*Warrant, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading warrants directory: %w", err)
+	}
+	var warrants []*Warrant
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".warrant.json") {
+			continue
+		}
+		w, err := Load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		warrants = append(warrants, w)
+	}
+	sort.Slice(warrants, func(i, j int) bool { return warrants[i].FiledAt.Before(warrants[j].FiledAt) })
+	return warrants, nil
+}
+
+// SessionName returns the tmux session of a warrant target. townRoot is
+// used for dog sessions, which are named after the town; it may be empty.
+func SessionName(townRoot, target string) (string, error) {
+	parts := strings.Split(target, "/")
+
+	// Handle different target formats
+	switch {
+	case len(parts) == 3 && parts[1] == "polecats":
+		// gastown/polecats/alpha -> gt-gastown-alpha
+		return fmt.Sprintf("gt-%s-%s", parts[0], parts[2]), nil
+	case len(parts) == 2 && parts[0] == "deacon" && parts[1] == "dogs":
+		// This shouldn't happen - need dog name
+		return "", fmt.Errorf("invalid target: need dog name (e.g., deacon/dogs/alpha)")
+	case len(parts) == 3 && parts[0] == "deacon" && parts[1] == "dogs":
+		// deacon/dogs/alpha -> gt-dog-alpha (or gt-<town>-deacon-alpha)
+		if townRoot == "" {
+			return fmt.Sprintf("gt-dog-%s", parts[2]), nil
+		}
+		townName, err := workspace.GetTownName(townRoot)
+		if err != nil {
+			return fmt.Sprintf("gt-dog-%s", parts[2]), nil
+		}
+		return fmt.Sprintf("gt-%s-deacon-%s", townName, parts[2]), nil
+	default:
+		// Fallback: just use the target with dashes
+		return "gt-" + strings.ReplaceAll(target, "/", "-"), nil
+	}
+}
@@ -0,0 +1,482 @@
+package web
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Role is the access level of an authenticated dashboard client.
+type Role string
+
+const (
+	// RoleAdmin can view everything and make changes (run commands, send mail).
+	RoleAdmin Role = "admin"
+	// RoleReadOnly can view the dashboard but not make POST requests.
+	RoleReadOnly Role = "read-only"
+)
+
+// CSRFHeader is the request header that must carry the CSRF token on
+// cookie-authenticated POSTs. The token is readable from the gt_csrf_<port>
+// cookie.
+const CSRFHeader = "X-CSRF-Token"
+
+// Cookie names get a "_<port>" suffix: browsers share localhost cookies
+// across ports, and the setup server and dashboards often run side by side.
+const (
+	sessionCookiePrefix = "gt_session_"
+	csrfCookiePrefix    = "gt_csrf_"
+	defaultSessionTTL   = 24 * time.Hour
+)
+
+// ErrNoToken is returned by NewAuth when the config has no admin token.
+var ErrNoToken = errors.New("web auth: no token configured")
+
+// Auth enforces token authentication, origin checks and CSRF protection
+// for the dashboard and setup servers.
+//
+// Browsers log in once at /login and get an HttpOnly session cookie; scripts
+// send "Authorization: Bearer <token>". Cookie-authenticated POSTs must also
+// pass an origin check and echo the CSRF token in the X-CSRF-Token header.
+type Auth struct {
+	token          string
+	readOnlyToken  string
+	key            []byte // HMAC key for session and CSRF tokens, derived from token
+	allowedOrigins map[string]bool
+	sessionTTL     time.Duration
+	now            func() time.Time
+}
+
+type roleContextKey struct{}
+
+// RoleFromContext returns the role of the authenticated client, if any.
+func RoleFromContext(ctx context.Context) (Role, bool) {
+	role, ok := ctx.Value(roleContextKey{}).(Role)
+	return role, ok
+}
+
+// NewAuth creates an Auth from dashboard auth settings.
+func NewAuth(cfg *config.WebAuthConfig) (*Auth, error) {
+	if cfg == nil || cfg.Token == "" {
+		return nil, ErrNoToken
+	}
+	if cfg.ReadOnlyToken == cfg.Token {
+		return nil, fmt.Errorf("web auth: read_only_token must differ from token")
+	}
+
+	key := sha256.Sum256([]byte("gt-dashboard-session:" + cfg.Token))
+	a := &Auth{
+		token:          cfg.Token,
+		readOnlyToken:  cfg.ReadOnlyToken,
+		key:            key[:],
+		allowedOrigins: make(map[string]bool),
+		sessionTTL:     config.ParseDurationOrDefault(cfg.SessionTTL, defaultSessionTTL),
+		now:            time.Now,
+	}
+	for _, origin := range cfg.AllowedOrigins {
+		a.allowedOrigins[strings.TrimRight(origin, "/")] = true
+	}
+	return a, nil
+}
+
+// GenerateToken returns a new random dashboard token.
+func GenerateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// LoadOrCreateAuthConfig returns the town's dashboard auth settings,
+// generating and saving an admin token on first use.
+func LoadOrCreateAuthConfig(townRoot string) (*config.WebAuthConfig, error) {
+	path := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.WebAuth != nil && settings.WebAuth.Token != "" {
+		return settings.WebAuth, nil
+	}
+
+	token, err := GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	if settings.WebAuth == nil {
+		settings.WebAuth = &config.WebAuthConfig{}
+	}
+	settings.WebAuth.Token = token
+	if err := config.SaveTownSettings(path, settings); err != nil {
+		return nil, fmt.Errorf("saving dashboard token: %w", err)
+	}
+	return settings.WebAuth, nil
+}
+
+// Wrap returns next protected by authentication.
+// /login and /logout are served by Auth itself; /static/ is public.
+func (a *Auth) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.handleCORS(w, r) {
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/login":
+			a.handleLogin(w, r)
+			return
+		case r.URL.Path == "/logout":
+			a.handleLogout(w, r)
+			return
+		case strings.HasPrefix(r.URL.Path, "/static/"):
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		role, viaCookie, nonce := a.authenticate(r)
+		if role == "" {
+			a.unauthorized(w, r)
+			return
+		}
+
+		if !isSafeMethod(r.Method) {
+			if role != RoleAdmin {
+				a.forbidden(w, r, "read-only access")
+				return
+			}
+			// Bearer tokens aren't sent automatically by browsers, so only
+			// cookie sessions need origin and CSRF checks.
+			if viaCookie {
+				if !a.sameOriginOrAllowed(r) {
+					a.forbidden(w, r, "cross-origin request rejected")
+					return
+				}
+				if !hmac.Equal([]byte(r.Header.Get(CSRFHeader)), []byte(a.csrfToken(nonce))) {
+					a.forbidden(w, r, "missing or invalid CSRF token")
+					return
+				}
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), roleContextKey{}, role)))
+	})
+}
+
+// handleCORS answers preflights and sets CORS headers for allowed origins.
+// Returns false if the request has been fully handled.
+func (a *Auth) handleCORS(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	allowed := origin != "" && a.allowedOrigins[origin]
+	if allowed {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		w.Header().Add("Vary", "Origin")
+	}
+
+	if r.Method != http.MethodOptions {
+		return true
+	}
+	if !allowed {
+		w.WriteHeader(http.StatusForbidden)
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+CSRFHeader)
+	w.WriteHeader(http.StatusNoContent)
+	return false
+}
+
+// authenticate returns the client's role, whether it came from a session
+// cookie, and the session nonce (for CSRF). Role is empty if unauthenticated.
+func (a *Auth) authenticate(r *http.Request) (role Role, viaCookie bool, nonce string) {
+	if h := r.Header.Get("Authorization"); h != "" {
+		if token, ok := strings.CutPrefix(h, "Bearer "); ok {
+			return a.roleForToken(strings.TrimSpace(token)), false, ""
+		}
+		return "", false, ""
+	}
+
+	c, err := r.Cookie(sessionCookiePrefix + cookiePort(r))
+	if err != nil {
+		return "", false, ""
+	}
+	role, nonce, ok := a.verifySession(c.Value)
+	if !ok {
+		return "", false, ""
+	}
+	return role, true, nonce
+}
+
+// roleForToken maps a presented token to a role using constant-time compares.
+func (a *Auth) roleForToken(token string) Role {
+	if token == "" {
+		return ""
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) == 1 {
+		return RoleAdmin
+	}
+	if a.readOnlyToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(a.readOnlyToken)) == 1 {
+		return RoleReadOnly
+	}
+	return ""
+}
+
+// newSession returns a signed session value "role.expiry.nonce.sig" and its nonce.
+func (a *Auth) newSession(role Role) (string, string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("generating session: %w", err)
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(b)
+	payload := fmt.Sprintf("%s.%d.%s", role, a.now().Add(a.sessionTTL).Unix(), nonce)
+	return payload + "." + a.sign("session|"+payload), nonce, nil
+}
+
+// verifySession checks a session value's signature and expiry.
+func (a *Auth) verifySession(value string) (Role, string, bool) {
+	i := strings.LastIndex(value, ".")
+	if i < 0 {
+		return "", "", false
+	}
+	payload, sig := value[:i], value[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(a.sign("session|"+payload))) {
+		return "", "", false
+	}
+
+	parts := strings.Split(payload, ".")
+	if len(parts) != 3 {
+		return "", "", false
+	}
+	role := Role(parts[0])
+	if role != RoleAdmin && role != RoleReadOnly {
+		return "", "", false
+	}
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || a.now().Unix() >= expiry {
+		return "", "", false
+	}
+	// A read-only session outlives removal of the read-only token otherwise
+	if role == RoleReadOnly && a.readOnlyToken == "" {
+		return "", "", false
+	}
+	return role, parts[2], true
+}
+
+func (a *Auth) csrfToken(nonce string) string {
+	return a.sign("csrf|" + nonce)
+}
+
+func (a *Auth) sign(msg string) string {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(msg))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// sameOriginOrAllowed checks Origin (or Referer, if Origin is absent)
+// against the request's host and the configured allowed origins.
+func (a *Auth) sameOriginOrAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		ref, err := url.Parse(r.Header.Get("Referer"))
+		if err != nil || ref.Host == "" {
+			return false
+		}
+		origin = ref.Scheme + "://" + ref.Host
+	}
+	if a.allowedOrigins[origin] {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host != "" && u.Host == r.Host
+}
+
+func (a *Auth) setSessionCookies(w http.ResponseWriter, r *http.Request, role Role) error {
+	session, nonce, err := a.newSession(role)
+	if err != nil {
+		return err
+	}
+	maxAge := int(a.sessionTTL.Seconds())
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookiePrefix + cookiePort(r),
+		Value:    session,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	// Readable by the dashboard's JavaScript, which echoes it in X-CSRF-Token
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookiePrefix + cookiePort(r),
+		Value:    a.csrfToken(nonce),
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return nil
+}
+
+func clearSessionCookies(w http.ResponseWriter, r *http.Request) {
+	for _, prefix := range []string{sessionCookiePrefix, csrfCookiePrefix} {
+		http.SetCookie(w, &http.Cookie{Name: prefix + cookiePort(r), Value: "", Path: "/", MaxAge: -1})
+	}
+}
+
+// cookiePort returns the port the client used to reach us, for cookie names.
+func cookiePort(r *http.Request) string {
+	if _, port, err := net.SplitHostPort(r.Host); err == nil && port != "" {
+		return port
+	}
+	if r.TLS != nil {
+		return "443"
+	}
+	return "80"
+}
+
+// handleLogin serves the login form and exchanges a token for a session.
+// GET /login?token=... logs in directly, so a printed URL can be opened
+// as-is; the token is then dropped from the address bar by the redirect.
+func (a *Auth) handleLogin(w http.ResponseWriter, r *http.Request) {
+	next := safeRedirect(r.URL.Query().Get("next"))
+
+	var token string
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		token = r.URL.Query().Get("token")
+		if token == "" {
+			renderLogin(w, http.StatusOK, next, "")
+			return
+		}
+	case http.MethodPost:
+		// Login CSRF: don't let other sites log the browser into our session
+		if !a.sameOriginOrAllowed(r) {
+			a.forbidden(w, r, "cross-origin request rejected")
+			return
+		}
+		token = r.PostFormValue("token")
+		if n := r.PostFormValue("next"); n != "" {
+			next = safeRedirect(n)
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	role := a.roleForToken(strings.TrimSpace(token))
+	if role == "" {
+		renderLogin(w, http.StatusUnauthorized, next, "Invalid token")
+		return
+	}
+	if err := a.setSessionCookies(w, r, role); err != nil {
+		http.Error(w, "Login failed", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, next, http.StatusSeeOther)
+}
+
+func (a *Auth) handleLogout(w http.ResponseWriter, r *http.Request) {
+	clearSessionCookies(w, r)
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// unauthorized sends API clients a 401 and browsers to the login page.
+func (a *Auth) unauthorized(w http.ResponseWriter, r *http.Request) {
+	if isAPIRequest(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="gt dashboard"`)
+		writeAuthError(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	target := "/login"
+	if r.URL.RequestURI() != "/" {
+		target += "?next=" + url.QueryEscape(r.URL.RequestURI())
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+func (a *Auth) forbidden(w http.ResponseWriter, r *http.Request, msg string) {
+	if isAPIRequest(r) {
+		writeAuthError(w, msg, http.StatusForbidden)
+		return
+	}
+	http.Error(w, "Forbidden: "+msg, http.StatusForbidden)
+}
+
+// writeAuthError sends a JSON error in the shape the dashboard API uses.
+func writeAuthError(w http.ResponseWriter, msg string, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "error": msg})
+}
+
+func isAPIRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, "/api/")
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// safeRedirect restricts post-login redirects to local paths.
+func safeRedirect(next string) string {
+	if next == "" || !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
+
+var loginTemplate = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Gas Town - Sign in</title>
+    <style>
+        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Helvetica, Arial, sans-serif;
+               background: #0d1117; color: #e6edf3; display: flex; align-items: center;
+               justify-content: center; min-height: 100vh; margin: 0; }
+        form { background: #161b22; border: 1px solid #30363d; border-radius: 8px; padding: 32px; width: 360px; }
+        h1 { font-size: 1.4rem; margin: 0 0 8px; }
+        p { color: #8b949e; font-size: 0.9rem; margin: 0 0 20px; }
+        code { color: #58a6ff; }
+        input[type=password] { width: 100%; box-sizing: border-box; padding: 10px; margin-bottom: 16px;
+               background: #0d1117; color: #e6edf3; border: 1px solid #30363d; border-radius: 6px; }
+        button { width: 100%; padding: 10px; background: #238636; color: #fff; border: 0; border-radius: 6px; cursor: pointer; }
+        .error { color: #f85149; margin-bottom: 16px; }
+    </style>
+</head>
+<body>
+    <form method="POST" action="/login">
+        <h1>Gas Town dashboard</h1>
+        <p>Open the login URL printed by <code>gt dashboard</code>, or enter the token from <code>gt dashboard token</code>.</p>
+        {{if .Error}}<div class="error">{{.Error}}</div>{{end}}
+        <input type="password" name="token" placeholder="Token" autofocus autocomplete="current-password">
+        <input type="hidden" name="next" value="{{.Next}}">
+        <button type="submit">Sign in</button>
+    </form>
+</body>
+</html>
+`))
+
+func renderLogin(w http.ResponseWriter, status int, next, errMsg string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_ = loginTemplate.Execute(w, struct{ Next, Error string }{next, errMsg})
+}
@@ -0,0 +1,326 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+const (
+	testAdminToken    = "admin-secret"
+	testReadOnlyToken = "viewer-secret"
+)
+
+func newTestAuth(t *testing.T) (*Auth, http.Handler) {
+	t.Helper()
+	auth, err := NewAuth(&config.WebAuthConfig{
+		Token:          testAdminToken,
+		ReadOnlyToken:  testReadOnlyToken,
+		AllowedOrigins: []string{"https://ops.example.com/"},
+	})
+	if err != nil {
+		t.Fatalf("NewAuth: %v", err)
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role, _ := RoleFromContext(r.Context())
+		_, _ = w.Write([]byte("ok:" + string(role)))
+	})
+	return auth, auth.Wrap(next)
+}
+
+func serve(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+// login posts the admin token to /login and returns the resulting cookies.
+func login(t *testing.T, h http.Handler, token string) []*http.Cookie {
+	t.Helper()
+	form := url.Values{"token": {token}}
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "http://localhost:8080")
+	w := serve(h, req)
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("POST /login status = %d, want 303; body: %s", w.Code, w.Body.String())
+	}
+	return w.Result().Cookies()
+}
+
+func cookieValue(cookies []*http.Cookie, name string) string {
+	for _, c := range cookies {
+		if c.Name == name {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+func TestNewAuth_RequiresToken(t *testing.T) {
+	if _, err := NewAuth(nil); err != ErrNoToken {
+		t.Errorf("NewAuth(nil) err = %v, want ErrNoToken", err)
+	}
+	if _, err := NewAuth(&config.WebAuthConfig{Token: "x", ReadOnlyToken: "x"}); err == nil {
+		t.Error("NewAuth with identical tokens succeeded, want error")
+	}
+}
+
+func TestAuth_Unauthenticated(t *testing.T) {
+	_, h := newTestAuth(t)
+
+	w := serve(h, httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/crew", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("API status = %d, want 401", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"success":false`) {
+		t.Errorf("API body = %q, want JSON error", w.Body.String())
+	}
+
+	w = serve(h, httptest.NewRequest(http.MethodGet, "http://localhost:8080/?expand=mail", nil))
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/login?next=%2F%3Fexpand%3Dmail" {
+		t.Errorf("page: status = %d, Location = %q, want redirect to login", w.Code, w.Header().Get("Location"))
+	}
+
+	w = serve(h, httptest.NewRequest(http.MethodGet, "http://localhost:8080/static/dashboard.css", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("static status = %d, want 200", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/crew", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	if w := serve(h, req); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong bearer status = %d, want 401", w.Code)
+	}
+}
+
+func TestAuth_BearerRoles(t *testing.T) {
+	_, h := newTestAuth(t)
+
+	for _, tt := range []struct {
+		token  string
+		method string
+		want   int
+		body   string
+	}{
+		{testAdminToken, http.MethodGet, http.StatusOK, "ok:admin"},
+		{testAdminToken, http.MethodPost, http.StatusOK, "ok:admin"},
+		{testReadOnlyToken, http.MethodGet, http.StatusOK, "ok:read-only"},
+		{testReadOnlyToken, http.MethodPost, http.StatusForbidden, "read-only"},
+	} {
+		// Bearer requests skip CSRF and origin checks: browsers never attach them implicitly
+		req := httptest.NewRequest(tt.method, "http://localhost:8080/api/run", strings.NewReader("{}"))
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		w := serve(h, req)
+		if w.Code != tt.want || !strings.Contains(w.Body.String(), tt.body) {
+			t.Errorf("%s %s: status = %d body = %q, want %d containing %q",
+				tt.token, tt.method, w.Code, w.Body.String(), tt.want, tt.body)
+		}
+	}
+}
+
+func TestAuth_CookieSessionAndCSRF(t *testing.T) {
+	_, h := newTestAuth(t)
+	cookies := login(t, h, testAdminToken)
+	csrf := cookieValue(cookies, "gt_csrf_8080")
+	if cookieValue(cookies, "gt_session_8080") == "" || csrf == "" {
+		t.Fatalf("login cookies = %v, want gt_session_8080 and gt_csrf_8080", cookies)
+	}
+
+	newReq := func(method, origin, token string) *http.Request {
+		req := httptest.NewRequest(method, "http://localhost:8080/api/mail/send", strings.NewReader("{}"))
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if token != "" {
+			req.Header.Set(CSRFHeader, token)
+		}
+		return req
+	}
+
+	if w := serve(h, newReq(http.MethodGet, "", "")); w.Code != http.StatusOK {
+		t.Errorf("GET with session: status = %d, want 200", w.Code)
+	}
+	if w := serve(h, newReq(http.MethodPost, "http://localhost:8080", csrf)); w.Code != http.StatusOK {
+		t.Errorf("same-origin POST with CSRF: status = %d, want 200", w.Code)
+	}
+	if w := serve(h, newReq(http.MethodPost, "http://localhost:8080", "")); w.Code != http.StatusForbidden {
+		t.Errorf("POST without CSRF: status = %d, want 403", w.Code)
+	}
+	if w := serve(h, newReq(http.MethodPost, "http://evil.example.com", csrf)); w.Code != http.StatusForbidden {
+		t.Errorf("cross-origin POST: status = %d, want 403", w.Code)
+	}
+	if w := serve(h, newReq(http.MethodPost, "", csrf)); w.Code != http.StatusForbidden {
+		t.Errorf("POST with no Origin or Referer: status = %d, want 403", w.Code)
+	}
+
+	referer := newReq(http.MethodPost, "", csrf)
+	referer.Header.Set("Referer", "http://localhost:8080/?expand=mail")
+	if w := serve(h, referer); w.Code != http.StatusOK {
+		t.Errorf("POST with same-origin Referer: status = %d, want 200", w.Code)
+	}
+
+	if w := serve(h, newReq(http.MethodPost, "https://ops.example.com", csrf)); w.Code != http.StatusOK {
+		t.Errorf("POST from allowed origin: status = %d, want 200", w.Code)
+	}
+}
+
+func TestAuth_ReadOnlySession(t *testing.T) {
+	_, h := newTestAuth(t)
+	cookies := login(t, h, testReadOnlyToken)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/run", strings.NewReader("{}"))
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	req.Header.Set("Origin", "http://localhost:8080")
+	req.Header.Set(CSRFHeader, cookieValue(cookies, "gt_csrf_8080"))
+	if w := serve(h, req); w.Code != http.StatusForbidden {
+		t.Errorf("read-only POST: status = %d, want 403", w.Code)
+	}
+}
+
+func TestAuth_SessionExpiryAndTampering(t *testing.T) {
+	auth, h := newTestAuth(t)
+	cookies := login(t, h, testAdminToken)
+	session := cookieValue(cookies, "gt_session_8080")
+
+	get := func(value string) int {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/crew", nil)
+		req.AddCookie(&http.Cookie{Name: "gt_session_8080", Value: value})
+		return serve(h, req).Code
+	}
+
+	if code := get(session); code != http.StatusOK {
+		t.Fatalf("fresh session status = %d, want 200", code)
+	}
+	if code := get(strings.Replace(session, "admin.", "read-only.", 1)); code != http.StatusUnauthorized {
+		t.Errorf("tampered session status = %d, want 401", code)
+	}
+
+	auth.now = func() time.Time { return time.Now().Add(25 * time.Hour) }
+	if code := get(session); code != http.StatusUnauthorized {
+		t.Errorf("expired session status = %d, want 401", code)
+	}
+
+	// Sessions are bound to the port they were issued on
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:9090/api/crew", nil)
+	req.AddCookie(&http.Cookie{Name: "gt_session_8080", Value: session})
+	if w := serve(h, req); w.Code != http.StatusUnauthorized {
+		t.Errorf("other port status = %d, want 401", w.Code)
+	}
+}
+
+func TestAuth_Login(t *testing.T) {
+	_, h := newTestAuth(t)
+
+	w := serve(h, httptest.NewRequest(http.MethodGet, "http://localhost:8080/login?token="+testAdminToken+"&next=/%3Fexpand%3Dmail", nil))
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/?expand=mail" {
+		t.Errorf("GET /login?token: status = %d Location = %q", w.Code, w.Header().Get("Location"))
+	}
+
+	w = serve(h, httptest.NewRequest(http.MethodGet, "http://localhost:8080/login?token=nope", nil))
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "Invalid token") {
+		t.Errorf("bad token: status = %d", w.Code)
+	}
+
+	form := url.Values{"token": {testAdminToken}}
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "http://evil.example.com")
+	if w := serve(h, req); w.Code != http.StatusForbidden {
+		t.Errorf("cross-origin login: status = %d, want 403", w.Code)
+	}
+}
+
+func TestAuth_Preflight(t *testing.T) {
+	_, h := newTestAuth(t)
+
+	req := httptest.NewRequest(http.MethodOptions, "http://localhost:8080/api/run", nil)
+	req.Header.Set("Origin", "http://evil.example.com")
+	w := serve(h, req)
+	if w.Code != http.StatusForbidden || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("unknown origin preflight: status = %d ACAO = %q", w.Code, w.Header().Get("Access-Control-Allow-Origin"))
+	}
+
+	req = httptest.NewRequest(http.MethodOptions, "http://localhost:8080/api/run", nil)
+	req.Header.Set("Origin", "https://ops.example.com")
+	w = serve(h, req)
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://ops.example.com" {
+		t.Errorf("allowed origin preflight: status = %d ACAO = %q", w.Code, w.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
+
+func TestSafeRedirect(t *testing.T) {
+	for in, want := range map[string]string{
+		"":                    "/",
+		"/?expand=mail":       "/?expand=mail",
+		"//evil.example.com":  "/",
+		"/\\evil.example.com": "/",
+		"https://evil.com":    "/",
+	} {
+		if got := safeRedirect(in); got != want {
+			t.Errorf("safeRedirect(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLoadOrCreateAuthConfig(t *testing.T) {
+	townRoot := t.TempDir()
+
+	cfg, err := LoadOrCreateAuthConfig(townRoot)
+	if err != nil {
+		t.Fatalf("LoadOrCreateAuthConfig: %v", err)
+	}
+	if len(cfg.Token) != 64 {
+		t.Errorf("Token = %q, want 64 hex chars", cfg.Token)
+	}
+
+	info, err := os.Stat(config.TownSettingsPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("settings mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	again, err := LoadOrCreateAuthConfig(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Token != cfg.Token {
+		t.Error("second call generated a new token, want the saved one")
+	}
+}
+
+func TestNewDashboardMux_RequiresAuth(t *testing.T) {
+	auth, err := NewAuth(&config.WebAuthConfig{Token: testAdminToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux, err := NewDashboardMux(&MockConvoyFetcher{}, nil, auth)
+	if err != nil {
+		t.Fatalf("NewDashboardMux: %v", err)
+	}
+	if w := serve(mux, httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/commands", nil)); w.Code != http.StatusUnauthorized {
+		t.Errorf("dashboard API without token: status = %d, want 401", w.Code)
+	}
+
+	setup, err := NewSetupMux(auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/install", strings.NewReader("{}"))
+	if w := serve(setup, req); w.Code != http.StatusUnauthorized {
+		t.Errorf("setup API without token: status = %d, want 401", w.Code)
+	}
+}
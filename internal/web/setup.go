@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -38,15 +39,7 @@ func NewSetupAPIHandler() *SetupAPIHandler {
 
 // ServeHTTP routes setup API requests.
 func (h *SetupAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
-
+	// CORS, authentication and CSRF checks are applied by Auth.Wrap.
 	path := strings.TrimPrefix(r.URL.Path, "/api")
 	switch {
 	case path == "/install" && r.Method == http.MethodPost:
@@ -236,6 +229,15 @@ func (h *SetupAPIHandler) handleLaunch(w http.ResponseWriter, r *http.Request) {
 	// Start new dashboard on a DIFFERENT port first, then we'll tell the browser to go there
 	newPort := port + 1
 
+	// Make sure the workspace has a dashboard token so the browser can be
+	// logged in to the new dashboard as part of the redirect. The caller is
+	// already authenticated to this setup server.
+	authCfg, err := LoadOrCreateAuthConfig(path)
+	if err != nil {
+		h.sendError(w, "Failed to set up dashboard token: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	// Start new dashboard process from the workspace directory
 	cmd := exec.Command("gt", "dashboard", "--port", fmt.Sprintf("%d", newPort))
 	cmd.Dir = path
@@ -269,7 +271,7 @@ func (h *SetupAPIHandler) handleLaunch(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
 		"success":  true,
 		"message":  fmt.Sprintf("Dashboard launching from %s", path),
-		"redirect": fmt.Sprintf("http://localhost:%d", newPort),
+		"redirect": fmt.Sprintf("http://localhost:%d/login?token=%s", newPort, url.QueryEscape(authCfg.Token)),
 	})
 }
 
@@ -388,7 +390,8 @@ func (h *SetupAPIHandler) sendJSON(w http.ResponseWriter, resp SetupResponse) {
 }
 
 // NewSetupMux creates the HTTP handler for setup mode.
-func NewSetupMux() (http.Handler, error) {
+// auth may be nil to disable authentication (tests only).
+func NewSetupMux(auth *Auth) (http.Handler, error) {
 	setupHandler := NewSetupHandler()
 	apiHandler := NewSetupAPIHandler()
 
@@ -396,6 +399,9 @@ func NewSetupMux() (http.Handler, error) {
 	mux.Handle("/api/", apiHandler)
 	mux.Handle("/", setupHandler)
 
+	if auth != nil {
+		return auth.Wrap(mux), nil
+	}
 	return mux, nil
 }
 
@@ -800,6 +806,27 @@ const setupHTML = `<!DOCTYPE html>
     <script>
         var workspacePath = '';
 
+        // Echo the CSRF cookie on POSTs (see web.Auth)
+        (function() {
+            var port = window.location.port || (window.location.protocol === 'https:' ? '443' : '80');
+            var name = 'gt_csrf_' + port + '=';
+            var nativeFetch = window.fetch.bind(window);
+            window.fetch = function(input, init) {
+                init = init || {};
+                if ((init.method || 'GET').toUpperCase() === 'POST') {
+                    var token = '';
+                    document.cookie.split(';').forEach(function(c) {
+                        c = c.trim();
+                        if (c.indexOf(name) === 0) token = decodeURIComponent(c.substring(name.length));
+                    });
+                    var headers = new Headers(init.headers || {});
+                    headers.set('X-CSRF-Token', token);
+                    init.headers = headers;
+                }
+                return nativeFetch(input, init);
+            };
+        })();
+
         function showMode(mode) {
             document.getElementById('tab-existing').className = mode === 'existing' ? 'mode-tab active' : 'mode-tab';
             document.getElementById('tab-create').className = mode === 'create' ? 'mode-tab active' : 'mode-tab';
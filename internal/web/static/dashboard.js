@@ -1,6 +1,39 @@
 (function() {
     'use strict';
 
+    // ============================================
+    // AUTH: CSRF header on POSTs, login redirect on 401
+    // ============================================
+    function csrfToken() {
+        var port = window.location.port || (window.location.protocol === 'https:' ? '443' : '80');
+        var name = 'gt_csrf_' + port + '=';
+        var cookies = document.cookie.split(';');
+        for (var i = 0; i < cookies.length; i++) {
+            var c = cookies[i].trim();
+            if (c.indexOf(name) === 0) {
+                return decodeURIComponent(c.substring(name.length));
+            }
+        }
+        return '';
+    }
+
+    var nativeFetch = window.fetch.bind(window);
+    window.fetch = function(input, init) {
+        init = init || {};
+        var method = (init.method || 'GET').toUpperCase();
+        if (method !== 'GET' && method !== 'HEAD') {
+            var headers = new Headers(init.headers || {});
+            headers.set('X-CSRF-Token', csrfToken());
+            init.headers = headers;
+        }
+        return nativeFetch(input, init).then(function(resp) {
+            if (resp.status === 401) {
+                window.location.href = '/login?next=' + encodeURIComponent(window.location.pathname + window.location.search);
+            }
+            return resp;
+        });
+    };
+
     // ============================================
     // SSE (Server-Sent Events) CONNECTION
     // ============================================
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// DefaultActionTimeout bounds each gt/bd subprocess run by ExecActions.
+const DefaultActionTimeout = 30 * time.Second
+
+// ExecActions performs webhook actions with the gt and bd CLIs, the same
+// way the daemon's other subprocess calls do.
+type ExecActions struct {
+	TownRoot string
+	GtPath   string // defaults to "gt"
+	BdPath   string // defaults to "bd"
+	Timeout  time.Duration
+}
+
+var _ Actions = (*ExecActions)(nil)
+
+// SendMail sends gt mail from the daemon.
+func (a *ExecActions) SendMail(to, subject, body string) error {
+	return a.run(a.gt(), "mail", "send", to, "-s", subject, "-m", body)
+}
+
+// CloseGate closes a gate and wakes the agents waiting on it.
+func (a *ExecActions) CloseGate(gateID, reason string) error {
+	args := []string{"gate", "close", gateID}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	if err := a.run(a.bd(), args...); err != nil {
+		return err
+	}
+	if err := a.run(a.gt(), "gate", "wake", gateID); err != nil {
+		return fmt.Errorf("gate %s closed but wake failed: %w", gateID, err)
+	}
+	return nil
+}
+
+// CreateIssue creates an issue in a registered rig and returns its ID.
+func (a *ExecActions) CreateIssue(rig string, opts beads.CreateOptions) (string, error) {
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(a.TownRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return "", fmt.Errorf("loading rigs: %w", err)
+	}
+	if _, ok := rigsConfig.Rigs[rig]; !ok {
+		return "", fmt.Errorf("unknown rig %q", rig)
+	}
+
+	var store beads.Store = beads.New(filepath.Join(a.TownRoot, rig))
+	issue, err := store.Create(opts)
+	if err != nil {
+		return "", err
+	}
+	return issue.ID, nil
+}
+
+// EmitEvent appends an event to the town's activity log.
+func (a *ExecActions) EmitEvent(eventType string, payload map[string]interface{}, visibility string) error {
+	return events.Log(eventType, "webhook", payload, visibility)
+}
+
+func (a *ExecActions) gt() string {
+	if a.GtPath != "" {
+		return a.GtPath
+	}
+	return "gt"
+}
+
+func (a *ExecActions) bd() string {
+	if a.BdPath != "" {
+		return a.BdPath
+	}
+	return "bd"
+}
+
+func (a *ExecActions) run(bin string, args ...string) error {
+	timeout := a.Timeout
+	if timeout <= 0 {
+		timeout = DefaultActionTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, bin, args...) //nolint:gosec // G204: binary is resolved internally; args are passed without a shell
+	cmd.Dir = a.TownRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s %s: %w: %s", filepath.Base(bin), args[0], err, msg)
+		}
+		return fmt.Errorf("%s %s: %w", filepath.Base(bin), args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,387 @@
+// Package webhook accepts signed HTTP callbacks from outside the town (CI
+// systems, code review, anything that can POST JSON) and turns them into
+// town actions: mail, gate closes, new issues and activity events.
+//
+// Requests are POSTed to /hooks/<path> and must carry an HMAC-SHA256
+// signature of the raw body in X-Gastown-Signature (or GitHub's
+// X-Hub-Signature-256), formatted as "sha256=<hex>". Routing rules live in
+// settings/webhooks.json; see config.WebhooksConfig.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Signature headers, checked in order.
+const (
+	SignatureHeader       = "X-Gastown-Signature"
+	GitHubSignatureHeader = "X-Hub-Signature-256"
+)
+
+// PathPrefix is the URL prefix webhook routes are served under.
+const PathPrefix = "/hooks/"
+
+// DefaultMaxBodyBytes caps request bodies when the config sets no limit.
+const DefaultMaxBodyBytes = 1 << 20
+
+// Actions performs the side effects requested by webhook routes.
+type Actions interface {
+	SendMail(to, subject, body string) error
+	CloseGate(gateID, reason string) error
+	CreateIssue(rig string, opts beads.CreateOptions) (string, error)
+	EmitEvent(eventType string, payload map[string]interface{}, visibility string) error
+}
+
+// ActionResult reports the outcome of one action.
+type ActionResult struct {
+	Route  string `json:"route"`
+	Action string `json:"action"`
+	OK     bool   `json:"ok"`
+	ID     string `json:"id,omitempty"` // created issue ID
+	Error  string `json:"error,omitempty"`
+}
+
+// Response is the JSON body returned for an accepted webhook.
+type Response struct {
+	Matched []string       `json:"matched"`
+	Results []ActionResult `json:"results,omitempty"`
+}
+
+// Handler serves webhook requests according to a WebhooksConfig.
+type Handler struct {
+	cfg     *config.WebhooksConfig
+	actions Actions
+	logf    func(format string, args ...interface{})
+	routes  []*route
+}
+
+// route is a config route with its action templates compiled.
+type route struct {
+	config.WebhookRoute
+	actions []compiledAction
+}
+
+// compiledAction holds parsed templates for an action's string fields.
+type compiledAction struct {
+	config.WebhookAction
+	fields map[string]*template.Template
+}
+
+// NewHandler compiles the configured routes. Template errors are reported
+// here rather than when a request arrives.
+func NewHandler(cfg *config.WebhooksConfig, actions Actions, logf func(format string, args ...interface{})) (*Handler, error) {
+	if logf == nil {
+		logf = func(format string, args ...interface{}) {}
+	}
+	h := &Handler{cfg: cfg, actions: actions, logf: logf}
+
+	for _, rc := range cfg.Routes {
+		r := &route{WebhookRoute: rc}
+		for i, ac := range rc.Actions {
+			ca := compiledAction{WebhookAction: ac, fields: make(map[string]*template.Template)}
+			for name, text := range templateFields(ac) {
+				if text == "" {
+					continue
+				}
+				tmpl, err := template.New(name).Parse(text)
+				if err != nil {
+					return nil, fmt.Errorf("route %q action %d %s: %w", rc.Name, i, name, err)
+				}
+				ca.fields[name] = tmpl
+			}
+			r.actions = append(r.actions, ca)
+		}
+		h.routes = append(h.routes, r)
+	}
+	return h, nil
+}
+
+// templateFields returns the action fields that accept templates.
+func templateFields(a config.WebhookAction) map[string]string {
+	return map[string]string{
+		"to":      a.To,
+		"subject": a.Subject,
+		"body":    a.Body,
+		"gate":    a.Gate,
+		"reason":  a.Reason,
+		"rig":     a.Rig,
+		"title":   a.Title,
+		"event":   a.Event,
+	}
+}
+
+// ServeHTTP verifies, matches and dispatches one webhook request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, PathPrefix) {
+		http.NotFound(w, r)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, PathPrefix)
+
+	var candidates []*route
+	for _, rt := range h.routes {
+		if rt.Path == path {
+			candidates = append(candidates, rt)
+		}
+	}
+	if len(candidates) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	limit := h.cfg.MaxBodyBytes
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "reading body failed")
+		return
+	}
+	if int64(len(body)) > limit {
+		writeError(w, http.StatusRequestEntityTooLarge, "body too large")
+		return
+	}
+
+	// Only routes whose secret produced the signature may run
+	signature := r.Header.Get(SignatureHeader)
+	if signature == "" {
+		signature = r.Header.Get(GitHubSignatureHeader)
+	}
+	var verified []*route
+	for _, rt := range candidates {
+		secret := rt.Secret
+		if secret == "" {
+			secret = h.cfg.Secret
+		}
+		if VerifySignature(secret, body, signature) {
+			verified = append(verified, rt)
+		}
+	}
+	if len(verified) == 0 {
+		h.logf("webhook: rejected /hooks/%s from %s: bad or missing signature", path, r.RemoteAddr)
+		writeError(w, http.StatusUnauthorized, "invalid signature")
+		return
+	}
+
+	var payload map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil || payload == nil {
+		writeError(w, http.StatusBadRequest, "body must be a JSON object")
+		return
+	}
+
+	resp := Response{Matched: []string{}}
+	for _, rt := range verified {
+		if !rt.matches(r.Header, payload) {
+			continue
+		}
+		name := rt.Name
+		if name == "" {
+			name = rt.Path
+		}
+		resp.Matched = append(resp.Matched, name)
+		for _, action := range rt.actions {
+			result := h.run(name, action, payload)
+			if !result.OK {
+				h.logf("webhook: route %s: %s failed: %s", name, result.Action, result.Error)
+			}
+			resp.Results = append(resp.Results, result)
+		}
+	}
+	if len(resp.Matched) == 0 {
+		h.logf("webhook: /hooks/%s: no rule matched", path)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// run renders an action's templates and performs it.
+func (h *Handler) run(routeName string, a compiledAction, payload map[string]interface{}) ActionResult {
+	result := ActionResult{Route: routeName, Action: a.Type}
+
+	fields := make(map[string]string, len(a.fields))
+	for name, tmpl := range a.fields {
+		value, err := render(tmpl, payload)
+		if err != nil {
+			result.Error = fmt.Sprintf("rendering %s: %v", name, err)
+			return result
+		}
+		// Rendered values become CLI arguments; never let a payload inject a flag
+		if strings.HasPrefix(value, "-") && (name == "to" || name == "gate" || name == "rig") {
+			result.Error = fmt.Sprintf("%s %q must not start with '-'", name, value)
+			return result
+		}
+		fields[name] = value
+	}
+
+	var err error
+	switch a.Type {
+	case config.WebhookActionMail:
+		err = h.actions.SendMail(fields["to"], fields["subject"], fields["body"])
+	case config.WebhookActionCloseGate:
+		if fields["gate"] == "" {
+			err = errors.New("gate ID rendered empty")
+			break
+		}
+		err = h.actions.CloseGate(fields["gate"], fields["reason"])
+	case config.WebhookActionCreateIssue:
+		priority := -1
+		if a.Priority != nil {
+			priority = *a.Priority
+		}
+		result.ID, err = h.actions.CreateIssue(fields["rig"], beads.CreateOptions{
+			Title:       fields["title"],
+			Type:        a.IssueType,
+			Priority:    priority,
+			Description: fields["body"],
+			Actor:       "webhook",
+		})
+	case config.WebhookActionEvent:
+		visibility := a.Visibility
+		if visibility == "" {
+			visibility = "feed"
+		}
+		eventPayload := map[string]interface{}{"route": routeName}
+		if msg := fields["body"]; msg != "" {
+			eventPayload["message"] = msg
+		}
+		err = h.actions.EmitEvent(fields["event"], eventPayload, visibility)
+	default:
+		err = fmt.Errorf("unknown action type %q", a.Type)
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.OK = true
+	return result
+}
+
+// matches reports whether the request headers and payload satisfy the route.
+func (rt *route) matches(header http.Header, payload map[string]interface{}) bool {
+	for name, want := range rt.Headers {
+		if header.Get(name) != want {
+			return false
+		}
+	}
+	for path, want := range rt.Match {
+		got, ok := Lookup(payload, path)
+		if !ok {
+			return false
+		}
+		if want != "*" && got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// Lookup resolves a dotted path (e.g. "pull_request.head.ref" or
+// "commits.0.id") in a decoded JSON payload and returns the value as a
+// string. Objects and arrays are returned as compact JSON.
+func Lookup(payload map[string]interface{}, path string) (string, bool) {
+	var cur interface{} = payload
+	for _, key := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			next, ok := node[key]
+			if !ok {
+				return "", false
+			}
+			cur = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return "", false
+			}
+			cur = node[i]
+		default:
+			return "", false
+		}
+	}
+	return stringify(cur), true
+}
+
+func stringify(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(data)
+	}
+}
+
+// render executes a template against the payload. Missing keys render as
+// empty strings rather than "<no value>".
+func render(tmpl *template.Template, payload map[string]interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, payload); err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(buf.String(), "<no value>", ""), nil
+}
+
+// Sign returns the signature header value for body: "sha256=<hex>".
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature is a valid Sign(secret, body).
+// An empty secret never verifies.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	if secret == "" {
+		return false
+	}
+	hexSig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(hexSig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,277 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+const testSecret = "s3cret"
+
+// fakeActions records calls instead of shelling out.
+type fakeActions struct {
+	calls []string
+	err   error
+}
+
+func (f *fakeActions) SendMail(to, subject, body string) error {
+	f.calls = append(f.calls, "mail "+to+" | "+subject+" | "+body)
+	return f.err
+}
+
+func (f *fakeActions) CloseGate(gateID, reason string) error {
+	f.calls = append(f.calls, "gate "+gateID+" | "+reason)
+	return f.err
+}
+
+func (f *fakeActions) CreateIssue(rig string, opts beads.CreateOptions) (string, error) {
+	f.calls = append(f.calls, "issue "+rig+" | "+opts.Title+" | "+opts.Type)
+	return "gt-new", f.err
+}
+
+func (f *fakeActions) EmitEvent(eventType string, payload map[string]interface{}, visibility string) error {
+	f.calls = append(f.calls, "event "+eventType+" | "+visibility+" | "+payload["route"].(string))
+	return f.err
+}
+
+func testConfig() *config.WebhooksConfig {
+	p1 := 1
+	return &config.WebhooksConfig{
+		Enabled: true,
+		Secret:  testSecret,
+		Routes: []config.WebhookRoute{
+			{
+				Name:  "ci-failed",
+				Path:  "ci",
+				Match: map[string]string{"status": "failure"},
+				Actions: []config.WebhookAction{
+					{Type: config.WebhookActionMail, To: "{{.rig}}/witness", Subject: "CI failed: {{.workflow}}", Body: "run {{.run.id}}{{.missing}}"},
+					{Type: config.WebhookActionCreateIssue, Rig: "{{.rig}}", Title: "Fix CI: {{.workflow}}", IssueType: "bug", Priority: &p1},
+				},
+			},
+			{
+				Name:  "ci-gate",
+				Path:  "ci",
+				Match: map[string]string{"gate": "*"},
+				Actions: []config.WebhookAction{
+					{Type: config.WebhookActionCloseGate, Gate: "{{.gate}}", Reason: "CI {{.status}}"},
+				},
+			},
+			{
+				Name:    "review",
+				Path:    "github",
+				Headers: map[string]string{"X-GitHub-Event": "pull_request_review"},
+				Actions: []config.WebhookAction{
+					{Type: config.WebhookActionEvent, Event: "pr_review"},
+				},
+			},
+			{
+				Name:    "own-secret",
+				Path:    "private",
+				Secret:  "other",
+				Actions: []config.WebhookAction{{Type: config.WebhookActionEvent, Event: "ping"}},
+			},
+		},
+	}
+}
+
+func newTestHandler(t *testing.T) (*Handler, *fakeActions) {
+	t.Helper()
+	fake := &fakeActions{}
+	h, err := NewHandler(testConfig(), fake, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h, fake
+}
+
+func post(h http.Handler, path, secret, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, []byte(body)))
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder) Response {
+	t.Helper()
+	var resp Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+	}
+	return resp
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"a":1}`)
+	sig := Sign(testSecret, body)
+	if !VerifySignature(testSecret, body, sig) {
+		t.Error("valid signature rejected")
+	}
+	for _, bad := range []string{"", "sha256=", "sha256=zz", strings.TrimPrefix(sig, "sha256="), Sign("wrong", body)} {
+		if VerifySignature(testSecret, body, bad) {
+			t.Errorf("signature %q accepted", bad)
+		}
+	}
+	if VerifySignature("", body, Sign("", body)) {
+		t.Error("empty secret verified")
+	}
+}
+
+func TestHandler_RunsMatchingRoutes(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	body := `{"status":"failure","workflow":"test","rig":"gastown","run":{"id":42},"gate":"hq-gate1"}`
+	rec := post(h, "/hooks/ci", testSecret, body, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+	resp := decode(t, rec)
+	if len(resp.Matched) != 2 || len(resp.Results) != 3 {
+		t.Fatalf("response = %+v, want 2 routes and 3 results", resp)
+	}
+	if resp.Results[1].ID != "gt-new" {
+		t.Errorf("create_issue result = %+v, want ID gt-new", resp.Results[1])
+	}
+
+	want := []string{
+		"mail gastown/witness | CI failed: test | run 42",
+		"issue gastown | Fix CI: test | bug",
+		"gate hq-gate1 | CI failure",
+	}
+	if strings.Join(fake.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls:\n%s\nwant:\n%s", strings.Join(fake.calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestHandler_NoMatch(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	rec := post(h, "/hooks/ci", testSecret, `{"status":"success"}`, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if resp := decode(t, rec); len(resp.Matched) != 0 || len(fake.calls) != 0 {
+		t.Errorf("response = %+v, calls = %v; want nothing run", resp, fake.calls)
+	}
+
+	// Header rules
+	post(h, "/hooks/github", testSecret, `{}`, map[string]string{"X-GitHub-Event": "push"})
+	if len(fake.calls) != 0 {
+		t.Errorf("push event ran %v", fake.calls)
+	}
+	post(h, "/hooks/github", testSecret, `{}`, map[string]string{"X-GitHub-Event": "pull_request_review"})
+	if len(fake.calls) != 1 || fake.calls[0] != "event pr_review | feed | review" {
+		t.Errorf("review event calls = %v", fake.calls)
+	}
+}
+
+func TestHandler_Rejects(t *testing.T) {
+	h, fake := newTestHandler(t)
+	h.cfg.MaxBodyBytes = 64
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		secret string
+		body   string
+		want   int
+	}{
+		{"unknown path", http.MethodPost, "/hooks/nope", testSecret, `{}`, http.StatusNotFound},
+		{"outside prefix", http.MethodPost, "/ci", testSecret, `{}`, http.StatusNotFound},
+		{"GET", http.MethodGet, "/hooks/ci", testSecret, ``, http.StatusMethodNotAllowed},
+		{"unsigned", http.MethodPost, "/hooks/ci", "", `{"status":"failure"}`, http.StatusUnauthorized},
+		{"wrong secret", http.MethodPost, "/hooks/ci", "nope", `{"status":"failure"}`, http.StatusUnauthorized},
+		{"route secret only", http.MethodPost, "/hooks/private", testSecret, `{}`, http.StatusUnauthorized},
+		{"not an object", http.MethodPost, "/hooks/ci", testSecret, `[1,2]`, http.StatusBadRequest},
+		{"too large", http.MethodPost, "/hooks/ci", testSecret, `{"pad":"` + strings.Repeat("x", 100) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		if tt.secret != "" {
+			req.Header.Set(SignatureHeader, Sign(tt.secret, []byte(tt.body)))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+	if len(fake.calls) != 0 {
+		t.Errorf("rejected requests ran actions: %v", fake.calls)
+	}
+
+	// A route's own secret works, and GitHub's header is accepted
+	req := httptest.NewRequest(http.MethodPost, "/hooks/private", strings.NewReader(`{}`))
+	req.Header.Set(GitHubSignatureHeader, Sign("other", []byte(`{}`)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || len(fake.calls) != 1 {
+		t.Errorf("route secret: status = %d, calls = %v", rec.Code, fake.calls)
+	}
+}
+
+func TestHandler_ActionErrors(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.err = errors.New("bd unavailable")
+
+	resp := decode(t, post(h, "/hooks/ci", testSecret, `{"status":"failure","rig":"gastown"}`, nil))
+	if len(resp.Results) != 2 || resp.Results[0].OK || !strings.Contains(resp.Results[0].Error, "bd unavailable") {
+		t.Errorf("results = %+v, want failures reported", resp.Results)
+	}
+
+	// Payload values may not smuggle CLI flags
+	fake.err, fake.calls = nil, nil
+	resp = decode(t, post(h, "/hooks/ci", testSecret, `{"status":"failure","rig":"--help"}`, nil))
+	if len(fake.calls) != 0 || resp.Results[0].OK {
+		t.Errorf("flag-like rig ran: calls = %v, results = %+v", fake.calls, resp.Results)
+	}
+}
+
+func TestNewHandler_BadTemplate(t *testing.T) {
+	cfg := &config.WebhooksConfig{Secret: testSecret, Routes: []config.WebhookRoute{{
+		Path:    "x",
+		Actions: []config.WebhookAction{{Type: config.WebhookActionEvent, Event: "{{.oops"}},
+	}}}
+	if _, err := NewHandler(cfg, &fakeActions{}, nil); err == nil {
+		t.Error("NewHandler accepted a malformed template")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"a":{"b":[{"c":"x"},{"c":true}]},"n":null}`), &payload); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"a.b.0.c", "x", true},
+		{"a.b.1.c", "true", true},
+		{"a.b.2.c", "", false},
+		{"a.b.x", "", false},
+		{"a.missing", "", false},
+		{"n", "", true},
+		{"a.b.0", `{"c":"x"}`, true},
+	}
+	for _, tt := range tests {
+		got, ok := Lookup(payload, tt.path)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Lookup(%q) = %q, %v; want %q, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
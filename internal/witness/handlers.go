@@ -1,7 +1,6 @@
 package witness
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/convoy"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -20,6 +20,10 @@ import (
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
+// openBeads returns the beads store the handlers use from workDir.
+// Tests swap it for a MemStore.
+var openBeads = func(workDir string) beads.Store { return beads.New(workDir) }
+
 // HandlerResult tracks the result of handling a protocol message.
 type HandlerResult struct {
 	MessageID    string
@@ -53,15 +57,15 @@ func HandlePolecatDone(workDir, rigName string, msg *mail.Message, router *mail.
 	}
 
 	// Parse the message
-	payload, err := ParsePolecatDone(msg.Subject, msg.Body)
+	payload, err := protocol.DecodeAs[*protocol.PolecatDonePayload](msg)
 	if err != nil {
 		result.Error = fmt.Errorf("parsing POLECAT_DONE: %w", err)
 		return result
 	}
 
-	if stale, reason := isStalePolecatDone(rigName, payload.PolecatName, msg); stale {
+	if stale, reason := isStalePolecatDone(rigName, payload.Polecat, msg); stale {
 		result.Handled = true
-		result.Action = fmt.Sprintf("ignored stale POLECAT_DONE for %s (%s)", payload.PolecatName, reason)
+		result.Action = fmt.Sprintf("ignored stale POLECAT_DONE for %s (%s)", payload.Polecat, reason)
 		return result
 	}
 
@@ -70,30 +74,32 @@ func HandlePolecatDone(workDir, rigName string, msg *mail.Message, router *mail.
 	// when the gate closes via gt gate wake.
 	if payload.Exit == "PHASE_COMPLETE" {
 		result.Handled = true
-		result.Action = fmt.Sprintf("phase-complete for %s (gate=%s) - session recycled, awaiting gate", payload.PolecatName, payload.Gate)
+		result.Action = fmt.Sprintf("phase-complete for %s (gate=%s) - session recycled, awaiting gate", payload.Polecat, payload.Gate)
 		// Note: The polecat has already registered itself as a gate waiter via bd
 		// The gate wake mechanism (gt gate wake) will send mail when gate closes
 		// A new polecat will be dispatched to continue the molecule from the next step
 		return result
 	}
 
+	store := openBeads(workDir)
+
 	// Check if this polecat has a pending MR
 	// ESCALATED/DEFERRED exits typically have no MR pending
-	hasPendingMR := payload.MRID != "" || payload.Exit == "COMPLETED"
+	hasPendingMR := payload.MR != "" || payload.Exit == "COMPLETED"
 
 	// Local-only branches model: if there's a pending MR, DON'T nuke.
 	// The polecat's local branch is needed for conflict resolution if merge fails.
 	// Once the MR merges (MERGED signal), HandleMerged will nuke the polecat.
 	if hasPendingMR {
 		// Create cleanup wisp to track this polecat is waiting for merge
-		wispID, err := createCleanupWisp(workDir, payload.PolecatName, payload.IssueID, payload.Branch)
+		wispID, err := createCleanupWisp(store, payload.Polecat, payload.Issue, payload.Branch)
 		if err != nil {
 			result.Error = fmt.Errorf("creating cleanup wisp: %w", err)
 			return result
 		}
 
 		// Update wisp state to indicate it's waiting for merge
-		if err := UpdateCleanupWispState(workDir, wispID, "merge-requested"); err != nil {
+		if err := updateCleanupWispState(store, wispID, "merge-requested"); err != nil {
 			// Non-fatal - wisp was created, just couldn't update state
 			result.Error = fmt.Errorf("updating wisp state: %w", err)
 		}
@@ -121,15 +127,15 @@ func HandlePolecatDone(workDir, rigName string, msg *mail.Message, router *mail.
 
 		result.Handled = true
 		result.WispCreated = wispID
-		result.Action = fmt.Sprintf("deferred cleanup for %s (pending MR=%s, MERGE_READY sent to refinery)", payload.PolecatName, payload.MRID)
+		result.Action = fmt.Sprintf("deferred cleanup for %s (pending MR=%s, MERGE_READY sent to refinery)", payload.Polecat, payload.MR)
 		return result
 	}
 
 	// No pending MR - try to auto-nuke immediately
-	nukeResult := AutoNukeIfClean(workDir, rigName, payload.PolecatName)
+	nukeResult := AutoNukeIfClean(workDir, rigName, payload.Polecat)
 	if nukeResult.Nuked {
 		result.Handled = true
-		result.Action = fmt.Sprintf("auto-nuked %s (exit=%s, no MR): %s", payload.PolecatName, payload.Exit, nukeResult.Reason)
+		result.Action = fmt.Sprintf("auto-nuked %s (exit=%s, no MR): %s", payload.Polecat, payload.Exit, nukeResult.Reason)
 		return result
 	}
 	if nukeResult.Error != nil {
@@ -138,7 +144,7 @@ func HandlePolecatDone(workDir, rigName string, msg *mail.Message, router *mail.
 	}
 
 	// Couldn't auto-nuke (dirty state or verification failed) - create wisp for manual intervention
-	wispID, err := createCleanupWisp(workDir, payload.PolecatName, payload.IssueID, payload.Branch)
+	wispID, err := createCleanupWisp(store, payload.Polecat, payload.Issue, payload.Branch)
 	if err != nil {
 		result.Error = fmt.Errorf("creating cleanup wisp: %w", err)
 		return result
@@ -146,7 +152,7 @@ func HandlePolecatDone(workDir, rigName string, msg *mail.Message, router *mail.
 
 	result.Handled = true
 	result.WispCreated = wispID
-	result.Action = fmt.Sprintf("created cleanup wisp %s for %s (needs manual cleanup: %s)", wispID, payload.PolecatName, nukeResult.Reason)
+	result.Action = fmt.Sprintf("created cleanup wisp %s for %s (needs manual cleanup: %s)", wispID, payload.Polecat, nukeResult.Reason)
 
 	return result
 }
@@ -175,13 +181,12 @@ func HandleLifecycleShutdown(workDir, rigName string, msg *mail.Message) *Handle
 		ProtocolType: ProtoLifecycleShutdown,
 	}
 
-	// Extract polecat name from subject
-	matches := PatternLifecycleShutdown.FindStringSubmatch(msg.Subject)
-	if len(matches) < 2 {
-		result.Error = fmt.Errorf("invalid LIFECYCLE:Shutdown subject: %s", msg.Subject)
+	payload, err := protocol.DecodeAs[*protocol.LifecycleShutdownPayload](msg)
+	if err != nil {
+		result.Error = fmt.Errorf("parsing LIFECYCLE:Shutdown: %w", err)
 		return result
 	}
-	polecatName := matches[1]
+	polecatName := payload.Polecat
 
 	// Shutdown means no pending work - try to auto-nuke immediately
 	nukeResult := AutoNukeIfClean(workDir, rigName, polecatName)
@@ -196,7 +201,7 @@ func HandleLifecycleShutdown(workDir, rigName string, msg *mail.Message) *Handle
 	}
 
 	// Couldn't auto-nuke - create a cleanup wisp for manual intervention
-	wispID, err := createCleanupWisp(workDir, polecatName, "", "")
+	wispID, err := createCleanupWisp(openBeads(workDir), polecatName, "", "")
 	if err != nil {
 		result.Error = fmt.Errorf("creating cleanup wisp: %w", err)
 		return result
@@ -218,7 +223,7 @@ func HandleHelp(workDir, rigName string, msg *mail.Message, router *mail.Router)
 	}
 
 	// Parse the message
-	payload, err := ParseHelp(msg.Subject, msg.Body)
+	payload, err := protocol.DecodeAs[*protocol.HelpPayload](msg)
 	if err != nil {
 		result.Error = fmt.Errorf("parsing HELP: %w", err)
 		return result
@@ -259,14 +264,15 @@ func HandleMerged(workDir, rigName string, msg *mail.Message) *HandlerResult {
 	}
 
 	// Parse the message
-	payload, err := ParseMerged(msg.Subject, msg.Body)
+	payload, err := protocol.DecodeAs[*protocol.MergedPayload](msg)
 	if err != nil {
 		result.Error = fmt.Errorf("parsing MERGED: %w", err)
 		return result
 	}
 
 	// Find the cleanup wisp for this polecat
-	wispID, err := findCleanupWisp(workDir, payload.PolecatName)
+	store := openBeads(workDir)
+	wispID, err := findCleanupWisp(store, payload.Polecat)
 	if err != nil {
 		result.Error = fmt.Errorf("finding cleanup wisp: %w", err)
 		return result
@@ -275,23 +281,23 @@ func HandleMerged(workDir, rigName string, msg *mail.Message) *HandlerResult {
 	if wispID == "" {
 		// No wisp found - polecat may have been cleaned up already
 		result.Handled = true
-		result.Action = fmt.Sprintf("no cleanup wisp found for %s (may be already cleaned)", payload.PolecatName)
+		result.Action = fmt.Sprintf("no cleanup wisp found for %s (may be already cleaned)", payload.Polecat)
 		return result
 	}
 
 	// Verify the polecat's commit is actually on main before allowing nuke.
 	// This prevents work loss when MERGED signal is for a stale MR or the merge failed.
-	onMain, err := verifyCommitOnMain(workDir, rigName, payload.PolecatName)
+	onMain, err := verifyCommitOnMain(workDir, rigName, payload.Polecat)
 	if err != nil {
 		// Couldn't verify - log warning but continue with other checks
 		// The polecat may not exist anymore (already nuked) which is fine
-		result.Action = fmt.Sprintf("warning: couldn't verify commit on main for %s: %v", payload.PolecatName, err)
+		result.Action = fmt.Sprintf("warning: couldn't verify commit on main for %s: %v", payload.Polecat, err)
 	} else if !onMain {
 		// Commit is NOT on main - don't nuke!
 		result.Handled = true
 		result.WispCreated = wispID
-		result.Error = fmt.Errorf("polecat %s commit is NOT on main - MERGED signal may be stale, DO NOT NUKE", payload.PolecatName)
-		result.Action = fmt.Sprintf("BLOCKED: %s commit not verified on main, merge may have failed", payload.PolecatName)
+		result.Error = fmt.Errorf("polecat %s commit is NOT on main - MERGED signal may be stale, DO NOT NUKE", payload.Polecat)
+		result.Action = fmt.Sprintf("BLOCKED: %s commit not verified on main, merge may have failed", payload.Polecat)
 		return result
 	}
 
@@ -299,66 +305,66 @@ func HandleMerged(workDir, rigName string, msg *mail.Message) *HandlerResult {
 	// Run this after verifyCommitOnMain succeeds, regardless of cleanup status.
 	// The work is confirmed merged at this point, so convoys tracking this issue
 	// can potentially close even if polecat cleanup is blocked.
-	if onMain && payload.IssueID != "" {
+	if onMain && payload.Issue != "" {
 		townRoot, _ := workspace.Find(workDir)
 		if townRoot != "" {
-			convoy.CheckConvoysForIssue(townRoot, payload.IssueID, "witness", nil)
+			convoy.CheckConvoysForIssue(townRoot, payload.Issue, "witness", nil)
 		}
 	}
 
 	// ZFC #10: Check cleanup_status before allowing nuke
 	// This prevents work loss when MERGED signal arrives for stale MRs or
 	// when polecat has new unpushed work since the MR was created.
-	cleanupStatus := getCleanupStatus(workDir, rigName, payload.PolecatName)
+	cleanupStatus := getCleanupStatus(store, workDir, rigName, payload.Polecat)
 
 	switch cleanupStatus {
 	case "clean":
 		// Safe to nuke - polecat has confirmed clean state
 		// Execute the nuke immediately
-		if err := NukePolecat(workDir, rigName, payload.PolecatName); err != nil {
+		if err := NukePolecat(workDir, rigName, payload.Polecat); err != nil {
 			result.Handled = true
 			result.WispCreated = wispID
-			result.Error = fmt.Errorf("nuke failed for %s: %w", payload.PolecatName, err)
-			result.Action = fmt.Sprintf("cleanup wisp %s for %s: nuke FAILED", wispID, payload.PolecatName)
+			result.Error = fmt.Errorf("nuke failed for %s: %w", payload.Polecat, err)
+			result.Action = fmt.Sprintf("cleanup wisp %s for %s: nuke FAILED", wispID, payload.Polecat)
 		} else {
 			result.Handled = true
 			result.WispCreated = wispID
-			result.Action = fmt.Sprintf("auto-nuked %s (cleanup_status=clean, wisp=%s)", payload.PolecatName, wispID)
+			result.Action = fmt.Sprintf("auto-nuked %s (cleanup_status=clean, wisp=%s)", payload.Polecat, wispID)
 		}
 
 	case "has_uncommitted":
 		// Has uncommitted changes - might be WIP, escalate to Mayor
 		result.Handled = true
 		result.WispCreated = wispID
-		result.Error = fmt.Errorf("polecat %s has uncommitted changes - escalate to Mayor before nuke", payload.PolecatName)
-		result.Action = fmt.Sprintf("BLOCKED: %s has uncommitted work, needs escalation", payload.PolecatName)
+		result.Error = fmt.Errorf("polecat %s has uncommitted changes - escalate to Mayor before nuke", payload.Polecat)
+		result.Action = fmt.Sprintf("BLOCKED: %s has uncommitted work, needs escalation", payload.Polecat)
 
 	case "has_stash":
 		// Has stashed work - definitely needs review
 		result.Handled = true
 		result.WispCreated = wispID
-		result.Error = fmt.Errorf("polecat %s has stashed work - escalate to Mayor before nuke", payload.PolecatName)
-		result.Action = fmt.Sprintf("BLOCKED: %s has stashed work, needs escalation", payload.PolecatName)
+		result.Error = fmt.Errorf("polecat %s has stashed work - escalate to Mayor before nuke", payload.Polecat)
+		result.Action = fmt.Sprintf("BLOCKED: %s has stashed work, needs escalation", payload.Polecat)
 
 	case "has_unpushed":
 		// Critical: has unpushed commits that could be lost
 		result.Handled = true
 		result.WispCreated = wispID
-		result.Error = fmt.Errorf("polecat %s has unpushed commits - DO NOT NUKE, escalate to Mayor", payload.PolecatName)
-		result.Action = fmt.Sprintf("BLOCKED: %s has unpushed commits, DO NOT NUKE", payload.PolecatName)
+		result.Error = fmt.Errorf("polecat %s has unpushed commits - DO NOT NUKE, escalate to Mayor", payload.Polecat)
+		result.Action = fmt.Sprintf("BLOCKED: %s has unpushed commits, DO NOT NUKE", payload.Polecat)
 
 	default:
 		// Unknown or no status - we already verified commit is on main above
 		// Safe to nuke since verification passed
-		if err := NukePolecat(workDir, rigName, payload.PolecatName); err != nil {
+		if err := NukePolecat(workDir, rigName, payload.Polecat); err != nil {
 			result.Handled = true
 			result.WispCreated = wispID
-			result.Error = fmt.Errorf("nuke failed for %s: %w", payload.PolecatName, err)
-			result.Action = fmt.Sprintf("cleanup wisp %s for %s: nuke FAILED", wispID, payload.PolecatName)
+			result.Error = fmt.Errorf("nuke failed for %s: %w", payload.Polecat, err)
+			result.Action = fmt.Sprintf("cleanup wisp %s for %s: nuke FAILED", wispID, payload.Polecat)
 		} else {
 			result.Handled = true
 			result.WispCreated = wispID
-			result.Action = fmt.Sprintf("auto-nuked %s (commit on main, cleanup_status=%s, wisp=%s)", payload.PolecatName, cleanupStatus, wispID)
+			result.Action = fmt.Sprintf("auto-nuked %s (commit on main, cleanup_status=%s, wisp=%s)", payload.Polecat, cleanupStatus, wispID)
 		}
 	}
 
@@ -374,14 +380,14 @@ func HandleMergeFailed(workDir, rigName string, msg *mail.Message, router *mail.
 	}
 
 	// Parse the message
-	payload, err := ParseMergeFailed(msg.Subject, msg.Body)
+	payload, err := protocol.DecodeAs[*protocol.MergeFailedPayload](msg)
 	if err != nil {
 		result.Error = fmt.Errorf("parsing MERGE_FAILED: %w", err)
 		return result
 	}
 
 	// Notify the polecat about the failure
-	polecatAddr := fmt.Sprintf("%s/polecats/%s", rigName, payload.PolecatName)
+	polecatAddr := fmt.Sprintf("%s/polecats/%s", rigName, payload.Polecat)
 	notification := &mail.Message{
 		From:     fmt.Sprintf("%s/witness", rigName),
 		To:       polecatAddr,
@@ -397,7 +403,7 @@ Error: %s
 
 Please fix the issue and resubmit with 'gt done'.`,
 			payload.Branch,
-			payload.IssueID,
+			payload.Issue,
 			payload.FailureType,
 			payload.Error,
 		),
@@ -410,7 +416,7 @@ Please fix the issue and resubmit with 'gt done'.`,
 
 	result.Handled = true
 	result.MailSent = notification.ID
-	result.Action = fmt.Sprintf("notified %s of merge failure: %s - %s", payload.PolecatName, payload.FailureType, payload.Error)
+	result.Action = fmt.Sprintf("notified %s of merge failure: %s - %s", payload.Polecat, payload.FailureType, payload.Error)
 
 	return result
 }
@@ -424,14 +430,14 @@ func HandleSwarmStart(workDir string, msg *mail.Message) *HandlerResult {
 	}
 
 	// Parse the message
-	payload, err := ParseSwarmStart(msg.Body)
+	payload, err := protocol.DecodeAs[*protocol.SwarmStartPayload](msg)
 	if err != nil {
 		result.Error = fmt.Errorf("parsing SWARM_START: %w", err)
 		return result
 	}
 
 	// Create a swarm tracking wisp
-	wispID, err := createSwarmWisp(workDir, payload)
+	wispID, err := createSwarmWisp(openBeads(workDir), payload)
 	if err != nil {
 		result.Error = fmt.Errorf("creating swarm wisp: %w", err)
 		return result
@@ -445,7 +451,7 @@ func HandleSwarmStart(workDir string, msg *mail.Message) *HandlerResult {
 }
 
 // createCleanupWisp creates a wisp to track polecat cleanup.
-func createCleanupWisp(workDir, polecatName, issueID, branch string) (string, error) {
+func createCleanupWisp(store beads.Store, polecatName, issueID, branch string) (string, error) {
 	title := fmt.Sprintf("cleanup:%s", polecatName)
 	description := fmt.Sprintf("Verify and cleanup polecat %s", polecatName)
 	if issueID != "" {
@@ -455,95 +461,67 @@ func createCleanupWisp(workDir, polecatName, issueID, branch string) (string, er
 		description += fmt.Sprintf("\nBranch: %s", branch)
 	}
 
-	labels := strings.Join(CleanupWispLabels(polecatName, "pending"), ",")
-
-	output, err := util.ExecWithOutput(workDir, "bd", "create",
-		"--ephemeral",
-		"--title", title,
-		"--description", description,
-		"--labels", labels,
-	)
+	issue, err := store.Create(beads.CreateOptions{
+		Title:       title,
+		Description: description,
+		Priority:    -1,
+		Ephemeral:   true,
+		Labels:      CleanupWispLabels(polecatName, "pending"),
+	})
 	if err != nil {
 		return "", err
 	}
-
-	// Extract wisp ID from output (bd create outputs "Created: <id>")
-	if strings.HasPrefix(output, "Created:") {
-		return strings.TrimSpace(strings.TrimPrefix(output, "Created:")), nil
-	}
-
-	// Try to extract ID from output
-	for _, line := range strings.Split(output, "\n") {
-		line = strings.TrimSpace(line)
-		// Look for bead ID pattern (e.g., "gt-abc123")
-		if strings.Contains(line, "-") && len(line) < 20 {
-			return line, nil
-		}
-	}
-
-	return output, nil
+	return issue.ID, nil
 }
 
 // createSwarmWisp creates a wisp to track swarm (batch) work.
-func createSwarmWisp(workDir string, payload *SwarmStartPayload) (string, error) {
-	title := fmt.Sprintf("swarm:%s", payload.SwarmID)
-	description := fmt.Sprintf("Tracking batch: %s\nTotal: %d polecats", payload.SwarmID, payload.Total)
-
-	labels := strings.Join(SwarmWispLabels(payload.SwarmID, payload.Total, 0, payload.StartedAt), ",")
-
-	output, err := util.ExecWithOutput(workDir, "bd", "create",
-		"--ephemeral",
-		"--title", title,
-		"--description", description,
-		"--labels", labels,
-	)
+func createSwarmWisp(store beads.Store, payload *protocol.SwarmStartPayload) (string, error) {
+	issue, err := store.Create(beads.CreateOptions{
+		Title:       fmt.Sprintf("swarm:%s", payload.SwarmID),
+		Description: fmt.Sprintf("Tracking batch: %s\nTotal: %d polecats", payload.SwarmID, payload.Total),
+		Priority:    -1,
+		Ephemeral:   true,
+		Labels:      SwarmWispLabels(payload.SwarmID, payload.Total, 0, payload.StartedAt),
+	})
 	if err != nil {
 		return "", err
 	}
-
-	if strings.HasPrefix(output, "Created:") {
-		return strings.TrimSpace(strings.TrimPrefix(output, "Created:")), nil
-	}
-
-	return output, nil
+	return issue.ID, nil
 }
 
 // findCleanupWisp finds an existing cleanup wisp for a polecat.
-func findCleanupWisp(workDir, polecatName string) (string, error) {
-	output, err := util.ExecWithOutput(workDir, "bd", "list",
-		"--ephemeral",
-		"--labels", fmt.Sprintf("polecat:%s,state:merge-requested", polecatName),
-		"--status", "open",
-		"--json",
-	)
+func findCleanupWisp(store beads.Store, polecatName string) (string, error) {
+	wisps, err := listCleanupWisps(store, polecatName)
 	if err != nil {
-		// Empty result is fine
-		if strings.Contains(err.Error(), "no issues found") {
-			return "", nil
-		}
 		return "", err
 	}
-
-	// Parse JSON to get the wisp ID
-	if output == "" || output == "[]" || output == "null" {
-		return "", nil
-	}
-
-	var items []struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal([]byte(output), &items); err != nil {
-		return "", fmt.Errorf("parsing cleanup wisp response: %w", err)
-	}
-	if len(items) > 0 {
-		return items[0].ID, nil
+	for _, wisp := range wisps {
+		if beads.HasLabel(wisp, "state:merge-requested") {
+			return wisp.ID, nil
+		}
 	}
 	return "", nil
 }
 
-// agentBeadResponse is used to parse the bd show --json response for agent beads.
-type agentBeadResponse struct {
-	Description string `json:"description"`
+// listCleanupWisps returns the open cleanup wisps for a polecat.
+func listCleanupWisps(store beads.Store, polecatName string) ([]*beads.Issue, error) {
+	issues, err := store.List(beads.ListOptions{
+		Status:    "open",
+		Label:     "polecat:" + polecatName,
+		Priority:  -1,
+		Limit:     -1,
+		Ephemeral: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var wisps []*beads.Issue
+	for _, issue := range issues {
+		if beads.HasLabel(issue, "cleanup") {
+			wisps = append(wisps, issue)
+		}
+	}
+	return wisps, nil
 }
 
 // getCleanupStatus retrieves the cleanup_status from a polecat's agent bead.
@@ -552,7 +530,7 @@ type agentBeadResponse struct {
 //
 // ZFC #10: This enables the Witness to verify it's safe to nuke before proceeding.
 // The polecat self-reports its git state when running `gt done`, and we trust that report.
-func getCleanupStatus(workDir, rigName, polecatName string) string {
+func getCleanupStatus(store beads.Store, workDir, rigName, polecatName string) string {
 	// Construct agent bead ID using the rig's configured prefix
 	// This supports non-gt prefixes like "bd-" for the beads rig
 	townRoot, err := workspace.Find(workDir)
@@ -563,25 +541,15 @@ func getCleanupStatus(workDir, rigName, polecatName string) string {
 	prefix := beads.GetPrefixForRig(townRoot, rigName)
 	agentBeadID := beads.PolecatBeadIDWithPrefix(prefix, rigName, polecatName)
 
-	output, err := util.ExecWithOutput(workDir, "bd", "show", agentBeadID, "--json")
+	issue, err := store.Show(agentBeadID)
 	if err != nil {
 		// Agent bead doesn't exist or bd failed - return empty (unknown status)
 		return ""
 	}
 
-	if output == "" {
-		return ""
-	}
-
-	// Parse the JSON response — bd show --json returns an array
-	var issues []agentBeadResponse
-	if err := json.Unmarshal([]byte(output), &issues); err != nil || len(issues) == 0 {
-		return ""
-	}
-
 	// Parse cleanup_status from description
 	// Description format has "cleanup_status: <value>" line
-	for _, line := range strings.Split(issues[0].Description, "\n") {
+	for _, line := range strings.Split(issue.Description, "\n") {
 		line = strings.TrimSpace(line)
 		if strings.HasPrefix(strings.ToLower(line), "cleanup_status:") {
 			value := strings.TrimSpace(strings.TrimPrefix(line, "cleanup_status:"))
@@ -597,26 +565,27 @@ func getCleanupStatus(workDir, rigName, polecatName string) string {
 
 // sendMergeReady sends a MERGE_READY notification to the Refinery.
 // This signals that a polecat's work is ready for merge queue processing.
-func sendMergeReady(router *mail.Router, rigName string, payload *PolecatDonePayload) (string, error) {
-	msg := mail.NewMessage(
-		fmt.Sprintf("%s/witness", rigName),
-		fmt.Sprintf("%s/refinery", rigName),
-		fmt.Sprintf("MERGE_READY %s", payload.PolecatName),
-		fmt.Sprintf(`Branch: %s
-Issue: %s
-MR: %s
-Polecat: %s
-Verified: clean git state`,
-			payload.Branch,
-			payload.IssueID,
-			payload.MRID,
-			payload.PolecatName,
-		),
-	)
-	msg.Priority = mail.PriorityHigh
-	msg.Type = mail.TypeTask
+func sendMergeReady(router *mail.Router, rigName string, payload *protocol.PolecatDonePayload) (string, error) {
+	msg := protocol.NewMergeReadyMessageFor(&protocol.MergeReadyPayload{
+		Branch:    payload.Branch,
+		Issue:     payload.Issue,
+		MR:        payload.MR,
+		Polecat:   payload.Polecat,
+		Rig:       rigName,
+		Verified:  "clean git state",
+		Timestamp: time.Now(),
+	})
+
+	// With an MR bead, track the refinery's MERGED/MERGE_FAILED reply so a
+	// lost MERGE_READY surfaces as a reply timeout instead of a stuck polecat
+	if payload.MR != "" {
+		if err := protocol.SendRequest(router, msg, protocol.MergeReplyTimeout); err != nil {
+			return "", err
+		}
+		return msg.ID, nil
+	}
 
-	if err := router.Send(msg); err != nil {
+	if err := protocol.Send(router, msg); err != nil {
 		return "", err
 	}
 
@@ -650,7 +619,7 @@ func nudgeRefinery(rigName string) error {
 // escalateToDeacon sends an escalation mail to the Deacon for routine operational issues.
 // The Deacon is the first line of escalation for witness operations. Only truly strategic
 // issues (deacon down, cross-rig coordination) should go directly to Mayor.
-func escalateToDeacon(router *mail.Router, rigName string, payload *HelpPayload, reason string) (string, error) {
+func escalateToDeacon(router *mail.Router, rigName string, payload *protocol.HelpPayload, reason string) (string, error) {
 	msg := &mail.Message{
 		From:     fmt.Sprintf("%s/witness", rigName),
 		To:       "deacon/",
@@ -664,7 +633,7 @@ Tried: %s
 Escalation reason: %s
 Requested at: %s`,
 			payload.Agent,
-			payload.IssueID,
+			payload.Issue,
 			payload.Topic,
 			payload.Problem,
 			payload.Tried,
@@ -732,35 +701,29 @@ DO NOT nuke without --force after recovery.`,
 
 // UpdateCleanupWispState updates a cleanup wisp's state label.
 func UpdateCleanupWispState(workDir, wispID, newState string) error {
-	// Get current labels to preserve other labels
-	output, err := util.ExecWithOutput(workDir, "bd", "show", wispID, "--json")
+	return updateCleanupWispState(openBeads(workDir), wispID, newState)
+}
+
+func updateCleanupWispState(store beads.Store, wispID, newState string) error {
+	// Get current labels to preserve the polecat name
+	wisp, err := store.Show(wispID)
 	if err != nil {
 		return fmt.Errorf("getting wisp: %w", err)
 	}
 
-	// Extract polecat name from existing labels via JSON parsing
-	polecatName := extractPolecatFromJSON(output)
-
+	polecatName := polecatFromLabels(wisp.Labels)
 	if polecatName == "" {
 		polecatName = "unknown"
 	}
 
 	// Update with new state
-	newLabels := strings.Join(CleanupWispLabels(polecatName, newState), ",")
-
-	return util.ExecRun(workDir, "bd", "update", wispID, "--labels", newLabels)
+	return store.Update(wispID, beads.UpdateOptions{SetLabels: CleanupWispLabels(polecatName, newState)})
 }
 
-// extractPolecatFromJSON extracts the polecat name from bd show --json output.
-// Returns empty string if the output is malformed or no polecat label is found.
-func extractPolecatFromJSON(output string) string {
-	var items []struct {
-		Labels []string `json:"labels"`
-	}
-	if err := json.Unmarshal([]byte(output), &items); err != nil || len(items) == 0 {
-		return ""
-	}
-	for _, label := range items[0].Labels {
+// polecatFromLabels returns the polecat name from a cleanup wisp's
+// polecat:<name> label, or "" if it has none.
+func polecatFromLabels(labels []string) string {
+	for _, label := range labels {
 		if name, ok := strings.CutPrefix(label, "polecat:"); ok {
 			return name
 		}
@@ -820,7 +783,7 @@ func AutoNukeIfClean(workDir, rigName, polecatName string) *NukePolecatResult {
 	result := &NukePolecatResult{}
 
 	// Check cleanup_status from agent bead
-	cleanupStatus := getCleanupStatus(workDir, rigName, polecatName)
+	cleanupStatus := getCleanupStatus(openBeads(workDir), workDir, rigName, polecatName)
 
 	switch cleanupStatus {
 	case "clean":
@@ -991,6 +954,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 		return result // No polecats directory
 	}
 
+	store := openBeads(workDir)
 	t := tmux.NewTmux()
 
 	for _, entry := range entries {
@@ -1020,7 +984,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 		// Done early because we need it for both live and dead session paths.
 		prefix := beads.GetPrefixForRig(townRoot, rigName)
 		agentBeadID := beads.PolecatBeadIDWithPrefix(prefix, rigName, polecatName)
-		labels := getAgentBeadLabels(workDir, agentBeadID)
+		labels := getAgentBeadLabels(store, agentBeadID)
 		doneIntent := extractDoneIntent(labels)
 
 		if sessionAlive {
@@ -1060,8 +1024,8 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 				// Agent is alive. Check if the hooked bead has been closed.
 				// A polecat that closed its bead but didn't run gt done is
 				// occupying a slot without doing work. See: gt-h1l6i
-				_, hookBead := getAgentBeadState(workDir, agentBeadID)
-				if hookBead != "" && getBeadStatus(workDir, hookBead) == "closed" {
+				_, hookBead := getAgentBeadState(store, agentBeadID)
+				if hookBead != "" && getBeadStatus(store, hookBead) == "closed" {
 					zombie := ZombieResult{
 						PolecatName: polecatName,
 						AgentState:  "bead-closed-still-running",
@@ -1102,7 +1066,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 		}
 
 		// No done-intent. Fall back to standard zombie detection.
-		agentState, hookBead := getAgentBeadState(workDir, agentBeadID)
+		agentState, hookBead := getAgentBeadState(store, agentBeadID)
 
 		// A zombie has a dead session but agent_state suggests it should be alive,
 		// or it still has work hooked. Include "spawning" so polecats that crash
@@ -1133,7 +1097,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 			HookBead:    hookBead,
 		}
 
-		cleanupStatus := getCleanupStatus(workDir, rigName, polecatName)
+		cleanupStatus := getCleanupStatus(store, workDir, rigName, polecatName)
 
 		switch cleanupStatus {
 		case "clean":
@@ -1142,7 +1106,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 			if nukeResult.Nuked {
 				zombie.Action = "auto-nuked"
 			} else if nukeResult.Skipped {
-				wispID, wispErr := createCleanupWisp(workDir, polecatName, hookBead, "")
+				wispID, wispErr := createCleanupWisp(store, polecatName, hookBead, "")
 				if wispErr != nil {
 					zombie.Error = wispErr
 				}
@@ -1162,7 +1126,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 				zombie.Action = "auto-nuked"
 			} else if nukeResult.Skipped {
 				// Couldn't nuke cleanly — create cleanup wisp
-				wispID, wispErr := createCleanupWisp(workDir, polecatName, hookBead, "")
+				wispID, wispErr := createCleanupWisp(store, polecatName, hookBead, "")
 				if wispErr != nil {
 					zombie.Error = wispErr
 				}
@@ -1175,7 +1139,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 		case "has_uncommitted", "has_stash", "has_unpushed":
 			// Dirty state — escalate to Mayor for recovery, but only if we
 			// haven't already created a cleanup wisp for this polecat (dedup).
-			existingWisp := findAnyCleanupWisp(workDir, polecatName)
+			existingWisp := findAnyCleanupWisp(store, polecatName)
 			if existingWisp != "" {
 				// Already tracked — skip escalation to prevent infinite loops.
 				zombie.Action = fmt.Sprintf("already-tracked (cleanup_status=%s, existing-wisp=%s)", cleanupStatus, existingWisp)
@@ -1193,7 +1157,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 					}
 				}
 				// Create cleanup wisp for tracking
-				wispID, wispErr := createCleanupWisp(workDir, polecatName, hookBead, "")
+				wispID, wispErr := createCleanupWisp(store, polecatName, hookBead, "")
 				if wispErr != nil && zombie.Error == nil {
 					zombie.Error = wispErr
 				}
@@ -1209,41 +1173,25 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 
 // getAgentBeadState reads agent_state and hook_bead from an agent bead.
 // Returns the agent_state string and hook_bead ID.
-func getAgentBeadState(workDir, agentBeadID string) (agentState, hookBead string) {
-	output, err := util.ExecWithOutput(workDir, "bd", "show", agentBeadID, "--json")
-	if err != nil || output == "" {
-		return "", ""
-	}
-
-	// Parse JSON response — bd show --json returns an array
-	var issues []struct {
-		AgentState string `json:"agent_state"`
-		HookBead   string `json:"hook_bead"`
-	}
-	if err := json.Unmarshal([]byte(output), &issues); err != nil || len(issues) == 0 {
+func getAgentBeadState(store beads.Store, agentBeadID string) (agentState, hookBead string) {
+	issue, err := store.Show(agentBeadID)
+	if err != nil {
 		return "", ""
 	}
-
-	return issues[0].AgentState, issues[0].HookBead
+	return issue.AgentState, issue.HookBead
 }
 
 // getBeadStatus returns the status of a bead (e.g., "open", "closed", "hooked").
 // Returns empty string if the bead doesn't exist or can't be queried.
-func getBeadStatus(workDir, beadID string) string {
+func getBeadStatus(store beads.Store, beadID string) string {
 	if beadID == "" {
 		return ""
 	}
-	output, err := util.ExecWithOutput(workDir, "bd", "show", beadID, "--json")
-	if err != nil || output == "" {
-		return ""
-	}
-	var issues []struct {
-		Status string `json:"status"`
-	}
-	if err := json.Unmarshal([]byte(output), &issues); err != nil || len(issues) == 0 {
+	issue, err := store.Show(beadID)
+	if err != nil {
 		return ""
 	}
-	return issues[0].Status
+	return issue.Status
 }
 
 // DoneIntent represents a parsed done-intent label from an agent bead.
@@ -1277,20 +1225,12 @@ func extractDoneIntent(labels []string) *DoneIntent {
 }
 
 // getAgentBeadLabels reads the labels from an agent bead.
-func getAgentBeadLabels(workDir, agentBeadID string) []string {
-	output, err := util.ExecWithOutput(workDir, "bd", "show", agentBeadID, "--json")
-	if err != nil || output == "" {
-		return nil
-	}
-
-	var issues []struct {
-		Labels []string `json:"labels"`
-	}
-	if err := json.Unmarshal([]byte(output), &issues); err != nil || len(issues) == 0 {
+func getAgentBeadLabels(store beads.Store, agentBeadID string) []string {
+	issue, err := store.Show(agentBeadID)
+	if err != nil {
 		return nil
 	}
-
-	return issues[0].Labels
+	return issue.Labels
 }
 
 // sessionRecreated checks whether a tmux session was (re)created after the
@@ -1314,24 +1254,109 @@ func sessionRecreated(t *tmux.Tmux, sessionName string, detectedAt time.Time) bo
 // findAnyCleanupWisp checks if any cleanup wisp already exists for a polecat,
 // regardless of state. Used to prevent duplicate escalation on repeated patrol
 // cycles for the same zombie.
-func findAnyCleanupWisp(workDir, polecatName string) string {
-	output, err := util.ExecWithOutput(workDir, "bd", "list",
-		"--ephemeral",
-		"--labels", fmt.Sprintf("cleanup,polecat:%s", polecatName),
-		"--status", "open",
-		"--json",
-	)
-	if err != nil {
+func findAnyCleanupWisp(store beads.Store, polecatName string) string {
+	wisps, err := listCleanupWisps(store, polecatName)
+	if err != nil || len(wisps) == 0 {
 		return ""
 	}
-	if output == "" || output == "[]" || output == "null" {
-		return ""
-	}
-	var items []struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal([]byte(output), &items); err != nil || len(items) == 0 {
-		return ""
+	return wisps[0].ID
+}
+
+// OverdueStep describes a molecule step that ran past its timeout.
+type OverdueStep struct {
+	StepID   string
+	Title    string
+	Assignee string
+	Timeout  string
+	Elapsed  time.Duration
+	MailID   string // Escalation mail sent to the Deacon
+	Error    error
+}
+
+// DetectOverdueStepsResult contains the results of an overdue step sweep.
+type DetectOverdueStepsResult struct {
+	Checked int
+	Overdue []OverdueStep
+	Errors  []error
+}
+
+// DetectOverdueSteps finds molecule steps in the rig that are still running
+// after their formula timeout (step_timeout / step_started_at fields stamped
+// by gt sling and gt mol step done) and escalates each one to the Deacon.
+//
+// Dedup: each escalation is recorded as step_escalated_at on the step bead,
+// so a step is escalated once per attempt rather than on every patrol.
+func DetectOverdueSteps(workDir, rigName string, router *mail.Router) *DetectOverdueStepsResult {
+	return detectOverdueSteps(openBeads(workDir), rigName, router.Send, time.Now())
+}
+
+func detectOverdueSteps(store beads.Store, rigName string, send func(*mail.Message) error, now time.Time) *DetectOverdueStepsResult {
+	result := &DetectOverdueStepsResult{}
+
+	for _, status := range []string{"in_progress", beads.StatusPinned, beads.StatusHooked} {
+		issues, err := store.List(beads.ListOptions{Status: status, Priority: -1})
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("listing %s steps: %w", status, err))
+			continue
+		}
+
+		for _, issue := range issues {
+			fields := beads.ParseStepFields(issue)
+			if fields == nil || fields.Timeout == "" {
+				continue
+			}
+			result.Checked++
+			elapsed, overdue := fields.Overdue(now)
+			if !overdue || fields.EscalatedAt != "" {
+				continue
+			}
+
+			step := OverdueStep{
+				StepID:   issue.ID,
+				Title:    issue.Title,
+				Assignee: issue.Assignee,
+				Timeout:  fields.Timeout,
+				Elapsed:  elapsed,
+			}
+			msg := &mail.Message{
+				From:     fmt.Sprintf("%s/witness", rigName),
+				To:       "deacon/",
+				Subject:  fmt.Sprintf("STEP_TIMEOUT %s: %s", issue.ID, issue.Title),
+				Priority: mail.PriorityHigh,
+				Body: fmt.Sprintf(`Step: %s
+Title: %s
+Assignee: %s
+Timeout: %s
+Running for: %s
+Started: %s
+
+This molecule step has run past its formula timeout. Check whether the
+assignee is stuck, then nudge it, or have it report the step failed with:
+gt mol step done %s --failed --reason "timed out"`,
+					issue.ID,
+					issue.Title,
+					issue.Assignee,
+					fields.Timeout,
+					elapsed.Round(time.Second),
+					fields.StartedAt,
+					issue.ID,
+				),
+			}
+			if err := send(msg); err != nil {
+				step.Error = fmt.Errorf("escalating: %w", err)
+				result.Overdue = append(result.Overdue, step)
+				continue
+			}
+			step.MailID = msg.ID
+
+			fields.EscalatedAt = now.UTC().Format(time.RFC3339)
+			desc := beads.SetStepFields(issue, fields)
+			if err := store.Update(issue.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+				step.Error = fmt.Errorf("recording escalation: %w", err)
+			}
+			result.Overdue = append(result.Overdue, step)
+		}
 	}
-	return items[0].ID
+
+	return result
 }
@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -114,9 +118,8 @@ func TestDetectZombiePolecats_EmptyPolecatsDir(t *testing.T) {
 }
 
 func TestGetAgentBeadState_EmptyOutput(t *testing.T) {
-	// getAgentBeadState with invalid bead ID should return empty strings
-	// (it calls bd which won't exist in test, so it returns empty)
-	state, hook := getAgentBeadState("/nonexistent", "nonexistent-bead")
+	// getAgentBeadState with a missing bead should return empty strings
+	state, hook := getAgentBeadState(beads.NewMemStore("gt"), "nonexistent-bead")
 
 	if state != "" {
 		t.Errorf("state = %q, want empty for missing bead", state)
@@ -223,12 +226,12 @@ func TestZombieClassification_NoHookNoActiveState(t *testing.T) {
 	}
 }
 
-func TestFindAnyCleanupWisp_NoBdAvailable(t *testing.T) {
-	// When bd is not available (test environment), findAnyCleanupWisp
-	// should return empty string without panicking
-	result := findAnyCleanupWisp("/nonexistent", "testpolecat")
+func TestFindAnyCleanupWisp_None(t *testing.T) {
+	// With no cleanup wisp for the polecat, findAnyCleanupWisp
+	// should return empty string
+	result := findAnyCleanupWisp(beads.NewMemStore("gt"), "testpolecat")
 	if result != "" {
-		t.Errorf("findAnyCleanupWisp = %q, want empty when bd unavailable", result)
+		t.Errorf("findAnyCleanupWisp = %q, want empty with no wisps", result)
 	}
 }
 
@@ -374,59 +377,45 @@ func TestDetectZombie_AgentDeadInLiveSession(t *testing.T) {
 	}
 }
 
-func TestGetAgentBeadLabels_NoBdAvailable(t *testing.T) {
-	// When bd is not available, should return nil without panicking
-	labels := getAgentBeadLabels("/nonexistent", "nonexistent-bead")
+func TestGetAgentBeadLabels_MissingBead(t *testing.T) {
+	// A missing bead should return nil
+	labels := getAgentBeadLabels(beads.NewMemStore("gt"), "nonexistent-bead")
 	if labels != nil {
-		t.Errorf("getAgentBeadLabels = %v, want nil when bd unavailable", labels)
+		t.Errorf("getAgentBeadLabels = %v, want nil for missing bead", labels)
 	}
 }
 
-// --- extractPolecatFromJSON tests (issue #1228: panic-safe JSON parsing) ---
-
-func TestExtractPolecatFromJSON_ValidOutput(t *testing.T) {
-	input := `[{"labels":["cleanup","polecat:nux","state:pending"]}]`
-	got := extractPolecatFromJSON(input)
-	if got != "nux" {
-		t.Errorf("extractPolecatFromJSON() = %q, want %q", got, "nux")
-	}
-}
-
-func TestExtractPolecatFromJSON_InvalidInputs(t *testing.T) {
+func TestPolecatFromLabels(t *testing.T) {
 	tests := []struct {
-		name  string
-		input string
+		name   string
+		labels []string
+		want   string
 	}{
-		{"empty output", ""},
-		{"malformed JSON", "{not valid json"},
-		{"empty array", "[]"},
-		{"no polecat label", `[{"labels":["cleanup","state:pending"]}]`},
-		{"empty labels", `[{"labels":[]}]`},
-		{"truncated JSON", `[{"labels":["polecat:`},
+		{"polecat label", []string{"cleanup", "polecat:nux", "state:pending"}, "nux"},
+		{"no polecat label", []string{"cleanup", "state:pending"}, ""},
+		{"empty labels", nil, ""},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := extractPolecatFromJSON(tt.input)
-			if got != "" {
-				t.Errorf("extractPolecatFromJSON(%q) = %q, want empty", tt.input, got)
+			if got := polecatFromLabels(tt.labels); got != tt.want {
+				t.Errorf("polecatFromLabels(%v) = %q, want %q", tt.labels, got, tt.want)
 			}
 		})
 	}
 }
 
-func TestGetBeadStatus_NoBdAvailable(t *testing.T) {
-	// When bd is not available (test environment), getBeadStatus
-	// should return empty string without panicking
-	result := getBeadStatus("/nonexistent", "gt-abc123")
+func TestGetBeadStatus_MissingBead(t *testing.T) {
+	// A missing bead should return empty string
+	result := getBeadStatus(beads.NewMemStore("gt"), "gt-abc123")
 	if result != "" {
-		t.Errorf("getBeadStatus = %q, want empty when bd unavailable", result)
+		t.Errorf("getBeadStatus = %q, want empty for missing bead", result)
 	}
 }
 
 func TestGetBeadStatus_EmptyBeadID(t *testing.T) {
 	// Empty bead ID should return empty string immediately
-	result := getBeadStatus("/nonexistent", "")
+	result := getBeadStatus(beads.NewMemStore("gt"), "")
 	if result != "" {
 		t.Errorf("getBeadStatus(\"\") = %q, want empty", result)
 	}
@@ -495,3 +484,100 @@ func TestDetectZombie_BeadClosedVsDoneIntent(t *testing.T) {
 		t.Error("closed-bead check should not run when done-intent exists")
 	}
 }
+
+func TestDetectOverdueSteps(t *testing.T) {
+	store := beads.NewMemStore("gt")
+	now := time.Date(2026, 1, 1, 13, 0, 0, 0, time.UTC)
+
+	newStep := func(title, status string, fields *beads.StepFields) string {
+		issue, err := store.Create(beads.CreateOptions{Title: title, Priority: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		desc := beads.SetStepFields(&beads.Issue{Description: "Do the work."}, fields)
+		if err := store.Update(issue.ID, beads.UpdateOptions{Status: &status, Description: &desc}); err != nil {
+			t.Fatal(err)
+		}
+		return issue.ID
+	}
+	overdue := newStep("Run tests", "in_progress", &beads.StepFields{Timeout: "30m", StartedAt: "2026-01-01T12:00:00Z"})
+	newStep("Within timeout", beads.StatusPinned, &beads.StepFields{Timeout: "2h", StartedAt: "2026-01-01T12:00:00Z"})
+	newStep("Already escalated", "in_progress", &beads.StepFields{Timeout: "5m", StartedAt: "2026-01-01T12:00:00Z", EscalatedAt: "2026-01-01T12:10:00Z"})
+	newStep("No timeout", "in_progress", &beads.StepFields{Retries: 1})
+	newStep("Finished", "closed", &beads.StepFields{Timeout: "1m", StartedAt: "2026-01-01T12:00:00Z"})
+
+	var sent []*mail.Message
+	send := func(msg *mail.Message) error {
+		sent = append(sent, msg)
+		return nil
+	}
+
+	result := detectOverdueSteps(store, "gastown", send, now)
+	if result.Checked != 3 {
+		t.Errorf("Checked = %d, want 3", result.Checked)
+	}
+	if len(result.Overdue) != 1 || result.Overdue[0].StepID != overdue || result.Overdue[0].Elapsed != time.Hour {
+		t.Fatalf("Overdue = %+v, want %s after 1h", result.Overdue, overdue)
+	}
+	if len(sent) != 1 || sent[0].To != "deacon/" || !strings.HasPrefix(sent[0].Subject, "STEP_TIMEOUT "+overdue) {
+		t.Errorf("sent = %+v, want one STEP_TIMEOUT to deacon/", sent)
+	}
+
+	// The escalation is recorded, so the next patrol stays quiet
+	issue, _ := store.Show(overdue)
+	if fields := beads.ParseStepFields(issue); fields.EscalatedAt == "" {
+		t.Errorf("step_escalated_at not recorded: %q", issue.Description)
+	}
+	if again := detectOverdueSteps(store, "gastown", send, now.Add(time.Hour)); len(again.Overdue) != 0 || len(sent) != 1 {
+		t.Errorf("second sweep escalated again: %+v", again.Overdue)
+	}
+}
+
+func TestHandlePolecatDone_PendingMRTracksCleanupWisp(t *testing.T) {
+	store := beads.NewMemStore("gt")
+	orig := openBeads
+	openBeads = func(string) beads.Store { return store }
+	defer func() { openBeads = orig }()
+
+	msg := protocol.NewMessage("testrig/nux", "testrig/witness", &protocol.PolecatDonePayload{
+		Polecat: "nux", Exit: "COMPLETED", Issue: "gt-abc", MR: "gt-mr1", Branch: "polecat/nux",
+	})
+	result := HandlePolecatDone(t.TempDir(), "testrig", msg, nil)
+	if result.Error != nil || !result.Handled {
+		t.Fatalf("HandlePolecatDone = %+v", result)
+	}
+
+	wisp, err := store.Show(result.WispCreated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !wisp.Ephemeral || !strings.Contains(wisp.Description, "Branch: polecat/nux") {
+		t.Errorf("wisp = %+v, want an ephemeral cleanup wisp for polecat/nux", wisp)
+	}
+	if got, err := findCleanupWisp(store, "nux"); err != nil || got != wisp.ID {
+		t.Errorf("findCleanupWisp = %q, %v; want %s in merge-requested", got, err, wisp.ID)
+	}
+	if got := findAnyCleanupWisp(store, "other"); got != "" {
+		t.Errorf("findAnyCleanupWisp(other) = %q, want empty", got)
+	}
+}
+
+func TestGetCleanupStatus(t *testing.T) {
+	store := beads.NewMemStore("gt")
+	townRoot := t.TempDir()
+	id := beads.PolecatBeadIDWithPrefix(beads.GetPrefixForRig(townRoot, "testrig"), "testrig", "nux")
+	if _, err := store.CreateWithID(id, beads.CreateOptions{
+		Title:       "nux",
+		Priority:    -1,
+		Description: "Polecat nux\n\ncleanup_status: has_unpushed",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := getCleanupStatus(store, townRoot, "testrig", "nux"); got != "has_unpushed" {
+		t.Errorf("getCleanupStatus = %q, want has_unpushed", got)
+	}
+	if got := getCleanupStatus(store, townRoot, "testrig", "slit"); got != "" {
+		t.Errorf("getCleanupStatus(no bead) = %q, want empty", got)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -28,21 +29,23 @@ var (
 // Manager handles witness lifecycle and monitoring operations.
 // ZFC-compliant: tmux session is the source of truth for running state.
 type Manager struct {
-	rig *rig.Rig
+	rig      *rig.Rig
+	sessions session.Backend
 }
 
-// NewManager creates a new witness manager for a rig.
+// NewManager creates a new witness manager for a rig, using the session
+// backend selected by GT_SESSION_BACKEND.
 func NewManager(r *rig.Rig) *Manager {
 	return &Manager{
-		rig: r,
+		rig:      r,
+		sessions: session.DefaultBackend(),
 	}
 }
 
 // IsRunning checks if the witness session is active.
 // ZFC: tmux session existence is the source of truth.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
-	return t.HasSession(m.SessionName())
+	return m.sessions.HasSession(m.SessionName())
 }
 
 // SessionName returns the tmux session name for this witness.
@@ -52,11 +55,11 @@ func (m *Manager) SessionName() string {
 
 // Status returns information about the witness session.
 // ZFC-compliant: tmux session is the source of truth.
+// Backends other than tmux report only the session name.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
 
-	running, err := t.HasSession(sessionID)
+	running, err := m.sessions.HasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -64,7 +67,10 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	if t, ok := m.sessions.(*tmux.Tmux); ok {
+		return t.GetSessionInfo(sessionID)
+	}
+	return &tmux.SessionInfo{Name: sessionID}, nil
 }
 
 // witnessDir returns the working directory for the witness.
@@ -90,7 +96,8 @@ func (m *Manager) witnessDir() string {
 // envOverrides are KEY=VALUE pairs that override all other env var sources.
 // ZFC-compliant: no state file, tmux session is source of truth.
 func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []string) error {
-	t := tmux.NewTmux()
+	t := m.sessions
+	realTmux, isTmux := t.(*tmux.Tmux)
 	sessionID := m.SessionName()
 
 	if foreground {
@@ -101,13 +108,14 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 	// Check if session already exists
 	running, _ := t.HasSession(sessionID)
 	if running {
-		// Session exists - check if Claude is actually running (healthy vs zombie)
-		if t.IsAgentAlive(sessionID) {
+		// Session exists - check if Claude is actually running (healthy vs zombie).
+		// Other backends end the session with the agent, so it is healthy.
+		if !isTmux || realTmux.IsAgentAlive(sessionID) {
 			// Healthy - Claude is running
 			return ErrAlreadyRunning
 		}
 		// Zombie - tmux alive but Claude dead. Kill and recreate.
-		if err := t.KillSession(sessionID); err != nil {
+		if err := t.KillSessionWithProcesses(sessionID); err != nil {
 			return fmt.Errorf("killing zombie session: %w", err)
 		}
 	}
@@ -146,6 +154,11 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
 
+	// Record pane output so the session can be replayed after it is gone (non-fatal)
+	if isTmux {
+		_ = transcript.Start(realTmux, townRoot, sessionID)
+	}
+
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	envVars := config.AgentEnv(config.AgentEnvConfig{
@@ -167,25 +180,27 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 		}
 	}
 
-	// Apply Gas Town theming (non-fatal: theming failure doesn't affect operation)
-	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "witness", "witness")
+	if isTmux {
+		// Apply Gas Town theming (non-fatal: theming failure doesn't affect operation)
+		theme := tmux.AssignTheme(m.rig.Name)
+		_ = realTmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "witness", "witness")
 
-	// Wait for Claude to start - fatal if Claude fails to launch
-	if err := t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
-		// Kill the zombie session before returning error
-		_ = t.KillSessionWithProcesses(sessionID)
-		return fmt.Errorf("waiting for witness to start: %w", err)
-	}
+		// Wait for Claude to start - fatal if Claude fails to launch
+		if err := realTmux.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+			// Kill the zombie session before returning error
+			_ = t.KillSessionWithProcesses(sessionID)
+			return fmt.Errorf("waiting for witness to start: %w", err)
+		}
 
-	// Accept bypass permissions warning dialog if it appears.
-	if err := t.AcceptBypassPermissionsWarning(sessionID); err != nil {
-		log.Printf("warning: accepting bypass permissions for %s: %v", sessionID, err)
-	}
+		// Accept bypass permissions warning dialog if it appears.
+		if err := realTmux.AcceptBypassPermissionsWarning(sessionID); err != nil {
+			log.Printf("warning: accepting bypass permissions for %s: %v", sessionID, err)
+		}
 
-	// Track PID for defense-in-depth orphan cleanup (non-fatal)
-	if err := session.TrackSessionPID(townRoot, sessionID, t); err != nil {
-		log.Printf("warning: tracking session PID for %s: %v", sessionID, err)
+		// Track PID for defense-in-depth orphan cleanup (non-fatal)
+		if err := session.TrackSessionPID(townRoot, sessionID, realTmux); err != nil {
+			log.Printf("warning: tracking session PID for %s: %v", sessionID, err)
+		}
 	}
 
 	time.Sleep(constants.ShutdownNotifyDelay)
@@ -245,15 +260,14 @@ func buildWitnessStartCommand(rigPath, rigName, townRoot, agentOverride string,
 // Stop stops the witness.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
 
 	// Check if tmux session exists
-	running, _ := t.HasSession(sessionID)
+	running, _ := m.sessions.HasSession(sessionID)
 	if !running {
 		return ErrNotRunning
 	}
 
 	// Kill the tmux session
-	return t.KillSession(sessionID)
+	return m.sessions.KillSessionWithProcesses(sessionID)
 }
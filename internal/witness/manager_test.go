@@ -1,10 +1,13 @@
 package witness
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session/sessiontest"
 )
 
 func TestBuildWitnessStartCommand_UsesRoleConfig(t *testing.T) {
@@ -53,3 +56,35 @@ func TestBuildWitnessStartCommand_AgentOverrideWins(t *testing.T) {
 		t.Errorf("expected GT_ROLE=gastown/witness in command, got %q", got)
 	}
 }
+
+func TestManagerSessionLifecycle(t *testing.T) {
+	m := NewManager(&rig.Rig{Name: "gastown", Path: t.TempDir()})
+	backend := sessiontest.New()
+	m.sessions = backend
+
+	if running, err := m.IsRunning(); err != nil || running {
+		t.Errorf("IsRunning = %v, %v; want false", running, err)
+	}
+	if _, err := m.Status(); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Status error = %v, want ErrNotRunning", err)
+	}
+	if err := m.Stop(); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Stop error = %v, want ErrNotRunning", err)
+	}
+
+	// A running session on a non-tmux backend is taken as healthy
+	backend = sessiontest.New(m.SessionName())
+	m.sessions = backend
+	if err := m.Start(false, "", nil); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("Start error = %v, want ErrAlreadyRunning", err)
+	}
+	if info, err := m.Status(); err != nil || info.Name != m.SessionName() {
+		t.Errorf("Status = %+v, %v; want %s", info, err, m.SessionName())
+	}
+	if err := m.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if len(backend.Killed) != 1 || backend.Killed[0] != m.SessionName() {
+		t.Errorf("killed %v, want %s", backend.Killed, m.SessionName())
+	}
+}
@@ -6,35 +6,15 @@ import (
 	"regexp"
 	"strings"
 	"time"
-)
-
-// Protocol message patterns for Witness inbox routing.
-var (
-	// POLECAT_DONE <name> - polecat signaling work completion
-	PatternPolecatDone = regexp.MustCompile(`^POLECAT_DONE\s+(\S+)`)
-
-	// LIFECYCLE:Shutdown <name> - daemon-triggered polecat shutdown
-	PatternLifecycleShutdown = regexp.MustCompile(`^LIFECYCLE:Shutdown\s+(\S+)`)
-
-	// HELP: <topic> - polecat requesting intervention
-	PatternHelp = regexp.MustCompile(`^HELP:\s+(.+)`)
-
-	// MERGED <name> - refinery confirms branch merged
-	PatternMerged = regexp.MustCompile(`^MERGED\s+(\S+)`)
 
-	// MERGE_FAILED <name> - refinery reporting merge failure
-	PatternMergeFailed = regexp.MustCompile(`^MERGE_FAILED\s+(\S+)`)
-
-	// MERGE_READY <polecat-name> - witness notifying refinery that work is ready
-	PatternMergeReady = regexp.MustCompile(`^MERGE_READY\s+(\S+)`)
-
-	// HANDOFF - session continuity message
-	PatternHandoff = regexp.MustCompile(`^🤝\s*HANDOFF`)
-
-	// SWARM_START - mayor initiating batch work
-	PatternSwarmStart = regexp.MustCompile(`^SWARM_START`)
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/protocol"
 )
 
+// PatternHandoff matches session continuity mail ("🤝 HANDOFF: ..."). Handoffs
+// are not protocol messages; the witness recognizes them for routing only.
+var PatternHandoff = regexp.MustCompile(`^🤝\s*HANDOFF`)
+
 // ProtocolType identifies the type of protocol message.
 type ProtocolType string
 
@@ -45,291 +25,47 @@ const (
 	ProtoMerged            ProtocolType = "merged"
 	ProtoMergeFailed       ProtocolType = "merge_failed"
 	ProtoMergeReady        ProtocolType = "merge_ready"
+	ProtoReworkRequest     ProtocolType = "rework_request"
 	ProtoHandoff           ProtocolType = "handoff"
 	ProtoSwarmStart        ProtocolType = "swarm_start"
 	ProtoUnknown           ProtocolType = "unknown"
 )
 
-// PolecatDonePayload contains parsed data from a POLECAT_DONE message.
-type PolecatDonePayload struct {
-	PolecatName string
-	Exit        string // COMPLETED, ESCALATED, DEFERRED, PHASE_COMPLETE
-	IssueID     string
-	MRID        string
-	Branch      string
-	Gate        string // Gate ID when Exit is PHASE_COMPLETE
-}
-
-// HelpPayload contains parsed data from a HELP message.
-type HelpPayload struct {
-	Topic       string
-	Agent       string
-	IssueID     string
-	Problem     string
-	Tried       string
-	RequestedAt time.Time
-}
-
-// MergedPayload contains parsed data from a MERGED message.
-type MergedPayload struct {
-	PolecatName string
-	Branch      string
-	IssueID     string
-	MergedAt    time.Time
-}
-
-// MergeReadyPayload contains parsed data from a MERGE_READY message.
-// This is sent by Witness to Refinery when a polecat completes work with a pending MR.
-type MergeReadyPayload struct {
-	PolecatName string
-	Branch      string
-	IssueID     string
-	MRID        string
-	ReadyAt     time.Time
-}
-
-// MergeFailedPayload contains parsed data from a MERGE_FAILED message.
-type MergeFailedPayload struct {
-	PolecatName string
-	Branch      string
-	IssueID     string
-	FailureType string // "build", "test", "lint", etc.
-	Error       string
-	FailedAt    time.Time
-}
-
-// SwarmStartPayload contains parsed data from a SWARM_START message.
-type SwarmStartPayload struct {
-	SwarmID   string
-	BeadIDs   []string
-	Total     int
-	StartedAt time.Time
+// protoTypes maps protocol message types to witness routing types.
+var protoTypes = map[protocol.MessageType]ProtocolType{
+	protocol.TypePolecatDone:       ProtoPolecatDone,
+	protocol.TypeLifecycleShutdown: ProtoLifecycleShutdown,
+	protocol.TypeHelp:              ProtoHelp,
+	protocol.TypeMerged:            ProtoMerged,
+	protocol.TypeMergeFailed:       ProtoMergeFailed,
+	protocol.TypeMergeReady:        ProtoMergeReady,
+	protocol.TypeReworkRequest:     ProtoReworkRequest,
+	protocol.TypeSwarmStart:        ProtoSwarmStart,
 }
 
 // ClassifyMessage determines the protocol type from a message subject.
+// Prefer Classify, which also honours the message envelope.
 func ClassifyMessage(subject string) ProtocolType {
-	switch {
-	case PatternPolecatDone.MatchString(subject):
-		return ProtoPolecatDone
-	case PatternLifecycleShutdown.MatchString(subject):
-		return ProtoLifecycleShutdown
-	case PatternHelp.MatchString(subject):
-		return ProtoHelp
-	case PatternMerged.MatchString(subject):
-		return ProtoMerged
-	case PatternMergeFailed.MatchString(subject):
-		return ProtoMergeFailed
-	case PatternMergeReady.MatchString(subject):
-		return ProtoMergeReady
-	case PatternHandoff.MatchString(subject):
+	if PatternHandoff.MatchString(subject) {
 		return ProtoHandoff
-	case PatternSwarmStart.MatchString(subject):
-		return ProtoSwarmStart
-	default:
-		return ProtoUnknown
-	}
-}
-
-// ParsePolecatDone extracts payload from a POLECAT_DONE message.
-// Subject format: POLECAT_DONE <polecat-name>
-// Body format:
-//
-//	Exit: COMPLETED|ESCALATED|DEFERRED|PHASE_COMPLETE
-//	Issue: <issue-id>
-//	MR: <mr-id>
-//	Gate: <gate-id>
-//	Branch: <branch>
-func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
-	matches := PatternPolecatDone.FindStringSubmatch(subject)
-	if len(matches) < 2 {
-		return nil, fmt.Errorf("invalid POLECAT_DONE subject: %s", subject)
-	}
-
-	payload := &PolecatDonePayload{
-		PolecatName: matches[1],
-	}
-
-	// Parse body for structured fields
-	for _, line := range strings.Split(body, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "Exit:") {
-			payload.Exit = strings.TrimSpace(strings.TrimPrefix(line, "Exit:"))
-		} else if strings.HasPrefix(line, "Issue:") {
-			payload.IssueID = strings.TrimSpace(strings.TrimPrefix(line, "Issue:"))
-		} else if strings.HasPrefix(line, "MR:") {
-			payload.MRID = strings.TrimSpace(strings.TrimPrefix(line, "MR:"))
-		} else if strings.HasPrefix(line, "Gate:") {
-			payload.Gate = strings.TrimSpace(strings.TrimPrefix(line, "Gate:"))
-		} else if strings.HasPrefix(line, "Branch:") {
-			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
-		}
-	}
-
-	return payload, nil
-}
-
-// ParseHelp extracts payload from a HELP message.
-// Subject format: HELP: <topic>
-// Body format:
-//
-//	Agent: <agent-id>
-//	Issue: <issue-id>
-//	Problem: <description>
-//	Tried: <what was attempted>
-func ParseHelp(subject, body string) (*HelpPayload, error) {
-	matches := PatternHelp.FindStringSubmatch(subject)
-	if len(matches) < 2 {
-		return nil, fmt.Errorf("invalid HELP subject: %s", subject)
-	}
-
-	payload := &HelpPayload{
-		Topic:       matches[1],
-		RequestedAt: time.Now(),
 	}
-
-	// Parse body for structured fields
-	for _, line := range strings.Split(body, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "Agent:") {
-			payload.Agent = strings.TrimSpace(strings.TrimPrefix(line, "Agent:"))
-		} else if strings.HasPrefix(line, "Issue:") {
-			payload.IssueID = strings.TrimSpace(strings.TrimPrefix(line, "Issue:"))
-		} else if strings.HasPrefix(line, "Problem:") {
-			payload.Problem = strings.TrimSpace(strings.TrimPrefix(line, "Problem:"))
-		} else if strings.HasPrefix(line, "Tried:") {
-			payload.Tried = strings.TrimSpace(strings.TrimPrefix(line, "Tried:"))
-		}
-	}
-
-	return payload, nil
+	return toProtocolType(protocol.ParseMessageType(subject))
 }
 
-// ParseMerged extracts payload from a MERGED message.
-// Subject format: MERGED <polecat-name>
-// Body format:
-//
-//	Branch: <branch>
-//	Issue: <issue-id>
-//	Merged-At: <timestamp>
-func ParseMerged(subject, body string) (*MergedPayload, error) {
-	matches := PatternMerged.FindStringSubmatch(subject)
-	if len(matches) < 2 {
-		return nil, fmt.Errorf("invalid MERGED subject: %s", subject)
-	}
-
-	payload := &MergedPayload{
-		PolecatName: matches[1],
-	}
-
-	// Parse body for structured fields
-	for _, line := range strings.Split(body, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "Branch:") {
-			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
-		} else if strings.HasPrefix(line, "Issue:") {
-			payload.IssueID = strings.TrimSpace(strings.TrimPrefix(line, "Issue:"))
-		} else if strings.HasPrefix(line, "Merged-At:") {
-			ts := strings.TrimSpace(strings.TrimPrefix(line, "Merged-At:"))
-			if t, err := time.Parse(time.RFC3339, ts); err == nil {
-				payload.MergedAt = t
-			}
-		}
-	}
-
-	return payload, nil
-}
-
-// ParseMergeFailed extracts payload from a MERGE_FAILED message.
-// Subject format: MERGE_FAILED <polecat-name>
-// Body format:
-//
-//	Branch: <branch>
-//	Issue: <issue-id>
-//	FailureType: <type>
-//	Error: <error-message>
-func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
-	matches := PatternMergeFailed.FindStringSubmatch(subject)
-	if len(matches) < 2 {
-		return nil, fmt.Errorf("invalid MERGE_FAILED subject: %s", subject)
-	}
-
-	payload := &MergeFailedPayload{
-		PolecatName: matches[1],
-		FailedAt:    time.Now(),
-	}
-
-	// Parse body for structured fields
-	for _, line := range strings.Split(body, "\n") {
-		line = strings.TrimSpace(line)
-		switch {
-		case strings.HasPrefix(line, "Branch:"):
-			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
-		case strings.HasPrefix(line, "Issue:"):
-			payload.IssueID = strings.TrimSpace(strings.TrimPrefix(line, "Issue:"))
-		case strings.HasPrefix(line, "FailureType:"):
-			payload.FailureType = strings.TrimSpace(strings.TrimPrefix(line, "FailureType:"))
-		case strings.HasPrefix(line, "Error:"):
-			payload.Error = strings.TrimSpace(strings.TrimPrefix(line, "Error:"))
-		}
-	}
-
-	return payload, nil
-}
-
-// ParseMergeReady extracts payload from a MERGE_READY message.
-// Subject format: MERGE_READY <polecat-name>
-// Body format:
-//
-//	Branch: <branch>
-//	Issue: <issue-id>
-//	MR: <mr-id>
-//	Verified: clean git state
-func ParseMergeReady(subject, body string) (*MergeReadyPayload, error) {
-	matches := PatternMergeReady.FindStringSubmatch(subject)
-	if len(matches) < 2 {
-		return nil, fmt.Errorf("invalid MERGE_READY subject: %s", subject)
-	}
-
-	payload := &MergeReadyPayload{
-		PolecatName: matches[1],
-		ReadyAt:     time.Now(),
-	}
-
-	// Parse body for structured fields
-	for _, line := range strings.Split(body, "\n") {
-		line = strings.TrimSpace(line)
-		switch {
-		case strings.HasPrefix(line, "Branch:"):
-			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
-		case strings.HasPrefix(line, "Issue:"):
-			payload.IssueID = strings.TrimSpace(strings.TrimPrefix(line, "Issue:"))
-		case strings.HasPrefix(line, "MR:"):
-			payload.MRID = strings.TrimSpace(strings.TrimPrefix(line, "MR:"))
-		}
+// Classify determines the protocol type of a message, dispatching on its
+// envelope when present and on the subject prefix otherwise.
+func Classify(msg *mail.Message) ProtocolType {
+	if msg.Envelope == nil && PatternHandoff.MatchString(msg.Subject) {
+		return ProtoHandoff
 	}
-
-	return payload, nil
+	return toProtocolType(protocol.Classify(msg))
 }
 
-// ParseSwarmStart extracts payload from a SWARM_START message.
-// Body format is JSON: {"swarm_id": "batch-123", "beads": ["bd-a", "bd-b"]}
-func ParseSwarmStart(body string) (*SwarmStartPayload, error) {
-	payload := &SwarmStartPayload{
-		StartedAt: time.Now(),
+func toProtocolType(t protocol.MessageType) ProtocolType {
+	if pt, ok := protoTypes[t]; ok {
+		return pt
 	}
-
-	// Parse the JSON-like body (simplified parsing for key-value extraction)
-	// Full JSON parsing would require encoding/json import
-	for _, line := range strings.Split(body, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "SwarmID:") || strings.HasPrefix(line, "swarm_id:") {
-			payload.SwarmID = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(line, "SwarmID:"), "swarm_id:"))
-		} else if strings.HasPrefix(line, "Total:") {
-			_, _ = fmt.Sscanf(line, "Total: %d", &payload.Total)
-		}
-	}
-
-	return payload, nil
+	return ProtoUnknown
 }
 
 // CleanupWispLabels generates labels for a cleanup wisp.
@@ -362,7 +98,7 @@ type HelpAssessment struct {
 
 // AssessHelpRequest provides guidance for the Witness to assess a help request.
 // This is a template/guide - actual assessment is done by the Claude agent.
-func AssessHelpRequest(payload *HelpPayload) *HelpAssessment {
+func AssessHelpRequest(payload *protocol.HelpPayload) *HelpAssessment {
 	assessment := &HelpAssessment{}
 
 	// Heuristics for common help requests that Witness can handle
@@ -1,4 +1,4 @@
-package protocol
+package witness
 
 import (
 	"fmt"
@@ -6,12 +6,13 @@ import (
 	"os"
 
 	"github.com/steveyegge/gastown/internal/mail"
-	"github.com/steveyegge/gastown/internal/witness"
+	"github.com/steveyegge/gastown/internal/protocol"
 )
 
-// DefaultWitnessHandler provides the default implementation for Witness protocol handlers.
+// ProtocolHandler provides the default implementation of protocol.WitnessHandler.
 // It receives messages from the Refinery about merge outcomes and takes appropriate action.
-type DefaultWitnessHandler struct {
+// Register it with protocol.WrapWitnessHandlers to dispatch witness mail.
+type ProtocolHandler struct {
 	// Rig is the name of the rig this witness manages.
 	Rig string
 
@@ -25,9 +26,9 @@ type DefaultWitnessHandler struct {
 	Output io.Writer
 }
 
-// NewWitnessHandler creates a new DefaultWitnessHandler.
-func NewWitnessHandler(rig, workDir string) *DefaultWitnessHandler {
-	return &DefaultWitnessHandler{
+// NewProtocolHandler creates a new ProtocolHandler.
+func NewProtocolHandler(rig, workDir string) *ProtocolHandler {
+	return &ProtocolHandler{
 		Rig:     rig,
 		WorkDir: workDir,
 		Router:  mail.NewRouter(workDir),
@@ -36,7 +37,7 @@ func NewWitnessHandler(rig, workDir string) *DefaultWitnessHandler {
 }
 
 // SetOutput sets the output writer for status messages.
-func (h *DefaultWitnessHandler) SetOutput(w io.Writer) {
+func (h *ProtocolHandler) SetOutput(w io.Writer) {
 	h.Output = w
 }
 
@@ -45,7 +46,7 @@ func (h *DefaultWitnessHandler) SetOutput(w io.Writer) {
 // 1. Logs the success
 // 2. Notifies the polecat of successful merge
 // 3. Initiates polecat cleanup (nuke worktree)
-func (h *DefaultWitnessHandler) HandleMerged(payload *MergedPayload) error {
+func (h *ProtocolHandler) HandleMerged(payload *protocol.MergedPayload) error {
 	_, _ = fmt.Fprintf(h.Output, "[Witness] MERGED received for polecat %s\n", payload.Polecat)
 	_, _ = fmt.Fprintf(h.Output, "  Branch: %s\n", payload.Branch)
 	_, _ = fmt.Fprintf(h.Output, "  Issue: %s\n", payload.Issue)
@@ -62,7 +63,7 @@ func (h *DefaultWitnessHandler) HandleMerged(payload *MergedPayload) error {
 
 	// Initiate polecat cleanup using AutoNukeIfClean
 	// This verifies cleanup_status before nuking to prevent work loss.
-	nukeResult := witness.AutoNukeIfClean(h.WorkDir, h.Rig, payload.Polecat)
+	nukeResult := AutoNukeIfClean(h.WorkDir, h.Rig, payload.Polecat)
 	if nukeResult.Nuked {
 		fmt.Fprintf(h.Output, "[Witness] ✓ Auto-nuked polecat %s: %s\n", payload.Polecat, nukeResult.Reason)
 	} else if nukeResult.Skipped {
@@ -82,7 +83,7 @@ func (h *DefaultWitnessHandler) HandleMerged(payload *MergedPayload) error {
 // 1. Logs the failure
 // 2. Notifies the polecat about the failure and required fixes
 // 3. Updates the polecat's state to indicate rework needed
-func (h *DefaultWitnessHandler) HandleMergeFailed(payload *MergeFailedPayload) error {
+func (h *ProtocolHandler) HandleMergeFailed(payload *protocol.MergeFailedPayload) error {
 	fmt.Fprintf(h.Output, "[Witness] MERGE_FAILED received for polecat %s\n", payload.Polecat)
 	fmt.Fprintf(h.Output, "  Branch: %s\n", payload.Branch)
 	fmt.Fprintf(h.Output, "  Issue: %s\n", payload.Issue)
@@ -105,7 +106,7 @@ func (h *DefaultWitnessHandler) HandleMergeFailed(payload *MergeFailedPayload) e
 // 1. Logs the conflict
 // 2. Notifies the polecat with rebase instructions
 // 3. Updates the polecat's state to indicate rebase needed
-func (h *DefaultWitnessHandler) HandleReworkRequest(payload *ReworkRequestPayload) error {
+func (h *ProtocolHandler) HandleReworkRequest(payload *protocol.ReworkRequestPayload) error {
 	fmt.Fprintf(h.Output, "[Witness] REWORK_REQUEST received for polecat %s\n", payload.Polecat)
 	fmt.Fprintf(h.Output, "  Branch: %s\n", payload.Branch)
 	fmt.Fprintf(h.Output, "  Issue: %s\n", payload.Issue)
@@ -126,7 +127,7 @@ func (h *DefaultWitnessHandler) HandleReworkRequest(payload *ReworkRequestPayloa
 }
 
 // notifyPolecatMerged sends a merge success notification to a polecat.
-func (h *DefaultWitnessHandler) notifyPolecatMerged(payload *MergedPayload) error {
+func (h *ProtocolHandler) notifyPolecatMerged(payload *protocol.MergedPayload) error {
 	msg := mail.NewMessage(
 		fmt.Sprintf("%s/witness", h.Rig),
 		fmt.Sprintf("%s/%s", h.Rig, payload.Polecat),
@@ -150,7 +151,7 @@ Thank you for your contribution! Your worktree will be cleaned up shortly.`,
 }
 
 // notifyPolecatFailed sends a merge failure notification to a polecat.
-func (h *DefaultWitnessHandler) notifyPolecatFailed(payload *MergeFailedPayload) error {
+func (h *ProtocolHandler) notifyPolecatFailed(payload *protocol.MergeFailedPayload) error {
 	msg := mail.NewMessage(
 		fmt.Sprintf("%s/witness", h.Rig),
 		fmt.Sprintf("%s/%s", h.Rig, payload.Polecat),
@@ -176,7 +177,7 @@ Please fix the issue and resubmit your work with 'gt done'.`,
 }
 
 // notifyPolecatRebase sends a rebase request notification to a polecat.
-func (h *DefaultWitnessHandler) notifyPolecatRebase(payload *ReworkRequestPayload) error {
+func (h *ProtocolHandler) notifyPolecatRebase(payload *protocol.ReworkRequestPayload) error {
 	conflictInfo := ""
 	if len(payload.ConflictFiles) > 0 {
 		conflictInfo = fmt.Sprintf("\nConflicting files:\n")
@@ -215,5 +216,5 @@ Then run 'gt done' to resubmit for merge.`,
 	return h.Router.Send(msg)
 }
 
-// Ensure DefaultWitnessHandler implements WitnessHandler.
-var _ WitnessHandler = (*DefaultWitnessHandler)(nil)
+// Ensure ProtocolHandler implements protocol.WitnessHandler.
+var _ protocol.WitnessHandler = (*ProtocolHandler)(nil)
@@ -1,7 +1,12 @@
 package witness
 
 import (
+	"bytes"
+	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/protocol"
 )
 
 func TestClassifyMessage(t *testing.T) {
@@ -37,243 +42,81 @@ func TestClassifyMessage(t *testing.T) {
 	}
 }
 
-func TestParsePolecatDone(t *testing.T) {
-	subject := "POLECAT_DONE nux"
-	body := `Exit: MERGED
-Issue: gt-abc123
-MR: gt-mr-xyz
-Branch: feature-branch`
-
-	payload, err := ParsePolecatDone(subject, body)
-	if err != nil {
-		t.Fatalf("ParsePolecatDone() error = %v", err)
+func TestClassify_Envelope(t *testing.T) {
+	// The envelope wins over a reworded subject
+	msg := protocol.NewMergeFailedMessage("gastown", "nux", "polecat/nux", "gt-abc", "main", "tests", "failed")
+	msg.Subject = "nux: merge failed"
+	if got := Classify(msg); got != ProtoMergeFailed {
+		t.Errorf("Classify = %v, want %v", got, ProtoMergeFailed)
 	}
 
-	if payload.PolecatName != "nux" {
-		t.Errorf("PolecatName = %q, want %q", payload.PolecatName, "nux")
-	}
-	if payload.Exit != "MERGED" {
-		t.Errorf("Exit = %q, want %q", payload.Exit, "MERGED")
-	}
-	if payload.IssueID != "gt-abc123" {
-		t.Errorf("IssueID = %q, want %q", payload.IssueID, "gt-abc123")
-	}
-	if payload.MRID != "gt-mr-xyz" {
-		t.Errorf("MRID = %q, want %q", payload.MRID, "gt-mr-xyz")
+	legacy := &mail.Message{Subject: "POLECAT_DONE nux"}
+	if got := Classify(legacy); got != ProtoPolecatDone {
+		t.Errorf("Classify(legacy) = %v, want %v", got, ProtoPolecatDone)
 	}
-	if payload.Branch != "feature-branch" {
-		t.Errorf("Branch = %q, want %q", payload.Branch, "feature-branch")
+	handoff := &mail.Message{Subject: "🤝 HANDOFF: Patrol context"}
+	if got := Classify(handoff); got != ProtoHandoff {
+		t.Errorf("Classify(handoff) = %v, want %v", got, ProtoHandoff)
 	}
 }
 
-func TestParsePolecatDone_MinimalBody(t *testing.T) {
-	subject := "POLECAT_DONE ace"
-	body := "Exit: DEFERRED"
+func TestProtocolHandler(t *testing.T) {
+	tmpDir := t.TempDir()
+	handler := NewProtocolHandler("gastown", tmpDir)
 
-	payload, err := ParsePolecatDone(subject, body)
-	if err != nil {
-		t.Fatalf("ParsePolecatDone() error = %v", err)
-	}
+	// Capture output
+	var buf bytes.Buffer
+	handler.SetOutput(&buf)
 
-	if payload.PolecatName != "ace" {
-		t.Errorf("PolecatName = %q, want %q", payload.PolecatName, "ace")
-	}
-	if payload.Exit != "DEFERRED" {
-		t.Errorf("Exit = %q, want %q", payload.Exit, "DEFERRED")
+	// Test HandleMerged
+	mergedPayload := &protocol.MergedPayload{
+		Branch:       "polecat/nux/gt-abc",
+		Issue:        "gt-abc",
+		Polecat:      "nux",
+		Rig:          "gastown",
+		TargetBranch: "main",
+		MergeCommit:  "abc123",
 	}
-	if payload.IssueID != "" {
-		t.Errorf("IssueID = %q, want empty", payload.IssueID)
-	}
-}
-
-func TestParsePolecatDone_InvalidSubject(t *testing.T) {
-	_, err := ParsePolecatDone("Invalid subject", "body")
-	if err == nil {
-		t.Error("ParsePolecatDone() expected error for invalid subject")
+	if err := handler.HandleMerged(mergedPayload); err != nil {
+		t.Errorf("HandleMerged error: %v", err)
 	}
-}
-
-func TestParseHelp(t *testing.T) {
-	subject := "HELP: Tests failing on CI"
-	body := `Agent: gastown/polecats/nux
-Issue: gt-abc123
-Problem: Unit tests timeout after 30 seconds
-Tried: Increased timeout, checked for deadlocks`
-
-	payload, err := ParseHelp(subject, body)
-	if err != nil {
-		t.Fatalf("ParseHelp() error = %v", err)
+	if !strings.Contains(buf.String(), "MERGED received") {
+		t.Errorf("Output missing expected text: %s", buf.String())
 	}
 
-	if payload.Topic != "Tests failing on CI" {
-		t.Errorf("Topic = %q, want %q", payload.Topic, "Tests failing on CI")
-	}
-	if payload.Agent != "gastown/polecats/nux" {
-		t.Errorf("Agent = %q, want %q", payload.Agent, "gastown/polecats/nux")
+	// Test HandleMergeFailed
+	buf.Reset()
+	failedPayload := &protocol.MergeFailedPayload{
+		Branch:       "polecat/nux/gt-abc",
+		Issue:        "gt-abc",
+		Polecat:      "nux",
+		Rig:          "gastown",
+		TargetBranch: "main",
+		FailureType:  "tests",
+		Error:        "Test failed",
 	}
-	if payload.IssueID != "gt-abc123" {
-		t.Errorf("IssueID = %q, want %q", payload.IssueID, "gt-abc123")
+	if err := handler.HandleMergeFailed(failedPayload); err != nil {
+		t.Errorf("HandleMergeFailed error: %v", err)
 	}
-	if payload.Problem != "Unit tests timeout after 30 seconds" {
-		t.Errorf("Problem = %q, want %q", payload.Problem, "Unit tests timeout after 30 seconds")
+	if !strings.Contains(buf.String(), "MERGE_FAILED received") {
+		t.Errorf("Output missing expected text: %s", buf.String())
 	}
-	if payload.Tried != "Increased timeout, checked for deadlocks" {
-		t.Errorf("Tried = %q, want %q", payload.Tried, "Increased timeout, checked for deadlocks")
-	}
-}
 
-func TestParseHelp_InvalidSubject(t *testing.T) {
-	_, err := ParseHelp("Not a help message", "body")
-	if err == nil {
-		t.Error("ParseHelp() expected error for invalid subject")
-	}
-}
-
-func TestParseMerged(t *testing.T) {
-	subject := "MERGED nux"
-	body := `Branch: feature-nux
-Issue: gt-abc123
-Merged-At: 2025-12-30T10:30:00Z`
-
-	payload, err := ParseMerged(subject, body)
-	if err != nil {
-		t.Fatalf("ParseMerged() error = %v", err)
-	}
-
-	if payload.PolecatName != "nux" {
-		t.Errorf("PolecatName = %q, want %q", payload.PolecatName, "nux")
-	}
-	if payload.Branch != "feature-nux" {
-		t.Errorf("Branch = %q, want %q", payload.Branch, "feature-nux")
-	}
-	if payload.IssueID != "gt-abc123" {
-		t.Errorf("IssueID = %q, want %q", payload.IssueID, "gt-abc123")
-	}
-	if payload.MergedAt.IsZero() {
-		t.Error("MergedAt should not be zero")
-	}
-}
-
-func TestParseMerged_InvalidSubject(t *testing.T) {
-	_, err := ParseMerged("Not merged", "body")
-	if err == nil {
-		t.Error("ParseMerged() expected error for invalid subject")
-	}
-}
-
-func TestParseMergeFailed(t *testing.T) {
-	subject := "MERGE_FAILED nux"
-	body := `Branch: feature-nux
-Issue: gt-abc123
-FailureType: tests
-Error: unit tests failed with 3 errors`
-
-	payload, err := ParseMergeFailed(subject, body)
-	if err != nil {
-		t.Fatalf("ParseMergeFailed() error = %v", err)
-	}
-
-	if payload.PolecatName != "nux" {
-		t.Errorf("PolecatName = %q, want %q", payload.PolecatName, "nux")
-	}
-	if payload.Branch != "feature-nux" {
-		t.Errorf("Branch = %q, want %q", payload.Branch, "feature-nux")
+	// Test HandleReworkRequest
+	buf.Reset()
+	reworkPayload := &protocol.ReworkRequestPayload{
+		Branch:        "polecat/nux/gt-abc",
+		Issue:         "gt-abc",
+		Polecat:       "nux",
+		Rig:           "gastown",
+		TargetBranch:  "main",
+		ConflictFiles: []string{"file1.go"},
 	}
-	if payload.IssueID != "gt-abc123" {
-		t.Errorf("IssueID = %q, want %q", payload.IssueID, "gt-abc123")
+	if err := handler.HandleReworkRequest(reworkPayload); err != nil {
+		t.Errorf("HandleReworkRequest error: %v", err)
 	}
-	if payload.FailureType != "tests" {
-		t.Errorf("FailureType = %q, want %q", payload.FailureType, "tests")
-	}
-	if payload.Error != "unit tests failed with 3 errors" {
-		t.Errorf("Error = %q, want %q", payload.Error, "unit tests failed with 3 errors")
-	}
-	if payload.FailedAt.IsZero() {
-		t.Error("FailedAt should not be zero")
-	}
-}
-
-func TestParseMergeFailed_MinimalBody(t *testing.T) {
-	subject := "MERGE_FAILED ace"
-	body := "FailureType: build"
-
-	payload, err := ParseMergeFailed(subject, body)
-	if err != nil {
-		t.Fatalf("ParseMergeFailed() error = %v", err)
-	}
-
-	if payload.PolecatName != "ace" {
-		t.Errorf("PolecatName = %q, want %q", payload.PolecatName, "ace")
-	}
-	if payload.FailureType != "build" {
-		t.Errorf("FailureType = %q, want %q", payload.FailureType, "build")
-	}
-	if payload.Branch != "" {
-		t.Errorf("Branch = %q, want empty", payload.Branch)
-	}
-}
-
-func TestParseMergeFailed_InvalidSubject(t *testing.T) {
-	_, err := ParseMergeFailed("Not a merge failed", "body")
-	if err == nil {
-		t.Error("ParseMergeFailed() expected error for invalid subject")
-	}
-}
-
-func TestParseMergeReady(t *testing.T) {
-	subject := "MERGE_READY nux"
-	body := `Branch: polecat/nux/gt-abc123
-Issue: gt-abc123
-MR: mr-xyz789
-Polecat: nux
-Verified: clean git state`
-
-	payload, err := ParseMergeReady(subject, body)
-	if err != nil {
-		t.Fatalf("ParseMergeReady() error = %v", err)
-	}
-
-	if payload.PolecatName != "nux" {
-		t.Errorf("PolecatName = %q, want %q", payload.PolecatName, "nux")
-	}
-	if payload.Branch != "polecat/nux/gt-abc123" {
-		t.Errorf("Branch = %q, want %q", payload.Branch, "polecat/nux/gt-abc123")
-	}
-	if payload.IssueID != "gt-abc123" {
-		t.Errorf("IssueID = %q, want %q", payload.IssueID, "gt-abc123")
-	}
-	if payload.MRID != "mr-xyz789" {
-		t.Errorf("MRID = %q, want %q", payload.MRID, "mr-xyz789")
-	}
-	if payload.ReadyAt.IsZero() {
-		t.Error("ReadyAt should not be zero")
-	}
-}
-
-func TestParseMergeReady_MinimalBody(t *testing.T) {
-	subject := "MERGE_READY ace"
-	body := "Branch: feature-ace"
-
-	payload, err := ParseMergeReady(subject, body)
-	if err != nil {
-		t.Fatalf("ParseMergeReady() error = %v", err)
-	}
-
-	if payload.PolecatName != "ace" {
-		t.Errorf("PolecatName = %q, want %q", payload.PolecatName, "ace")
-	}
-	if payload.Branch != "feature-ace" {
-		t.Errorf("Branch = %q, want %q", payload.Branch, "feature-ace")
-	}
-	if payload.IssueID != "" {
-		t.Errorf("IssueID = %q, want empty", payload.IssueID)
-	}
-}
-
-func TestParseMergeReady_InvalidSubject(t *testing.T) {
-	_, err := ParseMergeReady("Not a merge ready", "body")
-	if err == nil {
-		t.Error("ParseMergeReady() expected error for invalid subject")
+	if !strings.Contains(buf.String(), "REWORK_REQUEST received") {
+		t.Errorf("Output missing expected text: %s", buf.String())
 	}
 }
 
@@ -293,7 +136,7 @@ func TestCleanupWispLabels(t *testing.T) {
 }
 
 func TestAssessHelpRequest_GitConflict(t *testing.T) {
-	payload := &HelpPayload{
+	payload := &protocol.HelpPayload{
 		Topic:   "Git issue",
 		Problem: "Merge conflict in main.go",
 	}
@@ -309,7 +152,7 @@ func TestAssessHelpRequest_GitConflict(t *testing.T) {
 }
 
 func TestAssessHelpRequest_GitPush(t *testing.T) {
-	payload := &HelpPayload{
+	payload := &protocol.HelpPayload{
 		Topic:   "Git push failing",
 		Problem: "Cannot push to remote",
 	}
@@ -325,7 +168,7 @@ func TestAssessHelpRequest_GitPush(t *testing.T) {
 }
 
 func TestAssessHelpRequest_TestFailures(t *testing.T) {
-	payload := &HelpPayload{
+	payload := &protocol.HelpPayload{
 		Topic:   "Test failures",
 		Problem: "Tests fail on CI",
 	}
@@ -341,7 +184,7 @@ func TestAssessHelpRequest_TestFailures(t *testing.T) {
 }
 
 func TestAssessHelpRequest_RequirementsUnclear(t *testing.T) {
-	payload := &HelpPayload{
+	payload := &protocol.HelpPayload{
 		Topic:   "Requirements unclear",
 		Problem: "Don't understand the requirements for this task",
 	}
@@ -357,7 +200,7 @@ func TestAssessHelpRequest_RequirementsUnclear(t *testing.T) {
 }
 
 func TestAssessHelpRequest_BuildIssues(t *testing.T) {
-	payload := &HelpPayload{
+	payload := &protocol.HelpPayload{
 		Topic:   "Build failing",
 		Problem: "Cannot compile the project",
 	}